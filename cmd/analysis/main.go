@@ -1,33 +1,186 @@
 package main
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"math/big"
+	"math/rand"
 	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gonum.org/v1/plot"
 
 	"insolventbydesign/internal/analysis"
+	"insolventbydesign/internal/chart"
+	"insolventbydesign/internal/currency"
+	dataset "insolventbydesign/internal/io"
 	"insolventbydesign/internal/model"
+	"insolventbydesign/internal/storage"
 )
 
 func main() {
 	// Command line flags
 	var (
 		dataFile    = flag.String("data", "data/bribes.json", "Input data file")
-		mode        = flag.String("mode", "summary", "Analysis mode: summary, rolling, concentration, predict, montecarlo")
+		mode        = flag.String("mode", "summary", "Analysis mode: summary, rolling, concentration, histogram, anomalies, seasonality, drivers, predict, montecarlo, backtest, compare, relays, episodes")
 		windowSize  = flag.Int("window", 1000, "Rolling window size")
 		tau         = flag.Uint64("tau", 1800, "Duration in slots (for prediction)")
 		ethPrice    = flag.Float64("eth-price", 3500, "ETH price in USD")
 		bridgeTVL   = flag.Float64("bridge-tvl", 500000000, "Bridge TVL in USD")
 		successProb = flag.Float64("success-prob", 0.8, "Attack success probability")
+		episodes    = flag.String("episodes", "", "Censorship episode file (JSON): fit p(τ) in episodes mode, and in other modes use p(--tau) as --success-prob")
 		simulations = flag.Int("simulations", 10000, "Number of Monte Carlo simulations")
+		resample    = flag.Bool("resample", false, "Monte Carlo: draw each run's cost by block-bootstrapping the data instead of fixing it")
+		blockLen    = flag.Int("block-len", 0, "Block length in slots for --resample (0 resamples whole τ-slot windows) and --bootstrap (0 draws single slots)")
+		confidence  = flag.String("confidence", "0.95,0.99", "Comma-separated confidence levels for Monte Carlo VaR and CVaR")
+		lossLimits  = flag.String("loss-threshold", "", "Monte Carlo: comma-separated losses in USD to report the probability of exceeding (e.g. 1000000,5000000)")
+		priceDrop   = flag.Float64("price-drop", 0, "Monte Carlo: mean fractional ETH price drop after a successful attack (e.g. 0.2)")
+		dropStdDev  = flag.Float64("price-drop-stddev", 0, "Standard deviation of the --price-drop")
+		ethExposure = flag.Float64("eth-exposure", 1, "Share of the bridge TVL denominated in ETH, which loses value with the price")
+		bribeResp   = flag.Float64("bribe-response", 0, "How fully builders raise ETH bribes for the anticipated drop (1 keeps their USD value)")
+		method      = flag.String("method", "ema", "Predict mode forecasting method: ema, holt-winters or arima")
+		period      = flag.Int("period", 7200, "Holt-Winters seasonal period in slots")
+		arOrder     = flag.Int("ar-order", 2, "ARIMA autoregressive order p")
+		diffOrder   = flag.Int("diff", 1, "ARIMA differencing order d")
+		interval    = flag.Float64("interval", 0.9, "Coverage of the forecast prediction interval")
+		bootstrap   = flag.Int("bootstrap", 0, "Summary mode: bootstrap resamples for confidence intervals of the mean, median and percentiles (0 disables)")
+		ciLevel     = flag.Float64("ci", analysis.DefaultBootstrapConfidence, "Coverage of the --bootstrap confidence intervals and of the --episodes credible intervals")
+		beforeRange = flag.String("before", "", "Compare mode: first range, as START..END slots (inclusive) or dates (END exclusive)")
+		afterRange  = flag.String("after", "", "Compare mode: second range, as --before")
+		permutation = flag.Int("permutations", analysis.DefaultPermutations, "Compare mode: relabelings per permutation test")
+		bins        = flag.Int("bins", analysis.DefaultHistogramBins, "Histogram mode: number of bins")
+		binScale    = flag.String("bin-scale", "linear", "Histogram mode: bin spacing, linear or log")
+		histMin     = flag.Float64("hist-min", 0, "Histogram mode: lower edge of the first bin in ETH (default: the smallest bid)")
+		histMax     = flag.Float64("hist-max", 0, "Histogram mode: upper edge of the last bin in ETH (default: the largest bid)")
+		stream      = flag.Bool("stream", false, "Summary, rolling and concentration modes with --sqlite: stream the range instead of loading it (summary percentiles are approximate)")
+		out         = flag.String("out", "", "Summary, rolling, concentration, histogram, montecarlo, compare and relays modes: also write the results to this file (.csv, .json or .parquet)")
+		chartPath   = flag.String("chart", "", "Rolling, concentration and montecarlo modes: plot the results (montecarlo: the profit surface) to this file (.png or .svg)")
+		topK        = flag.Int("top-k", 3, "Cartel size k of the montecarlo --chart profit surface and of compare mode")
+		lorenz      = flag.String("lorenz", "", "Concentration mode: write the builder Lorenz curves to this file (.json, else CSV)")
+		baseFees    = flag.String("base-fees", "", "Drivers mode: CSV of slot,base_fee_gwei rows to correlate bids with")
+		seed        = flag.Int64("seed", 0, "Random seed for simulations and sampling (0 picks one from the clock)")
+		sqlitePath  = flag.String("sqlite", "", "Load data from this SQLite database instead of --data")
+		startSlot   = flag.Uint64("start-slot", 0, "First slot to load from the database, and of the montecarlo cost window")
+		endSlot     = flag.Uint64("end-slot", ^uint64(0)>>1, "Last slot to load from the database")
+		from        = flag.String("from", "", "Load slots starting at or after this time (YYYY-MM-DD or RFC3339; with --to, overrides slots)")
+		to          = flag.String("to", "", "Load slots starting before this time (YYYY-MM-DD or RFC3339)")
+		gapPolicy   = flag.String("gap-policy", "fail", "Missing slots in the montecarlo cost window: fail, zero or median")
 	)
 	flag.Parse()
 
+	policy, err := model.ParseGapPolicy(*gapPolicy)
+	if err != nil {
+		log.Fatal(err)
+	}
+	levels, err := parseConfidenceLevels(*confidence)
+	if err != nil {
+		log.Fatal(err)
+	}
+	thresholds, err := parseLossThresholds(*lossLimits)
+	if err != nil {
+		log.Fatal(err)
+	}
+	scale, err := analysis.ParseBinScale(*binScale)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Check --out before a long analysis rather than after it
+	if *out != "" {
+		switch *mode {
+		case "summary", "rolling", "concentration", "histogram", "montecarlo", "compare", "relays", "episodes":
+		default:
+			log.Fatalf("--out is not supported in %s mode", *mode)
+		}
+		if _, err := dataset.FormatForPath(*out); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *chartPath != "" {
+		switch *mode {
+		case "rolling", "concentration", "montecarlo":
+		default:
+			log.Fatalf("--chart is not supported in %s mode", *mode)
+		}
+		if _, err := chart.FormatForPath(*chartPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Report the seed so published simulation numbers can be regenerated
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	if *episodes != "" {
+		curve := fitSuccessCurve(*episodes, model.SuccessCurveConfig{Credibility: *ciLevel, Seed: *seed})
+		if *mode == "episodes" {
+			runEpisodeAnalysis(curve, *tau, *out)
+			return
+		}
+		explicit := false
+		flag.Visit(func(f *flag.Flag) { explicit = explicit || f.Name == "success-prob" })
+		if explicit {
+			log.Fatal("--episodes sets the success probability; drop --success-prob")
+		}
+		point, ok := curve.At(*tau)
+		if !ok {
+			log.Fatalf("The episodes estimate p(τ) up to τ=%d, not τ=%d", curve.Horizon, *tau)
+		}
+		*successProb = point.Probability
+		fmt.Printf("Success probability p(τ=%d) = %.4f [%.4f, %.4f] from %d censorship episodes\n\n",
+			*tau, point.Probability, point.Lower, point.Upper, curve.Episodes)
+	} else if *mode == "episodes" {
+		log.Fatal("Episodes mode needs --episodes")
+	}
+
+	if *mode == "compare" {
+		// Loads its two ranges itself
+		runComparison(*dataFile, *sqlitePath, *beforeRange, *afterRange, analysis.ComparisonConfig{
+			Tau:          *tau,
+			TopK:         *topK,
+			SuccessProb:  *successProb,
+			Permutations: *permutation,
+			Seed:         *seed,
+		}, *out)
+		return
+	}
+
 	// Load data
-	bribes, err := loadBribesFromFile(*dataFile)
+	var bribes []model.SlotBribe
+	if *sqlitePath != "" {
+		if *from != "" || *to != "" {
+			if *startSlot, *endSlot, err = parseTimeWindow(*from, *to); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if *stream {
+			// At most one window of slots in memory however large the range
+			switch *mode {
+			case "summary":
+				if *bootstrap > 0 {
+					log.Fatal("--bootstrap needs the data in memory; drop --stream")
+				}
+				runStreamingSummary(*sqlitePath, *startSlot, *endSlot, *out)
+			case "rolling", "concentration":
+				runStreamingWindows(*mode, *sqlitePath, *startSlot, *endSlot, *windowSize, *out, *chartPath)
+			default:
+				log.Fatalf("--stream is not supported in %s mode", *mode)
+			}
+			return
+		}
+		bribes, err = loadBribesFromSQLite(*sqlitePath, *startSlot, *endSlot)
+	} else {
+		bribes, err = loadBribesFromFile(*dataFile)
+	}
 	if err != nil {
 		log.Fatalf("Failed to load data: %v", err)
 	}
@@ -42,31 +195,99 @@ func main() {
 
 	switch *mode {
 	case "summary":
-		runSummaryAnalysis(stats)
+		runSummaryAnalysis(stats, analysis.BootstrapConfig{Resamples: *bootstrap, Confidence: *ciLevel, BlockLen: *blockLen, Seed: *seed}, *out)
 
 	case "rolling":
-		runRollingAnalysis(stats, *windowSize)
+		runRollingAnalysis(stats.ComputeRollingStats(*windowSize), *windowSize, *out, *chartPath)
 
 	case "concentration":
-		runConcentrationAnalysis(stats, *windowSize)
+		runConcentrationAnalysis(stats, bribes, *windowSize, *lorenz, *out, *chartPath)
+
+	case "histogram":
+		config := analysis.HistogramConfig{Bins: *bins, Scale: scale}
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "hist-min":
+				config.MinETH = histMin
+			case "hist-max":
+				config.MaxETH = histMax
+			}
+		})
+		runHistogram(stats, config, *out)
+
+	case "anomalies":
+		runAnomalyDetection(stats, *windowSize)
+
+	case "seasonality":
+		runSeasonalityAnalysis(stats, *tau)
+
+	case "drivers":
+		runDriverAnalysis(stats, bribes, *sqlitePath, *baseFees, *windowSize)
 
 	case "predict":
-		runPrediction(stats, *tau, *ethPrice)
+		runPrediction(stats, *tau, *ethPrice, *method, *period, *arOrder, *diffOrder, *interval)
 
 	case "montecarlo":
-		runMonteCarloSimulation(bribes, *tau, *ethPrice, *bridgeTVL, *successProb, *simulations)
+		runMonteCarloSimulation(bribes, *startSlot, *tau, policy, *ethPrice, *bridgeTVL, *successProb, *simulations, *seed, *resample, *blockLen,
+			analysis.RiskConfig{ConfidenceLevels: levels, LossThresholds: thresholds},
+			analysis.PriceShock{Mean: *priceDrop, StdDev: *dropStdDev, ETHExposure: *ethExposure, BribeResponse: *bribeResp}, *out)
+		if *chartPath != "" {
+			plotProfitSurface(bribes, *tau, *topK, *ethPrice, *bridgeTVL, *chartPath)
+		}
+
+	case "relays":
+		runRelayAnalysis(bribes, *sqlitePath, *startSlot, *endSlot, *out)
+
+	case "backtest":
+		runBacktest(bribes, *windowSize, *tau, *simulations, *seed)
 
 	default:
 		log.Fatalf("Unknown mode: %s", *mode)
 	}
 }
 
-func runSummaryAnalysis(stats *analysis.Statistics) {
+func runSummaryAnalysis(stats *analysis.Statistics, bootstrap analysis.BootstrapConfig, outPath string) {
 	fmt.Println("Statistical Summary")
 	fmt.Println("===================")
 
 	summary := stats.ComputeSummary()
+	if bootstrap.Resamples > 0 {
+		// Stop resampling on Ctrl-C
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
 
+		var err error
+		if summary, err = stats.ComputeSummaryWithCI(ctx, bootstrap); err != nil {
+			log.Fatalf("Bootstrap failed: %v", err)
+		}
+	}
+	printSummary(summary)
+	writeResults(outPath, dataset.EncodeSummary(summary))
+}
+
+func runStreamingSummary(path string, startSlot, endSlot uint64, outPath string) {
+	store, err := storage.NewSQLiteStore(path)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer store.Close()
+
+	fmt.Println("Statistical Summary (streamed, approximate percentiles)")
+	fmt.Println("=======================================================")
+
+	source := storage.SlotSource(context.Background(), store, startSlot, endSlot)
+	summary, err := analysis.SummarizeBribes(source, analysis.DefaultCompression)
+	if err != nil {
+		log.Fatalf("Failed to summarize: %v", err)
+	}
+	if summary.Count == 0 {
+		log.Fatal("No bribe data loaded")
+	}
+	printSummary(summary)
+	writeResults(outPath, dataset.EncodeSummary(summary))
+}
+
+func printSummary(summary analysis.Summary) {
 	fmt.Printf("Count:        %d slots\n", summary.Count)
 	fmt.Printf("Total:        %.6f ETH\n", summary.TotalETH)
 	fmt.Printf("Mean:         %.6f ETH\n", summary.MeanETH)
@@ -78,14 +299,66 @@ func runSummaryAnalysis(stats *analysis.Statistics) {
 	fmt.Printf("75th pctl:    %.6f ETH\n", summary.P75ETH)
 	fmt.Printf("95th pctl:    %.6f ETH\n", summary.P95ETH)
 	fmt.Printf("99th pctl:    %.6f ETH\n", summary.P99ETH)
+
+	ci := summary.CI
+	if ci == nil {
+		return
+	}
+	blocks := "single slots"
+	if ci.BlockLen > 1 {
+		blocks = fmt.Sprintf("blocks of %d slots", ci.BlockLen)
+	}
+	fmt.Printf("\n%g%% bootstrap intervals (%d resamples of %s, seed %d):\n",
+		ci.Confidence*100, ci.Resamples, blocks, ci.Seed)
+	fmt.Printf("Mean:         [%.6f, %.6f] ETH\n", ci.MeanETH.Lower, ci.MeanETH.Upper)
+	fmt.Printf("Median:       [%.6f, %.6f] ETH\n", ci.MedianETH.Lower, ci.MedianETH.Upper)
+	fmt.Printf("25th pctl:    [%.6f, %.6f] ETH\n", ci.P25ETH.Lower, ci.P25ETH.Upper)
+	fmt.Printf("75th pctl:    [%.6f, %.6f] ETH\n", ci.P75ETH.Lower, ci.P75ETH.Upper)
+	fmt.Printf("95th pctl:    [%.6f, %.6f] ETH\n", ci.P95ETH.Lower, ci.P95ETH.Upper)
+	fmt.Printf("99th pctl:    [%.6f, %.6f] ETH\n", ci.P99ETH.Lower, ci.P99ETH.Upper)
 }
 
-func runRollingAnalysis(stats *analysis.Statistics, windowSize int) {
+// runStreamingWindows runs the rolling or concentration mode over a stream
+// of the database's slot range, holding one window of slots and the
+// per-window results rather than every slot.
+func runStreamingWindows(mode, path string, startSlot, endSlot uint64, windowSize int, outPath, chartPath string) {
+	store, err := storage.NewSQLiteStore(path)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer store.Close()
+
+	source := storage.SlotSource(context.Background(), store, startSlot, endSlot)
+	if mode == "rolling" {
+		var rolling []analysis.RollingStatistics
+		err = analysis.StreamRollingStats(source, windowSize, func(r analysis.RollingStatistics) error {
+			rolling = append(rolling, r)
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("Failed to compute rolling statistics: %v", err)
+		}
+		runRollingAnalysis(rolling, windowSize, outPath, chartPath)
+		return
+	}
+
+	var trends []analysis.ConcentrationTrend
+	err = analysis.StreamConcentrationTrends(source, windowSize, func(t analysis.ConcentrationTrend) error {
+		trends = append(trends, t)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to compute concentration trends: %v", err)
+	}
+	fmt.Printf("Builder Concentration Trends (window=%d)\n", windowSize)
+	fmt.Println("=========================================")
+	printConcentrationTrends(trends, outPath, chartPath)
+}
+
+func runRollingAnalysis(rolling []analysis.RollingStatistics, windowSize int, outPath, chartPath string) {
 	fmt.Printf("Rolling Statistics (window=%d)\n", windowSize)
 	fmt.Println("===============================")
 
-	rolling := stats.ComputeRollingStats(windowSize)
-
 	if len(rolling) == 0 {
 		fmt.Println("Not enough data for rolling analysis")
 		return
@@ -107,17 +380,74 @@ func runRollingAnalysis(stats *analysis.Statistics, windowSize int) {
 				r.Slot, r.MeanETH, r.StdDevETH, r.MinETH, r.MaxETH)
 		}
 	}
+	writeResults(outPath, dataset.EncodeRollingStats(rolling))
+	if chartPath != "" {
+		p, err := chart.RollingCost(rolling)
+		if err != nil {
+			log.Fatalf("Failed to plot rolling cost: %v", err)
+		}
+		saveChart(chartPath, p)
+	}
 }
 
-func runConcentrationAnalysis(stats *analysis.Statistics, windowSize int) {
+func runConcentrationAnalysis(stats *analysis.Statistics, bribes []model.SlotBribe, windowSize int, lorenzPath, outPath, chartPath string) {
 	fmt.Printf("Builder Concentration Trends (window=%d)\n", windowSize)
 	fmt.Println("=========================================")
 
-	trends := stats.ComputeConcentrationTrends(windowSize)
+	if !printConcentrationTrends(stats.ComputeConcentrationTrends(windowSize), outPath, chartPath) {
+		return
+	}
 
+	// Structural shifts invalidate thresholds computed across them
+	if shifts, err := stats.DetectConcentrationShifts(windowSize, 0); err == nil {
+		fmt.Printf("\nStructural shifts (%d):\n", len(shifts))
+		for _, shift := range shifts {
+			fmt.Printf("Slot %d: %s %.3f -> %.3f\n", shift.Slot, shift.Metric, shift.Before, shift.After)
+		}
+	}
+
+	// Entry, exit and share turnover show whether the market is centralizing
+	if daily, err := model.ComputeMarketShareSeries(bribes, model.SlotsPerDay, nil); err == nil {
+		fmt.Printf("\nDaily market share (%d days):\n", len(daily.Periods))
+		fmt.Printf("HHI trend:     %+.4f per day\n", daily.HHITrend)
+		fmt.Printf("Entries/exits: %d/%d builders\n", daily.Entries, daily.Exits)
+		fmt.Printf("Churn rate:    %.3f per day\n", daily.ChurnRate)
+		fmt.Printf("Turnover:      %.3f of the market per day\n", daily.MeanTurnover)
+	}
+
+	// Nakamoto coefficient over the whole dataset
+	nakamoto, err := model.NakamotoCoefficient(bribes, model.DefaultNakamotoThreshold)
+	if err != nil {
+		log.Fatalf("Nakamoto coefficient failed: %v", err)
+	}
+	fmt.Printf("\nNakamoto coefficient (>%.0f%%): %d builders by blocks, %d by value\n",
+		nakamoto.Threshold*100, nakamoto.ByBlocks, nakamoto.ByValue)
+
+	gini, err := model.GiniCoefficient(bribes)
+	if err != nil {
+		log.Fatalf("Gini coefficient failed: %v", err)
+	}
+	fmt.Printf("Gini coefficient: %.3f by blocks, %.3f by value\n", gini.ByBlocks, gini.ByValue)
+
+	if lorenzPath != "" {
+		curves, err := model.ComputeLorenzCurves(bribes, 0, 0)
+		if err != nil {
+			log.Fatalf("Lorenz curves failed: %v", err)
+		}
+		if err := writeLorenzCurves(lorenzPath, curves); err != nil {
+			log.Fatalf("Failed to write Lorenz curves: %v", err)
+		}
+		fmt.Printf("Lorenz curves written to %s\n", lorenzPath)
+	}
+}
+
+// printConcentrationTrends prints the first and last windows' trends and
+// their averages, and writes them to outPath and chartPath if given. It
+// returns false if there are no trends.
+func printConcentrationTrends(trends []analysis.ConcentrationTrend, outPath, chartPath string) bool {
 	if len(trends) == 0 {
 		fmt.Println("Not enough data for concentration analysis")
-		return
+		return false
 	}
 
 	// Print summary of trends
@@ -150,45 +480,462 @@ func runConcentrationAnalysis(stats *analysis.Statistics, windowSize int) {
 	fmt.Printf("Avg α(top3): %.3f\n", avgTop3/n)
 	fmt.Printf("Avg α(top5): %.3f\n", avgTop5/n)
 	fmt.Printf("Avg HHI:     %.3f\n", avgHHI/n)
+	writeResults(outPath, dataset.EncodeConcentrationTrends(trends))
+	if chartPath != "" {
+		p, err := chart.ConcentrationTrends(trends)
+		if err != nil {
+			log.Fatalf("Failed to plot concentration trends: %v", err)
+		}
+		saveChart(chartPath, p)
+	}
+	return true
+}
+
+// writeResults writes table to path, in the format of its extension,
+// unless path is empty.
+func writeResults(path string, table dataset.Table) {
+	if path == "" {
+		return
+	}
+	format, err := dataset.FormatForPath(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Failed to create output file: %v", err)
+	}
+	err = dataset.WriteTable(f, table, format)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Fatalf("Failed to write results: %v", err)
+	}
+	fmt.Printf("Results written to %s\n", path)
+}
+
+// plotProfitSurface plots attacker profit over success probabilities from
+// 0 to 1 and bridge TVLs up to twice bridgeTVL (USD), with k builders in
+// the cartel.
+func plotProfitSurface(bribes []model.SlotBribe, tau uint64, topK int, ethPrice, bridgeTVL float64, path string) {
+	maxTVL := currency.USDToWei(2*bridgeTVL, ethPrice)
+	grid, err := model.SweepProfitGrid(context.Background(), bribes, tau, topK, 0, 1, 41, big.NewFloat(0), maxTVL, 41)
+	if err != nil {
+		log.Fatalf("Failed to compute profit surface: %v", err)
+	}
+	p, err := chart.ProfitSurface(grid)
+	if err != nil {
+		log.Fatalf("Failed to plot profit surface: %v", err)
+	}
+	saveChart(path, p)
+}
+
+func saveChart(path string, p *plot.Plot) {
+	if err := chart.Save(p, path); err != nil {
+		log.Fatalf("Failed to save chart: %v", err)
+	}
+	fmt.Printf("Chart written to %s\n", path)
 }
 
-func runPrediction(stats *analysis.Statistics, tau uint64, ethPrice float64) {
+// writeLorenzCurves writes curves as JSON if path ends in .json, else CSV.
+func writeLorenzCurves(path string, curves model.LorenzCurves) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if strings.HasSuffix(path, ".json") {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(curves)
+	} else {
+		err = dataset.WriteLorenzCSV(f, curves)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func runHistogram(stats *analysis.Statistics, config analysis.HistogramConfig, outPath string) {
+	title := fmt.Sprintf("Bid Histogram (%s bins)", config.Scale)
+	fmt.Println(title)
+	fmt.Println(strings.Repeat("=", len(title)))
+
+	h, err := stats.ComputeHistogram(config)
+	if err != nil {
+		log.Fatalf("Histogram failed: %v", err)
+	}
+
+	// Bars scaled to the fullest bin
+	peak := 1
+	for _, count := range h.Counts {
+		peak = max(peak, count)
+	}
+	for i, count := range h.Counts {
+		fmt.Printf("[%10.6f, %10.6f) %8d %s\n", h.Edges[i], h.Edges[i+1], count, strings.Repeat("#", count*40/peak))
+	}
+	if h.Underflow > 0 {
+		fmt.Printf("\nBelow %.6f ETH: %d slots\n", h.Edges[0], h.Underflow)
+	}
+	if h.Overflow > 0 {
+		fmt.Printf("Above %.6f ETH: %d slots\n", h.Edges[len(h.Edges)-1], h.Overflow)
+	}
+	writeResults(outPath, dataset.EncodeHistogram(h))
+}
+
+// runComparison compares two slot ranges of the database at sqlitePath, or
+// else of the data file.
+func runComparison(dataFile, sqlitePath, beforeRange, afterRange string, config analysis.ComparisonConfig, outPath string) {
+	if beforeRange == "" || afterRange == "" {
+		log.Fatal("compare mode needs --before and --after")
+	}
+	var ranges [2][2]uint64
+	for i, s := range []string{beforeRange, afterRange} {
+		start, end, err := parseSlotRange(s)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ranges[i] = [2]uint64{start, end}
+	}
+
+	var loaded [2][]model.SlotBribe
+	if sqlitePath != "" {
+		for i, r := range ranges {
+			bribes, err := loadBribesFromSQLite(sqlitePath, r[0], r[1])
+			if err != nil {
+				log.Fatalf("Failed to load data: %v", err)
+			}
+			loaded[i] = bribes
+		}
+	} else {
+		bribes, err := loadBribesFromFile(dataFile)
+		if err != nil {
+			log.Fatalf("Failed to load data: %v", err)
+		}
+		for _, bribe := range bribes {
+			for i, r := range ranges {
+				if bribe.Slot >= r[0] && bribe.Slot <= r[1] {
+					loaded[i] = append(loaded[i], bribe)
+				}
+			}
+		}
+	}
+
+	// Stop the permutation tests on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	comparison, err := analysis.CompareRanges(ctx, loaded[0], loaded[1], config)
+	if err != nil {
+		log.Fatalf("Comparison failed: %v", err)
+	}
+
+	fmt.Println("Range Comparison")
+	fmt.Println("================")
+	fmt.Printf("Before: slots %d-%d (%d slots)\n", ranges[0][0], ranges[0][1], comparison.BeforeSlots)
+	fmt.Printf("After:  slots %d-%d (%d slots)\n", ranges[1][0], ranges[1][1], comparison.AfterSlots)
+	fmt.Printf("τ=%d, k=%d, p=%.2f, %d permutations, seed %d\n\n",
+		config.Tau, config.TopK, config.SuccessProb, comparison.Config.Permutations, config.Seed)
+
+	fmt.Printf("%-20s %14s %14s %9s %9s  %s\n", "Metric", "Before", "After", "Change", "p-value", "Test")
+	for _, m := range comparison.Metrics {
+		pValue := "-"
+		if !math.IsNaN(m.PValue) {
+			pValue = fmt.Sprintf("%.4f", m.PValue)
+		}
+		change := "-"
+		if !math.IsNaN(m.RelativeChange) {
+			change = fmt.Sprintf("%+.1f%%", m.RelativeChange*100)
+		}
+		test := "-"
+		if m.Test != analysis.TestNone {
+			test = string(m.Test)
+		}
+		fmt.Printf("%-20s %14.6g %14.6g %9s %9s  %s\n", m.Metric, m.Before, m.After, change, pValue, test)
+	}
+	writeResults(outPath, dataset.EncodeComparison(comparison))
+}
+
+// runRelayAnalysis compares relays over every relay's reports in the
+// database, or else over the loaded bribes, whose relays come from the
+// data file.
+func runRelayAnalysis(bribes []model.SlotBribe, sqlitePath string, startSlot, endSlot uint64, outPath string) {
+	fmt.Println("Relay Comparison")
+	fmt.Println("================")
+
+	reports := bribes
+	if sqlitePath != "" {
+		store, err := storage.NewSQLiteStore(sqlitePath)
+		if err != nil {
+			log.Fatalf("Failed to open database: %v", err)
+		}
+		defer store.Close()
+		if reports, err = store.GetRelayReports(context.Background(), startSlot, endSlot); err != nil {
+			log.Fatalf("Failed to load relay reports: %v", err)
+		}
+	}
+
+	comparison, err := model.CompareRelays(reports)
+	if err != nil {
+		log.Fatalf("Relay comparison failed: %v", err)
+	}
+	fmt.Printf("Slots: %d (%d reported by several relays, %d disputed)\n\n",
+		comparison.Slots, comparison.MultiRelaySlots, comparison.DisputedSlots)
+
+	fmt.Printf("%-40s %9s %8s %10s %8s %8s %7s %9s %12s\n",
+		"Relay", "Slots", "Coverage", "Mean ETH", "Builders", "Top", "HHI", "Disagree", "Deviation")
+	for _, r := range comparison.Relays {
+		fmt.Printf("%-40s %9d %7.1f%% %10.6f %8d %7.1f%% %7.3f %8.1f%% %12.6f\n",
+			r.RelayURL, r.Slots, r.Coverage*100, currency.WeiToETHFloat64(r.MeanBidWei), r.UniqueBuilders,
+			r.TopBuilderShare*100, r.BuilderHHI, r.DisagreementRate*100, currency.WeiToETHFloat64(r.MeanDeviationWei))
+	}
+	writeResults(outPath, dataset.EncodeRelayComparison(comparison))
+}
+
+func fitSuccessCurve(path string, config model.SuccessCurveConfig) *model.SuccessCurve {
+	episodes, err := model.LoadCensorshipEpisodes(path)
+	if err != nil {
+		log.Fatalf("Failed to load episodes: %v", err)
+	}
+	curve, err := model.EstimateSuccessCurve(episodes, config)
+	if err != nil {
+		log.Fatalf("Success curve estimation failed: %v", err)
+	}
+	return curve
+}
+
+func runEpisodeAnalysis(curve *model.SuccessCurve, tau uint64, outPath string) {
+	fmt.Println("Empirical Success Probability p(τ)")
+	fmt.Println("==================================")
+	fmt.Printf("Episodes: %d (%d ended in inclusion, %d right-censored)\n",
+		curve.Episodes, curve.Included, curve.Episodes-curve.Included)
+	fmt.Printf("Estimated up to τ=%d slots, %.0f%% credible intervals (seed %d)\n\n",
+		curve.Horizon, curve.Credibility*100, curve.Seed)
+
+	fmt.Printf("%10s %10s %10s %10s %8s\n", "τ (slots)", "p(τ)", "Lower", "Upper", "At risk")
+	for _, p := range curve.Points {
+		fmt.Printf("%10d %10.4f %10.4f %10.4f %8d\n", p.Tau, p.Probability, p.Lower, p.Upper, p.AtRisk)
+	}
+
+	if point, ok := curve.At(tau); ok {
+		fmt.Printf("\np(τ=%d) = %.4f [%.4f, %.4f]\n", tau, point.Probability, point.Lower, point.Upper)
+	} else {
+		fmt.Printf("\np(τ=%d): beyond the longest observed episode\n", tau)
+	}
+	writeResults(outPath, dataset.EncodeSuccessCurve(curve))
+}
+
+func runAnomalyDetection(stats *analysis.Statistics, windowSize int) {
+	fmt.Printf("Bid Anomalies (baseline window=%d)\n", windowSize)
+	fmt.Println("=================================")
+
+	config := analysis.DefaultAnomalyConfig()
+	config.Window = windowSize
+	anomalies, err := stats.DetectBidAnomalies(config)
+	if err != nil {
+		log.Fatalf("Anomaly detection failed: %v", err)
+	}
+	if len(anomalies) == 0 {
+		fmt.Println("No anomalies")
+		return
+	}
+
+	for _, a := range anomalies {
+		switch a.Kind {
+		case analysis.AnomalyHighBid:
+			fmt.Printf("Slot %d: high bid %.4f ETH (baseline %.4f, score %.1f)\n",
+				a.StartSlot, a.ValueETH, a.BaselineETH, a.Score)
+		case analysis.AnomalyLowStretch:
+			fmt.Printf("Slots %d-%d: low stretch of %d slots, mean %.4f ETH (baseline %.4f, score <= %.1f)\n",
+				a.StartSlot, a.EndSlot, a.EndSlot-a.StartSlot+1, a.ValueETH, a.BaselineETH, a.Score)
+		}
+	}
+}
+
+func runSeasonalityAnalysis(stats *analysis.Statistics, tau uint64) {
+	fmt.Println("Bid Seasonality (UTC)")
+	fmt.Println("=====================")
+
+	seasons, err := stats.ComputeSeasonality(model.Mainnet)
+	if err != nil {
+		log.Fatalf("Seasonality failed: %v", err)
+	}
+
+	fmt.Println("\nHour of day:")
+	for h, b := range seasons.ByHour {
+		fmt.Printf("%02d:00  mean %.4f ETH  median %.4f ETH  %.2fx  (%d slots)\n", h, b.MeanETH, b.MedianETH, b.Factor, b.Count)
+	}
+	fmt.Println("\nDay of week:")
+	for d, b := range seasons.ByWeekday {
+		fmt.Printf("%-9s  mean %.4f ETH  median %.4f ETH  %.2fx  (%d slots)\n", time.Weekday(d), b.MeanETH, b.MedianETH, b.Factor, b.Count)
+	}
+
+	window, err := seasons.CheapestWindow(tau)
+	if err != nil {
+		log.Fatalf("Cheapest window failed: %v", err)
+	}
+	fmt.Printf("\nCheapest %d-slot window: %s %02d:00 UTC, %.4f ETH expected (%.2fx an average window)\n",
+		tau, window.Weekday, window.Hour, window.CostETH, window.Ratio())
+}
+
+func runDriverAnalysis(stats *analysis.Statistics, bribes []model.SlotBribe, sqlitePath, baseFeePath string, windowSize int) {
+	fmt.Printf("Bid Drivers (rolling window=%d)\n", windowSize)
+	fmt.Println("==============================")
+
+	var series []analysis.DriverSeries
+	if baseFeePath != "" {
+		fees, err := loadSlotSeriesCSV(baseFeePath)
+		if err != nil {
+			log.Fatalf("Failed to load base fees: %v", err)
+		}
+		series = append(series, analysis.DriverSeries{Name: "base_fee_gwei", Values: fees})
+	}
+	if sqlitePath != "" {
+		store, err := storage.NewSQLiteStore(sqlitePath)
+		if err != nil {
+			log.Fatalf("Failed to open database: %v", err)
+		}
+		// Hourly samples of the stored quotes
+		prices, err := analysis.SamplePrices(context.Background(), store, model.Mainnet,
+			bribes[0].Slot, bribes[len(bribes)-1].Slot, 300)
+		store.Close()
+		if err != nil {
+			log.Fatalf("Failed to sample ETH prices: %v", err)
+		}
+		if len(prices) > 0 {
+			series = append(series, analysis.DriverSeries{Name: "eth_usd", Values: prices})
+		}
+	}
+	if len(series) == 0 {
+		log.Fatal("No driver series: pass --base-fees, or --sqlite with stored ETH prices")
+	}
+
+	joined, err := stats.JoinDrivers(series...)
+	if err != nil {
+		log.Fatalf("Failed to join driver series: %v", err)
+	}
+	analyses, err := joined.Analyze(windowSize)
+	if err != nil {
+		log.Fatalf("Driver analysis failed: %v", err)
+	}
+	fmt.Printf("Joined %d bids\n", len(joined.Slots))
+
+	for _, a := range analyses {
+		fmt.Printf("\n%s:\n", a.Name)
+		fmt.Printf("  Pearson r:      %+.3f\n", a.Correlation)
+		fmt.Printf("  Spearman ρ:     %+.3f\n", a.RankCorrelation)
+		fmt.Printf("  Regression:     bid = %.6f + %.6f × %s ETH (R²=%.3f)\n", a.Intercept, a.Slope, a.Name, a.RSquared)
+		if len(a.Rolling) > 0 {
+			rolling := append([]float64(nil), a.Rolling...)
+			sort.Float64s(rolling)
+			fmt.Printf("  Rolling r:      min %+.3f, median %+.3f, max %+.3f, latest %+.3f\n",
+				rolling[0], rolling[len(rolling)/2], rolling[len(rolling)-1], a.Rolling[len(a.Rolling)-1])
+		}
+	}
+
+	if len(series) > 1 {
+		reg, err := joined.Regress(joined.Names...)
+		if err != nil {
+			log.Fatalf("Regression failed: %v", err)
+		}
+		fmt.Printf("\nJoint regression (R²=%.3f):\n  intercept %.6f ETH\n", reg.RSquared, reg.Intercept)
+		for i, name := range reg.Names {
+			fmt.Printf("  %-14s %.6f ETH per unit\n", name, reg.Coefficients[i])
+		}
+	}
+}
+
+func runPrediction(stats *analysis.Statistics, tau uint64, ethPrice float64, method string, period, p, d int, interval float64) {
 	fmt.Printf("Cost Prediction (τ=%d slots)\n", tau)
 	fmt.Println("============================")
 
-	// Use EMA with alpha=0.1
-	predictedCost, err := stats.PredictFutureCost(tau, 0.1)
+	var forecast analysis.CostForecast
+	var err error
+	switch method {
+	case analysis.ForecastEMA:
+		// Use EMA with alpha=0.1
+		predictedCost, err := stats.PredictFutureCost(tau, 0.1)
+		if err != nil {
+			log.Fatalf("Prediction failed: %v", err)
+		}
+
+		fmt.Printf("Predicted total cost: %.4f ETH\n", predictedCost)
+		fmt.Printf("Predicted cost (USD): $%.2f\n", predictedCost*ethPrice)
+		fmt.Printf("Average per slot:     %.6f ETH\n", predictedCost/float64(tau))
+		return
+	case analysis.ForecastHoltWinters:
+		forecast, err = stats.ForecastHoltWinters(tau, period, interval)
+	case analysis.ForecastARIMA:
+		forecast, err = stats.ForecastARIMA(tau, p, d, interval)
+	default:
+		log.Fatalf("Unknown forecasting method: %s", method)
+	}
 	if err != nil {
 		log.Fatalf("Prediction failed: %v", err)
 	}
 
-	fmt.Printf("Predicted total cost: %.4f ETH\n", predictedCost)
-	fmt.Printf("Predicted cost (USD): $%.2f\n", predictedCost*ethPrice)
-	fmt.Printf("Average per slot:     %.6f ETH\n", predictedCost/float64(tau))
+	params := make([]string, 0, len(forecast.Params))
+	for name, value := range forecast.Params {
+		params = append(params, fmt.Sprintf("%s=%g", name, value))
+	}
+	sort.Strings(params)
+
+	fmt.Printf("Method:               %s (%s)\n", forecast.Method, strings.Join(params, " "))
+	fmt.Printf("Predicted total cost: %.4f ETH\n", forecast.CostETH)
+	label := fmt.Sprintf("%g%% interval:", forecast.Confidence*100)
+	fmt.Printf("%-22s[%.4f, %.4f] ETH\n", label, forecast.LowerETH, forecast.UpperETH)
+	fmt.Printf("Predicted cost (USD): $%.2f\n", forecast.CostETH*ethPrice)
+	fmt.Printf("Average per slot:     %.6f ETH\n", forecast.CostETH/float64(tau))
 }
 
-func runMonteCarloSimulation(bribes []model.SlotBribe, tau uint64, ethPrice, bridgeTVL, successProb float64, numSims int) {
+func runMonteCarloSimulation(bribes []model.SlotBribe, startSlot, tau uint64, policy model.GapPolicy, ethPrice, bridgeTVL, successProb float64, numSims int, seed int64, resample bool, blockLen int, risk analysis.RiskConfig, shock analysis.PriceShock, outPath string) {
 	fmt.Printf("Monte Carlo Simulation (%d runs)\n", numSims)
 	fmt.Println("=================================")
 
-	// Compute actual censorship cost
-	cost, err := model.CensorshipCost(bribes, tau)
+	// Compute actual censorship cost, over the tau slots from --start-slot
+	// when one is given
+	var cost *big.Int
+	var err error
+	if startSlot > 0 && tau > 0 {
+		cost, err = model.CensorshipCostRangeBy(bribes, startSlot, startSlot+tau-1, policy)
+	} else {
+		cost, err = model.CensorshipCost(bribes, tau)
+	}
 	if err != nil {
 		log.Fatalf("Failed to compute cost: %v", err)
 	}
 
-	weiPerEth := new(big.Float).SetInt(big.NewInt(1e18))
-	costETH, _ := new(big.Float).Quo(new(big.Float).SetInt(cost), weiPerEth).Float64()
+	costETH := currency.WeiToETHFloat64(cost)
 
 	fmt.Printf("\nInput Parameters:\n")
 	fmt.Printf("Censorship Cost:     %.4f ETH ($%.2f)\n", costETH, costETH*ethPrice)
 	fmt.Printf("Bridge TVL:          $%.2f\n", bridgeTVL)
 	fmt.Printf("Success Probability: %.2f%%\n", successProb*100)
 	fmt.Printf("Simulations:         %d\n", numSims)
+	if windows, err := model.FindCheapestWindow(bribes, tau); err == nil {
+		cheapest := currency.WeiToETHFloat64(windows.Cheapest.CostWei)
+		dearest := currency.WeiToETHFloat64(windows.MostExpensive.CostWei)
+		fmt.Printf("Cheapest Window:     %.4f ETH from slot %d (most expensive %.4f ETH from slot %d)\n",
+			cheapest, windows.Cheapest.StartSlot, dearest, windows.MostExpensive.StartSlot)
+	}
+	if fits, err := model.FitBidDistributions(bribes); err == nil {
+		printBidFits(fits, tau, numSims, seed)
+	}
 	fmt.Println()
 
-	result := analysis.SimulateAttackOutcomes(costETH, bridgeTVL, ethPrice, successProb, numSims)
+	var result analysis.MonteCarloResult
+	if resample {
+		// Cost uncertainty from history, not just success or failure
+		result, err = analysis.SimulateAttackOutcomesResampled(bribes, tau, blockLen, bridgeTVL, ethPrice, successProb, shock, numSims, seed, risk)
+	} else {
+		result, err = analysis.SimulateAttackOutcomes(costETH, bridgeTVL, ethPrice, successProb, shock, numSims, seed, risk)
+	}
+	if err != nil {
+		log.Fatalf("Simulation failed: %v", err)
+	}
 	analysis.PrintMonteCarloResult(result)
+	writeResults(outPath, dataset.EncodeMonteCarloResult(result))
 
 	// Breakeven analysis
 	fmt.Println("\nBreakeven Analysis")
@@ -198,6 +945,196 @@ func runMonteCarloSimulation(bribes []model.SlotBribe, tau uint64, ethPrice, bri
 	fmt.Printf("Profit Margin:       %.2f%%\n", breakeven.ProfitMarginPercent)
 }
 
+func runBacktest(bribes []model.SlotBribe, trainSize int, tau uint64, samples int, seed int64) {
+	fmt.Printf("Forecast Backtest (train=%d, τ=%d slots)\n", trainSize, tau)
+	fmt.Println("=======================================")
+
+	// Stop refitting windows on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	rng := rand.New(rand.NewSource(seed))
+	result, err := analysis.BacktestCostForecasts(ctx, bribes, trainSize, tau, 0.1, samples, rng)
+	if err != nil {
+		log.Fatalf("Backtest failed: %v", err)
+	}
+
+	for _, w := range result.Windows {
+		fmt.Printf("Slot %d: realized=%.4f ema=%.4f %s p50=%.4f [p5=%.4f p95=%.4f] ETH\n",
+			w.StartSlot, w.RealizedETH, w.EMAETH, w.Distribution, w.MedianETH, w.P5ETH, w.P95ETH)
+	}
+
+	fmt.Printf("\nWindows:            %d\n", len(result.Windows))
+	for _, score := range []analysis.ForecastScore{result.EMA, result.Distribution} {
+		fmt.Printf("%-13s       MAPE=%.2f%% bias=%+.4f ETH\n", score.Method+":", score.MAPE*100, score.BiasETH)
+	}
+	fmt.Printf("p5-p95 coverage:    %.2f%% (90%% if calibrated)\n", result.Coverage90*100)
+	fmt.Printf("Below median:       %.2f%% (50%% if calibrated)\n", result.BelowMedian*100)
+	fmt.Printf("Seed:               %d\n", seed)
+}
+
+// printBidFits reports how well each candidate distribution fits the
+// bids and the spread of C_c over windows sampled from the best one.
+func printBidFits(fits []model.BidFit, tau uint64, numSims int, seed int64) {
+	for _, fit := range fits {
+		fmt.Printf("Bid Fit %-12s KS=%.4f AIC=%.1f\n", "("+fit.Distribution.Name()+"):", fit.KS, fit.AIC)
+	}
+	if numSims < 1 {
+		return
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	costs := make([]float64, numSims)
+	for i := range costs {
+		costs[i] = currency.WeiToETHFloat64(fits[0].SampleWindow(rng, tau))
+	}
+	sort.Float64s(costs)
+	fmt.Printf("Sampled C_c (%s):   p5=%.4f p50=%.4f p95=%.4f ETH\n", fits[0].Distribution.Name(),
+		costs[numSims*5/100], costs[numSims/2], costs[numSims*95/100])
+}
+
+func loadBribesFromSQLite(path string, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	store, err := storage.NewSQLiteStore(path)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	bribes, err := store.GetSlotRange(ctx, startSlot, endSlot)
+	if err != nil || len(bribes) == 0 {
+		return bribes, err
+	}
+
+	// Warn about holes between the first and last stored slot
+	gaps, err := store.FindGaps(ctx, bribes[0].Slot, bribes[len(bribes)-1].Slot)
+	if err != nil {
+		return nil, err
+	}
+	if len(gaps) > 0 {
+		log.Printf("Warning: %d slots missing in %d gaps between slots %d and %d; results cover incomplete data",
+			storage.MissingSlots(gaps), len(gaps), bribes[0].Slot, bribes[len(bribes)-1].Slot)
+	}
+
+	return bribes, nil
+}
+
+// parseTimeWindow converts a [from, to) wall-clock window into the
+// inclusive range of mainnet slots starting inside it.
+func parseTimeWindow(from, to string) (uint64, uint64, error) {
+	if from == "" || to == "" {
+		return 0, 0, fmt.Errorf("--from and --to must be given together")
+	}
+	fromTime, err := parseDate(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	toTime, err := parseDate(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	return model.Mainnet.SlotRangeForTimes(fromTime, toTime)
+}
+
+// parseConfidenceLevels parses a comma-separated list of levels such as
+// "0.95,0.99".
+func parseConfidenceLevels(s string) ([]float64, error) {
+	var levels []float64
+	for _, field := range strings.Split(s, ",") {
+		level, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil || !(level > 0 && level < 1) {
+			return nil, fmt.Errorf("invalid confidence level '%s' (expected a value in (0, 1))", field)
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+func parseLossThresholds(s string) ([]float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var thresholds []float64
+	for _, field := range strings.Split(s, ",") {
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil || !(threshold >= 0) || math.IsInf(threshold, 1) {
+			return nil, fmt.Errorf("invalid loss threshold '%s' (expected a non-negative amount in USD)", field)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
+}
+
+// parseSlotRange parses START..END as an inclusive range of slots, or as
+// a [START, END) window of dates converted to the mainnet slots starting
+// inside it.
+func parseSlotRange(s string) (uint64, uint64, error) {
+	from, to, ok := strings.Cut(s, "..")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid range '%s' (expected START..END)", s)
+	}
+	start, err1 := strconv.ParseUint(from, 10, 64)
+	end, err2 := strconv.ParseUint(to, 10, 64)
+	if err1 == nil && err2 == nil {
+		if end < start {
+			return 0, 0, fmt.Errorf("invalid range '%s': end precedes start", s)
+		}
+		return start, end, nil
+	}
+	fromTime, err := parseDate(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	toTime, err := parseDate(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	return model.Mainnet.SlotRangeForTimes(fromTime, toTime)
+}
+
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s' (expected YYYY-MM-DD or RFC3339)", s)
+	}
+	return t.UTC(), nil
+}
+
+// loadSlotSeriesCSV reads slot,value rows, skipping a header row, and sorts
+// them by slot.
+func loadSlotSeriesCSV(path string) ([]analysis.SlotValue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	var values []analysis.SlotValue
+	for i, record := range records {
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d: expected slot,value", i+1)
+		}
+		slot, err1 := strconv.ParseUint(strings.TrimSpace(record[0]), 10, 64)
+		value, err2 := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err1 != nil || err2 != nil {
+			if i == 0 {
+				continue // Header
+			}
+			return nil, fmt.Errorf("line %d: invalid slot or value", i+1)
+		}
+		values = append(values, analysis.SlotValue{Slot: slot, Value: value})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Slot < values[j].Slot })
+	return values, nil
+}
+
 func loadBribesFromFile(filename string) ([]model.SlotBribe, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
@@ -3,7 +3,10 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"math"
 	"math/big"
 	"net/http"
 	"os"
@@ -17,15 +20,20 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/time/rate"
 
+	"insolventbydesign/internal/analysis"
+	"insolventbydesign/internal/currency"
 	"insolventbydesign/internal/model"
+	"insolventbydesign/internal/relay"
 	"insolventbydesign/internal/storage"
 )
 
 // APIServer provides HTTP endpoints for censorship cost analysis.
 type APIServer struct {
-	store       *storage.PostgresStore
+	store       storage.Store
 	rateLimiter *rate.Limiter
 	metrics     *Metrics
+	labels      *model.BuilderRegistry // Optional pubkey → entity labels
+	maxDataAge  time.Duration          // Newest slot age beyond which /health reports stale; 0 disables
 }
 
 // Metrics tracks API performance.
@@ -33,6 +41,7 @@ type Metrics struct {
 	requestsTotal   *prometheus.CounterVec
 	requestDuration *prometheus.HistogramVec
 	activeRequests  prometheus.Gauge
+	analysisCache   *prometheus.CounterVec
 }
 
 func newMetrics() *Metrics {
@@ -58,27 +67,111 @@ func newMetrics() *Metrics {
 				Help: "Number of active API requests",
 			},
 		),
+		analysisCache: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_analysis_cache_total",
+				Help: "Censorship cost lookups served from stored analyses (hit) or recomputed because none was stored (miss) or its range changed (stale)",
+			},
+			[]string{"result"},
+		),
 	}
 
-	prometheus.MustRegister(m.requestsTotal, m.requestDuration, m.activeRequests)
+	prometheus.MustRegister(m.requestsTotal, m.requestDuration, m.activeRequests, m.analysisCache)
 	return m
 }
 
-func NewAPIServer(store *storage.PostgresStore) *APIServer {
+// anomalyCollector detects bid anomalies among the most recent slots on
+// every scrape, so Prometheus alerting rules (monitoring/alerts.yml) fire
+// on them without a separate job.
+type anomalyCollector struct {
+	store  storage.Store
+	slots  uint64 // Recent slots reported on
+	config analysis.AnomalyConfig
+	count  *prometheus.Desc
+	latest *prometheus.Desc
+}
+
+func newAnomalyCollector(store storage.Store, slots uint64) *anomalyCollector {
+	return &anomalyCollector{
+		store:  store,
+		slots:  slots,
+		config: analysis.DefaultAnomalyConfig(),
+		count: prometheus.NewDesc("bid_anomalies",
+			"Bid anomalies among the most recent slots, by kind (high_bid or low_stretch)",
+			[]string{"kind"}, nil),
+		latest: prometheus.NewDesc("bid_anomaly_latest_slot",
+			"Last slot of the most recent bid anomaly of each kind",
+			[]string{"kind"}, nil),
+	}
+}
+
+func (c *anomalyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.count
+	ch <- c.latest
+}
+
+// Collect reports nothing when the store is unavailable, leaving that to
+// /health, rather than failing the whole scrape.
+func (c *anomalyCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	health, err := storage.CheckHealth(ctx, c.store)
+	if err != nil || !health.HasData() {
+		return
+	}
+	// Load twice the baseline window before the reported slots to warm it up
+	first := health.NewestSlot - min(health.NewestSlot, c.slots-1)
+	warmup := first - min(first, uint64(2*c.config.Window))
+	bribes, err := c.store.GetSlotRange(ctx, warmup, health.NewestSlot)
+	if err != nil {
+		log.Printf("Failed to load slots for anomaly detection: %v", err)
+		return
+	}
+	anomalies, err := analysis.NewStatistics(bribes).DetectBidAnomalies(c.config)
+	if err != nil {
+		log.Printf("Failed to detect bid anomalies: %v", err)
+		return
+	}
+
+	for _, kind := range []analysis.AnomalyKind{analysis.AnomalyHighBid, analysis.AnomalyLowStretch} {
+		var count int
+		var latest uint64
+		for _, a := range anomalies {
+			if a.Kind == kind && a.EndSlot >= first {
+				count++
+				latest = max(latest, a.EndSlot)
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(c.count, prometheus.GaugeValue, float64(count), string(kind))
+		if count > 0 {
+			ch <- prometheus.MustNewConstMetric(c.latest, prometheus.GaugeValue, float64(latest), string(kind))
+		}
+	}
+}
+
+func NewAPIServer(store storage.Store, labels *model.BuilderRegistry) *APIServer {
 	return &APIServer{
 		store:       store,
 		rateLimiter: rate.NewLimiter(rate.Limit(100), 200), // 100 RPS burst 200
 		metrics:     newMetrics(),
+		labels:      labels,
 	}
 }
 
 // CensorshipCostRequest represents the API request payload.
+//
+// The range is given either as slots or as a [start_time, end_time)
+// wall-clock window, which selects the mainnet slots starting inside it.
 type CensorshipCostRequest struct {
-	StartSlot          uint64  `json:"start_slot"`
-	EndSlot            uint64  `json:"end_slot"`
-	TopKBuilders       int     `json:"top_k_builders"`
-	SuccessProbability float64 `json:"success_probability"`
-	ETHPriceUSD        float64 `json:"eth_price_usd,omitempty"`
+	StartSlot          uint64     `json:"start_slot"`
+	EndSlot            uint64     `json:"end_slot"`
+	StartTime          *time.Time `json:"start_time,omitempty"`
+	EndTime            *time.Time `json:"end_time,omitempty"`
+	TopKBuilders       int        `json:"top_k_builders"`
+	SuccessProbability float64    `json:"success_probability"`
+	ETHPriceUSD        float64    `json:"eth_price_usd,omitempty"`
+	GapPolicy          string     `json:"gap_policy,omitempty"` // fail (default), zero or median
 }
 
 // CensorshipCostResponse represents the API response.
@@ -91,20 +184,193 @@ type CensorshipCostResponse struct {
 	BuilderConcentration float64       `json:"builder_concentration"`
 	EffectiveCostETH     string        `json:"effective_cost_eth"`
 	BreakevenTVLUSD      float64       `json:"breakeven_tvl_usd,omitempty"`
+	ETHPriceUSD          float64       `json:"eth_price_usd,omitempty"`
+	ETHPriceTime         *time.Time    `json:"eth_price_time,omitempty"` // Set when the price came from stored quotes
+	MissingSlots         uint64        `json:"missing_slots,omitempty"`  // Slots priced by gap_policy
+	GapPolicy            string        `json:"gap_policy,omitempty"`     // Set when missing_slots is
 	TopBuilders          []BuilderInfo `json:"top_builders"`
 }
 
+// AnalysisInfo summarizes a stored censorship cost analysis.
+type AnalysisInfo struct {
+	StartSlot            uint64    `json:"start_slot"`
+	EndSlot              uint64    `json:"end_slot"`
+	DurationSlots        uint64    `json:"duration_slots"`
+	TopKBuilders         int       `json:"top_k_builders"`
+	TotalCostWei         string    `json:"total_cost_wei"`
+	TotalCostETH         float64   `json:"total_cost_eth"`
+	BuilderConcentration float64   `json:"builder_concentration"`
+	EffectiveCostETH     float64   `json:"effective_cost_eth"`
+	ComputedAt           time.Time `json:"computed_at"`
+}
+
+// GapsResponse lists slot ranges with no stored data.
+type GapsResponse struct {
+	StartSlot    uint64            `json:"start_slot"`
+	EndSlot      uint64            `json:"end_slot"`
+	MissingSlots uint64            `json:"missing_slots"`
+	Gaps         []storage.SlotGap `json:"gaps"`
+}
+
+// BuilderDetail describes one builder pubkey from the builders table.
+type BuilderDetail struct {
+	Pubkey    string   `json:"pubkey"`
+	Entity    string   `json:"entity,omitempty"`
+	Labels    []string `json:"labels"`
+	FirstSeen uint64   `json:"first_seen_slot,omitempty"`
+	LastSeen  uint64   `json:"last_seen_slot,omitempty"`
+}
+
+// DiscrepancyInfo lists every relay's report for a slot on which relays disagree.
+type DiscrepancyInfo struct {
+	Slot    uint64            `json:"slot"`
+	Reports []RelayReportInfo `json:"reports"`
+}
+
+// RelayReportInfo is one relay's record for a slot.
+type RelayReportInfo struct {
+	RelayURL      string `json:"relay_url"`
+	ValueWei      string `json:"value_wei"`
+	BuilderPubkey string `json:"builder_pubkey"`
+	Canonical     bool   `json:"canonical"` // The stored record used by analyses
+}
+
+// RelaysResponse compares what each relay reported for a slot range.
+type RelaysResponse struct {
+	StartSlot       uint64      `json:"start_slot"`
+	EndSlot         uint64      `json:"end_slot"`
+	Slots           uint64      `json:"slots"`             // Reported by any relay
+	MultiRelaySlots uint64      `json:"multi_relay_slots"` // Reported by two relays or more
+	DisputedSlots   uint64      `json:"disputed_slots"`
+	Relays          []RelayInfo `json:"relays"`
+}
+
+// RelayInfo summarizes one relay's reports (see model.RelayStatistics).
+type RelayInfo struct {
+	RelayURL         string  `json:"relay_url"`
+	Slots            uint64  `json:"slots"`
+	Coverage         float64 `json:"coverage"`
+	MeanBidWei       string  `json:"mean_bid_wei"`
+	UniqueBuilders   int     `json:"unique_builders"`
+	TopBuilder       string  `json:"top_builder"`
+	TopBuilderShare  float64 `json:"top_builder_share"`
+	BuilderHHI       float64 `json:"builder_hhi"`
+	SharedSlots      uint64  `json:"shared_slots"`
+	Disagreements    uint64  `json:"disagreements"`
+	DisagreementRate float64 `json:"disagreement_rate"`
+	MeanDeviationWei string  `json:"mean_deviation_wei"`
+}
+
 type BuilderInfo struct {
 	Pubkey     string  `json:"pubkey"`
+	Entity     string  `json:"entity,omitempty"`
 	BlockCount uint64  `json:"block_count"`
 	Percentage float64 `json:"percentage"`
 }
 
+// ConcentrationResponse reports top-k builder concentration over a slot range.
+type ConcentrationResponse struct {
+	StartSlot            uint64        `json:"start_slot"`
+	EndSlot              uint64        `json:"end_slot"`
+	TopK                 int           `json:"top_k"`
+	TotalBlocks          uint64        `json:"total_blocks"`
+	Builders             int           `json:"builders"`
+	BuilderConcentration float64       `json:"builder_concentration"`
+	TopBuilders          []BuilderInfo `json:"top_builders"`
+}
+
+// ForecastResponse forecasts the cost of the τ slots following a range.
+type ForecastResponse struct {
+	StartSlot  uint64             `json:"start_slot"`
+	EndSlot    uint64             `json:"end_slot"`
+	Tau        uint64             `json:"tau"`
+	Method     string             `json:"method"`
+	CostETH    float64            `json:"cost_eth"`
+	LowerETH   float64            `json:"lower_eth"`
+	UpperETH   float64            `json:"upper_eth"`
+	Confidence float64            `json:"confidence"`
+	StdDevETH  float64            `json:"residual_stddev_eth"`
+	Params     map[string]float64 `json:"params"`
+}
+
+// AnomaliesResponse lists the bid anomalies in a slot range.
+type AnomaliesResponse struct {
+	StartSlot uint64         `json:"start_slot"`
+	EndSlot   uint64         `json:"end_slot"`
+	Anomalies []AnomalyEntry `json:"anomalies"`
+}
+
+// AnomalyEntry is one abnormally high bid or low stretch of slots.
+type AnomalyEntry struct {
+	Kind        string  `json:"kind"` // high_bid or low_stretch
+	StartSlot   uint64  `json:"start_slot"`
+	EndSlot     uint64  `json:"end_slot"`
+	ValueETH    float64 `json:"value_eth"`
+	BaselineETH float64 `json:"baseline_eth"`
+	Score       float64 `json:"score"`
+}
+
+// HistogramResponse bins the bids of a slot range. Bin i spans
+// [edges[i], edges[i+1]) ETH; the last bin includes its upper edge.
+type HistogramResponse struct {
+	StartSlot uint64    `json:"start_slot"`
+	EndSlot   uint64    `json:"end_slot"`
+	Scale     string    `json:"scale"` // linear or log
+	Edges     []float64 `json:"edges_eth"`
+	Counts    []int     `json:"counts"`
+	Underflow int       `json:"underflow"` // Bids below the first edge
+	Overflow  int       `json:"overflow"`  // Bids above the last edge
+	Total     int       `json:"total"`
+}
+
+// ComparisonResponse compares the bids, concentration and attack
+// economics of two slot ranges.
+type ComparisonResponse struct {
+	Before       SlotRangeEntry     `json:"before"`
+	After        SlotRangeEntry     `json:"after"`
+	Tau          uint64             `json:"tau"`
+	TopK         int                `json:"top_k"`
+	SuccessProb  float64            `json:"success_prob"`
+	Permutations int                `json:"permutations"`
+	Seed         int64              `json:"seed"`
+	Metrics      []ComparisonMetric `json:"metrics"`
+}
+
+// SlotRangeEntry is an inclusive slot range and the slots found in it.
+type SlotRangeEntry struct {
+	StartSlot uint64 `json:"start_slot"`
+	EndSlot   uint64 `json:"end_slot"`
+	Slots     int    `json:"slots"`
+}
+
+// ComparisonMetric is one metric of a ComparisonResponse.
+type ComparisonMetric struct {
+	Metric         string   `json:"metric"`
+	Before         float64  `json:"before"`
+	After          float64  `json:"after"`
+	Change         float64  `json:"change"`
+	RelativeChange *float64 `json:"relative_change"` // null when before is 0
+	Test           string   `json:"test,omitempty"`  // welch, mann-whitney or permutation
+	PValue         *float64 `json:"p_value"`         // null without a test
+}
+
 // HealthResponse represents health check response.
 type HealthResponse struct {
-	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
-	Version   string    `json:"version"`
+	Status    string          `json:"status"` // healthy, stale or unhealthy
+	Timestamp time.Time       `json:"timestamp"`
+	Version   string          `json:"version"`
+	Database  *DatabaseHealth `json:"database,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// DatabaseHealth reports connectivity, pool usage and data freshness.
+type DatabaseHealth struct {
+	PingMs               float64 `json:"ping_ms"`
+	OpenConnections      int     `json:"open_connections"`
+	IdleConnections      int     `json:"idle_connections"`
+	InUseConnections     int     `json:"in_use_connections"`
+	NewestSlot           uint64  `json:"newest_slot"`
+	NewestSlotAgeSeconds float64 `json:"newest_slot_age_seconds"`
 }
 
 func (s *APIServer) rateLimitMiddleware(next http.Handler) http.Handler {
@@ -132,14 +398,42 @@ func (s *APIServer) metricsMiddleware(next http.Handler) http.Handler {
 }
 
 // HandleHealth returns API health status.
+//
+// Responds 503 when the database is unreachable, or when the newest stored
+// slot is older than maxDataAge (status "stale").
 func (s *APIServer) HandleHealth(w http.ResponseWriter, r *http.Request) {
 	response := HealthResponse{
 		Status:    "healthy",
 		Timestamp: time.Now(),
 		Version:   "1.0.0",
 	}
+	status := http.StatusOK
+
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	health, err := storage.CheckHealth(ctx, s.store)
+	if err != nil {
+		response.Status = "unhealthy"
+		response.Error = err.Error()
+		status = http.StatusServiceUnavailable
+	} else {
+		response.Database = &DatabaseHealth{
+			PingMs:               float64(health.PingLatency.Microseconds()) / 1000,
+			OpenConnections:      health.OpenConns,
+			IdleConnections:      health.IdleConns,
+			InUseConnections:     health.InUseConns,
+			NewestSlot:           health.NewestSlot,
+			NewestSlotAgeSeconds: health.NewestSlotAge.Seconds(),
+		}
+		if s.maxDataAge > 0 && (!health.HasData() || health.NewestSlotAge > s.maxDataAge) {
+			response.Status = "stale"
+			status = http.StatusServiceUnavailable
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -151,6 +445,19 @@ func (s *APIServer) HandleComputeCensorshipCost(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	if req.StartTime != nil || req.EndTime != nil {
+		if req.StartTime == nil || req.EndTime == nil {
+			http.Error(w, "start_time and end_time must be given together", http.StatusBadRequest)
+			return
+		}
+		var err error
+		req.StartSlot, req.EndSlot, err = model.Mainnet.SlotRangeForTimes(*req.StartTime, *req.EndTime)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Validation
 	if req.EndSlot <= req.StartSlot {
 		http.Error(w, "end_slot must be greater than start_slot", http.StatusBadRequest)
@@ -167,119 +474,1106 @@ func (s *APIServer) HandleComputeCensorshipCost(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Fetch data from database
+	gapPolicy, err := model.ParseGapPolicy(req.GapPolicy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
-	bribes, err := s.store.GetSlotRange(ctx, req.StartSlot, req.EndSlot)
+	// Serve repeated queries from stored analyses: cost, α and top builders
+	// depend only on (start, end, top-k). Only complete ranges are stored,
+	// so a stored analysis holds under every gap policy until the range's
+	// bribes change.
+	record, err := s.store.GetAnalysis(ctx, req.StartSlot, req.EndSlot, req.TopKBuilders)
+	cached := err == nil
+	switch {
+	case cached && s.analysisCurrent(ctx, record):
+		s.metrics.analysisCache.WithLabelValues("hit").Inc()
+	case cached:
+		s.metrics.analysisCache.WithLabelValues("stale").Inc()
+		cached = false
+	default:
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Failed to look up stored analysis: %v", err)
+		}
+		s.metrics.analysisCache.WithLabelValues("miss").Inc()
+	}
+
+	var missing uint64
+	if !cached {
+		var ok bool
+		if record, missing, ok = s.computeAnalysis(ctx, w, req, gapPolicy); !ok {
+			return
+		}
+	}
+
+	// Without an explicit price, convert at the stored quote for the end
+	// of the range
+	var priceTime *time.Time
+	if req.ETHPriceUSD == 0 {
+		if quote, ok := s.priceAt(ctx, model.Mainnet.SlotTime(req.EndSlot)); ok {
+			req.ETHPriceUSD = quote.USD
+			priceTime = &quote.Timestamp
+		}
+	}
+
+	response := s.buildCostResponse(req, record)
+	response.ETHPriceTime = priceTime
+	if missing > 0 {
+		response.MissingSlots = missing
+		response.GapPolicy = string(gapPolicy)
+	}
+
+	if !cached && missing == 0 {
+		record.TotalCostUSD = response.TotalCostUSD
+		record.BreakevenTVLUSD = response.BreakevenTVLUSD
+		record.SuccessProbability = req.SuccessProbability
+		if err := s.store.SaveAnalysis(ctx, record); err != nil {
+			log.Printf("Failed to save analysis: %v", err)
+		}
+	}
+
+	s.metrics.requestsTotal.WithLabelValues("/api/v1/censorship-cost", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// maxPriceAge is how old a stored ETH/USD quote may be, relative to the
+// time it is used for, before it is ignored.
+const maxPriceAge = 24 * time.Hour
+
+// priceAt returns the stored ETH/USD quote in effect at t, if a recent
+// enough one exists.
+func (s *APIServer) priceAt(ctx context.Context, t time.Time) (model.PriceQuote, bool) {
+	quote, err := currency.MaxAge(s.store, maxPriceAge).PriceAt(ctx, t)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) && !errors.Is(err, currency.ErrStalePrice) {
+			log.Printf("Failed to look up ETH price: %v", err)
+		}
+		return model.PriceQuote{}, false
+	}
+	return quote, true
+}
+
+// analysisCurrent reports whether a stored analysis still matches the
+// range's bribes. Later ingestion or conflict rewrites (e.g. keeping the
+// larger of two relays' bids) change the range's slot count or total
+// value; re-aggregating them is far cheaper than recomputing α. A failed
+// check counts as stale.
+func (s *APIServer) analysisCurrent(ctx context.Context, record storage.AnalysisRecord) bool {
+	stats, err := s.store.GetSlotRangeStats(ctx, record.StartSlot, record.EndSlot)
 	if err != nil {
-		log.Printf("Failed to fetch bribes: %v", err)
+		log.Printf("Failed to check stored analysis: %v", err)
+		return false
+	}
+	return stats.Count == record.DurationSlots && stats.TotalWei != nil &&
+		stats.TotalWei.Cmp(record.TotalCostWei) == 0
+}
+
+// computeAnalysis computes cost and concentration for the request's range
+// and returns the number of missing slots priced by policy. Both are
+// aggregated by the database, so the range's slots are only loaded to
+// impute missing slots. On failure it writes the error response and
+// returns false.
+func (s *APIServer) computeAnalysis(ctx context.Context, w http.ResponseWriter, req CensorshipCostRequest, policy model.GapPolicy) (storage.AnalysisRecord, uint64, bool) {
+	rangeStats, err := s.store.GetSlotRangeStats(ctx, req.StartSlot, req.EndSlot)
+	if err != nil {
+		log.Printf("Failed to aggregate bribes: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+		return storage.AnalysisRecord{}, 0, false
 	}
 
-	if len(bribes) == 0 {
+	if rangeStats.Count == 0 {
 		http.Error(w, "No data found for specified slot range", http.StatusNotFound)
-		return
+		return storage.AnalysisRecord{}, 0, false
 	}
 
-	// Compute censorship cost
+	// A complete range's sum is the censorship cost C_c(τ); otherwise the
+	// gap policy decides
 	tau := req.EndSlot - req.StartSlot + 1
-	totalCost, err := model.CensorshipCost(bribes, tau)
-	if err != nil {
-		log.Printf("Failed to compute cost: %v", err)
-		http.Error(w, "Failed to compute censorship cost", http.StatusInternalServerError)
-		return
+	missing := tau - rangeStats.Count
+	totalCost := rangeStats.TotalWei
+	switch {
+	case missing == 0 || policy == model.GapTreatAsZero:
+	case policy == model.GapImputeMedian:
+		bribes, err := s.store.GetSlotRange(ctx, req.StartSlot, req.EndSlot)
+		if err == nil {
+			totalCost, err = model.CensorshipCostRangeBy(bribes, req.StartSlot, req.EndSlot, policy)
+		}
+		if err != nil {
+			log.Printf("Failed to impute missing slots: %v", err)
+			writeModelError(w, err, "Internal server error")
+			return storage.AnalysisRecord{}, 0, false
+		}
+	default:
+		// Refuse to price a range with missing slots rather than fail opaquely
+		gaps, err := s.store.FindGaps(ctx, req.StartSlot, req.EndSlot)
+		if err != nil {
+			log.Printf("Failed to find gaps: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return storage.AnalysisRecord{}, 0, false
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(GapsResponse{
+			StartSlot:    req.StartSlot,
+			EndSlot:      req.EndSlot,
+			MissingSlots: storage.MissingSlots(gaps),
+			Gaps:         gaps,
+		})
+		return storage.AnalysisRecord{}, 0, false
 	}
 
 	// Compute builder concentration
-	alpha, builderStats, err := model.ComputeBuilderConcentration(bribes, req.TopKBuilders)
+	concentration, err := s.store.GetBuilderConcentration(ctx, req.StartSlot, req.EndSlot, req.TopKBuilders)
 	if err != nil {
 		log.Printf("Failed to compute concentration: %v", err)
-		http.Error(w, "Failed to compute builder concentration", http.StatusInternalServerError)
-		return
+		writeModelError(w, err, "Failed to compute builder concentration")
+		return storage.AnalysisRecord{}, 0, false
 	}
+	alpha, builderStats := concentration.Alpha, concentration.TopBuilders
 
-	// Compute effective cost
-	effectiveCost := new(big.Float).Mul(
-		new(big.Float).SetInt(totalCost),
-		big.NewFloat(1.0-alpha),
-	)
+	effectiveCostETH, _ := currency.FloatWeiToETH(effectiveCost(totalCost, alpha)).Float64()
+	return storage.AnalysisRecord{
+		StartSlot:            req.StartSlot,
+		EndSlot:              req.EndSlot,
+		DurationSlots:        tau,
+		TotalBlocks:          concentration.TotalBlocks,
+		TotalCostWei:         totalCost,
+		TotalCostETH:         currency.WeiToETHFloat64(totalCost),
+		BuilderConcentration: alpha,
+		TopKBuilders:         req.TopKBuilders,
+		EffectiveCostETH:     effectiveCostETH,
+		TopBuilders:          builderStats,
+		ComputedAt:           time.Now(),
+	}, missing, true
+}
 
+// buildCostResponse renders an analysis for a request. Price-dependent
+// fields are derived from the request, so stored analyses can serve
+// requests with any ETH price or success probability.
+func (s *APIServer) buildCostResponse(req CensorshipCostRequest, record storage.AnalysisRecord) CensorshipCostResponse {
 	// Convert to ETH
-	weiPerEth := new(big.Float).SetInt(big.NewInt(1e18))
-	totalCostETH := new(big.Float).Quo(new(big.Float).SetInt(totalCost), weiPerEth)
-	effectiveCostETH := new(big.Float).Quo(effectiveCost, weiPerEth)
+	totalCostETH := currency.WeiToETH(record.TotalCostWei)
+	effectiveCostETH := currency.FloatWeiToETH(effectiveCost(record.TotalCostWei, record.BuilderConcentration))
 
-	// Build response
 	response := CensorshipCostResponse{
-		StartSlot:            req.StartSlot,
-		EndSlot:              req.EndSlot,
-		DurationSlots:        tau,
+		StartSlot:            record.StartSlot,
+		EndSlot:              record.EndSlot,
+		DurationSlots:        record.DurationSlots,
 		TotalCostETH:         totalCostETH.Text('f', 6),
-		BuilderConcentration: alpha,
+		BuilderConcentration: record.BuilderConcentration,
 		EffectiveCostETH:     effectiveCostETH.Text('f', 6),
 		TopBuilders:          make([]BuilderInfo, 0),
 	}
 
 	// Compute USD values if ETH price provided
 	if req.ETHPriceUSD > 0 {
-		totalCostETHFloat, _ := totalCostETH.Float64()
-		effectiveCostETHFloat, _ := effectiveCostETH.Float64()
+		effectiveCostUSD, _ := currency.ETHToUSD(effectiveCostETH, req.ETHPriceUSD).Float64()
 
-		response.TotalCostUSD = totalCostETHFloat * req.ETHPriceUSD
-		response.BreakevenTVLUSD = (effectiveCostETHFloat * req.ETHPriceUSD) / req.SuccessProbability
+		response.ETHPriceUSD = req.ETHPriceUSD
+		response.TotalCostUSD = currency.WeiToUSD(record.TotalCostWei, req.ETHPriceUSD)
+		response.BreakevenTVLUSD = effectiveCostUSD / req.SuccessProbability
 	}
 
-	// Add top builders
-	totalBlocks := uint64(len(bribes))
-	for i := 0; i < req.TopKBuilders && i < len(builderStats); i++ {
+	// Add top builders; labels are applied at response time so registry
+	// reloads also affect stored analyses
+	builderStats := append([]model.BuilderStats(nil), record.TopBuilders...)
+	s.labels.LabelStats(builderStats)
+	for _, b := range builderStats {
 		response.TopBuilders = append(response.TopBuilders, BuilderInfo{
-			Pubkey:     builderStats[i].BuilderPubkey,
-			BlockCount: builderStats[i].BlockCount,
-			Percentage: float64(builderStats[i].BlockCount) / float64(totalBlocks) * 100,
+			Pubkey:     b.BuilderPubkey,
+			Entity:     b.Entity,
+			BlockCount: b.BlockCount,
+			Percentage: float64(b.BlockCount) / float64(record.TotalBlocks) * 100,
 		})
 	}
 
-	s.metrics.requestsTotal.WithLabelValues("/api/v1/censorship-cost", "200").Inc()
+	return response
+}
+
+// errorStatus maps model and storage failures to an HTTP status: invalid
+// parameters are the client's fault, missing data is not found, and
+// anything else, including corrupt stored values, is a server error.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, model.ErrInvalidProbability), errors.Is(err, model.ErrInvalidTopK):
+		return http.StatusBadRequest
+	case errors.Is(err, model.ErrInsufficientData), errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeModelError writes err with its errorStatus. Server errors get the
+// generic message instead, so internal details stay in the log.
+func writeModelError(w http.ResponseWriter, err error, message string) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		http.Error(w, message, status)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// effectiveCost computes C_c^eff = (1 - α) · C_c in wei.
+func effectiveCost(totalCost *big.Int, alpha float64) *big.Float {
+	return new(big.Float).Mul(new(big.Float).SetInt(totalCost), big.NewFloat(1.0-alpha))
+}
+
+// HandleGetAnalyses lists stored censorship cost analyses, most recent first.
+//
+// Query parameters (all optional): start_slot, end_slot, top_k and limit.
+func (s *APIServer) HandleGetAnalyses(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := storage.AnalysisQuery{Limit: 100}
+
+	var err error
+	if v := params.Get("start_slot"); v != "" {
+		if query.StartSlot, err = strconv.ParseUint(v, 10, 64); err != nil {
+			http.Error(w, "start_slot must be a slot number", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := params.Get("end_slot"); v != "" {
+		if query.EndSlot, err = strconv.ParseUint(v, 10, 64); err != nil {
+			http.Error(w, "end_slot must be a slot number", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := params.Get("top_k"); v != "" {
+		if query.TopK, err = strconv.Atoi(v); err != nil || query.TopK < 1 {
+			http.Error(w, "top_k must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := params.Get("limit"); v != "" {
+		if query.Limit, err = strconv.Atoi(v); err != nil || query.Limit < 1 || query.Limit > 1000 {
+			http.Error(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	records, err := s.store.GetAnalyses(ctx, query)
+	if err != nil {
+		log.Printf("Failed to fetch analyses: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	analyses := make([]AnalysisInfo, 0, len(records))
+	for _, record := range records {
+		analyses = append(analyses, AnalysisInfo{
+			StartSlot:            record.StartSlot,
+			EndSlot:              record.EndSlot,
+			DurationSlots:        record.DurationSlots,
+			TopKBuilders:         record.TopKBuilders,
+			TotalCostWei:         record.TotalCostWei.String(),
+			TotalCostETH:         record.TotalCostETH,
+			BuilderConcentration: record.BuilderConcentration,
+			EffectiveCostETH:     record.EffectiveCostETH,
+			ComputedAt:           record.ComputedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(analyses)
+}
+
+// HandleGetGaps reports slot ranges with no stored data.
+//
+// Query parameters: start_slot and end_slot (inclusive).
+func (s *APIServer) HandleGetGaps(w http.ResponseWriter, r *http.Request) {
+	startSlot, err1 := strconv.ParseUint(r.URL.Query().Get("start_slot"), 10, 64)
+	endSlot, err2 := strconv.ParseUint(r.URL.Query().Get("end_slot"), 10, 64)
+	if err1 != nil || err2 != nil || endSlot < startSlot {
+		http.Error(w, "start_slot and end_slot are required and end_slot must not precede start_slot", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	gaps, err := s.store.FindGaps(ctx, startSlot, endSlot)
+	if err != nil {
+		log.Printf("Failed to find gaps: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if gaps == nil {
+		gaps = []storage.SlotGap{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(GapsResponse{
+		StartSlot:    startSlot,
+		EndSlot:      endSlot,
+		MissingSlots: storage.MissingSlots(gaps),
+		Gaps:         gaps,
+	})
+}
+
+// HandleGetDiscrepancies reports slots on which relays disagree about the
+// delivered value or builder.
+//
+// Query parameters: start_slot and end_slot (inclusive).
+func (s *APIServer) HandleGetDiscrepancies(w http.ResponseWriter, r *http.Request) {
+	startSlot, err1 := strconv.ParseUint(r.URL.Query().Get("start_slot"), 10, 64)
+	endSlot, err2 := strconv.ParseUint(r.URL.Query().Get("end_slot"), 10, 64)
+	if err1 != nil || err2 != nil || endSlot < startSlot {
+		http.Error(w, "start_slot and end_slot are required and end_slot must not precede start_slot", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	discrepancies, err := s.store.FindDiscrepancies(ctx, startSlot, endSlot)
+	if err != nil {
+		log.Printf("Failed to find discrepancies: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]DiscrepancyInfo, 0, len(discrepancies))
+	for _, d := range discrepancies {
+		info := DiscrepancyInfo{Slot: d.Slot, Reports: make([]RelayReportInfo, 0, len(d.Reports))}
+		for _, report := range d.Reports {
+			info.Reports = append(info.Reports, RelayReportInfo{
+				RelayURL:      report.RelayURL,
+				ValueWei:      report.ValueWei.String(),
+				BuilderPubkey: report.BuilderPubkey,
+				Canonical:     report.Canonical,
+			})
+		}
+		response = append(response, info)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// HandleGetRelays compares the relays' reports for a slot range: coverage,
+// mean winning bid, builder mix and disagreement with the other relays.
+//
+// Query parameters: start_slot and end_slot (inclusive, at most 30 days).
+func (s *APIServer) HandleGetRelays(w http.ResponseWriter, r *http.Request) {
+	startSlot, err1 := strconv.ParseUint(r.URL.Query().Get("start_slot"), 10, 64)
+	endSlot, err2 := strconv.ParseUint(r.URL.Query().Get("end_slot"), 10, 64)
+	if err1 != nil || err2 != nil || endSlot < startSlot {
+		http.Error(w, "start_slot and end_slot are required and end_slot must not precede start_slot", http.StatusBadRequest)
+		return
+	}
+	if endSlot-startSlot >= maxForecastSlots {
+		http.Error(w, fmt.Sprintf("slot range must not exceed %d slots", maxForecastSlots), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	reports, err := s.store.GetRelayReports(ctx, startSlot, endSlot)
+	if err != nil {
+		log.Printf("Failed to load relay reports: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	comparison, err := model.CompareRelays(reports)
+	if err != nil {
+		writeModelError(w, err, "Failed to compare relays")
+		return
+	}
+
+	response := RelaysResponse{
+		StartSlot:       startSlot,
+		EndSlot:         endSlot,
+		Slots:           comparison.Slots,
+		MultiRelaySlots: comparison.MultiRelaySlots,
+		DisputedSlots:   comparison.DisputedSlots,
+		Relays:          make([]RelayInfo, 0, len(comparison.Relays)),
+	}
+	for _, relay := range comparison.Relays {
+		response.Relays = append(response.Relays, RelayInfo{
+			RelayURL:         relay.RelayURL,
+			Slots:            relay.Slots,
+			Coverage:         relay.Coverage,
+			MeanBidWei:       relay.MeanBidWei.String(),
+			UniqueBuilders:   relay.UniqueBuilders,
+			TopBuilder:       relay.TopBuilder,
+			TopBuilderShare:  relay.TopBuilderShare,
+			BuilderHHI:       relay.BuilderHHI,
+			SharedSlots:      relay.SharedSlots,
+			Disagreements:    relay.Disagreements,
+			DisagreementRate: relay.DisagreementRate,
+			MeanDeviationWei: relay.MeanDeviationWei.String(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// maxBuilderPageSize caps the limit parameter of the builders endpoint.
+const maxBuilderPageSize = 1000
+
 // HandleGetBuilderStats returns builder statistics.
+//
+// Query parameters: sort (block_count, total_value, last_seen), limit and
+// offset, and optionally start_slot and end_slot (inclusive) to restrict
+// the stats to a slot range.
 func (s *APIServer) HandleGetBuilderStats(w http.ResponseWriter, r *http.Request) {
+	query, err := parseBuilderStatsQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
-	stats, err := s.store.GetBuilderStats(ctx)
+	stats, err := s.store.GetBuilderStats(ctx, query)
 	if err != nil {
 		log.Printf("Failed to fetch builder stats: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	s.labels.LabelStats(stats)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(stats)
 }
 
+// HandleGetConcentration returns α and the top builders of a slot range,
+// computed by the database without loading the range.
+//
+// Query parameters: start_slot and end_slot (inclusive), and top_k (default 5).
+func (s *APIServer) HandleGetConcentration(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	startSlot, err1 := strconv.ParseUint(params.Get("start_slot"), 10, 64)
+	endSlot, err2 := strconv.ParseUint(params.Get("end_slot"), 10, 64)
+	if err1 != nil || err2 != nil || endSlot < startSlot {
+		http.Error(w, "start_slot and end_slot are required and end_slot must not precede start_slot", http.StatusBadRequest)
+		return
+	}
+	topK := 5
+	if v := params.Get("top_k"); v != "" {
+		var err error
+		if topK, err = strconv.Atoi(v); err != nil || topK < 1 || topK > 100 {
+			http.Error(w, "top_k must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	concentration, err := s.store.GetBuilderConcentration(ctx, startSlot, endSlot, topK)
+	if err != nil {
+		log.Printf("Failed to compute concentration: %v", err)
+		writeModelError(w, err, "Internal server error")
+		return
+	}
+	if concentration.TotalBlocks == 0 {
+		http.Error(w, "No data found for specified slot range", http.StatusNotFound)
+		return
+	}
+
+	response := ConcentrationResponse{
+		StartSlot:            startSlot,
+		EndSlot:              endSlot,
+		TopK:                 topK,
+		TotalBlocks:          concentration.TotalBlocks,
+		Builders:             concentration.Builders,
+		BuilderConcentration: concentration.Alpha,
+		TopBuilders:          make([]BuilderInfo, 0, len(concentration.TopBuilders)),
+	}
+	s.labels.LabelStats(concentration.TopBuilders)
+	for _, b := range concentration.TopBuilders {
+		response.TopBuilders = append(response.TopBuilders, BuilderInfo{
+			Pubkey:     b.BuilderPubkey,
+			Entity:     b.Entity,
+			BlockCount: b.BlockCount,
+			Percentage: float64(b.BlockCount) / float64(concentration.TotalBlocks) * 100,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// maxForecastSlots caps the history a forecast is fitted to (30 days).
+const maxForecastSlots = 30 * analysis.DefaultSeasonalPeriod
+
+// HandleGetForecast forecasts the censorship cost of the tau slots after a
+// slot range from the range's bribes.
+//
+// Query parameters: start_slot and end_slot (inclusive, at most 30 days),
+// tau, method (holt-winters, the default, or arima), confidence (default
+// 0.9), and period (Holt-Winters, default 7200) or p and d (ARIMA, default
+// 2 and 1).
+func (s *APIServer) HandleGetForecast(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	startSlot, err1 := strconv.ParseUint(params.Get("start_slot"), 10, 64)
+	endSlot, err2 := strconv.ParseUint(params.Get("end_slot"), 10, 64)
+	if err1 != nil || err2 != nil || endSlot < startSlot {
+		http.Error(w, "start_slot and end_slot are required and end_slot must not precede start_slot", http.StatusBadRequest)
+		return
+	}
+	if endSlot-startSlot >= maxForecastSlots {
+		http.Error(w, fmt.Sprintf("slot range must not exceed %d slots", maxForecastSlots), http.StatusBadRequest)
+		return
+	}
+	tau, err := strconv.ParseUint(params.Get("tau"), 10, 64)
+	if err != nil || tau < 1 || tau > maxForecastSlots {
+		http.Error(w, fmt.Sprintf("tau must be between 1 and %d", maxForecastSlots), http.StatusBadRequest)
+		return
+	}
+	confidence := 0.9
+	if v := params.Get("confidence"); v != "" {
+		if confidence, err = strconv.ParseFloat(v, 64); err != nil || !(confidence > 0 && confidence < 1) {
+			http.Error(w, "confidence must be in (0, 1)", http.StatusBadRequest)
+			return
+		}
+	}
+	intParam := func(name string, def int) (int, bool) {
+		v := params.Get(name)
+		if v == "" {
+			return def, true
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, name+" must be a non-negative integer", http.StatusBadRequest)
+			return 0, false
+		}
+		return n, true
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	bribes, err := s.store.GetSlotRange(ctx, startSlot, endSlot)
+	if err != nil {
+		log.Printf("Failed to load slot range: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	stats := analysis.NewStatistics(bribes)
+
+	var forecast analysis.CostForecast
+	switch method := params.Get("method"); method {
+	case "", analysis.ForecastHoltWinters:
+		period, ok := intParam("period", analysis.DefaultSeasonalPeriod)
+		if !ok {
+			return
+		}
+		forecast, err = stats.ForecastHoltWinters(tau, period, confidence)
+	case analysis.ForecastARIMA:
+		p, ok := intParam("p", 2)
+		if !ok {
+			return
+		}
+		d, ok := intParam("d", 1)
+		if !ok {
+			return
+		}
+		forecast, err = stats.ForecastARIMA(tau, p, d, confidence)
+	default:
+		http.Error(w, fmt.Sprintf("unknown method '%s' (expected holt-winters or arima)", method), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		if errorStatus(err) == http.StatusInternalServerError {
+			// Other failures are bad parameters or a series that cannot be fitted
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		writeModelError(w, err, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ForecastResponse{
+		StartSlot:  startSlot,
+		EndSlot:    endSlot,
+		Tau:        tau,
+		Method:     forecast.Method,
+		CostETH:    forecast.CostETH,
+		LowerETH:   forecast.LowerETH,
+		UpperETH:   forecast.UpperETH,
+		Confidence: forecast.Confidence,
+		StdDevETH:  forecast.StdDevETH,
+		Params:     forecast.Params,
+	})
+}
+
+// HandleGetAnomalies detects abnormally high bids and abnormally low
+// stretches of slots in a slot range. The range's first window bids only
+// form the baseline.
+//
+// Query parameters: start_slot and end_slot (inclusive, at most 30 days),
+// and optionally window (at most the range's slots), high_score, low_score
+// and min_stretch (see analysis.DefaultAnomalyConfig).
+func (s *APIServer) HandleGetAnomalies(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	startSlot, err1 := strconv.ParseUint(params.Get("start_slot"), 10, 64)
+	endSlot, err2 := strconv.ParseUint(params.Get("end_slot"), 10, 64)
+	if err1 != nil || err2 != nil || endSlot < startSlot {
+		http.Error(w, "start_slot and end_slot are required and end_slot must not precede start_slot", http.StatusBadRequest)
+		return
+	}
+	if endSlot-startSlot >= maxForecastSlots {
+		http.Error(w, fmt.Sprintf("slot range must not exceed %d slots", maxForecastSlots), http.StatusBadRequest)
+		return
+	}
+
+	var config analysis.AnomalyConfig
+	var err error
+	if v := params.Get("window"); v != "" {
+		slots := int(endSlot - startSlot + 1)
+		if config.Window, err = strconv.Atoi(v); err != nil || config.Window < 2 || config.Window > slots {
+			http.Error(w, fmt.Sprintf("window must be an integer from 2 to the range's %d slots", slots), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := params.Get("min_stretch"); v != "" {
+		if config.MinStretch, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "min_stretch must be an integer", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := params.Get("high_score"); v != "" {
+		if config.HighScore, err = strconv.ParseFloat(v, 64); err != nil {
+			http.Error(w, "high_score must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := params.Get("low_score"); v != "" {
+		if config.LowScore, err = strconv.ParseFloat(v, 64); err != nil {
+			http.Error(w, "low_score must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	bribes, err := s.store.GetSlotRange(ctx, startSlot, endSlot)
+	if err != nil {
+		log.Printf("Failed to load slot range: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	anomalies, err := analysis.NewStatistics(bribes).DetectBidAnomalies(config)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := AnomaliesResponse{
+		StartSlot: startSlot,
+		EndSlot:   endSlot,
+		Anomalies: make([]AnomalyEntry, 0, len(anomalies)),
+	}
+	for _, a := range anomalies {
+		response.Anomalies = append(response.Anomalies, AnomalyEntry{
+			Kind:        string(a.Kind),
+			StartSlot:   a.StartSlot,
+			EndSlot:     a.EndSlot,
+			ValueETH:    a.ValueETH,
+			BaselineETH: a.BaselineETH,
+			Score:       a.Score,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// maxHistogramBins bounds the bins of a histogram request, whose edges and
+// counts are allocated up front.
+const maxHistogramBins = 1000
+
+// HandleGetHistogram bins the bids of a slot range.
+//
+// Query parameters: start_slot and end_slot (inclusive, at most 30 days),
+// and optionally bins (default 50, at most maxHistogramBins), scale (linear
+// or log) and min_eth and max_eth bounding the bins (default: the range of
+// the bids).
+func (s *APIServer) HandleGetHistogram(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	startSlot, err1 := strconv.ParseUint(params.Get("start_slot"), 10, 64)
+	endSlot, err2 := strconv.ParseUint(params.Get("end_slot"), 10, 64)
+	if err1 != nil || err2 != nil || endSlot < startSlot {
+		http.Error(w, "start_slot and end_slot are required and end_slot must not precede start_slot", http.StatusBadRequest)
+		return
+	}
+	if endSlot-startSlot >= maxForecastSlots {
+		http.Error(w, fmt.Sprintf("slot range must not exceed %d slots", maxForecastSlots), http.StatusBadRequest)
+		return
+	}
+
+	var config analysis.HistogramConfig
+	var err error
+	if v := params.Get("bins"); v != "" {
+		if config.Bins, err = strconv.Atoi(v); err != nil || config.Bins < 1 || config.Bins > maxHistogramBins {
+			http.Error(w, fmt.Sprintf("bins must be an integer from 1 to %d", maxHistogramBins), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := params.Get("scale"); v != "" {
+		if config.Scale, err = analysis.ParseBinScale(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	for _, bound := range []struct {
+		name string
+		dst  **float64
+	}{{"min_eth", &config.MinETH}, {"max_eth", &config.MaxETH}} {
+		v := params.Get(bound.name)
+		if v == "" {
+			continue
+		}
+		eth, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(eth) || math.IsInf(eth, 0) {
+			http.Error(w, bound.name+" must be a finite number", http.StatusBadRequest)
+			return
+		}
+		*bound.dst = &eth
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	bribes, err := s.store.GetSlotRange(ctx, startSlot, endSlot)
+	if err != nil {
+		log.Printf("Failed to load slot range: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	h, err := analysis.NewStatistics(bribes).ComputeHistogram(config)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(HistogramResponse{
+		StartSlot: startSlot,
+		EndSlot:   endSlot,
+		Scale:     string(h.Scale),
+		Edges:     h.Edges,
+		Counts:    h.Counts,
+		Underflow: h.Underflow,
+		Overflow:  h.Overflow,
+		Total:     h.Total,
+	})
+}
+
+// maxPermutationSlots bounds the work of a comparison request's
+// permutation tests: permutations times the slots pooled from both ranges.
+// Two full 30-day ranges leave room for about 230 permutations.
+const maxPermutationSlots = 100_000_000
+
+// HandleGetComparison compares two slot ranges, e.g. before and after a
+// protocol upgrade, with a significance test per metric (see
+// analysis.CompareRanges).
+//
+// Query parameters: before_start, before_end, after_start and after_end
+// (inclusive slots, at most 30 days each), tau and success_prob, and
+// optionally top_k (default 3), permutations and seed (default 0, so
+// repeated requests agree). Permutations times the pooled slots may not
+// exceed maxPermutationSlots; without permutations, the default 1000 is
+// lowered to fit.
+func (s *APIServer) HandleGetComparison(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	var ranges [2]SlotRangeEntry
+	for i, name := range []string{"before", "after"} {
+		start, err1 := strconv.ParseUint(params.Get(name+"_start"), 10, 64)
+		end, err2 := strconv.ParseUint(params.Get(name+"_end"), 10, 64)
+		if err1 != nil || err2 != nil || end < start {
+			http.Error(w, fmt.Sprintf("%s_start and %s_end are required and %s_end must not precede %s_start", name, name, name, name), http.StatusBadRequest)
+			return
+		}
+		if end-start >= maxForecastSlots {
+			http.Error(w, fmt.Sprintf("slot ranges must not exceed %d slots", maxForecastSlots), http.StatusBadRequest)
+			return
+		}
+		ranges[i] = SlotRangeEntry{StartSlot: start, EndSlot: end}
+	}
+
+	config := analysis.ComparisonConfig{TopK: 3}
+	var err error
+	if config.Tau, err = strconv.ParseUint(params.Get("tau"), 10, 64); err != nil || config.Tau < 1 || config.Tau > maxForecastSlots {
+		http.Error(w, fmt.Sprintf("tau must be between 1 and %d", maxForecastSlots), http.StatusBadRequest)
+		return
+	}
+	if config.SuccessProb, err = strconv.ParseFloat(params.Get("success_prob"), 64); err != nil || config.SuccessProb <= 0 || config.SuccessProb > 1 {
+		http.Error(w, "success_prob must be between 0 and 1", http.StatusBadRequest)
+		return
+	}
+	if v := params.Get("top_k"); v != "" {
+		if config.TopK, err = strconv.Atoi(v); err != nil || config.TopK < 1 || config.TopK > 100 {
+			http.Error(w, "top_k must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := params.Get("permutations"); v != "" {
+		if config.Permutations, err = strconv.Atoi(v); err != nil || config.Permutations < 1 || config.Permutations > maxPermutationSlots {
+			http.Error(w, "permutations must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := params.Get("seed"); v != "" {
+		if config.Seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			http.Error(w, "seed must be an integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	var bribes [2][]model.SlotBribe
+	for i, rng := range ranges {
+		if bribes[i], err = s.store.GetSlotRange(ctx, rng.StartSlot, rng.EndSlot); err != nil {
+			log.Printf("Failed to load slot range: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		ranges[i].Slots = len(bribes[i])
+	}
+	pooled := max(len(bribes[0])+len(bribes[1]), 1)
+	if config.Permutations == 0 {
+		config.Permutations = max(min(analysis.DefaultPermutations, maxPermutationSlots/pooled), 1)
+	}
+	if config.Permutations*pooled > maxPermutationSlots {
+		http.Error(w, fmt.Sprintf("permutations must not exceed %d for %d pooled slots", maxPermutationSlots/pooled, pooled), http.StatusBadRequest)
+		return
+	}
+	comparison, err := analysis.CompareRanges(ctx, bribes[0], bribes[1], config)
+	if err != nil {
+		if ctx.Err() != nil {
+			http.Error(w, "comparison timed out", http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := ComparisonResponse{
+		Before:       ranges[0],
+		After:        ranges[1],
+		Tau:          comparison.Config.Tau,
+		TopK:         comparison.Config.TopK,
+		SuccessProb:  comparison.Config.SuccessProb,
+		Permutations: comparison.Config.Permutations,
+		Seed:         comparison.Config.Seed,
+		Metrics:      make([]ComparisonMetric, 0, len(comparison.Metrics)),
+	}
+	for _, m := range comparison.Metrics {
+		response.Metrics = append(response.Metrics, ComparisonMetric{
+			Metric:         m.Metric,
+			Before:         m.Before,
+			After:          m.After,
+			Change:         m.Change,
+			RelativeChange: finite(m.RelativeChange),
+			Test:           string(m.Test),
+			PValue:         finite(m.PValue),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// finite returns &v, or nil for NaN and infinities, which JSON cannot
+// encode.
+func finite(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+// HandleGetBuilder returns the entity, labels and activity span of one builder.
+func (s *APIServer) HandleGetBuilder(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	builder, err := s.store.GetBuilder(ctx, mux.Vars(r)["pubkey"])
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, "Unknown builder", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to fetch builder: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if entity, ok := s.labels.Label(builder.Pubkey); ok {
+		builder.Entity = entity
+	}
+	if builder.Labels == nil {
+		builder.Labels = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(BuilderDetail{
+		Pubkey:    builder.Pubkey,
+		Entity:    builder.Entity,
+		Labels:    builder.Labels,
+		FirstSeen: builder.FirstSeen,
+		LastSeen:  builder.LastSeen,
+	})
+}
+
+// parseBuilderStatsQuery reads pagination and sort parameters.
+func parseBuilderStatsQuery(r *http.Request) (storage.BuilderStatsQuery, error) {
+	params := r.URL.Query()
+
+	sortBy, err := storage.ParseBuilderSort(params.Get("sort"))
+	if err != nil {
+		return storage.BuilderStatsQuery{}, err
+	}
+	query := storage.BuilderStatsQuery{SortBy: sortBy}
+
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxBuilderPageSize {
+			return query, fmt.Errorf("limit must be between 1 and %d", maxBuilderPageSize)
+		}
+		query.Limit = limit
+	}
+	if v := params.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return query, fmt.Errorf("offset must be a non-negative integer")
+		}
+		query.Offset = offset
+	}
+	if params.Get("start_slot") != "" || params.Get("end_slot") != "" {
+		startSlot, err1 := strconv.ParseUint(params.Get("start_slot"), 10, 64)
+		endSlot, err2 := strconv.ParseUint(params.Get("end_slot"), 10, 64)
+		if err1 != nil || err2 != nil || endSlot < startSlot || endSlot == 0 {
+			return query, fmt.Errorf("start_slot and end_slot must be given together and end_slot must not precede start_slot")
+		}
+		query.StartSlot, query.EndSlot = startSlot, endSlot
+	}
+
+	return query, nil
+}
+
 func main() {
 	// Database configuration from environment
 	dbConfig := storage.Config{
+		Driver:   getEnv("DB_DRIVER", storage.DriverPostgres),
+		Path:     getEnv("DB_PATH", "data/censorship.db"),
 		Host:     getEnv("DB_HOST", "localhost"),
 		Port:     getEnvInt("DB_PORT", 5432),
 		User:     getEnv("DB_USER", "postgres"),
 		Password: getEnv("DB_PASSWORD", "postgres"),
 		Database: getEnv("DB_NAME", "censorship_db"),
 		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+		OnConflict: storage.ConflictPolicy(getEnv("DB_ON_CONFLICT", string(storage.ConflictKeepFirst))),
+
+		MaxOpenConns:     getEnvInt("DB_MAX_OPEN_CONNS", 0),
+		MaxIdleConns:     getEnvInt("DB_MAX_IDLE_CONNS", 0),
+		ConnMaxLifetime:  getEnvDuration("DB_CONN_MAX_LIFETIME", 0),
+		StatementTimeout: getEnvDuration("DB_STATEMENT_TIMEOUT", 0),
+
+		ReplicaDSN: getEnv("DB_REPLICA_DSN", ""),
 	}
 
-	store, err := storage.NewPostgresStore(dbConfig)
+	store, err := storage.Open(dbConfig)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
+
+	// Fresh deployments can opt in to migrating on startup; either way,
+	// refuse to serve from a schema the queries would fail against
+	schemaCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	if getEnvBool("DB_AUTO_MIGRATE", false) {
+		log.Printf("Applying pending %s schema migrations", dbConfig.Driver)
+		if err := store.InitSchema(schemaCtx); err != nil {
+			log.Fatalf("Failed to initialize database schema: %v", err)
+		}
+	}
+	if err := storage.VerifySchema(schemaCtx, store); err != nil {
+		var schemaErr *storage.SchemaError
+		if errors.As(err, &schemaErr) {
+			log.Fatalf("Database is not ready: %v (run `migrate --up` or set DB_AUTO_MIGRATE=true)", err)
+		}
+		log.Fatalf("Failed to verify database schema: %v", err)
+	}
+	cancel()
+
+	// Time every database call; the cache wraps this so hits are not counted
+	backend := dbConfig.Driver
+	if backend == "" {
+		backend = storage.DriverPostgres
+	}
+	store, err = storage.NewInstrumentedStore(store, storage.InstrumentConfig{
+		Backend:       backend,
+		SlowThreshold: getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 0),
+	})
+	if err != nil {
+		log.Fatalf("Failed to instrument database: %v", err)
+	}
+
+	// Optional cache-aside layer for hot queries
+	switch backend := getEnv("CACHE_BACKEND", ""); backend {
+	case "":
+	case "memory":
+		store = storage.NewCachedStore(store, storage.NewMemoryCache(getEnvInt("CACHE_MAX_ENTRIES", 0)), storage.DefaultCacheTTLs())
+		log.Printf("Caching queries in process")
+	case "redis":
+		cache, err := storage.NewRedisCache(storage.RedisConfig{
+			Addr:     getEnv("REDIS_HOST", "localhost:6379"),
+			Password: getEnv("REDIS_PASSWORD", ""),
+		})
+		if err != nil {
+			log.Fatalf("Failed to connect to cache: %v", err)
+		}
+		store = storage.NewCachedStore(store, cache, storage.DefaultCacheTTLs())
+		log.Printf("Caching queries in Redis")
+	default:
+		log.Fatalf("Unknown CACHE_BACKEND '%s' (want memory or redis)", backend)
+	}
 	defer store.Close()
 
-	server := NewAPIServer(store)
+	// Optional builder labels (file path or URL)
+	var labels *model.BuilderRegistry
+	if source := getEnv("BUILDER_LABELS", ""); source != "" {
+		labels, err = relay.LoadBuilderRegistry(context.Background(), source)
+		if err != nil {
+			log.Fatalf("Failed to load builder labels: %v", err)
+		}
+		log.Printf("Loaded %d builder labels from %s", labels.Len(), source)
+
+		// Persist labels so database consumers see entity names too
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := store.SaveBuilderLabels(ctx, storage.BuilderLabelsFromRegistry(labels)); err != nil {
+			log.Printf("Warning: failed to save builder labels: %v", err)
+		}
+		cancel()
+	}
+
+	server := NewAPIServer(store, labels)
+	server.maxDataAge = getEnvDuration("HEALTH_MAX_DATA_AGE", 0)
+
+	// Bid anomalies in the last hour by default, for alerting rules
+	if slots := getEnvInt("ANOMALY_ALERT_SLOTS", 300); slots > 0 {
+		prometheus.MustRegister(newAnomalyCollector(store, uint64(slots)))
+	}
 
 	// Setup router
 	r := mux.NewRouter()
@@ -290,6 +1584,16 @@ func main() {
 	r.HandleFunc("/health", server.HandleHealth).Methods("GET")
 	r.HandleFunc("/api/v1/censorship-cost", server.HandleComputeCensorshipCost).Methods("POST")
 	r.HandleFunc("/api/v1/builders", server.HandleGetBuilderStats).Methods("GET")
+	r.HandleFunc("/api/v1/builders/{pubkey}", server.HandleGetBuilder).Methods("GET")
+	r.HandleFunc("/api/v1/concentration", server.HandleGetConcentration).Methods("GET")
+	r.HandleFunc("/api/v1/forecast", server.HandleGetForecast).Methods("GET")
+	r.HandleFunc("/api/v1/anomalies", server.HandleGetAnomalies).Methods("GET")
+	r.HandleFunc("/api/v1/histogram", server.HandleGetHistogram).Methods("GET")
+	r.HandleFunc("/api/v1/compare", server.HandleGetComparison).Methods("GET")
+	r.HandleFunc("/api/v1/gaps", server.HandleGetGaps).Methods("GET")
+	r.HandleFunc("/api/v1/discrepancies", server.HandleGetDiscrepancies).Methods("GET")
+	r.HandleFunc("/api/v1/relays", server.HandleGetRelays).Methods("GET")
+	r.HandleFunc("/api/v1/analyses", server.HandleGetAnalyses).Methods("GET")
 
 	// Prometheus metrics endpoint
 	r.Handle("/metrics", promhttp.Handler())
@@ -335,6 +1639,24 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
@@ -5,6 +5,7 @@ import (
 	"log"
 	"math/big"
 
+	"insolventbydesign/internal/currency"
 	"insolventbydesign/internal/model"
 )
 
@@ -21,9 +22,5 @@ func main() {
 		log.Fatalf("CensorshipCost failed: %v", err)
 	}
 
-	// Convert to ETH for display
-	weiPerEth := new(big.Float).SetInt(big.NewInt(1e18))
-	costEth := new(big.Float).Quo(new(big.Float).SetInt(cost), weiPerEth)
-
-	fmt.Printf("Censorship cost for tau=%d slots: %s ETH (exact wei: %s)\n", tau, costEth.Text('f', 2), cost.String())
+	fmt.Printf("Censorship cost for tau=%d slots: %s ETH (exact wei: %s)\n", tau, currency.FormatETH(cost, 2), cost.String())
 }
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	dataset "insolventbydesign/internal/io"
+	"insolventbydesign/internal/model"
+	"insolventbydesign/internal/storage"
+)
+
+// export streams a slot range from the database to a CSV or Parquet
+// dataset, so results can be reproduced without database access.
+//
+// Connection settings come from the same environment variables as the
+// API server (DB_DRIVER, DB_PATH, DB_HOST, ...).
+func main() {
+	var (
+		start   = flag.Uint64("start", 0, "First slot to export")
+		end     = flag.Uint64("end", 0, "Last slot to export (inclusive)")
+		format  = flag.String("format", "", "Output format: csv or parquet (default: from --out extension, else csv)")
+		out     = flag.String("out", "-", "Output file, or - for stdout")
+		timeout = flag.Duration("timeout", time.Hour, "Maximum time to spend exporting")
+	)
+	flag.Parse()
+
+	if *end == 0 || *end < *start {
+		fmt.Fprintln(os.Stderr, "Specify a slot range with --start and --end")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = dataset.FormatCSV
+		if strings.EqualFold(filepath.Ext(*out), ".parquet") {
+			*format = dataset.FormatParquet
+		}
+	}
+
+	dbConfig := storage.Config{
+		Driver:   getEnv("DB_DRIVER", storage.DriverPostgres),
+		Path:     getEnv("DB_PATH", "data/censorship.db"),
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnvInt("DB_PORT", 5432),
+		User:     getEnv("DB_USER", "postgres"),
+		Password: getEnv("DB_PASSWORD", "postgres"),
+		Database: getEnv("DB_NAME", "censorship_db"),
+		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+	}
+
+	store, err := storage.Open(dbConfig)
+	if err != nil {
+		log.Fatalf("Failed to open %s store: %v", dbConfig.Driver, err)
+	}
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	rows, err := export(ctx, store, *start, *end, *format, *out)
+	if err != nil {
+		log.Fatalf("Export failed: %v", err)
+	}
+	log.Printf("Exported %d slots (%d-%d) as %s to %s", rows, *start, *end, *format, *out)
+}
+
+// export writes the slot range to path and returns the number of rows.
+// A partially written file is removed on failure.
+func export(ctx context.Context, store storage.Store, start, end uint64, format, path string) (int, error) {
+	file := os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		file = f
+	}
+
+	rows, err := writeDataset(ctx, store, start, end, format, file)
+	if path != "-" {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close %s: %w", path, closeErr)
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}
+	return rows, err
+}
+
+func writeDataset(ctx context.Context, store storage.Store, start, end uint64, format string, file *os.File) (int, error) {
+	buffered := bufio.NewWriterSize(file, 1<<20)
+	w, err := dataset.NewDatasetWriter(buffered, format)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	err = store.ForEachSlot(ctx, start, end, func(bribe model.SlotBribe) error {
+		rows++
+		return w.Write(bribe)
+	})
+	if err != nil {
+		return rows, fmt.Errorf("failed to read slots: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return rows, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return rows, fmt.Errorf("failed to write output: %w", err)
+	}
+	return rows, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
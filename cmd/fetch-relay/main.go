@@ -1,27 +1,245 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"time"
 
+	"insolventbydesign/internal/model"
 	"insolventbydesign/internal/relay"
+	"insolventbydesign/internal/storage"
 )
 
 func main() {
-	outDir := "data/relay_raw"
-	if err := os.MkdirAll(outDir, 0755); err != nil {
+	var (
+		logLevel     = flag.String("log-level", "info", "Log level: debug, info, warn, error")
+		logJSON      = flag.Bool("log-json", false, "Emit logs as JSON")
+		network      = flag.String("network", "mainnet", "Network for slot/time conversion: mainnet, sepolia, holesky")
+		from         = flag.String("from", "", "Start of date range, inclusive (YYYY-MM-DD or RFC3339)")
+		to           = flag.String("to", "", "End of date range, exclusive (YYYY-MM-DD or RFC3339)")
+		startSlot    = flag.Uint64("start", 0, "First slot to fetch (alternative to --from)")
+		endSlot      = flag.Uint64("end", 0, "Last slot to fetch (alternative to --to)")
+		outDir       = flag.String("out", "data/relay_raw", "Output directory for relay data")
+		manifestPath = flag.String("manifest", "data/fetch_manifest.json", "Path of the run manifest")
+		pseudonymize = flag.Bool("pseudonymize", false, "Replace pubkeys with keyed anonymous IDs (key from PSEUDONYM_KEY)")
+		httpTimeout  = flag.Duration("http-timeout", 10*time.Second, "Per-request HTTP timeout")
+		http2        = flag.Bool("http2", true, "Attempt HTTP/2 connections to relays")
+		logRequests  = flag.Bool("log-requests", false, "Log every relay HTTP request at debug level")
+		sqlitePath   = flag.String("sqlite", "", "Also import fetched payloads into this SQLite database")
+		onConflict   = flag.String("on-conflict", "first", "Duplicate slot handling for --sqlite: first or max")
+		batchSize    = flag.Int("batch-size", storage.DefaultSQLiteBatchSize, "Rows per insert transaction for --sqlite")
+		fetchBids    = flag.Bool("bids", false, "Also import every received bid in the slot range into --sqlite")
+	)
+	flag.Parse()
+
+	level, err := relay.ParseLogLevel(*logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logger := relay.NewLogger(os.Stderr, relay.LogConfig{Level: level, JSON: *logJSON})
+	relay.SetLogger(logger)
+
+	var pseudonymizer *relay.Pseudonymizer
+	if *pseudonymize {
+		pseudonymizer, err = relay.NewPseudonymizer([]byte(os.Getenv("PSEUDONYM_KEY")))
+		if err != nil {
+			log.Fatalf("--pseudonymize requires PSEUDONYM_KEY: %v", err)
+		}
+	}
+
+	net, err := model.NetworkByName(*network)
+	if err != nil {
 		log.Fatal(err)
 	}
 
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		logger.Error("failed to create output directory", "dir", *outDir, "error", err)
+		os.Exit(1)
+	}
+
 	relays := []string{
 		"https://boost-relay.flashbots.net",
 		"https://relay.ultrasound.money",
 	}
 
+	manifest := &relay.RunManifest{
+		StartedAt:     time.Now().UTC(),
+		Network:       net.Name,
+		Relays:        relays,
+		Files:         make([]string, 0),
+		Pseudonymized: pseudonymizer != nil,
+	}
+
+	// Resolve the requested range, if any
+	switch {
+	case *from != "" || *to != "":
+		if *from == "" || *to == "" {
+			log.Fatal("--from and --to must be given together")
+		}
+		fromTime, err := parseDate(*from)
+		if err != nil {
+			log.Fatal(err)
+		}
+		toTime, err := parseDate(*to)
+		if err != nil {
+			log.Fatal(err)
+		}
+		start, end, err := net.SlotRangeForTimes(fromTime, toTime)
+		if err != nil {
+			log.Fatal(err)
+		}
+		manifest.From = &fromTime
+		manifest.To = &toTime
+		manifest.SlotRange = &relay.SlotRange{Start: start, End: end}
+
+	case *startSlot != 0 || *endSlot != 0:
+		if *endSlot < *startSlot {
+			log.Fatalf("--end (%d) must be >= --start (%d)", *endSlot, *startSlot)
+		}
+		manifest.SlotRange = &relay.SlotRange{Start: *startSlot, End: *endSlot}
+	}
+
+	if manifest.SlotRange != nil {
+		logger.Info("resolved slot range",
+			"network", net.Name,
+			"slot_start", manifest.SlotRange.Start,
+			"slot_end", manifest.SlotRange.End,
+			"time_start", net.SlotTime(manifest.SlotRange.Start),
+			"time_end", net.SlotTime(manifest.SlotRange.End))
+	}
+
+	if *fetchBids && (*sqlitePath == "" || manifest.SlotRange == nil) {
+		log.Fatal("--bids requires --sqlite and a slot range (--from/--to or --start/--end)")
+	}
+
+	var store storage.Store
+	if *sqlitePath != "" {
+		policy, err := storage.ParseConflictPolicy(*onConflict)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sqliteStore, err := storage.NewSQLiteStore(*sqlitePath)
+		if err != nil {
+			log.Fatalf("Failed to open SQLite database: %v", err)
+		}
+		defer sqliteStore.Close()
+		if err := sqliteStore.SetConflictPolicy(policy); err != nil {
+			log.Fatal(err)
+		}
+		sqliteStore.SetBatchSize(*batchSize)
+		store = sqliteStore
+	}
+
+	clientConfig := relay.DefaultClientConfig()
+	clientConfig.Timeout = *httpTimeout
+	clientConfig.HTTP2 = *http2
+	clientConfig.LogRequests = *logRequests
+	baseClient := relay.NewClientWithConfig("", clientConfig)
+
+	ctx := context.Background()
 	for _, url := range relays {
-		log.Printf("Fetching from %s\n", url)
-		if err := relay.FetchAndStore(url, outDir); err != nil {
-			log.Println("error:", err)
+		client := baseClient.WithBaseURL(url)
+		logger.Info("fetching relay data", "relay", url)
+
+		var file string
+		var err error
+		if manifest.SlotRange != nil {
+			file, err = client.FetchRangeAndStore(ctx, *outDir, *manifest.SlotRange)
+		} else {
+			file, err = client.FetchAndStore(ctx, *outDir)
 		}
+		if err != nil {
+			logger.Error("relay fetch failed", "relay", url, "error", err)
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("%s: %v", url, err))
+			continue
+		}
+		if pseudonymizer != nil {
+			if err := relay.PseudonymizeFile(file, file, pseudonymizer); err != nil {
+				logger.Error("pseudonymization failed, removing file", "file", file, "error", err)
+				os.Remove(file)
+				manifest.Errors = append(manifest.Errors, fmt.Sprintf("%s: %v", url, err))
+				continue
+			}
+		}
+		manifest.Files = append(manifest.Files, file)
+
+		if store != nil {
+			if err := importFile(ctx, store, file, url); err != nil {
+				logger.Error("import failed", "file", file, "error", err)
+				manifest.Errors = append(manifest.Errors, fmt.Sprintf("%s: %v", url, err))
+			}
+		}
+
+		if *fetchBids {
+			if err := importBids(ctx, store, client, *manifest.SlotRange, pseudonymizer); err != nil {
+				logger.Error("bid import failed", "relay", url, "error", err)
+				manifest.Errors = append(manifest.Errors, fmt.Sprintf("%s bids: %v", url, err))
+			}
+		}
+	}
+
+	manifest.FinishedAt = time.Now().UTC()
+	if err := manifest.Write(*manifestPath); err != nil {
+		logger.Error("failed to write manifest", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("run manifest written", "path", *manifestPath)
+}
+
+// importFile parses a stored relay file and inserts its bribes into store.
+// Chunks that fail are logged individually; the rest of the file is kept.
+func importFile(ctx context.Context, store storage.Store, file, relayURL string) error {
+	bribes, err := relay.ParseRelayFile(file)
+	if err != nil {
+		return err
+	}
+
+	report, err := store.BatchInsertBribes(ctx, bribes, relayURL)
+	for _, chunk := range report.FailedChunks() {
+		relay.Logger().Error("import chunk failed",
+			"file", file,
+			"rows", fmt.Sprintf("%d-%d", chunk.Start, chunk.End-1),
+			"error", chunk.Err)
+	}
+	relay.Logger().Info("imported relay payloads",
+		"file", file,
+		"bribes", len(bribes),
+		"inserted", report.Inserted(),
+		"skipped", report.Skipped(),
+		"failed", report.Failed())
+	return err
+}
+
+// importBids fetches every bid the relay received in slotRange and inserts
+// the bids into store one slot at a time.
+func importBids(ctx context.Context, store storage.Store, client *relay.Client, slotRange relay.SlotRange, pseudonymizer *relay.Pseudonymizer) error {
+	total := 0
+	err := client.FetchReceivedBidRange(ctx, slotRange, func(bids []model.ReceivedBid) error {
+		if pseudonymizer != nil {
+			bids = pseudonymizer.Bids(bids)
+		}
+		total += len(bids)
+		return store.BatchInsertBids(ctx, bids)
+	})
+	if err != nil {
+		return err
+	}
+	relay.Logger().Info("imported received bids", "relay", client.BaseURL, "bids", total)
+	return nil
+}
+
+// parseDate accepts either a calendar date (interpreted as UTC midnight)
+// or a full RFC3339 timestamp.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s' (expected YYYY-MM-DD or RFC3339)", s)
 	}
+	return t.UTC(), nil
 }
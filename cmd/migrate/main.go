@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"insolventbydesign/internal/storage"
+)
+
+// migrate applies or reverts versioned schema migrations.
+//
+// Connection settings come from the same environment variables as the
+// API server (DB_DRIVER, DB_PATH, DB_HOST, ...).
+func main() {
+	var (
+		status  = flag.Bool("status", false, "Print the applied and latest schema versions")
+		up      = flag.Bool("up", false, "Apply all pending migrations")
+		down    = flag.Bool("down", false, "Revert the most recent migration")
+		to      = flag.Int("to", -1, "Migrate up or down to this version")
+		timeout = flag.Duration("timeout", 5*time.Minute, "Maximum time to spend migrating")
+	)
+	flag.Parse()
+
+	dbConfig := storage.Config{
+		Driver:   getEnv("DB_DRIVER", storage.DriverPostgres),
+		Path:     getEnv("DB_PATH", "data/censorship.db"),
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnvInt("DB_PORT", 5432),
+		User:     getEnv("DB_USER", "postgres"),
+		Password: getEnv("DB_PASSWORD", "postgres"),
+		Database: getEnv("DB_NAME", "censorship_db"),
+		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+	}
+
+	store, err := storage.OpenForMigration(dbConfig)
+	if err != nil {
+		log.Fatalf("Failed to open %s store: %v", dbConfig.Driver, err)
+	}
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	current, latest, err := store.SchemaVersion(ctx)
+	if err != nil {
+		log.Fatalf("Failed to read schema version: %v", err)
+	}
+
+	target := current
+	switch {
+	case *to >= 0:
+		target = *to
+	case *up:
+		target = latest
+	case *down:
+		if current == 0 {
+			log.Fatal("No migrations to revert")
+		}
+		target = current - 1
+	case *status:
+	default:
+		fmt.Fprintln(os.Stderr, "Specify one of --status, --up, --down or --to N")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if target != current {
+		log.Printf("Migrating %s schema from version %d to %d", dbConfig.Driver, current, target)
+		if err := store.MigrateTo(ctx, target); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		current = target
+	}
+
+	fmt.Printf("Schema version: %d (latest: %d)\n", current, latest)
+	if current < latest {
+		fmt.Printf("%d pending migration(s)\n", latest-current)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/big"
+	"os"
 	"strings"
 
+	"insolventbydesign/internal/analysis"
+	"insolventbydesign/internal/currency"
 	"insolventbydesign/internal/model"
 	"insolventbydesign/internal/relay"
 )
@@ -44,6 +48,18 @@ func main() {
 	diversity := model.GetBuilderDiversity(bribes)
 	fmt.Printf("Builder diversity: %d unique builders\n", diversity)
 
+	// Operators run several pubkeys; with labels, also count each entity once
+	if source := os.Getenv("BUILDER_LABELS"); source != "" {
+		labels, err := relay.LoadBuilderRegistry(context.Background(), source)
+		if err != nil {
+			log.Fatalf("Failed to load builder labels: %v", err)
+		}
+		fmt.Printf("Entity diversity: %d unique entities\n", model.GetEntityDiversity(bribes, labels))
+		if alpha, _, err := model.ComputeConcentrationByEntity(bribes, 5, model.ConcentrationByBlocks, labels); err == nil {
+			fmt.Printf("Entity α(top5)=%.3f\n", alpha)
+		}
+	}
+
 	// Show top builders
 	topBuilders, err := model.GetTopBuilders(bribes, 5)
 	if err == nil && len(topBuilders) > 0 {
@@ -57,6 +73,24 @@ func main() {
 			fmt.Printf("  %d. %s: %d blocks (%.1f%%)\n", i+1, pubkey, builder.BlockCount, pct)
 		}
 	}
+
+	// Bribing proposers is an alternative to a builder cartel
+	if alpha, _, err := model.ComputeProposerConcentration(bribes, 5); err == nil {
+		fmt.Printf("\nProposer diversity: %d unique proposers, α(top5)=%.3f\n", model.ProposerDiversity(bribes), alpha)
+	}
+	if alpha, _, err := model.ComputeFeeRecipientConcentration(bribes, 5); err == nil {
+		fmt.Printf("Fee recipient α(top5)=%.3f (approximates proposer operators)\n", alpha)
+	}
+	if daily, err := model.ComputeMarketShareSeries(bribes, model.SlotsPerDay, nil); err == nil && len(daily.Periods) > 1 {
+		fmt.Printf("Daily builder market: HHI trend %+.4f/day, churn %.3f/day, %d entries, %d exits\n",
+			daily.HHITrend, daily.ChurnRate, daily.Entries, daily.Exits)
+	}
+	if alpha, relays, err := model.ComputeRelayConcentration(bribes, 0, 0, 1); err == nil {
+		fmt.Printf("Relay α(top1)=%.3f across %d relays\n", alpha, len(relays))
+		for _, r := range relays {
+			fmt.Printf("  %s: %d payloads (%.1f%%)\n", r.RelayURL, r.PayloadCount, r.Share*100)
+		}
+	}
 	fmt.Println()
 
 	// Define scenarios to evaluate
@@ -92,8 +126,14 @@ func main() {
 	fmt.Println("=======================================================")
 	fmt.Println()
 
+	// Bids follow the time of day, so each scenario cites its cheapest start
+	seasons, err := analysis.NewStatistics(bribes).ComputeSeasonality(model.Mainnet)
+	if err != nil {
+		log.Fatalf("Failed to compute seasonality: %v", err)
+	}
+
 	for _, scenario := range scenarios {
-		if err := analyzeScenario(bribes, scenario); err != nil {
+		if err := analyzeScenario(bribes, scenario, seasons); err != nil {
 			fmt.Printf("⚠ Scenario '%s' failed: %v\n\n", scenario.Name, err)
 			continue
 		}
@@ -106,7 +146,7 @@ func main() {
 	fmt.Println("These thresholds are computed under EXPLICIT ASSUMPTIONS:")
 	fmt.Println("  - Success probability p is ASSUMED, not derived")
 	fmt.Println("  - Bridge defense mechanisms are NOT modeled")
-	fmt.Println("  - Inclusion lists (EIP-7547) are NOT considered")
+	fmt.Println("  - Inclusion lists (EIP-7547) are a scenario, not observed")
 	fmt.Println("  - Social/legal consequences are NOT factored")
 	fmt.Println()
 	fmt.Println("This analysis demonstrates economic BOUNDS, not attack")
@@ -114,7 +154,7 @@ func main() {
 	fmt.Println()
 }
 
-func analyzeScenario(bribes []model.SlotBribe, scenario ThresholdScenario) error {
+func analyzeScenario(bribes []model.SlotBribe, scenario ThresholdScenario, seasons analysis.Seasonality) error {
 	fmt.Printf("Scenario: %s\n", scenario.Name)
 	fmt.Println(strings.Repeat("-", 55))
 
@@ -135,6 +175,12 @@ func analyzeScenario(bribes []model.SlotBribe, scenario ThresholdScenario) error
 		return fmt.Errorf("failed to compute effective cost: %w", err)
 	}
 
+	// Bribe only the slots the cartel does not win itself
+	rest, err := model.NonCartelCensorshipCost(bribes, scenario.Tau, scenario.TopK)
+	if err != nil {
+		return fmt.Errorf("failed to compute non-cartel cost: %w", err)
+	}
+
 	// Compute breakeven TVL threshold
 	breakeven, _, err := model.FindBreakevenTVL(bribes, scenario.SuccessProb, scenario.Tau, scenario.TopK)
 	if err != nil {
@@ -142,28 +188,45 @@ func analyzeScenario(bribes []model.SlotBribe, scenario ThresholdScenario) error
 	}
 
 	// Convert to ETH for readability
-	weiPerEth := new(big.Float).SetInt(big.NewInt(1e18))
-
-	ccEth := new(big.Float).Quo(new(big.Float).SetInt(cc), weiPerEth)
-	ccEffEth := new(big.Float).Quo(ccEff, weiPerEth)
-	breakevenEth := new(big.Float).Quo(breakeven, weiPerEth)
+	ccEth := currency.WeiToETH(cc)
+	ccEffEth := currency.FloatWeiToETH(ccEff)
+	breakevenEth := currency.FloatWeiToETH(breakeven)
 
 	// Convert to USD (assuming $3000/ETH for reference)
 	ethToUSD := 3000.0
-	ccEffUSD := new(big.Float).Mul(ccEffEth, big.NewFloat(ethToUSD))
-	breakevenUSD := new(big.Float).Mul(breakevenEth, big.NewFloat(ethToUSD))
+	ccEffUSD := currency.ETHToUSD(ccEffEth, ethToUSD)
+	breakevenUSD := currency.ETHToUSD(breakevenEth, ethToUSD)
 
 	fmt.Printf("  Censorship duration (τ):     %d slots\n", scenario.Tau)
 	fmt.Printf("  Cartel size (k):              %d builders\n", scenario.TopK)
 	fmt.Printf("  Builder concentration (α):    %.3f\n", alpha)
 	fmt.Printf("  Assumed success prob (p):     %.2f\n", scenario.SuccessProb)
 	fmt.Println()
-	fmt.Printf("  Raw censorship cost (C_c):    %s ETH\n", formatFloat(ccEth))
+	fmt.Printf("  Raw censorship cost (C_c):    %s ETH\n", currency.FormatCompact(ccEth))
 	fmt.Printf("  Effective cost (C_c^eff):     %s ETH (~$%s)\n",
-		formatFloat(ccEffEth), formatFloat(ccEffUSD))
+		currency.FormatCompact(ccEffEth), currency.FormatCompact(ccEffUSD))
+	fmt.Printf("  Non-cartel cost (C_c^rest):   %s ETH\n",
+		currency.FormatCompact(currency.WeiToETH(rest)))
+	if window, err := seasons.CheapestWindow(scenario.Tau); err == nil {
+		fmt.Printf("  Cheapest seasonal window:     %s %02d:00 UTC, %.2fx → C_c^eff ≈ %s ETH\n",
+			window.Weekday.String()[:3], window.Hour, window.Ratio(), currency.FormatCompact(new(big.Float).Mul(ccEffEth, big.NewFloat(window.Ratio()))))
+	}
 	fmt.Println()
-	fmt.Printf("  BREAKEVEN TVL (V*):           %s ETH\n", formatFloat(breakevenEth))
-	fmt.Printf("                                ~$%s\n", formatFloat(breakevenUSD))
+	fmt.Printf("  BREAKEVEN TVL (V*):           %s ETH\n", currency.FormatCompact(breakevenEth))
+	fmt.Printf("                                ~$%s\n", currency.FormatCompact(breakevenUSD))
+	fmt.Println()
+
+	// Inclusion lists remove the cartel's discount on enforced slots
+	fmt.Println("  Breakeven TVL with inclusion lists (f = enforced share of slots):")
+	for _, fraction := range []float64{0.25, 0.5, 1} {
+		scenarioIL := &model.InclusionListScenario{EnforcedFraction: fraction}
+		withIL, _, err := model.FindBreakevenTVLWithInclusionLists(bribes, scenario.SuccessProb, scenario.Tau, scenario.TopK, scenarioIL)
+		if err != nil {
+			continue
+		}
+		ratio, _ := new(big.Float).Quo(withIL, breakeven).Float64()
+		fmt.Printf("    f=%.2f → %s ETH (%.2fx)\n", fraction, currency.FormatCompact(currency.FloatWeiToETH(withIL)), ratio)
+	}
 	fmt.Println()
 
 	// Show profitability at different TVL levels
@@ -171,8 +234,7 @@ func analyzeScenario(bribes []model.SlotBribe, scenario ThresholdScenario) error
 	fmt.Println("  Profit at different TVL levels (USD):")
 
 	for _, tvlUSD := range testTVLs {
-		tvlETH := tvlUSD / ethToUSD
-		tvlWei := new(big.Float).Mul(big.NewFloat(tvlETH), weiPerEth)
+		tvlWei := currency.USDToWei(tvlUSD, ethToUSD)
 
 		params := model.ProfitParams{
 			BridgeTVL:          tvlWei,
@@ -186,8 +248,7 @@ func analyzeScenario(bribes []model.SlotBribe, scenario ThresholdScenario) error
 			continue
 		}
 
-		profitETH := new(big.Float).Quo(result.Profit, weiPerEth)
-		profitUSD := new(big.Float).Mul(profitETH, big.NewFloat(ethToUSD))
+		profitUSD := currency.ETHToUSD(currency.FloatWeiToETH(result.Profit), ethToUSD)
 
 		profitSign := " "
 		if result.Profit.Sign() > 0 {
@@ -196,26 +257,26 @@ func analyzeScenario(bribes []model.SlotBribe, scenario ThresholdScenario) error
 			profitSign = "✗"
 		}
 
-		fmt.Printf("    %s TVL=$%s → Profit=$%s\n",
-			profitSign, formatMillion(tvlUSD), formatFloat(profitUSD))
+		// Minimum p at which this bridge is worth attacking
+		pStar, _, err := model.FindBreakevenProbability(bribes, tvlWei, scenario.Tau, scenario.TopK)
+		if err != nil {
+			continue
+		}
+
+		// Counter-bribe commitment that deters every p ≤ 1
+		defense, err := model.FindDefenseBudget(bribes, params)
+		if err != nil {
+			continue
+		}
+
+		fmt.Printf("    %s TVL=$%s → Profit=$%s (p*=%.4f, defense=%.1f%% of TVL)\n",
+			profitSign, formatMillion(tvlUSD), currency.FormatCompact(profitUSD), pStar, defense.FractionOfTVL*100)
 	}
 
 	fmt.Println()
 	return nil
 }
 
-func formatFloat(f *big.Float) string {
-	val, _ := f.Float64()
-	if val >= 1e9 {
-		return fmt.Sprintf("%.2fB", val/1e9)
-	} else if val >= 1e6 {
-		return fmt.Sprintf("%.2fM", val/1e6)
-	} else if val >= 1e3 {
-		return fmt.Sprintf("%.2fK", val/1e3)
-	}
-	return fmt.Sprintf("%.2f", val)
-}
-
 func formatMillion(val float64) string {
 	if val >= 1e9 {
 		return fmt.Sprintf("%.1fB", val/1e9)
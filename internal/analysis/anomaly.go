@@ -0,0 +1,241 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"insolventbydesign/internal/currency"
+)
+
+// AnomalyKind classifies a BidAnomaly.
+type AnomalyKind string
+
+const (
+	// AnomalyHighBid is a single bid far above recent bids: a censorship
+	// bidding war, or a block with unusually rich MEV.
+	AnomalyHighBid AnomalyKind = "high_bid"
+	// AnomalyLowStretch is a run of slots whose bids are far below recent
+	// bids, or missing: more often a hole in the data than in the market.
+	AnomalyLowStretch AnomalyKind = "low_stretch"
+)
+
+// BidAnomaly is a bid or stretch of slots whose values are implausible given
+// the bids before it.
+type BidAnomaly struct {
+	Kind        AnomalyKind
+	StartSlot   uint64
+	EndSlot     uint64  // Equal to StartSlot for high bids
+	ValueETH    float64 // The bid, or the stretch's mean with missing slots as zero
+	BaselineETH float64 // Median of the trailing window before StartSlot
+	Score       float64 // Robust z-score; for stretches, the highest in the stretch
+}
+
+// AnomalyConfig tunes DetectBidAnomalies. Zero fields select the values of
+// DefaultAnomalyConfig.
+type AnomalyConfig struct {
+	Window     int     // Trailing bids forming each slot's baseline
+	HighScore  float64 // Robust z-score at or above which a bid is abnormally high
+	LowScore   float64 // Robust z-score at or below which a slot is abnormally low
+	MinStretch int     // Consecutive low slots that form a stretch
+}
+
+// DefaultAnomalyConfig returns an hour-long baseline, flags bids more than
+// five robust deviations above it, and flags epochs (32 slots) in which
+// every bid is more than three below it.
+func DefaultAnomalyConfig() AnomalyConfig {
+	return AnomalyConfig{Window: 300, HighScore: 5, LowScore: -3, MinStretch: 32}
+}
+
+// anomalyFloorETH is added to bids before taking logarithms, so zero and
+// missing bids score finitely: as 1 gwei.
+const anomalyFloorETH = 1e-9
+
+// minAnomalyScale bounds the robust deviation of log bids from below, so a
+// window of near-identical bids does not make every small change extreme.
+const minAnomalyScale = 0.01
+
+// DetectBidAnomalies flags abnormally high bids and abnormally low stretches
+// of slots, ordered by start slot.
+//
+// Each slot is scored against the trailing Window positive bids by a robust
+// z-score of log values, (ln v - median) / (1.4826·MAD): bids are heavy-
+// tailed, and the median and median absolute deviation ignore the outliers
+// being looked for. Slots missing between the first and last bribe, and
+// bribes without a value, score as zero bids. Zero bids stay out of the
+// baseline so a data hole does not become the norm, and slots before the
+// first Window positive bids are not scored.
+func (s *Statistics) DetectBidAnomalies(config AnomalyConfig) ([]BidAnomaly, error) {
+	defaults := DefaultAnomalyConfig()
+	if config.Window == 0 {
+		config.Window = defaults.Window
+	}
+	if config.HighScore == 0 {
+		config.HighScore = defaults.HighScore
+	}
+	if config.LowScore == 0 {
+		config.LowScore = defaults.LowScore
+	}
+	if config.MinStretch == 0 {
+		config.MinStretch = defaults.MinStretch
+	}
+	if config.Window < 2 || config.HighScore < 0 || config.LowScore > 0 || config.MinStretch < 1 {
+		return nil, fmt.Errorf("invalid anomaly config %+v: need window >= 2, high score >= 0, low score <= 0 and min stretch >= 1", config)
+	}
+
+	var anomalies []BidAnomaly
+	baseline := newRollingBaseline(config.Window)
+
+	// The open low stretch, if any
+	var stretch *BidAnomaly
+	var stretchLen uint64
+	closeStretch := func() {
+		if stretch != nil && stretchLen >= uint64(config.MinStretch) {
+			stretch.ValueETH /= float64(stretchLen)
+			anomalies = append(anomalies, *stretch)
+		}
+		stretch, stretchLen = nil, 0
+	}
+	extendStretch := func(slot uint64, slots uint64, value, score float64) {
+		if stretch == nil {
+			stretch = &BidAnomaly{
+				Kind:        AnomalyLowStretch,
+				StartSlot:   slot,
+				BaselineETH: baseline.medianETH(),
+				Score:       score,
+			}
+		}
+		stretch.EndSlot = slot + slots - 1
+		stretch.ValueETH += value
+		stretch.Score = math.Max(stretch.Score, score)
+		stretchLen += slots
+	}
+
+	for i, bribe := range s.bribes {
+		// Missing slots since the previous bribe score as zero bids
+		if i > 0 && bribe.Slot > s.bribes[i-1].Slot+1 {
+			if missing := bribe.Slot - s.bribes[i-1].Slot - 1; baseline.ready() {
+				if score := baseline.score(0); score <= config.LowScore {
+					extendStretch(s.bribes[i-1].Slot+1, missing, 0, score)
+				} else {
+					closeStretch()
+				}
+			}
+		}
+
+		var value float64
+		if bribe.ValueWei != nil {
+			value = currency.WeiToETHFloat64(bribe.ValueWei)
+		}
+		if baseline.ready() {
+			score := baseline.score(value)
+			if score <= config.LowScore {
+				extendStretch(bribe.Slot, 1, value, score)
+			} else {
+				closeStretch()
+			}
+			if score >= config.HighScore {
+				anomalies = append(anomalies, BidAnomaly{
+					Kind:        AnomalyHighBid,
+					StartSlot:   bribe.Slot,
+					EndSlot:     bribe.Slot,
+					ValueETH:    value,
+					BaselineETH: baseline.medianETH(),
+					Score:       score,
+				})
+			}
+		}
+		if value > 0 {
+			baseline.add(value)
+		}
+	}
+	closeStretch()
+
+	sort.SliceStable(anomalies, func(i, j int) bool { return anomalies[i].StartSlot < anomalies[j].StartSlot })
+	return anomalies, nil
+}
+
+// rollingBaseline holds the log values of the most recent bids, sorted, so
+// their median and MAD cost O(window) per slot.
+type rollingBaseline struct {
+	window int
+	ring   []float64 // Log values in arrival order, oldest at next once full
+	next   int
+	sorted []float64
+	median float64
+	scale  float64 // 1.4826·MAD, at least minAnomalyScale
+	stale  bool
+}
+
+// newRollingBaseline returns an empty baseline of window bids. The ring
+// grows as bids arrive rather than up front, so a window longer than the
+// data costs nothing.
+func newRollingBaseline(window int) *rollingBaseline {
+	return &rollingBaseline{window: window}
+}
+
+// add pushes a bid, evicting the oldest once the window is full.
+func (b *rollingBaseline) add(valueETH float64) {
+	x := math.Log(valueETH + anomalyFloorETH)
+	if len(b.ring) < b.window {
+		b.ring = append(b.ring, x)
+	} else {
+		old := b.ring[b.next]
+		b.ring[b.next] = x
+		b.next = (b.next + 1) % len(b.ring)
+		i := sort.SearchFloat64s(b.sorted, old)
+		b.sorted = append(b.sorted[:i], b.sorted[i+1:]...)
+	}
+	i := sort.SearchFloat64s(b.sorted, x)
+	b.sorted = append(b.sorted, 0)
+	copy(b.sorted[i+1:], b.sorted[i:])
+	b.sorted[i] = x
+	b.stale = true
+}
+
+// ready reports whether the window is full.
+func (b *rollingBaseline) ready() bool {
+	return len(b.ring) == b.window
+}
+
+// score returns the robust z-score of a bid against the window.
+func (b *rollingBaseline) score(valueETH float64) float64 {
+	b.refresh()
+	return (math.Log(valueETH+anomalyFloorETH) - b.median) / b.scale
+}
+
+// medianETH returns the window's median bid.
+func (b *rollingBaseline) medianETH() float64 {
+	b.refresh()
+	return math.Max(0, math.Exp(b.median)-anomalyFloorETH)
+}
+
+// refresh recomputes the median and MAD after the window changed. Absolute
+// deviations grow moving outward from the median in both directions, so
+// merging the two walks yields them in order.
+func (b *rollingBaseline) refresh() {
+	if !b.stale {
+		return
+	}
+	b.stale = false
+	b.median = percentile(b.sorted, 50)
+
+	n := len(b.sorted)
+	deviations := make([]float64, 0, n/2+1)
+	hi := sort.SearchFloat64s(b.sorted, b.median)
+	lo := hi - 1
+	for len(deviations) < n/2+1 {
+		if lo < 0 || (hi < n && b.sorted[hi]-b.median <= b.median-b.sorted[lo]) {
+			deviations = append(deviations, b.sorted[hi]-b.median)
+			hi++
+		} else {
+			deviations = append(deviations, b.median-b.sorted[lo])
+			lo--
+		}
+	}
+	mad := deviations[n/2]
+	if n%2 == 0 {
+		mad = (deviations[n/2-1] + deviations[n/2]) / 2
+	}
+	b.scale = math.Max(1.4826*mad, minAnomalyScale)
+}
@@ -0,0 +1,157 @@
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+// cyclingBids returns bribes at the given slots whose bids cycle through
+// 0.08 to 0.12 ETH, a spread wide enough to keep robust deviations above
+// minAnomalyScale.
+func cyclingBids(slots []uint64) []model.SlotBribe {
+	values := make([]float64, len(slots))
+	for i := range values {
+		values[i] = 0.08 + 0.01*float64(i%5)
+	}
+	bribes := bribesFromETH(values)
+	for i := range bribes {
+		bribes[i].Slot = slots[i]
+	}
+	return bribes
+}
+
+// slotRange returns the slots from first to last, skipping those in skip.
+func slotRange(first, last uint64, skip ...uint64) []uint64 {
+	var slots []uint64
+	for slot := first; slot <= last; slot++ {
+		if len(skip) == 2 && slot >= skip[0] && slot <= skip[1] {
+			continue
+		}
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+// TestDetectBidAnomalies verifies spikes and long low stretches are
+// flagged, and short stretches and bids before the window fills are not.
+func TestDetectBidAnomalies(t *testing.T) {
+	config := AnomalyConfig{Window: 10, MinStretch: 5}
+	defaults := DefaultAnomalyConfig()
+
+	t.Run("spike", func(t *testing.T) {
+		bribes := cyclingBids(slotRange(1, 40))
+		setETH(bribes, 29, 5) // Slot 30
+		anomalies, err := NewStatistics(bribes).DetectBidAnomalies(config)
+		if err != nil {
+			t.Fatalf("DetectBidAnomalies failed: %v", err)
+		}
+		if len(anomalies) != 1 {
+			t.Fatalf("expected one anomaly, got %+v", anomalies)
+		}
+		a := anomalies[0]
+		if a.Kind != AnomalyHighBid || a.StartSlot != 30 || a.EndSlot != 30 || math.Abs(a.ValueETH-5) > 1e-9 {
+			t.Errorf("expected a 5 ETH high bid at slot 30, got %+v", a)
+		}
+		// The window before slot 30 holds each bid twice; its median is 0.1
+		if math.Abs(a.BaselineETH-0.1) > 1e-9 || a.Score < defaults.HighScore {
+			t.Errorf("expected baseline 0.1 and a score of at least %v, got %v and %v", defaults.HighScore, a.BaselineETH, a.Score)
+		}
+	})
+
+	t.Run("missing slots", func(t *testing.T) {
+		// Slots 31 to 40 are missing and slot 41 bids almost nothing
+		bribes := cyclingBids(slotRange(1, 60, 31, 40))
+		setETH(bribes, 30, 1e-6)
+		anomalies, err := NewStatistics(bribes).DetectBidAnomalies(config)
+		if err != nil {
+			t.Fatalf("DetectBidAnomalies failed: %v", err)
+		}
+		if len(anomalies) != 1 {
+			t.Fatalf("expected one anomaly, got %+v", anomalies)
+		}
+		a := anomalies[0]
+		if a.Kind != AnomalyLowStretch || a.StartSlot != 31 || a.EndSlot != 41 {
+			t.Errorf("expected a low stretch over slots 31 to 41, got %+v", a)
+		}
+		if math.Abs(a.ValueETH-1e-6/11) > 1e-15 || a.Score > defaults.LowScore {
+			t.Errorf("expected mean %v and a score of at most %v, got %v and %v", 1e-6/11, defaults.LowScore, a.ValueETH, a.Score)
+		}
+	})
+
+	t.Run("short stretch", func(t *testing.T) {
+		bribes := cyclingBids(slotRange(1, 40, 21, 24))
+		anomalies, err := NewStatistics(bribes).DetectBidAnomalies(config)
+		if err != nil {
+			t.Fatalf("DetectBidAnomalies failed: %v", err)
+		}
+		if len(anomalies) != 0 {
+			t.Errorf("expected a 4-slot stretch to be dropped, got %+v", anomalies)
+		}
+	})
+
+	t.Run("window filling", func(t *testing.T) {
+		// A spike and a hole among the first ten bids only form the baseline
+		bribes := cyclingBids(slotRange(1, 30, 3, 8))
+		setETH(bribes, 1, 5)
+		anomalies, err := NewStatistics(bribes).DetectBidAnomalies(config)
+		if err != nil {
+			t.Fatalf("DetectBidAnomalies failed: %v", err)
+		}
+		if len(anomalies) != 0 {
+			t.Errorf("expected nothing scored before the window fills, got %+v", anomalies)
+		}
+	})
+
+	for _, invalid := range []AnomalyConfig{{Window: 1}, {HighScore: -1}, {LowScore: 1}, {MinStretch: -1}} {
+		if _, err := NewStatistics(cyclingBids(slotRange(1, 10))).DetectBidAnomalies(invalid); err == nil {
+			t.Errorf("%+v: Expected error, got nil", invalid)
+		}
+	}
+}
+
+// setETH sets the bid of bribes[i] to v ETH.
+func setETH(bribes []model.SlotBribe, i int, v float64) {
+	bribes[i].ValueWei = bribesFromETH([]float64{v})[0].ValueWei
+}
+
+// TestRollingBaseline verifies the median and scale match a brute-force
+// recomputation over the last window bids as old ones are evicted.
+func TestRollingBaseline(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, window := range []int{7, 8} {
+		b := newRollingBaseline(window)
+		var logs []float64
+		for i := 0; i < 200; i++ {
+			// Repeated bids exercise evicting one of several equal values
+			v := math.Round(math.Exp(rng.NormFloat64())*20) / 100
+			b.add(v)
+			logs = append(logs, math.Log(v+anomalyFloorETH))
+			if len(logs) < window {
+				if b.ready() {
+					t.Fatalf("window %d: expected not ready after %d bids", window, len(logs))
+				}
+				continue
+			}
+
+			last := append([]float64(nil), logs[len(logs)-window:]...)
+			sort.Float64s(last)
+			median := percentile(last, 50)
+			deviations := make([]float64, window)
+			for j, x := range last {
+				deviations[j] = math.Abs(x - median)
+			}
+			sort.Float64s(deviations)
+			scale := math.Max(1.4826*percentile(deviations, 50), minAnomalyScale)
+
+			b.refresh()
+			if !b.ready() || math.Abs(b.median-median) > 1e-12 || math.Abs(b.scale-scale) > 1e-12 {
+				t.Fatalf("window %d after %d bids: expected median %v and scale %v, got %v and %v",
+					window, len(logs), median, scale, b.median, b.scale)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// BacktestWindow compares the forecasts made at one origin against the
+// censorship cost that was actually realized over the following tau slots.
+type BacktestWindow struct {
+	StartSlot    uint64
+	Distribution string // best-fitting bid distribution on the training slots
+	RealizedETH  float64
+	EMAETH       float64
+	MedianETH    float64
+	P5ETH        float64
+	P95ETH       float64
+}
+
+// ForecastScore summarizes the errors of one forecasting method.
+type ForecastScore struct {
+	Method    string
+	MAPE      float64 // mean absolute percentage error, over windows with nonzero realized cost
+	BiasETH   float64 // mean of forecast - realized
+	Forecasts int
+}
+
+// BacktestResult contains the out-of-sample accuracy of the cost forecasts.
+// Coverage90 is the fraction of windows whose realized cost fell inside the
+// sampled p5-p95 interval (90% when calibrated) and BelowMedian the fraction
+// that fell below the sampled median (50% when calibrated).
+type BacktestResult struct {
+	Tau          uint64
+	TrainSize    int
+	Windows      []BacktestWindow
+	EMA          ForecastScore
+	Distribution ForecastScore
+	Coverage90   float64
+	BelowMedian  float64
+}
+
+// BacktestCostForecasts walks forward through the bribes in steps of tau
+// slots. At each origin it fits PredictFutureCost and the best bid
+// distribution to the preceding trainSize slots, forecasts C_c for the next
+// tau slots, and scores the forecasts against the realized cost.
+// Cancelling ctx stops the backtest between windows with ctx's error.
+func BacktestCostForecasts(
+	ctx context.Context,
+	bribes []model.SlotBribe,
+	trainSize int,
+	tau uint64,
+	emaAlpha float64,
+	samples int,
+	rng *rand.Rand,
+) (*BacktestResult, error) {
+	if trainSize < 1 || tau == 0 {
+		return nil, fmt.Errorf("training size and tau must be positive")
+	}
+	if samples < 1 {
+		return nil, fmt.Errorf("samples must be positive")
+	}
+	if trainSize+int(tau) > len(bribes) {
+		return nil, fmt.Errorf("need at least %d slots, have %d", trainSize+int(tau), len(bribes))
+	}
+
+	result := &BacktestResult{Tau: tau, TrainSize: trainSize}
+	var emaErrs, distErrs []float64
+	var emaBias, distBias float64
+	covered, below := 0, 0
+
+	for origin := trainSize; origin+int(tau) <= len(bribes); origin += int(tau) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		train := bribes[origin-trainSize : origin]
+		realizedWei, err := model.CensorshipCost(bribes[origin:], tau)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute realized cost at slot %d: %w", bribes[origin].Slot, err)
+		}
+
+		ema, err := NewStatistics(train).PredictFutureCost(tau, emaAlpha)
+		if err != nil {
+			return nil, fmt.Errorf("failed to predict cost at slot %d: %w", bribes[origin].Slot, err)
+		}
+		fits, err := model.FitBidDistributions(train)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fit bids at slot %d: %w", bribes[origin].Slot, err)
+		}
+		sampled := make([]float64, samples)
+		for i := range sampled {
+			sampled[i] = currency.WeiToETHFloat64(fits[0].SampleWindow(rng, tau))
+		}
+		sort.Float64s(sampled)
+
+		w := BacktestWindow{
+			StartSlot:    bribes[origin].Slot,
+			Distribution: fits[0].Distribution.Name(),
+			RealizedETH:  currency.WeiToETHFloat64(realizedWei),
+			EMAETH:       ema,
+			MedianETH:    percentile(sampled, 50),
+			P5ETH:        percentile(sampled, 5),
+			P95ETH:       percentile(sampled, 95),
+		}
+		result.Windows = append(result.Windows, w)
+
+		emaBias += w.EMAETH - w.RealizedETH
+		distBias += w.MedianETH - w.RealizedETH
+		if w.RealizedETH > 0 {
+			emaErrs = append(emaErrs, math.Abs(w.EMAETH-w.RealizedETH)/w.RealizedETH)
+			distErrs = append(distErrs, math.Abs(w.MedianETH-w.RealizedETH)/w.RealizedETH)
+		}
+		if w.RealizedETH >= w.P5ETH && w.RealizedETH <= w.P95ETH {
+			covered++
+		}
+		if w.RealizedETH < w.MedianETH {
+			below++
+		}
+	}
+
+	n := float64(len(result.Windows))
+	result.EMA = ForecastScore{Method: "ema", MAPE: mean(emaErrs), BiasETH: emaBias / n, Forecasts: len(result.Windows)}
+	result.Distribution = ForecastScore{
+		Method:    "distribution",
+		MAPE:      mean(distErrs),
+		BiasETH:   distBias / n,
+		Forecasts: len(result.Windows),
+	}
+	result.Coverage90 = float64(covered) / n
+	result.BelowMedian = float64(below) / n
+
+	return result, nil
+}
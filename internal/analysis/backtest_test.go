@@ -0,0 +1,114 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestBacktestCostForecasts verifies the EMA forecast errors of a series
+// whose forecasts are known exactly, and that the distribution scores
+// follow from the windows.
+func TestBacktestCostForecasts(t *testing.T) {
+	// Blocks of four slots bidding 0.1 to 0.4 ETH times each scale. With
+	// α = 1 the EMA forecast is the last training bid times tau, and the
+	// realized cost is the next block's total, the scale times 1 ETH.
+	var values []float64
+	for _, scale := range []float64{1, 1, 2, 1, 3, 0} {
+		values = append(values, 0.1*scale, 0.2*scale, 0.3*scale, 0.4*scale)
+	}
+	bribes := bribesFromETH(values)
+
+	result, err := BacktestCostForecasts(context.Background(), bribes, 8, 4, 1, 200, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("BacktestCostForecasts failed: %v", err)
+	}
+
+	want := []BacktestWindow{
+		{StartSlot: 9, RealizedETH: 2, EMAETH: 1.6},
+		{StartSlot: 13, RealizedETH: 1, EMAETH: 3.2},
+		{StartSlot: 17, RealizedETH: 3, EMAETH: 1.6},
+		{StartSlot: 21, RealizedETH: 0, EMAETH: 4.8},
+	}
+	if len(result.Windows) != len(want) {
+		t.Fatalf("expected %d windows, got %d", len(want), len(result.Windows))
+	}
+	for i, w := range want {
+		got := result.Windows[i]
+		if got.StartSlot != w.StartSlot || math.Abs(got.RealizedETH-w.RealizedETH) > 1e-9 || math.Abs(got.EMAETH-w.EMAETH) > 1e-9 {
+			t.Errorf("window %d: expected start %d, realized %v and EMA %v, got %d, %v and %v",
+				i, w.StartSlot, w.RealizedETH, w.EMAETH, got.StartSlot, got.RealizedETH, got.EMAETH)
+		}
+		if !(got.P5ETH <= got.MedianETH && got.MedianETH <= got.P95ETH) {
+			t.Errorf("window %d: expected p5 %v <= median %v <= p95 %v", i, got.P5ETH, got.MedianETH, got.P95ETH)
+		}
+	}
+
+	// Errors 0.4/2, 2.2/1 and 1.4/3; the window realizing nothing has no
+	// percentage error but counts toward the bias of (-0.4+2.2-1.4+4.8)/4
+	if wantMAPE := (0.2 + 2.2 + 1.4/3) / 3; math.Abs(result.EMA.MAPE-wantMAPE) > 1e-9 {
+		t.Errorf("expected EMA MAPE %v, got %v", wantMAPE, result.EMA.MAPE)
+	}
+	if math.Abs(result.EMA.BiasETH-1.3) > 1e-9 {
+		t.Errorf("expected EMA bias 1.3, got %v", result.EMA.BiasETH)
+	}
+	if result.EMA.Forecasts != 4 || result.Distribution.Forecasts != 4 {
+		t.Errorf("expected 4 forecasts per method, got %d and %d", result.EMA.Forecasts, result.Distribution.Forecasts)
+	}
+
+	var distErrs []float64
+	var distBias float64
+	covered, below := 0, 0
+	for _, w := range result.Windows {
+		distBias += w.MedianETH - w.RealizedETH
+		if w.RealizedETH > 0 {
+			distErrs = append(distErrs, math.Abs(w.MedianETH-w.RealizedETH)/w.RealizedETH)
+		}
+		if w.RealizedETH >= w.P5ETH && w.RealizedETH <= w.P95ETH {
+			covered++
+		}
+		if w.RealizedETH < w.MedianETH {
+			below++
+		}
+	}
+	if math.Abs(result.Distribution.MAPE-mean(distErrs)) > 1e-9 || math.Abs(result.Distribution.BiasETH-distBias/4) > 1e-9 {
+		t.Errorf("expected distribution MAPE %v and bias %v, got %v and %v",
+			mean(distErrs), distBias/4, result.Distribution.MAPE, result.Distribution.BiasETH)
+	}
+	if result.Coverage90 != float64(covered)/4 || result.BelowMedian != float64(below)/4 {
+		t.Errorf("expected coverage %v and below median %v, got %v and %v",
+			float64(covered)/4, float64(below)/4, result.Coverage90, result.BelowMedian)
+	}
+}
+
+// TestBacktestCostForecasts_Errors verifies invalid sizes, too few slots
+// and a cancelled context are rejected.
+func TestBacktestCostForecasts_Errors(t *testing.T) {
+	bribes := bribesFromETH(repeatETH([]float64{0.1, 0.2, 0.3, 0.4}, 4))
+	rng := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		name      string
+		trainSize int
+		tau       uint64
+		samples   int
+	}{
+		{"zero training size", 0, 4, 10},
+		{"zero tau", 8, 0, 10},
+		{"zero samples", 8, 4, 0},
+		{"too few slots", 14, 4, 10},
+	}
+	for _, tt := range tests {
+		if _, err := BacktestCostForecasts(context.Background(), bribes, tt.trainSize, tt.tau, 0.1, tt.samples, rng); err == nil {
+			t.Errorf("%s: Expected error, got nil", tt.name)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := BacktestCostForecasts(ctx, bribes, 8, 4, 0.1, 10, rng); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
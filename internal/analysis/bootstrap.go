@@ -0,0 +1,140 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// BootstrapConfig tunes ComputeSummaryWithCI. Zero fields select
+// defaults: 1000 resamples of single bids for 95% intervals.
+type BootstrapConfig struct {
+	Resamples  int
+	Confidence float64 // Coverage of each interval, in (0, 1)
+	BlockLen   int     // Consecutive bids drawn together; 0 or 1 draws bids independently
+	Seed       int64
+}
+
+// Bootstrap defaults.
+const (
+	DefaultBootstrapResamples  = 1000
+	DefaultBootstrapConfidence = 0.95
+)
+
+// ConfidenceInterval bounds an estimate.
+type ConfidenceInterval struct {
+	Lower float64
+	Upper float64
+}
+
+// SummaryIntervals holds bootstrap confidence intervals of a Summary's
+// location statistics, in ETH.
+type SummaryIntervals struct {
+	Confidence float64
+	Resamples  int
+	BlockLen   int
+	Seed       int64
+	MeanETH    ConfidenceInterval
+	MedianETH  ConfidenceInterval
+	P25ETH     ConfidenceInterval
+	P75ETH     ConfidenceInterval
+	P95ETH     ConfidenceInterval
+	P99ETH     ConfidenceInterval
+}
+
+// ComputeSummaryWithCI is ComputeSummary with percentile bootstrap
+// confidence intervals for the mean, median and percentiles in CI.
+//
+// Each resample draws as many bids as the data holds, with replacement.
+// Bids of neighbouring slots are correlated, which independent draws
+// ignore and so understate the uncertainty; a BlockLen of an epoch or more
+// draws runs of consecutive bids instead (a moving block bootstrap, as in
+// SimulateAttackOutcomesResampled). Intervals of extreme percentiles from
+// few bids are unreliable whatever the method. Cancelling ctx stops the
+// resampling with ctx's error.
+func (s *Statistics) ComputeSummaryWithCI(ctx context.Context, config BootstrapConfig) (Summary, error) {
+	if config.Resamples == 0 {
+		config.Resamples = DefaultBootstrapResamples
+	}
+	if config.Confidence == 0 {
+		config.Confidence = DefaultBootstrapConfidence
+	}
+	if config.BlockLen == 0 {
+		config.BlockLen = 1
+	}
+	if config.Resamples < 1 {
+		return Summary{}, fmt.Errorf("resamples must be at least 1, got %d", config.Resamples)
+	}
+	if !(config.Confidence > 0 && config.Confidence < 1) {
+		return Summary{}, fmt.Errorf("confidence must be in (0, 1), got %v", config.Confidence)
+	}
+	if config.BlockLen < 1 {
+		return Summary{}, fmt.Errorf("blockLen must be at least 1, got %d", config.BlockLen)
+	}
+	if len(s.bribes) == 0 || len(s.bribes) < config.BlockLen {
+		return Summary{}, fmt.Errorf("%w: need %d bids per block, have %d", model.ErrInsufficientData, config.BlockLen, len(s.bribes))
+	}
+
+	values := make([]float64, len(s.bribes))
+	for i, bribe := range s.bribes {
+		if bribe.ValueWei != nil {
+			values[i] = currency.WeiToETHFloat64(bribe.ValueWei)
+		}
+	}
+
+	ci := &SummaryIntervals{
+		Confidence: config.Confidence,
+		Resamples:  config.Resamples,
+		BlockLen:   config.BlockLen,
+		Seed:       config.Seed,
+	}
+	at := func(p float64) func([]float64) float64 {
+		return func(sorted []float64) float64 { return percentile(sorted, p) }
+	}
+	targets := []struct {
+		interval  *ConfidenceInterval
+		statistic func(sorted []float64) float64
+		estimates []float64
+	}{
+		{interval: &ci.MeanETH, statistic: mean},
+		{interval: &ci.MedianETH, statistic: at(50)},
+		{interval: &ci.P25ETH, statistic: at(25)},
+		{interval: &ci.P75ETH, statistic: at(75)},
+		{interval: &ci.P95ETH, statistic: at(95)},
+		{interval: &ci.P99ETH, statistic: at(99)},
+	}
+
+	rng := rand.New(rand.NewSource(config.Seed))
+	starts := len(values) - config.BlockLen + 1
+	resample := make([]float64, len(values))
+	for r := 0; r < config.Resamples; r++ {
+		if err := ctx.Err(); err != nil {
+			return Summary{}, err
+		}
+		for n := 0; n < len(resample); {
+			n += copy(resample[n:], values[rng.Intn(starts):][:config.BlockLen])
+		}
+		sort.Float64s(resample)
+		for i := range targets {
+			targets[i].estimates = append(targets[i].estimates, targets[i].statistic(resample))
+		}
+	}
+
+	// Percentile intervals: the central Confidence of the estimates
+	tail := (1 - config.Confidence) / 2 * 100
+	for _, target := range targets {
+		sort.Float64s(target.estimates)
+		*target.interval = ConfidenceInterval{
+			Lower: percentile(target.estimates, tail),
+			Upper: percentile(target.estimates, 100-tail),
+		}
+	}
+
+	summary := s.ComputeSummary()
+	summary.CI = ci
+	return summary, nil
+}
@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+// TestComputeSummaryWithCI verifies the intervals bracket the sample's
+// estimates, are reproducible for a seed and depend on it.
+func TestComputeSummaryWithCI(t *testing.T) {
+	stats := NewStatistics(bribesFromETH(lognormalSample(500, 1)))
+	config := BootstrapConfig{Resamples: 500, Seed: 7}
+
+	summary, err := stats.ComputeSummaryWithCI(context.Background(), config)
+	if err != nil {
+		t.Fatalf("ComputeSummaryWithCI failed: %v", err)
+	}
+	ci := summary.CI
+	if ci == nil {
+		t.Fatal("expected confidence intervals, got none")
+	}
+	if ci.Confidence != DefaultBootstrapConfidence || ci.Resamples != 500 || ci.BlockLen != 1 || ci.Seed != 7 {
+		t.Errorf("expected the defaults recorded, got %+v", ci)
+	}
+
+	estimates := []struct {
+		name     string
+		interval ConfidenceInterval
+		estimate float64
+	}{
+		{"mean", ci.MeanETH, summary.MeanETH},
+		{"median", ci.MedianETH, summary.MedianETH},
+		{"p25", ci.P25ETH, summary.P25ETH},
+		{"p75", ci.P75ETH, summary.P75ETH},
+	}
+	for _, e := range estimates {
+		if !(e.interval.Lower < e.estimate && e.estimate < e.interval.Upper) {
+			t.Errorf("%s: expected %v inside (%v, %v)", e.name, e.estimate, e.interval.Lower, e.interval.Upper)
+		}
+	}
+
+	again, err := stats.ComputeSummaryWithCI(context.Background(), config)
+	if err != nil {
+		t.Fatalf("ComputeSummaryWithCI failed: %v", err)
+	}
+	if !reflect.DeepEqual(again.CI, ci) {
+		t.Errorf("expected intervals %+v again for seed 7, got %+v", ci, again.CI)
+	}
+	config.Seed = 8
+	other, err := stats.ComputeSummaryWithCI(context.Background(), config)
+	if err != nil {
+		t.Fatalf("ComputeSummaryWithCI failed: %v", err)
+	}
+	if other.CI.MeanETH == ci.MeanETH {
+		t.Errorf("expected mean intervals to differ between seeds 7 and 8, got %+v for both", ci.MeanETH)
+	}
+}
+
+// TestComputeSummaryWithCI_ZeroWidth verifies data every resample
+// reproduces gives intervals of zero width at the estimates.
+func TestComputeSummaryWithCI_ZeroWidth(t *testing.T) {
+	values := lognormalSample(50, 2)
+	tests := []struct {
+		name   string
+		values []float64
+		config BootstrapConfig
+	}{
+		{"constant bids", repeatETH([]float64{0.5}, 40), BootstrapConfig{Resamples: 100}},
+		// A block as long as the data resamples the data itself
+		{"one block", values, BootstrapConfig{Resamples: 100, BlockLen: len(values)}},
+	}
+
+	for _, tt := range tests {
+		summary, err := NewStatistics(bribesFromETH(tt.values)).ComputeSummaryWithCI(context.Background(), tt.config)
+		if err != nil {
+			t.Fatalf("%s: ComputeSummaryWithCI failed: %v", tt.name, err)
+		}
+		ci := summary.CI
+		for _, e := range []struct {
+			interval ConfidenceInterval
+			estimate float64
+		}{
+			{ci.MeanETH, summary.MeanETH},
+			{ci.MedianETH, summary.MedianETH},
+			{ci.P25ETH, summary.P25ETH},
+			{ci.P75ETH, summary.P75ETH},
+			{ci.P95ETH, summary.P95ETH},
+			{ci.P99ETH, summary.P99ETH},
+		} {
+			if math.Abs(e.interval.Lower-e.estimate) > 1e-12 || math.Abs(e.interval.Upper-e.estimate) > 1e-12 {
+				t.Errorf("%s: expected [%v, %v], got [%v, %v]", tt.name, e.estimate, e.estimate, e.interval.Lower, e.interval.Upper)
+			}
+		}
+	}
+}
+
+// TestComputeSummaryWithCI_Errors verifies invalid configurations, too few
+// bids and a cancelled context are rejected.
+func TestComputeSummaryWithCI_Errors(t *testing.T) {
+	stats := NewStatistics(bribesFromETH(repeatETH([]float64{0.1, 0.2}, 5)))
+
+	tests := []struct {
+		name   string
+		config BootstrapConfig
+	}{
+		{"negative resamples", BootstrapConfig{Resamples: -1}},
+		{"confidence 1", BootstrapConfig{Confidence: 1}},
+		{"confidence NaN", BootstrapConfig{Confidence: math.NaN()}},
+		{"negative block length", BootstrapConfig{BlockLen: -1}},
+	}
+	for _, tt := range tests {
+		if _, err := stats.ComputeSummaryWithCI(context.Background(), tt.config); err == nil {
+			t.Errorf("%s: Expected error, got nil", tt.name)
+		}
+	}
+
+	if _, err := stats.ComputeSummaryWithCI(context.Background(), BootstrapConfig{BlockLen: 11}); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData for a block longer than the data, got %v", err)
+	}
+	if _, err := NewStatistics(nil).ComputeSummaryWithCI(context.Background(), BootstrapConfig{}); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData without bids, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := stats.ComputeSummaryWithCI(ctx, BootstrapConfig{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
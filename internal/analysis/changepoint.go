@@ -0,0 +1,152 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"insolventbydesign/internal/model"
+)
+
+// DetectChangePoints finds shifts in the mean of series with PELT (pruned
+// exact linear time), minimizing the segments' squared deviations from
+// their means plus penalty per change point. It returns the index at which
+// each new segment starts, in increasing order.
+//
+// Segments are at least minSegment values long; a series shorter than two
+// segments has no change points. A penalty of 0 selects 3·σ²·ln(n), with
+// the noise variance σ² estimated from first differences, so pure noise
+// rarely splits, floored so that a noiseless series does not split on
+// rounding.
+func DetectChangePoints(series []float64, penalty float64, minSegment int) ([]int, error) {
+	n := len(series)
+	if minSegment < 1 {
+		return nil, fmt.Errorf("minimum segment length must be at least 1, got %d", minSegment)
+	}
+	if !(penalty >= 0) {
+		return nil, fmt.Errorf("penalty must not be negative, got %v", penalty)
+	}
+	if n < 2*minSegment {
+		return nil, nil
+	}
+
+	// Prefix sums give each segment's cost in constant time
+	sum := make([]float64, n+1)
+	sumSq := make([]float64, n+1)
+	for i, v := range series {
+		sum[i+1] = sum[i] + v
+		sumSq[i+1] = sumSq[i] + v*v
+	}
+	if penalty == 0 {
+		// The floor keeps rounding in the costs from splitting a noiseless
+		// series, whose estimated variance is 0
+		penalty = max(3*noiseVariance(series)*math.Log(float64(n)), 1e-9*sumSq[n])
+	}
+	cost := func(a, b int) float64 {
+		s, m := sum[b]-sum[a], float64(b-a)
+		return math.Max(0, sumSq[b]-sumSq[a]-s*s/m)
+	}
+
+	best := make([]float64, n+1) // Optimal penalized cost of series[:t]
+	last := make([]int, n+1)     // Start of the final segment in that optimum
+	best[0] = -penalty
+	candidates := []int{0}
+	for t := 1; t <= n; t++ {
+		best[t] = math.Inf(1)
+		for _, tau := range candidates {
+			if t-tau < minSegment {
+				continue
+			}
+			if c := best[tau] + cost(tau, t) + penalty; c < best[t] {
+				best[t], last[t] = c, tau
+			}
+		}
+
+		// Prune starts that can never again be optimal
+		kept := candidates[:0]
+		for _, tau := range candidates {
+			if t-tau < minSegment || best[tau]+cost(tau, t) <= best[t] {
+				kept = append(kept, tau)
+			}
+		}
+		candidates = append(kept, t)
+	}
+
+	var points []int
+	for t := last[n]; t > 0; t = last[t] {
+		points = append(points, t)
+	}
+	sort.Ints(points)
+	return points, nil
+}
+
+// noiseVariance estimates the variance of the noise around a piecewise
+// constant mean from first differences, which shifts barely affect: each
+// shift inflates only one difference, so the largest tenth are trimmed.
+func noiseVariance(series []float64) float64 {
+	squares := make([]float64, len(series)-1)
+	for i := range squares {
+		d := series[i+1] - series[i]
+		squares[i] = d * d
+	}
+	sort.Float64s(squares)
+	kept := squares[:len(squares)-len(squares)/10]
+	// Differences double the variance, and trimming the top tenth of a χ²₁
+	// sample keeps 56% of its mass
+	return mean(kept) / 2 / 0.56
+}
+
+// ConcentrationShift is a structural change in one concentration metric:
+// a change in the builder market that invalidates thresholds computed
+// before it.
+type ConcentrationShift struct {
+	Metric string  // "top3", "top5" or "hhi"
+	Slot   uint64  // Last slot of the first window after the shift
+	Before float64 // Mean of the metric over the preceding segment
+	After  float64 // Mean of the metric over the following segment
+}
+
+// DetectConcentrationShifts computes α(top3), α(top5) and HHI over
+// consecutive non-overlapping windows of windowSize slots and detects
+// change points in each with DetectChangePoints (penalty as there).
+// Windows do not overlap so the series' noise is independent; overlapping
+// rolling windows would report spurious shifts. Shifts are ordered by slot.
+func (s *Statistics) DetectConcentrationShifts(windowSize int, penalty float64) ([]ConcentrationShift, error) {
+	if windowSize < 1 {
+		return nil, fmt.Errorf("window size must be at least 1, got %d", windowSize)
+	}
+	trends := s.ComputeConcentrationTrends(windowSize)
+	if len(trends) == 0 {
+		return nil, fmt.Errorf("%w: need %d slots, have %d", model.ErrInsufficientData, windowSize, len(s.bribes))
+	}
+
+	var slots []uint64
+	series := map[string][]float64{}
+	for i := 0; i < len(trends); i += windowSize {
+		t := trends[i]
+		slots = append(slots, t.Slot)
+		series["top3"] = append(series["top3"], t.ConcentrationTop3)
+		series["top5"] = append(series["top5"], t.ConcentrationTop5)
+		series["hhi"] = append(series["hhi"], t.HerfindahlIndex)
+	}
+
+	var shifts []ConcentrationShift
+	for _, metric := range []string{"top3", "top5", "hhi"} {
+		values := series[metric]
+		points, err := DetectChangePoints(values, penalty, 2)
+		if err != nil {
+			return nil, err
+		}
+		bounds := append(append([]int{0}, points...), len(values))
+		for i, p := range points {
+			shifts = append(shifts, ConcentrationShift{
+				Metric: metric,
+				Slot:   slots[p],
+				Before: mean(values[bounds[i]:p]),
+				After:  mean(values[p:bounds[i+2]]),
+			})
+		}
+	}
+	sort.SliceStable(shifts, func(i, j int) bool { return shifts[i].Slot < shifts[j].Slot })
+	return shifts, nil
+}
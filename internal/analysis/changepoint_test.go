@@ -0,0 +1,124 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+// steps returns noisy levels, each held for length values.
+func steps(levels []float64, length int, noise float64, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	series := make([]float64, 0, len(levels)*length)
+	for _, level := range levels {
+		for i := 0; i < length; i++ {
+			series = append(series, level+noise*rng.NormFloat64())
+		}
+	}
+	return series
+}
+
+// TestDetectChangePoints verifies known breaks are found under the
+// default penalty and flat series are left whole.
+func TestDetectChangePoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		series     []float64
+		penalty    float64
+		minSegment int
+		want       []int
+	}{
+		{"step", steps([]float64{1, 3}, 30, 0.1, 1), 0, 2, []int{30}},
+		{"two steps", steps([]float64{0, 5, 2}, 20, 0.2, 2), 0, 2, []int{20, 40}},
+		{"noiseless step", steps([]float64{1, 3}, 30, 0, 1), 0, 2, []int{30}},
+		{"constant", steps([]float64{2}, 60, 0, 1), 0, 2, nil},
+		{"noise", steps([]float64{2}, 200, 0.5, 3), 0, 2, nil},
+		// A penalty above the step's cost reduction keeps it whole
+		{"step under a large penalty", steps([]float64{1, 3}, 30, 0.1, 1), 1000, 2, nil},
+		{"segments over half the series", steps([]float64{1, 3}, 30, 0.1, 1), 0, 31, nil},
+		{"empty", nil, 0, 1, nil},
+	}
+
+	for _, tt := range tests {
+		points, err := DetectChangePoints(tt.series, tt.penalty, tt.minSegment)
+		if err != nil {
+			t.Fatalf("%s: DetectChangePoints failed: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(points, tt.want) {
+			t.Errorf("%s: expected change points %v, got %v", tt.name, tt.want, points)
+		}
+	}
+
+	series := steps([]float64{1, 3}, 30, 0.1, 1)
+	for _, tt := range []struct {
+		name       string
+		penalty    float64
+		minSegment int
+	}{
+		{"negative penalty", -1, 2},
+		{"NaN penalty", math.NaN(), 2},
+		{"zero minimum segment", 0, 0},
+	} {
+		if _, err := DetectChangePoints(series, tt.penalty, tt.minSegment); err == nil {
+			t.Errorf("%s: Expected error, got nil", tt.name)
+		}
+	}
+}
+
+// TestDetectConcentrationShifts verifies a market consolidating onto one
+// builder shows as a shift in every metric at the first window after it.
+func TestDetectConcentrationShifts(t *testing.T) {
+	// 200 slots spread over ten builders, then 200 built by 0x0 alone
+	bribes := bribesFromETH(steps([]float64{0.1}, 400, 0, 1))
+	for i := range bribes {
+		if i < 200 {
+			bribes[i].BuilderPubkey = fmt.Sprintf("0x%d", i%10)
+		} else {
+			bribes[i].BuilderPubkey = "0x0"
+		}
+	}
+
+	shifts, err := NewStatistics(bribes).DetectConcentrationShifts(10, 0)
+	if err != nil {
+		t.Fatalf("DetectConcentrationShifts failed: %v", err)
+	}
+	want := []ConcentrationShift{
+		{Metric: "top3", Slot: 210, Before: 0.3, After: 1},
+		{Metric: "top5", Slot: 210, Before: 0.5, After: 1},
+		{Metric: "hhi", Slot: 210, Before: 0.1, After: 1},
+	}
+	if len(shifts) != len(want) {
+		t.Fatalf("expected shifts %+v, got %+v", want, shifts)
+	}
+	for i, w := range want {
+		got := shifts[i]
+		if got.Metric != w.Metric || got.Slot != w.Slot || math.Abs(got.Before-w.Before) > 1e-9 || math.Abs(got.After-w.After) > 1e-9 {
+			t.Errorf("expected shift %+v, got %+v", w, got)
+		}
+	}
+
+	// A stable market has none
+	shifts, err = NewStatistics(bribes[:200]).DetectConcentrationShifts(10, 0)
+	if err != nil {
+		t.Fatalf("DetectConcentrationShifts failed: %v", err)
+	}
+	if len(shifts) != 0 {
+		t.Errorf("expected no shifts, got %+v", shifts)
+	}
+
+	stats := NewStatistics(bribes[:5])
+	if _, err := stats.DetectConcentrationShifts(10, 0); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+	if _, err := stats.DetectConcentrationShifts(0, 0); err == nil {
+		t.Error("Expected error for zero window size, got nil")
+	}
+	if _, err := NewStatistics(bribes).DetectConcentrationShifts(10, -1); err == nil {
+		t.Error("Expected error for negative penalty, got nil")
+	}
+}
@@ -0,0 +1,317 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// SignificanceTest names the test behind a MetricComparison's p-value.
+type SignificanceTest string
+
+const (
+	// TestNone marks a metric reported without a test.
+	TestNone SignificanceTest = ""
+	// TestWelch is Welch's unequal-variance test of means, with the normal
+	// approximation to its t statistic that ranges of hundreds of slots
+	// justify.
+	TestWelch SignificanceTest = "welch"
+	// TestMannWhitney is the Mann-Whitney U test of whether bids in one
+	// range tend to exceed those in the other, normally approximated with a
+	// correction for ties. It tests the whole distribution rather than the
+	// median alone, but is the usual companion of a median shift.
+	TestMannWhitney SignificanceTest = "mann-whitney"
+	// TestPermutation compares the observed change with the changes over
+	// random relabelings of the pooled slots between the ranges.
+	TestPermutation SignificanceTest = "permutation"
+)
+
+// DefaultPermutations is the number of relabelings of a permutation test
+// when none is given, which resolves p-values down to about 0.001.
+const DefaultPermutations = 1000
+
+// ComparisonConfig parameterizes CompareRanges.
+type ComparisonConfig struct {
+	Tau          uint64  // Censorship duration of the cost and breakeven metrics
+	TopK         int     // Cartel size of α
+	SuccessProb  float64 // Attack success probability p of the breakeven TVL
+	Permutations int     // Relabelings per permutation test; 0 selects DefaultPermutations
+	Seed         int64
+}
+
+// MetricComparison is one metric of a Comparison.
+type MetricComparison struct {
+	Metric         string
+	Before         float64
+	After          float64
+	Change         float64 // After - Before
+	RelativeChange float64 // Change / |Before|; NaN when Before is 0
+	Test           SignificanceTest
+	PValue         float64 // Two-sided; NaN without a test
+}
+
+// Comparison is the difference between two slot ranges, e.g. before and
+// after a protocol upgrade.
+type Comparison struct {
+	BeforeSlots int
+	AfterSlots  int
+	Config      ComparisonConfig
+	Metrics     []MetricComparison
+}
+
+// Metric returns the comparison of the named metric, if present.
+func (c *Comparison) Metric(name string) (MetricComparison, bool) {
+	for _, m := range c.Metrics {
+		if m.Metric == name {
+			return m, true
+		}
+	}
+	return MetricComparison{}, false
+}
+
+// CompareRanges compares the bids, builder concentration and attack
+// economics of two ranges of slots. Metrics, in order:
+//
+//   - mean_bid_eth (Welch) and median_bid_eth (Mann-Whitney)
+//   - cost_eth: the expected τ-slot censorship cost C_c = τ · mean bid,
+//     tested as the mean
+//   - alpha: the top-k builders' share of blocks, α (permutation)
+//   - hhi: the Herfindahl-Hirschman index by blocks (permutation)
+//   - unique_builders (untested: it grows with the number of slots)
+//   - effective_cost_eth: C_c^eff = (1 - α) · C_c (permutation)
+//   - breakeven_tvl_eth: V* = C_c^eff / p, tested as C_c^eff
+//
+// Costs are estimated from mean bids rather than the ranges' first τ slots
+// so ranges of any length compare. Bribes without a value count as zero
+// bids. Neighbouring slots are correlated, which every test here ignores,
+// so p-values are optimistic; treat a small p-value as evidence of a real
+// shift only when the change is also large enough to matter.
+//
+// The permutation tests cost Permutations passes over the pooled slots;
+// ctx is checked before each.
+func CompareRanges(ctx context.Context, before, after []model.SlotBribe, config ComparisonConfig) (*Comparison, error) {
+	if len(before) < 2 || len(after) < 2 {
+		return nil, fmt.Errorf("%w: need at least 2 slots per range, have %d and %d", model.ErrInsufficientData, len(before), len(after))
+	}
+	if config.Tau < 1 {
+		return nil, fmt.Errorf("tau must be at least 1")
+	}
+	if config.TopK < 1 {
+		return nil, fmt.Errorf("%w: topK must be at least 1, got %d", model.ErrInvalidTopK, config.TopK)
+	}
+	if !(config.SuccessProb > 0 && config.SuccessProb <= 1) {
+		return nil, fmt.Errorf("%w: success probability must be in (0,1], got %f", model.ErrInvalidProbability, config.SuccessProb)
+	}
+	if config.Permutations == 0 {
+		config.Permutations = DefaultPermutations
+	}
+	if config.Permutations < 1 {
+		return nil, fmt.Errorf("permutations must be at least 1, got %d", config.Permutations)
+	}
+
+	// Pool both ranges, before first, with builders numbered
+	n := len(before) + len(after)
+	values := make([]float64, 0, n)
+	builders := make([]int, 0, n)
+	ids := make(map[string]int)
+	for _, bribe := range append(append([]model.SlotBribe{}, before...), after...) {
+		var v float64
+		if bribe.ValueWei != nil {
+			v = currency.WeiToETHFloat64(bribe.ValueWei)
+		}
+		id, ok := ids[bribe.BuilderPubkey]
+		if !ok {
+			id = len(ids)
+			ids[bribe.BuilderPubkey] = id
+		}
+		values = append(values, v)
+		builders = append(builders, id)
+	}
+	split := len(before)
+
+	m := newRangeMetrics(len(ids), config)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	b := m.compute(values, builders, order[:split])
+	a := m.compute(values, builders, order[split:])
+
+	// Permutation tests of the concentration metrics, one pass for all
+	observed := [3]float64{a.alpha - b.alpha, a.hhi - b.hhi, a.effectiveCost - b.effectiveCost}
+	var extreme [3]int
+	rng := rand.New(rand.NewSource(config.Seed))
+	for p := 0; p < config.Permutations; p++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rng.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+		pb := m.compute(values, builders, order[:split])
+		pa := m.compute(values, builders, order[split:])
+		for i, d := range [3]float64{pa.alpha - pb.alpha, pa.hhi - pb.hhi, pa.effectiveCost - pb.effectiveCost} {
+			// Tolerate rounding so relabelings tying the observed change count
+			if math.Abs(d) >= math.Abs(observed[i])-1e-12 {
+				extreme[i]++
+			}
+		}
+	}
+	permutationP := func(i int) float64 {
+		return float64(extreme[i]+1) / float64(config.Permutations+1)
+	}
+
+	beforeValues, afterValues := values[:split], values[split:]
+	welch := welchTest(beforeValues, afterValues)
+	tau := float64(config.Tau)
+	metrics := []MetricComparison{
+		compareMetric("mean_bid_eth", b.mean, a.mean, TestWelch, welch),
+		compareMetric("median_bid_eth", median(beforeValues), median(afterValues), TestMannWhitney, mannWhitneyTest(beforeValues, afterValues)),
+		compareMetric("cost_eth", tau*b.mean, tau*a.mean, TestWelch, welch),
+		compareMetric("alpha", b.alpha, a.alpha, TestPermutation, permutationP(0)),
+		compareMetric("hhi", b.hhi, a.hhi, TestPermutation, permutationP(1)),
+		compareMetric("unique_builders", float64(b.unique), float64(a.unique), TestNone, math.NaN()),
+		compareMetric("effective_cost_eth", b.effectiveCost, a.effectiveCost, TestPermutation, permutationP(2)),
+		compareMetric("breakeven_tvl_eth", b.effectiveCost/config.SuccessProb, a.effectiveCost/config.SuccessProb, TestPermutation, permutationP(2)),
+	}
+	return &Comparison{
+		BeforeSlots: len(before),
+		AfterSlots:  len(after),
+		Config:      config,
+		Metrics:     metrics,
+	}, nil
+}
+
+func compareMetric(name string, before, after float64, test SignificanceTest, pValue float64) MetricComparison {
+	relative := math.NaN()
+	if before != 0 {
+		relative = (after - before) / math.Abs(before)
+	}
+	return MetricComparison{
+		Metric:         name,
+		Before:         before,
+		After:          after,
+		Change:         after - before,
+		RelativeChange: relative,
+		Test:           test,
+		PValue:         pValue,
+	}
+}
+
+// rangeMetrics computes the permuted metrics of a subset of the pooled
+// slots, reusing its buffers across subsets.
+type rangeMetrics struct {
+	config ComparisonConfig
+	counts []int
+	shares []int
+}
+
+type rangeMetricValues struct {
+	mean          float64
+	alpha         float64
+	hhi           float64
+	unique        int
+	effectiveCost float64
+}
+
+func newRangeMetrics(numBuilders int, config ComparisonConfig) *rangeMetrics {
+	return &rangeMetrics{config: config, counts: make([]int, numBuilders)}
+}
+
+// compute returns the metrics of the slots at indexes, α and the HHI as
+// model.ComputeBuilderConcentration and model.HerfindahlIndex compute them
+// by blocks.
+func (m *rangeMetrics) compute(values []float64, builders []int, indexes []int) rangeMetricValues {
+	clear(m.counts)
+	var sum float64
+	for _, i := range indexes {
+		sum += values[i]
+		m.counts[builders[i]]++
+	}
+	m.shares = m.shares[:0]
+	for _, count := range m.counts {
+		if count > 0 {
+			m.shares = append(m.shares, count)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(m.shares)))
+
+	total := float64(len(indexes))
+	r := rangeMetricValues{mean: sum / total, unique: len(m.shares)}
+	var top int
+	for i, count := range m.shares {
+		if i < m.config.TopK {
+			top += count
+		}
+		share := float64(count) / total
+		r.hhi += share * share
+	}
+	r.alpha = float64(top) / total
+	r.effectiveCost = (1 - r.alpha) * float64(m.config.Tau) * r.mean
+	return r
+}
+
+// welchTest returns the two-sided p-value of Welch's test that x and y
+// have equal means.
+func welchTest(x, y []float64) float64 {
+	mx, my := mean(x), mean(y)
+	se := math.Sqrt(sampleVariance(x, mx)/float64(len(x)) + sampleVariance(y, my)/float64(len(y)))
+	return twoSidedP(my-mx, se)
+}
+
+// mannWhitneyTest returns the two-sided p-value of the Mann-Whitney U test
+// that values of x and y are equally likely to exceed each other.
+func mannWhitneyTest(x, y []float64) float64 {
+	pooled := append(append([]float64{}, x...), y...)
+	r := ranks(pooled)
+	var rankSum float64
+	for _, rank := range r[len(x):] {
+		rankSum += rank
+	}
+	nx, ny, n := float64(len(x)), float64(len(y)), float64(len(pooled))
+	u := rankSum - ny*(ny+1)/2
+
+	// Ties shrink the variance of U
+	sort.Float64s(pooled)
+	var ties float64
+	for start := 0; start < len(pooled); {
+		end := start + 1
+		for end < len(pooled) && pooled[end] == pooled[start] {
+			end++
+		}
+		t := float64(end - start)
+		ties += t*t*t - t
+		start = end
+	}
+	variance := nx * ny / 12 * ((n + 1) - ties/(n*(n-1)))
+	return twoSidedP(u-nx*ny/2, math.Sqrt(math.Max(variance, 0)))
+}
+
+// twoSidedP returns the two-sided normal p-value of a statistic's
+// deviation from its null value given its standard error.
+func twoSidedP(deviation, se float64) float64 {
+	if se == 0 {
+		if deviation == 0 {
+			return 1
+		}
+		return 0
+	}
+	return math.Erfc(math.Abs(deviation/se) / math.Sqrt2)
+}
+
+func sampleVariance(values []float64, mean float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / float64(len(values)-1)
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	return percentile(sorted, 50)
+}
@@ -0,0 +1,156 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+// comparisonRange builds n slots from startSlot whose bids cycle through
+// 0.1 to 0.7 ETH plus shiftETH, won by builders cycling through the given
+// count.
+func comparisonRange(startSlot uint64, n int, shiftETH float64, builders int) []model.SlotBribe {
+	bribes := make([]model.SlotBribe, n)
+	for i := range bribes {
+		wei, _ := new(big.Float).Mul(big.NewFloat(0.1*float64(1+i%7)+shiftETH), big.NewFloat(1e18)).Int(nil)
+		bribes[i] = model.SlotBribe{
+			Slot:          startSlot + uint64(i),
+			ValueWei:      wei,
+			BuilderPubkey: fmt.Sprintf("0x%d", i%builders),
+		}
+	}
+	return bribes
+}
+
+// TestCompareRanges_Identical verifies identical ranges show no change and
+// a p-value of 1 under every test.
+func TestCompareRanges_Identical(t *testing.T) {
+	bribes := comparisonRange(0, 60, 0, 4)
+	config := ComparisonConfig{Tau: 10, TopK: 2, SuccessProb: 0.5, Permutations: 200}
+	comparison, err := CompareRanges(context.Background(), bribes, bribes, config)
+	if err != nil {
+		t.Fatalf("CompareRanges failed: %v", err)
+	}
+
+	for _, m := range comparison.Metrics {
+		if math.Abs(m.Change) > 1e-12 {
+			t.Errorf("%s: expected no change, got %v", m.Metric, m.Change)
+		}
+		if m.Test != TestNone && math.Abs(m.PValue-1) > 1e-9 {
+			t.Errorf("%s: expected %s p-value 1, got %v", m.Metric, m.Test, m.PValue)
+		}
+	}
+}
+
+// TestCompareRanges_Shifted verifies a shift in bids and a change of
+// builders give small p-values under each test.
+func TestCompareRanges_Shifted(t *testing.T) {
+	before := comparisonRange(0, 60, 0, 1)
+	after := comparisonRange(60, 60, 0.5, 6)
+	config := ComparisonConfig{Tau: 10, TopK: 1, SuccessProb: 0.5, Permutations: 200}
+	comparison, err := CompareRanges(context.Background(), before, after, config)
+	if err != nil {
+		t.Fatalf("CompareRanges failed: %v", err)
+	}
+
+	tests := []struct {
+		metric string
+		test   SignificanceTest
+		change float64
+	}{
+		{"mean_bid_eth", TestWelch, 0.5},
+		{"median_bid_eth", TestMannWhitney, 0.5},
+		{"cost_eth", TestWelch, 5},
+		{"alpha", TestPermutation, 1.0/6 - 1},
+		{"hhi", TestPermutation, 1.0/6 - 1},
+	}
+	for _, tt := range tests {
+		m, ok := comparison.Metric(tt.metric)
+		if !ok {
+			t.Fatalf("expected metric %s, got none", tt.metric)
+		}
+		if m.Test != tt.test {
+			t.Errorf("%s: expected test %s, got %s", tt.metric, tt.test, m.Test)
+		}
+		if math.Abs(m.Change-tt.change) > 1e-9 {
+			t.Errorf("%s: expected change %v, got %v", tt.metric, tt.change, m.Change)
+		}
+		if m.PValue > 0.01 {
+			t.Errorf("%s: expected p-value below 0.01, got %v", tt.metric, m.PValue)
+		}
+	}
+
+	// No relabeling of the pooled slots is as extreme as the observed split
+	if m, _ := comparison.Metric("alpha"); m.PValue != 1.0/201 {
+		t.Errorf("expected permutation p-value 1/201, got %v", m.PValue)
+	}
+}
+
+// TestCompareRanges_Seed verifies permutation p-values are reproducible
+// for a seed and depend on it.
+func TestCompareRanges_Seed(t *testing.T) {
+	// Similar builder mixes, so p-values depend on the relabelings drawn
+	before := comparisonRange(0, 40, 0, 5)
+	after := comparisonRange(40, 40, 0.05, 6)
+	config := ComparisonConfig{Tau: 10, TopK: 1, SuccessProb: 0.5, Permutations: 100, Seed: 7}
+
+	first, err := CompareRanges(context.Background(), before, after, config)
+	if err != nil {
+		t.Fatalf("CompareRanges failed: %v", err)
+	}
+	second, err := CompareRanges(context.Background(), before, after, config)
+	if err != nil {
+		t.Fatalf("CompareRanges failed: %v", err)
+	}
+	for i, m := range first.Metrics {
+		if m.Test == TestPermutation && m.PValue != second.Metrics[i].PValue {
+			t.Errorf("%s: expected p-value %v again for seed 7, got %v", m.Metric, m.PValue, second.Metrics[i].PValue)
+		}
+	}
+
+	config.Seed = 8
+	other, err := CompareRanges(context.Background(), before, after, config)
+	if err != nil {
+		t.Fatalf("CompareRanges failed: %v", err)
+	}
+	a, _ := first.Metric("alpha")
+	b, _ := other.Metric("alpha")
+	if a.PValue == b.PValue {
+		t.Errorf("expected alpha p-values to differ between seeds 7 and 8, got %v for both", a.PValue)
+	}
+}
+
+// TestCompareRanges_Errors verifies invalid configurations and a
+// cancelled context are rejected.
+func TestCompareRanges_Errors(t *testing.T) {
+	bribes := comparisonRange(0, 10, 0, 2)
+	valid := ComparisonConfig{Tau: 10, TopK: 1, SuccessProb: 0.5}
+
+	tests := []struct {
+		name   string
+		before []model.SlotBribe
+		config ComparisonConfig
+	}{
+		{"one slot", bribes[:1], valid},
+		{"zero tau", bribes, ComparisonConfig{TopK: 1, SuccessProb: 0.5}},
+		{"zero top-k", bribes, ComparisonConfig{Tau: 10, SuccessProb: 0.5}},
+		{"zero success probability", bribes, ComparisonConfig{Tau: 10, TopK: 1}},
+		{"negative permutations", bribes, ComparisonConfig{Tau: 10, TopK: 1, SuccessProb: 0.5, Permutations: -1}},
+	}
+	for _, tt := range tests {
+		if _, err := CompareRanges(context.Background(), tt.before, bribes, tt.config); err == nil {
+			t.Errorf("%s: Expected error, got nil", tt.name)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CompareRanges(ctx, bribes, bribes, valid); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,306 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+	"insolventbydesign/internal/storage"
+)
+
+// SlotValue is one observation of a series at a slot.
+type SlotValue struct {
+	Slot  uint64
+	Value float64
+}
+
+// DriverSeries is a candidate driver of bids, such as the base fee or the
+// ETH price.
+type DriverSeries struct {
+	Name   string
+	Values []SlotValue // Sorted by slot
+}
+
+// SamplePrices samples provider's ETH/USD price every step slots from
+// startSlot to endSlot, for joining with bids as a DriverSeries. Samples
+// before the first quote, or too old for the provider, are skipped.
+func SamplePrices(ctx context.Context, provider currency.PriceProvider, network model.Network, startSlot, endSlot, step uint64) ([]SlotValue, error) {
+	if step < 1 {
+		return nil, fmt.Errorf("step must be at least 1 slot")
+	}
+	var prices []SlotValue
+	// more is computed from the slot before the step, so the loop stops
+	// before slot+step can overflow past endSlot
+	for slot, more := startSlot, startSlot <= endSlot; more; slot, more = slot+step, endSlot-slot >= step {
+		quote, err := provider.PriceAt(ctx, network.SlotTime(slot))
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, currency.ErrStalePrice) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ETH price at slot %d: %w", slot, err)
+		}
+		prices = append(prices, SlotValue{Slot: slot, Value: quote.USD})
+	}
+	return prices, nil
+}
+
+// JoinedDrivers is bids aligned with driver series slot by slot.
+type JoinedDrivers struct {
+	Slots   []uint64
+	BidETH  []float64
+	Names   []string
+	Drivers [][]float64 // Drivers[i] holds series Names[i] at each slot
+}
+
+// JoinDrivers aligns each bid with the latest value of every series at or
+// before its slot (an as-of join), so hourly prices cover the slots of
+// their hour. Bids before any series starts, and bribes without a value,
+// are dropped.
+func (s *Statistics) JoinDrivers(series ...DriverSeries) (*JoinedDrivers, error) {
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no driver series to join")
+	}
+	j := &JoinedDrivers{Drivers: make([][]float64, len(series))}
+	for _, d := range series {
+		if !sort.SliceIsSorted(d.Values, func(a, b int) bool { return d.Values[a].Slot < d.Values[b].Slot }) {
+			return nil, fmt.Errorf("driver series %s is not sorted by slot", d.Name)
+		}
+		j.Names = append(j.Names, d.Name)
+	}
+
+	next := make([]int, len(series)) // Index of each series' first value after the current slot
+	for _, bribe := range s.bribes {
+		if bribe.ValueWei == nil {
+			continue
+		}
+		ready := true
+		for i, d := range series {
+			for next[i] < len(d.Values) && d.Values[next[i]].Slot <= bribe.Slot {
+				next[i]++
+			}
+			ready = ready && next[i] > 0
+		}
+		if !ready {
+			continue
+		}
+		j.Slots = append(j.Slots, bribe.Slot)
+		j.BidETH = append(j.BidETH, currency.WeiToETHFloat64(bribe.ValueWei))
+		for i, d := range series {
+			j.Drivers[i] = append(j.Drivers[i], d.Values[next[i]-1].Value)
+		}
+	}
+	if len(j.Slots) < 3 {
+		return nil, fmt.Errorf("%w: %d bids overlap the driver series", model.ErrInsufficientData, len(j.Slots))
+	}
+	return j, nil
+}
+
+// DriverAnalysis relates bids to one driver.
+type DriverAnalysis struct {
+	Name            string
+	Correlation     float64   // Pearson correlation with bids
+	RankCorrelation float64   // Spearman correlation, robust to outlying bids
+	Slope           float64   // Bid ETH per unit of the driver in a simple regression
+	Intercept       float64   // Bid ETH at a driver value of zero
+	RSquared        float64   // Share of bid variance the regression explains
+	Rolling         []float64 // Pearson correlation over each window of slots, by window end
+}
+
+// Analyze correlates bids with each driver over the whole join and over
+// rolling windows of window bids, and regresses bids on each alone.
+func (j *JoinedDrivers) Analyze(window int) ([]DriverAnalysis, error) {
+	if window < 3 {
+		return nil, fmt.Errorf("window must be at least 3, got %d", window)
+	}
+	analyses := make([]DriverAnalysis, 0, len(j.Names))
+	for i, name := range j.Names {
+		reg, err := j.Regress(name)
+		if err != nil {
+			return nil, err
+		}
+		analyses = append(analyses, DriverAnalysis{
+			Name:            name,
+			Correlation:     Correlation(j.Drivers[i], j.BidETH),
+			RankCorrelation: RankCorrelation(j.Drivers[i], j.BidETH),
+			Slope:           reg.Coefficients[0],
+			Intercept:       reg.Intercept,
+			RSquared:        reg.RSquared,
+			Rolling:         RollingCorrelation(j.Drivers[i], j.BidETH, window),
+		})
+	}
+	return analyses, nil
+}
+
+// Regression is an ordinary least squares fit of bids on drivers.
+type Regression struct {
+	Names        []string
+	Intercept    float64
+	Coefficients []float64 // Bid ETH per unit of each driver, in Names order
+	RSquared     float64
+	Observations int
+}
+
+// Regress fits bid = intercept + Σ coefficient·driver over the named
+// drivers by least squares. A driver that is constant over the join, or a
+// linear combination of others, cannot be fitted.
+func (j *JoinedDrivers) Regress(names ...string) (Regression, error) {
+	if len(names) == 0 {
+		return Regression{}, fmt.Errorf("no drivers to regress on")
+	}
+	columns := make([][]float64, len(names))
+	for k, name := range names {
+		i := indexOf(j.Names, name)
+		if i < 0 {
+			return Regression{}, fmt.Errorf("unknown driver %s", name)
+		}
+		columns[k] = j.Drivers[i]
+	}
+	if len(j.BidETH) <= len(names)+1 {
+		return Regression{}, fmt.Errorf("%w: %d bids for %d drivers", model.ErrInsufficientData, len(j.BidETH), len(names))
+	}
+
+	// Normal equations X'X b = X'y over rows (1, x_1, ..., x_k)
+	n := len(names) + 1
+	xtx := make([][]float64, n)
+	for i := range xtx {
+		xtx[i] = make([]float64, n+1) // Augmented with X'y
+	}
+	row := make([]float64, n)
+	for t, y := range j.BidETH {
+		row[0] = 1
+		for k, column := range columns {
+			row[k+1] = column[t]
+		}
+		for a := 0; a < n; a++ {
+			for b := 0; b < n; b++ {
+				xtx[a][b] += row[a] * row[b]
+			}
+			xtx[a][n] += row[a] * y
+		}
+	}
+	coef, err := solveLinear(xtx)
+	if err != nil {
+		return Regression{}, fmt.Errorf("failed to regress bids on %v: %w", names, err)
+	}
+
+	yMean := mean(j.BidETH)
+	var sse, sst float64
+	for t, y := range j.BidETH {
+		pred := coef[0]
+		for k, column := range columns {
+			pred += coef[k+1] * column[t]
+		}
+		sse += (y - pred) * (y - pred)
+		sst += (y - yMean) * (y - yMean)
+	}
+	reg := Regression{
+		Names:        names,
+		Intercept:    coef[0],
+		Coefficients: coef[1:],
+		Observations: len(j.BidETH),
+	}
+	if sst > 0 {
+		reg.RSquared = 1 - sse/sst
+	}
+	return reg, nil
+}
+
+// Correlation returns the Pearson correlation of x and y, or 0 if either
+// is constant.
+func Correlation(x, y []float64) float64 {
+	mx, my := mean(x), mean(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// RankCorrelation returns the Spearman correlation of x and y: the Pearson
+// correlation of their ranks, with ties sharing their mean rank.
+func RankCorrelation(x, y []float64) float64 {
+	return Correlation(ranks(x), ranks(y))
+}
+
+// ranks returns each value's 1-based rank, averaging tied ranks.
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+
+	r := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && values[order[end]] == values[order[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2 // Mean of ranks start+1 .. end
+		for _, i := range order[start:end] {
+			r[i] = rank
+		}
+		start = end
+	}
+	return r
+}
+
+// RollingCorrelation returns the Pearson correlation of x and y over each
+// window of consecutive observations, the i-th ending at observation
+// window-1+i. Running sums make it O(n); values are centered on their
+// overall means first so the sums do not lose precision.
+func RollingCorrelation(x, y []float64, window int) []float64 {
+	if window < 2 || len(x) < window {
+		return nil
+	}
+	mx, my := mean(x), mean(y)
+	var sx, sy, sxx, syy, sxy float64
+	add := func(i int, sign float64) {
+		dx, dy := x[i]-mx, y[i]-my
+		sx += sign * dx
+		sy += sign * dy
+		sxx += sign * dx * dx
+		syy += sign * dy * dy
+		sxy += sign * dx * dy
+	}
+
+	w := float64(window)
+	rolling := make([]float64, 0, len(x)-window+1)
+	for i := range x {
+		add(i, 1)
+		if i >= window {
+			add(i-window, -1)
+		}
+		if i < window-1 {
+			continue
+		}
+		cov := sxy - sx*sy/w
+		vx, vy := sxx-sx*sx/w, syy-sy*sy/w
+		if vx <= 1e-12*w || vy <= 1e-12*w {
+			rolling = append(rolling, 0)
+			continue
+		}
+		rolling = append(rolling, math.Max(-1, math.Min(1, cov/math.Sqrt(vx*vy))))
+	}
+	return rolling
+}
+
+// indexOf returns the index of name in names, or -1.
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
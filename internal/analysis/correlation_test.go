@@ -0,0 +1,200 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+	"insolventbydesign/internal/storage"
+)
+
+// TestJoinDrivers verifies each bid takes the latest value of every series
+// at or before its slot, and bids before any series starts are dropped.
+func TestJoinDrivers(t *testing.T) {
+	bribes := bribesFromETH([]float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0})
+	bribes[6].ValueWei = nil // Slot 7
+	fee := DriverSeries{Name: "fee", Values: []SlotValue{{3, 10}, {6, 20}}}
+	price := DriverSeries{Name: "price", Values: []SlotValue{{5, 1}, {9, 2}}}
+
+	j, err := NewStatistics(bribes).JoinDrivers(fee, price)
+	if err != nil {
+		t.Fatalf("JoinDrivers failed: %v", err)
+	}
+	want := &JoinedDrivers{
+		Slots:   []uint64{5, 6, 8, 9, 10},
+		BidETH:  []float64{0.5, 0.6, 0.8, 0.9, 1.0},
+		Names:   []string{"fee", "price"},
+		Drivers: [][]float64{{10, 20, 20, 20, 20}, {1, 1, 1, 2, 2}},
+	}
+	if !reflect.DeepEqual(j.Slots, want.Slots) || !reflect.DeepEqual(j.Names, want.Names) || !reflect.DeepEqual(j.Drivers, want.Drivers) {
+		t.Errorf("expected join %+v, got %+v", want, j)
+	}
+	for i := range want.BidETH {
+		if math.Abs(j.BidETH[i]-want.BidETH[i]) > 1e-12 {
+			t.Errorf("expected bid %v at slot %d, got %v", want.BidETH[i], want.Slots[i], j.BidETH[i])
+		}
+	}
+
+	unsorted := DriverSeries{Name: "unsorted", Values: []SlotValue{{4, 1}, {2, 1}}}
+	if _, err := NewStatistics(bribes).JoinDrivers(fee, unsorted); err == nil {
+		t.Error("Expected error for unsorted series, got nil")
+	}
+	if _, err := NewStatistics(bribes).JoinDrivers(); err == nil {
+		t.Error("Expected error without series, got nil")
+	}
+	late := DriverSeries{Name: "late", Values: []SlotValue{{9, 1}}}
+	if _, err := NewStatistics(bribes).JoinDrivers(late); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData for two overlapping bids, got %v", err)
+	}
+}
+
+// TestRegress verifies known coefficients are recovered exactly and
+// unfittable drivers are rejected.
+func TestRegress(t *testing.T) {
+	j := &JoinedDrivers{Names: []string{"x1", "x2", "copy"}, Drivers: make([][]float64, 3)}
+	for i := 0; i < 20; i++ {
+		x1, x2 := float64(i), float64(i*i%7)
+		j.Slots = append(j.Slots, uint64(i))
+		j.BidETH = append(j.BidETH, 1+2*x1-3*x2)
+		j.Drivers[0] = append(j.Drivers[0], x1)
+		j.Drivers[1] = append(j.Drivers[1], x2)
+		j.Drivers[2] = append(j.Drivers[2], x1)
+	}
+
+	reg, err := j.Regress("x1", "x2")
+	if err != nil {
+		t.Fatalf("Regress failed: %v", err)
+	}
+	if math.Abs(reg.Intercept-1) > 1e-9 || math.Abs(reg.Coefficients[0]-2) > 1e-9 || math.Abs(reg.Coefficients[1]+3) > 1e-9 {
+		t.Errorf("expected y = 1 + 2·x1 - 3·x2, got intercept %v and coefficients %v", reg.Intercept, reg.Coefficients)
+	}
+	if math.Abs(reg.RSquared-1) > 1e-9 || reg.Observations != 20 {
+		t.Errorf("expected R² 1 over 20 observations, got %v over %d", reg.RSquared, reg.Observations)
+	}
+
+	// Alone, x2 explains only part of the bids
+	reg, err = j.Regress("x2")
+	if err != nil {
+		t.Fatalf("Regress failed: %v", err)
+	}
+	if reg.RSquared <= 0 || reg.RSquared >= 1 {
+		t.Errorf("expected R² strictly between 0 and 1, got %v", reg.RSquared)
+	}
+
+	for _, names := range [][]string{nil, {"unknown"}, {"x1", "copy"}} {
+		if _, err := j.Regress(names...); err == nil {
+			t.Errorf("%v: Expected error, got nil", names)
+		}
+	}
+}
+
+// TestRankCorrelation verifies tied values share their mean rank.
+func TestRankCorrelation(t *testing.T) {
+	x := []float64{3, 1, 2, 2}
+	if got, want := ranks(x), []float64{4, 1, 2.5, 2.5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected ranks %v, got %v", want, got)
+	}
+
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+	}{
+		// Ranks 1, 2.5, 2.5, 4 against 1, 2, 3, 4; ordinal ranks would give 1
+		{"ties in x", []float64{1, 2, 2, 3}, []float64{1, 2, 3, 4}, 4.5 / math.Sqrt(4.5*5)},
+		{"monotone with ties", []float64{1, 2, 2, 3}, []float64{10, 40, 40, 90}, 1},
+		{"reversed", []float64{1, 2, 3, 4}, []float64{8, 4, 2, 1}, -1},
+		{"constant", []float64{1, 2, 3}, []float64{5, 5, 5}, 0},
+	}
+	for _, tt := range tests {
+		if got := RankCorrelation(tt.x, tt.y); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestRollingCorrelation verifies the running sums match a Pearson
+// correlation recomputed over every window.
+func TestRollingCorrelation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	x, y := make([]float64, 300), make([]float64, 300)
+	for i := range x {
+		// Large offsets test the centering; a constant stretch tests the
+		// zero-variance case
+		x[i] = 1e6 + rng.NormFloat64()
+		if i >= 100 && i < 130 {
+			x[i] = 1e6
+		}
+		y[i] = 0.5*x[i] + rng.NormFloat64()
+	}
+
+	for _, window := range []int{2, 5, 20} {
+		rolling := RollingCorrelation(x, y, window)
+		if len(rolling) != len(x)-window+1 {
+			t.Fatalf("window %d: expected %d correlations, got %d", window, len(x)-window+1, len(rolling))
+		}
+		for i, got := range rolling {
+			if want := Correlation(x[i:i+window], y[i:i+window]); math.Abs(got-want) > 1e-6 {
+				t.Fatalf("window %d ending at %d: expected %v, got %v", window, i+window-1, want, got)
+			}
+		}
+	}
+
+	if RollingCorrelation(x, y, 1) != nil || RollingCorrelation(x[:3], y[:3], 4) != nil {
+		t.Error("expected no correlations for a window below 2 or above the series")
+	}
+}
+
+// missingPrices quotes the Unix time as the price when quoted reports one,
+// and reports it not found otherwise. It fails after 100 calls, so a
+// runaway sampling loop ends.
+type missingPrices struct {
+	quoted func(at time.Time) bool
+	calls  int
+}
+
+func (p *missingPrices) PriceAt(ctx context.Context, at time.Time) (model.PriceQuote, error) {
+	p.calls++
+	if p.calls > 100 {
+		return model.PriceQuote{}, errors.New("too many calls")
+	}
+	if !p.quoted(at) {
+		return model.PriceQuote{}, storage.ErrNotFound
+	}
+	return model.PriceQuote{Timestamp: at, USD: float64(at.Unix())}, nil
+}
+
+// TestSamplePrices verifies missing quotes are skipped and sampling stops
+// at the end slot even when the next step would overflow.
+func TestSamplePrices(t *testing.T) {
+	network := model.Network{Name: "test", GenesisTime: time.Unix(0, 0).UTC(), SecondsPerSlot: 1}
+
+	provider := &missingPrices{quoted: func(at time.Time) bool { return at.Unix()%2 == 0 }}
+	prices, err := SamplePrices(context.Background(), provider, network, 1, 10, 3)
+	if err != nil {
+		t.Fatalf("SamplePrices failed: %v", err)
+	}
+	// Slots 1, 4, 7 and 10, of which 1 and 7 have no quote
+	if want := []SlotValue{{4, 4}, {10, 10}}; !reflect.DeepEqual(prices, want) || provider.calls != 4 {
+		t.Errorf("expected %v from 4 calls, got %v from %d", want, prices, provider.calls)
+	}
+
+	// No quote at the last slot, so skipping it must still stop the loop
+	provider = &missingPrices{quoted: func(time.Time) bool { return false }}
+	if _, err := SamplePrices(context.Background(), provider, network, math.MaxUint64-4, math.MaxUint64, 4); err != nil {
+		t.Fatalf("SamplePrices failed: %v", err)
+	}
+	if provider.calls != 2 {
+		t.Errorf("expected 2 samples up to the last slot, got %d", provider.calls)
+	}
+
+	if _, err := SamplePrices(context.Background(), currency.FixedPrice(3000), network, 1, 10, 0); err == nil {
+		t.Error("Expected error for step 0, got nil")
+	}
+}
@@ -0,0 +1,340 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// DefaultSeasonalPeriod is the Holt-Winters season: one day of 12-second
+// slots, over which builder activity and MEV follow a daily cycle.
+const DefaultSeasonalPeriod = 7200
+
+// Forecasting methods.
+const (
+	ForecastEMA         = "ema"
+	ForecastHoltWinters = "holt-winters"
+	ForecastARIMA       = "arima"
+)
+
+// CostForecast is a forecast of the censorship cost of the next τ slots
+// with a prediction interval.
+type CostForecast struct {
+	Method     string
+	Tau        uint64
+	CostETH    float64            // Point forecast of the summed bribes
+	LowerETH   float64            // Lower prediction bound, at least 0
+	UpperETH   float64            // Upper prediction bound
+	Confidence float64            // Coverage of [LowerETH, UpperETH], e.g. 0.9
+	StdDevETH  float64            // In-sample one-step residual standard deviation per slot
+	Params     map[string]float64 // Fitted parameters
+}
+
+// Holt-Winters smoothing parameter grids searched by ForecastHoltWinters.
+var (
+	hwAlphas = []float64{0.01, 0.05, 0.1, 0.2, 0.4}
+	hwBetas  = []float64{0, 0.001, 0.01, 0.05}
+	hwGammas = []float64{0, 0.01, 0.05, 0.2}
+)
+
+// ForecastHoltWinters forecasts the cost of the next tau slots with
+// additive Holt-Winters smoothing: a level, a trend and a seasonal offset
+// per slot of the period (0 selects DefaultSeasonalPeriod). Smoothing
+// parameters are chosen from a grid by in-sample one-step squared error,
+// and the interval assumes normal one-step errors propagated through the
+// model's state. At least two periods of data are required.
+//
+// Like PredictFutureCost, the series is the bribes in order, skipping
+// those without a value; missing slots are not filled.
+func (s *Statistics) ForecastHoltWinters(tau uint64, period int, confidence float64) (CostForecast, error) {
+	if period == 0 {
+		period = DefaultSeasonalPeriod
+	}
+	if period < 2 {
+		return CostForecast{}, fmt.Errorf("seasonal period must be at least 2, got %d", period)
+	}
+	if err := validateForecast(tau, confidence); err != nil {
+		return CostForecast{}, err
+	}
+	y := s.valueSeries()
+	if len(y) < 2*period {
+		return CostForecast{}, fmt.Errorf("%w: Holt-Winters needs two periods (%d slots), have %d", model.ErrInsufficientData, 2*period, len(y))
+	}
+
+	var best *holtWinters
+	for _, alpha := range hwAlphas {
+		for _, beta := range hwBetas {
+			for _, gamma := range hwGammas {
+				hw := &holtWinters{alpha: alpha, beta: beta, gamma: gamma, period: period}
+				hw.fit(y)
+				if best == nil || hw.sse < best.sse {
+					best = hw
+				}
+			}
+		}
+	}
+
+	// Point forecast, and the weights c_j of past errors in the j-step
+	// forecast error
+	var cost float64
+	weights := make([]float64, tau)
+	for h := uint64(1); h <= tau; h++ {
+		cost += best.level + float64(h)*best.trend + best.season[(len(y)+int(h)-1)%period]
+		j := h - 1
+		if j == 0 {
+			weights[j] = 1
+			continue
+		}
+		weights[j] = best.alpha * (1 + best.beta*float64(j))
+		if j%uint64(period) == 0 {
+			weights[j] += best.gamma * (1 - best.alpha)
+		}
+	}
+
+	sigma := math.Sqrt(best.sse / float64(len(y)-period))
+	forecast := newCostForecast(ForecastHoltWinters, tau, cost, sigma, weights, confidence)
+	forecast.Params = map[string]float64{
+		"alpha":  best.alpha,
+		"beta":   best.beta,
+		"gamma":  best.gamma,
+		"period": float64(period),
+	}
+	return forecast, nil
+}
+
+// holtWinters is additive Holt-Winters smoothing with fixed parameters.
+type holtWinters struct {
+	alpha, beta, gamma float64
+	period             int
+
+	// State after fitting
+	level, trend float64
+	season       []float64
+	sse          float64 // Sum of squared one-step errors after the first period
+}
+
+// fit initializes the state from the first two periods and smooths
+// through y.
+func (hw *holtWinters) fit(y []float64) {
+	m := hw.period
+	first, second := mean(y[:m]), mean(y[m:2*m])
+	hw.level = first
+	hw.trend = (second - first) / float64(m)
+	hw.season = make([]float64, m)
+	for i := 0; i < m; i++ {
+		hw.season[i] = y[i] - first
+	}
+
+	hw.sse = 0
+	for t := m; t < len(y); t++ {
+		seasonal := hw.season[t%m]
+		err := y[t] - (hw.level + hw.trend + seasonal)
+		hw.sse += err * err
+
+		level := hw.alpha*(y[t]-seasonal) + (1-hw.alpha)*(hw.level+hw.trend)
+		hw.trend = hw.beta*(level-hw.level) + (1-hw.beta)*hw.trend
+		hw.season[t%m] = hw.gamma*(y[t]-level) + (1-hw.gamma)*seasonal
+		hw.level = level
+	}
+}
+
+// ForecastARIMA forecasts the cost of the next tau slots with an
+// ARIMA(p, d, 0) model: the series is differenced d times, an AR(p) with
+// intercept is fitted by least squares, and forecasts are integrated back.
+// The interval propagates the residual variance through the model's ψ
+// weights.
+//
+// The series is as in ForecastHoltWinters.
+func (s *Statistics) ForecastARIMA(tau uint64, p, d int, confidence float64) (CostForecast, error) {
+	if p < 0 || d < 0 || d > 2 {
+		return CostForecast{}, fmt.Errorf("ARIMA order must have p >= 0 and d in [0, 2], got (%d, %d, 0)", p, d)
+	}
+	if err := validateForecast(tau, confidence); err != nil {
+		return CostForecast{}, err
+	}
+
+	// Difference d times, keeping each level's last value to integrate
+	z := s.valueSeries()
+	lasts := make([]float64, d)
+	for k := 0; k < d && len(z) > 0; k++ {
+		lasts[k] = z[len(z)-1]
+		diff := make([]float64, len(z)-1)
+		for i := range diff {
+			diff[i] = z[i+1] - z[i]
+		}
+		z = diff
+	}
+	if len(z) < 2*(p+1) {
+		return CostForecast{}, fmt.Errorf("%w: ARIMA(%d,%d,0) needs %d slots, have %d", model.ErrInsufficientData, p, d, 2*(p+1)+d, len(z)+d)
+	}
+
+	coef, sigma, err := fitAR(z, p)
+	if err != nil {
+		return CostForecast{}, err
+	}
+
+	// Forecast the differenced series recursively
+	history := append([]float64(nil), z[len(z)-p:]...)
+	ahead := make([]float64, tau)
+	for h := range ahead {
+		next := coef[0]
+		for i := 1; i <= p; i++ {
+			next += coef[i] * history[len(history)-i]
+		}
+		ahead[h] = next
+		if p > 0 {
+			history = append(history[1:], next)
+		}
+	}
+
+	// ψ weights of the differenced series: ψ_0 = 1, ψ_j = Σ φ_i ψ_{j-i}
+	weights := make([]float64, tau)
+	for j := range weights {
+		if j == 0 {
+			weights[j] = 1
+			continue
+		}
+		for i := 1; i <= min(j, p); i++ {
+			weights[j] += coef[i] * weights[j-i]
+		}
+	}
+
+	// Integrating forecasts and weights are both cumulative sums
+	for k := d - 1; k >= 0; k-- {
+		level := lasts[k]
+		for h := range ahead {
+			level += ahead[h]
+			ahead[h] = level
+		}
+		for j := 1; j < len(weights); j++ {
+			weights[j] += weights[j-1]
+		}
+	}
+
+	var cost float64
+	for _, v := range ahead {
+		cost += v
+	}
+	forecast := newCostForecast(fmt.Sprintf("%s(%d,%d,0)", ForecastARIMA, p, d), tau, cost, sigma, weights, confidence)
+	forecast.Params = map[string]float64{"intercept": coef[0], "p": float64(p), "d": float64(d)}
+	for i := 1; i <= p; i++ {
+		forecast.Params[fmt.Sprintf("phi%d", i)] = coef[i]
+	}
+	return forecast, nil
+}
+
+// fitAR fits z_t = c + Σ φ_i z_{t-i} by least squares, returning
+// [c, φ_1, ..., φ_p] and the residual standard deviation.
+func fitAR(z []float64, p int) ([]float64, float64, error) {
+	// Normal equations X'X b = X'y over rows (1, z_{t-1}, ..., z_{t-p})
+	n := p + 1
+	xtx := make([][]float64, n)
+	for i := range xtx {
+		xtx[i] = make([]float64, n+1) // Augmented with X'y
+	}
+	row := make([]float64, n)
+	for t := p; t < len(z); t++ {
+		row[0] = 1
+		for i := 1; i <= p; i++ {
+			row[i] = z[t-i]
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				xtx[i][j] += row[i] * row[j]
+			}
+			xtx[i][n] += row[i] * z[t]
+		}
+	}
+	coef, err := solveLinear(xtx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fit AR(%d): %w", p, err)
+	}
+
+	var sse float64
+	for t := p; t < len(z); t++ {
+		pred := coef[0]
+		for i := 1; i <= p; i++ {
+			pred += coef[i] * z[t-i]
+		}
+		sse += (z[t] - pred) * (z[t] - pred)
+	}
+	return coef, math.Sqrt(sse / float64(len(z)-p-n)), nil
+}
+
+// solveLinear solves an augmented n×(n+1) system by Gaussian elimination
+// with partial pivoting, overwriting it.
+func solveLinear(a [][]float64) ([]float64, error) {
+	n := len(a)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, fmt.Errorf("singular system (constant or collinear series)")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := col + 1; r < n; r++ {
+			f := a[r][col] / a[col][col]
+			for c := col; c <= n; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for r := n - 1; r >= 0; r-- {
+		sum := a[r][n]
+		for c := r + 1; c < n; c++ {
+			sum -= a[r][c] * x[c]
+		}
+		x[r] = sum / a[r][r]
+	}
+	return x, nil
+}
+
+// newCostForecast builds a forecast whose summed error over the horizon is
+// Σ_i e_{T+i} Σ_{j<=τ-i} c_j for one-step errors e of deviation sigma and
+// error weights c.
+func newCostForecast(method string, tau uint64, cost, sigma float64, weights []float64, confidence float64) CostForecast {
+	var variance, cumulative float64
+	for _, c := range weights {
+		cumulative += c
+		variance += cumulative * cumulative
+	}
+	half := math.Sqrt2 * math.Erfinv(confidence) * sigma * math.Sqrt(variance)
+	return CostForecast{
+		Method:     method,
+		Tau:        tau,
+		CostETH:    cost,
+		LowerETH:   math.Max(0, cost-half),
+		UpperETH:   cost + half,
+		Confidence: confidence,
+		StdDevETH:  sigma,
+	}
+}
+
+// validateForecast checks the horizon and interval coverage.
+func validateForecast(tau uint64, confidence float64) error {
+	if tau < 1 {
+		return fmt.Errorf("tau must be at least 1")
+	}
+	if !(confidence > 0 && confidence < 1) {
+		return fmt.Errorf("confidence must be in (0, 1), got %v", confidence)
+	}
+	return nil
+}
+
+// valueSeries returns the bribe values in ETH, skipping bribes without one.
+func (s *Statistics) valueSeries() []float64 {
+	values := make([]float64, 0, len(s.bribes))
+	for _, bribe := range s.bribes {
+		if bribe.ValueWei != nil {
+			values = append(values, currency.WeiToETHFloat64(bribe.ValueWei))
+		}
+	}
+	return values
+}
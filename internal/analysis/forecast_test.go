@@ -0,0 +1,178 @@
+package analysis
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+// bribesFromETH builds consecutive slots from 1 with the given bids in
+// ETH, all won by one builder.
+func bribesFromETH(values []float64) []model.SlotBribe {
+	bribes := make([]model.SlotBribe, len(values))
+	for i, v := range values {
+		wei, _ := new(big.Float).Mul(big.NewFloat(v), big.NewFloat(1e18)).Int(nil)
+		bribes[i] = model.SlotBribe{Slot: uint64(i + 1), ValueWei: wei, BuilderPubkey: "0xA"}
+	}
+	return bribes
+}
+
+// repeatETH returns pattern repeated n times.
+func repeatETH(pattern []float64, n int) []float64 {
+	values := make([]float64, 0, len(pattern)*n)
+	for i := 0; i < n; i++ {
+		values = append(values, pattern...)
+	}
+	return values
+}
+
+// TestForecast_ExactSeries verifies series each model fits without error
+// are forecast exactly, with a zero-width interval.
+func TestForecast_ExactSeries(t *testing.T) {
+	trend := make([]float64, 50)
+	for i := range trend {
+		trend[i] = 1 + 0.01*float64(i)
+	}
+	constant := NewStatistics(bribesFromETH(repeatETH([]float64{0.5}, 40)))
+	seasonal := NewStatistics(bribesFromETH(repeatETH([]float64{0.1, 0.3, 0.5, 0.7}, 10)))
+
+	tests := []struct {
+		name     string
+		forecast func() (CostForecast, error)
+		cost     float64
+	}{
+		{"Holt-Winters constant", func() (CostForecast, error) { return constant.ForecastHoltWinters(6, 4, 0.9) }, 3},
+		{"ARIMA(0,0,0) constant", func() (CostForecast, error) { return constant.ForecastARIMA(6, 0, 0, 0.9) }, 3},
+		// The next season starts over at 0.1
+		{"Holt-Winters seasonal", func() (CostForecast, error) { return seasonal.ForecastHoltWinters(2, 4, 0.9) }, 0.4},
+		{"Holt-Winters seasonal full period", func() (CostForecast, error) { return seasonal.ForecastHoltWinters(4, 4, 0.9) }, 1.6},
+		// Differencing leaves a constant 0.01 step after the last value 1.49
+		{"ARIMA(0,1,0) trend", func() (CostForecast, error) {
+			return NewStatistics(bribesFromETH(trend)).ForecastARIMA(3, 0, 1, 0.9)
+		}, 1.50 + 1.51 + 1.52},
+	}
+
+	for _, tt := range tests {
+		forecast, err := tt.forecast()
+		if err != nil {
+			t.Fatalf("%s: forecast failed: %v", tt.name, err)
+		}
+		if math.Abs(forecast.CostETH-tt.cost) > 1e-9 {
+			t.Errorf("%s: expected cost %v, got %v", tt.name, tt.cost, forecast.CostETH)
+		}
+		if forecast.StdDevETH > 1e-9 || forecast.UpperETH-forecast.LowerETH > 1e-8 {
+			t.Errorf("%s: expected a zero-width interval, got [%v, %v] with deviation %v",
+				tt.name, forecast.LowerETH, forecast.UpperETH, forecast.StdDevETH)
+		}
+	}
+}
+
+// TestForecastARIMA_RecoversAR1 verifies an AR(1) process's coefficient,
+// intercept and noise are recovered, and the forecast reverts toward the
+// process mean.
+func TestForecastARIMA_RecoversAR1(t *testing.T) {
+	const phi, c, noise = 0.6, 0.2, 0.05
+	rng := rand.New(rand.NewSource(1))
+	values := make([]float64, 5000)
+	values[0] = c / (1 - phi)
+	for i := 1; i < len(values); i++ {
+		values[i] = c + phi*values[i-1] + noise*rng.NormFloat64()
+	}
+
+	coef, sigma, err := fitAR(values, 1)
+	if err != nil {
+		t.Fatalf("fitAR failed: %v", err)
+	}
+	if math.Abs(coef[1]-phi) > 0.03 || math.Abs(coef[0]-c) > 0.02 {
+		t.Errorf("expected φ %v and c %v, got %v and %v", phi, c, coef[1], coef[0])
+	}
+	if math.Abs(sigma-noise) > 0.005 {
+		t.Errorf("expected residual deviation %v, got %v", noise, sigma)
+	}
+
+	forecast, err := NewStatistics(bribesFromETH(values)).ForecastARIMA(1000, 1, 0, 0.9)
+	if err != nil {
+		t.Fatalf("ForecastARIMA failed: %v", err)
+	}
+	if math.Abs(forecast.Params["phi1"]-coef[1]) > 1e-9 {
+		t.Errorf("expected phi1 %v, got %v", coef[1], forecast.Params["phi1"])
+	}
+	// Over a long horizon the mean slot converges to c / (1 - φ) = 0.5
+	if mean := forecast.CostETH / 1000; math.Abs(mean-0.5) > 0.01 {
+		t.Errorf("expected a mean forecast slot near 0.5, got %v", mean)
+	}
+	if !(forecast.LowerETH < forecast.CostETH && forecast.CostETH < forecast.UpperETH) {
+		t.Errorf("expected cost %v inside [%v, %v]", forecast.CostETH, forecast.LowerETH, forecast.UpperETH)
+	}
+}
+
+// TestSolveLinear verifies a system needing a pivot is solved and a
+// singular one is rejected.
+func TestSolveLinear(t *testing.T) {
+	tests := []struct {
+		name   string
+		system [][]float64
+		want   []float64
+	}{
+		{"diagonal", [][]float64{{2, 0, 4}, {0, 4, 2}}, []float64{2, 0.5}},
+		{"pivot", [][]float64{{0, 1, 2}, {1, 0, 3}}, []float64{3, 2}},
+		{"dense", [][]float64{{1, 1, 1, 6}, {1, -1, 2, 5}, {2, 1, -1, 1}}, []float64{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		x, err := solveLinear(tt.system)
+		if err != nil {
+			t.Fatalf("%s: solveLinear failed: %v", tt.name, err)
+		}
+		for i := range tt.want {
+			if math.Abs(x[i]-tt.want[i]) > 1e-12 {
+				t.Errorf("%s: expected x[%d] = %v, got %v", tt.name, i, tt.want[i], x[i])
+			}
+		}
+	}
+
+	if _, err := solveLinear([][]float64{{1, 2, 3}, {2, 4, 6}}); err == nil {
+		t.Error("Expected error for singular system, got nil")
+	}
+}
+
+// TestForecast_Errors verifies invalid horizons, coverages and orders,
+// short series and singular fits are rejected.
+func TestForecast_Errors(t *testing.T) {
+	stats := NewStatistics(bribesFromETH(repeatETH([]float64{0.1, 0.3, 0.5, 0.7}, 10)))
+	constant := NewStatistics(bribesFromETH(repeatETH([]float64{0.5}, 40)))
+
+	tests := []struct {
+		name         string
+		forecast     func() (CostForecast, error)
+		insufficient bool
+	}{
+		{"Holt-Winters tau 0", func() (CostForecast, error) { return stats.ForecastHoltWinters(0, 4, 0.9) }, false},
+		{"ARIMA tau 0", func() (CostForecast, error) { return stats.ForecastARIMA(0, 1, 0, 0.9) }, false},
+		{"confidence 0", func() (CostForecast, error) { return stats.ForecastHoltWinters(4, 4, 0) }, false},
+		{"confidence 1", func() (CostForecast, error) { return stats.ForecastARIMA(4, 1, 0, 1) }, false},
+		{"confidence NaN", func() (CostForecast, error) { return stats.ForecastARIMA(4, 1, 0, math.NaN()) }, false},
+		{"period 1", func() (CostForecast, error) { return stats.ForecastHoltWinters(4, 1, 0.9) }, false},
+		{"negative p", func() (CostForecast, error) { return stats.ForecastARIMA(4, -1, 0, 0.9) }, false},
+		{"d 3", func() (CostForecast, error) { return stats.ForecastARIMA(4, 1, 3, 0.9) }, false},
+		{"Holt-Winters one period", func() (CostForecast, error) { return stats.ForecastHoltWinters(4, 21, 0.9) }, true},
+		{"ARIMA too few points", func() (CostForecast, error) { return stats.ForecastARIMA(4, 20, 0, 0.9) }, true},
+		{"no data", func() (CostForecast, error) { return NewStatistics(nil).ForecastARIMA(4, 0, 1, 0.9) }, true},
+		// A lag of a constant series is collinear with the intercept
+		{"singular", func() (CostForecast, error) { return constant.ForecastARIMA(4, 1, 0, 0.9) }, false},
+	}
+
+	for _, tt := range tests {
+		_, err := tt.forecast()
+		if err == nil {
+			t.Errorf("%s: Expected error, got nil", tt.name)
+			continue
+		}
+		if errors.Is(err, model.ErrInsufficientData) != tt.insufficient {
+			t.Errorf("%s: expected ErrInsufficientData %v, got %v", tt.name, tt.insufficient, err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// BinScale spaces the bins of a Histogram.
+type BinScale string
+
+const (
+	// BinsLinear gives every bin the same width in ETH.
+	BinsLinear BinScale = "linear"
+	// BinsLog gives every bin the same width in log ETH, so bids spanning
+	// orders of magnitude stay readable. Zero bids fall below every bin.
+	BinsLog BinScale = "log"
+)
+
+// ParseBinScale returns the BinScale named s.
+func ParseBinScale(s string) (BinScale, error) {
+	switch scale := BinScale(s); scale {
+	case BinsLinear, BinsLog:
+		return scale, nil
+	default:
+		return "", fmt.Errorf("unknown bin scale '%s' (want %s or %s)", s, BinsLinear, BinsLog)
+	}
+}
+
+// HistogramConfig tunes ComputeHistogram. Zero fields select defaults: 50
+// linear bins spanning the bids.
+type HistogramConfig struct {
+	Bins   int
+	Scale  BinScale
+	MinETH *float64 // Lower edge of the first bin; nil starts at the smallest bid (smallest positive bid for log bins)
+	MaxETH *float64 // Upper edge of the last bin; nil ends at the largest bid
+}
+
+// DefaultHistogramBins is the number of bins used when none is given.
+const DefaultHistogramBins = 50
+
+// Histogram counts bids in consecutive bins.
+type Histogram struct {
+	Scale     BinScale
+	Edges     []float64 // Bin i spans [Edges[i], Edges[i+1]); the last bin includes its upper edge
+	Counts    []int     // Bids in each bin
+	Underflow int       // Bids below Edges[0]
+	Overflow  int       // Bids above the last edge
+	Total     int       // All bids, binned or not
+}
+
+// ComputeHistogram counts bid values in ETH into bins. Bribes without a
+// value count as zero bids, as in ComputeSummary. A range without width,
+// such as bids that are all equal, is widened to [v, v+1] ETH for linear
+// bins and [v, 10v] for log bins; a fixed edge beyond every bid leaves the
+// range empty, which is an error.
+func (s *Statistics) ComputeHistogram(config HistogramConfig) (Histogram, error) {
+	if config.Bins == 0 {
+		config.Bins = DefaultHistogramBins
+	}
+	if config.Scale == "" {
+		config.Scale = BinsLinear
+	}
+	if config.Bins < 1 {
+		return Histogram{}, fmt.Errorf("bins must be at least 1, got %d", config.Bins)
+	}
+	if _, err := ParseBinScale(string(config.Scale)); err != nil {
+		return Histogram{}, err
+	}
+	for _, bound := range []*float64{config.MinETH, config.MaxETH} {
+		if bound != nil && !(*bound >= 0 && !math.IsInf(*bound, 1)) {
+			return Histogram{}, fmt.Errorf("histogram edges must be finite and not negative, got %g ETH", *bound)
+		}
+	}
+	if config.MinETH != nil && config.MaxETH != nil && *config.MaxETH <= *config.MinETH {
+		return Histogram{}, fmt.Errorf("invalid histogram range [%g, %g] ETH", *config.MinETH, *config.MaxETH)
+	}
+
+	values := make([]float64, len(s.bribes))
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i, bribe := range s.bribes {
+		if bribe.ValueWei != nil {
+			values[i] = currency.WeiToETHFloat64(bribe.ValueWei)
+		}
+		if config.Scale == BinsLog && values[i] <= 0 {
+			continue
+		}
+		lo = math.Min(lo, values[i])
+		hi = math.Max(hi, values[i])
+	}
+	if math.IsInf(lo, 1) && (config.MinETH == nil || config.MaxETH == nil) {
+		return Histogram{}, fmt.Errorf("%w: no bids to bin on a %s scale", model.ErrInsufficientData, config.Scale)
+	}
+	if config.MinETH != nil {
+		lo = *config.MinETH
+	}
+	if config.MaxETH != nil {
+		hi = *config.MaxETH
+	}
+	if hi < lo {
+		return Histogram{}, fmt.Errorf("histogram range [%g, %g] ETH is empty", lo, hi)
+	}
+
+	// Positions of values along the axis, so both scales bin linearly
+	pos := func(v float64) float64 { return v }
+	edge := func(x float64) float64 { return x }
+	if config.Scale == BinsLog {
+		if lo <= 0 {
+			return Histogram{}, fmt.Errorf("log bins need a positive lower edge, got %g", lo)
+		}
+		if hi <= lo {
+			hi = 10 * lo
+		}
+		// Base 10 keeps decade edges such as 0.01 exact
+		pos, edge = math.Log10, func(x float64) float64 { return math.Pow(10, x) }
+	} else if hi <= lo {
+		hi = lo + 1
+	}
+
+	h := Histogram{
+		Scale:  config.Scale,
+		Edges:  make([]float64, config.Bins+1),
+		Counts: make([]int, config.Bins),
+		Total:  len(values),
+	}
+	start, width := pos(lo), (pos(hi)-pos(lo))/float64(config.Bins)
+	for i := range h.Edges {
+		h.Edges[i] = edge(start + float64(i)*width)
+	}
+	h.Edges[0], h.Edges[config.Bins] = lo, hi // Exact despite rounding
+
+	for _, v := range values {
+		switch {
+		case v < lo:
+			h.Underflow++
+		case v > hi:
+			h.Overflow++
+		default:
+			i := int((pos(v) - start) / width)
+			// Rounding can put a value a bin off its edges
+			i = max(0, min(i, config.Bins-1))
+			if v < h.Edges[i] && i > 0 {
+				i--
+			} else if i < config.Bins-1 && v >= h.Edges[i+1] {
+				i++
+			}
+			h.Counts[i]++
+		}
+	}
+	return h, nil
+}
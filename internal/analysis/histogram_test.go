@@ -0,0 +1,148 @@
+package analysis
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+// eth returns a pointer to v, for the optional edges of a HistogramConfig.
+func eth(v float64) *float64 {
+	return &v
+}
+
+// TestComputeHistogram verifies edges on both scales, which bin values on
+// an edge land in, and bids counted outside the bins.
+func TestComputeHistogram(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []float64
+		config    HistogramConfig
+		edges     []float64
+		counts    []int
+		underflow int
+		overflow  int
+	}{
+		{
+			// A value on an edge opens the next bin, but the last bin
+			// includes its upper edge
+			name:   "linear from 0",
+			values: []float64{0, 1, 2, 3.5, 4, 10},
+			config: HistogramConfig{Bins: 5, MinETH: eth(0), MaxETH: eth(10)},
+			edges:  []float64{0, 2, 4, 6, 8, 10},
+			counts: []int{2, 2, 1, 0, 1},
+		},
+		{
+			name:   "linear spanning the bids",
+			values: []float64{1, 2, 3, 4, 5},
+			config: HistogramConfig{Bins: 4},
+			edges:  []float64{1, 2, 3, 4, 5},
+			counts: []int{1, 1, 1, 2},
+		},
+		{
+			name:   "log decades",
+			values: []float64{0.001, 0.005, 0.01, 0.1, 0.5, 10},
+			config: HistogramConfig{Bins: 4, Scale: BinsLog, MinETH: eth(0.001), MaxETH: eth(10)},
+			edges:  []float64{0.001, 0.01, 0.1, 1, 10},
+			counts: []int{2, 1, 2, 1},
+		},
+		{
+			name:      "underflow and overflow",
+			values:    []float64{0.5, 1, 1.5, 2, 3, 4},
+			config:    HistogramConfig{Bins: 2, MinETH: eth(1), MaxETH: eth(2)},
+			edges:     []float64{1, 1.5, 2},
+			counts:    []int{1, 2},
+			underflow: 1,
+			overflow:  2,
+		},
+		{
+			// Zero bids have no log and start no bin
+			name:      "zero bids on a log scale",
+			values:    []float64{0, 0, 0.1, 1},
+			config:    HistogramConfig{Bins: 1, Scale: BinsLog},
+			edges:     []float64{0.1, 1},
+			counts:    []int{2},
+			underflow: 2,
+		},
+		{
+			name:   "equal bids widened on a linear scale",
+			values: []float64{0.5, 0.5, 0.5},
+			config: HistogramConfig{Bins: 2},
+			edges:  []float64{0.5, 1, 1.5},
+			counts: []int{3, 0},
+		},
+		{
+			name:   "equal bids widened on a log scale",
+			values: []float64{0.01, 0.01},
+			config: HistogramConfig{Bins: 1, Scale: BinsLog},
+			edges:  []float64{0.01, 0.1},
+			counts: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		h, err := NewStatistics(bribesFromETH(tt.values)).ComputeHistogram(tt.config)
+		if err != nil {
+			t.Fatalf("%s: ComputeHistogram failed: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(h.Edges, tt.edges) {
+			t.Errorf("%s: expected edges %v, got %v", tt.name, tt.edges, h.Edges)
+		}
+		if !reflect.DeepEqual(h.Counts, tt.counts) || h.Underflow != tt.underflow || h.Overflow != tt.overflow {
+			t.Errorf("%s: expected counts %v, underflow %d and overflow %d, got %v, %d and %d",
+				tt.name, tt.counts, tt.underflow, tt.overflow, h.Counts, h.Underflow, h.Overflow)
+		}
+		if h.Total != len(tt.values) {
+			t.Errorf("%s: expected total %d, got %d", tt.name, len(tt.values), h.Total)
+		}
+	}
+
+	h, err := NewStatistics(bribesFromETH([]float64{0.2, 0.7})).ComputeHistogram(HistogramConfig{})
+	if err != nil {
+		t.Fatalf("ComputeHistogram failed: %v", err)
+	}
+	if h.Scale != BinsLinear || len(h.Counts) != DefaultHistogramBins {
+		t.Errorf("expected %d linear bins by default, got %d %s bins", DefaultHistogramBins, len(h.Counts), h.Scale)
+	}
+}
+
+// TestComputeHistogram_Errors verifies invalid configurations and empty
+// ranges are rejected.
+func TestComputeHistogram_Errors(t *testing.T) {
+	bribes := bribesFromETH([]float64{1, 2, 3})
+
+	tests := []struct {
+		name   string
+		config HistogramConfig
+	}{
+		{"negative bins", HistogramConfig{Bins: -1}},
+		{"unknown scale", HistogramConfig{Scale: "sqrt"}},
+		{"negative edge", HistogramConfig{MinETH: eth(-1)}},
+		{"NaN edge", HistogramConfig{MaxETH: eth(math.NaN())}},
+		{"infinite edge", HistogramConfig{MaxETH: eth(math.Inf(1))}},
+		{"inverted range", HistogramConfig{MinETH: eth(2), MaxETH: eth(2)}},
+		{"minimum above every bid", HistogramConfig{MinETH: eth(5)}},
+		{"maximum below every bid", HistogramConfig{MaxETH: eth(0.5)}},
+		{"log scale from 0", HistogramConfig{Scale: BinsLog, MinETH: eth(0)}},
+	}
+	for _, tt := range tests {
+		if _, err := NewStatistics(bribes).ComputeHistogram(tt.config); err == nil {
+			t.Errorf("%s: Expected error, got nil", tt.name)
+		}
+	}
+
+	zeros := NewStatistics(bribesFromETH([]float64{0, 0}))
+	if _, err := zeros.ComputeHistogram(HistogramConfig{Scale: BinsLog}); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData for zero bids on a log scale, got %v", err)
+	}
+	if _, err := NewStatistics(nil).ComputeHistogram(HistogramConfig{}); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData without bids, got %v", err)
+	}
+	// With both edges fixed, no bids give empty bins
+	if h, err := NewStatistics(nil).ComputeHistogram(HistogramConfig{Bins: 2, MinETH: eth(0), MaxETH: eth(1)}); err != nil || h.Total != 0 {
+		t.Errorf("expected an empty histogram, got %+v and %v", h, err)
+	}
+}
@@ -1,9 +1,15 @@
 package analysis
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"math/rand"
+	"sort"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
 )
 
 // MonteCarloResult contains simulation results.
@@ -11,109 +17,426 @@ type MonteCarloResult struct {
 	ExpectedProfit        float64
 	ProfitStdDev          float64
 	ProbabilityProfitable float64
-	ValueAtRisk95         float64
 	MedianProfit          float64
 	MaxProfit             float64
 	MaxLoss               float64
+	ProfitSkewness        float64           // Third standardized moment; NaN when every run profits the same
+	ProfitKurtosis        float64           // Excess kurtosis (0 for a normal); NaN when every run profits the same
+	TailRisk              []TailRisk        // One entry per requested confidence level
+	LossProbabilities     []LossProbability // One entry per requested loss threshold
+	Seed                  int64             // Seed the run drew from; rerunning with it reproduces the result
+
+	// Censorship cost across runs; constant unless costs are resampled
+	MeanCostETH float64
+	CostP5ETH   float64
+	CostP95ETH  float64
+
+	// Price shock effect; without a shock these equal ExpectedProfit and 0
+	UnshockedExpectedProfit float64 // Expected profit of the same runs at the pre-attack price
+	MeanPriceDrop           float64 // Mean drawn ETH price drop
+}
+
+// PriceShock is the distribution of ETH's fractional price drop caused by
+// a successful bridge attack. A zero mean and standard deviation is no
+// shock.
+//
+// Each run draws a drop d from a normal distribution with the given mean
+// and standard deviation, clipped to [0, MaxPriceDrop]. The drop lowers
+// the USD value of the ETHExposure share of the stolen TVL on success,
+// and raises the ETH bribes builders demand by a factor
+// 1 + BribeResponse·d/(1-d) whether or not the attack succeeds, since they
+// price the anticipated crash in; BribeResponse = 1 keeps their bribes'
+// USD value at the post-attack price.
+type PriceShock struct {
+	Mean          float64 // Mean fractional drop, e.g. 0.2 for -20%
+	StdDev        float64 // Standard deviation of the drop (0 for a fixed drop)
+	ETHExposure   float64 // Share of the stolen TVL denominated in ETH, in [0, 1]
+	BribeResponse float64 // How fully builders pass the drop into bribes (0 to ignore)
+}
+
+// MaxPriceDrop caps drawn price drops below a total collapse.
+const MaxPriceDrop = 0.99
+
+// validate checks the shock's parameters.
+func (s PriceShock) validate() error {
+	if s.Mean < 0 || s.Mean > MaxPriceDrop {
+		return fmt.Errorf("price shock mean must be in [0, %v], got %v", MaxPriceDrop, s.Mean)
+	}
+	if s.StdDev < 0 {
+		return fmt.Errorf("price shock standard deviation must not be negative, got %v", s.StdDev)
+	}
+	if s.ETHExposure < 0 || s.ETHExposure > 1 {
+		return fmt.Errorf("ETH exposure must be in [0, 1], got %v", s.ETHExposure)
+	}
+	if s.BribeResponse < 0 {
+		return fmt.Errorf("bribe response must not be negative, got %v", s.BribeResponse)
+	}
+	return nil
+}
+
+// draw samples a price drop.
+func (s PriceShock) draw(rng *rand.Rand) float64 {
+	return math.Min(math.Max(s.Mean+s.StdDev*rng.NormFloat64(), 0), MaxPriceDrop)
+}
+
+// TailRisk describes the loss tail of the simulated profit at one
+// confidence level. Both figures are profits in USD, so losses are
+// negative.
+type TailRisk struct {
+	Confidence float64 // e.g. 0.95
+	VaR        float64 // Profit at the (1 - Confidence) quantile
+	CVaR       float64 // Expected shortfall: mean profit over the worst 1 - Confidence of runs
+}
+
+// LossProbability is the share of runs losing more than ThresholdUSD,
+// i.e. with a profit below -ThresholdUSD.
+type LossProbability struct {
+	ThresholdUSD float64
+	Probability  float64
+}
+
+// DefaultConfidenceLevels are the tail risk levels reported when none are
+// requested.
+var DefaultConfidenceLevels = []float64{0.95, 0.99}
+
+// RiskConfig selects the risk metrics of a MonteCarloResult beyond its
+// moments.
+type RiskConfig struct {
+	ConfidenceLevels []float64 // VaR and CVaR levels in (0, 1); empty selects DefaultConfidenceLevels
+	LossThresholds   []float64 // Non-negative losses in USD to report the probability of exceeding
+}
+
+// validate checks the levels and thresholds and fills in default levels.
+func (r *RiskConfig) validate() error {
+	if len(r.ConfidenceLevels) == 0 {
+		r.ConfidenceLevels = DefaultConfidenceLevels
+	}
+	for _, c := range r.ConfidenceLevels {
+		if !(c > 0 && c < 1) {
+			return fmt.Errorf("confidence level must be in (0, 1), got %v", c)
+		}
+	}
+	for _, x := range r.LossThresholds {
+		if !(x >= 0) || math.IsInf(x, 1) {
+			return fmt.Errorf("loss threshold must be a non-negative amount, got %v", x)
+		}
+	}
+	return nil
 }
 
 // SimulateAttackOutcomes runs Monte Carlo simulation of attack profitability.
+//
+// Outcomes are drawn from a generator seeded with seed, so the same inputs
+// and seed always give the same result. A non-zero shock values each run
+// under the ETH price drop a successful attack causes (see PriceShock).
+// VaR and CVaR are reported at each of risk's confidence levels, and the
+// probability of losing more than each of its loss thresholds.
 func SimulateAttackOutcomes(
 	censorshipCostETH float64,
 	bridgeTVLUSD float64,
 	ethPriceUSD float64,
 	successProbability float64,
+	shock PriceShock,
 	numSimulations int,
-) MonteCarloResult {
+	seed int64,
+	risk RiskConfig,
+) (MonteCarloResult, error) {
+	return simulateAttackOutcomes(func(*rand.Rand) float64 { return censorshipCostETH },
+		bridgeTVLUSD, ethPriceUSD, successProbability, shock, numSimulations, seed, risk)
+}
 
-	censorshipCostUSD := censorshipCostETH * ethPriceUSD
+// SimulateAttackOutcomesResampled is SimulateAttackOutcomes with the
+// censorship cost of each run drawn from history rather than fixed, so the
+// profit distribution reflects cost uncertainty too.
+//
+// Each run's τ-slot cost is a moving block bootstrap: ⌈τ/blockLen⌉ runs of
+// blockLen consecutive bribes, each starting at a random position, are
+// concatenated and cut to τ slots. Blocks keep the short-range correlation
+// of bids; blockLen = τ (or 0) draws whole historical windows. bribes must
+// be sorted by strictly increasing slot and hold at least blockLen entries.
+func SimulateAttackOutcomesResampled(
+	bribes []model.SlotBribe,
+	tau uint64,
+	blockLen int,
+	bridgeTVLUSD float64,
+	ethPriceUSD float64,
+	successProbability float64,
+	shock PriceShock,
+	numSimulations int,
+	seed int64,
+	risk RiskConfig,
+) (MonteCarloResult, error) {
+	if tau < 1 {
+		return MonteCarloResult{}, fmt.Errorf("tau must be at least 1")
+	}
+	if blockLen < 0 {
+		return MonteCarloResult{}, fmt.Errorf("blockLen must not be negative, got %d", blockLen)
+	}
+	if blockLen == 0 || uint64(blockLen) > tau {
+		blockLen = int(min(tau, uint64(len(bribes))))
+	}
+	if blockLen < 1 || len(bribes) < blockLen {
+		return MonteCarloResult{}, fmt.Errorf("%w: need %d slots per block, have %d", model.ErrInsufficientData, blockLen, len(bribes))
+	}
+	idx, err := model.NewCostIndex(bribes)
+	if err != nil {
+		return MonteCarloResult{}, fmt.Errorf("failed to index bribes: %w", err)
+	}
+
+	starts := len(bribes) - blockLen + 1
+	sampleCost := func(rng *rand.Rand) float64 {
+		cost := new(big.Int)
+		for remaining := tau; remaining > 0; {
+			n := min(remaining, uint64(blockLen))
+			// Blocks lie within the data, so Cost cannot fail
+			block, _ := idx.Cost(rng.Intn(starts), n)
+			cost.Add(cost, block)
+			remaining -= n
+		}
+		return currency.WeiToETHFloat64(cost)
+	}
+	return simulateAttackOutcomes(sampleCost, bridgeTVLUSD, ethPriceUSD, successProbability, shock, numSimulations, seed, risk)
+}
+
+// simulateAttackOutcomes draws each run's cost in ETH from costETH, then
+// its success, then its price drop.
+func simulateAttackOutcomes(
+	costETH func(*rand.Rand) float64,
+	bridgeTVLUSD float64,
+	ethPriceUSD float64,
+	successProbability float64,
+	shock PriceShock,
+	numSimulations int,
+	seed int64,
+	risk RiskConfig,
+) (MonteCarloResult, error) {
+	if numSimulations < 1 {
+		return MonteCarloResult{}, fmt.Errorf("numSimulations must be at least 1, got %d", numSimulations)
+	}
+	if err := risk.validate(); err != nil {
+		return MonteCarloResult{}, err
+	}
+	if err := shock.validate(); err != nil {
+		return MonteCarloResult{}, err
+	}
+	shocked := shock.Mean > 0 || shock.StdDev > 0
+
+	rng := rand.New(rand.NewSource(seed))
 
 	profits := make([]float64, numSimulations)
+	costs := make([]float64, numSimulations)
 	profitableCount := 0
+	var unshockedSum, dropSum float64
 
 	for i := 0; i < numSimulations; i++ {
+		costs[i] = costETH(rng)
+		censorshipCostUSD := costs[i] * ethPriceUSD
+
 		// Simulate success (1) or failure (0)
 		success := 0.0
-		if rand.Float64() < successProbability {
+		if rng.Float64() < successProbability {
 			success = 1.0
-			profitableCount++
 		}
 
 		// Profit = success * TVL - cost
 		profit := success*bridgeTVLUSD - censorshipCostUSD
+		unshockedSum += profit
+
+		// Drawn only with a shock, so unshocked runs keep their seeds' results
+		if shocked {
+			drop := shock.draw(rng)
+			dropSum += drop
+			loot := bridgeTVLUSD * (1 - shock.ETHExposure*drop)
+			bribes := censorshipCostUSD * (1 + shock.BribeResponse*drop/(1-drop))
+			profit = success*loot - bribes
+		}
+		if profit > 0 {
+			profitableCount++
+		}
 		profits[i] = profit
 	}
 
 	// Compute statistics
+	meanCost := mean(costs)
 	mean := mean(profits)
 	stdDev := stdDev(profits, mean)
+	skewness, kurtosis := standardizedMoments(profits, mean, stdDev)
 
 	// Sort for percentiles
 	sortedProfits := make([]float64, len(profits))
 	copy(sortedProfits, profits)
-	sortFloat64Slice(sortedProfits)
+	sort.Float64s(sortedProfits)
+	sort.Float64s(costs)
 
 	return MonteCarloResult{
 		ExpectedProfit:        mean,
 		ProfitStdDev:          stdDev,
 		ProbabilityProfitable: float64(profitableCount) / float64(numSimulations),
-		ValueAtRisk95:         percentile(sortedProfits, 5),
 		MedianProfit:          percentile(sortedProfits, 50),
 		MaxProfit:             sortedProfits[len(sortedProfits)-1],
 		MaxLoss:               sortedProfits[0],
+		ProfitSkewness:        skewness,
+		ProfitKurtosis:        kurtosis,
+		TailRisk:              tailRisk(sortedProfits, risk.ConfidenceLevels),
+		LossProbabilities:     lossProbabilities(sortedProfits, risk.LossThresholds),
+		Seed:                  seed,
+		MeanCostETH:           meanCost,
+		CostP5ETH:             percentile(costs, 5),
+		CostP95ETH:            percentile(costs, 95),
+
+		UnshockedExpectedProfit: unshockedSum / float64(numSimulations),
+		MeanPriceDrop:           dropSum / float64(numSimulations),
+	}, nil
+}
+
+// lossProbabilities returns the share of sortedProfits below -x for each
+// threshold x.
+func lossProbabilities(sortedProfits []float64, thresholds []float64) []LossProbability {
+	losses := make([]LossProbability, len(thresholds))
+	for i, x := range thresholds {
+		below := sort.SearchFloat64s(sortedProfits, -x) // Runs with profit < -x
+		losses[i] = LossProbability{
+			ThresholdUSD: x,
+			Probability:  float64(below) / float64(len(sortedProfits)),
+		}
+	}
+	return losses
+}
+
+// tailRisk computes VaR and CVaR of sortedProfits at each confidence level.
+func tailRisk(sortedProfits []float64, confidenceLevels []float64) []TailRisk {
+	tails := make([]TailRisk, len(confidenceLevels))
+	for i, c := range confidenceLevels {
+		// The worst ⌈(1-c)·n⌉ runs, at least one. 1 - c is inexact (1 - 0.95
+		// exceeds 0.05), so products landing just above an integer round down.
+		worst := max(1, int(math.Ceil((1-c)*float64(len(sortedProfits))-1e-9)))
+		tails[i] = TailRisk{
+			Confidence: c,
+			VaR:        percentile(sortedProfits, (1-c)*100),
+			CVaR:       mean(sortedProfits[:worst]),
+		}
+	}
+	return tails
+}
+
+// DurationDecay scales the success probability of a τ-slot attack, e.g.
+// because a longer exclusion is likelier to be noticed and worked around.
+// It returns a factor in [0, 1].
+type DurationDecay func(tau uint64) float64
+
+// ExponentialDecay returns the decay exp(-τ / scale).
+func ExponentialDecay(scale float64) DurationDecay {
+	return func(tau uint64) float64 {
+		return math.Exp(-float64(tau) / scale)
 	}
 }
 
-// OptimalAttackDuration finds the duration that maximizes expected profit.
+// DefaultDurationStep is the spacing of candidate durations when none is
+// given: 300 slots, about an hour.
+const DefaultDurationStep = 300
+
+// OptimalDurationConfig parameterizes FindOptimalAttackDuration.
+type OptimalDurationConfig struct {
+	BridgeTVLUSD float64
+	ETHPriceUSD  float64
+	TopK         int                    // Cartel size of α
+	Probability  model.ProbabilityModel // p(V) at the bridge TVL
+	Decay        DurationDecay          // Scales p by duration; nil for none
+	MaxTau       uint64                 // Longest candidate duration
+	Step         uint64                 // Candidates are Step, 2·Step, ... MaxTau; 0 selects DefaultDurationStep
+}
+
+// AttackDuration is the expected profit of censoring for Tau slots in the
+// cheapest window of that length.
+type AttackDuration struct {
+	Tau                uint64
+	StartSlot          uint64  // First slot of the cheapest window
+	CensorshipCostETH  float64 // C_c of the window
+	Alpha              float64 // Top-k share of the window's blocks
+	EffectiveCostETH   float64 // (1 - α) · C_c
+	SuccessProbability float64 // p(V), decayed over Tau
+	ExpectedProfit     float64 // p · V - C_c^eff, in USD
+}
+
+// OptimalAttackResult is the most profitable candidate duration, with
+// every candidate evaluated.
 type OptimalAttackResult struct {
-	OptimalDurationSlots int
-	ExpectedProfit       float64
-	CensorshipCostETH    float64
-	SuccessProbability   float64
+	AttackDuration
+	Candidates []AttackDuration // Ascending Tau
 }
 
-// FindOptimalAttackDuration calculates optimal attack duration.
-func FindOptimalAttackDuration(
-	avgBribeETH float64,
-	bridgeTVLUSD float64,
-	ethPriceUSD float64,
-	successProbBase float64,
-	maxDurationSlots int,
-	decayConstant float64,
-) OptimalAttackResult {
-
-	bestDuration := 0
-	bestProfit := math.Inf(-1)
-	bestCost := 0.0
-	bestSuccessProb := 0.0
-
-	// Check every 300 slots (~1 hour)
-	for tau := 300; tau <= maxDurationSlots; tau += 300 {
-		// Cost = average bribe * duration
-		cost := avgBribeETH * float64(tau)
-		costUSD := cost * ethPriceUSD
-
-		// Success probability decays with duration
-		// p(tau) = p_base * exp(-tau / decayConstant)
-		successProb := successProbBase * math.Exp(-float64(tau)/decayConstant)
-
-		// Expected profit = success_prob * TVL - cost
-		expectedProfit := successProb*bridgeTVLUSD - costUSD
-
-		if expectedProfit > bestProfit {
-			bestProfit = expectedProfit
-			bestDuration = tau
-			bestCost = cost
-			bestSuccessProb = successProb
+// FindOptimalAttackDuration finds the censorship duration that maximizes
+// the attacker's expected profit p(τ) · V - C_c^eff(τ) over observed bids.
+//
+// For each candidate τ the attacker strikes in the cheapest gap-free
+// window of τ slots (model.FindCheapestWindow), and α is measured over that
+// window's builders (model.AlphaScope{Window: true}) rather than the whole
+// dataset. The window is chosen by raw cost, so a slightly dearer window
+// with a more concentrated cartel could be cheaper after the discount.
+// Candidates stop at the first τ with no gap-free window; bribes must be
+// sorted by slot without duplicates.
+func FindOptimalAttackDuration(bribes []model.SlotBribe, config OptimalDurationConfig) (OptimalAttackResult, error) {
+	if config.Step == 0 {
+		config.Step = DefaultDurationStep
+	}
+	if config.Probability == nil {
+		return OptimalAttackResult{}, fmt.Errorf("success probability model is required")
+	}
+	if config.MaxTau < config.Step {
+		return OptimalAttackResult{}, fmt.Errorf("max duration %d is shorter than the step %d", config.MaxTau, config.Step)
+	}
+	if config.ETHPriceUSD <= 0 {
+		return OptimalAttackResult{}, fmt.Errorf("ETH price must be positive, got %v", config.ETHPriceUSD)
+	}
+	p := config.Probability.Probability(currency.USDToWei(config.BridgeTVLUSD, config.ETHPriceUSD))
+	if math.IsNaN(p) || p < 0 || p > 1 {
+		return OptimalAttackResult{}, fmt.Errorf("%w: success probability %f (must be in [0,1])", model.ErrInvalidProbability, p)
+	}
+
+	var result OptimalAttackResult
+	for tau := config.Step; tau <= config.MaxTau; tau += config.Step {
+		extremes, err := model.FindCheapestWindow(bribes, tau)
+		if errors.Is(err, model.ErrInsufficientData) {
+			break // No longer window fits either
+		}
+		if err != nil {
+			return OptimalAttackResult{}, fmt.Errorf("failed to find cheapest %d-slot window: %w", tau, err)
+		}
+		start := sort.Search(len(bribes), func(i int) bool { return bribes[i].Slot >= extremes.Cheapest.StartSlot })
+		effective, used, err := model.EffectiveCensorshipCostScoped(bribes[start:start+int(tau)], tau, config.TopK,
+			model.ConcentrationByBlocks, model.AlphaScope{Window: true})
+		if err != nil {
+			return OptimalAttackResult{}, fmt.Errorf("failed to compute effective cost at tau %d: %w", tau, err)
+		}
+
+		successProb := p
+		if config.Decay != nil {
+			decay := config.Decay(tau)
+			if math.IsNaN(decay) || decay < 0 || decay > 1 {
+				return OptimalAttackResult{}, fmt.Errorf("decay at tau %d is %f (must be in [0,1])", tau, decay)
+			}
+			successProb *= decay
+		}
+		effectiveETH, _ := currency.FloatWeiToETH(effective).Float64()
+		candidate := AttackDuration{
+			Tau:                tau,
+			StartSlot:          extremes.Cheapest.StartSlot,
+			CensorshipCostETH:  currency.WeiToETHFloat64(extremes.Cheapest.CostWei),
+			Alpha:              used.Alpha,
+			EffectiveCostETH:   effectiveETH,
+			SuccessProbability: successProb,
+			ExpectedProfit:     successProb*config.BridgeTVLUSD - effectiveETH*config.ETHPriceUSD,
 		}
+		if len(result.Candidates) == 0 || candidate.ExpectedProfit > result.ExpectedProfit {
+			result.AttackDuration = candidate
+		}
+		result.Candidates = append(result.Candidates, candidate)
 	}
 
-	return OptimalAttackResult{
-		OptimalDurationSlots: bestDuration,
-		ExpectedProfit:       bestProfit,
-		CensorshipCostETH:    bestCost,
-		SuccessProbability:   bestSuccessProb,
+	if len(result.Candidates) == 0 {
+		return OptimalAttackResult{}, fmt.Errorf("%w: no gap-free window of %d slots", model.ErrInsufficientData, config.Step)
 	}
+	return result, nil
 }
 
 // ProfitabilityMatrix generates a 2D profitability landscape.
@@ -200,10 +523,29 @@ func PrintMonteCarloResult(result MonteCarloResult) {
 	fmt.Printf("Expected Profit:    $%.2f\n", result.ExpectedProfit)
 	fmt.Printf("Profit Std Dev:     $%.2f\n", result.ProfitStdDev)
 	fmt.Printf("Probability Profit: %.2f%%\n", result.ProbabilityProfitable*100)
-	fmt.Printf("95%% VaR:            $%.2f\n", result.ValueAtRisk95)
+	for _, tail := range result.TailRisk {
+		label := fmt.Sprintf("%g%% VaR:", tail.Confidence*100)
+		fmt.Printf("%-20s$%.2f (CVaR $%.2f)\n", label, tail.VaR, tail.CVaR)
+	}
+	for _, loss := range result.LossProbabilities {
+		label := fmt.Sprintf("P(loss > $%.0f):", loss.ThresholdUSD)
+		fmt.Printf("%-19s %.2f%%\n", label, loss.Probability*100)
+	}
+	if !math.IsNaN(result.ProfitSkewness) {
+		fmt.Printf("Profit Skewness:    %.3f\n", result.ProfitSkewness)
+		fmt.Printf("Excess Kurtosis:    %.3f\n", result.ProfitKurtosis)
+	}
 	fmt.Printf("Median Profit:      $%.2f\n", result.MedianProfit)
 	fmt.Printf("Max Profit:         $%.2f\n", result.MaxProfit)
 	fmt.Printf("Max Loss:           $%.2f\n", result.MaxLoss)
+	if result.CostP5ETH != result.CostP95ETH {
+		fmt.Printf("Cost (resampled):   mean=%.4f p5=%.4f p95=%.4f ETH\n", result.MeanCostETH, result.CostP5ETH, result.CostP95ETH)
+	}
+	if result.MeanPriceDrop > 0 {
+		fmt.Printf("Price shock:        mean drop %.1f%%, expected profit $%.2f without it\n",
+			result.MeanPriceDrop*100, result.UnshockedExpectedProfit)
+	}
+	fmt.Printf("Seed:               %d\n", result.Seed)
 }
 
 // Helper functions
@@ -219,6 +561,29 @@ func mean(values []float64) float64 {
 	return sum / float64(len(values))
 }
 
+// standardizedMoments returns the skewness and excess kurtosis of values
+// with the given mean and population standard deviation, or NaN for both
+// when the values are all equal and the moments undefined.
+func standardizedMoments(values []float64, mean, stdDev float64) (skewness, kurtosis float64) {
+	// Compare the values rather than stdDev with 0: rounding in the mean
+	// leaves equal values a tiny spread
+	constant := true
+	for _, v := range values {
+		constant = constant && v == values[0]
+	}
+	if constant || stdDev == 0 {
+		return math.NaN(), math.NaN()
+	}
+	var m3, m4 float64
+	for _, v := range values {
+		z := (v - mean) / stdDev
+		m3 += z * z * z
+		m4 += z * z * z * z
+	}
+	n := float64(len(values))
+	return m3 / n, m4/n - 3
+}
+
 func stdDev(values []float64, mean float64) float64 {
 	if len(values) == 0 {
 		return 0
@@ -230,15 +595,3 @@ func stdDev(values []float64, mean float64) float64 {
 	}
 	return math.Sqrt(variance / float64(len(values)))
 }
-
-func sortFloat64Slice(data []float64) {
-	// Simple bubble sort (fine for our use case)
-	n := len(data)
-	for i := 0; i < n-1; i++ {
-		for j := 0; j < n-i-1; j++ {
-			if data[j] > data[j+1] {
-				data[j], data[j+1] = data[j+1], data[j]
-			}
-		}
-	}
-}
@@ -0,0 +1,486 @@
+package analysis
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+// optimalDurationBribes has a cheapest 2-slot window split between two
+// builders (slots 1-2) and a cheapest 4-slot window built by one (5-8).
+// Over the whole range the top builder, 0xA, has α = 4/10.
+func optimalDurationBribes() []model.SlotBribe {
+	bribes := bribesFromETH([]float64{0.1, 0.1, 1, 1, 0.15, 0.15, 0.15, 0.15, 1, 1})
+	for i, builder := range []string{"0xX", "0xY", "0xZ", "0xW", "0xA", "0xA", "0xA", "0xA", "0xV", "0xU"} {
+		bribes[i].BuilderPubkey = builder
+	}
+	return bribes
+}
+
+// TestFindOptimalAttackDuration verifies each candidate strikes in the
+// cheapest window with α scoped to it, and that scoping moves the optimum
+// away from the one whole-range α gives.
+func TestFindOptimalAttackDuration(t *testing.T) {
+	bribes := optimalDurationBribes()
+	config := OptimalDurationConfig{
+		BridgeTVLUSD: 10000,
+		ETHPriceUSD:  1000,
+		TopK:         1,
+		Probability:  model.ConstantProbability(1),
+		Decay:        ExponentialDecay(1000),
+		MaxTau:       4,
+		Step:         2,
+	}
+	result, err := FindOptimalAttackDuration(bribes, config)
+	if err != nil {
+		t.Fatalf("FindOptimalAttackDuration failed: %v", err)
+	}
+
+	want := []AttackDuration{
+		// 0xX and 0xY split slots 1-2: C_c^eff = 0.5 · 0.2
+		{Tau: 2, StartSlot: 1, CensorshipCostETH: 0.2, Alpha: 0.5, EffectiveCostETH: 0.1, SuccessProbability: math.Exp(-0.002)},
+		// 0xA alone builds slots 5-8, which cost nothing to censor
+		{Tau: 4, StartSlot: 5, CensorshipCostETH: 0.6, Alpha: 1, EffectiveCostETH: 0, SuccessProbability: math.Exp(-0.004)},
+	}
+	if len(result.Candidates) != len(want) {
+		t.Fatalf("expected %d candidates, got %d", len(want), len(result.Candidates))
+	}
+	for i, w := range want {
+		got := result.Candidates[i]
+		w.ExpectedProfit = w.SuccessProbability*config.BridgeTVLUSD - w.EffectiveCostETH*config.ETHPriceUSD
+		if got.Tau != w.Tau || got.StartSlot != w.StartSlot {
+			t.Errorf("candidate %d: expected τ %d from slot %d, got τ %d from slot %d", i, w.Tau, w.StartSlot, got.Tau, got.StartSlot)
+		}
+		for _, f := range []struct {
+			name      string
+			want, got float64
+		}{
+			{"cost", w.CensorshipCostETH, got.CensorshipCostETH},
+			{"alpha", w.Alpha, got.Alpha},
+			{"effective cost", w.EffectiveCostETH, got.EffectiveCostETH},
+			{"success probability", w.SuccessProbability, got.SuccessProbability},
+			{"profit", w.ExpectedProfit, got.ExpectedProfit},
+		} {
+			if math.Abs(f.got-f.want) > 1e-9 {
+				t.Errorf("τ %d: expected %s %v, got %v", w.Tau, f.name, f.want, f.got)
+			}
+		}
+	}
+	if result.Tau != 4 {
+		t.Errorf("expected optimal τ 4, got %d", result.Tau)
+	}
+
+	// With the whole range's α both windows keep 60% of their cost, and
+	// the shorter one wins
+	alpha, _, err := model.ComputeBuilderConcentration(bribes, 1)
+	if err != nil {
+		t.Fatalf("ComputeBuilderConcentration failed: %v", err)
+	}
+	bestTau, bestProfit := uint64(0), math.Inf(-1)
+	for _, c := range result.Candidates {
+		profit := c.SuccessProbability*config.BridgeTVLUSD - (1-alpha)*c.CensorshipCostETH*config.ETHPriceUSD
+		if profit > bestProfit {
+			bestTau, bestProfit = c.Tau, profit
+		}
+	}
+	if bestTau != 2 {
+		t.Errorf("expected whole-range α to favour τ 2, got %d", bestTau)
+	}
+}
+
+// TestFindOptimalAttackDuration_Gaps verifies candidates stop at the first
+// duration with no gap-free window.
+func TestFindOptimalAttackDuration_Gaps(t *testing.T) {
+	// Dropping slot 3 leaves a gap-free run of 7 slots
+	bribes := optimalDurationBribes()
+	bribes = append(bribes[:2:2], bribes[3:]...)
+	result, err := FindOptimalAttackDuration(bribes, OptimalDurationConfig{
+		BridgeTVLUSD: 10000,
+		ETHPriceUSD:  1000,
+		TopK:         1,
+		Probability:  model.ConstantProbability(1),
+		MaxTau:       10,
+		Step:         2,
+	})
+	if err != nil {
+		t.Fatalf("FindOptimalAttackDuration failed: %v", err)
+	}
+	if n := len(result.Candidates); n != 3 || result.Candidates[n-1].Tau != 6 {
+		t.Errorf("expected candidates up to τ 6, got %+v", result.Candidates)
+	}
+}
+
+// TestFindOptimalAttackDuration_Errors verifies invalid configurations
+// and data too short for any candidate are rejected.
+func TestFindOptimalAttackDuration_Errors(t *testing.T) {
+	bribes := optimalDurationBribes()
+	valid := OptimalDurationConfig{
+		BridgeTVLUSD: 10000,
+		ETHPriceUSD:  1000,
+		TopK:         1,
+		Probability:  model.ConstantProbability(1),
+		MaxTau:       4,
+		Step:         2,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*OptimalDurationConfig)
+	}{
+		{"no probability model", func(c *OptimalDurationConfig) { c.Probability = nil }},
+		{"max below step", func(c *OptimalDurationConfig) { c.MaxTau = 1 }},
+		{"zero ETH price", func(c *OptimalDurationConfig) { c.ETHPriceUSD = 0 }},
+		{"probability above 1", func(c *OptimalDurationConfig) { c.Probability = model.ConstantProbability(1.5) }},
+		{"decay above 1", func(c *OptimalDurationConfig) { c.Decay = func(uint64) float64 { return 2 } }},
+		{"zero top-k", func(c *OptimalDurationConfig) { c.TopK = 0 }},
+	}
+	for _, tt := range tests {
+		config := valid
+		tt.modify(&config)
+		if _, err := FindOptimalAttackDuration(bribes, config); err == nil {
+			t.Errorf("%s: Expected error, got nil", tt.name)
+		}
+	}
+
+	config := valid
+	config.Step, config.MaxTau = 20, 20
+	if _, err := FindOptimalAttackDuration(bribes, config); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+}
+
+// TestStandardizedMoments verifies skewness and excess kurtosis against
+// hand-computed samples, and NaN when every value is equal.
+func TestStandardizedMoments(t *testing.T) {
+	tests := []struct {
+		name               string
+		values             []float64
+		skewness, kurtosis float64
+	}{
+		// Variance 2, fourth moment 6.8 = 1.7σ⁴
+		{"symmetric", []float64{-2, -1, 0, 1, 2}, 0, 1.7 - 3},
+		{"two points", []float64{-1, 1}, 0, 1 - 3},
+		// Mean 1, deviations (-1, -1, -1, 3): variance 3, third moment 6,
+		// fourth moment 21
+		{"right-skewed", []float64{0, 0, 0, 4}, 6 / math.Pow(3, 1.5), 21.0/9 - 3},
+		{"left-skewed", []float64{0, 0, 0, -4}, -6 / math.Pow(3, 1.5), 21.0/9 - 3},
+	}
+
+	for _, tt := range tests {
+		m := mean(tt.values)
+		skewness, kurtosis := standardizedMoments(tt.values, m, stdDev(tt.values, m))
+		if math.Abs(skewness-tt.skewness) > 1e-12 || math.Abs(kurtosis-tt.kurtosis) > 1e-12 {
+			t.Errorf("%s: expected skewness %v and kurtosis %v, got %v and %v", tt.name, tt.skewness, tt.kurtosis, skewness, kurtosis)
+		}
+	}
+
+	constant := []float64{0.1, 0.1, 0.1}
+	m := mean(constant)
+	if skewness, kurtosis := standardizedMoments(constant, m, stdDev(constant, m)); !math.IsNaN(skewness) || !math.IsNaN(kurtosis) {
+		t.Errorf("expected NaN moments for equal values, got %v and %v", skewness, kurtosis)
+	}
+}
+
+// TestLossProbabilities verifies the share of runs losing strictly more
+// than each threshold.
+func TestLossProbabilities(t *testing.T) {
+	sorted := []float64{-300, -200, -100, -100, 0, 50, 400, 1000}
+	thresholds := []float64{0, 99.5, 100, 250, 500}
+	want := []float64{
+		4.0 / 8, // Profit below 0; breaking even is no loss
+		4.0 / 8,
+		2.0 / 8, // Losing exactly 100 does not exceed 100
+		1.0 / 8,
+		0,
+	}
+
+	losses := lossProbabilities(sorted, thresholds)
+	if len(losses) != len(want) {
+		t.Fatalf("expected %d loss probabilities, got %d", len(want), len(losses))
+	}
+	for i, loss := range losses {
+		if loss.ThresholdUSD != thresholds[i] || loss.Probability != want[i] {
+			t.Errorf("expected P(loss > %v) = %v, got %+v", thresholds[i], want[i], loss)
+		}
+	}
+}
+
+// TestSimulateAttackOutcomes_Seed verifies a seed reproduces a simulation
+// exactly, that other seeds draw other runs, and that the result records
+// its seed.
+func TestSimulateAttackOutcomes_Seed(t *testing.T) {
+	simulate := func(seed int64) MonteCarloResult {
+		result, err := SimulateAttackOutcomes(2, 10000, 1000, 0.5, PriceShock{}, 1000, seed, RiskConfig{})
+		if err != nil {
+			t.Fatalf("SimulateAttackOutcomes failed: %v", err)
+		}
+		return result
+	}
+
+	first, second := simulate(42), simulate(42)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("expected seed 42 to reproduce %+v, got %+v", first, second)
+	}
+	if first.Seed != 42 {
+		t.Errorf("expected seed 42 recorded, got %d", first.Seed)
+	}
+	if other := simulate(43); other.ExpectedProfit == first.ExpectedProfit {
+		t.Errorf("expected seeds 42 and 43 to differ, got expected profit %v for both", first.ExpectedProfit)
+	}
+
+	// Each run succeeds or fails: profit 8000 or -2000, each about half the time
+	if math.Abs(first.ProbabilityProfitable-0.5) > 0.05 {
+		t.Errorf("expected about half the runs profitable, got %v", first.ProbabilityProfitable)
+	}
+	if first.MaxProfit != 8000 || first.MaxLoss != -2000 {
+		t.Errorf("expected profits between -2000 and 8000, got %v to %v", first.MaxLoss, first.MaxProfit)
+	}
+
+	if _, err := SimulateAttackOutcomes(2, 10000, 1000, 0.5, PriceShock{}, 0, 42, RiskConfig{}); err == nil {
+		t.Error("Expected error for zero simulations, got nil")
+	}
+}
+
+// TestSimulateAttackOutcomesResampled verifies run costs are sums of
+// bootstrapped blocks of consecutive bids: whole windows keep the bids'
+// correlation, single-slot blocks lose it.
+func TestSimulateAttackOutcomesResampled(t *testing.T) {
+	// Bids rising 1, 2, ..., 10 ETH. With p = 0 and $1/ETH every run's
+	// profit is minus its cost.
+	bids := make([]float64, 10)
+	for i := range bids {
+		bids[i] = float64(i + 1)
+	}
+	bribes := bribesFromETH(bids)
+	simulate := func(blockLen int) MonteCarloResult {
+		result, err := SimulateAttackOutcomesResampled(bribes, 3, blockLen, 10000, 1, 0, PriceShock{}, 5000, 1, RiskConfig{})
+		if err != nil {
+			t.Fatalf("blockLen %d: SimulateAttackOutcomesResampled failed: %v", blockLen, err)
+		}
+		return result
+	}
+
+	// Whole 3-slot windows cost 3s + 3 for a start s in 1..8
+	windows := simulate(0)
+	if windows.MaxProfit != -6 || windows.MaxLoss != -27 {
+		t.Errorf("expected window costs from 6 to 27, got %v to %v", -windows.MaxProfit, -windows.MaxLoss)
+	}
+	if !reflect.DeepEqual(simulate(3), windows) || !reflect.DeepEqual(simulate(5), windows) {
+		t.Error("expected blockLen 0 and blockLen above τ to draw whole windows")
+	}
+
+	// Single slots draw any three bids, from 3 to 30
+	slots := simulate(1)
+	if slots.MaxProfit != -3 || slots.MaxLoss != -30 {
+		t.Errorf("expected slot-resampled costs from 3 to 30, got %v to %v", -slots.MaxProfit, -slots.MaxLoss)
+	}
+
+	// Both average 3 · 5.5 ETH. Consecutive rising bids move together, so
+	// window costs vary with variance 9 · 5.25 against 3 · 8.25 for
+	// independent slots.
+	for _, tt := range []struct {
+		name   string
+		result MonteCarloResult
+		stdDev float64
+	}{
+		{"windows", windows, math.Sqrt(9 * 5.25)},
+		{"slots", slots, math.Sqrt(3 * 8.25)},
+	} {
+		if math.Abs(tt.result.MeanCostETH-16.5) > 0.3 {
+			t.Errorf("%s: expected mean cost near 16.5, got %v", tt.name, tt.result.MeanCostETH)
+		}
+		if math.Abs(tt.result.ProfitStdDev-tt.stdDev) > 0.2 {
+			t.Errorf("%s: expected cost deviation near %v, got %v", tt.name, tt.stdDev, tt.result.ProfitStdDev)
+		}
+	}
+
+	constant, err := SimulateAttackOutcomesResampled(bribesFromETH([]float64{0.1, 0.1, 0.1, 0.1}), 6, 2, 10000, 1000, 0.5, PriceShock{}, 100, 1, RiskConfig{})
+	if err != nil {
+		t.Fatalf("SimulateAttackOutcomesResampled failed: %v", err)
+	}
+	if math.Abs(constant.MeanCostETH-0.6) > 1e-12 || constant.CostP5ETH != constant.CostP95ETH {
+		t.Errorf("expected every run to cost 0.6, got mean %v from %v to %v", constant.MeanCostETH, constant.CostP5ETH, constant.CostP95ETH)
+	}
+
+	unsorted := bribesFromETH([]float64{1, 2, 3})
+	unsorted[1].Slot = 1
+	for _, tt := range []struct {
+		name     string
+		bribes   []model.SlotBribe
+		tau      uint64
+		blockLen int
+	}{
+		{"tau 0", bribes, 0, 1},
+		{"negative blockLen", bribes, 3, -1},
+		{"blocks longer than data", bribes[:2], 3, 3},
+		{"no data", nil, 3, 0},
+		{"unsorted", unsorted, 2, 1},
+	} {
+		if _, err := SimulateAttackOutcomesResampled(tt.bribes, tt.tau, tt.blockLen, 10000, 1, 0.5, PriceShock{}, 10, 1, RiskConfig{}); err == nil {
+			t.Errorf("%s: Expected error, got nil", tt.name)
+		}
+	}
+}
+
+// TestTailRisk verifies VaR as the (1 - c) percentile and CVaR as the mean
+// of the worst ⌈(1 - c)·n⌉ runs on a fixed sample.
+func TestTailRisk(t *testing.T) {
+	// -100, -90, ..., 90
+	sorted := make([]float64, 20)
+	for i := range sorted {
+		sorted[i] = float64(10*i - 100)
+	}
+
+	tests := []struct {
+		confidence float64
+		varUSD     float64
+		cvarUSD    float64
+	}{
+		// Position 0.05 · 19 = 0.95 interpolates -100 and -90; the worst run
+		{0.95, -90.5, -100},
+		// Position 0.19 rounds into the worst run, which is the whole tail
+		{0.99, -98.1, -100},
+		// Position 1.9; the worst 2 runs
+		{0.9, -81, -95},
+		// Position 4.75; the worst 5 runs
+		{0.75, -52.5, -80},
+		// Position 9.5, the median; the worst 10 runs
+		{0.5, -5, -55},
+	}
+
+	levels := make([]float64, len(tests))
+	for i, tt := range tests {
+		levels[i] = tt.confidence
+	}
+	tails := tailRisk(sorted, levels)
+	if len(tails) != len(tests) {
+		t.Fatalf("expected %d tail risks, got %d", len(tests), len(tails))
+	}
+	for i, tt := range tests {
+		got := tails[i]
+		if got.Confidence != tt.confidence || math.Abs(got.VaR-tt.varUSD) > 1e-9 || math.Abs(got.CVaR-tt.cvarUSD) > 1e-9 {
+			t.Errorf("confidence %v: expected VaR %v and CVaR %v, got %+v", tt.confidence, tt.varUSD, tt.cvarUSD, got)
+		}
+	}
+}
+
+// TestSimulateAttackOutcomes_TailRisk verifies simulated VaR and CVaR at
+// the default and requested levels, and rejected risk configurations.
+func TestSimulateAttackOutcomes_TailRisk(t *testing.T) {
+	// 20% of runs fail and lose the 2000 USD cost; the rest make 8000
+	result, err := SimulateAttackOutcomes(2, 10000, 1000, 0.8, PriceShock{}, 10000, 1, RiskConfig{})
+	if err != nil {
+		t.Fatalf("SimulateAttackOutcomes failed: %v", err)
+	}
+	if len(result.TailRisk) != len(DefaultConfidenceLevels) {
+		t.Fatalf("expected tail risk at %v, got %+v", DefaultConfidenceLevels, result.TailRisk)
+	}
+	for i, tail := range result.TailRisk {
+		// Both tails lie within the failed runs
+		if tail.Confidence != DefaultConfidenceLevels[i] || tail.VaR != -2000 || tail.CVaR != -2000 {
+			t.Errorf("expected VaR and CVaR -2000 at %v, got %+v", DefaultConfidenceLevels[i], tail)
+		}
+	}
+
+	// At 50% the tail mixes the failed fifth with profitable runs
+	result, err = SimulateAttackOutcomes(2, 10000, 1000, 0.8, PriceShock{}, 10000, 1, RiskConfig{ConfidenceLevels: []float64{0.5}})
+	if err != nil {
+		t.Fatalf("SimulateAttackOutcomes failed: %v", err)
+	}
+	failed := 1 - result.ProbabilityProfitable
+	if tail := result.TailRisk[0]; tail.VaR != 8000 || math.Abs(tail.CVaR-(2*failed*-2000+(1-2*failed)*8000)) > 1e-6 {
+		t.Errorf("expected VaR 8000 and CVaR over %v failed runs at 0.5, got %+v", failed, tail)
+	}
+
+	for _, risk := range []RiskConfig{
+		{ConfidenceLevels: []float64{1}},
+		{ConfidenceLevels: []float64{0}},
+		{ConfidenceLevels: []float64{math.NaN()}},
+		{LossThresholds: []float64{-1}},
+		{LossThresholds: []float64{math.Inf(1)}},
+	} {
+		if _, err := SimulateAttackOutcomes(2, 10000, 1000, 0.8, PriceShock{}, 10, 1, risk); err == nil {
+			t.Errorf("Expected error for risk config %+v, got nil", risk)
+		}
+	}
+}
+
+// TestSimulateAttackOutcomes_PriceShock verifies a price drop shrinks the
+// ETH share of the loot and inflates bribes, and that no shock leaves the
+// simulation unchanged.
+func TestSimulateAttackOutcomes_PriceShock(t *testing.T) {
+	// A fixed 20% drop takes 10% off loot half in ETH and lifts bribes by
+	// 0.2/0.8: each success makes 9000 - 2500, each failure loses 2500
+	fixed := PriceShock{Mean: 0.2, ETHExposure: 0.5, BribeResponse: 1}
+	tests := []struct {
+		successProbability float64
+		profit, unshocked  float64
+	}{
+		{1, 6500, 8000},
+		{0, -2500, -2000},
+	}
+	for _, tt := range tests {
+		result, err := SimulateAttackOutcomes(2, 10000, 1000, tt.successProbability, fixed, 100, 1, RiskConfig{})
+		if err != nil {
+			t.Fatalf("SimulateAttackOutcomes failed: %v", err)
+		}
+		if math.Abs(result.ExpectedProfit-tt.profit) > 1e-9 || math.Abs(result.UnshockedExpectedProfit-tt.unshocked) > 1e-9 {
+			t.Errorf("p %v: expected profit %v (unshocked %v), got %v (%v)",
+				tt.successProbability, tt.profit, tt.unshocked, result.ExpectedProfit, result.UnshockedExpectedProfit)
+		}
+		if math.Abs(result.MeanPriceDrop-0.2) > 1e-12 {
+			t.Errorf("p %v: expected mean drop 0.2, got %v", tt.successProbability, result.MeanPriceDrop)
+		}
+	}
+
+	// Drawn drops average the mean; clipping at 0 adds little 2σ away
+	drawn, err := SimulateAttackOutcomes(2, 10000, 1000, 0.5, PriceShock{Mean: 0.2, StdDev: 0.1, ETHExposure: 1}, 10000, 1, RiskConfig{})
+	if err != nil {
+		t.Fatalf("SimulateAttackOutcomes failed: %v", err)
+	}
+	if math.Abs(drawn.MeanPriceDrop-0.2) > 0.01 {
+		t.Errorf("expected mean drop near 0.2, got %v", drawn.MeanPriceDrop)
+	}
+	if drawn.ExpectedProfit >= drawn.UnshockedExpectedProfit {
+		t.Errorf("expected the shock to lower expected profit %v, got %v", drawn.UnshockedExpectedProfit, drawn.ExpectedProfit)
+	}
+	clipped, err := SimulateAttackOutcomes(2, 10000, 1000, 0.5, PriceShock{Mean: MaxPriceDrop, StdDev: 1}, 1000, 1, RiskConfig{})
+	if err != nil {
+		t.Fatalf("SimulateAttackOutcomes failed: %v", err)
+	}
+	if clipped.MeanPriceDrop < 0 || clipped.MeanPriceDrop > MaxPriceDrop {
+		t.Errorf("expected drops clipped to [0, %v], got mean %v", MaxPriceDrop, clipped.MeanPriceDrop)
+	}
+
+	// Exposure and response without a drop change nothing, down to the
+	// runs drawn for the seed
+	plain, err := SimulateAttackOutcomes(2, 10000, 1000, 0.5, PriceShock{}, 1000, 1, RiskConfig{})
+	if err != nil {
+		t.Fatalf("SimulateAttackOutcomes failed: %v", err)
+	}
+	inert, err := SimulateAttackOutcomes(2, 10000, 1000, 0.5, PriceShock{ETHExposure: 1, BribeResponse: 1}, 1000, 1, RiskConfig{})
+	if err != nil {
+		t.Fatalf("SimulateAttackOutcomes failed: %v", err)
+	}
+	if !reflect.DeepEqual(plain, inert) {
+		t.Errorf("expected a zero shock to leave %+v unchanged, got %+v", plain, inert)
+	}
+	if plain.UnshockedExpectedProfit != plain.ExpectedProfit || plain.MeanPriceDrop != 0 {
+		t.Errorf("expected unshocked profit %v and no drop, got %v and %v", plain.ExpectedProfit, plain.UnshockedExpectedProfit, plain.MeanPriceDrop)
+	}
+
+	for _, shock := range []PriceShock{
+		{Mean: -0.1},
+		{Mean: 1},
+		{Mean: 0.2, StdDev: -0.1},
+		{Mean: 0.2, ETHExposure: 1.5},
+		{Mean: 0.2, BribeResponse: -1},
+	} {
+		if _, err := SimulateAttackOutcomes(2, 10000, 1000, 0.5, shock, 10, 1, RiskConfig{}); err == nil {
+			t.Errorf("Expected error for shock %+v, got nil", shock)
+		}
+	}
+}
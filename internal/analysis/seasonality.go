@@ -0,0 +1,148 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// SeasonalBucket summarizes the bids placed in one hour of the day or one
+// day of the week.
+type SeasonalBucket struct {
+	Count     int
+	MeanETH   float64
+	MedianETH float64
+	Factor    float64 // MeanETH relative to the overall mean bid; 1 without bids
+}
+
+// Seasonality decomposes bid values by the UTC time their slots started.
+type Seasonality struct {
+	ByHour       [24]SeasonalBucket // Hour of the day
+	ByWeekday    [7]SeasonalBucket  // Indexed by time.Weekday, Sunday first
+	MeanETH      float64            // Overall mean bid
+	slotsPerHour uint64
+}
+
+// ComputeSeasonality groups bids by the hour of the day and the day of the
+// week in which their slots started on network. Bribes without a value
+// count as zero, as in ComputeSummary.
+//
+// Censorship is cheapest when bids are lowest, so the factors show when an
+// attacker would strike; CheapestWindow turns them into a start time.
+func (s *Statistics) ComputeSeasonality(network model.Network) (Seasonality, error) {
+	if len(s.bribes) == 0 {
+		return Seasonality{}, fmt.Errorf("%w: no bribes", model.ErrInsufficientData)
+	}
+	if network.SecondsPerSlot == 0 || 3600%network.SecondsPerSlot != 0 {
+		return Seasonality{}, fmt.Errorf("network %s slot time %ds does not divide an hour", network.Name, network.SecondsPerSlot)
+	}
+
+	var byHour [24][]float64
+	var byWeekday [7][]float64
+	var total float64
+	for _, bribe := range s.bribes {
+		var value float64
+		if bribe.ValueWei != nil {
+			value = currency.WeiToETHFloat64(bribe.ValueWei)
+		}
+		t := network.SlotTime(bribe.Slot).UTC()
+		byHour[t.Hour()] = append(byHour[t.Hour()], value)
+		byWeekday[t.Weekday()] = append(byWeekday[t.Weekday()], value)
+		total += value
+	}
+
+	z := Seasonality{
+		MeanETH:      total / float64(len(s.bribes)),
+		slotsPerHour: 3600 / network.SecondsPerSlot,
+	}
+	for h, values := range byHour {
+		z.ByHour[h] = seasonalBucket(values, z.MeanETH)
+	}
+	for d, values := range byWeekday {
+		z.ByWeekday[d] = seasonalBucket(values, z.MeanETH)
+	}
+	return z, nil
+}
+
+// seasonalBucket summarizes values against the overall mean.
+func seasonalBucket(values []float64, overall float64) SeasonalBucket {
+	if len(values) == 0 {
+		return SeasonalBucket{Factor: 1}
+	}
+	sort.Float64s(values)
+	b := SeasonalBucket{
+		Count:     len(values),
+		MeanETH:   mean(values),
+		MedianETH: percentile(values, 50),
+		Factor:    1,
+	}
+	if overall > 0 {
+		b.Factor = b.MeanETH / overall
+	}
+	return b
+}
+
+// ExpectedBidETH returns the mean bid expected at t under a multiplicative
+// decomposition: the overall mean scaled by t's hour and weekday factors.
+func (z Seasonality) ExpectedBidETH(t time.Time) float64 {
+	t = t.UTC()
+	return z.MeanETH * z.ByHour[t.Hour()].Factor * z.ByWeekday[t.Weekday()].Factor
+}
+
+// SeasonalWindow is a censorship window of τ slots starting at the top of
+// an hour of the week.
+type SeasonalWindow struct {
+	Weekday        time.Weekday
+	Hour           int // UTC
+	Tau            uint64
+	CostETH        float64 // Expected bids of the window's slots
+	AverageCostETH float64 // τ slots at the overall mean bid
+}
+
+// Ratio returns the window's expected cost relative to an average window.
+func (w SeasonalWindow) Ratio() float64 {
+	if w.AverageCostETH == 0 {
+		return 1
+	}
+	return w.CostETH / w.AverageCostETH
+}
+
+// CheapestWindow returns the hour of the week at which a τ-slot censorship
+// window starting on the hour has the lowest expected cost, pricing each
+// slot with ExpectedBidETH.
+func (z Seasonality) CheapestWindow(tau uint64) (SeasonalWindow, error) {
+	if tau < 1 {
+		return SeasonalWindow{}, fmt.Errorf("tau must be at least 1")
+	}
+	if z.slotsPerHour == 0 {
+		return SeasonalWindow{}, fmt.Errorf("%w: seasonality not computed", model.ErrInsufficientData)
+	}
+
+	// Expected cost of each full hour of the week, Sunday 00:00 first;
+	// whole weeks cost the same from any start
+	var hourly [7 * 24]float64
+	var week float64
+	for i := range hourly {
+		hourly[i] = z.MeanETH * z.ByHour[i%24].Factor * z.ByWeekday[i/24].Factor * float64(z.slotsPerHour)
+		week += hourly[i]
+	}
+	weeks, rest := tau/(z.slotsPerHour*7*24), tau%(z.slotsPerHour*7*24)
+
+	best := SeasonalWindow{Tau: tau, CostETH: math.Inf(1), AverageCostETH: float64(tau) * z.MeanETH}
+	for start := range hourly {
+		cost := float64(weeks) * week
+		for i, remaining := start, rest; remaining > 0; i = (i + 1) % len(hourly) {
+			slots := min(remaining, z.slotsPerHour)
+			cost += hourly[i] * float64(slots) / float64(z.slotsPerHour)
+			remaining -= slots
+		}
+		if cost < best.CostETH {
+			best.Weekday, best.Hour, best.CostETH = time.Weekday(start/24), start%24, cost
+		}
+	}
+	return best, nil
+}
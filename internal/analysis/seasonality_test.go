@@ -0,0 +1,158 @@
+package analysis
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// seasonalNetwork has three slots an hour from Sunday 2023-01-01 00:00 UTC,
+// so slot i falls in hour (i/3)%24 of weekday (i/72)%7.
+var seasonalNetwork = model.Network{
+	Name:           "seasonal",
+	GenesisTime:    time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	SecondsPerSlot: 1200,
+}
+
+// seasonalWeek returns one week of slots on seasonalNetwork bidding the
+// product of an hour factor (0.5 at 00:00, 2 at 03:00, else 1) and a day
+// factor (0.5 on Saturday, else 1), so the decomposition is exact.
+func seasonalWeek() []model.SlotBribe {
+	values := make([]float64, 7*24*3)
+	for i := range values {
+		hour, day := (i/3)%24, time.Weekday(i/72)
+		v := 1.0
+		switch hour {
+		case 0:
+			v = 0.5
+		case 3:
+			v = 2
+		}
+		if day == time.Saturday {
+			v *= 0.5
+		}
+		values[i] = v
+	}
+	bribes := bribesFromETH(values)
+	for i := range bribes {
+		bribes[i].Slot = uint64(i)
+	}
+	return bribes
+}
+
+// TestComputeSeasonality verifies bids are grouped by their UTC hour and
+// weekday and factored against the overall mean.
+func TestComputeSeasonality(t *testing.T) {
+	z, err := NewStatistics(seasonalWeek()).ComputeSeasonality(seasonalNetwork)
+	if err != nil {
+		t.Fatalf("ComputeSeasonality failed: %v", err)
+	}
+
+	// Hour factors sum to 24.5 and day factors to 6.5 over 504 slots
+	overall := 3 * 24.5 * 6.5 / 504
+	if math.Abs(z.MeanETH-overall) > 1e-9 {
+		t.Errorf("expected mean %v, got %v", overall, z.MeanETH)
+	}
+
+	tests := []struct {
+		name   string
+		bucket SeasonalBucket
+		count  int
+		mean   float64
+		median float64
+		factor float64
+	}{
+		{"00:00", z.ByHour[0], 21, 0.5 * 6.5 / 7, 0.5, 0.5 * 24 / 24.5},
+		{"03:00", z.ByHour[3], 21, 2 * 6.5 / 7, 2, 2 * 24 / 24.5},
+		{"12:00", z.ByHour[12], 21, 6.5 / 7, 1, 24 / 24.5},
+		{"Saturday", z.ByWeekday[time.Saturday], 72, 0.5 * 24.5 / 24, 0.5, 0.5 * 7 / 6.5},
+		{"Wednesday", z.ByWeekday[time.Wednesday], 72, 24.5 / 24, 1, 7 / 6.5},
+	}
+	for _, tt := range tests {
+		b := tt.bucket
+		if b.Count != tt.count || math.Abs(b.MeanETH-tt.mean) > 1e-9 || math.Abs(b.MedianETH-tt.median) > 1e-9 || math.Abs(b.Factor-tt.factor) > 1e-9 {
+			t.Errorf("%s: expected count %d, mean %v, median %v and factor %v, got %+v",
+				tt.name, tt.count, tt.mean, tt.median, tt.factor, b)
+		}
+	}
+
+	// The factors reproduce every bid
+	saturday3am := seasonalNetwork.GenesisTime.Add((6*24 + 3) * time.Hour)
+	if got := z.ExpectedBidETH(saturday3am); math.Abs(got-1) > 1e-9 {
+		t.Errorf("expected bid 1 on Saturday at 03:00, got %v", got)
+	}
+
+	// Hours without bids are neutral
+	z, err = NewStatistics(seasonalWeek()[:3]).ComputeSeasonality(seasonalNetwork)
+	if err != nil {
+		t.Fatalf("ComputeSeasonality failed: %v", err)
+	}
+	if b := z.ByHour[1]; b.Count != 0 || b.Factor != 1 {
+		t.Errorf("expected an empty hour with factor 1, got %+v", b)
+	}
+}
+
+// TestComputeSeasonality_Errors verifies slot times that do not divide an
+// hour and missing bribes are rejected.
+func TestComputeSeasonality_Errors(t *testing.T) {
+	stats := NewStatistics(seasonalWeek())
+	for _, seconds := range []uint64{0, 7, 7200} {
+		network := seasonalNetwork
+		network.SecondsPerSlot = seconds
+		if _, err := stats.ComputeSeasonality(network); err == nil {
+			t.Errorf("%ds slots: Expected error, got nil", seconds)
+		}
+	}
+	if _, err := NewStatistics(nil).ComputeSeasonality(seasonalNetwork); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+}
+
+// TestCheapestWindow verifies windows within an hour, across the end of
+// the week and over more than a week start at the cheapest hour.
+func TestCheapestWindow(t *testing.T) {
+	z, err := NewStatistics(seasonalWeek()).ComputeSeasonality(seasonalNetwork)
+	if err != nil {
+		t.Fatalf("ComputeSeasonality failed: %v", err)
+	}
+	week := 3 * 24.5 * 6.5
+
+	tests := []struct {
+		name    string
+		tau     uint64
+		weekday time.Weekday
+		hour    int
+		cost    float64
+	}{
+		// Two slots of Saturday's cheapest hour
+		{"under an hour", 2, time.Saturday, 0, 2 * 0.25},
+		// All of Saturday, then Sunday's cheap first hour
+		{"Saturday into Sunday", 75, time.Saturday, 0, 3*0.5*24.5 + 3*0.5},
+		// A whole week costs the same from any start
+		{"over a week", 504 + 75, time.Saturday, 0, week + 3*0.5*24.5 + 3*0.5},
+	}
+	for _, tt := range tests {
+		w, err := z.CheapestWindow(tt.tau)
+		if err != nil {
+			t.Fatalf("%s: CheapestWindow failed: %v", tt.name, err)
+		}
+		if w.Weekday != tt.weekday || w.Hour != tt.hour || w.Tau != tt.tau || math.Abs(w.CostETH-tt.cost) > 1e-9 {
+			t.Errorf("%s: expected %s %02d:00 costing %v, got %s %02d:00 costing %v",
+				tt.name, tt.weekday, tt.hour, tt.cost, w.Weekday, w.Hour, w.CostETH)
+		}
+		if average := float64(tt.tau) * z.MeanETH; math.Abs(w.AverageCostETH-average) > 1e-9 || math.Abs(w.Ratio()-tt.cost/average) > 1e-9 {
+			t.Errorf("%s: expected average cost %v and ratio %v, got %v and %v",
+				tt.name, average, tt.cost/average, w.AverageCostETH, w.Ratio())
+		}
+	}
+
+	if _, err := z.CheapestWindow(0); err == nil {
+		t.Error("Expected error for tau 0, got nil")
+	}
+	if _, err := (Seasonality{}).CheapestWindow(10); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData for a zero Seasonality, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultCompression is the sketch compression used when none is given.
+// Quantile errors are then typically well under 1% of rank near the median
+// and far smaller in the tails.
+const DefaultCompression = 100
+
+// QuantileSketch estimates quantiles of a stream in memory proportional to
+// its compression rather than to the number of values: a merging t-digest.
+//
+// Values are summarized by weighted centroids that are small near the
+// tails and larger near the median, so extreme percentiles (p99, VaR) stay
+// accurate. Min and max are exact. A QuantileSketch is not safe for
+// concurrent use.
+type QuantileSketch struct {
+	compression float64
+	centroids   []centroid // Merged centroids, sorted by mean
+	buffer      []centroid // Values added since the last merge
+	count       float64
+	min, max    float64
+}
+
+// centroid is a cluster of values summarized by their mean.
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// NewQuantileSketch returns an empty sketch. Higher compression keeps more
+// centroids and gives more accurate quantiles; compression <= 0 selects
+// DefaultCompression.
+func NewQuantileSketch(compression float64) *QuantileSketch {
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+	return &QuantileSketch{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add adds a value to the sketch. NaNs are ignored.
+func (q *QuantileSketch) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	q.buffer = append(q.buffer, centroid{mean: x, weight: 1})
+	q.count++
+	q.min = math.Min(q.min, x)
+	q.max = math.Max(q.max, x)
+	if len(q.buffer) >= 8*int(q.compression) {
+		q.merge()
+	}
+}
+
+// Merge adds every value summarized by other to q.
+func (q *QuantileSketch) Merge(other *QuantileSketch) {
+	if other.count == 0 {
+		return
+	}
+	q.buffer = append(q.buffer, other.centroids...)
+	q.buffer = append(q.buffer, other.buffer...)
+	q.count += other.count
+	q.min = math.Min(q.min, other.min)
+	q.max = math.Max(q.max, other.max)
+	q.merge()
+}
+
+// Count returns the number of values added.
+func (q *QuantileSketch) Count() int {
+	return int(q.count)
+}
+
+// Min returns the smallest value added (+Inf if none).
+func (q *QuantileSketch) Min() float64 {
+	return q.min
+}
+
+// Max returns the largest value added (-Inf if none).
+func (q *QuantileSketch) Max() float64 {
+	return q.max
+}
+
+// Quantile estimates the value at quantile p in [0, 1], interpolating
+// between centroids as percentile does between sorted values. It returns
+// NaN for an empty sketch.
+func (q *QuantileSketch) Quantile(p float64) float64 {
+	if q.count == 0 || math.IsNaN(p) {
+		return math.NaN()
+	}
+	q.merge()
+	if p <= 0 {
+		return q.min
+	}
+	if p >= 1 {
+		return q.max
+	}
+
+	c := q.centroids
+	target := p * q.count
+	// Below the first centroid's center, interpolate from the minimum
+	if first := c[0]; target < first.weight/2 {
+		if first.weight == 1 {
+			return first.mean
+		}
+		return q.min + (first.mean-q.min)*target/(first.weight/2)
+	}
+	last := c[len(c)-1]
+	if target > q.count-last.weight/2 {
+		if last.weight == 1 {
+			return last.mean
+		}
+		return q.max - (q.max-last.mean)*(q.count-target)/(last.weight/2)
+	}
+
+	// Otherwise between the centers of neighboring centroids
+	cumulative := c[0].weight / 2
+	for i := 0; i < len(c)-1; i++ {
+		step := (c[i].weight + c[i+1].weight) / 2
+		if cumulative+step >= target {
+			frac := (target - cumulative) / step
+			return c[i].mean + frac*(c[i+1].mean-c[i].mean)
+		}
+		cumulative += step
+	}
+	return last.mean
+}
+
+// merge folds the buffer into the centroids, keeping each centroid within
+// the size the k1 scale function allows at its quantile.
+func (q *QuantileSketch) merge() {
+	if len(q.buffer) == 0 {
+		return
+	}
+	all := append(q.buffer, q.centroids...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := make([]centroid, 0, len(q.centroids)+1)
+	current := all[0]
+	var before float64 // Weight of the centroids already emitted
+	limit := q.count * q.kInverse(q.k(0)+1)
+	for _, next := range all[1:] {
+		if before+current.weight+next.weight <= limit {
+			current.weight += next.weight
+			current.mean += (next.mean - current.mean) * next.weight / current.weight
+			continue
+		}
+		before += current.weight
+		merged = append(merged, current)
+		limit = q.count * q.kInverse(q.k(before/q.count)+1)
+		current = next
+	}
+	q.centroids = append(merged, current)
+	q.buffer = q.buffer[:0]
+}
+
+// k is the k1 scale function δ/(2π)·asin(2p-1), whose unit steps bound
+// centroid sizes.
+func (q *QuantileSketch) k(p float64) float64 {
+	return q.compression / (2 * math.Pi) * math.Asin(2*p-1)
+}
+
+// kInverse is the inverse of k, saturating at quantile 1.
+func (q *QuantileSketch) kInverse(k float64) float64 {
+	if k >= q.compression/4 {
+		return 1
+	}
+	return (math.Sin(k*2*math.Pi/q.compression) + 1) / 2
+}
@@ -0,0 +1,126 @@
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// lognormalSample returns n heavy-tailed values, shaped like bids in ETH.
+func lognormalSample(n int, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.Exp(-3 + rng.NormFloat64())
+	}
+	return values
+}
+
+// rankError returns how far the rank of estimate in sorted is from p, as a
+// fraction of the sample.
+func rankError(sorted []float64, estimate, p float64) float64 {
+	rank := sort.SearchFloat64s(sorted, estimate)
+	return math.Abs(float64(rank)/float64(len(sorted)) - p)
+}
+
+// sketchQuantiles are the quantiles checked, with the rank error allowed at
+// each under DefaultCompression: looser at the median, where centroids are
+// largest.
+var sketchQuantiles = []struct {
+	p        float64
+	maxError float64
+}{
+	{0.50, 0.005},
+	{0.95, 0.002},
+	{0.99, 0.001},
+}
+
+// TestQuantileSketch verifies p50, p95 and p99 of a known distribution
+// fall within a stated rank error of the exact sorted-sample quantiles.
+func TestQuantileSketch(t *testing.T) {
+	values := lognormalSample(100000, 1)
+	sketch := NewQuantileSketch(0)
+	for _, v := range values {
+		sketch.Add(v)
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	for _, q := range sketchQuantiles {
+		estimate := sketch.Quantile(q.p)
+		if e := rankError(sorted, estimate, q.p); e > q.maxError {
+			t.Errorf("p%v: expected rank error at most %v, got %v (estimate %v, exact %v)",
+				q.p*100, q.maxError, e, estimate, percentile(sorted, q.p*100))
+		}
+	}
+	if sketch.Count() != len(values) || sketch.Min() != sorted[0] || sketch.Max() != sorted[len(sorted)-1] {
+		t.Errorf("expected count %d, min %v and max %v, got %d, %v and %v",
+			len(values), sorted[0], sorted[len(sorted)-1], sketch.Count(), sketch.Min(), sketch.Max())
+	}
+}
+
+// TestQuantileSketch_Merge verifies two merged sketches estimate the
+// quantiles of their combined values as closely as a single sketch.
+func TestQuantileSketch_Merge(t *testing.T) {
+	// Different distributions, so the merge has to interleave centroids
+	low := lognormalSample(50000, 2)
+	high := lognormalSample(30000, 3)
+	for i := range high {
+		high[i] *= 4
+	}
+	a, b := NewQuantileSketch(0), NewQuantileSketch(0)
+	for _, v := range low {
+		a.Add(v)
+	}
+	for _, v := range high {
+		b.Add(v)
+	}
+	a.Merge(b)
+
+	sorted := append(append([]float64(nil), low...), high...)
+	sort.Float64s(sorted)
+	for _, q := range sketchQuantiles {
+		estimate := a.Quantile(q.p)
+		if e := rankError(sorted, estimate, q.p); e > q.maxError {
+			t.Errorf("p%v: expected rank error at most %v, got %v (estimate %v, exact %v)",
+				q.p*100, q.maxError, e, estimate, percentile(sorted, q.p*100))
+		}
+	}
+	if a.Count() != len(sorted) || a.Min() != sorted[0] || a.Max() != sorted[len(sorted)-1] {
+		t.Errorf("expected count %d, min %v and max %v, got %d, %v and %v",
+			len(sorted), sorted[0], sorted[len(sorted)-1], a.Count(), a.Min(), a.Max())
+	}
+	if b.Count() != len(high) {
+		t.Errorf("expected the merged sketch to keep %d values, got %d", len(high), b.Count())
+	}
+}
+
+// TestQuantileSketch_Small verifies an empty sketch has no quantiles and a
+// single value is every quantile.
+func TestQuantileSketch_Small(t *testing.T) {
+	empty := NewQuantileSketch(0)
+	if !math.IsNaN(empty.Quantile(0.5)) {
+		t.Errorf("expected NaN median for an empty sketch, got %v", empty.Quantile(0.5))
+	}
+	if empty.Count() != 0 || !math.IsInf(empty.Min(), 1) || !math.IsInf(empty.Max(), -1) {
+		t.Errorf("expected count 0, min +Inf and max -Inf, got %d, %v and %v", empty.Count(), empty.Min(), empty.Max())
+	}
+
+	single := NewQuantileSketch(0)
+	single.Add(0.25)
+	single.Add(math.NaN())
+	// Merging an empty sketch changes nothing
+	single.Merge(empty)
+	for _, p := range []float64{0, 0.01, 0.5, 0.99, 1} {
+		if got := single.Quantile(p); got != 0.25 {
+			t.Errorf("expected quantile %v of a single value 0.25, got %v", p, got)
+		}
+	}
+	if single.Count() != 1 {
+		t.Errorf("expected count 1, got %d", single.Count())
+	}
+	if !math.IsNaN(single.Quantile(math.NaN())) {
+		t.Errorf("expected NaN for quantile NaN, got %v", single.Quantile(math.NaN()))
+	}
+}
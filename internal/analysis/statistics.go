@@ -3,9 +3,9 @@ package analysis
 import (
 	"fmt"
 	"math"
-	"math/big"
 	"sort"
 
+	"insolventbydesign/internal/currency"
 	"insolventbydesign/internal/model"
 )
 
@@ -32,6 +32,7 @@ type Summary struct {
 	P95ETH    float64
 	P99ETH    float64
 	TotalETH  float64
+	CI        *SummaryIntervals // Bootstrap confidence intervals, if computed with ComputeSummaryWithCI
 }
 
 // ComputeSummary computes comprehensive statistics.
@@ -41,12 +42,9 @@ func (s *Statistics) ComputeSummary() Summary {
 	}
 
 	valuesETH := make([]float64, len(s.bribes))
-	weiPerEth := new(big.Float).SetInt(big.NewInt(1e18))
-
 	for i, bribe := range s.bribes {
 		if bribe.ValueWei != nil {
-			ethVal := new(big.Float).Quo(new(big.Float).SetInt(bribe.ValueWei), weiPerEth)
-			valuesETH[i], _ = ethVal.Float64()
+			valuesETH[i] = currency.WeiToETHFloat64(bribe.ValueWei)
 		}
 	}
 
@@ -93,48 +91,15 @@ type RollingStatistics struct {
 
 // ComputeRollingStats computes statistics over sliding windows.
 func (s *Statistics) ComputeRollingStats(windowSize int) []RollingStatistics {
-	if len(s.bribes) < windowSize {
+	if windowSize < 1 || len(s.bribes) < windowSize {
 		return nil
 	}
 
 	results := make([]RollingStatistics, 0, len(s.bribes)-windowSize+1)
-	weiPerEth := new(big.Float).SetInt(big.NewInt(1e18))
-
-	for i := windowSize - 1; i < len(s.bribes); i++ {
-		window := s.bribes[i-windowSize+1 : i+1]
-
-		values := make([]float64, windowSize)
-		for j, bribe := range window {
-			if bribe.ValueWei != nil {
-				ethVal := new(big.Float).Quo(new(big.Float).SetInt(bribe.ValueWei), weiPerEth)
-				values[j], _ = ethVal.Float64()
-			}
-		}
-
-		stat := RollingStatistics{
-			Slot:   s.bribes[i].Slot,
-			MaxETH: maxFloat64(values),
-			MinETH: minFloat64(values),
-		}
-
-		// Mean
-		var sum float64
-		for _, v := range values {
-			sum += v
-		}
-		stat.MeanETH = sum / float64(len(values))
-
-		// StdDev
-		var variance float64
-		for _, v := range values {
-			diff := v - stat.MeanETH
-			variance += diff * diff
-		}
-		stat.StdDevETH = math.Sqrt(variance / float64(len(values)))
-
+	StreamRollingStats(model.BribesFromSlice(s.bribes), windowSize, func(stat RollingStatistics) error {
 		results = append(results, stat)
-	}
-
+		return nil
+	})
 	return results
 }
 
@@ -149,42 +114,16 @@ type ConcentrationTrend struct {
 
 // ComputeConcentrationTrends computes rolling concentration metrics.
 func (s *Statistics) ComputeConcentrationTrends(windowSize int) []ConcentrationTrend {
-	if len(s.bribes) < windowSize {
+	if windowSize < 1 || len(s.bribes) < windowSize {
 		return nil
 	}
 
+	// Slide one counter across the data instead of recounting each window
 	results := make([]ConcentrationTrend, 0, len(s.bribes)-windowSize+1)
-
-	for i := windowSize - 1; i < len(s.bribes); i++ {
-		window := s.bribes[i-windowSize+1 : i+1]
-
-		alpha3, _, _ := model.ComputeBuilderConcentration(window, 3)
-		alpha5, _, _ := model.ComputeBuilderConcentration(window, 5)
-
-		// Count unique builders
-		builderSet := make(map[string]bool)
-		builderCounts := make(map[string]int)
-		for _, bribe := range window {
-			builderSet[bribe.BuilderPubkey] = true
-			builderCounts[bribe.BuilderPubkey]++
-		}
-
-		// Herfindahl-Hirschman Index
-		var hhi float64
-		for _, count := range builderCounts {
-			share := float64(count) / float64(len(window))
-			hhi += share * share
-		}
-
-		results = append(results, ConcentrationTrend{
-			Slot:              s.bribes[i].Slot,
-			ConcentrationTop3: alpha3,
-			ConcentrationTop5: alpha5,
-			UniqueBuilders:    len(builderSet),
-			HerfindahlIndex:   hhi,
-		})
-	}
-
+	StreamConcentrationTrends(model.BribesFromSlice(s.bribes), windowSize, func(trend ConcentrationTrend) error {
+		results = append(results, trend)
+		return nil
+	})
 	return results
 }
 
@@ -194,18 +133,13 @@ func (s *Statistics) PredictFutureCost(tau uint64, alpha float64) (float64, erro
 		return 0, fmt.Errorf("no data available")
 	}
 
-	weiPerEth := new(big.Float).SetInt(big.NewInt(1e18))
-
 	// Start with first value
-	firstVal := new(big.Float).Quo(new(big.Float).SetInt(s.bribes[0].ValueWei), weiPerEth)
-	ema, _ := firstVal.Float64()
+	ema := currency.WeiToETHFloat64(s.bribes[0].ValueWei)
 
 	// Compute EMA
 	for i := 1; i < len(s.bribes); i++ {
 		if s.bribes[i].ValueWei != nil {
-			ethVal := new(big.Float).Quo(new(big.Float).SetInt(s.bribes[i].ValueWei), weiPerEth)
-			val, _ := ethVal.Float64()
-			ema = alpha*val + (1-alpha)*ema
+			ema = alpha*currency.WeiToETHFloat64(s.bribes[i].ValueWei) + (1-alpha)*ema
 		}
 	}
 
@@ -230,29 +164,3 @@ func percentile(sortedData []float64, p float64) float64 {
 	weight := index - float64(lower)
 	return sortedData[lower]*(1-weight) + sortedData[upper]*weight
 }
-
-func maxFloat64(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-	max := values[0]
-	for _, v := range values[1:] {
-		if v > max {
-			max = v
-		}
-	}
-	return max
-}
-
-func minFloat64(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-	min := values[0]
-	for _, v := range values[1:] {
-		if v < min {
-			min = v
-		}
-	}
-	return min
-}
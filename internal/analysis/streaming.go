@@ -0,0 +1,206 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// StreamingStatistics accumulates a Summary one bribe at a time in memory
+// independent of the number of bribes, for datasets too large to load into
+// a Statistics. Count, total, mean, standard deviation, min and max are
+// exact; percentiles are estimated with a QuantileSketch. Bribes without a
+// value count as zero, as in ComputeSummary. A StreamingStatistics is not
+// safe for concurrent use; give each goroutine its own and Merge them.
+type StreamingStatistics struct {
+	sketch *QuantileSketch
+	mean   float64 // Welford's running mean
+	m2     float64 // Sum of squared deviations from mean
+	total  float64
+}
+
+// NewStreamingStatistics returns an empty accumulator whose percentile
+// sketch has the given compression (<= 0 selects DefaultCompression).
+func NewStreamingStatistics(compression float64) *StreamingStatistics {
+	return &StreamingStatistics{sketch: NewQuantileSketch(compression)}
+}
+
+// Add adds one bribe's value.
+func (s *StreamingStatistics) Add(bribe model.SlotBribe) {
+	var v float64
+	if bribe.ValueWei != nil {
+		v = currency.WeiToETHFloat64(bribe.ValueWei)
+	}
+	s.sketch.Add(v)
+	s.total += v
+	delta := v - s.mean
+	s.mean += delta / s.sketch.count
+	s.m2 += delta * (v - s.mean)
+}
+
+// Merge adds every bribe accumulated by other to s, combining the moments
+// with Chan et al.'s parallel update.
+func (s *StreamingStatistics) Merge(other *StreamingStatistics) {
+	n, m := s.sketch.count, other.sketch.count
+	if m == 0 {
+		return
+	}
+	delta := other.mean - s.mean
+	s.mean += delta * m / (n + m)
+	s.m2 += other.m2 + delta*delta*n*m/(n+m)
+	s.total += other.total
+	s.sketch.Merge(other.sketch)
+}
+
+// Count returns the number of bribes added.
+func (s *StreamingStatistics) Count() int {
+	return s.sketch.Count()
+}
+
+// Summary returns the statistics of the bribes added so far, or a zero
+// Summary if there are none.
+func (s *StreamingStatistics) Summary() Summary {
+	if s.sketch.Count() == 0 {
+		return Summary{}
+	}
+	return Summary{
+		Count:     s.sketch.Count(),
+		MeanETH:   s.mean,
+		MedianETH: s.sketch.Quantile(0.50),
+		StdDevETH: math.Sqrt(s.m2 / s.sketch.count),
+		MinETH:    s.sketch.Min(),
+		MaxETH:    s.sketch.Max(),
+		P25ETH:    s.sketch.Quantile(0.25),
+		P75ETH:    s.sketch.Quantile(0.75),
+		P95ETH:    s.sketch.Quantile(0.95),
+		P99ETH:    s.sketch.Quantile(0.99),
+		TotalETH:  s.total,
+	}
+}
+
+// SummarizeBribes computes a Summary of bribe values from a stream with a
+// StreamingStatistics of the given compression.
+func SummarizeBribes(source model.BribeSource, compression float64) (Summary, error) {
+	stats := NewStreamingStatistics(compression)
+	err := source(func(bribe model.SlotBribe) error {
+		stats.Add(bribe)
+		return nil
+	})
+	if err != nil {
+		return Summary{}, fmt.Errorf("failed to read bribes: %w", err)
+	}
+	return stats.Summary(), nil
+}
+
+// StreamRollingStats calls fn with the statistics of every window of
+// windowSize consecutive bribes from source, as ComputeRollingStats
+// returns them, holding only one window in memory. The mean and variance
+// are updated as each bribe replaces the oldest and recomputed once per
+// window, and the min and max are kept in monotonic queues, so each bribe
+// costs O(1) amortized.
+func StreamRollingStats(source model.BribeSource, windowSize int, fn func(RollingStatistics) error) error {
+	if windowSize < 1 {
+		return fmt.Errorf("window must be at least 1, got %d", windowSize)
+	}
+	w := float64(windowSize)
+	ring := make([]float64, windowSize) // Values of the window, oldest at i % windowSize
+	var mean, m2 float64
+	var minQ, maxQ []int // Positions of candidate minima (ascending values) and maxima (descending)
+
+	i := 0
+	err := source(func(bribe model.SlotBribe) error {
+		var v float64
+		if bribe.ValueWei != nil {
+			v = currency.WeiToETHFloat64(bribe.ValueWei)
+		}
+		if i < windowSize {
+			delta := v - mean
+			mean += delta / float64(i+1)
+			m2 += delta * (v - mean)
+		} else {
+			// Replace the oldest value in the mean and sum of squares
+			old := ring[i%windowSize]
+			prevMean := mean
+			mean += (v - old) / w
+			m2 = math.Max(0, m2+(v-old)*(v-mean+old-prevMean))
+		}
+		ring[i%windowSize] = v
+		if i%windowSize == windowSize-1 {
+			// Recompute once per turn of the ring so rounding errors of the
+			// updates do not accumulate over the stream
+			mean = 0
+			for _, x := range ring {
+				mean += x
+			}
+			mean /= w
+			m2 = 0
+			for _, x := range ring {
+				m2 += (x - mean) * (x - mean)
+			}
+		}
+
+		for len(minQ) > 0 && ring[minQ[len(minQ)-1]%windowSize] >= v {
+			minQ = minQ[:len(minQ)-1]
+		}
+		minQ = append(minQ, i)
+		for len(maxQ) > 0 && ring[maxQ[len(maxQ)-1]%windowSize] <= v {
+			maxQ = maxQ[:len(maxQ)-1]
+		}
+		maxQ = append(maxQ, i)
+		if minQ[0] <= i-windowSize {
+			minQ = minQ[1:]
+		}
+		if maxQ[0] <= i-windowSize {
+			maxQ = maxQ[1:]
+		}
+		i++
+
+		if i < windowSize {
+			return nil
+		}
+		return fn(RollingStatistics{
+			Slot:      bribe.Slot,
+			MeanETH:   mean,
+			StdDevETH: math.Sqrt(m2 / w),
+			MaxETH:    ring[maxQ[0]%windowSize],
+			MinETH:    ring[minQ[0]%windowSize],
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream rolling statistics: %w", err)
+	}
+	return nil
+}
+
+// StreamConcentrationTrends calls fn with the builder concentration of
+// every window of windowSize consecutive bribes from source, as
+// ComputeConcentrationTrends returns them, sliding one
+// model.SlidingConcentration across the stream.
+func StreamConcentrationTrends(source model.BribeSource, windowSize int, fn func(ConcentrationTrend) error) error {
+	sc, err := model.NewSlidingConcentration(windowSize)
+	if err != nil {
+		return err
+	}
+	err = source(func(bribe model.SlotBribe) error {
+		sc.Add(bribe)
+		if sc.Len() < windowSize {
+			return nil
+		}
+		alpha3, _ := sc.Alpha(3)
+		alpha5, _ := sc.Alpha(5)
+		hhi, _ := sc.HerfindahlIndex()
+		return fn(ConcentrationTrend{
+			Slot:              bribe.Slot,
+			ConcentrationTop3: alpha3,
+			ConcentrationTop5: alpha5,
+			UniqueBuilders:    sc.UniqueBuilders(),
+			HerfindahlIndex:   hhi,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream concentration trends: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// streamingFixture returns n slots of heavy-tailed bids won by a skewed mix
+// of eight builders, with every 50th bid missing.
+func streamingFixture(n int) []model.SlotBribe {
+	rng := rand.New(rand.NewSource(1))
+	values := lognormalSample(n, 2)
+	bribes := bribesFromETH(values)
+	for i := range bribes {
+		// Builder 0x0 wins about half the slots, 0x7 about 1 in 256
+		bribes[i].BuilderPubkey = fmt.Sprintf("0x%d", min(7, int(-math.Log2(rng.Float64()))))
+		if i%50 == 49 {
+			bribes[i].ValueWei = nil
+		}
+	}
+	return bribes
+}
+
+// closeTo reports whether got is within a relative 1e-9 of want.
+func closeTo(got, want float64) bool {
+	return math.Abs(got-want) <= 1e-9*math.Max(1, math.Abs(want))
+}
+
+// TestSummarizeBribes verifies the streamed summary's moments and extremes
+// match the batch summary exactly and its percentiles are the sketch's
+// estimates of the batch ones, also when accumulated in parts and merged.
+func TestSummarizeBribes(t *testing.T) {
+	bribes := streamingFixture(20000)
+	want := NewStatistics(bribes).ComputeSummary()
+	streamed, err := SummarizeBribes(model.BribesFromSlice(bribes), 0)
+	if err != nil {
+		t.Fatalf("SummarizeBribes failed: %v", err)
+	}
+
+	first, second := NewStreamingStatistics(0), NewStreamingStatistics(0)
+	for i, bribe := range bribes {
+		if i < 7000 {
+			first.Add(bribe)
+		} else {
+			second.Add(bribe)
+		}
+	}
+	first.Merge(second)
+
+	sorted := make([]float64, len(bribes))
+	for i, bribe := range bribes {
+		if bribe.ValueWei != nil {
+			sorted[i] = currency.WeiToETHFloat64(bribe.ValueWei)
+		}
+	}
+	sort.Float64s(sorted)
+
+	for name, got := range map[string]Summary{"streamed": streamed, "merged": first.Summary()} {
+		if got.Count != want.Count {
+			t.Errorf("%s: expected count %d, got %d", name, want.Count, got.Count)
+		}
+		exact := []struct {
+			field     string
+			got, want float64
+		}{
+			{"mean", got.MeanETH, want.MeanETH},
+			{"standard deviation", got.StdDevETH, want.StdDevETH},
+			{"min", got.MinETH, want.MinETH},
+			{"max", got.MaxETH, want.MaxETH},
+			{"total", got.TotalETH, want.TotalETH},
+		}
+		for _, e := range exact {
+			if !closeTo(e.got, e.want) {
+				t.Errorf("%s: expected %s %v, got %v", name, e.field, e.want, e.got)
+			}
+		}
+		estimated := []struct {
+			p        float64
+			got      float64
+			maxError float64
+		}{
+			{0.25, got.P25ETH, 0.005},
+			{0.50, got.MedianETH, 0.005},
+			{0.75, got.P75ETH, 0.005},
+			{0.95, got.P95ETH, 0.002},
+			{0.99, got.P99ETH, 0.001},
+		}
+		for _, e := range estimated {
+			if err := rankError(sorted, e.got, e.p); err > e.maxError {
+				t.Errorf("%s: p%v: expected rank error at most %v, got %v (estimate %v, exact %v)",
+					name, e.p*100, e.maxError, err, e.got, percentile(sorted, e.p*100))
+			}
+		}
+	}
+
+	if got, err := SummarizeBribes(model.BribesFromSlice(nil), 0); err != nil || got.Count != 0 {
+		t.Errorf("expected an empty summary, got %+v and %v", got, err)
+	}
+}
+
+// TestStreamRollingStats verifies every streamed window matches the batch
+// summary of the same window, across many turns of the ring.
+func TestStreamRollingStats(t *testing.T) {
+	bribes := streamingFixture(2000)
+	for _, windowSize := range []int{1, 7, 100} {
+		var got []RollingStatistics
+		err := StreamRollingStats(model.BribesFromSlice(bribes), windowSize, func(stat RollingStatistics) error {
+			got = append(got, stat)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamRollingStats failed: %v", err)
+		}
+		if len(got) != len(bribes)-windowSize+1 {
+			t.Fatalf("window %d: expected %d windows, got %d", windowSize, len(bribes)-windowSize+1, len(got))
+		}
+		for i, stat := range got {
+			window := bribes[i : i+windowSize]
+			want := NewStatistics(window).ComputeSummary()
+			if stat.Slot != window[windowSize-1].Slot {
+				t.Fatalf("window %d: expected slot %d, got %d", windowSize, window[windowSize-1].Slot, stat.Slot)
+			}
+			// The standard deviation is updated incrementally, so it only
+			// matches to the rounding of the sum of squares
+			if !closeTo(stat.MeanETH, want.MeanETH) || math.Abs(stat.StdDevETH-want.StdDevETH) > 1e-6 ||
+				stat.MinETH != want.MinETH || stat.MaxETH != want.MaxETH {
+				t.Fatalf("window %d at slot %d: expected mean %v, deviation %v, min %v and max %v, got %+v",
+					windowSize, stat.Slot, want.MeanETH, want.StdDevETH, want.MinETH, want.MaxETH, stat)
+			}
+		}
+	}
+
+	if err := StreamRollingStats(model.BribesFromSlice(bribes), 0, func(RollingStatistics) error { return nil }); err == nil {
+		t.Error("Expected error for zero window size, got nil")
+	}
+	stop := errors.New("stop")
+	err := StreamRollingStats(model.BribesFromSlice(bribes), 10, func(RollingStatistics) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Errorf("expected the callback's error, got %v", err)
+	}
+}
+
+// TestStreamConcentrationTrends verifies every streamed window matches the
+// concentration recounted from scratch over the same window.
+func TestStreamConcentrationTrends(t *testing.T) {
+	bribes := streamingFixture(1000)
+	const windowSize = 64
+	var got []ConcentrationTrend
+	err := StreamConcentrationTrends(model.BribesFromSlice(bribes), windowSize, func(trend ConcentrationTrend) error {
+		got = append(got, trend)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamConcentrationTrends failed: %v", err)
+	}
+	if len(got) != len(bribes)-windowSize+1 {
+		t.Fatalf("expected %d windows, got %d", len(bribes)-windowSize+1, len(got))
+	}
+
+	for i, trend := range got {
+		window := bribes[i : i+windowSize]
+		top3, _, err := model.ComputeBuilderConcentration(window, 3)
+		if err != nil {
+			t.Fatalf("ComputeBuilderConcentration failed: %v", err)
+		}
+		top5, _, err := model.ComputeBuilderConcentration(window, 5)
+		if err != nil {
+			t.Fatalf("ComputeBuilderConcentration failed: %v", err)
+		}
+		hhi, err := model.HerfindahlIndex(window, model.ConcentrationByBlocks)
+		if err != nil {
+			t.Fatalf("HerfindahlIndex failed: %v", err)
+		}
+		want := ConcentrationTrend{
+			Slot:              window[windowSize-1].Slot,
+			ConcentrationTop3: top3,
+			ConcentrationTop5: top5,
+			UniqueBuilders:    model.GetBuilderDiversity(window),
+			HerfindahlIndex:   hhi,
+		}
+		if trend.Slot != want.Slot || trend.UniqueBuilders != want.UniqueBuilders ||
+			!closeTo(trend.ConcentrationTop3, want.ConcentrationTop3) ||
+			!closeTo(trend.ConcentrationTop5, want.ConcentrationTop5) ||
+			!closeTo(trend.HerfindahlIndex, want.HerfindahlIndex) {
+			t.Fatalf("expected trend %+v, got %+v", want, trend)
+		}
+	}
+
+	if err := StreamConcentrationTrends(model.BribesFromSlice(bribes), 0, func(ConcentrationTrend) error { return nil }); err == nil {
+		t.Error("Expected error for zero window size, got nil")
+	}
+}
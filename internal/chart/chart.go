@@ -0,0 +1,238 @@
+// Package chart renders analysis results as PNG or SVG plots, so results
+// can be inspected without a separate notebook.
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette/moreland"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+
+	"insolventbydesign/internal/analysis"
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// Chart file formats.
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// Size of a rendered chart.
+const (
+	Width  = 8 * vg.Inch
+	Height = 4.5 * vg.Inch
+)
+
+// FormatForPath returns the chart format implied by path's extension.
+func FormatForPath(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".png":
+		return FormatPNG, nil
+	case ".svg":
+		return FormatSVG, nil
+	default:
+		return "", fmt.Errorf("unknown chart format '%s' (want .png or .svg)", ext)
+	}
+}
+
+// Write renders p at Width × Height in format (FormatPNG or FormatSVG).
+func Write(w io.Writer, p *plot.Plot, format string) error {
+	if format != FormatPNG && format != FormatSVG {
+		return fmt.Errorf("unknown chart format '%s' (want %s or %s)", format, FormatPNG, FormatSVG)
+	}
+	canvas, err := p.WriterTo(Width, Height, format)
+	if err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+	if _, err := canvas.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write chart: %w", err)
+	}
+	return nil
+}
+
+// Save renders p to path in the format of its extension.
+func Save(p *plot.Plot, path string) error {
+	format, err := FormatForPath(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create chart file: %w", err)
+	}
+	err = Write(f, p, format)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// RollingCost plots the mean, minimum and maximum bid of each rolling
+// window against its last slot: the cost of censoring one slot at that
+// point in the data.
+func RollingCost(rolling []analysis.RollingStatistics) (*plot.Plot, error) {
+	if len(rolling) == 0 {
+		return nil, fmt.Errorf("%w: no rolling windows to plot", model.ErrInsufficientData)
+	}
+	mean := make(plotter.XYs, len(rolling))
+	low := make(plotter.XYs, len(rolling))
+	high := make(plotter.XYs, len(rolling))
+	for i, r := range rolling {
+		x := float64(r.Slot)
+		mean[i] = plotter.XY{X: x, Y: r.MeanETH}
+		low[i] = plotter.XY{X: x, Y: r.MinETH}
+		high[i] = plotter.XY{X: x, Y: r.MaxETH}
+	}
+
+	p := plot.New()
+	p.Title.Text = "Rolling censorship cost per slot"
+	p.X.Label.Text = "Slot"
+	p.X.Tick.Marker = slotTicks
+	p.Y.Label.Text = "Bid (ETH)"
+	p.Legend.Top = true
+	if err := plotutil.AddLines(p, "Max", high, "Mean", mean, "Min", low); err != nil {
+		return nil, fmt.Errorf("failed to plot rolling cost: %w", err)
+	}
+	return p, nil
+}
+
+// ConcentrationTrends plots the top-3 and top-5 builder shares and the
+// Herfindahl-Hirschman index of each rolling window against its last slot.
+func ConcentrationTrends(trends []analysis.ConcentrationTrend) (*plot.Plot, error) {
+	if len(trends) == 0 {
+		return nil, fmt.Errorf("%w: no concentration windows to plot", model.ErrInsufficientData)
+	}
+	top3 := make(plotter.XYs, len(trends))
+	top5 := make(plotter.XYs, len(trends))
+	hhi := make(plotter.XYs, len(trends))
+	for i, t := range trends {
+		x := float64(t.Slot)
+		top3[i] = plotter.XY{X: x, Y: t.ConcentrationTop3}
+		top5[i] = plotter.XY{X: x, Y: t.ConcentrationTop5}
+		hhi[i] = plotter.XY{X: x, Y: t.HerfindahlIndex}
+	}
+
+	p := plot.New()
+	p.Title.Text = "Builder concentration"
+	p.X.Label.Text = "Slot"
+	p.X.Tick.Marker = slotTicks
+	p.Y.Label.Text = "Share of blocks"
+	p.Y.Min, p.Y.Max = 0, 1.2 // Headroom for the legend above α = 1
+	p.Legend.Top = true
+	if err := plotutil.AddLines(p, "α(top5)", top5, "α(top3)", top3, "HHI", hhi); err != nil {
+		return nil, fmt.Errorf("failed to plot concentration trends: %w", err)
+	}
+	return p, nil
+}
+
+// ProfitSurface plots a profit grid as a heat map over bridge TVL and
+// success probability, red where the attack profits and blue where it
+// loses, with the breakeven curve V* = C_c^eff / p drawn over it.
+func ProfitSurface(grid *model.ProfitGrid) (*plot.Plot, error) {
+	if grid == nil || len(grid.Probabilities) < 2 || len(grid.TVLs) < 2 {
+		return nil, fmt.Errorf("%w: profit grid needs at least 2 probabilities and 2 TVLs", model.ErrInsufficientData)
+	}
+	surface := &profitSurface{grid: grid}
+
+	// Center the palette on zero so white marks breakeven
+	var bound float64
+	for _, row := range grid.Results {
+		for _, cell := range row {
+			bound = math.Max(bound, math.Abs(weiToETH(cell.Profit)))
+		}
+	}
+	if bound == 0 {
+		bound = 1
+	}
+	colors := moreland.SmoothBlueRed()
+	colors.SetMin(-bound)
+	colors.SetMax(bound)
+	heat := plotter.NewHeatMap(surface, colors.Palette(255))
+	heat.Min, heat.Max = -bound, bound
+
+	p := plot.New()
+	p.Title.Text = "Attacker profit (ETH)"
+	p.X.Label.Text = "Bridge TVL (ETH)"
+	p.Y.Label.Text = "Success probability"
+	p.Add(heat)
+
+	// Breakeven TVL of each probability inside the grid's TVL range
+	maxTVL := weiToETH(grid.TVLs[len(grid.TVLs)-1])
+	var breakeven plotter.XYs
+	for _, point := range grid.Contour() {
+		if point.Breakeven == nil {
+			continue
+		}
+		if v := weiToETH(point.Breakeven); v <= maxTVL {
+			breakeven = append(breakeven, plotter.XY{X: v, Y: point.SuccessProb})
+		}
+	}
+	if len(breakeven) > 1 {
+		line, err := plotter.NewLine(breakeven)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plot breakeven curve: %w", err)
+		}
+		line.Color = color.Black
+		line.Width = vg.Points(1.5)
+		p.Add(line)
+		p.Legend.Add("Breakeven", line)
+		p.Legend.Top = true
+	}
+	return p, nil
+}
+
+// slotTicks labels slots as integers rather than with DefaultTicks' fixed
+// decimals.
+var slotTicks = plot.TickerFunc(func(min, max float64) []plot.Tick {
+	ticks := plot.DefaultTicks{}.Ticks(min, max)
+	for i, tick := range ticks {
+		if tick.Label != "" {
+			ticks[i].Label = strconv.FormatFloat(tick.Value, 'f', -1, 64)
+		}
+	}
+	return ticks
+})
+
+// profitSurface adapts a ProfitGrid to plotter.GridXYZ: columns are TVLs
+// and rows probabilities, in ETH.
+type profitSurface struct {
+	grid *model.ProfitGrid
+}
+
+func (s *profitSurface) Dims() (c, r int) {
+	return len(s.grid.TVLs), len(s.grid.Probabilities)
+}
+
+func (s *profitSurface) Z(c, r int) float64 {
+	return weiToETH(s.grid.Results[r][c].Profit)
+}
+
+func (s *profitSurface) X(c int) float64 {
+	return weiToETH(s.grid.TVLs[c])
+}
+
+func (s *profitSurface) Y(r int) float64 {
+	return s.grid.Probabilities[r]
+}
+
+// weiToETH returns wei in ETH, or 0 when nil.
+func weiToETH(wei *big.Float) float64 {
+	if wei == nil {
+		return 0
+	}
+	eth, _ := currency.FloatWeiToETH(wei).Float64()
+	return eth
+}
@@ -0,0 +1,92 @@
+package chart
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/plot"
+
+	"insolventbydesign/internal/analysis"
+	"insolventbydesign/internal/model"
+)
+
+func testBribes() []model.SlotBribe {
+	bribes := make([]model.SlotBribe, 40)
+	for i := range bribes {
+		bribes[i] = model.SlotBribe{
+			Slot:          uint64(100 + i),
+			ValueWei:      big.NewInt(int64(i%7+1) * 1e16),
+			BuilderPubkey: []string{"0xA", "0xB", "0xC"}[i%3],
+		}
+	}
+	return bribes
+}
+
+// TestCharts verifies each chart renders as SVG and PNG.
+func TestCharts(t *testing.T) {
+	bribes := testBribes()
+	stats := analysis.NewStatistics(bribes)
+	rolling, err := RollingCost(stats.ComputeRollingStats(10))
+	if err != nil {
+		t.Fatalf("RollingCost failed: %v", err)
+	}
+	trends, err := ConcentrationTrends(stats.ComputeConcentrationTrends(10))
+	if err != nil {
+		t.Fatalf("ConcentrationTrends failed: %v", err)
+	}
+	grid, err := model.SweepProfitGrid(context.Background(), bribes, 10, 3, 0, 1, 5, big.NewFloat(0), big.NewFloat(2e18), 5)
+	if err != nil {
+		t.Fatalf("SweepProfitGrid failed: %v", err)
+	}
+	surface, err := ProfitSurface(grid)
+	if err != nil {
+		t.Fatalf("ProfitSurface failed: %v", err)
+	}
+
+	for name, p := range map[string]*plot.Plot{"rolling": rolling, "trends": trends, "surface": surface} {
+		var buf bytes.Buffer
+		if err := Write(&buf, p, FormatSVG); err != nil {
+			t.Fatalf("Write %s failed: %v", name, err)
+		}
+		if !strings.Contains(buf.String(), "<svg") {
+			t.Errorf("expected %s to render as SVG", name)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "surface.png")
+	if err := Save(surface, path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read chart: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("\x89PNG")) {
+		t.Errorf("expected a PNG file, got %q", data[:min(len(data), 8)])
+	}
+}
+
+// TestChartErrors verifies empty inputs and unknown formats are rejected.
+func TestChartErrors(t *testing.T) {
+	if _, err := RollingCost(nil); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+	if _, err := ConcentrationTrends(nil); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+	if _, err := ProfitSurface(&model.ProfitGrid{}); !errors.Is(err, model.ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+	if _, err := FormatForPath("chart.pdf"); err == nil {
+		t.Error("Expected error for an unknown extension, got nil")
+	}
+	if format, err := FormatForPath("plots/Cost.SVG"); err != nil || format != FormatSVG {
+		t.Errorf("expected %s, got %s (%v)", FormatSVG, format, err)
+	}
+}
@@ -0,0 +1,150 @@
+// Package currency converts between wei, ETH and USD and formats amounts
+// for display.
+//
+// Wei amounts are exact integers; conversions to ETH are carried out at
+// 256 bits of precision so that rounding only happens once, when a caller
+// asks for a float64 or a decimal string.
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// prec is the big.Float precision used for wei/ETH conversions, enough to
+// hold any uint256 amount exactly.
+const prec = 256
+
+// WeiPerETH is the number of wei in one ETH.
+const WeiPerETH = 1e18
+
+var weiPerETH = big.NewInt(WeiPerETH)
+
+// ErrStalePrice is returned by a MaxAge provider when the latest quote is
+// older than the allowed age.
+var ErrStalePrice = errors.New("price quote too old")
+
+// WeiToETH converts a wei amount to ETH.
+func WeiToETH(wei *big.Int) *big.Float {
+	return FloatWeiToETH(new(big.Float).SetPrec(prec).SetInt(wei))
+}
+
+// FloatWeiToETH converts a fractional wei amount, such as an effective cost
+// scaled by 1-α, to ETH.
+func FloatWeiToETH(wei *big.Float) *big.Float {
+	return new(big.Float).SetPrec(prec).Quo(wei, new(big.Float).SetPrec(prec).SetInt(weiPerETH))
+}
+
+// WeiToETHFloat64 converts a wei amount to float64 ETH, rounded once to the
+// nearest float64.
+func WeiToETHFloat64(wei *big.Int) float64 {
+	eth, _ := WeiToETH(wei).Float64()
+	return eth
+}
+
+// ETHToWei converts an ETH amount to wei, keeping any fraction of a wei.
+func ETHToWei(eth float64) *big.Float {
+	return new(big.Float).SetPrec(prec).Mul(big.NewFloat(eth), new(big.Float).SetInt(weiPerETH))
+}
+
+// USDToWei converts a USD amount to wei at usdPerETH.
+func USDToWei(usd, usdPerETH float64) *big.Float {
+	return ETHToWei(usd / usdPerETH)
+}
+
+// ETHToUSD values an ETH amount at usdPerETH.
+func ETHToUSD(eth *big.Float, usdPerETH float64) *big.Float {
+	return new(big.Float).SetPrec(prec).Mul(eth, big.NewFloat(usdPerETH))
+}
+
+// WeiToUSD values a wei amount at usdPerETH.
+func WeiToUSD(wei *big.Int, usdPerETH float64) float64 {
+	usd, _ := ETHToUSD(WeiToETH(wei), usdPerETH).Float64()
+	return usd
+}
+
+// ParseETH parses a decimal ETH amount such as "1.5" into exact wei. It
+// fails on amounts with more than 18 decimal places.
+func ParseETH(s string) (*big.Int, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid ETH amount %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(weiPerETH))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("ETH amount %q has more than 18 decimal places", s)
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// FormatETH formats a wei amount in ETH with the given number of decimal
+// places, rounding the exact value half away from zero.
+func FormatETH(wei *big.Int, decimals int) string {
+	return new(big.Rat).SetFrac(wei, weiPerETH).FloatString(decimals)
+}
+
+// FormatCompact formats a value with two decimals and a K, M or B suffix,
+// for amounts that span many orders of magnitude.
+func FormatCompact(f *big.Float) string {
+	val, _ := f.Float64()
+	if val >= 1e9 {
+		return fmt.Sprintf("%.2fB", val/1e9)
+	} else if val >= 1e6 {
+		return fmt.Sprintf("%.2fM", val/1e6)
+	} else if val >= 1e3 {
+		return fmt.Sprintf("%.2fK", val/1e3)
+	}
+	return fmt.Sprintf("%.2f", val)
+}
+
+// PriceProvider returns the ETH/USD quote in effect at a point in time.
+// storage.Store satisfies it with stored quotes.
+type PriceProvider interface {
+	PriceAt(ctx context.Context, at time.Time) (model.PriceQuote, error)
+}
+
+// FixedPrice is a PriceProvider that quotes the same USD price at every
+// time, for command-line flags and reference scenarios.
+type FixedPrice float64
+
+// PriceAt returns the fixed price, timestamped at.
+func (p FixedPrice) PriceAt(_ context.Context, at time.Time) (model.PriceQuote, error) {
+	return model.PriceQuote{Timestamp: at, USD: float64(p), Source: "fixed"}, nil
+}
+
+// MaxAge wraps a PriceProvider so that quotes taken more than maxAge before
+// the requested time fail with ErrStalePrice.
+func MaxAge(provider PriceProvider, maxAge time.Duration) PriceProvider {
+	return maxAgeProvider{provider: provider, maxAge: maxAge}
+}
+
+type maxAgeProvider struct {
+	provider PriceProvider
+	maxAge   time.Duration
+}
+
+func (p maxAgeProvider) PriceAt(ctx context.Context, at time.Time) (model.PriceQuote, error) {
+	quote, err := p.provider.PriceAt(ctx, at)
+	if err != nil {
+		return model.PriceQuote{}, err
+	}
+	if at.Sub(quote.Timestamp) > p.maxAge {
+		return model.PriceQuote{}, fmt.Errorf("%w: quoted at %s for %s", ErrStalePrice, quote.Timestamp, at)
+	}
+	return quote, nil
+}
+
+// ValueUSD values a wei amount at the provider's quote for at, returning
+// the quote used.
+func ValueUSD(ctx context.Context, provider PriceProvider, wei *big.Int, at time.Time) (float64, model.PriceQuote, error) {
+	quote, err := provider.PriceAt(ctx, at)
+	if err != nil {
+		return 0, model.PriceQuote{}, fmt.Errorf("failed to get ETH price: %w", err)
+	}
+	return WeiToUSD(wei, quote.USD), quote, nil
+}
@@ -0,0 +1,104 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// TestWeiToETH verifies conversions round only once, at the float64.
+func TestWeiToETH(t *testing.T) {
+	if got := WeiToETHFloat64(big.NewInt(1500000000000000000)); got != 1.5 {
+		t.Errorf("expected 1.5 ETH, got %v", got)
+	}
+
+	// 1 wei survives the conversion to ETH and back
+	wei := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 200), big.NewInt(1))
+	back, _ := new(big.Float).Mul(WeiToETH(wei), new(big.Float).SetInt(weiPerETH)).Int(nil)
+	if back.Cmp(wei) != 0 {
+		t.Errorf("expected %s wei after round trip, got %s", wei, back)
+	}
+
+	if got := WeiToUSD(big.NewInt(2e18), 3000); got != 6000 {
+		t.Errorf("expected $6000, got %v", got)
+	}
+	if got, _ := USDToWei(3000, 3000).Int(nil); got.Cmp(weiPerETH) != 0 {
+		t.Errorf("expected 1e18 wei, got %s", got)
+	}
+}
+
+// TestParseAndFormatETH verifies decimal ETH strings convert to exact wei
+// and back.
+func TestParseAndFormatETH(t *testing.T) {
+	wei, err := ParseETH("1.000000000000000001")
+	if err != nil {
+		t.Fatalf("ParseETH failed: %v", err)
+	}
+	if wei.String() != "1000000000000000001" {
+		t.Errorf("expected 1000000000000000001 wei, got %s", wei)
+	}
+	if got := FormatETH(wei, 18); got != "1.000000000000000001" {
+		t.Errorf("expected 1.000000000000000001, got %s", got)
+	}
+	if got := FormatETH(big.NewInt(1250000000000000000), 1); got != "1.3" {
+		t.Errorf("expected 1.3, got %s", got)
+	}
+
+	for _, bad := range []string{"abc", "0.0000000000000000001"} {
+		if _, err := ParseETH(bad); err == nil {
+			t.Errorf("Expected error for %q, got nil", bad)
+		}
+	}
+}
+
+// TestFormatCompact verifies the magnitude suffixes.
+func TestFormatCompact(t *testing.T) {
+	cases := map[float64]string{
+		12.345:        "12.35",
+		12_345:        "12.35K",
+		12_345_678:    "12.35M",
+		1_234_567_890: "1.23B",
+	}
+	for val, want := range cases {
+		if got := FormatCompact(big.NewFloat(val)); got != want {
+			t.Errorf("expected %s for %v, got %s", want, val, got)
+		}
+	}
+}
+
+// quotes is a PriceProvider returning one stored quote.
+type quotes struct{ quote model.PriceQuote }
+
+func (q quotes) PriceAt(_ context.Context, at time.Time) (model.PriceQuote, error) {
+	if at.Before(q.quote.Timestamp) {
+		return model.PriceQuote{}, errors.New("not found")
+	}
+	return q.quote, nil
+}
+
+// TestValueUSD verifies USD valuation through providers, including stale
+// quotes rejected by MaxAge.
+func TestValueUSD(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	usd, quote, err := ValueUSD(ctx, FixedPrice(2500), big.NewInt(2e18), now)
+	if err != nil {
+		t.Fatalf("ValueUSD failed: %v", err)
+	}
+	if usd != 5000 || quote.Source != "fixed" || !quote.Timestamp.Equal(now) {
+		t.Errorf("expected $5000 from a fixed quote at %s, got $%v from %+v", now, usd, quote)
+	}
+
+	stored := quotes{model.PriceQuote{Timestamp: now.Add(-2 * time.Hour), USD: 2000}}
+	if _, _, err := ValueUSD(ctx, MaxAge(stored, 3*time.Hour), big.NewInt(1e18), now); err != nil {
+		t.Errorf("expected a fresh quote, got %v", err)
+	}
+	if _, _, err := ValueUSD(ctx, MaxAge(stored, time.Hour), big.NewInt(1e18), now); !errors.Is(err, ErrStalePrice) {
+		t.Errorf("expected ErrStalePrice, got %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+package io
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"insolventbydesign/internal/analysis"
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// SummaryColumns are the leading columns of an encoded analysis.Summary;
+// summaries with bootstrap intervals add SummaryCIColumns.
+var SummaryColumns = []Column{
+	{"count", ColumnInt},
+	{"mean_eth", ColumnFloat},
+	{"median_eth", ColumnFloat},
+	{"stddev_eth", ColumnFloat},
+	{"min_eth", ColumnFloat},
+	{"max_eth", ColumnFloat},
+	{"p25_eth", ColumnFloat},
+	{"p75_eth", ColumnFloat},
+	{"p95_eth", ColumnFloat},
+	{"p99_eth", ColumnFloat},
+	{"total_eth", ColumnFloat},
+}
+
+// SummaryCIColumns are the columns of an analysis.SummaryIntervals,
+// following SummaryColumns.
+var SummaryCIColumns = []Column{
+	{"ci_confidence", ColumnFloat},
+	{"ci_resamples", ColumnInt},
+	{"ci_block_len", ColumnInt},
+	{"ci_seed", ColumnInt},
+	{"mean_lower_eth", ColumnFloat},
+	{"mean_upper_eth", ColumnFloat},
+	{"median_lower_eth", ColumnFloat},
+	{"median_upper_eth", ColumnFloat},
+	{"p25_lower_eth", ColumnFloat},
+	{"p25_upper_eth", ColumnFloat},
+	{"p75_lower_eth", ColumnFloat},
+	{"p75_upper_eth", ColumnFloat},
+	{"p95_lower_eth", ColumnFloat},
+	{"p95_upper_eth", ColumnFloat},
+	{"p99_lower_eth", ColumnFloat},
+	{"p99_upper_eth", ColumnFloat},
+}
+
+// RollingStatsColumns are the columns of encoded rolling statistics.
+var RollingStatsColumns = []Column{
+	{"slot", ColumnInt},
+	{"mean_eth", ColumnFloat},
+	{"stddev_eth", ColumnFloat},
+	{"min_eth", ColumnFloat},
+	{"max_eth", ColumnFloat},
+}
+
+// ConcentrationTrendColumns are the columns of encoded concentration
+// trends.
+var ConcentrationTrendColumns = []Column{
+	{"slot", ColumnInt},
+	{"concentration_top3", ColumnFloat},
+	{"concentration_top5", ColumnFloat},
+	{"unique_builders", ColumnInt},
+	{"herfindahl_index", ColumnFloat},
+}
+
+// HistogramColumns are the columns of an encoded analysis.Histogram.
+var HistogramColumns = []Column{
+	{"lower_eth", ColumnFloat},
+	{"upper_eth", ColumnFloat},
+	{"count", ColumnInt},
+}
+
+// ComparisonColumns are the columns of an encoded analysis.Comparison.
+var ComparisonColumns = []Column{
+	{"metric", ColumnString},
+	{"before", ColumnFloat},
+	{"after", ColumnFloat},
+	{"change", ColumnFloat},
+	{"relative_change", ColumnFloat},
+	{"test", ColumnString},
+	{"p_value", ColumnFloat},
+}
+
+// RelayStatsColumns are the columns of encoded per-relay statistics.
+var RelayStatsColumns = []Column{
+	{"relay_url", ColumnString},
+	{"slots", ColumnInt},
+	{"coverage", ColumnFloat},
+	{"mean_bid_eth", ColumnFloat},
+	{"unique_builders", ColumnInt},
+	{"top_builder", ColumnString},
+	{"top_builder_share", ColumnFloat},
+	{"builder_hhi", ColumnFloat},
+	{"shared_slots", ColumnInt},
+	{"disagreements", ColumnInt},
+	{"disagreement_rate", ColumnFloat},
+	{"mean_deviation_eth", ColumnFloat},
+}
+
+// SuccessCurveColumns are the columns of an encoded model.SuccessCurve.
+var SuccessCurveColumns = []Column{
+	{"tau", ColumnInt},
+	{"probability", ColumnFloat},
+	{"lower", ColumnFloat},
+	{"upper", ColumnFloat},
+	{"at_risk", ColumnInt},
+}
+
+// MonteCarloColumns are the leading columns of an encoded
+// analysis.MonteCarloResult; var_<level>_usd and cvar_<level>_usd columns
+// follow for each tail risk level, e.g. var_95_usd, then a
+// p_loss_over_<threshold>_usd column for each loss threshold.
+var MonteCarloColumns = []Column{
+	{"seed", ColumnInt},
+	{"expected_profit_usd", ColumnFloat},
+	{"profit_stddev_usd", ColumnFloat},
+	{"probability_profitable", ColumnFloat},
+	{"median_profit_usd", ColumnFloat},
+	{"max_profit_usd", ColumnFloat},
+	{"max_loss_usd", ColumnFloat},
+	{"profit_skewness", ColumnFloat},
+	{"profit_kurtosis", ColumnFloat},
+	{"mean_cost_eth", ColumnFloat},
+	{"cost_p5_eth", ColumnFloat},
+	{"cost_p95_eth", ColumnFloat},
+	{"unshocked_expected_profit_usd", ColumnFloat},
+	{"mean_price_drop", ColumnFloat},
+}
+
+// EncodeSummary returns summary as a single row, with its bootstrap
+// intervals if it has them.
+func EncodeSummary(summary analysis.Summary) Table {
+	row := []any{
+		int64(summary.Count),
+		summary.MeanETH,
+		summary.MedianETH,
+		summary.StdDevETH,
+		summary.MinETH,
+		summary.MaxETH,
+		summary.P25ETH,
+		summary.P75ETH,
+		summary.P95ETH,
+		summary.P99ETH,
+		summary.TotalETH,
+	}
+	ci := summary.CI
+	if ci == nil {
+		return Table{Columns: SummaryColumns, Rows: [][]any{row}}
+	}
+	row = append(row,
+		ci.Confidence,
+		int64(ci.Resamples),
+		int64(ci.BlockLen),
+		ci.Seed,
+	)
+	for _, interval := range []analysis.ConfidenceInterval{ci.MeanETH, ci.MedianETH, ci.P25ETH, ci.P75ETH, ci.P95ETH, ci.P99ETH} {
+		row = append(row, interval.Lower, interval.Upper)
+	}
+	columns := append(append([]Column{}, SummaryColumns...), SummaryCIColumns...)
+	return Table{Columns: columns, Rows: [][]any{row}}
+}
+
+// EncodeRollingStats returns one row per window, by its last slot.
+func EncodeRollingStats(rolling []analysis.RollingStatistics) Table {
+	t := Table{Columns: RollingStatsColumns, Rows: make([][]any, 0, len(rolling))}
+	for _, r := range rolling {
+		t.Rows = append(t.Rows, []any{int64(r.Slot), r.MeanETH, r.StdDevETH, r.MinETH, r.MaxETH})
+	}
+	return t
+}
+
+// EncodeConcentrationTrends returns one row per window, by its last slot.
+func EncodeConcentrationTrends(trends []analysis.ConcentrationTrend) Table {
+	t := Table{Columns: ConcentrationTrendColumns, Rows: make([][]any, 0, len(trends))}
+	for _, c := range trends {
+		t.Rows = append(t.Rows, []any{
+			int64(c.Slot),
+			c.ConcentrationTop3,
+			c.ConcentrationTop5,
+			int64(c.UniqueBuilders),
+			c.HerfindahlIndex,
+		})
+	}
+	return t
+}
+
+// EncodeHistogram returns one row per bin. Bids outside the bins are not
+// encoded.
+func EncodeHistogram(h analysis.Histogram) Table {
+	t := Table{Columns: HistogramColumns, Rows: make([][]any, 0, len(h.Counts))}
+	for i, count := range h.Counts {
+		t.Rows = append(t.Rows, []any{h.Edges[i], h.Edges[i+1], int64(count)})
+	}
+	return t
+}
+
+// EncodeComparison returns one row per metric. Untested metrics have an
+// empty test and a NaN p-value.
+func EncodeComparison(c *analysis.Comparison) Table {
+	t := Table{Columns: ComparisonColumns, Rows: make([][]any, 0, len(c.Metrics))}
+	for _, m := range c.Metrics {
+		t.Rows = append(t.Rows, []any{m.Metric, m.Before, m.After, m.Change, m.RelativeChange, string(m.Test), m.PValue})
+	}
+	return t
+}
+
+// EncodeRelayComparison returns one row per relay.
+func EncodeRelayComparison(c *model.RelayComparison) Table {
+	t := Table{Columns: RelayStatsColumns, Rows: make([][]any, 0, len(c.Relays))}
+	for _, r := range c.Relays {
+		t.Rows = append(t.Rows, []any{
+			r.RelayURL,
+			int64(r.Slots),
+			r.Coverage,
+			currency.WeiToETHFloat64(r.MeanBidWei),
+			int64(r.UniqueBuilders),
+			r.TopBuilder,
+			r.TopBuilderShare,
+			r.BuilderHHI,
+			int64(r.SharedSlots),
+			int64(r.Disagreements),
+			r.DisagreementRate,
+			currency.WeiToETHFloat64(r.MeanDeviationWei),
+		})
+	}
+	return t
+}
+
+// EncodeSuccessCurve returns one row per step of the curve.
+func EncodeSuccessCurve(c *model.SuccessCurve) Table {
+	t := Table{Columns: SuccessCurveColumns, Rows: make([][]any, 0, len(c.Points))}
+	for _, p := range c.Points {
+		t.Rows = append(t.Rows, []any{int64(p.Tau), p.Probability, p.Lower, p.Upper, int64(p.AtRisk)})
+	}
+	return t
+}
+
+// EncodeMonteCarloResult returns result as a single row. Levels name their
+// columns in percent with "_" for the decimal point, so 0.995 becomes
+// var_99_5_usd; loss thresholds are named in plain decimal USD, so 1e6
+// becomes p_loss_over_1000000_usd.
+func EncodeMonteCarloResult(result analysis.MonteCarloResult) Table {
+	columns := append([]Column(nil), MonteCarloColumns...)
+	row := []any{
+		result.Seed,
+		result.ExpectedProfit,
+		result.ProfitStdDev,
+		result.ProbabilityProfitable,
+		result.MedianProfit,
+		result.MaxProfit,
+		result.MaxLoss,
+		result.ProfitSkewness,
+		result.ProfitKurtosis,
+		result.MeanCostETH,
+		result.CostP5ETH,
+		result.CostP95ETH,
+		result.UnshockedExpectedProfit,
+		result.MeanPriceDrop,
+	}
+	for _, tail := range result.TailRisk {
+		// Round away float noise such as 0.57*100 = 56.99999999999999
+		percent := math.Round(tail.Confidence*1e8) / 1e6
+		level := strings.ReplaceAll(formatFloat64(percent), ".", "_")
+		columns = append(columns,
+			Column{"var_" + level + "_usd", ColumnFloat},
+			Column{"cvar_" + level + "_usd", ColumnFloat})
+		row = append(row, tail.VaR, tail.CVaR)
+	}
+	for _, loss := range result.LossProbabilities {
+		threshold := strings.ReplaceAll(strconv.FormatFloat(loss.ThresholdUSD, 'f', -1, 64), ".", "_")
+		columns = append(columns, Column{"p_loss_over_" + threshold + "_usd", ColumnFloat})
+		row = append(row, loss.Probability)
+	}
+	return Table{Columns: columns, Rows: [][]any{row}}
+}
@@ -0,0 +1,86 @@
+package io
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"insolventbydesign/internal/model"
+)
+
+// DatasetColumns are the columns of an exported bribe dataset, in order.
+// value_wei is a base-10 string, so values beyond 64 bits survive any
+// reader unchanged.
+var DatasetColumns = []string{"slot", "builder_pubkey", "builder_entity", "value_wei"}
+
+// Dataset file formats.
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// DatasetWriter writes delivered payloads as rows of a dataset file.
+type DatasetWriter interface {
+	// Write appends one row. Bribes with a nil value are rejected.
+	Write(bribe model.SlotBribe) error
+
+	// Close writes any buffered rows and trailer. It does not close the
+	// underlying writer.
+	Close() error
+}
+
+// NewDatasetWriter returns a writer for format (FormatCSV or FormatParquet).
+func NewDatasetWriter(w io.Writer, format string) (DatasetWriter, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatParquet:
+		return NewParquetWriter(w, 0), nil
+	default:
+		return nil, fmt.Errorf("unknown dataset format '%s' (want %s or %s)", format, FormatCSV, FormatParquet)
+	}
+}
+
+// csvWriter writes a header row followed by one row per bribe.
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVWriter returns a DatasetWriter producing RFC 4180 CSV with a
+// header row.
+func NewCSVWriter(w io.Writer) DatasetWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+	c.header = true
+	return c.w.Write(DatasetColumns)
+}
+
+func (c *csvWriter) Write(bribe model.SlotBribe) error {
+	if bribe.ValueWei == nil {
+		return fmt.Errorf("nil ValueWei for slot %d", bribe.Slot)
+	}
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.w.Write([]string{
+		strconv.FormatUint(bribe.Slot, 10),
+		bribe.BuilderPubkey,
+		bribe.BuilderEntity,
+		bribe.ValueWei.String(),
+	})
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
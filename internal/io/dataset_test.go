@@ -0,0 +1,209 @@
+package io
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+func testBribes() []model.SlotBribe {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	return []model.SlotBribe{
+		{Slot: 100, ValueWei: huge, BuilderPubkey: "0xA", BuilderEntity: "Titan"},
+		{Slot: 101, ValueWei: big.NewInt(200), BuilderPubkey: "0xB"},
+		{Slot: 102, ValueWei: big.NewInt(0), BuilderPubkey: "0xA", BuilderEntity: "Titan, Inc"},
+	}
+}
+
+// expectedRows returns testBribes as dataset rows.
+func expectedRows() [][]string {
+	var rows [][]string
+	for _, b := range testBribes() {
+		rows = append(rows, []string{strconv.FormatUint(b.Slot, 10), b.BuilderPubkey, b.BuilderEntity, b.ValueWei.String()})
+	}
+	return rows
+}
+
+// TestCSVWriter verifies the header and that wei values are exact strings.
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf)
+	for _, b := range testBribes() {
+		if err := w.Write(b); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	want := append([][]string{DatasetColumns}, expectedRows()...)
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("expected %v, got %v", want, records)
+	}
+
+	if err := w.Write(model.SlotBribe{Slot: 1}); err == nil {
+		t.Error("Expected error for nil ValueWei, got nil")
+	}
+}
+
+// compactReader decodes Thrift compact structs into maps keyed by field id.
+type compactReader struct {
+	b   []byte
+	pos int
+	t   *testing.T
+}
+
+func (r *compactReader) varint() uint64 {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	if n <= 0 {
+		r.t.Fatalf("bad varint at %d", r.pos)
+	}
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(typ byte) any {
+	switch typ {
+	case compactI32, compactI64:
+		return r.zigzag()
+	case compactBinary:
+		n := int(r.varint())
+		s := string(r.b[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case compactList:
+		header := r.b[r.pos]
+		r.pos++
+		n, elem := int(header>>4), header&0x0f
+		if n == 15 {
+			n = int(r.varint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.value(elem)
+		}
+		return list
+	case compactStruct:
+		return r.readStruct()
+	}
+	r.t.Fatalf("unsupported compact type %d at %d", typ, r.pos)
+	return nil
+}
+
+func (r *compactReader) readStruct() map[int16]any {
+	fields := make(map[int16]any)
+	var last int16
+	for {
+		header := r.b[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+}
+
+// readParquet decodes a file written by parquetWriter into its column
+// names and string-formatted rows, checking the footer as it goes.
+func readParquet(t *testing.T, file []byte) ([]string, [][]string) {
+	if string(file[:4]) != parquetMagic || string(file[len(file)-4:]) != parquetMagic {
+		t.Fatalf("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := &compactReader{b: file[len(file)-8-footerLen : len(file)-8], t: t}
+	meta := footer.readStruct()
+
+	var names []string
+	for _, e := range meta[2].([]any)[1:] {
+		names = append(names, e.(map[int16]any)[4].(string))
+	}
+
+	var rows [][]string
+	for _, g := range meta[4].([]any) {
+		group := g.(map[int16]any)
+		n := int(group[3].(int64))
+		groupRows := make([][]string, n)
+		for _, c := range group[1].([]any) {
+			columnMeta := c.(map[int16]any)[3].(map[int16]any)
+			if columnMeta[5].(int64) != int64(n) {
+				t.Fatalf("expected %d values in column chunk, got %d", n, columnMeta[5])
+			}
+			page := &compactReader{b: file, pos: int(columnMeta[9].(int64)), t: t}
+			header := page.readStruct()
+			data := file[page.pos : page.pos+int(header[3].(int64))]
+			for i := 0; i < n; i++ {
+				switch columnMeta[1].(int64) {
+				case parquetInt64:
+					groupRows[i] = append(groupRows[i], strconv.FormatInt(int64(binary.LittleEndian.Uint64(data)), 10))
+					data = data[8:]
+					continue
+				case parquetDouble:
+					groupRows[i] = append(groupRows[i], formatFloat64(math.Float64frombits(binary.LittleEndian.Uint64(data))))
+					data = data[8:]
+					continue
+				}
+				size := int(binary.LittleEndian.Uint32(data))
+				groupRows[i] = append(groupRows[i], string(data[4:4+size]))
+				data = data[4+size:]
+			}
+		}
+		rows = append(rows, groupRows...)
+	}
+	if int(meta[3].(int64)) != len(rows) {
+		t.Errorf("expected num_rows %d, got %d", len(rows), meta[3])
+	}
+	return names, rows
+}
+
+// TestParquetWriter verifies the file decodes back to the input rows
+// across several row groups, and that an empty dataset is well formed.
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf, 2)
+	for _, b := range testBribes() {
+		if err := w.Write(b); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	names, rows := readParquet(t, buf.Bytes())
+	if !reflect.DeepEqual(names, DatasetColumns) {
+		t.Errorf("expected columns %v, got %v", DatasetColumns, names)
+	}
+	if want := expectedRows(); !reflect.DeepEqual(rows, want) {
+		t.Errorf("expected rows %v, got %v", want, rows)
+	}
+
+	buf.Reset()
+	if err := NewParquetWriter(&buf, 0).Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, rows := readParquet(t, buf.Bytes()); len(rows) != 0 {
+		t.Errorf("expected no rows, got %v", rows)
+	}
+}
@@ -0,0 +1,352 @@
+package io
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"insolventbydesign/internal/model"
+)
+
+// DefaultParquetRowGroupSize is the number of rows buffered per row group.
+const DefaultParquetRowGroupSize = 100_000
+
+// Parquet format constants (parquet.thrift).
+const (
+	parquetMagic = "PAR1"
+
+	parquetInt64     = 2 // Type.INT64
+	parquetDouble    = 5 // Type.DOUBLE
+	parquetByteArray = 6 // Type.BYTE_ARRAY
+
+	parquetRequired     = 0 // FieldRepetitionType.REQUIRED
+	parquetUTF8         = 0 // ConvertedType.UTF8
+	parquetDataPage     = 0 // PageType.DATA_PAGE
+	parquetPlain        = 0 // Encoding.PLAIN
+	parquetRLE          = 3 // Encoding.RLE
+	parquetUncompressed = 0 // CompressionCodec.UNCOMPRESSED
+)
+
+// parquetColumn describes one column of a file's schema.
+type parquetColumn struct {
+	name     string
+	physical int32
+	utf8     bool
+}
+
+// datasetSchema matches DatasetColumns.
+var datasetSchema = []parquetColumn{
+	{name: "slot", physical: parquetInt64},
+	{name: "builder_pubkey", physical: parquetByteArray, utf8: true},
+	{name: "builder_entity", physical: parquetByteArray, utf8: true},
+	{name: "value_wei", physical: parquetByteArray, utf8: true},
+}
+
+// parquetChunk locates one column chunk within the file.
+type parquetChunk struct {
+	offset int64 // Data page header offset
+	size   int64 // Page header and data
+}
+
+// parquetRowGroup records a written row group for the footer.
+type parquetRowGroup struct {
+	chunks []parquetChunk
+	rows   int64
+	size   int64
+}
+
+// parquetWriter writes an uncompressed, PLAIN-encoded Parquet file with
+// one data page per column chunk. All columns are required, so pages
+// carry no definition or repetition levels.
+//
+// Rows are buffered column-wise until a row group is full; the footer
+// listing every row group is written by Close.
+type parquetWriter struct {
+	w            io.Writer
+	schema       []parquetColumn
+	offset       int64
+	rowGroupSize int
+	columns      [][]byte // Encoded values of the buffered row group
+	rows         int
+	rowGroups    []parquetRowGroup
+	totalRows    int64
+	err          error // Sticky write error
+}
+
+// NewParquetWriter returns a DatasetWriter producing a Parquet file with
+// rowGroupSize rows per row group (0 selects DefaultParquetRowGroupSize).
+// Strings are annotated UTF8 and slots are INT64.
+func NewParquetWriter(w io.Writer, rowGroupSize int) DatasetWriter {
+	return newParquetWriter(w, rowGroupSize, datasetSchema)
+}
+
+func newParquetWriter(w io.Writer, rowGroupSize int, schema []parquetColumn) *parquetWriter {
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultParquetRowGroupSize
+	}
+	return &parquetWriter{
+		w:            w,
+		schema:       schema,
+		rowGroupSize: rowGroupSize,
+		columns:      make([][]byte, len(schema)),
+	}
+}
+
+func (p *parquetWriter) write(b []byte) {
+	if p.err != nil {
+		return
+	}
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	if err != nil {
+		p.err = fmt.Errorf("failed to write parquet: %w", err)
+	}
+}
+
+func (p *parquetWriter) Write(bribe model.SlotBribe) error {
+	if bribe.ValueWei == nil {
+		return fmt.Errorf("nil ValueWei for slot %d", bribe.Slot)
+	}
+	return p.writeRow([]any{bribe.Slot, bribe.BuilderPubkey, bribe.BuilderEntity, bribe.ValueWei.String()})
+}
+
+// writeRow appends one value per schema column: an int64 or uint64 for
+// INT64, a float64 for DOUBLE and a string for BYTE_ARRAY.
+func (p *parquetWriter) writeRow(values []any) error {
+	if p.err != nil {
+		return p.err
+	}
+	if len(values) != len(p.schema) {
+		return fmt.Errorf("expected %d values, got %d", len(p.schema), len(values))
+	}
+	// Check the whole row first so a bad value leaves the columns aligned
+	for i, column := range p.schema {
+		var ok bool
+		switch column.physical {
+		case parquetInt64:
+			switch values[i].(type) {
+			case int64, uint64:
+				ok = true
+			}
+		case parquetDouble:
+			_, ok = values[i].(float64)
+		case parquetByteArray:
+			_, ok = values[i].(string)
+		}
+		if !ok {
+			return fmt.Errorf("invalid value %v (%T) for column %s", values[i], values[i], column.name)
+		}
+	}
+	for i, value := range values {
+		switch v := value.(type) {
+		case int64:
+			p.columns[i] = binary.LittleEndian.AppendUint64(p.columns[i], uint64(v))
+		case uint64:
+			p.columns[i] = binary.LittleEndian.AppendUint64(p.columns[i], v)
+		case float64:
+			p.columns[i] = binary.LittleEndian.AppendUint64(p.columns[i], math.Float64bits(v))
+		case string:
+			p.columns[i] = appendByteArray(p.columns[i], v)
+		}
+	}
+	if p.offset == 0 {
+		p.write([]byte(parquetMagic))
+	}
+	p.rows++
+
+	if p.rows >= p.rowGroupSize {
+		p.flushRowGroup()
+	}
+	return p.err
+}
+
+// appendByteArray PLAIN-encodes s: a 4-byte little-endian length, then the bytes.
+func appendByteArray(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// flushRowGroup writes the buffered rows as a row group.
+func (p *parquetWriter) flushRowGroup() {
+	if p.rows == 0 {
+		return
+	}
+
+	group := parquetRowGroup{rows: int64(p.rows)}
+	for i, data := range p.columns {
+		header := newCompactWriter()
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(data))) // Uncompressed size
+		header.i32(3, int32(len(data))) // Compressed size
+		header.beginStruct(5)           // DataPageHeader
+		header.i32(1, int32(p.rows))
+		header.i32(2, parquetPlain)
+		header.i32(3, parquetRLE)
+		header.i32(4, parquetRLE)
+		header.endStruct()
+		header.endStruct()
+
+		chunk := parquetChunk{offset: p.offset, size: int64(header.buf.Len() + len(data))}
+		p.write(header.buf.Bytes())
+		p.write(data)
+		group.chunks = append(group.chunks, chunk)
+		group.size += chunk.size
+		p.columns[i] = data[:0]
+	}
+
+	p.rowGroups = append(p.rowGroups, group)
+	p.totalRows += group.rows
+	p.rows = 0
+}
+
+func (p *parquetWriter) Close() error {
+	if p.offset == 0 {
+		p.write([]byte(parquetMagic))
+	}
+	p.flushRowGroup()
+
+	footer := newCompactWriter()
+	footer.i32(1, 1) // Version
+	footer.beginList(2, compactStruct, len(p.schema)+1)
+	footer.beginElement()
+	footer.binary(4, "schema")
+	footer.i32(5, int32(len(p.schema)))
+	footer.endStruct()
+	for _, column := range p.schema {
+		footer.beginElement()
+		footer.i32(1, column.physical)
+		footer.i32(3, parquetRequired)
+		footer.binary(4, column.name)
+		if column.utf8 {
+			footer.i32(6, parquetUTF8)
+		}
+		footer.endStruct()
+	}
+	footer.i64(3, p.totalRows)
+	footer.beginList(4, compactStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		footer.beginElement()
+		footer.beginList(1, compactStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			column := p.schema[i]
+			footer.beginElement()
+			footer.i64(2, chunk.offset) // File offset
+			footer.beginStruct(3)       // ColumnMetaData
+			footer.i32(1, column.physical)
+			footer.beginList(2, compactI32, 1)
+			footer.elementI32(parquetPlain)
+			footer.beginList(3, compactBinary, 1)
+			footer.elementBinary(column.name)
+			footer.i32(4, parquetUncompressed)
+			footer.i64(5, group.rows)
+			footer.i64(6, chunk.size)
+			footer.i64(7, chunk.size)
+			footer.i64(9, chunk.offset) // Data page offset
+			footer.endStruct()
+			footer.endStruct()
+		}
+		footer.i64(2, group.size)
+		footer.i64(3, group.rows)
+		footer.endStruct()
+	}
+	footer.binary(6, "insolventbydesign")
+	footer.endStruct()
+
+	p.write(footer.buf.Bytes())
+	p.write(binary.LittleEndian.AppendUint32(nil, uint32(footer.buf.Len())))
+	p.write([]byte(parquetMagic))
+	return p.err
+}
+
+// Thrift compact protocol type ids.
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes Thrift structs in the compact protocol used by
+// Parquet metadata. Only the types the footer and page headers need are
+// supported. Fields must be written in increasing id order.
+type compactWriter struct {
+	buf  bytes.Buffer
+	last []int16 // Last field id of each open struct
+}
+
+// newCompactWriter starts the outermost struct; end it with endStruct.
+func newCompactWriter() *compactWriter {
+	return &compactWriter{last: []int16{0}}
+}
+
+func (c *compactWriter) varint(v uint64) {
+	c.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func (c *compactWriter) field(id int16, typ byte) {
+	top := len(c.last) - 1
+	if delta := id - c.last[top]; delta > 0 && delta <= 15 {
+		c.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		c.buf.WriteByte(typ)
+		c.varint(zigzag(int64(id)))
+	}
+	c.last[top] = id
+}
+
+func (c *compactWriter) i32(id int16, v int32) {
+	c.field(id, compactI32)
+	c.varint(zigzag(int64(v)))
+}
+
+func (c *compactWriter) i64(id int16, v int64) {
+	c.field(id, compactI64)
+	c.varint(zigzag(v))
+}
+
+func (c *compactWriter) binary(id int16, s string) {
+	c.field(id, compactBinary)
+	c.elementBinary(s)
+}
+
+func (c *compactWriter) beginStruct(id int16) {
+	c.field(id, compactStruct)
+	c.last = append(c.last, 0)
+}
+
+func (c *compactWriter) endStruct() {
+	c.buf.WriteByte(0) // Stop field
+	c.last = c.last[:len(c.last)-1]
+}
+
+// beginList writes a list field header; follow it with n elements.
+func (c *compactWriter) beginList(id int16, elem byte, n int) {
+	c.field(id, compactList)
+	if n < 15 {
+		c.buf.WriteByte(byte(n)<<4 | elem)
+	} else {
+		c.buf.WriteByte(0xF0 | elem)
+		c.varint(uint64(n))
+	}
+}
+
+// beginElement starts a struct list element; end it with endStruct.
+func (c *compactWriter) beginElement() {
+	c.last = append(c.last, 0)
+}
+
+func (c *compactWriter) elementI32(v int32) {
+	c.varint(zigzag(int64(v)))
+}
+
+func (c *compactWriter) elementBinary(s string) {
+	c.varint(uint64(len(s)))
+	c.buf.WriteString(s)
+}
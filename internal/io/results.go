@@ -0,0 +1,152 @@
+package io
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"insolventbydesign/internal/model"
+)
+
+// ProfitColumns are the columns of an encoded ProfitResult, in order.
+// Wei amounts are exact decimal strings (see model.ExactDecimal) and
+// float64 parameters use the shortest representation that round-trips.
+var ProfitColumns = []string{
+	"success_prob", "tvl_wei", "alpha",
+	"expected_revenue_wei", "effective_cost_wei", "profit_wei",
+	"coordination_cost_wei", "expected_penalty_wei",
+	"discounted_revenue_wei", "discounted_cost_wei", "discounted_profit_wei",
+}
+
+// TauSweepColumns are the columns of an encoded SweepTau result.
+var TauSweepColumns = []string{
+	"tau", "censorship_cost_wei", "effective_cost_wei", "breakeven_tvl_wei", "alpha", "success_prob",
+}
+
+// LorenzColumns are the columns of encoded Lorenz curves, in order.
+var LorenzColumns = []string{"metric", "builder_share", "share"}
+
+// EncodeProfitResults returns a header row followed by one row per result.
+func EncodeProfitResults(results []model.ProfitResult) [][]string {
+	records := make([][]string, 0, len(results)+1)
+	records = append(records, ProfitColumns)
+	for _, r := range results {
+		records = append(records, profitRecord(r))
+	}
+	return records
+}
+
+// EncodeProfitSweep encodes a SweepProbability result. A SweepTVL
+// result's Results encode the same way with EncodeProfitResults.
+func EncodeProfitSweep(sweep *model.ProfitSweepResult) [][]string {
+	return EncodeProfitResults(sweep.Results)
+}
+
+// EncodeProfitGrid encodes every cell of a grid in long form, one row per
+// (p, V) pair with rows of the grid in order, so it loads directly into
+// tools that pivot or plot heatmaps.
+func EncodeProfitGrid(grid *model.ProfitGrid) [][]string {
+	var cells []model.ProfitResult
+	for _, row := range grid.Results {
+		cells = append(cells, row...)
+	}
+	return EncodeProfitResults(cells)
+}
+
+// EncodeTauSweep returns a header row followed by one row per duration.
+func EncodeTauSweep(sweep *model.TauSweepResult) [][]string {
+	records := make([][]string, 0, len(sweep.Points)+1)
+	records = append(records, TauSweepColumns)
+	alpha := formatFloat64(sweep.Alpha)
+	p := formatFloat64(sweep.SuccessProb)
+	for _, point := range sweep.Points {
+		records = append(records, []string{
+			strconv.FormatUint(point.Tau, 10),
+			point.CensorshipCost.String(),
+			formatBigFloat(point.EffectiveCost),
+			formatBigFloat(point.Breakeven),
+			alpha,
+			p,
+		})
+	}
+	return records
+}
+
+// EncodeLorenzCurves encodes both curves in long form, blocks first, one
+// row per point, so plotting tools can draw them as two series.
+func EncodeLorenzCurves(curves model.LorenzCurves) [][]string {
+	records := make([][]string, 0, len(curves.ByBlocks)+len(curves.ByValue)+1)
+	records = append(records, LorenzColumns)
+	for _, series := range []struct {
+		metric model.ConcentrationMetric
+		points []model.LorenzPoint
+	}{{model.ConcentrationByBlocks, curves.ByBlocks}, {model.ConcentrationByValue, curves.ByValue}} {
+		for _, point := range series.points {
+			records = append(records, []string{
+				string(series.metric),
+				formatFloat64(point.BuilderShare),
+				formatFloat64(point.Share),
+			})
+		}
+	}
+	return records
+}
+
+// WriteCSV writes encoded records as RFC 4180 CSV.
+func WriteCSV(w io.Writer, records [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// WriteProfitSweepCSV writes a probability sweep as CSV.
+func WriteProfitSweepCSV(w io.Writer, sweep *model.ProfitSweepResult) error {
+	return WriteCSV(w, EncodeProfitSweep(sweep))
+}
+
+// WriteProfitGridCSV writes a profit grid as CSV.
+func WriteProfitGridCSV(w io.Writer, grid *model.ProfitGrid) error {
+	return WriteCSV(w, EncodeProfitGrid(grid))
+}
+
+// WriteTauSweepCSV writes a duration sweep as CSV.
+func WriteTauSweepCSV(w io.Writer, sweep *model.TauSweepResult) error {
+	return WriteCSV(w, EncodeTauSweep(sweep))
+}
+
+// WriteLorenzCSV writes Lorenz curves as CSV.
+func WriteLorenzCSV(w io.Writer, curves model.LorenzCurves) error {
+	return WriteCSV(w, EncodeLorenzCurves(curves))
+}
+
+func profitRecord(r model.ProfitResult) []string {
+	return []string{
+		formatFloat64(r.SuccessProb),
+		formatBigFloat(r.TVL),
+		formatFloat64(r.Alpha),
+		formatBigFloat(r.ExpectedRevenue),
+		formatBigFloat(r.EffectiveCost),
+		formatBigFloat(r.Profit),
+		formatBigFloat(r.CoordinationCost),
+		formatBigFloat(r.ExpectedPenalty),
+		formatBigFloat(r.DiscountedRevenue),
+		formatBigFloat(r.DiscountedCost),
+		formatBigFloat(r.DiscountedProfit),
+	}
+}
+
+// formatBigFloat returns f's exact decimal value, or "" when nil.
+func formatBigFloat(f *big.Float) string {
+	if f == nil {
+		return ""
+	}
+	return model.ExactDecimal(f)
+}
+
+func formatFloat64(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
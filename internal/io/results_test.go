@@ -0,0 +1,118 @@
+package io
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+func resultBribes() []model.SlotBribe {
+	return []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(3000), BuilderPubkey: "0xB"},
+		{Slot: 3, ValueWei: big.NewInt(2000), BuilderPubkey: "0xB"},
+	}
+}
+
+// TestWriteProfitSweepCSV verifies the header, one row per point and exact
+// wei values.
+func TestWriteProfitSweepCSV(t *testing.T) {
+	tvl, _ := new(big.Float).SetPrec(128).SetString("123456789012345678901234567.5")
+	sweep, err := model.SweepProbability(context.Background(), resultBribes(), tvl, 3, 1, 0.5, 1, 2)
+	if err != nil {
+		t.Fatalf("SweepProbability failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteProfitSweepCSV(&buf, sweep); err != nil {
+		t.Fatalf("WriteProfitSweepCSV failed: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	if len(records) != 3 || !reflect.DeepEqual(records[0], ProfitColumns) {
+		t.Fatalf("expected header and 2 rows, got %v", records)
+	}
+
+	// C_c = 6000, α = 2/3 so C_c^eff = 2000 up to float rounding; revenue
+	// at p = 1 is V exactly
+	row := records[2]
+	if row[0] != "1" || row[1] != "123456789012345678901234567.5" || row[3] != "123456789012345678901234567.5" {
+		t.Errorf("expected p=1 and exact TVL and revenue, got %v", row)
+	}
+	cost, ok := new(big.Float).SetString(row[4])
+	if !ok || cost.Cmp(sweep.Results[1].EffectiveCost) != 0 {
+		t.Errorf("expected effective cost %s, got %s", sweep.Results[1].EffectiveCost.String(), row[4])
+	}
+}
+
+// TestEncodeProfitGrid verifies cells are encoded row by row.
+func TestEncodeProfitGrid(t *testing.T) {
+	grid, err := model.SweepProfitGrid(context.Background(), resultBribes(), 3, 1, 0, 1, 2, big.NewFloat(0), big.NewFloat(4000), 3)
+	if err != nil {
+		t.Fatalf("SweepProfitGrid failed: %v", err)
+	}
+
+	records := EncodeProfitGrid(grid)
+	if len(records) != 1+2*3 {
+		t.Fatalf("expected header and 6 cells, got %d records", len(records))
+	}
+	expected := [][2]string{{"0", "0"}, {"0", "2000"}, {"0", "4000"}, {"1", "0"}, {"1", "2000"}, {"1", "4000"}}
+	for i, want := range expected {
+		if got := records[i+1]; got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("cell %d: expected p=%s TVL=%s, got p=%s TVL=%s", i, want[0], want[1], got[0], got[1])
+		}
+	}
+}
+
+// TestEncodeTauSweep verifies one row per duration with exact costs.
+func TestEncodeTauSweep(t *testing.T) {
+	sweep, err := model.SweepTau(context.Background(), resultBribes(), 0.5, 1, 1, 3, 2)
+	if err != nil {
+		t.Fatalf("SweepTau failed: %v", err)
+	}
+
+	records := EncodeTauSweep(sweep)
+	if len(records) != 3 || !reflect.DeepEqual(records[0], TauSweepColumns) {
+		t.Fatalf("expected header and 2 rows, got %v", records)
+	}
+	if records[1][0] != "1" || records[1][1] != "1000" || records[2][0] != "3" || records[2][1] != "6000" {
+		t.Errorf("expected C_c 1000 at tau 1 and 6000 at tau 3, got %v", records[1:])
+	}
+	if records[1][5] != "0.5" {
+		t.Errorf("expected success probability 0.5, got %s", records[1][5])
+	}
+}
+
+// TestWriteLorenzCSV verifies both curves are written in long form.
+func TestWriteLorenzCSV(t *testing.T) {
+	curves, err := model.ComputeLorenzCurves(resultBribes(), 0, 0)
+	if err != nil {
+		t.Fatalf("ComputeLorenzCurves failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLorenzCSV(&buf, curves); err != nil {
+		t.Fatalf("WriteLorenzCSV failed: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	points := curves.Builders + 1
+	if len(records) != 1+2*points || !reflect.DeepEqual(records[0], LorenzColumns) {
+		t.Fatalf("expected header and %d points per curve, got %v", points, records)
+	}
+	if got := records[points]; got[0] != "blocks" || got[1] != "1" || got[2] != "1" {
+		t.Errorf("expected the blocks curve to end at (1, 1), got %v", got)
+	}
+	if got := records[points+1]; got[0] != "value" || got[1] != "0" || got[2] != "0" {
+		t.Errorf("expected the value curve to start at the origin, got %v", got)
+	}
+}
@@ -0,0 +1,187 @@
+package io
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FormatJSON writes a Table as a JSON array with one object per row.
+const FormatJSON = "json"
+
+// ColumnType is the type of a Table column's values.
+type ColumnType int
+
+const (
+	ColumnInt    ColumnType = iota // int64 values, Parquet INT64
+	ColumnFloat                    // float64 values, Parquet DOUBLE
+	ColumnString                   // string values, Parquet UTF8
+)
+
+// Column is a named, typed column of a Table.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Table is an analysis result laid out as typed rows, so one encoder
+// serves every output format with the same column names.
+type Table struct {
+	Columns []Column
+	Rows    [][]any // One value per column, of the column's type
+}
+
+// FormatForPath returns the table format implied by path's extension:
+// .json, .parquet or .csv.
+func FormatForPath(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".parquet":
+		return FormatParquet, nil
+	default:
+		return "", fmt.Errorf("unknown output format '%s' (want .csv, .json or .parquet)", ext)
+	}
+}
+
+// WriteTable writes t in format (FormatCSV, FormatJSON or FormatParquet).
+// Floats are written in the shortest form that round-trips; JSON has no
+// NaN or infinities, so those are written as null.
+func WriteTable(w io.Writer, t Table, format string) error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, t.records())
+	case FormatJSON:
+		return writeTableJSON(w, t)
+	case FormatParquet:
+		return writeTableParquet(w, t)
+	default:
+		return fmt.Errorf("unknown table format '%s' (want %s, %s or %s)", format, FormatCSV, FormatJSON, FormatParquet)
+	}
+}
+
+// validate checks every row has one value of each column's type.
+func (t Table) validate() error {
+	for i, row := range t.Rows {
+		if len(row) != len(t.Columns) {
+			return fmt.Errorf("row %d has %d values for %d columns", i, len(row), len(t.Columns))
+		}
+		for j, column := range t.Columns {
+			var ok bool
+			switch column.Type {
+			case ColumnInt:
+				_, ok = row[j].(int64)
+			case ColumnFloat:
+				_, ok = row[j].(float64)
+			case ColumnString:
+				_, ok = row[j].(string)
+			}
+			if !ok {
+				return fmt.Errorf("row %d: invalid value %v (%T) for column %s", i, row[j], row[j], column.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// records returns a header row followed by each row's values as text.
+func (t Table) records() [][]string {
+	records := make([][]string, 0, len(t.Rows)+1)
+	header := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		header[i] = column.Name
+	}
+	records = append(records, header)
+	for _, row := range t.Rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			switch v := value.(type) {
+			case int64:
+				record[i] = strconv.FormatInt(v, 10)
+			case float64:
+				record[i] = formatFloat64(v)
+			case string:
+				record[i] = v
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// writeTableJSON writes one object per row, keys in column order.
+func writeTableJSON(w io.Writer, t Table) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[")
+	for i, row := range t.Rows {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n  {")
+		for j, value := range row {
+			if j > 0 {
+				bw.WriteString(", ")
+			}
+			name, _ := json.Marshal(t.Columns[j].Name)
+			bw.Write(name)
+			bw.WriteString(": ")
+			switch v := value.(type) {
+			case int64:
+				bw.WriteString(strconv.FormatInt(v, 10))
+			case float64:
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					bw.WriteString("null")
+				} else {
+					bw.WriteString(formatFloat64(v))
+				}
+			case string:
+				s, _ := json.Marshal(v)
+				bw.Write(s)
+			}
+		}
+		bw.WriteString("}")
+	}
+	if len(t.Rows) > 0 {
+		bw.WriteString("\n")
+	}
+	bw.WriteString("]\n")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// writeTableParquet writes t as a Parquet file with one row group per
+// DefaultParquetRowGroupSize rows.
+func writeTableParquet(w io.Writer, t Table) error {
+	schema := make([]parquetColumn, len(t.Columns))
+	for i, column := range t.Columns {
+		schema[i].name = column.Name
+		switch column.Type {
+		case ColumnInt:
+			schema[i].physical = parquetInt64
+		case ColumnFloat:
+			schema[i].physical = parquetDouble
+		case ColumnString:
+			schema[i].physical = parquetByteArray
+			schema[i].utf8 = true
+		}
+	}
+	p := newParquetWriter(w, 0, schema)
+	for _, row := range t.Rows {
+		if err := p.writeRow(row); err != nil {
+			return err
+		}
+	}
+	return p.Close()
+}
@@ -0,0 +1,144 @@
+package io
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"insolventbydesign/internal/analysis"
+)
+
+func testTable() Table {
+	return Table{
+		Columns: []Column{{"slot", ColumnInt}, {"mean_eth", ColumnFloat}, {"builder", ColumnString}},
+		Rows: [][]any{
+			{int64(7), 0.125, "0xA"},
+			{int64(8), math.NaN(), `say "hi"`},
+		},
+	}
+}
+
+// TestWriteTable verifies each format writes the same columns and values.
+func TestWriteTable(t *testing.T) {
+	header := []string{"slot", "mean_eth", "builder"}
+	rows := [][]string{{"7", "0.125", "0xA"}, {"8", "NaN", `say "hi"`}}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, testTable(), FormatCSV); err != nil {
+		t.Fatalf("WriteTable failed: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	if want := append([][]string{header}, rows...); !reflect.DeepEqual(records, want) {
+		t.Errorf("expected CSV %v, got %v", want, records)
+	}
+
+	buf.Reset()
+	if err := WriteTable(&buf, testTable(), FormatJSON); err != nil {
+		t.Fatalf("WriteTable failed: %v", err)
+	}
+	var objects []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &objects); err != nil {
+		t.Fatalf("failed to read JSON: %v\n%s", err, buf.String())
+	}
+	want := []map[string]any{
+		{"slot": 7.0, "mean_eth": 0.125, "builder": "0xA"},
+		{"slot": 8.0, "mean_eth": nil, "builder": `say "hi"`},
+	}
+	if !reflect.DeepEqual(objects, want) {
+		t.Errorf("expected JSON %v, got %v", want, objects)
+	}
+
+	buf.Reset()
+	if err := WriteTable(&buf, testTable(), FormatParquet); err != nil {
+		t.Fatalf("WriteTable failed: %v", err)
+	}
+	names, parquetRows := readParquet(t, buf.Bytes())
+	if !reflect.DeepEqual(names, header) || !reflect.DeepEqual(parquetRows, rows) {
+		t.Errorf("expected Parquet %v %v, got %v %v", header, rows, names, parquetRows)
+	}
+
+	buf.Reset()
+	if err := WriteTable(&buf, Table{}, FormatJSON); err != nil || buf.String() != "[]\n" {
+		t.Errorf("expected an empty JSON array, got %q (%v)", buf.String(), err)
+	}
+}
+
+// TestWriteTable_Invalid verifies mistyped rows and unknown formats are
+// rejected.
+func TestWriteTable_Invalid(t *testing.T) {
+	bad := testTable()
+	bad.Rows[1][0] = 8 // int, not int64
+	if err := WriteTable(&bytes.Buffer{}, bad, FormatCSV); err == nil {
+		t.Error("Expected error for a mistyped value, got nil")
+	}
+	if err := WriteTable(&bytes.Buffer{}, testTable(), "xml"); err == nil {
+		t.Error("Expected error for an unknown format, got nil")
+	}
+	if _, err := FormatForPath("out.txt"); err == nil {
+		t.Error("Expected error for an unknown extension, got nil")
+	}
+	if format, err := FormatForPath("results/RUN.Parquet"); err != nil || format != FormatParquet {
+		t.Errorf("expected %s, got %s (%v)", FormatParquet, format, err)
+	}
+}
+
+// TestEncodeMonteCarloResult verifies one pair of tail risk columns per
+// level, named by the level in percent, followed by one column per loss
+// threshold.
+func TestEncodeMonteCarloResult(t *testing.T) {
+	result := analysis.MonteCarloResult{
+		Seed:              42,
+		TailRisk:          []analysis.TailRisk{{Confidence: 0.95, VaR: -1, CVaR: -2}, {Confidence: 0.57, VaR: 3, CVaR: 4}},
+		LossProbabilities: []analysis.LossProbability{{ThresholdUSD: 1e6, Probability: 0.25}, {ThresholdUSD: 2.5, Probability: 0.5}},
+	}
+	table := EncodeMonteCarloResult(result)
+	records := table.records()
+	n := len(MonteCarloColumns)
+	want := []string{"var_95_usd", "cvar_95_usd", "var_57_usd", "cvar_57_usd", "p_loss_over_1000000_usd", "p_loss_over_2_5_usd"}
+	if got := records[0][n:]; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected tail risk and loss columns, got %v", got)
+	}
+	if got := records[1]; got[0] != "42" || !reflect.DeepEqual(got[n:], []string{"-1", "-2", "3", "4", "0.25", "0.5"}) {
+		t.Errorf("expected seed 42, tail risk and loss values, got %v", got)
+	}
+	if err := table.validate(); err != nil {
+		t.Errorf("expected a valid table, got %v", err)
+	}
+}
+
+// TestEncodeSummary verifies bootstrap interval columns follow the summary
+// columns only when the summary has intervals.
+func TestEncodeSummary(t *testing.T) {
+	summary := analysis.Summary{Count: 3, MedianETH: 0.04}
+	if table := EncodeSummary(summary); len(table.Columns) != len(SummaryColumns) {
+		t.Errorf("expected %d columns, got %d", len(SummaryColumns), len(table.Columns))
+	}
+
+	summary.CI = &analysis.SummaryIntervals{
+		Confidence: 0.9,
+		Resamples:  100,
+		BlockLen:   1,
+		MedianETH:  analysis.ConfidenceInterval{Lower: 0.03, Upper: 0.05},
+	}
+	table := EncodeSummary(summary)
+	if err := table.validate(); err != nil {
+		t.Fatalf("expected a valid table, got %v", err)
+	}
+	records := table.records()
+	if want := len(SummaryColumns) + len(SummaryCIColumns); len(records[0]) != want {
+		t.Fatalf("expected %d columns, got %d", want, len(records[0]))
+	}
+	values := make(map[string]string)
+	for i, name := range records[0] {
+		values[name] = records[1][i]
+	}
+	if values["ci_confidence"] != "0.9" || values["median_lower_eth"] != "0.03" || values["median_upper_eth"] != "0.05" {
+		t.Errorf("expected the median interval at 90%%, got %v", values)
+	}
+}
@@ -0,0 +1,178 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// CostAccumulator maintains C_c(τ) over the most recent τ bribes of a
+// stream for one or more window lengths, updating in O(windows) per slot
+// so live views need not recompute over the full dataset. Like
+// CensorshipCost, windows count bribes, not slot numbers.
+//
+// It also counts builders within each window, so EffectiveCost uses α of
+// the same slots as the cost. Memory is proportional to the longest
+// window. A CostAccumulator is not safe for concurrent use.
+type CostAccumulator struct {
+	windows  []*accumulatorWindow
+	ring     []accumulatedSlot // The last len(ring) bribes, indexed by position mod len(ring)
+	seen     uint64
+	lastSlot uint64
+}
+
+type accumulatorWindow struct {
+	tau      uint64
+	sum      *big.Int
+	builders map[string]uint64
+}
+
+type accumulatedSlot struct {
+	value   *big.Int
+	builder string
+}
+
+// WindowCost is the current state of one accumulator window.
+type WindowCost struct {
+	Tau      uint64
+	CostWei  *big.Int // Sum over the last min(τ, slots seen) bribes
+	Complete bool     // Whether τ bribes have been seen
+}
+
+// NewCostAccumulator returns an accumulator tracking each window length in
+// taus.
+func NewCostAccumulator(taus ...uint64) (*CostAccumulator, error) {
+	if len(taus) == 0 {
+		return nil, fmt.Errorf("at least one window length is required")
+	}
+	a := &CostAccumulator{}
+	var longest uint64
+	for _, tau := range taus {
+		if tau < 1 {
+			return nil, fmt.Errorf("tau must be at least 1, got %d", tau)
+		}
+		if a.window(tau) != nil {
+			return nil, fmt.Errorf("duplicate window length %d", tau)
+		}
+		a.windows = append(a.windows, &accumulatorWindow{tau: tau, sum: new(big.Int), builders: make(map[string]uint64)})
+		longest = max(longest, tau)
+	}
+	a.ring = make([]accumulatedSlot, longest)
+	return a, nil
+}
+
+// Add appends the next bribe of the stream. Slots must be strictly
+// increasing.
+func (a *CostAccumulator) Add(bribe SlotBribe) error {
+	if bribe.ValueWei == nil {
+		return fmt.Errorf("%w for slot %d", ErrNilValue, bribe.Slot)
+	}
+	if a.seen > 0 && bribe.Slot <= a.lastSlot {
+		return fmt.Errorf("slot %d does not follow slot %d", bribe.Slot, a.lastSlot)
+	}
+	builder := bribe.BuilderPubkey
+	if builder == "" {
+		builder = "unknown"
+	}
+
+	size := uint64(len(a.ring))
+	for _, w := range a.windows {
+		// Evict the bribe falling out of the window before its ring entry
+		// is overwritten
+		if a.seen >= w.tau {
+			out := a.ring[(a.seen-w.tau)%size]
+			w.sum.Sub(w.sum, out.value)
+			if w.builders[out.builder]--; w.builders[out.builder] == 0 {
+				delete(w.builders, out.builder)
+			}
+		}
+		w.sum.Add(w.sum, bribe.ValueWei)
+		w.builders[builder]++
+	}
+	a.ring[a.seen%size] = accumulatedSlot{value: new(big.Int).Set(bribe.ValueWei), builder: builder}
+	a.seen++
+	a.lastSlot = bribe.Slot
+	return nil
+}
+
+// Consume adds every bribe from source.
+func (a *CostAccumulator) Consume(source BribeSource) error {
+	return source(a.Add)
+}
+
+// Slots returns the number of bribes added so far.
+func (a *CostAccumulator) Slots() uint64 {
+	return a.seen
+}
+
+// LastSlot returns the slot of the most recent bribe, or 0 before any.
+func (a *CostAccumulator) LastSlot() uint64 {
+	return a.lastSlot
+}
+
+// Cost returns C_c over the last tau bribes. tau must be one of the
+// accumulator's windows, and fails with ErrInsufficientData until tau
+// bribes have been added.
+func (a *CostAccumulator) Cost(tau uint64) (*big.Int, error) {
+	w, err := a.completeWindow(tau)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(w.sum), nil
+}
+
+// EffectiveCost returns C_c^eff = (1 - α) · C_c over the last tau bribes,
+// with α the share of those slots won by the top k builders.
+func (a *CostAccumulator) EffectiveCost(tau uint64, topK int) (*big.Float, float64, error) {
+	if topK < 1 {
+		return nil, 0, fmt.Errorf("%w: must be at least 1, got %d", ErrInvalidTopK, topK)
+	}
+	w, err := a.completeWindow(tau)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	counts := make([]uint64, 0, len(w.builders))
+	for _, count := range w.builders {
+		counts = append(counts, count)
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i] > counts[j] })
+	var top uint64
+	for _, count := range counts[:min(topK, len(counts))] {
+		top += count
+	}
+	alpha := float64(top) / float64(w.tau)
+
+	ccEff := new(big.Float).Mul(new(big.Float).SetInt(w.sum), big.NewFloat(1-alpha))
+	return ccEff, alpha, nil
+}
+
+// Windows returns a snapshot of every window, in the order given to
+// NewCostAccumulator.
+func (a *CostAccumulator) Windows() []WindowCost {
+	costs := make([]WindowCost, len(a.windows))
+	for i, w := range a.windows {
+		costs[i] = WindowCost{Tau: w.tau, CostWei: new(big.Int).Set(w.sum), Complete: a.seen >= w.tau}
+	}
+	return costs
+}
+
+func (a *CostAccumulator) window(tau uint64) *accumulatorWindow {
+	for _, w := range a.windows {
+		if w.tau == tau {
+			return w
+		}
+	}
+	return nil
+}
+
+func (a *CostAccumulator) completeWindow(tau uint64) (*accumulatorWindow, error) {
+	w := a.window(tau)
+	if w == nil {
+		return nil, fmt.Errorf("window length %d is not tracked", tau)
+	}
+	if a.seen < tau {
+		return nil, fmt.Errorf("%w: need %d slots, have %d", ErrInsufficientData, tau, a.seen)
+	}
+	return w, nil
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// TestCostAccumulator verifies every window matches CensorshipCost and
+// EffectiveCensorshipCost over the trailing bribes as the stream advances.
+func TestCostAccumulator(t *testing.T) {
+	builders := []string{"0xA", "0xA", "0xB", "0xC", "0xA", "0xB", "0xB", "0xB"}
+	var bribes []SlotBribe
+	for i, builder := range builders {
+		bribes = append(bribes, SlotBribe{Slot: uint64(10 + 2*i), ValueWei: big.NewInt(int64(100 * (i + 1))), BuilderPubkey: builder})
+	}
+
+	acc, err := NewCostAccumulator(3, 5)
+	if err != nil {
+		t.Fatalf("NewCostAccumulator failed: %v", err)
+	}
+	for n, bribe := range bribes {
+		if err := acc.Add(bribe); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		for _, tau := range []uint64{3, 5} {
+			got, err := acc.Cost(tau)
+			if uint64(n+1) < tau {
+				if !errors.Is(err, ErrInsufficientData) {
+					t.Errorf("after %d slots: expected ErrInsufficientData for tau %d, got %v", n+1, tau, err)
+				}
+				continue
+			}
+			window := bribes[n+1-int(tau) : n+1]
+			want, _ := CensorshipCost(window, tau)
+			if err != nil || got.Cmp(want) != 0 {
+				t.Errorf("after %d slots: expected C_c(%d) %s, got %v (err %v)", n+1, tau, want, got, err)
+			}
+
+			wantEff, wantAlpha, _ := EffectiveCensorshipCost(window, tau, 1)
+			gotEff, gotAlpha, err := acc.EffectiveCost(tau, 1)
+			if err != nil || gotAlpha != wantAlpha || !floatEqual(gotEff, wantEff, 1e-9) {
+				t.Errorf("after %d slots: expected C_c^eff(%d) %s at alpha %f, got %v at %f (err %v)",
+					n+1, tau, wantEff.String(), wantAlpha, gotEff, gotAlpha, err)
+			}
+		}
+	}
+
+	windows := acc.Windows()
+	if len(windows) != 2 || windows[0].Tau != 3 || !windows[1].Complete || windows[1].CostWei.Int64() != 400+500+600+700+800 {
+		t.Errorf("expected complete windows 3 and 5 ending in slot 24, got %+v", windows)
+	}
+	if acc.Slots() != 8 || acc.LastSlot() != 24 {
+		t.Errorf("expected 8 slots ending at 24, got %d ending at %d", acc.Slots(), acc.LastSlot())
+	}
+}
+
+// TestCostAccumulator_Invalid verifies bad windows and out-of-order or
+// nil bribes are rejected.
+func TestCostAccumulator_Invalid(t *testing.T) {
+	if _, err := NewCostAccumulator(); err == nil {
+		t.Error("Expected error for no windows, got nil")
+	}
+	if _, err := NewCostAccumulator(0); err == nil {
+		t.Error("Expected error for tau 0, got nil")
+	}
+	if _, err := NewCostAccumulator(4, 4); err == nil {
+		t.Error("Expected error for duplicate windows, got nil")
+	}
+
+	acc, _ := NewCostAccumulator(2)
+	if err := acc.Consume(BribesFromSlice([]SlotBribe{{Slot: 5, ValueWei: big.NewInt(1)}})); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if err := acc.Add(SlotBribe{Slot: 5, ValueWei: big.NewInt(1)}); err == nil {
+		t.Error("Expected error for a repeated slot, got nil")
+	}
+	if err := acc.Add(SlotBribe{Slot: 6}); !errors.Is(err, ErrNilValue) {
+		t.Errorf("expected ErrNilValue, got %v", err)
+	}
+	if _, err := acc.Cost(3); err == nil {
+		t.Error("Expected error for an untracked window, got nil")
+	}
+}
@@ -26,6 +26,31 @@ func BenchmarkCensorshipCost(b *testing.B) {
 	}
 }
 
+// BenchmarkCostIndexRange measures a window query against the prefix-sum
+// index, the counterpart of BenchmarkCensorshipCost
+func BenchmarkCostIndexRange(b *testing.B) {
+	bribes := make([]SlotBribe, 100000)
+	for i := 0; i < 100000; i++ {
+		bribes[i] = SlotBribe{
+			Slot:          uint64(i),
+			ValueWei:      big.NewInt(int64(1e18 + i*1e15)),
+			BuilderPubkey: "builder_1",
+		}
+	}
+	idx, err := NewCostIndex(bribes)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := idx.CostRange(0, 99999)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 // BenchmarkCensorshipCostSmall tests small tau performance
 func BenchmarkCensorshipCostSmall(b *testing.B) {
 	bribes := make([]SlotBribe, 1000)
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// ReceivedBid is one block a builder submitted to a relay for a slot,
+// whether or not it was delivered to the proposer.
+//
+// Delivered payloads (SlotBribe) only reveal the winning bid; the full
+// set of received bids is what second-price and bid-depth analyses need.
+type ReceivedBid struct {
+	Slot          uint64    // Consensus slot number
+	RelayURL      string    // Relay that received the bid
+	BuilderPubkey string    // Submitting builder
+	BlockHash     string    // Block the bid pays for; identical across relays
+	ValueWei      *big.Int  // Bid value in wei (exact)
+	ReceivedAt    time.Time // Relay receive time (millisecond precision)
+}
+
+// SlotBidSummary describes the competition for a single slot.
+type SlotBidSummary struct {
+	Slot           uint64
+	Depth          int      // Distinct blocks bid, across all relays
+	Builders       int      // Distinct builders that bid
+	TopBuilder     string   // Builder of the highest bid
+	HighestWei     *big.Int // Highest bid
+	SecondPriceWei *big.Int // Highest bid from any other builder; 0 if uncontested
+	PriceMarginWei *big.Int // HighestWei - SecondPriceWei
+}
+
+// SummarizeSlotBids computes one SlotBidSummary per slot, ordered by slot.
+//
+// The same block submitted to several relays counts once. The second
+// price is the best bid of a builder other than the winner, which is the
+// amount the winner had to beat; a builder outbidding itself does not
+// raise it.
+//
+// Guarantees:
+// - Exact wei arithmetic (big.Int)
+// - Deterministic output regardless of input order
+// - Fails on nil values
+func SummarizeSlotBids(bids []ReceivedBid) ([]SlotBidSummary, error) {
+	type slotBids struct {
+		blocks   map[string]bool
+		builders map[string]*big.Int // Best bid per builder
+	}
+
+	slots := make(map[uint64]*slotBids)
+	for i, bid := range bids {
+		if bid.ValueWei == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilValue, i)
+		}
+
+		sb, ok := slots[bid.Slot]
+		if !ok {
+			sb = &slotBids{blocks: make(map[string]bool), builders: make(map[string]*big.Int)}
+			slots[bid.Slot] = sb
+		}
+		sb.blocks[bid.BlockHash] = true
+		if best, ok := sb.builders[bid.BuilderPubkey]; !ok || bid.ValueWei.Cmp(best) > 0 {
+			sb.builders[bid.BuilderPubkey] = bid.ValueWei
+		}
+	}
+
+	summaries := make([]SlotBidSummary, 0, len(slots))
+	for slot, sb := range slots {
+		// Rank builders by best bid, ties broken by pubkey
+		builders := make([]string, 0, len(sb.builders))
+		for pubkey := range sb.builders {
+			builders = append(builders, pubkey)
+		}
+		sort.Slice(builders, func(i, j int) bool {
+			if c := sb.builders[builders[i]].Cmp(sb.builders[builders[j]]); c != 0 {
+				return c > 0
+			}
+			return builders[i] < builders[j]
+		})
+
+		summary := SlotBidSummary{
+			Slot:           slot,
+			Depth:          len(sb.blocks),
+			Builders:       len(builders),
+			TopBuilder:     builders[0],
+			HighestWei:     new(big.Int).Set(sb.builders[builders[0]]),
+			SecondPriceWei: new(big.Int),
+		}
+		if len(builders) > 1 {
+			summary.SecondPriceWei.Set(sb.builders[builders[1]])
+		}
+		summary.PriceMarginWei = new(big.Int).Sub(summary.HighestWei, summary.SecondPriceWei)
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Slot < summaries[j].Slot
+	})
+	return summaries, nil
+}
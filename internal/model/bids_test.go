@@ -0,0 +1,49 @@
+package model
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestSummarizeSlotBids_SecondPrice verifies depth, dedup across relays
+// and that the second price comes from a different builder.
+func TestSummarizeSlotBids_SecondPrice(t *testing.T) {
+	bids := []ReceivedBid{
+		{Slot: 2, RelayURL: "r1", BuilderPubkey: "0xA", BlockHash: "0x01", ValueWei: big.NewInt(100)},
+		{Slot: 1, RelayURL: "r1", BuilderPubkey: "0xA", BlockHash: "0x10", ValueWei: big.NewInt(50)},
+		{Slot: 1, RelayURL: "r1", BuilderPubkey: "0xA", BlockHash: "0x11", ValueWei: big.NewInt(90)},
+		{Slot: 1, RelayURL: "r2", BuilderPubkey: "0xA", BlockHash: "0x11", ValueWei: big.NewInt(90)}, // Same block, other relay
+		{Slot: 1, RelayURL: "r1", BuilderPubkey: "0xB", BlockHash: "0x12", ValueWei: big.NewInt(70)},
+	}
+
+	summaries, err := SummarizeSlotBids(bids)
+	if err != nil {
+		t.Fatalf("SummarizeSlotBids failed: %v", err)
+	}
+	if len(summaries) != 2 || summaries[0].Slot != 1 || summaries[1].Slot != 2 {
+		t.Fatalf("expected summaries for slots 1 and 2, got %+v", summaries)
+	}
+
+	s := summaries[0]
+	if s.Depth != 3 || s.Builders != 2 {
+		t.Errorf("expected depth 3 and 2 builders, got %d and %d", s.Depth, s.Builders)
+	}
+	if s.TopBuilder != "0xA" || s.HighestWei.Int64() != 90 {
+		t.Errorf("expected 0xA winning at 90, got %s at %s", s.TopBuilder, s.HighestWei)
+	}
+	// 0xA's own 50 bid does not count as competition
+	if s.SecondPriceWei.Int64() != 70 || s.PriceMarginWei.Int64() != 20 {
+		t.Errorf("expected second price 70 and margin 20, got %s and %s", s.SecondPriceWei, s.PriceMarginWei)
+	}
+
+	if uncontested := summaries[1]; uncontested.SecondPriceWei.Sign() != 0 || uncontested.PriceMarginWei.Int64() != 100 {
+		t.Errorf("expected uncontested slot with second price 0, got %+v", uncontested)
+	}
+}
+
+// TestSummarizeSlotBids_NilValue verifies nil values are rejected.
+func TestSummarizeSlotBids_NilValue(t *testing.T) {
+	if _, err := SummarizeSlotBids([]ReceivedBid{{Slot: 1}}); err == nil {
+		t.Error("Expected error for nil ValueWei, got nil")
+	}
+}
@@ -1,8 +1,11 @@
 package model
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"math/big"
+	"sort"
 )
 
 // SlotBribe represents the minimum cost required
@@ -14,6 +17,16 @@ type SlotBribe struct {
 	Slot          uint64   // Consensus slot number
 	ValueWei      *big.Int // Winning bid in wei (exact)
 	BuilderPubkey string   // Builder identity for concentration analysis
+	BuilderEntity string   // Operating entity from the builder registry ("" if unlabeled)
+
+	// Proposer identity from the relay trace. Retained by relay parsing but
+	// not stored, so both are "" for slots loaded from a database.
+	ProposerPubkey       string
+	ProposerFeeRecipient string
+
+	// Relay that delivered the payload: the stored relay_url, or for
+	// parsed relay files, recovered from the file name ("" if unknown).
+	RelayURL string
 }
 
 // CensorshipCost computes the total cost required
@@ -30,13 +43,13 @@ type SlotBribe struct {
 // - Fails if bribes slice has fewer than tau elements
 func CensorshipCost(bribes []SlotBribe, tau uint64) (*big.Int, error) {
 	if uint64(len(bribes)) < tau {
-		return nil, fmt.Errorf("insufficient data: need %d slots, have %d", tau, len(bribes))
+		return nil, fmt.Errorf("%w: need %d slots, have %d", ErrInsufficientData, tau, len(bribes))
 	}
 
 	total := new(big.Int)
 	for i := uint64(0); i < tau; i++ {
 		if bribes[i].ValueWei == nil {
-			return nil, fmt.Errorf("nil ValueWei at index %d", i)
+			return nil, fmt.Errorf("%w at index %d", ErrNilValue, i)
 		}
 		total.Add(total, bribes[i].ValueWei)
 	}
@@ -44,6 +57,214 @@ func CensorshipCost(bribes []SlotBribe, tau uint64) (*big.Int, error) {
 	return total, nil
 }
 
+// CensorshipCostWindow computes C_c(τ) for the τ consecutive slots
+// starting at startSlot, locating the window by slot number rather than
+// by position in bribes. It is CensorshipCostRange over
+// [startSlot, startSlot+τ-1], so it fails the same way on missing slots.
+func CensorshipCostWindow(bribes []SlotBribe, startSlot, tau uint64) (*big.Int, error) {
+	if tau == 0 {
+		return new(big.Int), nil
+	}
+	return CensorshipCostRange(bribes, startSlot, startSlot+tau-1)
+}
+
+// MissingSlotsError reports the slots of a range with no bribe.
+type MissingSlotsError struct {
+	StartSlot uint64
+	EndSlot   uint64
+	Missing   []uint64 // Ascending
+}
+
+func (e *MissingSlotsError) Error() string {
+	return fmt.Sprintf("incomplete slot range %d-%d: %d slots missing, first %d",
+		e.StartSlot, e.EndSlot, len(e.Missing), e.Missing[0])
+}
+
+// CensorshipCostRange computes the exact censorship cost of the inclusive
+// slot range [startSlot, endSlot], i.e. C_c(τ) with τ = endSlot-startSlot+1.
+//
+// Summing whatever slots happen to be present would under-count the cost
+// of a range with gaps, so any missing slot fails with a
+// *MissingSlotsError listing all of them. See CensorshipCostRangeBy for
+// other gap policies.
+//
+// bribes must be sorted by slot, as ParseRelayFile and the stores return
+// them; a slot appearing twice within the range is an error.
+func CensorshipCostRange(bribes []SlotBribe, startSlot, endSlot uint64) (*big.Int, error) {
+	return CensorshipCostRangeBy(bribes, startSlot, endSlot, GapFail)
+}
+
+// GapPolicy decides how slots missing from the data affect C_c. A slot
+// can be missing because it was missed on chain (no block, so nothing to
+// bribe) or because no fetched relay delivered it (a coverage hole, whose
+// cost is unknown).
+type GapPolicy string
+
+// Gap policies.
+const (
+	// GapFail refuses to price a range with missing slots.
+	GapFail GapPolicy = "fail"
+
+	// GapTreatAsZero prices missing slots at zero, which is right for
+	// slots missed on chain and a lower bound for coverage holes.
+	GapTreatAsZero GapPolicy = "zero"
+
+	// GapImputeMedian prices each missing slot at the median winning bid
+	// of the range's present slots, an estimate for coverage holes.
+	GapImputeMedian GapPolicy = "median"
+)
+
+// ParseGapPolicy validates a policy name; "" selects GapFail.
+func ParseGapPolicy(s string) (GapPolicy, error) {
+	switch policy := GapPolicy(s); policy {
+	case "":
+		return GapFail, nil
+	case GapFail, GapTreatAsZero, GapImputeMedian:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown gap policy '%s' (want %s, %s or %s)", s, GapFail, GapTreatAsZero, GapImputeMedian)
+	}
+}
+
+// CensorshipCostRangeBy is CensorshipCostRange with missing slots handled
+// by policy. GapImputeMedian fails if the range has no slots at all, since
+// there is nothing to take the median of.
+func CensorshipCostRangeBy(bribes []SlotBribe, startSlot, endSlot uint64, policy GapPolicy) (*big.Int, error) {
+	if endSlot < startSlot {
+		return nil, fmt.Errorf("end slot %d precedes start slot %d", endSlot, startSlot)
+	}
+
+	i := sort.Search(len(bribes), func(i int) bool {
+		return bribes[i].Slot >= startSlot
+	})
+
+	total := new(big.Int)
+	var values []*big.Int
+	var missing []uint64
+	next := startSlot // Next slot expected in the range
+	reachedEnd := false
+	for ; i < len(bribes) && bribes[i].Slot <= endSlot; i++ {
+		bribe := bribes[i]
+		if bribe.Slot < next {
+			return nil, fmt.Errorf("duplicate or unsorted slot %d at index %d", bribe.Slot, i)
+		}
+		for ; next < bribe.Slot; next++ {
+			missing = append(missing, next)
+		}
+		if bribe.ValueWei == nil {
+			return nil, fmt.Errorf("%w for slot %d", ErrNilValue, bribe.Slot)
+		}
+		total.Add(total, bribe.ValueWei)
+		values = append(values, bribe.ValueWei)
+		// Stop at endSlot rather than test next <= endSlot, which
+		// overflows when endSlot is the largest slot
+		reachedEnd = bribe.Slot == endSlot
+		next = bribe.Slot + 1
+	}
+	for slot := next; !reachedEnd; slot++ {
+		missing = append(missing, slot)
+		reachedEnd = slot == endSlot
+	}
+
+	if len(missing) == 0 {
+		return total, nil
+	}
+	switch policy {
+	case GapTreatAsZero:
+		return total, nil
+	case GapImputeMedian:
+		if len(values) == 0 {
+			return nil, fmt.Errorf("%w: cannot impute slots %d-%d: no slots present", ErrInsufficientData, startSlot, endSlot)
+		}
+		imputed := new(big.Int).Mul(medianWei(values), new(big.Int).SetUint64(uint64(len(missing))))
+		return total.Add(total, imputed), nil
+	case GapFail:
+		return nil, &MissingSlotsError{StartSlot: startSlot, EndSlot: endSlot, Missing: missing}
+	default:
+		return nil, fmt.Errorf("unknown gap policy '%s'", policy)
+	}
+}
+
+// medianWei returns the median of values, rounding down between the two
+// middle values of an even count. values is reordered.
+func medianWei(values []*big.Int) *big.Int {
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Cmp(values[j]) < 0
+	})
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return new(big.Int).Set(values[mid])
+	}
+	median := new(big.Int).Add(values[mid-1], values[mid])
+	return median.Rsh(median, 1)
+}
+
+// SlotWindow is a run of consecutive slots and its censorship cost.
+type SlotWindow struct {
+	StartSlot uint64
+	CostWei   *big.Int
+}
+
+// WindowExtremes contains the cheapest and most expensive τ-slot windows.
+type WindowExtremes struct {
+	Tau           uint64
+	Cheapest      SlotWindow // Ties go to the earliest window
+	MostExpensive SlotWindow
+	Windows       int // Gap-free windows considered
+}
+
+// FindCheapestWindow finds the τ consecutive slots that are cheapest to
+// censor. An attacker free to choose when to strike picks this window
+// rather than an arbitrary one, so its cost is the relevant lower bound;
+// the most expensive window is returned for comparison.
+//
+// A running big.Int sum slides over bribes, which must be sorted by slot
+// without duplicates. Windows spanning a missing slot are skipped, as in
+// CensorshipCostWindow. Fails if no gap-free window of τ slots exists.
+func FindCheapestWindow(bribes []SlotBribe, tau uint64) (WindowExtremes, error) {
+	if tau == 0 {
+		return WindowExtremes{}, fmt.Errorf("tau must be at least 1")
+	}
+
+	result := WindowExtremes{Tau: tau}
+	sum := new(big.Int)
+	runStart := 0 // Index of the first slot of the current gap-free run
+	for i, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			return WindowExtremes{}, fmt.Errorf("%w for slot %d", ErrNilValue, bribe.Slot)
+		}
+		if i > 0 && bribe.Slot <= bribes[i-1].Slot {
+			return WindowExtremes{}, fmt.Errorf("bribes not sorted by slot at index %d", i)
+		}
+		if i > 0 && bribe.Slot != bribes[i-1].Slot+1 {
+			runStart = i
+			sum.SetInt64(0)
+		}
+
+		sum.Add(sum, bribe.ValueWei)
+		if uint64(i-runStart+1) > tau {
+			sum.Sub(sum, bribes[i-int(tau)].ValueWei)
+		}
+		if uint64(i-runStart+1) < tau {
+			continue
+		}
+
+		start := bribe.Slot - tau + 1
+		if result.Windows == 0 || sum.Cmp(result.Cheapest.CostWei) < 0 {
+			result.Cheapest = SlotWindow{StartSlot: start, CostWei: new(big.Int).Set(sum)}
+		}
+		if result.Windows == 0 || sum.Cmp(result.MostExpensive.CostWei) > 0 {
+			result.MostExpensive = SlotWindow{StartSlot: start, CostWei: new(big.Int).Set(sum)}
+		}
+		result.Windows++
+	}
+
+	if result.Windows == 0 {
+		return WindowExtremes{}, fmt.Errorf("%w: no %d consecutive slots without gaps", ErrInsufficientData, tau)
+	}
+	return result, nil
+}
+
 // EffectiveCensorshipCost computes the censorship cost adjusted for builder concentration.
 //
 // Formula: C_c^eff = (1 - α) · C_c
@@ -61,7 +282,17 @@ func CensorshipCost(bribes []SlotBribe, tau uint64) (*big.Int, error) {
 // This is the "rent-a-cartel" economic model.
 //
 // Returns the effective cost as *big.Float for precision, since α is inherently float64.
+// α is block-count weighted; see EffectiveCensorshipCostBy. It is measured
+// over all of bribes, not just the τ-window; see
+// EffectiveCensorshipCostScoped to align the two.
 func EffectiveCensorshipCost(bribes []SlotBribe, tau uint64, topK int) (*big.Float, float64, error) {
+	return EffectiveCensorshipCostBy(bribes, tau, topK, ConcentrationByBlocks)
+}
+
+// EffectiveCensorshipCostBy is EffectiveCensorshipCost with α measured by
+// metric. With ConcentrationByValue the discount is the top-k builders'
+// share of wei won rather than of blocks built.
+func EffectiveCensorshipCostBy(bribes []SlotBribe, tau uint64, topK int, metric ConcentrationMetric) (*big.Float, float64, error) {
 	// Compute raw censorship cost
 	cc, err := CensorshipCost(bribes, tau)
 	if err != nil {
@@ -69,7 +300,7 @@ func EffectiveCensorshipCost(bribes []SlotBribe, tau uint64, topK int) (*big.Flo
 	}
 
 	// Compute builder concentration
-	alpha, _, err := ComputeBuilderConcentration(bribes, topK)
+	alpha, _, err := ComputeConcentration(bribes, topK, metric)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to compute concentration: %w", err)
 	}
@@ -89,22 +320,332 @@ func EffectiveCensorshipCost(bribes []SlotBribe, tau uint64, topK int) (*big.Flo
 	return ccEff, alpha, nil
 }
 
+// AlphaScope selects the bribes α is measured over. The zero value is the
+// whole dataset, which is what EffectiveCensorshipCost uses even though
+// C_c covers only the first τ slots.
+type AlphaScope struct {
+	Window    bool   // Measure α over the same τ slots as C_c
+	StartSlot uint64 // Otherwise an explicit inclusive slot range,
+	EndSlot   uint64 // such as a lookback before the attack
+}
+
+// String describes the scope: "dataset", "window" or "slots A-B".
+func (s AlphaScope) String() string {
+	switch {
+	case s.Window:
+		return "window"
+	case s.StartSlot == 0 && s.EndSlot == 0:
+		return "dataset"
+	default:
+		return fmt.Sprintf("slots %d-%d", s.StartSlot, s.EndSlot)
+	}
+}
+
+// ScopedAlpha is the α used by EffectiveCensorshipCostScoped and the data
+// it was measured over.
+type ScopedAlpha struct {
+	Alpha     float64
+	Scope     AlphaScope
+	Bribes    int    // Number of bribes α was computed from
+	FirstSlot uint64 // First and last slot among them
+	LastSlot  uint64
+}
+
+// EffectiveCensorshipCostScoped is EffectiveCensorshipCostBy with α
+// measured over scope rather than always over all of bribes, so cost and
+// concentration can share a time horizon. It returns the α used along
+// with the scope and the slots it covered.
+func EffectiveCensorshipCostScoped(bribes []SlotBribe, tau uint64, topK int, metric ConcentrationMetric, scope AlphaScope) (*big.Float, ScopedAlpha, error) {
+	cc, err := CensorshipCost(bribes, tau)
+	if err != nil {
+		return nil, ScopedAlpha{}, fmt.Errorf("failed to compute censorship cost: %w", err)
+	}
+
+	var scoped []SlotBribe
+	switch {
+	case scope.Window:
+		scoped = bribes[:tau]
+	case scope.StartSlot == 0 && scope.EndSlot == 0:
+		scoped = bribes
+	default:
+		if scope.StartSlot > scope.EndSlot {
+			return nil, ScopedAlpha{}, fmt.Errorf("invalid alpha scope: start slot %d after end slot %d", scope.StartSlot, scope.EndSlot)
+		}
+		for _, bribe := range bribes {
+			if bribe.Slot >= scope.StartSlot && bribe.Slot <= scope.EndSlot {
+				scoped = append(scoped, bribe)
+			}
+		}
+	}
+	if len(scoped) == 0 {
+		return nil, ScopedAlpha{}, fmt.Errorf("%w: no bribes in alpha scope %s", ErrInsufficientData, scope)
+	}
+
+	alpha, _, err := ComputeConcentration(scoped, topK, metric)
+	if err != nil {
+		return nil, ScopedAlpha{}, fmt.Errorf("failed to compute concentration: %w", err)
+	}
+	used := ScopedAlpha{
+		Alpha:     alpha,
+		Scope:     scope,
+		Bribes:    len(scoped),
+		FirstSlot: scoped[0].Slot,
+		LastSlot:  scoped[0].Slot,
+	}
+	for _, bribe := range scoped {
+		used.FirstSlot = min(used.FirstSlot, bribe.Slot)
+		used.LastSlot = max(used.LastSlot, bribe.Slot)
+	}
+
+	ccEff := new(big.Float).SetInt(cc)
+	return ccEff.Mul(ccEff, big.NewFloat(1-alpha)), used, nil
+}
+
+// EffectiveCensorshipCostExact is EffectiveCensorshipCostBy in exact
+// rational arithmetic: α is the fraction returned by
+// ComputeConcentrationExact rather than a float64, so
+//
+//	C_c^eff = (1 - α) · C_c
+//
+// is exact and reproducible bit for bit across platforms.
+func EffectiveCensorshipCostExact(bribes []SlotBribe, tau uint64, topK int, metric ConcentrationMetric) (*big.Rat, *big.Rat, error) {
+	cc, err := CensorshipCost(bribes, tau)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute censorship cost: %w", err)
+	}
+
+	alpha, _, err := ComputeConcentrationExact(bribes, topK, metric)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute concentration: %w", err)
+	}
+
+	discount := new(big.Rat).Sub(big.NewRat(1, 1), alpha)
+	ccEff := new(big.Rat).Mul(new(big.Rat).SetInt(cc), discount)
+
+	return ccEff, alpha, nil
+}
+
+// ForegoneMEV estimates the opportunity cost to cartel builders of
+// censoring: the share of their own winning bids in the first τ slots that
+// they give up by excluding the target transaction and whatever depends
+// on it.
+//
+//	C_forgone = foregoneFraction · Σ(t ≤ τ, builder(t) in cartel) b(t)
+//
+// The cartel is the top k builders over all of bribes under metric, as
+// for α, with ties broken by pubkey. foregoneFraction ∈ [0, 1] is an
+// assumption about how much of a block's value depends on the censored
+// transaction.
+func ForegoneMEV(bribes []SlotBribe, tau uint64, topK int, metric ConcentrationMetric, foregoneFraction float64) (*big.Float, error) {
+	if !(foregoneFraction >= 0 && foregoneFraction <= 1) {
+		return nil, fmt.Errorf("invalid foregone fraction: %f (must be in [0,1])", foregoneFraction)
+	}
+	if uint64(len(bribes)) < tau {
+		return nil, fmt.Errorf("%w: need %d slots, have %d", ErrInsufficientData, tau, len(bribes))
+	}
+
+	won, _, err := splitCostByCartel(bribes, tau, topK, metric)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Float).Mul(new(big.Float).SetInt(won), big.NewFloat(foregoneFraction)), nil
+}
+
+// NonCartelCensorshipCost is the "bribe the rest" cost: the sum of the
+// winning bids in the first τ slots that were won by builders outside
+// the cartel,
+//
+//	C_c^rest = Σ(t ≤ τ, builder(t) not in cartel) b(t)
+//
+// The cartel builds the other slots itself and pays nothing for them.
+// Unlike (1 - α) · C_c, which scales a value by a block-count share, this
+// weighs each slot by its own bid, so it is exact when the cartel happens
+// to win the cheap or the expensive slots of the window. The cartel is
+// the top k builders by blocks over all of bribes, as for α, with ties
+// broken by pubkey.
+func NonCartelCensorshipCost(bribes []SlotBribe, tau uint64, topK int) (*big.Int, error) {
+	if uint64(len(bribes)) < tau {
+		return nil, fmt.Errorf("%w: need %d slots, have %d", ErrInsufficientData, tau, len(bribes))
+	}
+	_, rest, err := splitCostByCartel(bribes, tau, topK, ConcentrationByBlocks)
+	return rest, err
+}
+
+// splitCostByCartel sums the first τ bids won by the top k builders under
+// metric and by everyone else.
+func splitCostByCartel(bribes []SlotBribe, tau uint64, topK int, metric ConcentrationMetric) (cartelWon, rest *big.Int, err error) {
+	_, stats, err := ComputeConcentration(bribes, topK, metric)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute concentration: %w", err)
+	}
+	// Value-weighted stats already come ranked with ties broken
+	if metric == ConcentrationByBlocks {
+		sort.SliceStable(stats, func(i, j int) bool {
+			if stats[i].BlockCount != stats[j].BlockCount {
+				return stats[i].BlockCount > stats[j].BlockCount
+			}
+			return stats[i].BuilderPubkey < stats[j].BuilderPubkey
+		})
+	}
+	cartel := make(map[string]bool, topK)
+	for i := 0; i < topK && i < len(stats); i++ {
+		cartel[stats[i].BuilderPubkey] = true
+	}
+
+	cartelWon, rest = new(big.Int), new(big.Int)
+	for i := uint64(0); i < tau; i++ {
+		bribe := bribes[i]
+		if bribe.ValueWei == nil {
+			return nil, nil, fmt.Errorf("%w at index %d", ErrNilValue, i)
+		}
+		// Concentration groups bribes without a pubkey as "unknown"
+		if cartel[bribe.BuilderPubkey] || (bribe.BuilderPubkey == "" && cartel["unknown"]) {
+			cartelWon.Add(cartelWon, bribe.ValueWei)
+		} else {
+			rest.Add(rest, bribe.ValueWei)
+		}
+	}
+	return cartelWon, rest, nil
+}
+
+// EffectiveCensorshipCostWithForegone is EffectiveCensorshipCostBy plus
+// the cartel builders' foregone MEV (see ForegoneMEV):
+//
+//	C_c^eff = (1 - α) · C_c + C_forgone
+func EffectiveCensorshipCostWithForegone(bribes []SlotBribe, tau uint64, topK int, metric ConcentrationMetric, foregoneFraction float64) (*big.Float, float64, error) {
+	ccEff, alpha, err := EffectiveCensorshipCostBy(bribes, tau, topK, metric)
+	if err != nil {
+		return nil, 0, err
+	}
+	foregone, err := ForegoneMEV(bribes, tau, topK, metric, foregoneFraction)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to compute foregone MEV: %w", err)
+	}
+	return ccEff.Add(ccEff, foregone), alpha, nil
+}
+
+// EffectiveCensorshipCostWithSelfBuild is EffectiveCensorshipCostBy when
+// each proposer ignores the relays and builds its own block with
+// probability selfBuildProb. A locally built block includes the target
+// transaction unless the proposer itself is bribed, so only the cartel's
+// share of relay-built slots is censored for free:
+//
+//	C_c^eff = (1 - (1 - q) · α) · C_c
+//
+// Even a cartel with α = 1 then pays q · C_c. The returned α is the
+// builder concentration before the self-build adjustment.
+func EffectiveCensorshipCostWithSelfBuild(bribes []SlotBribe, tau uint64, topK int, metric ConcentrationMetric, selfBuildProb float64) (*big.Float, float64, error) {
+	if err := validateSelfBuildProbability(selfBuildProb); err != nil {
+		return nil, 0, err
+	}
+	cc, err := CensorshipCost(bribes, tau)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to compute censorship cost: %w", err)
+	}
+	alpha, _, err := ComputeConcentration(bribes, topK, metric)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to compute concentration: %w", err)
+	}
+
+	ccEff := new(big.Float).SetInt(cc)
+	return ccEff.Mul(ccEff, big.NewFloat(1-selfBuildAlpha(alpha, selfBuildProb))), alpha, nil
+}
+
+// validateSelfBuildProbability rejects probabilities outside [0, 1].
+func validateSelfBuildProbability(q float64) error {
+	if q < 0 || q > 1 {
+		return fmt.Errorf("%w: self-build probability %f (must be in [0,1])", ErrInvalidProbability, q)
+	}
+	return nil
+}
+
+// selfBuildAlpha is the share of slots the cartel censors for free when
+// proposers build locally with probability q.
+func selfBuildAlpha(alpha, q float64) float64 {
+	return (1 - q) * alpha
+}
+
 // ProfitParams contains parameters for attacker profit calculation.
 type ProfitParams struct {
-	BridgeTVL          *big.Float // V: Total Value Locked in bridge (wei)
-	SuccessProbability float64    // p: Probability of successful attack ∈ [0, 1]
-	Tau                uint64     // τ: Censorship duration in slots
-	TopK               int        // k: Number of top builders in cartel
+	BridgeTVL            *big.Float             // V: Total Value Locked in bridge (wei)
+	SuccessProbability   float64                // p: Probability of successful attack ∈ [0, 1]
+	Probability          ProbabilityModel       // p(V); overrides SuccessProbability when set
+	Tau                  uint64                 // τ: Censorship duration in slots
+	TopK                 int                    // k: Number of top builders in cartel
+	DiscountRate         float64                // r: Continuously compounded annual rate; 0 disables discounting
+	Coordination         *CoordinationCost      // Cartel overhead added to C_c^eff; nil means none
+	Penalty              *CartelPenalty         // Members' expected penalty added to C_c^eff; nil means none
+	InclusionLists       *InclusionListScenario // Share of slots with enforced inclusion lists; nil means none
+	SelfBuildProbability float64                // q: Probability a proposer builds locally instead of using a relay
+	ForegoneFraction     float64                // Share of cartel builders' block value given up (see ForegoneMEV); 0 means none
+}
+
+// CoordinationCost is the overhead of forming and running the cartel,
+// which C_c^eff alone treats as free: trust, communication and
+// enforcement between the colluding builders.
+//
+// For a cartel of k builders censoring τ slots it totals
+//
+//	C_coord = Fixed + k · τ · PerBuilderSlot
+//
+// Either component may be nil for zero.
+type CoordinationCost struct {
+	FixedWei          *big.Int // One-off formation cost
+	PerBuilderSlotWei *big.Int // Paid to keep each cartel builder in line, per slot
+}
+
+// fixed returns the one-off cost, 0 for a nil c.
+func (c *CoordinationCost) fixed() *big.Int {
+	if c == nil || c.FixedWei == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(c.FixedWei)
+}
+
+// perSlot returns the cost per slot of a cartel of k builders.
+func (c *CoordinationCost) perSlot(k int) *big.Int {
+	if c == nil || c.PerBuilderSlotWei == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(c.PerBuilderSlotWei, big.NewInt(int64(k)))
+}
+
+// total returns C_coord for a cartel of k builders over tau slots.
+func (c *CoordinationCost) total(k int, tau uint64) *big.Int {
+	total := c.perSlot(k)
+	total.Mul(total, new(big.Int).SetUint64(tau))
+	return total.Add(total, c.fixed())
+}
+
+// validate rejects negative components.
+func (c *CoordinationCost) validate() error {
+	if c == nil {
+		return nil
+	}
+	if (c.FixedWei != nil && c.FixedWei.Sign() < 0) || (c.PerBuilderSlotWei != nil && c.PerBuilderSlotWei.Sign() < 0) {
+		return fmt.Errorf("coordination cost cannot be negative")
+	}
+	return nil
 }
 
 // ProfitResult contains the output of profit calculation.
 type ProfitResult struct {
 	ExpectedRevenue *big.Float // p(V) * V
-	EffectiveCost   *big.Float // C_c^eff
+	EffectiveCost   *big.Float // C_c^eff, including CoordinationCost
 	Profit          *big.Float // P(V) = p(V)*V - C_c^eff
 	Alpha           float64    // Builder concentration coefficient
-	SuccessProb     float64    // p used in calculation
+	SuccessProb     float64    // p(V) used in calculation
 	TVL             *big.Float // V used in calculation
+
+	CoordinationCost *big.Float // C_coord included in EffectiveCost; 0 without params.Coordination
+	ExpectedPenalty  *big.Float // E[penalty] included in EffectiveCost; 0 without params.Penalty
+	ForegoneMEV      *big.Float // C_forgone included in EffectiveCost; 0 without params.ForegoneFraction
+
+	// Present values at the start of the attack under DiscountRate; equal
+	// to the nominal values when it is 0
+	DiscountedRevenue *big.Float // Revenue realized after τ slots
+	DiscountedCost    *big.Float // Bribes (times 1 - α) and per-slot coordination paid slot by slot, plus fixed coordination up front
+	DiscountedProfit  *big.Float // DiscountedRevenue - DiscountedCost
 }
 
 // AttackerProfit computes the expected profit from a censorship attack.
@@ -119,14 +660,32 @@ type ProfitResult struct {
 // Critical note:
 // We do NOT claim to know p(V). This function evaluates profit under
 // EXPLICIT assumptions about p. The caller must justify any p value used.
+// A TVL-dependent assumption is given as params.Probability (see
+// ProbabilityModel); otherwise p is the constant params.SuccessProbability.
+//
+// With a DiscountRate r, the result also carries present values: the bribe
+// for slot t is paid t slots into the attack and the revenue is realized
+// only once all τ slots (the challenge window) have passed, each
+// discounted by e^(-r·years). A τ spanning days makes this material.
+//
+// params.Coordination adds the cartel's overhead to C_c^eff and
+// params.Penalty its members' expected penalty; the cartel is the top k
+// builders, or all of them if there are fewer. Members are assumed to be
+// compensated for the penalty up front, so it is not discounted.
+// params.ForegoneFraction adds the cartel's foregone MEV (see ForegoneMEV),
+// likewise compensated up front.
+// params.InclusionLists prices the slots where an inclusion list forces
+// the transaction in (see InclusionListScenario), and
+// params.SelfBuildProbability those a proposer builds itself (see
+// EffectiveCensorshipCostWithSelfBuild).
 //
 // Returns:
 // - ProfitResult containing all economic parameters
 // - error if computation fails
 func AttackerProfit(bribes []SlotBribe, params ProfitParams) (*ProfitResult, error) {
 	// Validate inputs
-	if params.SuccessProbability < 0 || params.SuccessProbability > 1 {
-		return nil, fmt.Errorf("invalid success probability: %f (must be in [0,1])", params.SuccessProbability)
+	if params.Probability == nil && (params.SuccessProbability < 0 || params.SuccessProbability > 1) {
+		return nil, fmt.Errorf("%w: success probability %f (must be in [0,1])", ErrInvalidProbability, params.SuccessProbability)
 	}
 	if params.BridgeTVL == nil {
 		return nil, fmt.Errorf("BridgeTVL cannot be nil")
@@ -135,29 +694,161 @@ func AttackerProfit(bribes []SlotBribe, params ProfitParams) (*ProfitResult, err
 		return nil, fmt.Errorf("BridgeTVL cannot be negative")
 	}
 
-	// Compute effective censorship cost
-	ccEff, alpha, err := EffectiveCensorshipCost(bribes, params.Tau, params.TopK)
+	// Evaluate p(V) at this TVL
+	successProb := params.SuccessProbability
+	if params.Probability != nil {
+		var err error
+		if successProb, err = evaluateProbability(params.Probability, params.BridgeTVL); err != nil {
+			return nil, err
+		}
+	}
+
+	cost, err := computeAttackCost(bribes, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compute effective cost: %w", err)
+		return nil, err
 	}
 
+	return profitFromCost(params.BridgeTVL, successProb, params, cost), nil
+}
+
+// profitFromCost completes AttackerProfit for a TVL and success
+// probability once the cost side is known.
+func profitFromCost(tvl *big.Float, successProb float64, params ProfitParams, cost attackCost) *ProfitResult {
 	// Compute expected revenue: p(V) * V
-	pFloat := big.NewFloat(params.SuccessProbability)
-	expectedRevenue := new(big.Float).Mul(pFloat, params.BridgeTVL)
+	pFloat := big.NewFloat(successProb)
+	expectedRevenue := new(big.Float).Mul(pFloat, tvl)
 
 	// Compute profit: P(V) = p(V)*V - C_c^eff
-	profit := new(big.Float).Sub(expectedRevenue, ccEff)
+	profit := new(big.Float).Sub(expectedRevenue, cost.effective)
+
+	// Discount revenue to the start of the attack
+	discountedRevenue := new(big.Float).Mul(expectedRevenue, big.NewFloat(discountFactor(params.DiscountRate, params.Tau)))
 
 	return &ProfitResult{
-		ExpectedRevenue: expectedRevenue,
-		EffectiveCost:   ccEff,
-		Profit:          profit,
-		Alpha:           alpha,
-		SuccessProb:     params.SuccessProbability,
-		TVL:             new(big.Float).Set(params.BridgeTVL),
+		ExpectedRevenue:   expectedRevenue,
+		EffectiveCost:     new(big.Float).Set(cost.effective),
+		Profit:            profit,
+		Alpha:             cost.alpha,
+		SuccessProb:       successProb,
+		TVL:               new(big.Float).Set(tvl),
+		CoordinationCost:  new(big.Float).Set(cost.coordination),
+		ExpectedPenalty:   new(big.Float).Set(cost.penalty),
+		ForegoneMEV:       new(big.Float).Set(cost.foregone),
+		DiscountedRevenue: discountedRevenue,
+		DiscountedCost:    new(big.Float).Set(cost.discounted),
+		DiscountedProfit:  new(big.Float).Sub(discountedRevenue, cost.discounted),
+	}
+}
+
+// attackCost is the cost side of AttackerProfit, which does not depend on
+// the targeted bridge.
+type attackCost struct {
+	effective    *big.Float // C_c^eff with coordination and penalty
+	discounted   *big.Float // Present value of effective
+	coordination *big.Float
+	penalty      *big.Float
+	foregone     *big.Float
+	alpha        float64
+}
+
+// computeAttackCost validates the cost parameters of params and computes
+// the attack's cost.
+func computeAttackCost(bribes []SlotBribe, params ProfitParams) (attackCost, error) {
+	if params.DiscountRate < 0 || math.IsNaN(params.DiscountRate) {
+		return attackCost{}, fmt.Errorf("invalid discount rate: %f (must be non-negative)", params.DiscountRate)
+	}
+	if err := params.Coordination.validate(); err != nil {
+		return attackCost{}, err
+	}
+	if err := params.Penalty.validate(); err != nil {
+		return attackCost{}, err
+	}
+	if err := params.InclusionLists.validate(); err != nil {
+		return attackCost{}, err
+	}
+	if err := validateSelfBuildProbability(params.SelfBuildProbability); err != nil {
+		return attackCost{}, err
+	}
+
+	// Compute effective censorship cost
+	ccEff, alpha, err := bribeCost(bribes, params.Tau, params.TopK, params.SelfBuildProbability, params.InclusionLists)
+	if err != nil {
+		return attackCost{}, err
+	}
+
+	// Add coordination overhead for the cartel's actual size
+	cartelSize := min(params.TopK, GetBuilderDiversity(bribes))
+	coordination := new(big.Float).SetInt(params.Coordination.total(cartelSize, params.Tau))
+	ccEff.Add(ccEff, coordination)
+	penalty := params.Penalty.Expected(cartelSize)
+	ccEff.Add(ccEff, penalty)
+	foregone := new(big.Float)
+	if params.ForegoneFraction != 0 {
+		if foregone, err = ForegoneMEV(bribes, params.Tau, params.TopK, ConcentrationByBlocks, params.ForegoneFraction); err != nil {
+			return attackCost{}, fmt.Errorf("failed to compute foregone MEV: %w", err)
+		}
+		ccEff.Add(ccEff, foregone)
+	}
+
+	// Discount each slot's bribe from when it is paid
+	discountedCost := new(big.Float)
+	for i := uint64(0); i < params.Tau; i++ {
+		bribe := new(big.Float).SetInt(bribes[i].ValueWei)
+		discountedCost.Add(discountedCost, bribe.Mul(bribe, big.NewFloat(discountFactor(params.DiscountRate, i+1))))
+	}
+	discountedCost.Mul(discountedCost, big.NewFloat(params.InclusionLists.costFactor(selfBuildAlpha(alpha, params.SelfBuildProbability))))
+	discountedCost.Add(discountedCost, penalty)
+	discountedCost.Add(discountedCost, foregone)
+	if params.Coordination != nil {
+		// The fixed part is paid up front, the rest alongside each slot
+		discountedCost.Add(discountedCost, new(big.Float).SetInt(params.Coordination.fixed()))
+		perSlot := new(big.Float).SetInt(params.Coordination.perSlot(cartelSize))
+		for i := uint64(0); i < params.Tau; i++ {
+			discountedCost.Add(discountedCost, new(big.Float).Mul(perSlot, big.NewFloat(discountFactor(params.DiscountRate, i+1))))
+		}
+	}
+
+	return attackCost{
+		effective:    ccEff,
+		discounted:   discountedCost,
+		coordination: coordination,
+		penalty:      penalty,
+		foregone:     foregone,
+		alpha:        alpha,
 	}, nil
 }
 
+// bribeCost is the bribe term of C_c^eff, C_c times the share the
+// attacker pays once self-building proposers (selfBuildProb) and enforced
+// inclusion lists (scenario) are accounted for, along with the unadjusted
+// block-count α.
+func bribeCost(bribes []SlotBribe, tau uint64, topK int, selfBuildProb float64, scenario *InclusionListScenario) (*big.Float, float64, error) {
+	ccEff, alpha, err := EffectiveCensorshipCost(bribes, tau, topK)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to compute effective cost: %w", err)
+	}
+	if scenario == nil && selfBuildProb == 0 {
+		return ccEff, alpha, nil
+	}
+
+	cc, err := CensorshipCost(bribes, tau)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to compute censorship cost: %w", err)
+	}
+	cost := new(big.Float).SetInt(cc)
+	return cost.Mul(cost, big.NewFloat(scenario.costFactor(selfBuildAlpha(alpha, selfBuildProb)))), alpha, nil
+}
+
+// secondsPerYear is the Julian year used to convert annual rates.
+const secondsPerYear = 365.25 * 24 * 60 * 60
+
+// discountFactor is e^(-r·t) for a payment due the given number of mainnet
+// slots from now, with r an annual continuously compounded rate.
+func discountFactor(rate float64, slots uint64) float64 {
+	years := float64(slots*Mainnet.SecondsPerSlot) / secondsPerYear
+	return math.Exp(-rate * years)
+}
+
 // ProfitSweepResult contains results from sweeping probability values.
 type ProfitSweepResult struct {
 	Results []ProfitResult
@@ -181,16 +872,17 @@ type ProfitSweepResult struct {
 // - minP, maxP: probability range to sweep
 // - steps: number of evaluation points
 //
-// Returns sweep results for analysis.
-func SweepProbability(bribes []SlotBribe, tvl *big.Float, tau uint64, topK int, minP, maxP float64, steps int) (*ProfitSweepResult, error) {
+// Returns sweep results for analysis, or ctx's error if ctx is cancelled
+// before the sweep completes.
+func SweepProbability(ctx context.Context, bribes []SlotBribe, tvl *big.Float, tau uint64, topK int, minP, maxP float64, steps int) (*ProfitSweepResult, error) {
 	if steps < 1 {
 		return nil, fmt.Errorf("steps must be at least 1, got %d", steps)
 	}
 	if minP < 0 || minP > 1 {
-		return nil, fmt.Errorf("minP must be in [0,1], got %f", minP)
+		return nil, fmt.Errorf("%w: minP must be in [0,1], got %f", ErrInvalidProbability, minP)
 	}
 	if maxP < 0 || maxP > 1 {
-		return nil, fmt.Errorf("maxP must be in [0,1], got %f", maxP)
+		return nil, fmt.Errorf("%w: maxP must be in [0,1], got %f", ErrInvalidProbability, maxP)
 	}
 	if minP > maxP {
 		return nil, fmt.Errorf("minP (%f) must be <= maxP (%f)", minP, maxP)
@@ -200,6 +892,9 @@ func SweepProbability(bribes []SlotBribe, tvl *big.Float, tau uint64, topK int,
 
 	// Handle single step case
 	if steps == 1 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		params := ProfitParams{
 			BridgeTVL:          tvl,
 			SuccessProbability: minP,
@@ -222,6 +917,9 @@ func SweepProbability(bribes []SlotBribe, tvl *big.Float, tau uint64, topK int,
 	// Multiple steps: sweep from minP to maxP
 	stepSize := (maxP - minP) / float64(steps-1)
 	for i := 0; i < steps; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		p := minP + float64(i)*stepSize
 
 		params := ProfitParams{
@@ -247,6 +945,270 @@ func SweepProbability(bribes []SlotBribe, tvl *big.Float, tau uint64, topK int,
 	}, nil
 }
 
+// TVLSweepResult contains results from sweeping bridge TVL.
+type TVLSweepResult struct {
+	Results []ProfitResult
+	MinTVL  *big.Float
+	MaxTVL  *big.Float
+	Steps   int
+
+	// Breakeven is V* = C_c^eff / p, where profit crosses zero; nil when
+	// p = 0 and no TVL is profitable
+	Breakeven *big.Float
+	// FirstProfitable is the index of the first result with positive
+	// profit, or -1 if none is
+	FirstProfitable int
+}
+
+// SweepTVL evaluates profit across a range of bridge TVLs at a fixed
+// success probability, the counterpart of SweepProbability.
+//
+// TVLs are spaced evenly from minTVL to maxTVL in big.Float precision, so
+// sweeps over wei amounts do not lose digits to float64. Since profit is
+// linear in V, the exact breakeven crossing is reported alongside the
+// sampled points. Cancelling ctx stops the sweep with ctx's error.
+func SweepTVL(ctx context.Context, bribes []SlotBribe, successProb float64, tau uint64, topK int, minTVL, maxTVL *big.Float, steps int) (*TVLSweepResult, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1, got %d", steps)
+	}
+	if successProb < 0 || successProb > 1 {
+		return nil, fmt.Errorf("%w: success probability must be in [0,1], got %f", ErrInvalidProbability, successProb)
+	}
+	tvls, err := tvlPoints(minTVL, maxTVL, steps)
+	if err != nil {
+		return nil, err
+	}
+
+	sweep := &TVLSweepResult{
+		Results:         make([]ProfitResult, 0, steps),
+		MinTVL:          new(big.Float).Set(minTVL),
+		MaxTVL:          new(big.Float).Set(maxTVL),
+		Steps:           steps,
+		FirstProfitable: -1,
+	}
+	for i, tvl := range tvls {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		params := ProfitParams{
+			BridgeTVL:          tvl,
+			SuccessProbability: successProb,
+			Tau:                tau,
+			TopK:               topK,
+		}
+
+		result, err := AttackerProfit(bribes, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed at TVL=%s: %w", tvl.String(), err)
+		}
+		if sweep.FirstProfitable < 0 && result.Profit.Sign() > 0 {
+			sweep.FirstProfitable = i
+		}
+		sweep.Results = append(sweep.Results, *result)
+	}
+
+	if successProb > 0 {
+		breakeven, _, err := FindBreakevenTVL(bribes, successProb, tau, topK)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute breakeven: %w", err)
+		}
+		sweep.Breakeven = breakeven
+	}
+
+	return sweep, nil
+}
+
+// TauSweepPoint is the cost and breakeven for one censorship duration.
+type TauSweepPoint struct {
+	Tau            uint64
+	CensorshipCost *big.Int   // C_c(τ)
+	EffectiveCost  *big.Float // C_c^eff(τ)
+	Breakeven      *big.Float // V*(τ) = C_c^eff(τ) / p
+}
+
+// TauSweepResult contains results from sweeping censorship duration.
+type TauSweepResult struct {
+	Points      []TauSweepPoint
+	Alpha       float64 // Builder concentration, which does not depend on τ
+	SuccessProb float64
+}
+
+// ctxCheckInterval is how many slots per-slot loops process between
+// checks for cancellation.
+const ctxCheckInterval = 1 << 16
+
+// SweepTau evaluates C_c, C_c^eff and V* for τ = minTau, minTau+step, ...
+// up to maxTau. A bridge's fraud-proof window fixes τ, so this shows how
+// its safety margin grows with the window length.
+//
+// C_c(τ) is accumulated once across the sweep rather than recomputed for
+// each τ. ctx is checked every ctxCheckInterval slots, so cancelling it
+// stops sweeps over millions of slots promptly.
+func SweepTau(ctx context.Context, bribes []SlotBribe, successProb float64, topK int, minTau, maxTau, step uint64) (*TauSweepResult, error) {
+	if successProb <= 0 || successProb > 1 {
+		return nil, fmt.Errorf("%w: success probability must be in (0,1], got %f", ErrInvalidProbability, successProb)
+	}
+	if step < 1 {
+		return nil, fmt.Errorf("step must be at least 1, got %d", step)
+	}
+	if minTau < 1 {
+		return nil, fmt.Errorf("minTau must be at least 1, got %d", minTau)
+	}
+	if minTau > maxTau {
+		return nil, fmt.Errorf("minTau (%d) must be <= maxTau (%d)", minTau, maxTau)
+	}
+	if uint64(len(bribes)) < maxTau {
+		return nil, fmt.Errorf("%w: need %d slots, have %d", ErrInsufficientData, maxTau, len(bribes))
+	}
+
+	alpha, _, err := ComputeBuilderConcentration(bribes, topK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute concentration: %w", err)
+	}
+
+	result := &TauSweepResult{
+		Points:      make([]TauSweepPoint, 0, (maxTau-minTau)/step+1),
+		Alpha:       alpha,
+		SuccessProb: successProb,
+	}
+	cc := new(big.Int)
+	next := minTau
+	for i := uint64(0); i < maxTau; i++ {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if bribes[i].ValueWei == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilValue, i)
+		}
+		cc.Add(cc, bribes[i].ValueWei)
+		if i+1 != next {
+			continue
+		}
+
+		ccEff := new(big.Float).Mul(new(big.Float).SetInt(cc), big.NewFloat(1-alpha))
+		result.Points = append(result.Points, TauSweepPoint{
+			Tau:            next,
+			CensorshipCost: new(big.Int).Set(cc),
+			EffectiveCost:  ccEff,
+			Breakeven:      new(big.Float).Quo(ccEff, big.NewFloat(successProb)),
+		})
+		if maxTau-next < step {
+			break
+		}
+		next += step
+	}
+
+	return result, nil
+}
+
+// TopKSweepPoint is the concentration, cost and breakeven for one cartel
+// size.
+type TopKSweepPoint struct {
+	TopK          int
+	Alpha         float64    // α(k)
+	EffectiveCost *big.Float // C_c^eff(k)
+	Breakeven     *big.Float // V*(k) = C_c^eff(k) / p
+}
+
+// TopKSweepResult contains results from sweeping cartel size.
+type TopKSweepResult struct {
+	Points         []TopKSweepPoint // k = 1 .. number of builders
+	CensorshipCost *big.Int         // C_c(τ), which does not depend on k
+	SuccessProb    float64
+}
+
+// SweepTopK evaluates α, C_c^eff and V* for every cartel size from one
+// builder up to all of them. Larger cartels only lower V*, so the first
+// size whose V* falls below a bridge's TVL is how many builders must
+// collude to attack it profitably (see MinCartelSize).
+//
+// Builders are ranked once; α(k) is the running share of the top k, as
+// in ComputeBuilderConcentration. Cancelling ctx stops the sweep with
+// ctx's error.
+func SweepTopK(ctx context.Context, bribes []SlotBribe, successProb float64, tau uint64) (*TopKSweepResult, error) {
+	if successProb <= 0 || successProb > 1 {
+		return nil, fmt.Errorf("%w: success probability must be in (0,1], got %f", ErrInvalidProbability, successProb)
+	}
+
+	cc, err := CensorshipCost(bribes, tau)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute censorship cost: %w", err)
+	}
+	_, stats, err := ComputeBuilderConcentration(bribes, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute concentration: %w", err)
+	}
+
+	var total uint64
+	for _, stat := range stats {
+		total += stat.BlockCount
+	}
+
+	result := &TopKSweepResult{
+		Points:         make([]TopKSweepPoint, len(stats)),
+		CensorshipCost: cc,
+		SuccessProb:    successProb,
+	}
+	ccFloat := new(big.Float).SetInt(cc)
+	var topKBlocks uint64
+	for i, stat := range stats {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		topKBlocks += stat.BlockCount
+		alpha := float64(topKBlocks) / float64(total)
+		ccEff := new(big.Float).Mul(ccFloat, big.NewFloat(1-alpha))
+		result.Points[i] = TopKSweepPoint{
+			TopK:          i + 1,
+			Alpha:         alpha,
+			EffectiveCost: ccEff,
+			Breakeven:     new(big.Float).Quo(ccEff, big.NewFloat(successProb)),
+		}
+	}
+
+	return result, nil
+}
+
+// MinCartelSize returns the smallest k for which attacking a bridge with
+// the given TVL is profitable (V*(k) < tvl), or 0 if no cartel is large
+// enough.
+func (r *TopKSweepResult) MinCartelSize(tvl *big.Float) int {
+	for _, point := range r.Points {
+		if point.Breakeven.Cmp(tvl) < 0 {
+			return point.TopK
+		}
+	}
+	return 0
+}
+
+// tvlPoints spaces steps TVLs evenly from minTVL to maxTVL, or returns
+// just minTVL for a single step.
+func tvlPoints(minTVL, maxTVL *big.Float, steps int) ([]*big.Float, error) {
+	if minTVL == nil || maxTVL == nil {
+		return nil, fmt.Errorf("minTVL and maxTVL cannot be nil")
+	}
+	if minTVL.Sign() < 0 {
+		return nil, fmt.Errorf("minTVL cannot be negative")
+	}
+	if minTVL.Cmp(maxTVL) > 0 {
+		return nil, fmt.Errorf("minTVL (%s) must be <= maxTVL (%s)", minTVL.String(), maxTVL.String())
+	}
+
+	stepSize := new(big.Float)
+	if steps > 1 {
+		stepSize.Sub(maxTVL, minTVL)
+		stepSize.Quo(stepSize, new(big.Float).SetInt64(int64(steps-1)))
+	}
+	tvls := make([]*big.Float, steps)
+	for i := range tvls {
+		tvls[i] = new(big.Float).Mul(stepSize, new(big.Float).SetInt64(int64(i)))
+		tvls[i].Add(tvls[i], minTVL)
+	}
+	return tvls, nil
+}
+
 // FindBreakevenTVL finds the minimum TVL where profit becomes positive.
 //
 // This is the threshold V* where:
@@ -261,7 +1223,7 @@ func SweepProbability(bribes []SlotBribe, tvl *big.Float, tau uint64, topK int,
 // This function implements the "kill shot" calculation from the blueprint.
 func FindBreakevenTVL(bribes []SlotBribe, successProb float64, tau uint64, topK int) (*big.Float, float64, error) {
 	if successProb <= 0 || successProb > 1 {
-		return nil, 0, fmt.Errorf("success probability must be in (0,1], got %f", successProb)
+		return nil, 0, fmt.Errorf("%w: success probability must be in (0,1], got %f", ErrInvalidProbability, successProb)
 	}
 
 	// Compute effective censorship cost
@@ -276,3 +1238,69 @@ func FindBreakevenTVL(bribes []SlotBribe, successProb float64, tau uint64, topK
 
 	return breakeven, alpha, nil
 }
+
+// FindBreakevenTau finds the longest censorship duration an attacker can
+// afford against a bridge: the largest τ with
+//
+//	C_c^eff(τ) < p · V
+//
+// Bids are non-negative, so the cost only grows with τ and a challenge
+// window of τ* + 1 slots or more prices the attack out. τ* is 0 when even
+// one slot costs more than the expected revenue. α is measured over all of
+// bribes, as for EffectiveCensorshipCost.
+//
+// If every τ the data covers stays below p · V, the data cannot bound τ*
+// and an error is returned rather than a misleadingly small window.
+func FindBreakevenTau(bribes []SlotBribe, tvl *big.Float, successProb float64, topK int) (uint64, float64, error) {
+	if tvl == nil || tvl.Sign() < 0 {
+		return 0, 0, fmt.Errorf("TVL cannot be nil or negative")
+	}
+	if successProb < 0 || successProb > 1 {
+		return 0, 0, fmt.Errorf("%w: success probability must be in [0,1], got %f", ErrInvalidProbability, successProb)
+	}
+
+	alpha, _, err := ComputeBuilderConcentration(bribes, topK)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compute concentration: %w", err)
+	}
+
+	budget := new(big.Float).Mul(tvl, big.NewFloat(successProb))
+	discount := big.NewFloat(1 - alpha)
+	cc := new(big.Int)
+	for i, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			return 0, 0, fmt.Errorf("%w at index %d", ErrNilValue, i)
+		}
+		cc.Add(cc, bribe.ValueWei)
+		ccEff := new(big.Float).Mul(new(big.Float).SetInt(cc), discount)
+		if ccEff.Cmp(budget) >= 0 {
+			return uint64(i), alpha, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%w: all %d slots cost less than p·V; more data is needed to bound tau", ErrInsufficientData, len(bribes))
+}
+
+// FindBreakevenProbability finds the minimum success probability at which
+// attacking a bridge with the given TVL becomes profitable.
+//
+//	p* = C_c^eff / V
+//
+// For a bridge operator whose TVL is fixed this is often the more
+// intuitive threshold: the attack pays off if the attacker believes it
+// succeeds with probability above p*. A p* above 1 means no probability
+// makes the attack profitable; it is returned as is so the margin is
+// visible.
+func FindBreakevenProbability(bribes []SlotBribe, tvl *big.Float, tau uint64, topK int) (float64, float64, error) {
+	if tvl == nil || tvl.Sign() <= 0 {
+		return 0, 0, fmt.Errorf("TVL must be positive")
+	}
+
+	ccEff, alpha, err := EffectiveCensorshipCost(bribes, tau, topK)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compute effective cost: %w", err)
+	}
+
+	// p* = C_c^eff / V
+	pStar, _ := new(big.Float).Quo(ccEff, tvl).Float64()
+	return pStar, alpha, nil
+}
@@ -1,8 +1,11 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"math"
 	"math/big"
+	"reflect"
 	"testing"
 )
 
@@ -159,6 +162,177 @@ func TestCensorshipCost_Deterministic(t *testing.T) {
 	}
 }
 
+// TestCensorshipCostWindow verifies the window is located by slot number
+// and that missing slots fail rather than shorten the window.
+func TestCensorshipCostWindow(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 100, ValueWei: big.NewInt(1)},
+		{Slot: 101, ValueWei: big.NewInt(2)},
+		{Slot: 102, ValueWei: big.NewInt(4)},
+		{Slot: 104, ValueWei: big.NewInt(8)}, // Slot 103 missing
+	}
+
+	cost, err := CensorshipCostWindow(bribes, 101, 2)
+	if err != nil {
+		t.Fatalf("CensorshipCostWindow failed: %v", err)
+	}
+	if cost.Cmp(big.NewInt(6)) != 0 {
+		t.Errorf("expected cost 6, got %s", cost.String())
+	}
+
+	failures := []struct {
+		name      string
+		startSlot uint64
+		tau       uint64
+	}{
+		{"gap", 101, 3},
+		{"absent start", 103, 1},
+		{"past end", 104, 2},
+		{"before data", 99, 1},
+	}
+	for _, f := range failures {
+		if _, err := CensorshipCostWindow(bribes, f.startSlot, f.tau); err == nil {
+			t.Errorf("Expected error for %s, got nil", f.name)
+		}
+	}
+}
+
+// TestCensorshipCostRange verifies the exact sum of a complete range and
+// that every missing slot is reported.
+func TestCensorshipCostRange(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 100, ValueWei: big.NewInt(1)},
+		{Slot: 101, ValueWei: big.NewInt(2)},
+		{Slot: 103, ValueWei: big.NewInt(4)},
+		{Slot: 104, ValueWei: big.NewInt(8)},
+	}
+
+	cost, err := CensorshipCostRange(bribes, 103, 104)
+	if err != nil {
+		t.Fatalf("CensorshipCostRange failed: %v", err)
+	}
+	if cost.Cmp(big.NewInt(12)) != 0 {
+		t.Errorf("expected cost 12, got %s", cost.String())
+	}
+
+	// 99 precedes the data, 102 is a gap and 105-106 follow it
+	_, err = CensorshipCostRange(bribes, 99, 106)
+	var missingErr *MissingSlotsError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("Expected *MissingSlotsError, got %v", err)
+	}
+	if want := []uint64{99, 102, 105, 106}; !reflect.DeepEqual(missingErr.Missing, want) {
+		t.Errorf("expected missing slots %v, got %v", want, missingErr.Missing)
+	}
+
+	if _, err := CensorshipCostRange(bribes, 104, 103); err == nil {
+		t.Error("Expected error for inverted range, got nil")
+	}
+	duplicated := []SlotBribe{bribes[0], bribes[0]}
+	if _, err := CensorshipCostRange(duplicated, 100, 100); err == nil {
+		t.Error("Expected error for duplicate slot, got nil")
+	}
+
+	last := []SlotBribe{{Slot: math.MaxUint64, ValueWei: big.NewInt(5)}}
+	if cost, err := CensorshipCostRange(last, math.MaxUint64, math.MaxUint64); err != nil || cost.Int64() != 5 {
+		t.Errorf("expected cost 5 for the largest slot, got %v (err %v)", cost, err)
+	}
+}
+
+// TestCensorshipCostRangeBy verifies each gap policy on a range with a
+// coverage hole.
+func TestCensorshipCostRangeBy(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 100, ValueWei: big.NewInt(1)},
+		{Slot: 101, ValueWei: big.NewInt(3)},
+		{Slot: 103, ValueWei: big.NewInt(10)},
+		{Slot: 104, ValueWei: big.NewInt(6)},
+	}
+
+	// Present values 1, 3, 6, 10 have median (3+6)/2 = 4 (rounded down)
+	// and slots 102 and 105 are missing
+	tests := []struct {
+		policy GapPolicy
+		want   int64
+	}{
+		{GapTreatAsZero, 20},
+		{GapImputeMedian, 28},
+	}
+	for _, tt := range tests {
+		cost, err := CensorshipCostRangeBy(bribes, 100, 105, tt.policy)
+		if err != nil {
+			t.Fatalf("CensorshipCostRangeBy(%s) failed: %v", tt.policy, err)
+		}
+		if cost.Int64() != tt.want {
+			t.Errorf("expected %s cost %d, got %s", tt.policy, tt.want, cost.String())
+		}
+	}
+
+	var missingErr *MissingSlotsError
+	if _, err := CensorshipCostRangeBy(bribes, 100, 105, GapFail); !errors.As(err, &missingErr) {
+		t.Errorf("Expected *MissingSlotsError under %s, got %v", GapFail, err)
+	}
+	if _, err := CensorshipCostRangeBy(bribes, 200, 201, GapImputeMedian); err == nil {
+		t.Error("Expected error imputing a range with no slots, got nil")
+	}
+
+	// The input order must survive the median's sort
+	if bribes[2].ValueWei.Int64() != 10 {
+		t.Errorf("expected input values unchanged, got %s at slot 103", bribes[2].ValueWei)
+	}
+
+	if policy, err := ParseGapPolicy(""); err != nil || policy != GapFail {
+		t.Errorf("expected default policy %s, got %s (err %v)", GapFail, policy, err)
+	}
+	if _, err := ParseGapPolicy("skip"); err == nil {
+		t.Error("Expected error for unknown policy, got nil")
+	}
+}
+
+// TestFindCheapestWindow verifies the cheapest and most expensive windows
+// and that windows spanning a gap are skipped.
+func TestFindCheapestWindow(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 10, ValueWei: big.NewInt(5)},
+		{Slot: 11, ValueWei: big.NewInt(1)},
+		{Slot: 12, ValueWei: big.NewInt(9)},
+		{Slot: 14, ValueWei: big.NewInt(0)}, // Slot 13 missing
+		{Slot: 15, ValueWei: big.NewInt(0)},
+		{Slot: 16, ValueWei: big.NewInt(2)},
+	}
+
+	// Windows: 10-11=6, 11-12=10, 14-15=0, 15-16=2; 12-14 spans the gap
+	result, err := FindCheapestWindow(bribes, 2)
+	if err != nil {
+		t.Fatalf("FindCheapestWindow failed: %v", err)
+	}
+	if result.Windows != 4 {
+		t.Errorf("expected 4 windows, got %d", result.Windows)
+	}
+	if result.Cheapest.StartSlot != 14 || result.Cheapest.CostWei.Int64() != 0 {
+		t.Errorf("expected cheapest window at 14 costing 0, got %d costing %s", result.Cheapest.StartSlot, result.Cheapest.CostWei)
+	}
+	if result.MostExpensive.StartSlot != 11 || result.MostExpensive.CostWei.Int64() != 10 {
+		t.Errorf("expected most expensive window at 11 costing 10, got %d costing %s", result.MostExpensive.StartSlot, result.MostExpensive.CostWei)
+	}
+
+	// The cheapest window must agree with CensorshipCostWindow
+	cost, err := CensorshipCostWindow(bribes, result.Cheapest.StartSlot, 2)
+	if err != nil || cost.Cmp(result.Cheapest.CostWei) != 0 {
+		t.Errorf("expected CensorshipCostWindow to give %s, got %v (err %v)", result.Cheapest.CostWei, cost, err)
+	}
+
+	if _, err := FindCheapestWindow(bribes, 4); err == nil {
+		t.Error("Expected error when no gap-free window exists, got nil")
+	}
+	if _, err := FindCheapestWindow(bribes, 0); err == nil {
+		t.Error("Expected error for tau=0, got nil")
+	}
+	if _, err := FindCheapestWindow([]SlotBribe{bribes[1], bribes[0]}, 1); err == nil {
+		t.Error("Expected error for unsorted bribes, got nil")
+	}
+}
+
 // ========================================================================
 // PHASE 4: EFFECTIVE CENSORSHIP COST TESTS
 // ========================================================================
@@ -316,6 +490,68 @@ func TestEffectiveCensorshipCost_Formula(t *testing.T) {
 	}
 }
 
+// TestEffectiveCensorshipCostBy_Value verifies the value-weighted discount.
+func TestEffectiveCensorshipCostBy_Value(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(2000), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(3000), BuilderPubkey: "0xB"},
+		{Slot: 4, ValueWei: big.NewInt(4000), BuilderPubkey: "0xC"},
+	}
+
+	// Top-1 by value: C has 4000 of 10000, α_v = 0.4
+	// C_c^eff = (1 - 0.4) * 10000 = 6000
+	ccEff, alpha, err := EffectiveCensorshipCostBy(bribes, 4, 1, ConcentrationByValue)
+	if err != nil {
+		t.Fatalf("EffectiveCensorshipCostBy failed: %v", err)
+	}
+	if alpha != 0.4 {
+		t.Errorf("expected alpha=0.4, got %f", alpha)
+	}
+	if expectedCost := big.NewFloat(6000.0); !floatEqual(ccEff, expectedCost, 0.01) {
+		t.Errorf("expected effective cost %s, got %s", expectedCost.String(), ccEff.String())
+	}
+
+	if _, _, err := EffectiveCensorshipCostBy(bribes, 4, 1, "stake"); err == nil {
+		t.Error("Expected error for unknown metric, got nil")
+	}
+}
+
+// TestEffectiveCensorshipCostExact verifies α and C_c^eff are exact
+// fractions under both metrics, including ones float64 cannot represent.
+func TestEffectiveCensorshipCostExact(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1), BuilderPubkey: "0xB"},
+		{Slot: 3, ValueWei: big.NewInt(8), BuilderPubkey: "0xC"},
+	}
+
+	// Top-1 by blocks: α = 1/3, C_c^eff = (2/3) * 10 = 20/3
+	ccEff, alpha, err := EffectiveCensorshipCostExact(bribes, 3, 1, ConcentrationByBlocks)
+	if err != nil {
+		t.Fatalf("EffectiveCensorshipCostExact failed: %v", err)
+	}
+	if alpha.Cmp(big.NewRat(1, 3)) != 0 {
+		t.Errorf("expected alpha=1/3, got %s", alpha.RatString())
+	}
+	if ccEff.Cmp(big.NewRat(20, 3)) != 0 {
+		t.Errorf("expected effective cost 20/3, got %s", ccEff.RatString())
+	}
+
+	// Top-1 by value: C has 8 of 10, C_c^eff = (1/5) * 10 = 2
+	ccEff, alpha, err = EffectiveCensorshipCostExact(bribes, 3, 1, ConcentrationByValue)
+	if err != nil {
+		t.Fatalf("EffectiveCensorshipCostExact failed: %v", err)
+	}
+	if alpha.Cmp(big.NewRat(4, 5)) != 0 || ccEff.Cmp(big.NewRat(2, 1)) != 0 {
+		t.Errorf("expected alpha=4/5 and cost 2, got %s and %s", alpha.RatString(), ccEff.RatString())
+	}
+
+	if _, _, err := EffectiveCensorshipCostExact(bribes, 4, 1, ConcentrationByBlocks); err == nil {
+		t.Error("Expected error for insufficient slots, got nil")
+	}
+}
+
 // TestEffectiveCensorshipCost_TopKVariation verifies different k values.
 func TestEffectiveCensorshipCost_TopKVariation(t *testing.T) {
 	bribes := []SlotBribe{
@@ -478,6 +714,355 @@ func TestAttackerProfit_Basic(t *testing.T) {
 	}
 }
 
+// TestAttackerProfit_Discounted verifies bribes are discounted from the
+// slot they are paid and revenue from the end of the window.
+func TestAttackerProfit_Discounted(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(2000), BuilderPubkey: "0xB"},
+	}
+	params := ProfitParams{
+		BridgeTVL:          big.NewFloat(10000),
+		SuccessProbability: 0.5,
+		Tau:                2,
+		TopK:               1,
+	}
+
+	// Without a rate, present values equal nominal ones
+	result, err := AttackerProfit(bribes, params)
+	if err != nil {
+		t.Fatalf("AttackerProfit failed: %v", err)
+	}
+	if !floatEqual(result.DiscountedProfit, result.Profit, 0.01) {
+		t.Errorf("expected discounted profit %s, got %s", result.Profit.String(), result.DiscountedProfit.String())
+	}
+
+	// A rate halving value every slot: cost (1-0.5)·(1000/2 + 2000/4) = 500,
+	// revenue 5000/4 = 1250, profit 750
+	params.DiscountRate = math.Ln2 * secondsPerYear / float64(Mainnet.SecondsPerSlot)
+	result, err = AttackerProfit(bribes, params)
+	if err != nil {
+		t.Fatalf("AttackerProfit failed: %v", err)
+	}
+	if !floatEqual(result.DiscountedCost, big.NewFloat(500), 0.01) {
+		t.Errorf("expected discounted cost 500, got %s", result.DiscountedCost.String())
+	}
+	if !floatEqual(result.DiscountedRevenue, big.NewFloat(1250), 0.01) {
+		t.Errorf("expected discounted revenue 1250, got %s", result.DiscountedRevenue.String())
+	}
+	if !floatEqual(result.DiscountedProfit, big.NewFloat(750), 0.01) {
+		t.Errorf("expected discounted profit 750, got %s", result.DiscountedProfit.String())
+	}
+	if !floatEqual(result.Profit, big.NewFloat(3500), 0.01) {
+		t.Errorf("expected nominal profit 3500, got %s", result.Profit.String())
+	}
+
+	params.DiscountRate = -0.05
+	if _, err := AttackerProfit(bribes, params); err == nil {
+		t.Error("Expected error for negative discount rate, got nil")
+	}
+}
+
+// TestAttackerProfit_Coordination verifies coordination overhead is added
+// to C_c^eff for the cartel's actual size.
+func TestAttackerProfit_Coordination(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(2000), BuilderPubkey: "0xB"},
+	}
+
+	// k=5 but only 2 builders exist: C_coord = 100 + 2·2·10 = 140,
+	// and α = 1 leaves C_c^eff = 0 + 140
+	result, err := AttackerProfit(bribes, ProfitParams{
+		BridgeTVL:          big.NewFloat(10000),
+		SuccessProbability: 0.5,
+		Tau:                2,
+		TopK:               5,
+		Coordination:       &CoordinationCost{FixedWei: big.NewInt(100), PerBuilderSlotWei: big.NewInt(10)},
+	})
+	if err != nil {
+		t.Fatalf("AttackerProfit failed: %v", err)
+	}
+	if !floatEqual(result.CoordinationCost, big.NewFloat(140), 0.01) {
+		t.Errorf("expected coordination cost 140, got %s", result.CoordinationCost.String())
+	}
+	if !floatEqual(result.EffectiveCost, big.NewFloat(140), 0.01) {
+		t.Errorf("expected effective cost 140, got %s", result.EffectiveCost.String())
+	}
+	if !floatEqual(result.DiscountedCost, result.EffectiveCost, 0.01) {
+		t.Errorf("expected undiscounted cost %s, got %s", result.EffectiveCost.String(), result.DiscountedCost.String())
+	}
+
+	_, err = AttackerProfit(bribes, ProfitParams{
+		BridgeTVL:          big.NewFloat(10000),
+		SuccessProbability: 0.5,
+		Tau:                2,
+		TopK:               1,
+		Coordination:       &CoordinationCost{FixedWei: big.NewInt(-1)},
+	})
+	if err == nil {
+		t.Error("Expected error for negative coordination cost, got nil")
+	}
+}
+
+// TestAttackerProfit_Penalty verifies the expected penalty to cartel
+// builders and proposers is added to C_c^eff.
+func TestAttackerProfit_Penalty(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+	}
+
+	// k=1: C_c^eff = 0.5·2000 = 1000, plus E[penalty] = 0.25·(1·400 + 2·1000) = 600
+	result, err := AttackerProfit(bribes, ProfitParams{
+		BridgeTVL:          big.NewFloat(10000),
+		SuccessProbability: 0.5,
+		Tau:                2,
+		TopK:               1,
+		Penalty: &CartelPenalty{
+			DetectionProbability: 0.25,
+			BuilderFutureMEVWei:  big.NewInt(400),
+			ProposerStakeWei:     big.NewInt(1000),
+			Proposers:            2,
+		},
+	})
+	if err != nil {
+		t.Fatalf("AttackerProfit failed: %v", err)
+	}
+	if !floatEqual(result.ExpectedPenalty, big.NewFloat(600), 0.01) {
+		t.Errorf("expected penalty 600, got %s", result.ExpectedPenalty.String())
+	}
+	if !floatEqual(result.EffectiveCost, big.NewFloat(1600), 0.01) {
+		t.Errorf("expected effective cost 1600, got %s", result.EffectiveCost.String())
+	}
+	if !floatEqual(result.Profit, big.NewFloat(3400), 0.01) {
+		t.Errorf("expected profit 3400, got %s", result.Profit.String())
+	}
+
+	_, err = AttackerProfit(bribes, ProfitParams{
+		BridgeTVL:          big.NewFloat(10000),
+		SuccessProbability: 0.5,
+		Tau:                2,
+		TopK:               1,
+		Penalty:            &CartelPenalty{DetectionProbability: 1.5},
+	})
+	if err == nil {
+		t.Error("Expected error for detection probability above 1, got nil")
+	}
+}
+
+// TestForegoneMEV verifies only cartel builders' bids within τ count, that
+// the cartel follows the concentration metric, and that the term adds to
+// the effective cost and to AttackerProfit.
+func TestForegoneMEV(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(2000), BuilderPubkey: "0xB"},
+		{Slot: 3, ValueWei: big.NewInt(4000), BuilderPubkey: "0xA"},
+		{Slot: 4, ValueWei: big.NewInt(8000), BuilderPubkey: "0xA"}, // Beyond τ
+	}
+
+	// Cartel {0xA}; within τ=3 it won 1000 + 4000, of which 10% is foregone
+	foregone, err := ForegoneMEV(bribes, 3, 1, ConcentrationByBlocks, 0.1)
+	if err != nil {
+		t.Fatalf("ForegoneMEV failed: %v", err)
+	}
+	if !floatEqual(foregone, big.NewFloat(500), 0.01) {
+		t.Errorf("expected foregone MEV 500, got %s", foregone.String())
+	}
+
+	// α = 0.75: C_c^eff = 0.25·7000 + 500 = 2250
+	ccEff, _, err := EffectiveCensorshipCostWithForegone(bribes, 3, 1, ConcentrationByBlocks, 0.1)
+	if err != nil {
+		t.Fatalf("EffectiveCensorshipCostWithForegone failed: %v", err)
+	}
+	if !floatEqual(ccEff, big.NewFloat(2250), 0.01) {
+		t.Errorf("expected effective cost 2250, got %s", ccEff.String())
+	}
+
+	if _, err := ForegoneMEV(bribes, 3, 1, ConcentrationByBlocks, 1.5); err == nil {
+		t.Error("Expected error for foregone fraction above 1, got nil")
+	}
+	if _, err := ForegoneMEV(bribes, 5, 1, ConcentrationByBlocks, 0.1); err == nil {
+		t.Error("Expected error for insufficient slots, got nil")
+	}
+
+	// AttackerProfit adds the same term: 10000 - 2250 = 7750
+	params := ProfitParams{
+		BridgeTVL:          big.NewFloat(10000),
+		SuccessProbability: 1,
+		Tau:                3,
+		TopK:               1,
+		ForegoneFraction:   0.1,
+	}
+	result, err := AttackerProfit(bribes, params)
+	if err != nil {
+		t.Fatalf("AttackerProfit failed: %v", err)
+	}
+	if !floatEqual(result.ForegoneMEV, big.NewFloat(500), 0.01) {
+		t.Errorf("expected foregone MEV 500, got %s", result.ForegoneMEV.String())
+	}
+	if !floatEqual(result.EffectiveCost, big.NewFloat(2250), 0.01) {
+		t.Errorf("expected effective cost 2250, got %s", result.EffectiveCost.String())
+	}
+	if !floatEqual(result.Profit, big.NewFloat(7750), 0.01) {
+		t.Errorf("expected profit 7750, got %s", result.Profit.String())
+	}
+	params.ForegoneFraction = 1.5
+	if _, err := AttackerProfit(bribes, params); err == nil {
+		t.Error("Expected error for foregone fraction above 1, got nil")
+	}
+
+	// 0xA wins more blocks, 0xB more value: the metric picks the cartel
+	skewed := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(10000), BuilderPubkey: "0xB"},
+	}
+	tests := []struct {
+		metric   ConcentrationMetric
+		foregone float64
+		ccEff    float64
+	}{
+		{ConcentrationByBlocks, 20, 10200.0/3 + 20}, // α = 2/3
+		{ConcentrationByValue, 1000, 200 + 1000},    // α = 10000/10200
+	}
+	for _, tt := range tests {
+		foregone, err := ForegoneMEV(skewed, 3, 1, tt.metric, 0.1)
+		if err != nil {
+			t.Fatalf("%s: ForegoneMEV failed: %v", tt.metric, err)
+		}
+		if !floatEqual(foregone, big.NewFloat(tt.foregone), 0.01) {
+			t.Errorf("%s: expected foregone MEV %v, got %s", tt.metric, tt.foregone, foregone.String())
+		}
+		ccEff, _, err := EffectiveCensorshipCostWithForegone(skewed, 3, 1, tt.metric, 0.1)
+		if err != nil {
+			t.Fatalf("%s: EffectiveCensorshipCostWithForegone failed: %v", tt.metric, err)
+		}
+		if !floatEqual(ccEff, big.NewFloat(tt.ccEff), 0.01) {
+			t.Errorf("%s: expected effective cost %v, got %s", tt.metric, tt.ccEff, ccEff.String())
+		}
+	}
+}
+
+// TestEffectiveCensorshipCostWithSelfBuild verifies self-building
+// proposers keep a monopoly cartel's cost above zero.
+func TestEffectiveCensorshipCostWithSelfBuild(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+	}
+
+	// α = 1, q = 0.1: C_c^eff = (1 - 0.9) · 2000 = 200
+	ccEff, alpha, err := EffectiveCensorshipCostWithSelfBuild(bribes, 2, 1, ConcentrationByBlocks, 0.1)
+	if err != nil {
+		t.Fatalf("EffectiveCensorshipCostWithSelfBuild failed: %v", err)
+	}
+	if alpha != 1 {
+		t.Errorf("expected alpha 1, got %f", alpha)
+	}
+	if !floatEqual(ccEff, big.NewFloat(200), 0.01) {
+		t.Errorf("expected effective cost 200, got %s", ccEff.String())
+	}
+
+	// AttackerProfit applies the same adjustment
+	result, err := AttackerProfit(bribes, ProfitParams{
+		BridgeTVL:            big.NewFloat(0),
+		SuccessProbability:   1,
+		Tau:                  2,
+		TopK:                 1,
+		SelfBuildProbability: 0.1,
+	})
+	if err != nil {
+		t.Fatalf("AttackerProfit failed: %v", err)
+	}
+	if !floatEqual(result.EffectiveCost, big.NewFloat(200), 0.01) || !floatEqual(result.DiscountedCost, big.NewFloat(200), 0.01) {
+		t.Errorf("expected cost 200, got %s (discounted %s)", result.EffectiveCost.String(), result.DiscountedCost.String())
+	}
+
+	if _, _, err := EffectiveCensorshipCostWithSelfBuild(bribes, 2, 1, ConcentrationByBlocks, 1.5); err == nil {
+		t.Error("Expected error for self-build probability above 1, got nil")
+	}
+}
+
+// TestEffectiveCensorshipCostScoped verifies α follows the requested scope
+// and the scope is reported back.
+func TestEffectiveCensorshipCostScoped(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+		{Slot: 4, ValueWei: big.NewInt(1000), BuilderPubkey: "0xC"},
+	}
+
+	cases := []struct {
+		scope     AlphaScope
+		alpha     float64
+		cost      float64
+		name      string
+		first     uint64
+		last      uint64
+		numBribes int
+	}{
+		{AlphaScope{}, 0.5, 1000, "dataset", 1, 4, 4},       // 2 of 4 blocks, C_c = 2000
+		{AlphaScope{Window: true}, 1, 0, "window", 1, 2, 2}, // 0xA built both window slots
+		{AlphaScope{StartSlot: 2, EndSlot: 4}, 1.0 / 3, 2000.0 * 2 / 3, "slots 2-4", 2, 4, 3},
+	}
+	for _, tc := range cases {
+		ccEff, used, err := EffectiveCensorshipCostScoped(bribes, 2, 1, ConcentrationByBlocks, tc.scope)
+		if err != nil {
+			t.Fatalf("%s: EffectiveCensorshipCostScoped failed: %v", tc.name, err)
+		}
+		if used.Scope.String() != tc.name || used.Bribes != tc.numBribes || used.FirstSlot != tc.first || used.LastSlot != tc.last {
+			t.Errorf("%s: expected %d bribes over slots %d-%d, got %+v (%s)", tc.name, tc.numBribes, tc.first, tc.last, used, used.Scope)
+		}
+		if math.Abs(used.Alpha-tc.alpha) > 1e-9 {
+			t.Errorf("%s: expected alpha %f, got %f", tc.name, tc.alpha, used.Alpha)
+		}
+		if !floatEqual(ccEff, big.NewFloat(tc.cost), 0.01) {
+			t.Errorf("%s: expected effective cost %f, got %s", tc.name, tc.cost, ccEff.String())
+		}
+	}
+
+	if _, _, err := EffectiveCensorshipCostScoped(bribes, 2, 1, ConcentrationByBlocks, AlphaScope{StartSlot: 10, EndSlot: 20}); err == nil {
+		t.Error("Expected error for an empty scope, got nil")
+	}
+}
+
+// TestNonCartelCensorshipCost verifies only the bids of slots won outside
+// the cartel are counted.
+func TestNonCartelCensorshipCost(t *testing.T) {
+	// 0xA wins 3 of 4 slots (α = 0.75) but only the cheap ones
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 4, ValueWei: big.NewInt(9700), BuilderPubkey: "0xB"},
+	}
+
+	// (1 - α) · C_c = 2500, but the cartel must still buy slot 4
+	rest, err := NonCartelCensorshipCost(bribes, 4, 1)
+	if err != nil {
+		t.Fatalf("NonCartelCensorshipCost failed: %v", err)
+	}
+	if rest.Cmp(big.NewInt(9700)) != 0 {
+		t.Errorf("expected cost 9700, got %s", rest.String())
+	}
+
+	// Within the first 3 slots the cartel wins everything
+	rest, err = NonCartelCensorshipCost(bribes, 3, 1)
+	if err != nil {
+		t.Fatalf("NonCartelCensorshipCost failed: %v", err)
+	}
+	if rest.Sign() != 0 {
+		t.Errorf("expected cost 0, got %s", rest.String())
+	}
+
+	if _, err := NonCartelCensorshipCost(bribes, 5, 1); err == nil {
+		t.Error("Expected error for insufficient slots, got nil")
+	}
+}
+
 // TestAttackerProfit_Breakeven verifies zero profit case.
 func TestAttackerProfit_Breakeven(t *testing.T) {
 	bribes := []SlotBribe{
@@ -611,7 +1196,7 @@ func TestSweepProbability_Basic(t *testing.T) {
 	}
 
 	tvl := big.NewFloat(10000)
-	sweep, err := SweepProbability(bribes, tvl, 2, 1, 0.1, 1.0, 10)
+	sweep, err := SweepProbability(context.Background(), bribes, tvl, 2, 1, 0.1, 1.0, 10)
 	if err != nil {
 		t.Fatalf("SweepProbability failed: %v", err)
 	}
@@ -653,7 +1238,7 @@ func TestSweepProbability_SingleStep(t *testing.T) {
 	}
 
 	tvl := big.NewFloat(5000)
-	sweep, err := SweepProbability(bribes, tvl, 1, 1, 0.5, 0.9, 1)
+	sweep, err := SweepProbability(context.Background(), bribes, tvl, 1, 1, 0.5, 0.9, 1)
 	if err != nil {
 		t.Fatalf("SweepProbability failed: %v", err)
 	}
@@ -668,6 +1253,243 @@ func TestSweepProbability_SingleStep(t *testing.T) {
 	}
 }
 
+// TestSweepTVL verifies the TVL sweep and its breakeven crossing.
+func TestSweepTVL(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+	}
+
+	// C_c^eff = 0.5·2000 = 1000, p = 0.5: V* = 2000
+	sweep, err := SweepTVL(context.Background(), bribes, 0.5, 2, 1, big.NewFloat(0), big.NewFloat(4000), 5)
+	if err != nil {
+		t.Fatalf("SweepTVL failed: %v", err)
+	}
+	if len(sweep.Results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(sweep.Results))
+	}
+	for i, result := range sweep.Results {
+		expectedTVL := big.NewFloat(float64(i) * 1000)
+		if !floatEqual(result.TVL, expectedTVL, 0.01) {
+			t.Errorf("step %d: expected TVL %s, got %s", i, expectedTVL.String(), result.TVL.String())
+		}
+	}
+	if !floatEqual(sweep.Breakeven, big.NewFloat(2000), 0.01) {
+		t.Errorf("expected breakeven 2000, got %s", sweep.Breakeven.String())
+	}
+	// Profit is zero at V = 2000 and positive from 3000
+	if sweep.FirstProfitable != 3 {
+		t.Errorf("expected first profitable step 3, got %d", sweep.FirstProfitable)
+	}
+
+	// p = 0 never breaks even
+	sweep, err = SweepTVL(context.Background(), bribes, 0, 2, 1, big.NewFloat(0), big.NewFloat(4000), 1)
+	if err != nil {
+		t.Fatalf("SweepTVL failed: %v", err)
+	}
+	if sweep.Breakeven != nil || sweep.FirstProfitable != -1 {
+		t.Errorf("expected no breakeven, got %v at step %d", sweep.Breakeven, sweep.FirstProfitable)
+	}
+
+	if _, err := SweepTVL(context.Background(), bribes, 0.5, 2, 1, big.NewFloat(4000), big.NewFloat(0), 5); err == nil {
+		t.Error("Expected error for minTVL > maxTVL, got nil")
+	}
+	if _, err := SweepTVL(context.Background(), bribes, 0.5, 2, 1, big.NewFloat(0), big.NewFloat(4000), 0); err == nil {
+		t.Error("Expected error for zero steps, got nil")
+	}
+}
+
+// TestSweepTau verifies cost and breakeven per duration match the
+// single-τ functions.
+func TestSweepTau(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(2000), BuilderPubkey: "0xB"},
+		{Slot: 3, ValueWei: big.NewInt(3000), BuilderPubkey: "0xA"},
+		{Slot: 4, ValueWei: big.NewInt(4000), BuilderPubkey: "0xA"},
+		{Slot: 5, ValueWei: big.NewInt(5000), BuilderPubkey: "0xB"},
+	}
+
+	sweep, err := SweepTau(context.Background(), bribes, 0.5, 1, 1, 5, 2)
+	if err != nil {
+		t.Fatalf("SweepTau failed: %v", err)
+	}
+	if len(sweep.Points) != 3 {
+		t.Fatalf("expected τ = 1, 3, 5, got %d points", len(sweep.Points))
+	}
+	for i, point := range sweep.Points {
+		tau := uint64(1 + 2*i)
+		if point.Tau != tau {
+			t.Errorf("point %d: expected τ=%d, got %d", i, tau, point.Tau)
+		}
+		cc, _ := CensorshipCost(bribes, tau)
+		if point.CensorshipCost.Cmp(cc) != 0 {
+			t.Errorf("τ=%d: expected C_c %s, got %s", tau, cc.String(), point.CensorshipCost.String())
+		}
+		breakeven, _, _ := FindBreakevenTVL(bribes, 0.5, tau, 1)
+		if !floatEqual(point.Breakeven, breakeven, 0.01) {
+			t.Errorf("τ=%d: expected V* %s, got %s", tau, breakeven.String(), point.Breakeven.String())
+		}
+	}
+
+	// A step past maxTau stops at minTau
+	sweep, err = SweepTau(context.Background(), bribes, 0.5, 1, 2, 4, 5)
+	if err != nil {
+		t.Fatalf("SweepTau failed: %v", err)
+	}
+	if len(sweep.Points) != 1 || sweep.Points[0].Tau != 2 {
+		t.Errorf("expected only τ=2, got %+v", sweep.Points)
+	}
+
+	if _, err := SweepTau(context.Background(), bribes, 0.5, 1, 1, 6, 1); err == nil {
+		t.Error("Expected error for maxTau beyond the data, got nil")
+	}
+	if _, err := SweepTau(context.Background(), bribes, 0.5, 1, 1, 5, 0); err == nil {
+		t.Error("Expected error for zero step, got nil")
+	}
+}
+
+// TestSweepTopK verifies α and breakeven per cartel size and the minimum
+// cartel for a bridge.
+func TestSweepTopK(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+		{Slot: 4, ValueWei: big.NewInt(1000), BuilderPubkey: "0xC"},
+	}
+
+	sweep, err := SweepTopK(context.Background(), bribes, 0.5, 4)
+	if err != nil {
+		t.Fatalf("SweepTopK failed: %v", err)
+	}
+	if len(sweep.Points) != 3 {
+		t.Fatalf("expected 3 cartel sizes, got %d", len(sweep.Points))
+	}
+
+	// C_c = 4000; α = 0.5, 0.75, 1; V* = 4000, 2000, 0
+	expected := []struct {
+		alpha     float64
+		breakeven float64
+	}{{0.5, 4000}, {0.75, 2000}, {1, 0}}
+	for i, want := range expected {
+		point := sweep.Points[i]
+		if point.TopK != i+1 || point.Alpha != want.alpha {
+			t.Errorf("k=%d: expected alpha %f, got k=%d alpha %f", i+1, want.alpha, point.TopK, point.Alpha)
+		}
+		if !floatEqual(point.Breakeven, big.NewFloat(want.breakeven), 0.01) {
+			t.Errorf("k=%d: expected V* %f, got %s", i+1, want.breakeven, point.Breakeven.String())
+		}
+	}
+
+	if k := sweep.MinCartelSize(big.NewFloat(3000)); k != 2 {
+		t.Errorf("expected a cartel of 2 for TVL 3000, got %d", k)
+	}
+	if k := sweep.MinCartelSize(big.NewFloat(0)); k != 0 {
+		t.Errorf("expected no cartel for TVL 0, got %d", k)
+	}
+
+	if _, err := SweepTopK(context.Background(), bribes, 0, 4); err == nil {
+		t.Error("Expected error for zero success probability, got nil")
+	}
+}
+
+// TestSweeps_Cancelled verifies every sweep stops with the context's
+// error once it is cancelled.
+func TestSweeps_Cancelled(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tvl := big.NewFloat(1000)
+	errs := map[string]error{}
+	_, errs["SweepProbability"] = SweepProbability(ctx, bribes, tvl, 2, 1, 0.1, 1.0, 5)
+	_, errs["SweepProbability (1 step)"] = SweepProbability(ctx, bribes, tvl, 2, 1, 0.5, 0.5, 1)
+	_, errs["SweepTVL"] = SweepTVL(ctx, bribes, 0.5, 2, 1, big.NewFloat(0), tvl, 5)
+	_, errs["SweepTau"] = SweepTau(ctx, bribes, 0.5, 1, 1, 2, 1)
+	_, errs["SweepTopK"] = SweepTopK(ctx, bribes, 0.5, 2)
+	_, errs["SweepProfitGrid"] = SweepProfitGrid(ctx, bribes, 2, 1, 0, 1, 3, big.NewFloat(0), tvl, 3)
+	for name, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+	}
+}
+
+// TestFindBreakevenTau verifies the longest affordable duration.
+func TestFindBreakevenTau(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+		{Slot: 3, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 4, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+	}
+
+	// α = 0.5, so C_c^eff(τ) = 500·τ; p·V = 1200 affords τ = 2
+	tau, alpha, err := FindBreakevenTau(bribes, big.NewFloat(2400), 0.5, 1)
+	if err != nil {
+		t.Fatalf("FindBreakevenTau failed: %v", err)
+	}
+	if tau != 2 || alpha != 0.5 {
+		t.Errorf("expected tau=2 and alpha=0.5, got %d and %f", tau, alpha)
+	}
+
+	// A budget equal to C_c^eff(τ) does not afford τ
+	tau, _, err = FindBreakevenTau(bribes, big.NewFloat(1000), 0.5, 1)
+	if err != nil {
+		t.Fatalf("FindBreakevenTau failed: %v", err)
+	}
+	if tau != 0 {
+		t.Errorf("expected tau=0, got %d", tau)
+	}
+
+	if _, _, err := FindBreakevenTau(bribes, big.NewFloat(1e6), 0.5, 1); err == nil {
+		t.Error("Expected error when the data cannot bound tau, got nil")
+	}
+}
+
+// TestFindBreakevenProbability verifies p* = C_c^eff / V.
+func TestFindBreakevenProbability(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+	}
+
+	// C_c^eff = 1000
+	pStar, alpha, err := FindBreakevenProbability(bribes, big.NewFloat(4000), 2, 1)
+	if err != nil {
+		t.Fatalf("FindBreakevenProbability failed: %v", err)
+	}
+	if pStar != 0.25 || alpha != 0.5 {
+		t.Errorf("expected p*=0.25 and alpha=0.5, got %f and %f", pStar, alpha)
+	}
+
+	// At p* the attack exactly breaks even
+	result, err := AttackerProfit(bribes, ProfitParams{BridgeTVL: big.NewFloat(4000), SuccessProbability: pStar, Tau: 2, TopK: 1})
+	if err != nil {
+		t.Fatalf("AttackerProfit failed: %v", err)
+	}
+	if result.Profit.Sign() != 0 {
+		t.Errorf("expected zero profit at p*, got %s", result.Profit.String())
+	}
+
+	// A small bridge cannot be attacked profitably at any p
+	pStar, _, err = FindBreakevenProbability(bribes, big.NewFloat(500), 2, 1)
+	if err != nil {
+		t.Fatalf("FindBreakevenProbability failed: %v", err)
+	}
+	if pStar != 2 {
+		t.Errorf("expected p*=2, got %f", pStar)
+	}
+
+	if _, _, err := FindBreakevenProbability(bribes, big.NewFloat(0), 2, 1); err == nil {
+		t.Error("Expected error for zero TVL, got nil")
+	}
+}
+
 // TestSweepProbability_InvalidParams verifies parameter validation.
 func TestSweepProbability_InvalidParams(t *testing.T) {
 	bribes := []SlotBribe{
@@ -676,25 +1498,25 @@ func TestSweepProbability_InvalidParams(t *testing.T) {
 	tvl := big.NewFloat(1000)
 
 	// Test steps < 1
-	_, err := SweepProbability(bribes, tvl, 1, 1, 0.1, 1.0, 0)
+	_, err := SweepProbability(context.Background(), bribes, tvl, 1, 1, 0.1, 1.0, 0)
 	if err == nil {
 		t.Error("Expected error for steps=0, got nil")
 	}
 
 	// Test minP > maxP
-	_, err = SweepProbability(bribes, tvl, 1, 1, 0.9, 0.1, 5)
+	_, err = SweepProbability(context.Background(), bribes, tvl, 1, 1, 0.9, 0.1, 5)
 	if err == nil {
 		t.Error("Expected error for minP > maxP, got nil")
 	}
 
 	// Test minP out of bounds
-	_, err = SweepProbability(bribes, tvl, 1, 1, -0.1, 1.0, 5)
+	_, err = SweepProbability(context.Background(), bribes, tvl, 1, 1, -0.1, 1.0, 5)
 	if err == nil {
 		t.Error("Expected error for minP < 0, got nil")
 	}
 
 	// Test maxP out of bounds
-	_, err = SweepProbability(bribes, tvl, 1, 1, 0.1, 1.5, 5)
+	_, err = SweepProbability(context.Background(), bribes, tvl, 1, 1, 0.1, 1.5, 5)
 	if err == nil {
 		t.Error("Expected error for maxP > 1, got nil")
 	}
@@ -896,6 +1718,7 @@ func TestLimitation_InclusionLists(t *testing.T) {
 	t.Log("  - Attack cost model breaks down")
 	t.Log("")
 	t.Log("This model is valid ONLY in pre-inclusion-list Ethereum.")
+	t.Log("InclusionListScenario prices partial deployment as an assumption.")
 	t.Log("")
 	t.Log("Current status (Feb 2026): Inclusion lists under development")
 }
@@ -957,7 +1780,7 @@ func TestLimitation_DetectionRisk(t *testing.T) {
 func TestLimitation_CoordinationCost(t *testing.T) {
 	// This test exists to document the limitation, not to test code
 
-	t.Log("LIMITATION: This model assumes zero coordination cost")
+	t.Log("LIMITATION: By default this model assumes zero coordination cost")
 	t.Log("")
 	t.Log("Real coordination requires:")
 	t.Log("  - Trust between cartel members")
@@ -966,6 +1789,7 @@ func TestLimitation_CoordinationCost(t *testing.T) {
 	t.Log("  - Time to organize")
 	t.Log("")
 	t.Log("True cost = C_c^eff + coordination overhead")
+	t.Log("Set ProfitParams.Coordination to include an assumed overhead")
 }
 
 // TestModelValidityBounds documents when this model is valid.
@@ -2,13 +2,23 @@ package model
 
 import (
 	"fmt"
+	"math/big"
 	"sort"
 )
 
 // BuilderStats contains builder-level statistics for concentration analysis.
+//
+// The value fields are filled by storage aggregates, and by the model's
+// concentration functions whenever every bribe has a value.
 type BuilderStats struct {
 	BuilderPubkey string
 	BlockCount    uint64
+	Entity        string   // Operating entity from the builder registry ("" if unlabeled)
+	TotalValueWei *big.Int // Sum of the builder's winning bids (nil if not computed)
+	MeanBidWei    *big.Int // TotalValueWei / BlockCount
+	ValueShare    float64  // Fraction of the total value across all builders
+	FirstSeenSlot uint64   // First slot the builder won in the data (0 if not computed)
+	LastSeenSlot  uint64   // Last slot the builder won in the data
 }
 
 // ComputeBuilderConcentration analyzes builder centralization from relay data.
@@ -29,35 +39,274 @@ type BuilderStats struct {
 // - builderStats: sorted list of builders by block count (descending)
 // - error: if data is invalid
 func ComputeBuilderConcentration(bribes []SlotBribe, topK int) (alpha float64, builderStats []BuilderStats, err error) {
-	if len(bribes) == 0 {
-		return 0, nil, fmt.Errorf("empty bribes slice")
+	return computeConcentration(bribes, topK, func(bribe SlotBribe) (string, string) {
+		return bribe.BuilderPubkey, ""
+	})
+}
+
+// ConcentrationMetric selects how a builder's share of the market is measured.
+type ConcentrationMetric string
+
+// Concentration metrics.
+const (
+	// ConcentrationByBlocks weighs every block equally: α is the share of
+	// blocks built by the top k builders.
+	ConcentrationByBlocks ConcentrationMetric = "blocks"
+
+	// ConcentrationByValue weighs blocks by their winning bid: α is the
+	// share of wei won by the top k builders, so a builder winning a few
+	// high-value slots outranks one winning many dust slots.
+	ConcentrationByValue ConcentrationMetric = "value"
+)
+
+// ParseConcentrationMetric validates a metric name; "" selects
+// ConcentrationByBlocks.
+func ParseConcentrationMetric(s string) (ConcentrationMetric, error) {
+	switch metric := ConcentrationMetric(s); metric {
+	case "":
+		return ConcentrationByBlocks, nil
+	case ConcentrationByBlocks, ConcentrationByValue:
+		return metric, nil
+	default:
+		return "", fmt.Errorf("unknown concentration metric '%s' (want %s or %s)", s, ConcentrationByBlocks, ConcentrationByValue)
 	}
+}
 
-	if topK < 1 {
-		return 0, nil, fmt.Errorf("topK must be at least 1, got %d", topK)
+// ComputeValueWeightedConcentration computes the value-weighted α:
+//
+//	α_v = (wei won by top k builders) / (total wei)
+//
+// Builders are ranked by total value won, and the returned stats carry
+// TotalValueWei, MeanBidWei and ValueShare. Every bribe must have a value.
+// A range in which every bid is zero has α_v = 0.
+func ComputeValueWeightedConcentration(bribes []SlotBribe, topK int) (alpha float64, builderStats []BuilderStats, err error) {
+	return computeValueWeightedConcentration(bribes, topK, func(bribe SlotBribe) (string, string) {
+		return bribe.BuilderPubkey, ""
+	})
+}
+
+// computeValueWeightedConcentration implements α_v over an arbitrary
+// grouping.
+func computeValueWeightedConcentration(bribes []SlotBribe, topK int, groupKey func(SlotBribe) (string, string)) (alpha float64, builderStats []BuilderStats, err error) {
+	counter := newConcentrationCounter(groupKey)
+	counter.weighValues()
+	for _, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			return 0, nil, fmt.Errorf("%w for slot %d", ErrNilValue, bribe.Slot)
+		}
+		counter.add(bribe)
+	}
+	return counter.resultBy(topK, ConcentrationByValue)
+}
+
+// ComputeConcentration computes α under metric (ConcentrationByBlocks or
+// ConcentrationByValue).
+func ComputeConcentration(bribes []SlotBribe, topK int, metric ConcentrationMetric) (alpha float64, builderStats []BuilderStats, err error) {
+	switch metric {
+	case ConcentrationByBlocks:
+		return ComputeBuilderConcentration(bribes, topK)
+	case ConcentrationByValue:
+		return ComputeValueWeightedConcentration(bribes, topK)
+	default:
+		return 0, nil, fmt.Errorf("unknown concentration metric '%s'", metric)
 	}
+}
 
-	// Count blocks per builder
-	builderCounts := make(map[string]uint64)
-	totalBlocks := uint64(len(bribes))
+// ComputeConcentrationExact is ComputeConcentration with α as the exact
+// fraction topKBlocks/totalBlocks (or top-k value over total value), so
+// results derived from it can be reproduced bit for bit.
+func ComputeConcentrationExact(bribes []SlotBribe, topK int, metric ConcentrationMetric) (alpha *big.Rat, builderStats []BuilderStats, err error) {
+	_, stats, err := ComputeConcentration(bribes, topK, metric)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	// stats are ranked by metric, so the top k lead
+	top, total := new(big.Int), new(big.Int)
+	for i, s := range stats {
+		weight := new(big.Int).SetUint64(s.BlockCount)
+		if metric == ConcentrationByValue {
+			weight = s.TotalValueWei
+		}
+		total.Add(total, weight)
+		if i < topK {
+			top.Add(top, weight)
+		}
+	}
+	if total.Sign() == 0 {
+		return new(big.Rat), stats, nil
+	}
+	return new(big.Rat).SetFrac(top, total), stats, nil
+}
+
+// ComputeEntityConcentration computes α over builder entities instead of pubkeys.
+//
+// Bribes are grouped by BuilderEntity (see BuilderRegistry.LabelBribes), so an
+// operator running several pubkeys is counted once. Unlabeled bribes fall back
+// to their pubkey.
+//
+// In the returned stats, BuilderPubkey holds the grouping key (entity name, or
+// pubkey when unlabeled) and Entity holds the entity name ("" when unlabeled).
+func ComputeEntityConcentration(bribes []SlotBribe, topK int) (alpha float64, builderStats []BuilderStats, err error) {
+	return computeConcentration(bribes, topK, entityGroupKey(nil))
+}
+
+// ComputeConcentrationByEntity computes α under metric after mapping each
+// builder pubkey to its operating entity through registry, so α reflects
+// operator concentration (several beaverbuild keys count as one builder)
+// without labeling the bribes first. Pubkeys the registry does not know
+// fall back to the bribe's BuilderEntity, then to the pubkey itself; a nil
+// registry relies on BuilderEntity alone, as ComputeEntityConcentration.
+//
+// Stats are keyed as in ComputeEntityConcentration.
+func ComputeConcentrationByEntity(bribes []SlotBribe, topK int, metric ConcentrationMetric, registry *BuilderRegistry) (alpha float64, builderStats []BuilderStats, err error) {
+	groupKey := entityGroupKey(registry)
+	switch metric {
+	case ConcentrationByBlocks:
+		return computeConcentration(bribes, topK, groupKey)
+	case ConcentrationByValue:
+		return computeValueWeightedConcentration(bribes, topK, groupKey)
+	default:
+		return 0, nil, fmt.Errorf("unknown concentration metric '%s'", metric)
+	}
+}
+
+// entityGroupKey groups bribes by the entity registry assigns their
+// builder, then by BuilderEntity, then by pubkey.
+func entityGroupKey(registry *BuilderRegistry) func(SlotBribe) (string, string) {
+	return func(bribe SlotBribe) (string, string) {
+		if entity, ok := registry.Label(bribe.BuilderPubkey); ok {
+			return entity, entity
+		}
+		if bribe.BuilderEntity != "" {
+			return bribe.BuilderEntity, bribe.BuilderEntity
+		}
+		return bribe.BuilderPubkey, ""
+	}
+}
+
+// computeConcentration implements top-k concentration over an arbitrary grouping.
+// groupKey returns the grouping key and entity label for a bribe.
+func computeConcentration(bribes []SlotBribe, topK int, groupKey func(SlotBribe) (string, string)) (alpha float64, builderStats []BuilderStats, err error) {
+	counter := newConcentrationCounter(groupKey)
+	if allValued(bribes) {
+		counter.weighValues()
+	}
 	for _, bribe := range bribes {
-		// Handle empty builder pubkeys
-		key := bribe.BuilderPubkey
-		if key == "" {
-			key = "unknown"
+		counter.add(bribe)
+	}
+	return counter.result(topK)
+}
+
+// allValued reports whether every bribe has a value, so value totals can
+// be reported alongside block counts.
+func allValued(bribes []SlotBribe) bool {
+	for _, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// concentrationCounter accumulates per-group block counts one bribe at a
+// time, so concentration can be computed over streamed data in memory
+// proportional to the number of builders rather than slots.
+type concentrationCounter struct {
+	groupKey    func(SlotBribe) (string, string)
+	counts      map[string]uint64
+	entities    map[string]string
+	firstSlot   map[string]uint64
+	lastSlot    map[string]uint64
+	totalBlocks uint64
+
+	// Per-group value sums, tracked only after weighValues
+	values     map[string]*big.Int
+	totalValue *big.Int
+}
+
+func newConcentrationCounter(groupKey func(SlotBribe) (string, string)) *concentrationCounter {
+	return &concentrationCounter{
+		groupKey:  groupKey,
+		counts:    make(map[string]uint64),
+		entities:  make(map[string]string),
+		firstSlot: make(map[string]uint64),
+		lastSlot:  make(map[string]uint64),
+	}
+}
+
+// weighValues makes the counter also sum bribe values, which
+// value-weighted results need. Bribes added afterwards must have values.
+func (c *concentrationCounter) weighValues() {
+	c.values = make(map[string]*big.Int)
+	c.totalValue = new(big.Int)
+}
+
+func (c *concentrationCounter) add(bribe SlotBribe) {
+	key, entity := c.groupKey(bribe)
+	// Handle empty builder pubkeys
+	if key == "" {
+		key = "unknown"
+	}
+	if c.counts[key] == 0 || bribe.Slot < c.firstSlot[key] {
+		c.firstSlot[key] = bribe.Slot
+	}
+	c.lastSlot[key] = max(c.lastSlot[key], bribe.Slot)
+	c.counts[key]++
+	if entity != "" {
+		c.entities[key] = entity
+	}
+	c.totalBlocks++
+
+	if c.values != nil {
+		value, ok := c.values[key]
+		if !ok {
+			value = new(big.Int)
+			c.values[key] = value
 		}
-		builderCounts[key]++
+		value.Add(value, bribe.ValueWei)
+		c.totalValue.Add(c.totalValue, bribe.ValueWei)
+	}
+}
+
+func (c *concentrationCounter) result(topK int) (alpha float64, builderStats []BuilderStats, err error) {
+	return c.resultBy(topK, ConcentrationByBlocks)
+}
+
+// resultBy ranks groups and computes α under metric. ConcentrationByValue
+// requires weighValues; without it the stats carry no value fields.
+func (c *concentrationCounter) resultBy(topK int, metric ConcentrationMetric) (alpha float64, builderStats []BuilderStats, err error) {
+	if c.totalBlocks == 0 {
+		return 0, nil, fmt.Errorf("%w: empty bribes slice", ErrInsufficientData)
+	}
+
+	if topK < 1 {
+		return 0, nil, fmt.Errorf("%w: must be at least 1, got %d", ErrInvalidTopK, topK)
 	}
 
 	// Convert to sorted slice
-	stats := make([]BuilderStats, 0, len(builderCounts))
-	for builder, count := range builderCounts {
+	stats := make([]BuilderStats, 0, len(c.counts))
+	for builder, count := range c.counts {
 		stats = append(stats, BuilderStats{
 			BuilderPubkey: builder,
 			BlockCount:    count,
+			Entity:        c.entities[builder],
+			FirstSeenSlot: c.firstSlot[builder],
+			LastSeenSlot:  c.lastSlot[builder],
 		})
 	}
+	if c.values != nil {
+		c.fillValues(stats)
+	}
+
+	actualK := topK
+	if actualK > len(stats) {
+		actualK = len(stats)
+	}
+
+	if metric == ConcentrationByValue {
+		return c.valueWeighted(stats, actualK), stats, nil
+	}
 
 	// Sort by block count descending
 	sort.Slice(stats, func(i, j int) bool {
@@ -66,21 +315,55 @@ func ComputeBuilderConcentration(bribes []SlotBribe, topK int) (alpha float64, b
 
 	// Compute top-k concentration
 	var topKBlocks uint64
-	actualK := topK
-	if actualK > len(stats) {
-		actualK = len(stats)
-	}
-
 	for i := 0; i < actualK; i++ {
 		topKBlocks += stats[i].BlockCount
 	}
 
 	// α = top-k blocks / total blocks
-	alpha = float64(topKBlocks) / float64(totalBlocks)
+	alpha = float64(topKBlocks) / float64(c.totalBlocks)
 
 	return alpha, stats, nil
 }
 
+// fillValues sets the value fields of stats from the summed values.
+func (c *concentrationCounter) fillValues(stats []BuilderStats) {
+	total := new(big.Float).SetInt(c.totalValue)
+	for i := range stats {
+		value := c.values[stats[i].BuilderPubkey]
+		stats[i].TotalValueWei = value
+		stats[i].MeanBidWei = new(big.Int).Quo(value, new(big.Int).SetUint64(stats[i].BlockCount))
+		if c.totalValue.Sign() > 0 {
+			stats[i].ValueShare, _ = new(big.Float).Quo(new(big.Float).SetInt(value), total).Float64()
+		}
+	}
+}
+
+// valueWeighted sorts stats, whose value fields are filled, by total value
+// descending (ties by block count, then key) and returns the top k's share
+// of the total value.
+func (c *concentrationCounter) valueWeighted(stats []BuilderStats, k int) float64 {
+	sort.Slice(stats, func(i, j int) bool {
+		if cmp := stats[i].TotalValueWei.Cmp(stats[j].TotalValueWei); cmp != 0 {
+			return cmp > 0
+		}
+		if stats[i].BlockCount != stats[j].BlockCount {
+			return stats[i].BlockCount > stats[j].BlockCount
+		}
+		return stats[i].BuilderPubkey < stats[j].BuilderPubkey
+	})
+
+	if c.totalValue.Sign() == 0 {
+		return 0
+	}
+	// α_v = top-k value / total value, divided exactly before rounding
+	topValue := new(big.Int)
+	for i := 0; i < k; i++ {
+		topValue.Add(topValue, stats[i].TotalValueWei)
+	}
+	alpha, _ := new(big.Rat).SetFrac(topValue, c.totalValue).Float64()
+	return alpha
+}
+
 // GetTopBuilders returns the top k builders by block count.
 //
 // This is a convenience wrapper around ComputeBuilderConcentration
@@ -112,3 +395,282 @@ func GetBuilderDiversity(bribes []SlotBribe) int {
 	}
 	return len(builders)
 }
+
+// GetEntityDiversity returns the number of unique builder entities, with
+// pubkeys grouped as in ComputeConcentrationByEntity.
+func GetEntityDiversity(bribes []SlotBribe, registry *BuilderRegistry) int {
+	groupKey := entityGroupKey(registry)
+	entities := make(map[string]struct{})
+	for _, bribe := range bribes {
+		key, _ := groupKey(bribe)
+		if key == "" {
+			key = "unknown"
+		}
+		entities[key] = struct{}{}
+	}
+	return len(entities)
+}
+
+// HerfindahlIndex computes the Herfindahl-Hirschman index of builder
+// market share under metric:
+//
+//	HHI = Σ s_i²
+//
+// where s_i is builder i's share of blocks (ConcentrationByBlocks) or of
+// wei won (ConcentrationByValue). It ranges from 1/n for n equal builders
+// to 1 for a monopoly. The value-weighted index requires every bribe to
+// have a value and is 0 when every bid is zero.
+func HerfindahlIndex(bribes []SlotBribe, metric ConcentrationMetric) (float64, error) {
+	if metric != ConcentrationByBlocks && metric != ConcentrationByValue {
+		return 0, fmt.Errorf("unknown concentration metric '%s'", metric)
+	}
+	counter := newConcentrationCounter(func(bribe SlotBribe) (string, string) {
+		return bribe.BuilderPubkey, ""
+	})
+	if metric == ConcentrationByValue {
+		counter.weighValues()
+	}
+	for _, bribe := range bribes {
+		if metric == ConcentrationByValue && bribe.ValueWei == nil {
+			return 0, fmt.Errorf("%w for slot %d", ErrNilValue, bribe.Slot)
+		}
+		counter.add(bribe)
+	}
+	if counter.totalBlocks == 0 {
+		return 0, fmt.Errorf("%w: empty bribes slice", ErrInsufficientData)
+	}
+
+	var hhi float64
+	if metric == ConcentrationByBlocks {
+		for _, count := range counter.counts {
+			share := float64(count) / float64(counter.totalBlocks)
+			hhi += share * share
+		}
+		return hhi, nil
+	}
+
+	if counter.totalValue.Sign() == 0 {
+		return 0, nil
+	}
+	total := new(big.Float).SetInt(counter.totalValue)
+	for _, value := range counter.values {
+		share, _ := new(big.Float).Quo(new(big.Float).SetInt(value), total).Float64()
+		hhi += share * share
+	}
+	return hhi, nil
+}
+
+// DefaultNakamotoThreshold is the share a coalition must exceed to control
+// block production: a simple majority.
+const DefaultNakamotoThreshold = 0.5
+
+// NakamotoCoefficients are the smallest numbers of builders whose combined
+// share exceeds a threshold, under each concentration metric.
+type NakamotoCoefficients struct {
+	Threshold float64
+	ByBlocks  int // Builders ranked by blocks built
+	ByValue   int // Builders ranked by wei won; 0 when every bid is zero
+}
+
+// NakamotoCoefficient computes the Nakamoto coefficient of the builder
+// market: the minimum number of builders whose combined share strictly
+// exceeds threshold (0 selects DefaultNakamotoThreshold), counted both by
+// blocks and by value. Lower is more centralized.
+//
+// threshold must lie in (0, 1), and every bribe must have a value.
+func NakamotoCoefficient(bribes []SlotBribe, threshold float64) (NakamotoCoefficients, error) {
+	if threshold == 0 {
+		threshold = DefaultNakamotoThreshold
+	}
+	if !(threshold > 0 && threshold < 1) {
+		return NakamotoCoefficients{}, fmt.Errorf("threshold must be in (0, 1), got %v", threshold)
+	}
+
+	counter := newConcentrationCounter(func(bribe SlotBribe) (string, string) {
+		return bribe.BuilderPubkey, ""
+	})
+	counter.weighValues()
+	for _, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			return NakamotoCoefficients{}, fmt.Errorf("%w for slot %d", ErrNilValue, bribe.Slot)
+		}
+		counter.add(bribe)
+	}
+
+	_, byBlocks, err := counter.resultBy(1, ConcentrationByBlocks)
+	if err != nil {
+		return NakamotoCoefficients{}, err
+	}
+	result := NakamotoCoefficients{Threshold: threshold}
+	var blocks uint64
+	for i, b := range byBlocks {
+		blocks += b.BlockCount
+		if float64(blocks) > threshold*float64(counter.totalBlocks) {
+			result.ByBlocks = i + 1
+			break
+		}
+	}
+
+	_, byValue, err := counter.resultBy(1, ConcentrationByValue)
+	if err != nil {
+		return NakamotoCoefficients{}, err
+	}
+	if counter.totalValue.Sign() == 0 {
+		return result, nil
+	}
+	// Compare exactly: top value > threshold · total value
+	limit := new(big.Rat).Mul(new(big.Rat).SetInt(counter.totalValue), new(big.Rat).SetFloat64(threshold))
+	value := new(big.Int)
+	for i, b := range byValue {
+		value.Add(value, b.TotalValueWei)
+		if new(big.Rat).SetInt(value).Cmp(limit) > 0 {
+			result.ByValue = i + 1
+			break
+		}
+	}
+	return result, nil
+}
+
+// GiniCoefficients measure inequality of builder market share, under each
+// concentration metric.
+type GiniCoefficients struct {
+	ByBlocks float64 // Over blocks built per builder
+	ByValue  float64 // Over wei won per builder; 0 when every bid is zero
+}
+
+// GiniCoefficient computes the Gini coefficient of builder market share:
+// 0 when every builder has the same share, approaching 1 as one builder
+// takes everything. Unlike α it needs no choice of k.
+//
+// Only builders that won at least one slot are counted, so a single
+// builder is perfectly "equal" by blocks; its dominance shows in α, HHI
+// and the Nakamoto coefficient instead. Every bribe must have a value.
+func GiniCoefficient(bribes []SlotBribe) (GiniCoefficients, error) {
+	counter := newConcentrationCounter(func(bribe SlotBribe) (string, string) {
+		return bribe.BuilderPubkey, ""
+	})
+	counter.weighValues()
+	for _, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			return GiniCoefficients{}, fmt.Errorf("%w for slot %d", ErrNilValue, bribe.Slot)
+		}
+		counter.add(bribe)
+	}
+	if counter.totalBlocks == 0 {
+		return GiniCoefficients{}, fmt.Errorf("%w: empty bribes slice", ErrInsufficientData)
+	}
+
+	blocks := make([]float64, 0, len(counter.counts))
+	values := make([]float64, 0, len(counter.values))
+	for key, count := range counter.counts {
+		blocks = append(blocks, float64(count))
+		value, _ := new(big.Float).SetInt(counter.values[key]).Float64()
+		values = append(values, value)
+	}
+	return GiniCoefficients{ByBlocks: gini(blocks), ByValue: gini(values)}, nil
+}
+
+// gini computes the Gini coefficient of non-negative xs, reordering them:
+//
+//	G = 2·Σ i·x_(i) / (n·Σ x) - (n+1)/n
+//
+// over xs sorted ascending with i from 1. An all-zero input yields 0.
+func gini(xs []float64) float64 {
+	sort.Float64s(xs)
+	var sum, weighted float64
+	for i, x := range xs {
+		sum += x
+		weighted += float64(i+1) * x
+	}
+	if sum == 0 {
+		return 0
+	}
+	n := float64(len(xs))
+	return 2*weighted/(n*sum) - (n+1)/n
+}
+
+// LorenzPoint is a point of a Lorenz curve: the smallest builders, making
+// up BuilderShare of all builders, hold Share of the market.
+type LorenzPoint struct {
+	BuilderShare float64 `json:"builder_share"`
+	Share        float64 `json:"share"`
+}
+
+// LorenzCurves are the Lorenz curves of builder market share under each
+// concentration metric, from (0, 0) to (1, 1).
+type LorenzCurves struct {
+	StartSlot uint64        `json:"start_slot"`
+	EndSlot   uint64        `json:"end_slot"` // 0 when unbounded
+	Builders  int           `json:"builders"`
+	ByBlocks  []LorenzPoint `json:"by_blocks"`
+	ByValue   []LorenzPoint `json:"by_value"` // Flat at 0 when every bid is zero
+}
+
+// ComputeLorenzCurves computes the Lorenz curves of builder market share
+// over the slots in [startSlot, endSlot] (endSlot 0 means no upper bound),
+// one point per builder after the origin, for plotting alongside
+// GiniCoefficient: the Gini coefficient is twice the area between a curve
+// and the diagonal. The same builders are counted, and every bribe in the
+// window must have a value.
+func ComputeLorenzCurves(bribes []SlotBribe, startSlot, endSlot uint64) (LorenzCurves, error) {
+	if endSlot != 0 && endSlot < startSlot {
+		return LorenzCurves{}, fmt.Errorf("invalid slot window %d-%d", startSlot, endSlot)
+	}
+	counter := newConcentrationCounter(func(bribe SlotBribe) (string, string) {
+		return bribe.BuilderPubkey, ""
+	})
+	counter.weighValues()
+	for _, bribe := range bribes {
+		if bribe.Slot < startSlot || (endSlot != 0 && bribe.Slot > endSlot) {
+			continue
+		}
+		if bribe.ValueWei == nil {
+			return LorenzCurves{}, fmt.Errorf("%w for slot %d", ErrNilValue, bribe.Slot)
+		}
+		counter.add(bribe)
+	}
+	if counter.totalBlocks == 0 {
+		return LorenzCurves{}, fmt.Errorf("%w: no bribes in slots %d-%d", ErrInsufficientData, startSlot, endSlot)
+	}
+
+	blocks := make([]float64, 0, len(counter.counts))
+	values := make([]float64, 0, len(counter.values))
+	for key, count := range counter.counts {
+		blocks = append(blocks, float64(count))
+		value, _ := new(big.Float).SetInt(counter.values[key]).Float64()
+		values = append(values, value)
+	}
+	return LorenzCurves{
+		StartSlot: startSlot,
+		EndSlot:   endSlot,
+		Builders:  len(blocks),
+		ByBlocks:  lorenz(blocks),
+		ByValue:   lorenz(values),
+	}, nil
+}
+
+// lorenz returns the Lorenz curve of non-negative xs, reordering them.
+func lorenz(xs []float64) []LorenzPoint {
+	sort.Float64s(xs)
+	var total float64
+	for _, x := range xs {
+		total += x
+	}
+	n := float64(len(xs))
+	points := make([]LorenzPoint, 0, len(xs)+1)
+	points = append(points, LorenzPoint{})
+	var cumulative float64
+	for i, x := range xs {
+		cumulative += x
+		p := LorenzPoint{BuilderShare: float64(i+1) / n}
+		if total > 0 {
+			p.Share = cumulative / total
+		}
+		points = append(points, p)
+	}
+	// Exact endpoint despite rounding in the running sum
+	if total > 0 {
+		points[len(points)-1].Share = 1
+	}
+	return points
+}
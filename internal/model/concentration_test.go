@@ -1,7 +1,10 @@
 package model
 
 import (
+	"errors"
+	"math"
 	"math/big"
+	"reflect"
 	"testing"
 )
 
@@ -184,6 +187,38 @@ func TestComputeBuilderConcentration_EmptyPubkey(t *testing.T) {
 	}
 }
 
+// TestComputeBuilderConcentration_ValueAndSpan verifies block-count stats
+// also carry value totals and first/last seen slots.
+func TestComputeBuilderConcentration_ValueAndSpan(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 7, ValueWei: big.NewInt(300), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 5, ValueWei: big.NewInt(600), BuilderPubkey: "0xB"},
+	}
+
+	_, stats, err := ComputeBuilderConcentration(bribes, 1)
+	if err != nil {
+		t.Fatalf("ComputeBuilderConcentration failed: %v", err)
+	}
+	a := stats[0]
+	if a.BuilderPubkey != "0xA" || a.FirstSeenSlot != 3 || a.LastSeenSlot != 7 {
+		t.Errorf("expected 0xA seen in slots 3-7, got %+v", a)
+	}
+	if a.TotalValueWei.Int64() != 400 || a.MeanBidWei.Int64() != 200 || a.ValueShare != 0.4 {
+		t.Errorf("expected 0xA with 400 wei, mean 200 and share 0.4, got %+v", a)
+	}
+
+	// Without every value, only the spans are filled
+	bribes[2].ValueWei = nil
+	_, stats, err = ComputeBuilderConcentration(bribes, 1)
+	if err != nil {
+		t.Fatalf("ComputeBuilderConcentration failed: %v", err)
+	}
+	if stats[0].TotalValueWei != nil || stats[1].FirstSeenSlot != 5 {
+		t.Errorf("expected spans without values, got %+v", stats)
+	}
+}
+
 // TestComputeBuilderConcentration_Distribution verifies statistical correctness.
 func TestComputeBuilderConcentration_Distribution(t *testing.T) {
 	// Create a more realistic distribution
@@ -300,3 +335,233 @@ func TestGetBuilderDiversity_EmptyPubkeys(t *testing.T) {
 		t.Errorf("expected diversity=2, got %d", diversity)
 	}
 }
+
+// TestComputeValueWeightedConcentration verifies builders are ranked by
+// value won and α is their share of the total value.
+func TestComputeValueWeightedConcentration(t *testing.T) {
+	// 0xA wins most blocks but only dust; 0xB wins one valuable slot
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(10), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(10), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(10), BuilderPubkey: "0xA"},
+		{Slot: 4, ValueWei: big.NewInt(900), BuilderPubkey: "0xB"},
+		{Slot: 5, ValueWei: big.NewInt(70), BuilderPubkey: "0xC"},
+	}
+
+	blockAlpha, _, err := ComputeBuilderConcentration(bribes, 1)
+	if err != nil {
+		t.Fatalf("ComputeBuilderConcentration failed: %v", err)
+	}
+	alpha, stats, err := ComputeValueWeightedConcentration(bribes, 1)
+	if err != nil {
+		t.Fatalf("ComputeValueWeightedConcentration failed: %v", err)
+	}
+
+	if blockAlpha != 0.6 {
+		t.Errorf("expected block-count alpha=0.6, got %f", blockAlpha)
+	}
+	if alpha != 0.9 {
+		t.Errorf("expected value-weighted alpha=0.9, got %f", alpha)
+	}
+	if stats[0].BuilderPubkey != "0xB" || stats[1].BuilderPubkey != "0xC" || stats[2].BuilderPubkey != "0xA" {
+		t.Errorf("expected builders ranked 0xB, 0xC, 0xA, got %+v", stats)
+	}
+	if stats[2].TotalValueWei.Int64() != 30 || stats[2].MeanBidWei.Int64() != 10 || stats[2].ValueShare != 0.03 {
+		t.Errorf("expected 0xA to total 30 wei (mean 10, share 0.03), got %+v", stats[2])
+	}
+
+	// All-zero bids have no value to concentrate
+	zero := []SlotBribe{{Slot: 1, ValueWei: big.NewInt(0), BuilderPubkey: "0xA"}}
+	if alpha, _, err := ComputeValueWeightedConcentration(zero, 1); err != nil || alpha != 0 {
+		t.Errorf("expected alpha=0 for zero-value bids, got %f (%v)", alpha, err)
+	}
+
+	if _, _, err := ComputeValueWeightedConcentration([]SlotBribe{{Slot: 1, BuilderPubkey: "0xA"}}, 1); err == nil {
+		t.Error("Expected error for nil ValueWei, got nil")
+	}
+}
+
+// TestNakamotoCoefficient verifies the count- and value-weighted
+// coefficients and threshold handling.
+func TestNakamotoCoefficient(t *testing.T) {
+	// Blocks: A=4, B=3, C=2, D=1 of 10. Value: D=700, C=200, A=40, B=30 of 970
+	var bribes []SlotBribe
+	slot := uint64(0)
+	add := func(pubkey string, n int, value int64) {
+		for i := 0; i < n; i++ {
+			slot++
+			bribes = append(bribes, SlotBribe{Slot: slot, ValueWei: big.NewInt(value), BuilderPubkey: pubkey})
+		}
+	}
+	add("0xA", 4, 10)
+	add("0xB", 3, 10)
+	add("0xC", 2, 100)
+	add("0xD", 1, 700)
+
+	tests := []struct {
+		threshold         float64
+		byBlocks, byValue int
+	}{
+		{0, 2, 1},    // Majority: A+B = 7/10 > 0.5; D = 700/970 > 0.5
+		{0.7, 3, 1},  // A+B = 0.7 does not exceed 0.7
+		{0.8, 3, 2},  // D = 700 < 776 < D+C
+		{0.95, 4, 3}, // 921.5 needs D+C+A
+	}
+	for _, tt := range tests {
+		got, err := NakamotoCoefficient(bribes, tt.threshold)
+		if err != nil {
+			t.Fatalf("NakamotoCoefficient(%v) failed: %v", tt.threshold, err)
+		}
+		if got.ByBlocks != tt.byBlocks || got.ByValue != tt.byValue {
+			t.Errorf("threshold %v: expected %d by blocks and %d by value, got %+v", tt.threshold, tt.byBlocks, tt.byValue, got)
+		}
+	}
+
+	for _, threshold := range []float64{-0.1, 1, 1.5} {
+		if _, err := NakamotoCoefficient(bribes, threshold); err == nil {
+			t.Errorf("Expected error for threshold %v, got nil", threshold)
+		}
+	}
+	if _, err := NakamotoCoefficient(nil, 0.5); err == nil {
+		t.Error("Expected error for empty bribes, got nil")
+	}
+}
+
+// TestGiniCoefficient verifies perfectly equal and monopoly distributions.
+func TestGiniCoefficient(t *testing.T) {
+	// Four builders with one block each and equal bids: perfect equality
+	equal := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(100), BuilderPubkey: "0xB"},
+		{Slot: 3, ValueWei: big.NewInt(100), BuilderPubkey: "0xC"},
+		{Slot: 4, ValueWei: big.NewInt(100), BuilderPubkey: "0xD"},
+	}
+	got, err := GiniCoefficient(equal)
+	if err != nil {
+		t.Fatalf("GiniCoefficient failed: %v", err)
+	}
+	if math.Abs(got.ByBlocks) > 1e-12 || math.Abs(got.ByValue) > 1e-12 {
+		t.Errorf("expected Gini 0 for an equal market, got %+v", got)
+	}
+
+	// Same blocks, but 0xA wins all the value: G = (n-1)/n = 0.75
+	monopoly := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(0), BuilderPubkey: "0xB"},
+		{Slot: 3, ValueWei: big.NewInt(0), BuilderPubkey: "0xC"},
+		{Slot: 4, ValueWei: big.NewInt(0), BuilderPubkey: "0xD"},
+	}
+	got, err = GiniCoefficient(monopoly)
+	if err != nil {
+		t.Fatalf("GiniCoefficient failed: %v", err)
+	}
+	if math.Abs(got.ByBlocks) > 1e-12 || math.Abs(got.ByValue-0.75) > 1e-12 {
+		t.Errorf("expected Gini 0 by blocks and 0.75 by value, got %+v", got)
+	}
+
+	// Block monopoly among observed builders: 9 of 10 blocks to one of two
+	var skewed []SlotBribe
+	for slot := uint64(1); slot <= 10; slot++ {
+		pubkey := "0xA"
+		if slot == 10 {
+			pubkey = "0xB"
+		}
+		skewed = append(skewed, SlotBribe{Slot: slot, ValueWei: big.NewInt(1), BuilderPubkey: pubkey})
+	}
+	got, err = GiniCoefficient(skewed)
+	if err != nil {
+		t.Fatalf("GiniCoefficient failed: %v", err)
+	}
+	// Shares 0.1 and 0.9: G = |0.9-0.1| / (2·2·0.5) = 0.4
+	if math.Abs(got.ByBlocks-0.4) > 1e-12 {
+		t.Errorf("expected Gini 0.4 by blocks, got %f", got.ByBlocks)
+	}
+
+	if _, err := GiniCoefficient(nil); err == nil {
+		t.Error("Expected error for empty bribes, got nil")
+	}
+}
+
+// TestComputeLorenzCurves verifies the curve points, that the area they
+// enclose gives the Gini coefficient, and the slot window.
+func TestComputeLorenzCurves(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(10), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(10), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(10), BuilderPubkey: "0xA"},
+		{Slot: 4, ValueWei: big.NewInt(70), BuilderPubkey: "0xB"},
+		{Slot: 9, ValueWei: nil, BuilderPubkey: "0xC"},
+	}
+
+	curves, err := ComputeLorenzCurves(bribes, 1, 4)
+	if err != nil {
+		t.Fatalf("ComputeLorenzCurves failed: %v", err)
+	}
+	wantBlocks := []LorenzPoint{{0, 0}, {0.5, 0.25}, {1, 1}}
+	wantValue := []LorenzPoint{{0, 0}, {0.5, 0.3}, {1, 1}}
+	if curves.Builders != 2 || !reflect.DeepEqual(curves.ByBlocks, wantBlocks) || !reflect.DeepEqual(curves.ByValue, wantValue) {
+		t.Errorf("expected %v by blocks and %v by value, got %+v", wantBlocks, wantValue, curves)
+	}
+
+	// G = 1 - Σ (x_k - x_{k-1})(y_k + y_{k-1})
+	gini, _ := GiniCoefficient(bribes[:4])
+	for _, c := range []struct {
+		points []LorenzPoint
+		want   float64
+	}{{curves.ByBlocks, gini.ByBlocks}, {curves.ByValue, gini.ByValue}} {
+		area := 1.0
+		for i := 1; i < len(c.points); i++ {
+			area -= (c.points[i].BuilderShare - c.points[i-1].BuilderShare) * (c.points[i].Share + c.points[i-1].Share)
+		}
+		if math.Abs(area-c.want) > 1e-12 {
+			t.Errorf("expected the curve to enclose Gini %f, got %f", c.want, area)
+		}
+	}
+
+	if _, err := ComputeLorenzCurves(bribes, 0, 0); !errors.Is(err, ErrNilValue) {
+		t.Errorf("expected ErrNilValue with slot 9 in the window, got %v", err)
+	}
+	if _, err := ComputeLorenzCurves(bribes, 100, 0); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData for an empty window, got %v", err)
+	}
+}
+
+// TestHerfindahlIndex verifies both weightings against hand-computed shares.
+func TestHerfindahlIndex(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(600), BuilderPubkey: "0xB"},
+		{Slot: 4, ValueWei: big.NewInt(200), BuilderPubkey: "0xC"},
+	}
+
+	// Block shares 0.5, 0.25, 0.25: 0.25 + 0.0625 + 0.0625
+	hhi, err := HerfindahlIndex(bribes, ConcentrationByBlocks)
+	if err != nil {
+		t.Fatalf("HerfindahlIndex failed: %v", err)
+	}
+	if math.Abs(hhi-0.375) > 1e-12 {
+		t.Errorf("expected block HHI 0.375, got %f", hhi)
+	}
+
+	// Value shares 0.2, 0.6, 0.2: 0.04 + 0.36 + 0.04
+	hhi, err = HerfindahlIndex(bribes, ConcentrationByValue)
+	if err != nil {
+		t.Fatalf("HerfindahlIndex failed: %v", err)
+	}
+	if math.Abs(hhi-0.44) > 1e-12 {
+		t.Errorf("expected value HHI 0.44, got %f", hhi)
+	}
+
+	// A monopoly has HHI 1
+	if hhi, _ := HerfindahlIndex(bribes[:2], ConcentrationByBlocks); hhi != 1 {
+		t.Errorf("expected monopoly HHI 1, got %f", hhi)
+	}
+
+	if _, err := HerfindahlIndex(nil, ConcentrationByBlocks); err == nil {
+		t.Error("Expected error for empty bribes, got nil")
+	}
+	if _, err := HerfindahlIndex(bribes, "stake"); err == nil {
+		t.Error("Expected error for unknown metric, got nil")
+	}
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// CostIndex answers censorship cost queries for any contiguous window of a
+// dataset from prefix sums, so interactive callers (e.g. sliders over τ
+// and start slot) do not re-sum the window each time. Sums are exact
+// big.Ints and memory is proportional to the dataset.
+//
+// A CostIndex is immutable once built and safe for concurrent use.
+type CostIndex struct {
+	slots  []uint64
+	prefix []*big.Int // prefix[i] is the sum of the first i bribes
+	dense  bool       // Whether slots are consecutive, so positions follow from slot numbers
+}
+
+// NewCostIndex builds the prefix sums of bribes, which must be sorted by
+// strictly increasing slot.
+func NewCostIndex(bribes []SlotBribe) (*CostIndex, error) {
+	idx := &CostIndex{
+		slots:  make([]uint64, len(bribes)),
+		prefix: make([]*big.Int, len(bribes)+1),
+	}
+	idx.prefix[0] = new(big.Int)
+	for i, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			return nil, fmt.Errorf("%w for slot %d", ErrNilValue, bribe.Slot)
+		}
+		if i > 0 && bribe.Slot <= bribes[i-1].Slot {
+			return nil, fmt.Errorf("duplicate or unsorted slot %d at index %d", bribe.Slot, i)
+		}
+		idx.slots[i] = bribe.Slot
+		idx.prefix[i+1] = new(big.Int).Add(idx.prefix[i], bribe.ValueWei)
+	}
+	idx.dense = len(bribes) == 0 || bribes[len(bribes)-1].Slot-bribes[0].Slot == uint64(len(bribes)-1)
+	return idx, nil
+}
+
+// Len returns the number of indexed bribes.
+func (idx *CostIndex) Len() int {
+	return len(idx.slots)
+}
+
+// Cost returns C_c(τ) over the tau bribes starting at position start, as
+// CensorshipCost(bribes[start:], tau), in constant time.
+func (idx *CostIndex) Cost(start int, tau uint64) (*big.Int, error) {
+	if start < 0 || start > len(idx.slots) {
+		return nil, fmt.Errorf("start position %d out of range [0, %d]", start, len(idx.slots))
+	}
+	if uint64(len(idx.slots)-start) < tau {
+		return nil, fmt.Errorf("%w: need %d slots, have %d", ErrInsufficientData, tau, len(idx.slots)-start)
+	}
+	end := start + int(tau)
+	return new(big.Int).Sub(idx.prefix[end], idx.prefix[start]), nil
+}
+
+// CostRange returns the cost of the inclusive slot range
+// [startSlot, endSlot] with the semantics of CensorshipCostRange: a range
+// with missing slots fails with a *MissingSlotsError. It takes constant
+// time on gap-free data and logarithmic time otherwise.
+func (idx *CostIndex) CostRange(startSlot, endSlot uint64) (*big.Int, error) {
+	if endSlot < startSlot {
+		return nil, fmt.Errorf("end slot %d precedes start slot %d", endSlot, startSlot)
+	}
+	lo, hi := idx.position(startSlot), len(idx.slots)
+	if endSlot < ^uint64(0) {
+		hi = idx.position(endSlot + 1)
+	}
+	if uint64(hi-lo) != endSlot-startSlot+1 {
+		return nil, &MissingSlotsError{StartSlot: startSlot, EndSlot: endSlot, Missing: idx.missing(lo, hi, startSlot, endSlot)}
+	}
+	return new(big.Int).Sub(idx.prefix[hi], idx.prefix[lo]), nil
+}
+
+// CostWindow returns the cost of the tau slots starting at startSlot, as
+// CensorshipCostWindow.
+func (idx *CostIndex) CostWindow(startSlot, tau uint64) (*big.Int, error) {
+	if tau < 1 {
+		return nil, fmt.Errorf("tau must be at least 1")
+	}
+	return idx.CostRange(startSlot, startSlot+tau-1)
+}
+
+// position returns the index of the first bribe with a slot >= slot.
+func (idx *CostIndex) position(slot uint64) int {
+	n := len(idx.slots)
+	if idx.dense && n > 0 {
+		switch {
+		case slot <= idx.slots[0]:
+			return 0
+		case slot-idx.slots[0] >= uint64(n):
+			return n
+		default:
+			return int(slot - idx.slots[0])
+		}
+	}
+	return sort.Search(n, func(i int) bool { return idx.slots[i] >= slot })
+}
+
+// missing lists the slots of [startSlot, endSlot] absent from positions
+// lo to hi.
+func (idx *CostIndex) missing(lo, hi int, startSlot, endSlot uint64) []uint64 {
+	var missing []uint64
+	next := startSlot
+	for _, slot := range idx.slots[lo:hi] {
+		for ; next < slot; next++ {
+			missing = append(missing, next)
+		}
+		next = slot + 1
+	}
+	for ; next <= endSlot && next >= startSlot; next++ {
+		missing = append(missing, next)
+	}
+	return missing
+}
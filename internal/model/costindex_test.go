@@ -0,0 +1,77 @@
+package model
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// TestCostIndex verifies every window matches CensorshipCost and
+// CensorshipCostRange, on dense and gapped data.
+func TestCostIndex(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	dense := []SlotBribe{
+		{Slot: 100, ValueWei: big.NewInt(5)},
+		{Slot: 101, ValueWei: huge},
+		{Slot: 102, ValueWei: big.NewInt(7)},
+		{Slot: 103, ValueWei: big.NewInt(0)},
+	}
+	gapped := []SlotBribe{
+		{Slot: 100, ValueWei: big.NewInt(5)},
+		{Slot: 102, ValueWei: big.NewInt(7)},
+		{Slot: 103, ValueWei: big.NewInt(9)},
+		{Slot: 106, ValueWei: big.NewInt(11)},
+	}
+
+	for name, bribes := range map[string][]SlotBribe{"dense": dense, "gapped": gapped} {
+		idx, err := NewCostIndex(bribes)
+		if err != nil {
+			t.Fatalf("%s: NewCostIndex failed: %v", name, err)
+		}
+		for start := 0; start <= len(bribes); start++ {
+			for tau := uint64(0); tau <= uint64(len(bribes)-start); tau++ {
+				want, _ := CensorshipCost(bribes[start:], tau)
+				got, err := idx.Cost(start, tau)
+				if err != nil || got.Cmp(want) != 0 {
+					t.Errorf("%s: expected Cost(%d, %d) %s, got %v (err %v)", name, start, tau, want, got, err)
+				}
+			}
+		}
+		for startSlot := uint64(98); startSlot <= 108; startSlot++ {
+			for endSlot := startSlot; endSlot <= 108; endSlot++ {
+				want, wantErr := CensorshipCostRange(bribes, startSlot, endSlot)
+				got, err := idx.CostRange(startSlot, endSlot)
+				if wantErr != nil {
+					var wantMissing, gotMissing *MissingSlotsError
+					errors.As(wantErr, &wantMissing)
+					if !errors.As(err, &gotMissing) || !reflect.DeepEqual(gotMissing.Missing, wantMissing.Missing) {
+						t.Errorf("%s: expected %v for %d-%d, got %v", name, wantErr, startSlot, endSlot, err)
+					}
+					continue
+				}
+				if err != nil || got.Cmp(want) != 0 {
+					t.Errorf("%s: expected %s for %d-%d, got %v (err %v)", name, want, startSlot, endSlot, got, err)
+				}
+			}
+		}
+	}
+
+	idx, _ := NewCostIndex(dense)
+	if _, err := idx.Cost(2, 3); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+	if cost, err := idx.CostWindow(102, 2); err != nil || cost.Int64() != 7 {
+		t.Errorf("expected cost 7 for slots 102-103, got %v (err %v)", cost, err)
+	}
+}
+
+// TestNewCostIndex_Invalid verifies unsorted and nil bribes are rejected.
+func TestNewCostIndex_Invalid(t *testing.T) {
+	if _, err := NewCostIndex([]SlotBribe{{Slot: 2, ValueWei: big.NewInt(1)}, {Slot: 1, ValueWei: big.NewInt(1)}}); err == nil {
+		t.Error("Expected error for unsorted slots, got nil")
+	}
+	if _, err := NewCostIndex([]SlotBribe{{Slot: 1}}); !errors.Is(err, ErrNilValue) {
+		t.Errorf("expected ErrNilValue, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// CounterBribeResult is the equilibrium of a bidding war between the
+// attacker and a defending bridge (see CounterBribeEquilibrium).
+type CounterBribeResult struct {
+	AttackerValue   *big.Float // A = p(V)·V, what winning is worth to the attacker
+	DefenderValue   *big.Float // D, what keeping the proof in is worth to the bridge
+	BaseCost        *big.Float // C_c^eff, paid before any counter-bribe
+	AttackerBid     *big.Float // Expected escalation paid by the attacker
+	DefenderBid     *big.Float // Expected counter-bribe paid by the bridge
+	EscalatedCost   *big.Float // BaseCost + AttackerBid
+	AttackerWinProb float64    // Probability the attacker outbids the bridge
+	ExpectedProfit  *big.Float // A·AttackerWinProb - EscalatedCost
+	BaseProfit      *big.Float // A - BaseCost, with no defender
+}
+
+// CounterBribeEquilibrium models a bridge that counter-bribes builders
+// and proposers to get its fraud proof included. After the attacker has
+// paid C_c^eff to censor an undefended window, the two bid for the same
+// slots in an all-pay contest: both pay what they bid, and the higher
+// total wins. With complete information about the values A (attacker) and
+// D (defender) the contest has a unique mixed equilibrium (Hillman and
+// Riley, 1989; Baye, Kovenock and de Vries, 1996). When A ≥ D:
+//
+//	E[attacker bid] = D / 2
+//	E[defender bid] = D² / (2A)
+//	P(attacker wins) = 1 - D / (2A)
+//
+// and symmetrically when D > A. The defender's best response makes the
+// attacker's cost C_c^eff + E[attacker bid] and scales its revenue by the
+// probability of winning, so a bridge willing to spend D of the value it
+// protects raises the bar for every attack.
+//
+// params are as for AttackerProfit. defenderValue is an assumption, at
+// most the TVL the bridge would lose.
+func CounterBribeEquilibrium(bribes []SlotBribe, params ProfitParams, defenderValue *big.Float) (*CounterBribeResult, error) {
+	if defenderValue == nil || defenderValue.Sign() < 0 {
+		return nil, fmt.Errorf("defender value cannot be nil or negative")
+	}
+
+	base, err := AttackerProfit(bribes, params)
+	if err != nil {
+		return nil, err
+	}
+
+	a, d := base.ExpectedRevenue, defenderValue
+	result := &CounterBribeResult{
+		AttackerValue: new(big.Float).Set(a),
+		DefenderValue: new(big.Float).Set(d),
+		BaseCost:      new(big.Float).Set(base.EffectiveCost),
+		AttackerBid:   new(big.Float),
+		DefenderBid:   new(big.Float),
+		BaseProfit:    new(big.Float).Set(base.Profit),
+	}
+
+	switch {
+	case d.Sign() == 0:
+		// An undefended bridge is attacked at the base cost
+		result.AttackerWinProb = 1
+	case a.Sign() == 0:
+		// Nothing to win, so the attacker bids nothing and loses
+		result.AttackerWinProb = 0
+	case a.Cmp(d) >= 0:
+		ratio, _ := new(big.Float).Quo(d, a).Float64()
+		result.AttackerBid.Quo(d, big.NewFloat(2))
+		result.DefenderBid.Mul(result.AttackerBid, big.NewFloat(ratio))
+		result.AttackerWinProb = 1 - ratio/2
+	default:
+		ratio, _ := new(big.Float).Quo(a, d).Float64()
+		result.DefenderBid.Quo(a, big.NewFloat(2))
+		result.AttackerBid.Mul(result.DefenderBid, big.NewFloat(ratio))
+		result.AttackerWinProb = ratio / 2
+	}
+
+	result.EscalatedCost = new(big.Float).Add(result.BaseCost, result.AttackerBid)
+	result.ExpectedProfit = new(big.Float).Mul(a, big.NewFloat(result.AttackerWinProb))
+	result.ExpectedProfit.Sub(result.ExpectedProfit, result.EscalatedCost)
+
+	return result, nil
+}
+
+// DefenseBudget is the smallest credible counter-bribe commitment that
+// makes an attack unprofitable (see FindDefenseBudget).
+type DefenseBudget struct {
+	Budget        *big.Float // D* = max(0, V - C_c^eff)
+	FractionOfTVL float64    // D* / V
+	BaseCost      *big.Float // C_c^eff
+}
+
+// FindDefenseBudget computes the defense budget a bridge must credibly
+// commit so that censoring it is unprofitable for every p ≤ 1.
+//
+// In the equilibrium of CounterBribeEquilibrium the attacker's expected
+// profit is A - D - C_c^eff when A ≥ D, and -C_c^eff when D > A, with
+// A = p·V. It is therefore non-positive exactly when D ≥ A - C_c^eff, and
+// the binding case is p = 1:
+//
+//	D* = max(0, V - C_c^eff)
+//
+// params.SuccessProbability and params.Probability are ignored; the other
+// cost parameters apply as for AttackerProfit.
+func FindDefenseBudget(bribes []SlotBribe, params ProfitParams) (*DefenseBudget, error) {
+	if params.BridgeTVL == nil || params.BridgeTVL.Sign() <= 0 {
+		return nil, fmt.Errorf("BridgeTVL must be positive")
+	}
+
+	params.SuccessProbability = 1
+	params.Probability = nil
+	base, err := AttackerProfit(bribes, params)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := new(big.Float)
+	if base.Profit.Sign() > 0 {
+		budget.Set(base.Profit)
+	}
+	fraction, _ := new(big.Float).Quo(budget, params.BridgeTVL).Float64()
+	return &DefenseBudget{
+		Budget:        budget,
+		FractionOfTVL: fraction,
+		BaseCost:      new(big.Float).Set(base.EffectiveCost),
+	}, nil
+}
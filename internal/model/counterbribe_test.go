@@ -0,0 +1,92 @@
+package model
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestCounterBribeEquilibrium verifies the all-pay equilibrium for a
+// stronger attacker, a stronger defender and no defense.
+func TestCounterBribeEquilibrium(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+	}
+	// A = 0.5·8000 = 4000, C_c^eff = 1000
+	params := ProfitParams{BridgeTVL: big.NewFloat(8000), SuccessProbability: 0.5, Tau: 2, TopK: 1}
+
+	cases := []struct {
+		name         string
+		defender     float64
+		attackerBid  float64
+		defenderBid  float64
+		winProb      float64
+		expectedGain float64
+	}{
+		{"undefended", 0, 0, 0, 1, 3000},
+		{"weaker defender", 2000, 1000, 500, 0.75, 1000},     // 4000·0.75 - 2000
+		{"stronger defender", 8000, 1000, 2000, 0.25, -1000}, // 4000·0.25 - 2000
+	}
+	for _, tc := range cases {
+		result, err := CounterBribeEquilibrium(bribes, params, big.NewFloat(tc.defender))
+		if err != nil {
+			t.Fatalf("%s: CounterBribeEquilibrium failed: %v", tc.name, err)
+		}
+		if result.AttackerWinProb != tc.winProb {
+			t.Errorf("%s: expected win probability %f, got %f", tc.name, tc.winProb, result.AttackerWinProb)
+		}
+		if !floatEqual(result.AttackerBid, big.NewFloat(tc.attackerBid), 0.01) || !floatEqual(result.DefenderBid, big.NewFloat(tc.defenderBid), 0.01) {
+			t.Errorf("%s: expected bids %f and %f, got %s and %s", tc.name, tc.attackerBid, tc.defenderBid, result.AttackerBid.String(), result.DefenderBid.String())
+		}
+		if !floatEqual(result.ExpectedProfit, big.NewFloat(tc.expectedGain), 0.01) {
+			t.Errorf("%s: expected profit %f, got %s", tc.name, tc.expectedGain, result.ExpectedProfit.String())
+		}
+		if !floatEqual(result.BaseProfit, big.NewFloat(3000), 0.01) {
+			t.Errorf("%s: expected base profit 3000, got %s", tc.name, result.BaseProfit.String())
+		}
+	}
+
+	if _, err := CounterBribeEquilibrium(bribes, params, big.NewFloat(-1)); err == nil {
+		t.Error("Expected error for negative defender value, got nil")
+	}
+}
+
+// TestFindDefenseBudget verifies the budget deters the worst-case attacker
+// and every weaker one.
+func TestFindDefenseBudget(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+	}
+	params := ProfitParams{BridgeTVL: big.NewFloat(8000), Tau: 2, TopK: 1}
+
+	// D* = 8000 - 1000
+	defense, err := FindDefenseBudget(bribes, params)
+	if err != nil {
+		t.Fatalf("FindDefenseBudget failed: %v", err)
+	}
+	if !floatEqual(defense.Budget, big.NewFloat(7000), 0.01) || defense.FractionOfTVL != 0.875 {
+		t.Errorf("expected budget 7000 (0.875 of TVL), got %s (%f)", defense.Budget.String(), defense.FractionOfTVL)
+	}
+
+	for _, p := range []float64{1, 0.9, 0.5, 0.1} {
+		params.SuccessProbability = p
+		result, err := CounterBribeEquilibrium(bribes, params, defense.Budget)
+		if err != nil {
+			t.Fatalf("CounterBribeEquilibrium failed: %v", err)
+		}
+		if result.ExpectedProfit.Cmp(big.NewFloat(1e-6)) > 0 {
+			t.Errorf("p=%f: expected no profit against D*, got %s", p, result.ExpectedProfit.String())
+		}
+	}
+
+	// A bridge already below V* needs no defense
+	params.BridgeTVL = big.NewFloat(500)
+	defense, err = FindDefenseBudget(bribes, params)
+	if err != nil {
+		t.Fatalf("FindDefenseBudget failed: %v", err)
+	}
+	if defense.Budget.Sign() != 0 {
+		t.Errorf("expected zero budget, got %s", defense.Budget.String())
+	}
+}
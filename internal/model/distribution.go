@@ -0,0 +1,208 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+)
+
+// BidDistribution is a parametric distribution of positive per-slot bids
+// in wei. Fitted distributions generate synthetic τ-windows for Monte
+// Carlo simulation in place of a single observed C_c.
+type BidDistribution interface {
+	Name() string
+	NumParams() int
+	CDF(x float64) float64
+	LogPDF(x float64) float64
+	Sample(rng *rand.Rand) float64
+}
+
+// LognormalBids is the lognormal distribution: ln b ~ N(Mu, Sigma²).
+type LognormalBids struct {
+	Mu    float64
+	Sigma float64
+}
+
+func (d LognormalBids) Name() string   { return "lognormal" }
+func (d LognormalBids) NumParams() int { return 2 }
+
+func (d LognormalBids) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return 0.5 * math.Erfc(-(math.Log(x)-d.Mu)/(d.Sigma*math.Sqrt2))
+}
+
+func (d LognormalBids) LogPDF(x float64) float64 {
+	if x <= 0 {
+		return math.Inf(-1)
+	}
+	z := (math.Log(x) - d.Mu) / d.Sigma
+	return -math.Log(x) - math.Log(d.Sigma) - 0.5*math.Log(2*math.Pi) - 0.5*z*z
+}
+
+func (d LognormalBids) Sample(rng *rand.Rand) float64 {
+	return math.Exp(d.Mu + d.Sigma*rng.NormFloat64())
+}
+
+// ParetoBids is the Pareto distribution with scale Xm and tail index
+// Alpha: P(b > x) = (Xm / x)^Alpha for x ≥ Xm.
+type ParetoBids struct {
+	Xm    float64
+	Alpha float64
+}
+
+func (d ParetoBids) Name() string   { return "pareto" }
+func (d ParetoBids) NumParams() int { return 2 }
+
+func (d ParetoBids) CDF(x float64) float64 {
+	if x < d.Xm {
+		return 0
+	}
+	return 1 - math.Pow(d.Xm/x, d.Alpha)
+}
+
+func (d ParetoBids) LogPDF(x float64) float64 {
+	if x < d.Xm {
+		return math.Inf(-1)
+	}
+	return math.Log(d.Alpha) + d.Alpha*math.Log(d.Xm) - (d.Alpha+1)*math.Log(x)
+}
+
+func (d ParetoBids) Sample(rng *rand.Rand) float64 {
+	// 1 - U lies in (0, 1], so the inverse CDF is finite
+	return d.Xm * math.Pow(1-rng.Float64(), -1/d.Alpha)
+}
+
+// FitLognormal returns the maximum-likelihood lognormal for positive
+// values.
+func FitLognormal(values []float64) (LognormalBids, error) {
+	if err := checkFitValues(values); err != nil {
+		return LognormalBids{}, err
+	}
+	var sum float64
+	for _, v := range values {
+		sum += math.Log(v)
+	}
+	mu := sum / float64(len(values))
+	var squares float64
+	for _, v := range values {
+		d := math.Log(v) - mu
+		squares += d * d
+	}
+	return LognormalBids{Mu: mu, Sigma: math.Sqrt(squares / float64(len(values)))}, nil
+}
+
+// FitPareto returns the maximum-likelihood Pareto for positive values:
+// Xm is the smallest value and Alpha = n / Σ ln(x / Xm).
+func FitPareto(values []float64) (ParetoBids, error) {
+	if err := checkFitValues(values); err != nil {
+		return ParetoBids{}, err
+	}
+	xm := values[0]
+	for _, v := range values {
+		xm = math.Min(xm, v)
+	}
+	var sum float64
+	for _, v := range values {
+		sum += math.Log(v / xm)
+	}
+	return ParetoBids{Xm: xm, Alpha: float64(len(values)) / sum}, nil
+}
+
+// checkFitValues requires at least two positive values that are not all
+// equal, without which neither fit has a finite parameter.
+func checkFitValues(values []float64) error {
+	if len(values) < 2 {
+		return fmt.Errorf("%w: need at least 2 positive bids to fit, have %d", ErrInsufficientData, len(values))
+	}
+	for _, v := range values {
+		if v <= 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+			return fmt.Errorf("cannot fit non-positive bid %g", v)
+		}
+		if v != values[0] {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot fit %d identical bids", len(values))
+}
+
+// BidFit is a distribution fitted to the positive bids of a dataset,
+// along with measures of fit quality over those bids.
+type BidFit struct {
+	Distribution  BidDistribution
+	ZeroFraction  float64 // Share of slots with a zero bid, sampled as exactly zero
+	LogLikelihood float64
+	AIC           float64 // 2·params - 2·LogLikelihood; lower is better
+	KS            float64 // Kolmogorov–Smirnov distance to the empirical CDF
+}
+
+// FitBidDistributions fits each candidate distribution (lognormal,
+// Pareto) to the per-slot bids and returns the fits, best (smallest KS
+// distance) first.
+//
+// Neither candidate has mass at zero, so zero bids are left out of the
+// fit and kept as ZeroFraction.
+func FitBidDistributions(bribes []SlotBribe) ([]BidFit, error) {
+	values := make([]float64, 0, len(bribes))
+	for i, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilValue, i)
+		}
+		if bribe.ValueWei.Sign() > 0 {
+			v, _ := new(big.Float).SetInt(bribe.ValueWei).Float64()
+			values = append(values, v)
+		}
+	}
+
+	lognormal, err := FitLognormal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fit lognormal: %w", err)
+	}
+	pareto, err := FitPareto(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fit pareto: %w", err)
+	}
+
+	sort.Float64s(values)
+	zeroFraction := float64(len(bribes)-len(values)) / float64(len(bribes))
+	fits := []BidFit{
+		newBidFit(lognormal, values, zeroFraction),
+		newBidFit(pareto, values, zeroFraction),
+	}
+	sort.SliceStable(fits, func(i, j int) bool {
+		return fits[i].KS < fits[j].KS
+	})
+	return fits, nil
+}
+
+// newBidFit measures how well dist fits sorted.
+func newBidFit(dist BidDistribution, sorted []float64, zeroFraction float64) BidFit {
+	fit := BidFit{Distribution: dist, ZeroFraction: zeroFraction}
+	n := float64(len(sorted))
+	for i, v := range sorted {
+		fit.LogLikelihood += dist.LogPDF(v)
+		cdf := dist.CDF(v)
+		fit.KS = math.Max(fit.KS, math.Max(cdf-float64(i)/n, float64(i+1)/n-cdf))
+	}
+	fit.AIC = 2*float64(dist.NumParams()) - 2*fit.LogLikelihood
+	return fit
+}
+
+// SampleWindow draws a synthetic censorship cost C_c(τ): the sum of τ
+// independent bids, each zero with probability ZeroFraction and otherwise
+// drawn from the fitted distribution.
+func (f BidFit) SampleWindow(rng *rand.Rand, tau uint64) *big.Int {
+	total := new(big.Int)
+	for i := uint64(0); i < tau; i++ {
+		if rng.Float64() < f.ZeroFraction {
+			continue
+		}
+		bid := math.Min(f.Distribution.Sample(rng), math.MaxFloat64)
+		wei, _ := big.NewFloat(bid).Int(nil)
+		total.Add(total, wei)
+	}
+	return total
+}
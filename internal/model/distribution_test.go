@@ -0,0 +1,98 @@
+package model
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// sampleBribes draws n bribes from dist with a fixed seed.
+func sampleBribes(dist BidDistribution, n int) []SlotBribe {
+	rng := rand.New(rand.NewSource(1))
+	bribes := make([]SlotBribe, n)
+	for i := range bribes {
+		wei, _ := big.NewFloat(dist.Sample(rng)).Int(nil)
+		bribes[i] = SlotBribe{Slot: uint64(i + 1), ValueWei: wei, BuilderPubkey: "0xA"}
+	}
+	return bribes
+}
+
+// TestFitBidDistributions verifies each candidate is recovered from its
+// own samples and ranked first.
+func TestFitBidDistributions(t *testing.T) {
+	lognormal := LognormalBids{Mu: math.Log(5e16), Sigma: 0.8}
+	fits, err := FitBidDistributions(sampleBribes(lognormal, 5000))
+	if err != nil {
+		t.Fatalf("FitBidDistributions failed: %v", err)
+	}
+	best, ok := fits[0].Distribution.(LognormalBids)
+	if !ok {
+		t.Fatalf("expected lognormal to fit best, got %s", fits[0].Distribution.Name())
+	}
+	if math.Abs(best.Mu-lognormal.Mu) > 0.05 || math.Abs(best.Sigma-lognormal.Sigma) > 0.05 {
+		t.Errorf("expected mu %f sigma %f, got %f %f", lognormal.Mu, lognormal.Sigma, best.Mu, best.Sigma)
+	}
+	if fits[0].KS > fits[1].KS || fits[0].AIC > fits[1].AIC {
+		t.Errorf("expected lognormal to have the lower KS and AIC, got %+v", fits)
+	}
+
+	pareto := ParetoBids{Xm: 1e16, Alpha: 2.5}
+	fits, err = FitBidDistributions(sampleBribes(pareto, 5000))
+	if err != nil {
+		t.Fatalf("FitBidDistributions failed: %v", err)
+	}
+	fitted, ok := fits[0].Distribution.(ParetoBids)
+	if !ok {
+		t.Fatalf("expected pareto to fit best, got %s", fits[0].Distribution.Name())
+	}
+	if math.Abs(fitted.Alpha-pareto.Alpha) > 0.15 {
+		t.Errorf("expected alpha %f, got %f", pareto.Alpha, fitted.Alpha)
+	}
+}
+
+// TestFitBidDistributions_Zeros verifies zero bids become ZeroFraction and
+// degenerate data is rejected.
+func TestFitBidDistributions_Zeros(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(0)},
+		{Slot: 2, ValueWei: big.NewInt(1000)},
+		{Slot: 3, ValueWei: big.NewInt(2000)},
+		{Slot: 4, ValueWei: big.NewInt(4000)},
+	}
+	fits, err := FitBidDistributions(bribes)
+	if err != nil {
+		t.Fatalf("FitBidDistributions failed: %v", err)
+	}
+	for _, fit := range fits {
+		if fit.ZeroFraction != 0.25 {
+			t.Errorf("%s: expected zero fraction 0.25, got %f", fit.Distribution.Name(), fit.ZeroFraction)
+		}
+	}
+
+	if _, err := FitBidDistributions(bribes[:2]); err == nil {
+		t.Error("Expected error for a single positive bid, got nil")
+	}
+	same := []SlotBribe{{Slot: 1, ValueWei: big.NewInt(5)}, {Slot: 2, ValueWei: big.NewInt(5)}}
+	if _, err := FitBidDistributions(same); err == nil {
+		t.Error("Expected error for identical bids, got nil")
+	}
+}
+
+// TestBidFit_SampleWindow verifies sampled windows average τ times the
+// distribution's mean.
+func TestBidFit_SampleWindow(t *testing.T) {
+	fit := BidFit{Distribution: ParetoBids{Xm: 1000, Alpha: 3}, ZeroFraction: 0.5}
+	rng := rand.New(rand.NewSource(1))
+
+	// Mean bid = 0.5 · 3·1000/2 = 750, so C_c(10) averages 7500
+	total := new(big.Int)
+	const draws = 20000
+	for i := 0; i < draws; i++ {
+		total.Add(total, fit.SampleWindow(rng, 10))
+	}
+	mean, _ := new(big.Float).Quo(new(big.Float).SetInt(total), big.NewFloat(draws)).Float64()
+	if math.Abs(mean-7500) > 150 {
+		t.Errorf("expected mean window cost ~7500, got %f", mean)
+	}
+}
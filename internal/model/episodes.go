@@ -0,0 +1,253 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+// CensorshipEpisode is one observed exclusion of a transaction: from
+// FirstSlot to LastSlot every block omitted it although it was valid and
+// paid enough to be included.
+//
+// Included is true when the transaction landed in the slot after
+// LastSlot, ending the episode. An episode still excluded when
+// observation stopped (or whose transaction was dropped) is right-censored:
+// it lasted at least its duration, not exactly.
+type CensorshipEpisode struct {
+	TxHash    string `json:"tx_hash"`
+	FirstSlot uint64 `json:"first_slot"`
+	LastSlot  uint64 `json:"last_slot"`
+	Included  bool   `json:"included"`
+}
+
+// Duration returns the number of slots the transaction was excluded for.
+func (e CensorshipEpisode) Duration() uint64 {
+	return e.LastSlot - e.FirstSlot + 1
+}
+
+// CensorshipEpisodeFile is the on-disk episode dataset format:
+//
+//	{"episodes": [{"tx_hash": "0xabc...", "first_slot": 100, "last_slot": 104, "included": true}]}
+type CensorshipEpisodeFile struct {
+	Episodes []CensorshipEpisode `json:"episodes"`
+}
+
+// ParseCensorshipEpisodes decodes episodes in CensorshipEpisodeFile format.
+//
+// Fails if an episode ends before it starts.
+func ParseCensorshipEpisodes(data []byte) ([]CensorshipEpisode, error) {
+	var file CensorshipEpisodeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse censorship episodes: %w", err)
+	}
+	for i, e := range file.Episodes {
+		if e.LastSlot < e.FirstSlot {
+			return nil, fmt.Errorf("episode %d (%s) ends at slot %d before it starts at slot %d", i, e.TxHash, e.LastSlot, e.FirstSlot)
+		}
+	}
+	return file.Episodes, nil
+}
+
+// LoadCensorshipEpisodes reads an episode file in CensorshipEpisodeFile
+// format.
+func LoadCensorshipEpisodes(path string) ([]CensorshipEpisode, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read censorship episodes %s: %w", path, err)
+	}
+	return ParseCensorshipEpisodes(data)
+}
+
+// SuccessCurveConfig tunes EstimateSuccessCurve. Zero fields select
+// defaults: 95% intervals from 2000 posterior draws.
+type SuccessCurveConfig struct {
+	Credibility float64 // Posterior mass of each interval, in (0, 1)
+	Draws       int     // Posterior draws behind the intervals
+	Seed        int64
+}
+
+// Success curve defaults.
+const (
+	DefaultCredibility    = 0.95
+	DefaultPosteriorDraws = 2000
+)
+
+// SuccessPoint is the estimated probability that an exclusion lasts at
+// least Tau slots. It holds until the next point of the curve.
+type SuccessPoint struct {
+	Tau         uint64
+	Probability float64 // Kaplan-Meier estimate
+	Lower       float64 // Credible interval
+	Upper       float64
+	AtRisk      int // Episodes observed excluded for at least Tau slots
+}
+
+// SuccessCurve is an empirical p(τ): the probability that a censorship
+// attempt keeps a transaction out for at least τ slots, as a step
+// function of τ.
+type SuccessCurve struct {
+	Episodes    int
+	Included    int    // Episodes that ended in inclusion; the rest are right-censored
+	Horizon     uint64 // Largest τ the data estimate
+	Credibility float64
+	Draws       int
+	Seed        int64
+	Points      []SuccessPoint // Ascending Tau, starting at 1
+}
+
+// At returns the point of the curve in effect at tau, or false when tau
+// is 0 or beyond Horizon, where the data say nothing.
+func (c *SuccessCurve) At(tau uint64) (SuccessPoint, bool) {
+	if tau < 1 || tau > c.Horizon {
+		return SuccessPoint{}, false
+	}
+	i := sort.Search(len(c.Points), func(i int) bool { return c.Points[i].Tau > tau })
+	return c.Points[i-1], true
+}
+
+// EstimateSuccessCurve fits p(τ) = P(exclusion lasts ≥ τ slots) to
+// observed episodes, so analyses can cite a success probability derived
+// from data rather than assumed.
+//
+// The point estimate is the Kaplan-Meier product over the slots τ' < τ
+// at which episodes ended, p(τ) = Π (1 - d/n), with d the episodes
+// included after exactly τ' slots and n those excluded for at least τ'.
+// Right-censored episodes count as at risk until they leave observation.
+// Credible intervals treat each of those hazards d/n as an independent
+// Beta(1/2 + d, 1/2 + n - d) posterior (a Jeffreys prior) and take central
+// percentiles of the product over draws. Horizon is one slot past the
+// longest episode: beyond it no episode was at risk.
+//
+// Episodes are conditioned on an exclusion having begun, and on whatever
+// selected them into the dataset (e.g. only transactions a monitor
+// flagged), so p(τ) carries that selection; p(1) is 1 by construction.
+func EstimateSuccessCurve(episodes []CensorshipEpisode, config SuccessCurveConfig) (*SuccessCurve, error) {
+	if config.Credibility == 0 {
+		config.Credibility = DefaultCredibility
+	}
+	if config.Draws == 0 {
+		config.Draws = DefaultPosteriorDraws
+	}
+	if !(config.Credibility > 0 && config.Credibility < 1) {
+		return nil, fmt.Errorf("credibility must be in (0, 1), got %v", config.Credibility)
+	}
+	if config.Draws < 1 {
+		return nil, fmt.Errorf("draws must be at least 1, got %d", config.Draws)
+	}
+	if len(episodes) == 0 {
+		return nil, fmt.Errorf("%w: no censorship episodes", ErrInsufficientData)
+	}
+
+	curve := &SuccessCurve{
+		Episodes:    len(episodes),
+		Credibility: config.Credibility,
+		Draws:       config.Draws,
+		Seed:        config.Seed,
+	}
+
+	// Episodes ending at each duration, and episodes lasting at least it
+	durations := make([]uint64, len(episodes))
+	ended := make(map[uint64]int)
+	for i, e := range episodes {
+		if e.LastSlot < e.FirstSlot {
+			return nil, fmt.Errorf("episode %d (%s) ends at slot %d before it starts at slot %d", i, e.TxHash, e.LastSlot, e.FirstSlot)
+		}
+		durations[i] = e.Duration()
+		curve.Horizon = max(curve.Horizon, durations[i]+1)
+		if e.Included {
+			ended[durations[i]]++
+			curve.Included++
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	atRisk := func(tau uint64) int {
+		return len(durations) - sort.Search(len(durations), func(i int) bool { return durations[i] >= tau })
+	}
+
+	type hazard struct {
+		slot          uint64
+		ended, atRisk int
+	}
+	hazards := make([]hazard, 0, len(ended))
+	for slot, d := range ended {
+		hazards = append(hazards, hazard{slot: slot, ended: d, atRisk: atRisk(slot)})
+	}
+	sort.Slice(hazards, func(i, j int) bool { return hazards[i].slot < hazards[j].slot })
+
+	// The curve steps down the slot after each hazard
+	curve.Points = make([]SuccessPoint, len(hazards)+1)
+	curve.Points[0] = SuccessPoint{Tau: 1, Probability: 1, AtRisk: len(episodes)}
+	for i, h := range hazards {
+		tau := h.slot + 1
+		curve.Points[i+1] = SuccessPoint{
+			Tau:         tau,
+			Probability: curve.Points[i].Probability * (1 - float64(h.ended)/float64(h.atRisk)),
+			AtRisk:      atRisk(tau),
+		}
+	}
+
+	// Posterior draws of every point at once
+	rng := rand.New(rand.NewSource(config.Seed))
+	draws := make([][]float64, len(curve.Points))
+	for i := range draws {
+		draws[i] = make([]float64, config.Draws)
+	}
+	for r := 0; r < config.Draws; r++ {
+		p := 1.0
+		draws[0][r] = p
+		for i, h := range hazards {
+			p *= 1 - sampleBeta(rng, 0.5+float64(h.ended), 0.5+float64(h.atRisk-h.ended))
+			draws[i+1][r] = p
+		}
+	}
+	tail := (1 - config.Credibility) / 2
+	for i := range curve.Points {
+		sort.Float64s(draws[i])
+		curve.Points[i].Lower = sortedQuantile(draws[i], tail)
+		curve.Points[i].Upper = sortedQuantile(draws[i], 1-tail)
+	}
+	return curve, nil
+}
+
+// sampleBeta draws from Beta(a, b) as the ratio of gamma variates.
+func sampleBeta(rng *rand.Rand, a, b float64) float64 {
+	x := sampleGamma(rng, a)
+	return x / (x + sampleGamma(rng, b))
+}
+
+// sampleGamma draws from Gamma(shape, 1) by Marsaglia and Tsang's method,
+// boosting shapes below 1 by one and scaling the draw by U^(1/shape).
+func sampleGamma(rng *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		return sampleGamma(rng, shape+1) * math.Pow(1-rng.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := 1 - rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// sortedQuantile returns the q-quantile of sorted values, interpolating
+// linearly between ranks.
+func sortedQuantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lo] + (pos-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestParseCensorshipEpisodes verifies decoding and rejection of episodes ending before they start.
+func TestParseCensorshipEpisodes(t *testing.T) {
+	data := `{"episodes": [
+		{"tx_hash": "0xa", "first_slot": 100, "last_slot": 104, "included": true},
+		{"tx_hash": "0xb", "first_slot": 200, "last_slot": 200}
+	]}`
+	episodes, err := ParseCensorshipEpisodes([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseCensorshipEpisodes failed: %v", err)
+	}
+	if len(episodes) != 2 {
+		t.Fatalf("expected 2 episodes, got %d", len(episodes))
+	}
+	if d := episodes[0].Duration(); d != 5 || !episodes[0].Included {
+		t.Errorf("expected an included 5-slot episode, got %d slots (included %v)", d, episodes[0].Included)
+	}
+	if d := episodes[1].Duration(); d != 1 || episodes[1].Included {
+		t.Errorf("expected a censored 1-slot episode, got %d slots (included %v)", d, episodes[1].Included)
+	}
+
+	bad := `{"episodes": [{"tx_hash": "0xc", "first_slot": 10, "last_slot": 9}]}`
+	if _, err := ParseCensorshipEpisodes([]byte(bad)); err == nil {
+		t.Error("Expected error for an episode ending before it starts, got nil")
+	}
+}
+
+// TestEstimateSuccessCurve verifies the Kaplan-Meier steps, at-risk counts
+// and horizon with right-censored episodes.
+func TestEstimateSuccessCurve(t *testing.T) {
+	episode := func(duration uint64, included bool) CensorshipEpisode {
+		return CensorshipEpisode{FirstSlot: 1000, LastSlot: 1000 + duration - 1, Included: included}
+	}
+	episodes := []CensorshipEpisode{
+		episode(2, true), episode(2, true), episode(3, false), episode(4, true), episode(5, false),
+	}
+	curve, err := EstimateSuccessCurve(episodes, SuccessCurveConfig{Seed: 1})
+	if err != nil {
+		t.Fatalf("EstimateSuccessCurve failed: %v", err)
+	}
+	if curve.Included != 3 || curve.Horizon != 6 {
+		t.Errorf("expected 3 included and horizon 6, got %d and %d", curve.Included, curve.Horizon)
+	}
+
+	tests := []struct {
+		tau    uint64
+		p      float64
+		atRisk int
+	}{
+		{1, 1, 5},
+		{2, 1, 5},
+		{3, 0.6, 3}, // 2 of 5 ended after 2 slots
+		{4, 0.6, 3},
+		{5, 0.3, 1}, // 1 of 2 ended after 4 slots
+		{6, 0.3, 1},
+	}
+	for _, tt := range tests {
+		point, ok := curve.At(tt.tau)
+		if !ok {
+			t.Errorf("expected a point at τ=%d", tt.tau)
+			continue
+		}
+		if math.Abs(point.Probability-tt.p) > 1e-12 || point.AtRisk != tt.atRisk {
+			t.Errorf("τ=%d: expected p=%v with %d at risk, got %v with %d", tt.tau, tt.p, tt.atRisk, point.Probability, point.AtRisk)
+		}
+		if !(point.Lower <= point.Probability+1e-9 && point.Probability <= point.Upper+1e-9) || point.Lower < 0 || point.Upper > 1 {
+			t.Errorf("τ=%d: expected [%v, %v] to bracket %v within [0, 1]", tt.tau, point.Lower, point.Upper, point.Probability)
+		}
+	}
+	if _, ok := curve.At(7); ok {
+		t.Error("expected no point beyond the horizon")
+	}
+	if _, ok := curve.At(0); ok {
+		t.Error("expected no point at τ=0")
+	}
+
+	if _, err := EstimateSuccessCurve(nil, SuccessCurveConfig{}); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+	if _, err := EstimateSuccessCurve(episodes, SuccessCurveConfig{Credibility: 1}); err == nil {
+		t.Error("Expected error for credibility 1, got nil")
+	}
+}
+
+// TestEstimateSuccessCurve_Coverage verifies the credible interval narrows
+// around a known geometric p(τ) as episodes accumulate.
+func TestEstimateSuccessCurve_Coverage(t *testing.T) {
+	// Each slot of exclusion ends with probability 0.2: p(τ) = 0.8^(τ-1)
+	rng := rand.New(rand.NewSource(7))
+	sample := func(n int) []CensorshipEpisode {
+		episodes := make([]CensorshipEpisode, n)
+		for i := range episodes {
+			duration := uint64(1)
+			for rng.Float64() >= 0.2 {
+				duration++
+			}
+			episodes[i] = CensorshipEpisode{FirstSlot: 1, LastSlot: duration, Included: true}
+		}
+		return episodes
+	}
+
+	want := math.Pow(0.8, 4)
+	var widths []float64
+	for _, n := range []int{50, 2000} {
+		curve, err := EstimateSuccessCurve(sample(n), SuccessCurveConfig{Seed: 1})
+		if err != nil {
+			t.Fatalf("EstimateSuccessCurve failed: %v", err)
+		}
+		point, ok := curve.At(5)
+		if !ok {
+			t.Fatalf("expected a point at τ=5 from %d episodes", n)
+		}
+		if want < point.Lower || want > point.Upper {
+			t.Errorf("%d episodes: expected [%v, %v] to cover %v", n, point.Lower, point.Upper, want)
+		}
+		widths = append(widths, point.Upper-point.Lower)
+	}
+	if widths[1] >= widths[0]/3 {
+		t.Errorf("expected the interval to narrow with 40x the episodes, got widths %v", widths)
+	}
+}
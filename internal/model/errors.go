@@ -0,0 +1,21 @@
+package model
+
+import "errors"
+
+// Sentinel errors for the failure classes callers need to tell apart.
+// Model functions wrap them with context, so test with errors.Is.
+var (
+	// ErrInsufficientData means the bribes cannot support the request, e.g.
+	// fewer slots than τ or no slots at all.
+	ErrInsufficientData = errors.New("insufficient data")
+
+	// ErrInvalidProbability means a probability parameter is outside its
+	// allowed range.
+	ErrInvalidProbability = errors.New("invalid probability")
+
+	// ErrNilValue means a bribe has a nil ValueWei.
+	ErrNilValue = errors.New("nil ValueWei")
+
+	// ErrInvalidTopK means the cartel size k is below 1.
+	ErrInvalidTopK = errors.New("invalid topK")
+)
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// TestSentinelErrors verifies model failures wrap the sentinel for their
+// class so callers can match them with errors.Is.
+func TestSentinelErrors(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(200), BuilderPubkey: "0xB"},
+	}
+
+	_, err := CensorshipCost(bribes, 3)
+	if !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData for tau > slots, got %v", err)
+	}
+	_, _, err = ComputeConcentration(nil, 1, ConcentrationByBlocks)
+	if !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData for no bribes, got %v", err)
+	}
+
+	_, err = CensorshipCost([]SlotBribe{{Slot: 1}}, 1)
+	if !errors.Is(err, ErrNilValue) {
+		t.Errorf("expected ErrNilValue, got %v", err)
+	}
+
+	_, _, err = ComputeConcentration(bribes, 0, ConcentrationByBlocks)
+	if !errors.Is(err, ErrInvalidTopK) {
+		t.Errorf("expected ErrInvalidTopK, got %v", err)
+	}
+
+	_, err = AttackerProfit(bribes, ProfitParams{BridgeTVL: big.NewFloat(1000), SuccessProbability: 1.5, Tau: 2, TopK: 1})
+	if !errors.Is(err, ErrInvalidProbability) {
+		t.Errorf("expected ErrInvalidProbability, got %v", err)
+	}
+
+	// Wrapping by callers keeps the sentinel
+	_, _, err = FindBreakevenTVL(bribes, 0.5, 3, 1)
+	if !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("expected wrapped ErrInsufficientData, got %v", err)
+	}
+}
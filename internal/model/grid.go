@@ -0,0 +1,100 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// ProfitGrid is AttackerProfit over a grid of success probabilities
+// (rows) and bridge TVLs (columns), in big.Float precision throughout.
+// Unlike analysis.ComputeProfitabilityMatrix it keeps each cell's full
+// ProfitResult, so heatmaps and contours are exact.
+type ProfitGrid struct {
+	Probabilities []float64
+	TVLs          []*big.Float
+	Results       [][]ProfitResult // Results[i][j] is at Probabilities[i] and TVLs[j]
+	EffectiveCost *big.Float       // C_c^eff shared by every cell
+}
+
+// ContourPoint is where one row of a ProfitGrid breaks even.
+type ContourPoint struct {
+	SuccessProb     float64
+	Breakeven       *big.Float // V* = C_c^eff / p; nil when p = 0
+	FirstProfitable int        // Column of the first profitable cell, or -1
+}
+
+// SweepProfitGrid evaluates profit for every combination of pSteps
+// probabilities from minP to maxP and tvlSteps TVLs from minTVL to maxTVL,
+// each spaced evenly as in SweepProbability and SweepTVL.
+//
+// The cost side does not depend on p or V, so it is computed once.
+// Cancelling ctx stops the sweep between rows with ctx's error.
+func SweepProfitGrid(ctx context.Context, bribes []SlotBribe, tau uint64, topK int, minP, maxP float64, pSteps int, minTVL, maxTVL *big.Float, tvlSteps int) (*ProfitGrid, error) {
+	if pSteps < 1 || tvlSteps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1, got %d x %d", pSteps, tvlSteps)
+	}
+	if minP < 0 || minP > 1 {
+		return nil, fmt.Errorf("%w: minP must be in [0,1], got %f", ErrInvalidProbability, minP)
+	}
+	if maxP < 0 || maxP > 1 {
+		return nil, fmt.Errorf("%w: maxP must be in [0,1], got %f", ErrInvalidProbability, maxP)
+	}
+	if minP > maxP {
+		return nil, fmt.Errorf("minP (%f) must be <= maxP (%f)", minP, maxP)
+	}
+	tvls, err := tvlPoints(minTVL, maxTVL, tvlSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	params := ProfitParams{Tau: tau, TopK: topK}
+	cost, err := computeAttackCost(bribes, params)
+	if err != nil {
+		return nil, err
+	}
+
+	grid := &ProfitGrid{
+		Probabilities: make([]float64, pSteps),
+		TVLs:          tvls,
+		Results:       make([][]ProfitResult, pSteps),
+		EffectiveCost: new(big.Float).Set(cost.effective),
+	}
+	stepSize := 0.0
+	if pSteps > 1 {
+		stepSize = (maxP - minP) / float64(pSteps-1)
+	}
+	for i := range grid.Results {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p := minP + float64(i)*stepSize
+		grid.Probabilities[i] = p
+		grid.Results[i] = make([]ProfitResult, len(tvls))
+		for j, tvl := range tvls {
+			grid.Results[i][j] = *profitFromCost(tvl, p, params, cost)
+		}
+	}
+
+	return grid, nil
+}
+
+// Contour extracts the breakeven threshold of each row: the exact V* and
+// the first sampled TVL above it.
+func (g *ProfitGrid) Contour() []ContourPoint {
+	contour := make([]ContourPoint, len(g.Probabilities))
+	for i, p := range g.Probabilities {
+		point := ContourPoint{SuccessProb: p, FirstProfitable: -1}
+		if p > 0 {
+			point.Breakeven = new(big.Float).Quo(g.EffectiveCost, big.NewFloat(p))
+		}
+		for j, result := range g.Results[i] {
+			if result.Profit.Sign() > 0 {
+				point.FirstProfitable = j
+				break
+			}
+		}
+		contour[i] = point
+	}
+	return contour
+}
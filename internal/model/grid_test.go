@@ -0,0 +1,52 @@
+package model
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+// TestSweepProfitGrid verifies every cell matches AttackerProfit and the
+// contour follows V* = C_c^eff / p.
+func TestSweepProfitGrid(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1000), BuilderPubkey: "0xB"},
+	}
+
+	// C_c^eff = 1000; p ∈ {0, 0.5, 1}, V ∈ {0, 1000, 2000, 3000}
+	grid, err := SweepProfitGrid(context.Background(), bribes, 2, 1, 0, 1, 3, big.NewFloat(0), big.NewFloat(3000), 4)
+	if err != nil {
+		t.Fatalf("SweepProfitGrid failed: %v", err)
+	}
+	if len(grid.Results) != 3 || len(grid.Results[0]) != 4 {
+		t.Fatalf("expected a 3x4 grid, got %dx%d", len(grid.Results), len(grid.Results[0]))
+	}
+
+	for i, p := range grid.Probabilities {
+		for j, tvl := range grid.TVLs {
+			expected, err := AttackerProfit(bribes, ProfitParams{BridgeTVL: tvl, SuccessProbability: p, Tau: 2, TopK: 1})
+			if err != nil {
+				t.Fatalf("AttackerProfit failed: %v", err)
+			}
+			if grid.Results[i][j].Profit.Cmp(expected.Profit) != 0 {
+				t.Errorf("cell (%d,%d): expected profit %s, got %s", i, j, expected.Profit.String(), grid.Results[i][j].Profit.String())
+			}
+		}
+	}
+
+	contour := grid.Contour()
+	if contour[0].Breakeven != nil || contour[0].FirstProfitable != -1 {
+		t.Errorf("expected no breakeven at p=0, got %+v", contour[0])
+	}
+	if !floatEqual(contour[1].Breakeven, big.NewFloat(2000), 0.01) || contour[1].FirstProfitable != 3 {
+		t.Errorf("expected breakeven 2000 first profitable at column 3 for p=0.5, got %+v", contour[1])
+	}
+	if !floatEqual(contour[2].Breakeven, big.NewFloat(1000), 0.01) || contour[2].FirstProfitable != 2 {
+		t.Errorf("expected breakeven 1000 first profitable at column 2 for p=1, got %+v", contour[2])
+	}
+
+	if _, err := SweepProfitGrid(context.Background(), bribes, 2, 1, 0.9, 0.1, 3, big.NewFloat(0), big.NewFloat(3000), 4); err == nil {
+		t.Error("Expected error for minP > maxP, got nil")
+	}
+}
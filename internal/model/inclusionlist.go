@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// InclusionListScenario describes partial deployment of enforceable
+// inclusion lists (FOCIL, EIP-7547). On an enforced slot the builder must
+// include the listed transactions, so the cartel's own blocks no longer
+// censor for free: the slot can only be censored by having it missed,
+// which costs at least the proposer's forgone bribe. Enforced slots are
+// assumed to be spread evenly over the window, so the bribe term of
+// C_c^eff becomes
+//
+//	C_c · ((1 - f) · (1 - α) + f · m)
+//
+// with f the EnforcedFraction and m the EnforcedSlotMultiplier. f = 1
+// removes the concentration discount entirely.
+type InclusionListScenario struct {
+	EnforcedFraction       float64 // f: share of slots with an enforceable inclusion list, in [0, 1]
+	EnforcedSlotMultiplier float64 // m: cost of censoring an enforced slot as a multiple of its bribe; 0 means 1
+}
+
+// validate rejects fractions outside [0, 1] and multipliers below 1.
+func (s *InclusionListScenario) validate() error {
+	if s == nil {
+		return nil
+	}
+	if s.EnforcedFraction < 0 || s.EnforcedFraction > 1 {
+		return fmt.Errorf("invalid enforced fraction: %f (must be in [0,1])", s.EnforcedFraction)
+	}
+	if s.EnforcedSlotMultiplier != 0 && s.EnforcedSlotMultiplier < 1 {
+		return fmt.Errorf("invalid enforced slot multiplier: %f (must be at least 1)", s.EnforcedSlotMultiplier)
+	}
+	return nil
+}
+
+// costFactor is the share of C_c the attacker pays under concentration
+// alpha: 1 - α without inclusion lists.
+func (s *InclusionListScenario) costFactor(alpha float64) float64 {
+	if s == nil {
+		return 1 - alpha
+	}
+	m := s.EnforcedSlotMultiplier
+	if m == 0 {
+		m = 1
+	}
+	return (1-s.EnforcedFraction)*(1-alpha) + s.EnforcedFraction*m
+}
+
+// FindBreakevenTVLWithInclusionLists is FindBreakevenTVL under an
+// inclusion-list scenario:
+//
+//	V* = C_c · ((1 - f) · (1 - α) + f · m) / p
+//
+// Comparing it with the nil-scenario result quantifies how much inclusion
+// lists raise the breakeven TVL.
+func FindBreakevenTVLWithInclusionLists(bribes []SlotBribe, successProb float64, tau uint64, topK int, scenario *InclusionListScenario) (*big.Float, float64, error) {
+	if successProb <= 0 || successProb > 1 {
+		return nil, 0, fmt.Errorf("%w: success probability must be in (0,1], got %f", ErrInvalidProbability, successProb)
+	}
+	if err := scenario.validate(); err != nil {
+		return nil, 0, err
+	}
+
+	cost, alpha, err := bribeCost(bribes, tau, topK, 0, scenario)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cost.Quo(cost, big.NewFloat(successProb)), alpha, nil
+}
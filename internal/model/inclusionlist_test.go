@@ -0,0 +1,73 @@
+package model
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestFindBreakevenTVLWithInclusionLists verifies enforced slots raise the
+// breakeven TVL by removing the concentration discount on those slots.
+func TestFindBreakevenTVLWithInclusionLists(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(3000), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(2000), BuilderPubkey: "0xA"},
+		{Slot: 4, ValueWei: big.NewInt(2000), BuilderPubkey: "0xB"},
+	}
+
+	// C_c = 8000, α = 0.75, p = 0.5
+	cases := []struct {
+		name     string
+		scenario *InclusionListScenario
+		expected float64
+	}{
+		{"none", nil, 4000}, // 8000 · 0.25 / 0.5
+		{"zero fraction", &InclusionListScenario{}, 4000},              // unchanged
+		{"half", &InclusionListScenario{EnforcedFraction: 0.5}, 10000}, // 8000 · (0.125 + 0.5) / 0.5
+		{"full", &InclusionListScenario{EnforcedFraction: 1}, 16000},   // 8000 / 0.5
+		{"full, doubled", &InclusionListScenario{EnforcedFraction: 1, EnforcedSlotMultiplier: 2}, 32000},
+	}
+	for _, tc := range cases {
+		breakeven, alpha, err := FindBreakevenTVLWithInclusionLists(bribes, 0.5, 4, 1, tc.scenario)
+		if err != nil {
+			t.Fatalf("%s: FindBreakevenTVLWithInclusionLists failed: %v", tc.name, err)
+		}
+		if alpha != 0.75 {
+			t.Errorf("%s: expected alpha 0.75, got %f", tc.name, alpha)
+		}
+		if !floatEqual(breakeven, big.NewFloat(tc.expected), 0.01) {
+			t.Errorf("%s: expected breakeven %f, got %s", tc.name, tc.expected, breakeven.String())
+		}
+	}
+
+	// AttackerProfit prices the same scenario
+	result, err := AttackerProfit(bribes, ProfitParams{
+		BridgeTVL:          big.NewFloat(10000),
+		SuccessProbability: 0.5,
+		Tau:                4,
+		TopK:               1,
+		InclusionLists:     &InclusionListScenario{EnforcedFraction: 0.5},
+	})
+	if err != nil {
+		t.Fatalf("AttackerProfit failed: %v", err)
+	}
+	if !floatEqual(result.EffectiveCost, big.NewFloat(5000), 0.01) || !floatEqual(result.DiscountedCost, big.NewFloat(5000), 0.01) {
+		t.Errorf("expected cost 5000, got %s (discounted %s)", result.EffectiveCost.String(), result.DiscountedCost.String())
+	}
+}
+
+// TestInclusionListScenario_Validation verifies invalid scenarios are
+// rejected.
+func TestInclusionListScenario_Validation(t *testing.T) {
+	bribes := []SlotBribe{{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"}}
+
+	for _, scenario := range []*InclusionListScenario{
+		{EnforcedFraction: -0.1},
+		{EnforcedFraction: 1.5},
+		{EnforcedFraction: 0.5, EnforcedSlotMultiplier: 0.5},
+	} {
+		if _, _, err := FindBreakevenTVLWithInclusionLists(bribes, 0.5, 1, 1, scenario); err == nil {
+			t.Errorf("Expected error for %+v, got nil", *scenario)
+		}
+	}
+}
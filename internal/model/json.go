@@ -0,0 +1,188 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// decimalInt is a *big.Int that marshals as an exact decimal string, or
+// null when nil. It also unmarshals bare JSON numbers, the encoding/json
+// default for *big.Int.
+type decimalInt struct{ *big.Int }
+
+func (d decimalInt) MarshalJSON() ([]byte, error) {
+	if d.Int == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.Int.String())
+}
+
+func (d *decimalInt) UnmarshalJSON(data []byte) error {
+	s, ok, err := jsonDecimal(data)
+	if err != nil || !ok {
+		return err
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("invalid integer %q", s)
+	}
+	d.Int = v
+	return nil
+}
+
+// decimalFloat is a *big.Float that marshals as its ExactDecimal string,
+// or null when nil. Finite big.Floats are binary fractions, so the
+// expansion terminates.
+type decimalFloat struct{ *big.Float }
+
+func (d decimalFloat) MarshalJSON() ([]byte, error) {
+	if d.Float == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(ExactDecimal(d.Float))
+}
+
+func (d *decimalFloat) UnmarshalJSON(data []byte) error {
+	s, ok, err := jsonDecimal(data)
+	if err != nil || !ok {
+		return err
+	}
+	switch s {
+	case "+Inf", "Inf":
+		d.Float = new(big.Float).SetInf(false)
+		return nil
+	case "-Inf":
+		d.Float = new(big.Float).SetInf(true)
+		return nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return fmt.Errorf("invalid decimal %q", s)
+	}
+	// SetRat keeps enough precision to hold a binary fraction exactly
+	d.Float = new(big.Float).SetRat(r)
+	return nil
+}
+
+// ExactDecimal formats f as a decimal string holding its exact value, with
+// no exponent and no trailing rounding; infinities format as "+Inf" and
+// "-Inf".
+func ExactDecimal(f *big.Float) string {
+	if f.IsInf() {
+		return f.String()
+	}
+	r, _ := f.Rat(nil)
+	// The denominator is 2^n, which needs exactly n decimal places
+	return r.FloatString(r.Denom().BitLen() - 1)
+}
+
+// jsonDecimal returns the text of a JSON string or number, and false for
+// null.
+func jsonDecimal(data []byte) (string, bool, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", false, nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", false, err
+		}
+		return s, true, nil
+	}
+	return string(data), true, nil
+}
+
+// profitResultJSON overrides ProfitResult's big.Float fields with their
+// exact decimal encodings.
+type profitResultJSON struct {
+	plainProfitResult
+	ExpectedRevenue   decimalFloat
+	EffectiveCost     decimalFloat
+	Profit            decimalFloat
+	TVL               decimalFloat
+	CoordinationCost  decimalFloat
+	ExpectedPenalty   decimalFloat
+	ForegoneMEV       decimalFloat
+	DiscountedRevenue decimalFloat
+	DiscountedCost    decimalFloat
+	DiscountedProfit  decimalFloat
+}
+
+// plainProfitResult has ProfitResult's fields without its methods, so
+// encoding it does not recurse.
+type plainProfitResult ProfitResult
+
+// MarshalJSON encodes the result with big.Float values as exact decimal
+// strings rather than lossy numbers.
+func (r ProfitResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(profitResultJSON{
+		plainProfitResult: plainProfitResult(r),
+		ExpectedRevenue:   decimalFloat{r.ExpectedRevenue},
+		EffectiveCost:     decimalFloat{r.EffectiveCost},
+		Profit:            decimalFloat{r.Profit},
+		TVL:               decimalFloat{r.TVL},
+		CoordinationCost:  decimalFloat{r.CoordinationCost},
+		ExpectedPenalty:   decimalFloat{r.ExpectedPenalty},
+		ForegoneMEV:       decimalFloat{r.ForegoneMEV},
+		DiscountedRevenue: decimalFloat{r.DiscountedRevenue},
+		DiscountedCost:    decimalFloat{r.DiscountedCost},
+		DiscountedProfit:  decimalFloat{r.DiscountedProfit},
+	})
+}
+
+// UnmarshalJSON decodes the encoding of MarshalJSON.
+func (r *ProfitResult) UnmarshalJSON(data []byte) error {
+	var v profitResultJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = ProfitResult(v.plainProfitResult)
+	r.ExpectedRevenue = v.ExpectedRevenue.Float
+	r.EffectiveCost = v.EffectiveCost.Float
+	r.Profit = v.Profit.Float
+	r.TVL = v.TVL.Float
+	r.CoordinationCost = v.CoordinationCost.Float
+	r.ExpectedPenalty = v.ExpectedPenalty.Float
+	r.ForegoneMEV = v.ForegoneMEV.Float
+	r.DiscountedRevenue = v.DiscountedRevenue.Float
+	r.DiscountedCost = v.DiscountedCost.Float
+	r.DiscountedProfit = v.DiscountedProfit.Float
+	return nil
+}
+
+// builderStatsJSON overrides BuilderStats' wei fields with their decimal
+// encodings.
+type builderStatsJSON struct {
+	plainBuilderStats
+	TotalValueWei decimalInt
+	MeanBidWei    decimalInt
+}
+
+// plainBuilderStats has BuilderStats' fields without its methods.
+type plainBuilderStats BuilderStats
+
+// MarshalJSON encodes the stats with wei values as decimal strings, which
+// JavaScript and other float64 JSON readers cannot round.
+func (s BuilderStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(builderStatsJSON{
+		plainBuilderStats: plainBuilderStats(s),
+		TotalValueWei:     decimalInt{s.TotalValueWei},
+		MeanBidWei:        decimalInt{s.MeanBidWei},
+	})
+}
+
+// UnmarshalJSON decodes the encoding of MarshalJSON, and also wei values
+// written as bare numbers.
+func (s *BuilderStats) UnmarshalJSON(data []byte) error {
+	var v builderStatsJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = BuilderStats(v.plainBuilderStats)
+	s.TotalValueWei = v.TotalValueWei.Int
+	s.MeanBidWei = v.MeanBidWei.Int
+	return nil
+}
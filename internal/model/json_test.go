@@ -0,0 +1,107 @@
+package model
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// TestProfitResultJSON verifies big.Float fields encode as exact decimal
+// strings and decode to the same values.
+func TestProfitResultJSON(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(3000), BuilderPubkey: "0xB"},
+		{Slot: 3, ValueWei: big.NewInt(2000), BuilderPubkey: "0xB"},
+	}
+	// A TVL beyond float64's exact integers
+	tvl, _ := new(big.Float).SetPrec(128).SetString("123456789012345678901234567.5")
+	result, err := AttackerProfit(bribes, ProfitParams{BridgeTVL: tvl, SuccessProbability: 0.3, Tau: 3, TopK: 1})
+	if err != nil {
+		t.Fatalf("AttackerProfit failed: %v", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"TVL":"123456789012345678901234567.5"`) {
+		t.Errorf("expected exact decimal TVL, got %s", data)
+	}
+
+	var decoded ProfitResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	fields := map[string][2]*big.Float{
+		"TVL":             {result.TVL, decoded.TVL},
+		"ExpectedRevenue": {result.ExpectedRevenue, decoded.ExpectedRevenue},
+		"EffectiveCost":   {result.EffectiveCost, decoded.EffectiveCost},
+		"Profit":          {result.Profit, decoded.Profit},
+		"DiscountedCost":  {result.DiscountedCost, decoded.DiscountedCost},
+	}
+	for name, pair := range fields {
+		if pair[0].Cmp(pair[1]) != 0 {
+			t.Errorf("%s: expected %s after round trip, got %s", name, pair[0].Text('g', 40), pair[1].Text('g', 40))
+		}
+	}
+	if decoded.Alpha != result.Alpha || decoded.SuccessProb != result.SuccessProb {
+		t.Errorf("expected alpha %f and p %f, got %f and %f", result.Alpha, result.SuccessProb, decoded.Alpha, decoded.SuccessProb)
+	}
+
+	// Sweeps serialize through their results
+	sweep := ProfitSweepResult{Results: []ProfitResult{*result}, MinP: 0.3, MaxP: 0.3, Steps: 1}
+	data, err = json.Marshal(sweep)
+	if err != nil {
+		t.Fatalf("Marshal sweep failed: %v", err)
+	}
+	var decodedSweep ProfitSweepResult
+	if err := json.Unmarshal(data, &decodedSweep); err != nil {
+		t.Fatalf("Unmarshal sweep failed: %v", err)
+	}
+	if decodedSweep.Results[0].Profit.Cmp(result.Profit) != 0 {
+		t.Errorf("expected sweep profit %s, got %s", result.Profit.String(), decodedSweep.Results[0].Profit.String())
+	}
+
+	// Infinities and nil fields survive too
+	inf := ProfitResult{Profit: new(big.Float).SetInf(true)}
+	data, _ = json.Marshal(inf)
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !decoded.Profit.IsInf() || decoded.Profit.Sign() >= 0 || decoded.TVL != nil {
+		t.Errorf("expected -Inf profit and nil TVL, got %v and %v", decoded.Profit, decoded.TVL)
+	}
+}
+
+// TestBuilderStatsJSON verifies wei fields encode as decimal strings and
+// that numeric encodings still decode.
+func TestBuilderStatsJSON(t *testing.T) {
+	total, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	stats := BuilderStats{BuilderPubkey: "0xA", BlockCount: 3, TotalValueWei: total, MeanBidWei: big.NewInt(7), ValueShare: 0.5}
+
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"TotalValueWei":"123456789012345678901234567890"`) {
+		t.Errorf("expected TotalValueWei as a string, got %s", data)
+	}
+
+	var decoded BuilderStats
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.TotalValueWei.Cmp(total) != 0 || decoded.MeanBidWei.Int64() != 7 || decoded.BlockCount != 3 || decoded.BuilderPubkey != "0xA" {
+		t.Errorf("expected %+v after round trip, got %+v", stats, decoded)
+	}
+
+	var legacy BuilderStats
+	if err := json.Unmarshal([]byte(`{"BuilderPubkey":"0xB","TotalValueWei":42,"MeanBidWei":null}`), &legacy); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if legacy.TotalValueWei.Int64() != 42 || legacy.MeanBidWei != nil {
+		t.Errorf("expected TotalValueWei 42 and nil MeanBidWei, got %v and %v", legacy.TotalValueWei, legacy.MeanBidWei)
+	}
+}
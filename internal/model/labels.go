@@ -0,0 +1,172 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BuilderRegistry maps builder pubkeys to the real-world entities operating them.
+//
+// Many builders run several pubkeys (e.g. beaverbuild, Titan). Counting each
+// key separately understates concentration, so analyses can resolve keys to
+// entities through this registry. Safe for concurrent use.
+type BuilderRegistry struct {
+	mu     sync.RWMutex
+	labels map[string]string // normalized pubkey -> entity name
+}
+
+// BuilderRegistryFile is the on-disk (and over-the-wire) registry format:
+//
+//	{"entities": {"beaverbuild": ["0xabc...", "0xdef..."], "titan": ["0x123..."]}}
+type BuilderRegistryFile struct {
+	Entities map[string][]string `json:"entities"`
+}
+
+// NewBuilderRegistry creates a registry from a pubkey → entity map.
+func NewBuilderRegistry(labels map[string]string) *BuilderRegistry {
+	r := &BuilderRegistry{labels: make(map[string]string, len(labels))}
+	for pubkey, entity := range labels {
+		r.labels[normalizePubkey(pubkey)] = entity
+	}
+	return r
+}
+
+// ParseBuilderRegistry decodes a registry in BuilderRegistryFile format.
+//
+// Fails if a pubkey is assigned to more than one entity.
+func ParseBuilderRegistry(data []byte) (*BuilderRegistry, error) {
+	var file BuilderRegistryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse builder registry: %w", err)
+	}
+
+	labels := make(map[string]string)
+	for entity, pubkeys := range file.Entities {
+		if entity == "" {
+			return nil, fmt.Errorf("builder registry contains an empty entity name")
+		}
+		for _, pubkey := range pubkeys {
+			key := normalizePubkey(pubkey)
+			if existing, ok := labels[key]; ok && existing != entity {
+				return nil, fmt.Errorf("pubkey %s assigned to both %s and %s", pubkey, existing, entity)
+			}
+			labels[key] = entity
+		}
+	}
+
+	return &BuilderRegistry{labels: labels}, nil
+}
+
+// LoadBuilderRegistry reads a registry file in BuilderRegistryFile format.
+func LoadBuilderRegistry(path string) (*BuilderRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read builder registry %s: %w", path, err)
+	}
+	return ParseBuilderRegistry(data)
+}
+
+// Label returns the entity operating pubkey, if known.
+func (r *BuilderRegistry) Label(pubkey string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entity, ok := r.labels[normalizePubkey(pubkey)]
+	return entity, ok
+}
+
+// Entity returns the entity operating pubkey, or pubkey itself when unlabeled.
+func (r *BuilderRegistry) Entity(pubkey string) string {
+	if entity, ok := r.Label(pubkey); ok {
+		return entity
+	}
+	return pubkey
+}
+
+// Len returns the number of labeled pubkeys.
+func (r *BuilderRegistry) Len() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.labels)
+}
+
+// Labels returns a copy of the normalized pubkey → entity map.
+func (r *BuilderRegistry) Labels() map[string]string {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	labels := make(map[string]string, len(r.labels))
+	for pubkey, entity := range r.labels {
+		labels[pubkey] = entity
+	}
+	return labels
+}
+
+// Entities returns the sorted list of distinct entity names.
+func (r *BuilderRegistry) Entities() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]struct{})
+	for _, entity := range r.labels {
+		seen[entity] = struct{}{}
+	}
+	entities := make([]string, 0, len(seen))
+	for entity := range seen {
+		entities = append(entities, entity)
+	}
+	sort.Strings(entities)
+	return entities
+}
+
+// Replace atomically swaps the registry contents with those of other.
+// Used to hot-reload labels without disturbing concurrent readers.
+func (r *BuilderRegistry) Replace(other *BuilderRegistry) {
+	other.mu.RLock()
+	labels := make(map[string]string, len(other.labels))
+	for k, v := range other.labels {
+		labels[k] = v
+	}
+	other.mu.RUnlock()
+
+	r.mu.Lock()
+	r.labels = labels
+	r.mu.Unlock()
+}
+
+// LabelBribes sets BuilderEntity on every bribe whose builder is labeled.
+// Bribes from unlabeled builders are left with an empty BuilderEntity.
+func (r *BuilderRegistry) LabelBribes(bribes []SlotBribe) {
+	for i := range bribes {
+		bribes[i].BuilderEntity, _ = r.Label(bribes[i].BuilderPubkey)
+	}
+}
+
+// LabelStats sets Entity on every builder stat whose pubkey is labeled.
+func (r *BuilderRegistry) LabelStats(stats []BuilderStats) {
+	for i := range stats {
+		if entity, ok := r.Label(stats[i].BuilderPubkey); ok {
+			stats[i].Entity = entity
+		}
+	}
+}
+
+// normalizePubkey makes pubkey lookups insensitive to hex case.
+func normalizePubkey(pubkey string) string {
+	return strings.ToLower(strings.TrimSpace(pubkey))
+}
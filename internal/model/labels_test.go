@@ -0,0 +1,200 @@
+package model
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testRegistryJSON = `{
+	"entities": {
+		"beaverbuild": ["0xBEAVER1", "0xbeaver2"],
+		"titan": ["0xtitan1"]
+	}
+}`
+
+// TestParseBuilderRegistry verifies entity lookups and case-insensitive pubkeys.
+func TestParseBuilderRegistry(t *testing.T) {
+	reg, err := ParseBuilderRegistry([]byte(testRegistryJSON))
+	if err != nil {
+		t.Fatalf("ParseBuilderRegistry failed: %v", err)
+	}
+
+	if reg.Len() != 3 {
+		t.Errorf("expected 3 labeled pubkeys, got %d", reg.Len())
+	}
+	if got := reg.Entity("0xbeaver1"); got != "beaverbuild" {
+		t.Errorf("expected beaverbuild, got %s", got)
+	}
+	if got := reg.Entity("0xunknown"); got != "0xunknown" {
+		t.Errorf("expected unlabeled pubkey to map to itself, got %s", got)
+	}
+	if _, ok := reg.Label("0xunknown"); ok {
+		t.Error("expected no label for unknown pubkey")
+	}
+
+	entities := reg.Entities()
+	if len(entities) != 2 || entities[0] != "beaverbuild" || entities[1] != "titan" {
+		t.Errorf("expected [beaverbuild titan], got %v", entities)
+	}
+}
+
+// TestParseBuilderRegistry_Conflict verifies a pubkey cannot belong to two entities.
+func TestParseBuilderRegistry_Conflict(t *testing.T) {
+	data := `{"entities": {"a": ["0x1"], "b": ["0x1"]}}`
+	if _, err := ParseBuilderRegistry([]byte(data)); err == nil {
+		t.Error("Expected error for conflicting labels, got nil")
+	}
+}
+
+// TestLoadBuilderRegistry verifies loading from a file.
+func TestLoadBuilderRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.json")
+	if err := os.WriteFile(path, []byte(testRegistryJSON), 0644); err != nil {
+		t.Fatalf("Failed to write registry: %v", err)
+	}
+
+	reg, err := LoadBuilderRegistry(path)
+	if err != nil {
+		t.Fatalf("LoadBuilderRegistry failed: %v", err)
+	}
+	if reg.Entity("0xtitan1") != "titan" {
+		t.Errorf("expected titan, got %s", reg.Entity("0xtitan1"))
+	}
+}
+
+// TestBuilderRegistry_Nil verifies a nil registry behaves as empty.
+func TestBuilderRegistry_Nil(t *testing.T) {
+	var reg *BuilderRegistry
+	if reg.Entity("0xA") != "0xA" || reg.Len() != 0 {
+		t.Error("expected nil registry to label nothing")
+	}
+
+	bribes := []SlotBribe{{Slot: 1, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"}}
+	reg.LabelBribes(bribes)
+	if bribes[0].BuilderEntity != "" {
+		t.Errorf("expected empty entity, got %s", bribes[0].BuilderEntity)
+	}
+}
+
+// TestComputeEntityConcentration verifies multi-key operators are counted once.
+func TestComputeEntityConcentration(t *testing.T) {
+	reg, _ := ParseBuilderRegistry([]byte(testRegistryJSON))
+
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1), BuilderPubkey: "0xbeaver1"},
+		{Slot: 2, ValueWei: big.NewInt(1), BuilderPubkey: "0xbeaver2"},
+		{Slot: 3, ValueWei: big.NewInt(1), BuilderPubkey: "0xtitan1"},
+		{Slot: 4, ValueWei: big.NewInt(1), BuilderPubkey: "0xother1"},
+		{Slot: 5, ValueWei: big.NewInt(1), BuilderPubkey: "0xother1"},
+		{Slot: 6, ValueWei: big.NewInt(1), BuilderPubkey: "0xother2"},
+	}
+
+	// By pubkey: 0xother1 leads with 2/6
+	alphaKey, _, err := ComputeBuilderConcentration(bribes, 1)
+	if err != nil {
+		t.Fatalf("ComputeBuilderConcentration failed: %v", err)
+	}
+
+	reg.LabelBribes(bribes)
+
+	// By entity: beaverbuild (2) ties 0xother1 (2); α(top1) is still 2/6,
+	// but α(top2) rises from 3/6 to 4/6 once beaver keys merge
+	alphaKey2, _, _ := ComputeBuilderConcentration(bribes, 2)
+	alphaEntity2, stats, err := ComputeEntityConcentration(bribes, 2)
+	if err != nil {
+		t.Fatalf("ComputeEntityConcentration failed: %v", err)
+	}
+
+	if alphaKey != 2.0/6.0 {
+		t.Errorf("expected pubkey α(top1)=%f, got %f", 2.0/6.0, alphaKey)
+	}
+	if alphaKey2 != 3.0/6.0 {
+		t.Errorf("expected pubkey α(top2)=%f, got %f", 3.0/6.0, alphaKey2)
+	}
+	if alphaEntity2 != 4.0/6.0 {
+		t.Errorf("expected entity α(top2)=%f, got %f", 4.0/6.0, alphaEntity2)
+	}
+	if len(stats) != 4 {
+		t.Errorf("expected 4 entities, got %d", len(stats))
+	}
+
+	for _, s := range stats {
+		if s.BuilderPubkey == "beaverbuild" && (s.Entity != "beaverbuild" || s.BlockCount != 2) {
+			t.Errorf("unexpected beaverbuild stats: %+v", s)
+		}
+		if s.BuilderPubkey == "0xother2" && s.Entity != "" {
+			t.Errorf("expected unlabeled builder to have empty entity, got %s", s.Entity)
+		}
+	}
+}
+
+// TestComputeConcentrationByEntity verifies pubkeys are resolved through
+// the registry without labeling the bribes, under both metrics.
+func TestComputeConcentrationByEntity(t *testing.T) {
+	reg, _ := ParseBuilderRegistry([]byte(testRegistryJSON))
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(10), BuilderPubkey: "0xbeaver1"},
+		{Slot: 2, ValueWei: big.NewInt(10), BuilderPubkey: "0xbeaver2"},
+		{Slot: 3, ValueWei: big.NewInt(50), BuilderPubkey: "0xtitan1"},
+		{Slot: 4, ValueWei: big.NewInt(20), BuilderPubkey: "0xother1", BuilderEntity: "rsync"},
+		{Slot: 5, ValueWei: big.NewInt(10), BuilderPubkey: "0xother2"},
+	}
+
+	alpha, stats, err := ComputeConcentrationByEntity(bribes, 1, ConcentrationByBlocks, reg)
+	if err != nil {
+		t.Fatalf("ComputeConcentrationByEntity failed: %v", err)
+	}
+	if alpha != 2.0/5.0 || stats[0].BuilderPubkey != "beaverbuild" || stats[0].Entity != "beaverbuild" {
+		t.Errorf("expected beaverbuild to lead with α 0.4, got %f and %+v", alpha, stats[0])
+	}
+	if bribes[0].BuilderEntity != "" {
+		t.Errorf("expected bribes to be left unlabeled, got %s", bribes[0].BuilderEntity)
+	}
+
+	alpha, stats, err = ComputeConcentrationByEntity(bribes, 1, ConcentrationByValue, reg)
+	if err != nil || alpha != 0.5 || stats[0].BuilderPubkey != "titan" {
+		t.Errorf("expected titan to lead by value with α 0.5, got %f and %+v (err %v)", alpha, stats, err)
+	}
+
+	if got := GetEntityDiversity(bribes, reg); got != 4 {
+		t.Errorf("expected 4 entities, got %d", got)
+	}
+	if got := GetEntityDiversity(bribes, nil); got != 5 {
+		t.Errorf("expected 5 entities without a registry, got %d", got)
+	}
+	if _, _, err := ComputeConcentrationByEntity(bribes, 1, "bogus", reg); err == nil {
+		t.Error("Expected error for unknown metric, got nil")
+	}
+}
+
+// TestBuilderRegistry_LabelStats verifies stats annotation.
+func TestBuilderRegistry_LabelStats(t *testing.T) {
+	reg, _ := ParseBuilderRegistry([]byte(testRegistryJSON))
+	stats := []BuilderStats{{BuilderPubkey: "0xtitan1", BlockCount: 3}, {BuilderPubkey: "0xnope", BlockCount: 1}}
+
+	reg.LabelStats(stats)
+
+	if stats[0].Entity != "titan" {
+		t.Errorf("expected titan, got %s", stats[0].Entity)
+	}
+	if stats[1].Entity != "" {
+		t.Errorf("expected empty entity, got %s", stats[1].Entity)
+	}
+}
+
+// TestBuilderRegistry_Labels verifies the exported map is a normalized copy.
+func TestBuilderRegistry_Labels(t *testing.T) {
+	reg := NewBuilderRegistry(map[string]string{"0xABC": "titan"})
+
+	labels := reg.Labels()
+	if labels["0xabc"] != "titan" {
+		t.Fatalf("expected normalized pubkey, got %v", labels)
+	}
+
+	labels["0xdef"] = "other"
+	if reg.Len() != 1 {
+		t.Errorf("expected registry unaffected by edits to the copy, got %d labels", reg.Len())
+	}
+}
@@ -0,0 +1,172 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Market share period lengths on mainnet.
+const (
+	SlotsPerEpoch = 32
+	SlotsPerDay   = 7200
+)
+
+// MarketSharePeriod is the builder market over one period of slots.
+type MarketSharePeriod struct {
+	StartSlot uint64             // First slot of the period, a multiple of its length
+	EndSlot   uint64             // Last slot of the period
+	Blocks    uint64             // Blocks won in the period
+	Shares    map[string]float64 // Each active builder's share of the period's blocks
+	HHI       float64            // Herfindahl-Hirschman index of Shares
+	Entered   []string           // Builders first seen in this period, sorted
+	Exited    []string           // Builders last seen in the previous period, sorted
+	Turnover  float64            // ½ Σ |share change| since the previous period
+}
+
+// ChurnRate returns the builders entering and exiting relative to the
+// builders active in the period.
+func (p MarketSharePeriod) ChurnRate() float64 {
+	if len(p.Shares) == 0 {
+		return 0
+	}
+	return float64(len(p.Entered)+len(p.Exited)) / float64(len(p.Shares))
+}
+
+// MarketShareSeries is the builder market share over consecutive periods.
+type MarketShareSeries struct {
+	PeriodSlots  uint64
+	Periods      []MarketSharePeriod // Periods with at least one block, in order
+	Entries      int                 // Builders entering after the first period
+	Exits        int                 // Builders exiting before the last period
+	ChurnRate    float64             // Mean ChurnRate of the periods after the first
+	MeanTurnover float64             // Mean Turnover of the periods after the first
+	HHITrend     float64             // Least-squares slope of HHI per period
+}
+
+// ComputeMarketShareSeries splits bribes into periods of periodSlots slots
+// (e.g. SlotsPerEpoch or SlotsPerDay), aligned to slot 0, and computes each
+// builder's share of every period's blocks. With a registry, builders are
+// grouped by labeled entity as in ComputeConcentrationByEntity.
+//
+// A builder enters in the first period it wins a block and exits in the
+// period after the last one; builders of the first period are incumbents,
+// not entries, and builders of the last period have not exited. Entries
+// and exits are only as good as the data's span: a builder winning a block
+// every few periods enters and exits once, but one that is active before
+// and after the data is neither. A rising HHITrend means the market is
+// centralizing. Periods without blocks are skipped.
+func ComputeMarketShareSeries(bribes []SlotBribe, periodSlots uint64, registry *BuilderRegistry) (*MarketShareSeries, error) {
+	if periodSlots < 1 {
+		return nil, fmt.Errorf("period must be at least 1 slot, got %d", periodSlots)
+	}
+	if len(bribes) == 0 {
+		return nil, fmt.Errorf("%w: empty bribes slice", ErrInsufficientData)
+	}
+	groupKey := func(bribe SlotBribe) (string, string) { return bribe.BuilderPubkey, "" }
+	if registry != nil {
+		groupKey = entityGroupKey(registry)
+	}
+
+	// Block counts per period and builder
+	counts := make(map[uint64]map[string]uint64)
+	for _, bribe := range bribes {
+		key, _ := groupKey(bribe)
+		if key == "" {
+			key = "unknown"
+		}
+		period := bribe.Slot / periodSlots
+		if counts[period] == nil {
+			counts[period] = make(map[string]uint64)
+		}
+		counts[period][key]++
+	}
+	indices := make([]uint64, 0, len(counts))
+	for period := range counts {
+		indices = append(indices, period)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	// Each builder's first and last period, by position in the series
+	first := make(map[string]int)
+	last := make(map[string]int)
+	for i, period := range indices {
+		for builder := range counts[period] {
+			if _, ok := first[builder]; !ok {
+				first[builder] = i
+			}
+			last[builder] = i
+		}
+	}
+
+	series := &MarketShareSeries{PeriodSlots: periodSlots, Periods: make([]MarketSharePeriod, len(indices))}
+	for i, period := range indices {
+		p := MarketSharePeriod{
+			StartSlot: period * periodSlots,
+			EndSlot:   period*periodSlots + periodSlots - 1,
+			Shares:    make(map[string]float64, len(counts[period])),
+		}
+		for _, count := range counts[period] {
+			p.Blocks += count
+		}
+		for builder, count := range counts[period] {
+			share := float64(count) / float64(p.Blocks)
+			p.Shares[builder] = share
+			p.HHI += share * share
+			if i > 0 && first[builder] == i {
+				p.Entered = append(p.Entered, builder)
+			}
+		}
+		if i > 0 {
+			prev := series.Periods[i-1].Shares
+			for builder, share := range prev {
+				if last[builder] == i-1 {
+					p.Exited = append(p.Exited, builder)
+				}
+				p.Turnover += math.Abs(p.Shares[builder] - share)
+			}
+			for builder, share := range p.Shares {
+				if _, ok := prev[builder]; !ok {
+					p.Turnover += share
+				}
+			}
+			p.Turnover /= 2
+		}
+		sort.Strings(p.Entered)
+		sort.Strings(p.Exited)
+		series.Periods[i] = p
+	}
+
+	if n := len(series.Periods) - 1; n > 0 {
+		for _, p := range series.Periods[1:] {
+			series.Entries += len(p.Entered)
+			series.Exits += len(p.Exited)
+			series.ChurnRate += p.ChurnRate()
+			series.MeanTurnover += p.Turnover
+		}
+		series.ChurnRate /= float64(n)
+		series.MeanTurnover /= float64(n)
+		series.HHITrend = hhiTrend(series.Periods, periodSlots)
+	}
+	return series, nil
+}
+
+// hhiTrend returns the least-squares slope of HHI per period, placing each
+// period by its start so skipped periods keep their distance.
+func hhiTrend(periods []MarketSharePeriod, periodSlots uint64) float64 {
+	n := float64(len(periods))
+	var meanX, meanHHI float64
+	for _, p := range periods {
+		meanX += float64(p.StartSlot / periodSlots)
+		meanHHI += p.HHI
+	}
+	meanX /= n
+	meanHHI /= n
+	var sxy, sxx float64
+	for _, p := range periods {
+		dx := float64(p.StartSlot/periodSlots) - meanX
+		sxy += dx * (p.HHI - meanHHI)
+		sxx += dx * dx
+	}
+	return sxy / sxx
+}
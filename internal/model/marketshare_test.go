@@ -0,0 +1,87 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// marketShareBribes returns count bribes from builder starting at slot.
+func marketShareBribes(builder string, slot uint64, count int) []SlotBribe {
+	bribes := make([]SlotBribe, count)
+	for i := range bribes {
+		bribes[i] = SlotBribe{Slot: slot + uint64(i), BuilderPubkey: builder}
+	}
+	return bribes
+}
+
+// TestComputeMarketShareSeries verifies shares, entries, exits, turnover
+// and the HHI trend over periods with a gap.
+func TestComputeMarketShareSeries(t *testing.T) {
+	var bribes []SlotBribe
+	bribes = append(bribes, marketShareBribes("0xA", 0, 5)...)
+	bribes = append(bribes, marketShareBribes("0xB", 5, 5)...)
+	bribes = append(bribes, marketShareBribes("0xA", 10, 5)...)
+	bribes = append(bribes, marketShareBribes("0xC", 15, 5)...)
+	// Period 2 is empty
+	bribes = append(bribes, marketShareBribes("0xC", 30, 10)...)
+
+	series, err := ComputeMarketShareSeries(bribes, 10, nil)
+	if err != nil {
+		t.Fatalf("ComputeMarketShareSeries failed: %v", err)
+	}
+	if len(series.Periods) != 3 {
+		t.Fatalf("expected 3 non-empty periods, got %d", len(series.Periods))
+	}
+
+	p := series.Periods[1]
+	if p.StartSlot != 10 || p.EndSlot != 19 || p.Blocks != 10 {
+		t.Errorf("expected slots 10-19 with 10 blocks, got %d-%d with %d", p.StartSlot, p.EndSlot, p.Blocks)
+	}
+	if !reflect.DeepEqual(p.Entered, []string{"0xC"}) || !reflect.DeepEqual(p.Exited, []string{"0xB"}) {
+		t.Errorf("expected 0xC to enter and 0xB to exit, got %v and %v", p.Entered, p.Exited)
+	}
+	if p.Turnover != 0.5 || p.HHI != 0.5 || p.ChurnRate() != 1 {
+		t.Errorf("expected turnover 0.5, HHI 0.5 and churn 1, got %f, %f and %f", p.Turnover, p.HHI, p.ChurnRate())
+	}
+	if last := series.Periods[2]; !reflect.DeepEqual(last.Exited, []string{"0xA"}) || last.Shares["0xC"] != 1 {
+		t.Errorf("expected 0xA to exit leaving 0xC the whole market, got %v and %v", last.Exited, last.Shares)
+	}
+	if len(series.Periods[0].Entered) != 0 {
+		t.Errorf("expected first-period builders to be incumbents, got entries %v", series.Periods[0].Entered)
+	}
+
+	if series.Entries != 1 || series.Exits != 2 {
+		t.Errorf("expected 1 entry and 2 exits, got %d and %d", series.Entries, series.Exits)
+	}
+	if series.ChurnRate != 1 || series.MeanTurnover != 0.5 {
+		t.Errorf("expected churn 1 and turnover 0.5, got %f and %f", series.ChurnRate, series.MeanTurnover)
+	}
+	// HHI 0.5, 0.5, 1 at periods 0, 1, 3
+	if want := 5.0 / 28; math.Abs(series.HHITrend-want) > 1e-12 {
+		t.Errorf("expected HHI trend %f, got %f", want, series.HHITrend)
+	}
+}
+
+// TestComputeMarketShareSeries_Entities verifies a registry merges an
+// entity's pubkeys, and the error cases.
+func TestComputeMarketShareSeries_Entities(t *testing.T) {
+	reg := NewBuilderRegistry(map[string]string{"0xA": "alpha", "0xB": "alpha"})
+	bribes := append(marketShareBribes("0xA", 0, 3), marketShareBribes("0xB", 3, 1)...)
+
+	series, err := ComputeMarketShareSeries(bribes, SlotsPerEpoch, reg)
+	if err != nil {
+		t.Fatalf("ComputeMarketShareSeries failed: %v", err)
+	}
+	if shares := series.Periods[0].Shares; len(shares) != 1 || shares["alpha"] != 1 {
+		t.Errorf("expected alpha to hold the whole market, got %v", shares)
+	}
+
+	if _, err := ComputeMarketShareSeries(nil, SlotsPerDay, nil); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+	if _, err := ComputeMarketShareSeries(bribes, 0, nil); err == nil {
+		t.Error("Expected error for a zero period, got nil")
+	}
+}
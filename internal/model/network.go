@@ -0,0 +1,95 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Network describes the beacon chain timing parameters needed to
+// convert between wall-clock time and slot numbers.
+type Network struct {
+	Name           string
+	GenesisTime    time.Time // Start time of slot 0
+	SecondsPerSlot uint64
+}
+
+// Known networks.
+var (
+	Mainnet = Network{Name: "mainnet", GenesisTime: time.Unix(1606824023, 0).UTC(), SecondsPerSlot: 12}
+	Sepolia = Network{Name: "sepolia", GenesisTime: time.Unix(1655733600, 0).UTC(), SecondsPerSlot: 12}
+	Holesky = Network{Name: "holesky", GenesisTime: time.Unix(1695902400, 0).UTC(), SecondsPerSlot: 12}
+)
+
+// NetworkByName returns the timing parameters of a known network.
+func NetworkByName(name string) (Network, error) {
+	switch strings.ToLower(name) {
+	case "mainnet", "":
+		return Mainnet, nil
+	case "sepolia":
+		return Sepolia, nil
+	case "holesky":
+		return Holesky, nil
+	default:
+		return Network{}, fmt.Errorf("unknown network '%s'", name)
+	}
+}
+
+// SlotTime returns the start time of a slot.
+func (n Network) SlotTime(slot uint64) time.Time {
+	return n.GenesisTime.Add(time.Duration(slot*n.SecondsPerSlot) * time.Second)
+}
+
+// SlotAt returns the slot in progress at time t.
+//
+// Fails if t is before genesis.
+func (n Network) SlotAt(t time.Time) (uint64, error) {
+	if t.Before(n.GenesisTime) {
+		return 0, fmt.Errorf("time %s is before %s genesis (%s)",
+			t.UTC().Format(time.RFC3339), n.Name, n.GenesisTime.Format(time.RFC3339))
+	}
+	elapsed := uint64(t.Sub(n.GenesisTime) / time.Second)
+	return elapsed / n.SecondsPerSlot, nil
+}
+
+// SlotRangeForTimes converts the half-open interval [from, to) into
+// the inclusive slot range whose slots start inside it.
+//
+// Fails if the interval is empty, starts before genesis, or contains no slot start.
+func (n Network) SlotRangeForTimes(from, to time.Time) (startSlot, endSlot uint64, err error) {
+	if !from.Before(to) {
+		return 0, 0, fmt.Errorf("from (%s) must be before to (%s)",
+			from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	}
+
+	startSlot, err = n.SlotAt(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	// Round up to the first slot starting at or after from
+	if n.SlotTime(startSlot).Before(from) {
+		startSlot++
+	}
+
+	// Last slot starting strictly before to
+	endSlot, err = n.SlotAt(to.Add(-time.Nanosecond))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if endSlot < startSlot {
+		return 0, 0, fmt.Errorf("no slot starts between %s and %s",
+			from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	}
+
+	return startSlot, endSlot, nil
+}
+
+// SlotRangeAround returns the inclusive slot range whose slots start within
+// width centered on t, e.g. the six hours around a bridge exploit.
+func (n Network) SlotRangeAround(t time.Time, width time.Duration) (startSlot, endSlot uint64, err error) {
+	if width <= 0 {
+		return 0, 0, fmt.Errorf("window width must be positive, got %s", width)
+	}
+	return n.SlotRangeForTimes(t.Add(-width/2), t.Add(width-width/2))
+}
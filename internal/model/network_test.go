@@ -0,0 +1,112 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNetwork_SlotTimeRoundTrip verifies slot→time→slot is the identity.
+func TestNetwork_SlotTimeRoundTrip(t *testing.T) {
+	for _, slot := range []uint64{0, 1, 7200, 8_000_000} {
+		got, err := Mainnet.SlotAt(Mainnet.SlotTime(slot))
+		if err != nil {
+			t.Fatalf("SlotAt failed: %v", err)
+		}
+		if got != slot {
+			t.Errorf("expected slot %d, got %d", slot, got)
+		}
+	}
+}
+
+// TestNetwork_SlotAtMidSlot verifies a time inside a slot maps to that slot.
+func TestNetwork_SlotAtMidSlot(t *testing.T) {
+	ts := Mainnet.SlotTime(100).Add(7 * time.Second)
+	slot, err := Mainnet.SlotAt(ts)
+	if err != nil {
+		t.Fatalf("SlotAt failed: %v", err)
+	}
+	if slot != 100 {
+		t.Errorf("expected slot 100, got %d", slot)
+	}
+}
+
+// TestNetwork_SlotAtBeforeGenesis verifies failure before genesis.
+func TestNetwork_SlotAtBeforeGenesis(t *testing.T) {
+	_, err := Mainnet.SlotAt(Mainnet.GenesisTime.Add(-time.Second))
+	if err == nil {
+		t.Error("Expected error for time before genesis, got nil")
+	}
+}
+
+// TestNetwork_SlotRangeForTimes verifies a one-day range covers exactly 7200 slots.
+func TestNetwork_SlotRangeForTimes(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	start, end, err := Mainnet.SlotRangeForTimes(from, to)
+	if err != nil {
+		t.Fatalf("SlotRangeForTimes failed: %v", err)
+	}
+
+	if end-start+1 != 7200 {
+		t.Errorf("expected 7200 slots, got %d", end-start+1)
+	}
+	if Mainnet.SlotTime(start).Before(from) {
+		t.Errorf("start slot %d begins before range start", start)
+	}
+	if !Mainnet.SlotTime(end).Before(to) {
+		t.Errorf("end slot %d begins at or after range end", end)
+	}
+	if !Mainnet.SlotTime(end + 1).After(to.Add(-time.Nanosecond)) {
+		t.Errorf("slot after end (%d) should begin at or after range end", end+1)
+	}
+}
+
+// TestNetwork_SlotRangeForTimes_Invalid verifies empty and inverted ranges fail.
+func TestNetwork_SlotRangeForTimes_Invalid(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, _, err := Mainnet.SlotRangeForTimes(from, from); err == nil {
+		t.Error("Expected error for empty range, got nil")
+	}
+	if _, _, err := Mainnet.SlotRangeForTimes(from, from.Add(-time.Hour)); err == nil {
+		t.Error("Expected error for inverted range, got nil")
+	}
+	// A 1-second window strictly inside a slot contains no slot start
+	mid := Mainnet.SlotTime(1000).Add(3 * time.Second)
+	if _, _, err := Mainnet.SlotRangeForTimes(mid, mid.Add(time.Second)); err == nil {
+		t.Error("Expected error for range containing no slot start, got nil")
+	}
+}
+
+// TestNetwork_SlotRangeAround verifies a window is centered on its instant.
+func TestNetwork_SlotRangeAround(t *testing.T) {
+	exploit := Mainnet.SlotTime(10_000)
+
+	start, end, err := Mainnet.SlotRangeAround(exploit, 6*time.Hour)
+	if err != nil {
+		t.Fatalf("SlotRangeAround failed: %v", err)
+	}
+	if start != 10_000-900 || end != 10_000+899 {
+		t.Errorf("expected slots %d-%d, got %d-%d", 10_000-900, 10_000+899, start, end)
+	}
+
+	if _, _, err := Mainnet.SlotRangeAround(exploit, 0); err == nil {
+		t.Error("Expected error for zero width, got nil")
+	}
+}
+
+// TestNetworkByName verifies known and unknown network names.
+func TestNetworkByName(t *testing.T) {
+	n, err := NetworkByName("Holesky")
+	if err != nil {
+		t.Fatalf("NetworkByName failed: %v", err)
+	}
+	if n.Name != "holesky" {
+		t.Errorf("expected holesky, got %s", n.Name)
+	}
+
+	if _, err := NetworkByName("ropsten"); err == nil {
+		t.Error("Expected error for unknown network, got nil")
+	}
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// CartelPenalty describes what cartel members stand to lose if the
+// collusion is attributed to them. Members must be compensated for the
+// expected loss, so it adds to the cost of the attack:
+//
+//	E[penalty] = DetectionProbability · (k · BuilderFutureMEVWei + Proposers · ProposerStakeWei)
+//
+// for a cartel of k builders. Nil amounts count as zero. Every parameter
+// is an explicit assumption; none is measured from relay data.
+type CartelPenalty struct {
+	DetectionProbability float64  // Probability the collusion is attributed to its members
+	BuilderFutureMEVWei  *big.Int // Future MEV profit each builder forfeits if relays delist it
+	ProposerStakeWei     *big.Int // Stake each colluding proposer loses to social slashing
+	Proposers            uint64   // Colluding proposers; 0 for a builder-only cartel
+}
+
+// validate rejects probabilities outside [0, 1] and negative amounts.
+func (p *CartelPenalty) validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.DetectionProbability < 0 || p.DetectionProbability > 1 {
+		return fmt.Errorf("%w: detection probability %f (must be in [0,1])", ErrInvalidProbability, p.DetectionProbability)
+	}
+	if (p.BuilderFutureMEVWei != nil && p.BuilderFutureMEVWei.Sign() < 0) || (p.ProposerStakeWei != nil && p.ProposerStakeWei.Sign() < 0) {
+		return fmt.Errorf("cartel penalty cannot be negative")
+	}
+	return nil
+}
+
+// Expected returns the expected penalty for a cartel of k builders, 0 for
+// a nil p.
+func (p *CartelPenalty) Expected(k int) *big.Float {
+	if p == nil {
+		return new(big.Float)
+	}
+
+	atRisk := new(big.Int)
+	if p.BuilderFutureMEVWei != nil {
+		atRisk.Add(atRisk, new(big.Int).Mul(p.BuilderFutureMEVWei, big.NewInt(int64(k))))
+	}
+	if p.ProposerStakeWei != nil {
+		atRisk.Add(atRisk, new(big.Int).Mul(p.ProposerStakeWei, new(big.Int).SetUint64(p.Proposers)))
+	}
+	return new(big.Float).Mul(new(big.Float).SetInt(atRisk), big.NewFloat(p.DetectionProbability))
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// BridgeTarget is one bridge attacked in a portfolio.
+type BridgeTarget struct {
+	Name               string
+	TVL                *big.Float       // V_i in wei
+	SuccessProbability float64          // p_i ∈ [0, 1]
+	Probability        ProbabilityModel // p_i(V_i); overrides SuccessProbability when set
+}
+
+// BridgeOutcome is one target's share of a portfolio result.
+type BridgeOutcome struct {
+	Name            string
+	TVL             *big.Float
+	SuccessProb     float64    // p_i(V_i) used in calculation
+	ExpectedRevenue *big.Float // p_i · V_i
+}
+
+// PortfolioResult contains the output of AttackerProfitPortfolio.
+type PortfolioResult struct {
+	Targets         []BridgeOutcome
+	ExpectedRevenue *big.Float // Σ p_i · V_i
+	EffectiveCost   *big.Float // C_c^eff, paid once
+	Profit          *big.Float // ExpectedRevenue - EffectiveCost
+	Alpha           float64
+
+	// Present values under params.DiscountRate, as in ProfitResult
+	DiscountedRevenue *big.Float
+	DiscountedCost    *big.Float
+	DiscountedProfit  *big.Float
+}
+
+// AttackerProfitPortfolio computes the expected profit of censoring τ
+// slots to attack several bridges at once:
+//
+//	P = Σ p_i(V_i) · V_i - C_c^eff
+//
+// Bridges whose challenge windows overlap the same τ slots can all be
+// attacked by one censorship campaign, so the cost is paid once while the
+// revenue adds up; a portfolio can be profitable when no single bridge is.
+// Each target's success is evaluated independently.
+//
+// The cost is computed from params exactly as in AttackerProfit, whose
+// BridgeTVL, SuccessProbability and Probability are ignored in favor of
+// the targets.
+func AttackerProfitPortfolio(bribes []SlotBribe, targets []BridgeTarget, params ProfitParams) (*PortfolioResult, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no bridge targets")
+	}
+
+	result := &PortfolioResult{
+		Targets:         make([]BridgeOutcome, 0, len(targets)),
+		ExpectedRevenue: new(big.Float),
+	}
+	for i, target := range targets {
+		if target.TVL == nil || target.TVL.Sign() < 0 {
+			return nil, fmt.Errorf("invalid TVL for target %d (%s)", i, target.Name)
+		}
+		p := target.SuccessProbability
+		if target.Probability != nil {
+			var err error
+			if p, err = evaluateProbability(target.Probability, target.TVL); err != nil {
+				return nil, fmt.Errorf("target %d (%s): %w", i, target.Name, err)
+			}
+		} else if p < 0 || p > 1 {
+			return nil, fmt.Errorf("%w: success probability %f for target %d (%s)", ErrInvalidProbability, p, i, target.Name)
+		}
+
+		revenue := new(big.Float).Mul(big.NewFloat(p), target.TVL)
+		result.ExpectedRevenue.Add(result.ExpectedRevenue, revenue)
+		result.Targets = append(result.Targets, BridgeOutcome{
+			Name:            target.Name,
+			TVL:             new(big.Float).Set(target.TVL),
+			SuccessProb:     p,
+			ExpectedRevenue: revenue,
+		})
+	}
+
+	cost, err := computeAttackCost(bribes, params)
+	if err != nil {
+		return nil, err
+	}
+	result.EffectiveCost = cost.effective
+	result.Alpha = cost.alpha
+	result.Profit = new(big.Float).Sub(result.ExpectedRevenue, cost.effective)
+
+	result.DiscountedRevenue = new(big.Float).Mul(result.ExpectedRevenue, big.NewFloat(discountFactor(params.DiscountRate, params.Tau)))
+	result.DiscountedCost = cost.discounted
+	result.DiscountedProfit = new(big.Float).Sub(result.DiscountedRevenue, cost.discounted)
+
+	return result, nil
+}
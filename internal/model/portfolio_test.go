@@ -0,0 +1,63 @@
+package model
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestAttackerProfitPortfolio verifies revenues add up across bridges
+// against a single shared cost.
+func TestAttackerProfitPortfolio(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(2000), BuilderPubkey: "0xB"},
+	}
+	params := ProfitParams{Tau: 2, TopK: 1}
+	targets := []BridgeTarget{
+		{Name: "small", TVL: big.NewFloat(2000), SuccessProbability: 0.5},
+		{Name: "stepped", TVL: big.NewFloat(4000), Probability: StepProbability{Threshold: big.NewFloat(3000), Below: 0.9, Above: 0.25}},
+	}
+
+	// Revenue 0.5·2000 + 0.25·4000 = 2000; C_c^eff = 0.5·3000 = 1500, paid once
+	result, err := AttackerProfitPortfolio(bribes, targets, params)
+	if err != nil {
+		t.Fatalf("AttackerProfitPortfolio failed: %v", err)
+	}
+	if len(result.Targets) != 2 || result.Targets[1].SuccessProb != 0.25 {
+		t.Fatalf("expected the second target at p=0.25, got %+v", result.Targets)
+	}
+	if !floatEqual(result.ExpectedRevenue, big.NewFloat(2000), 0.01) {
+		t.Errorf("expected revenue 2000, got %s", result.ExpectedRevenue.String())
+	}
+	if !floatEqual(result.EffectiveCost, big.NewFloat(1500), 0.01) {
+		t.Errorf("expected cost 1500, got %s", result.EffectiveCost.String())
+	}
+	if !floatEqual(result.Profit, big.NewFloat(500), 0.01) {
+		t.Errorf("expected profit 500, got %s", result.Profit.String())
+	}
+
+	// Neither bridge alone covers the cost
+	for _, target := range targets {
+		single, err := AttackerProfit(bribes, ProfitParams{
+			BridgeTVL:          target.TVL,
+			SuccessProbability: target.SuccessProbability,
+			Probability:        target.Probability,
+			Tau:                2,
+			TopK:               1,
+		})
+		if err != nil {
+			t.Fatalf("AttackerProfit failed: %v", err)
+		}
+		if single.Profit.Sign() >= 0 {
+			t.Errorf("expected %s alone to be unprofitable, got %s", target.Name, single.Profit.String())
+		}
+	}
+
+	if _, err := AttackerProfitPortfolio(bribes, nil, params); err == nil {
+		t.Error("Expected error for no targets, got nil")
+	}
+	bad := []BridgeTarget{{Name: "bad", TVL: big.NewFloat(1), SuccessProbability: 1.5}}
+	if _, err := AttackerProfitPortfolio(bribes, bad, params); err == nil {
+		t.Error("Expected error for probability above 1, got nil")
+	}
+}
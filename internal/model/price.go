@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// PriceQuote is the ETH/USD price reported by a data source at a point in
+// time.
+type PriceQuote struct {
+	Timestamp time.Time // Quote time (second precision)
+	USD       float64   // Price of 1 ETH in USD
+	Source    string    // Data provider, e.g. "coingecko"
+}
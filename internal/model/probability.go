@@ -0,0 +1,213 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// ProbabilityModel is an assumption about the attack success probability
+// p(V) as a function of the bridge TVL V (wei).
+//
+// None of these models is claimed to be correct; they let a caller state
+// how success scales with the prize (e.g. larger bridges attract more
+// monitoring, or justify more attacker effort) and make that explicit.
+type ProbabilityModel interface {
+	// Probability returns p(V) ∈ [0, 1].
+	Probability(tvl *big.Float) float64
+}
+
+// ConstantProbability is a p that does not depend on V, the model behind
+// ProfitParams.SuccessProbability.
+type ConstantProbability float64
+
+// Probability returns p.
+func (p ConstantProbability) Probability(*big.Float) float64 {
+	return float64(p)
+}
+
+// LogisticProbability rises from 0 towards Max around Midpoint:
+//
+//	p(V) = Max / (1 + exp(-(V - Midpoint) / Scale))
+//
+// Midpoint and Scale are in wei like V; a negative Scale makes p fall as
+// V grows. A curve without a Midpoint or a nonzero Scale is undefined.
+type LogisticProbability struct {
+	Max      float64
+	Midpoint *big.Float
+	Scale    *big.Float
+}
+
+// Probability evaluates the logistic curve at tvl. It returns NaN, which
+// callers reject as an invalid probability, for an undefined curve.
+func (l LogisticProbability) Probability(tvl *big.Float) float64 {
+	if l.Midpoint == nil || l.Scale == nil || l.Scale.Sign() == 0 {
+		return math.NaN()
+	}
+	z := new(big.Float).Sub(tvl, l.Midpoint)
+	x, _ := z.Quo(z, l.Scale).Float64()
+	return l.Max / (1 + math.Exp(-x))
+}
+
+// StepProbability is Below for V < Threshold and Above from Threshold on.
+type StepProbability struct {
+	Threshold *big.Float
+	Below     float64
+	Above     float64
+}
+
+// Probability returns Below or Above depending on tvl.
+func (s StepProbability) Probability(tvl *big.Float) float64 {
+	if tvl.Cmp(s.Threshold) < 0 {
+		return s.Below
+	}
+	return s.Above
+}
+
+// ProbabilityPoint is one row of a piecewise probability table.
+type ProbabilityPoint struct {
+	TVL         *big.Float // wei
+	Probability float64
+}
+
+// PiecewiseProbability interpolates linearly between table points and is
+// constant beyond the first and last. Construct it with
+// NewPiecewiseProbability.
+type PiecewiseProbability struct {
+	points []ProbabilityPoint
+}
+
+// NewPiecewiseProbability validates a table of at least one point with
+// probabilities in [0, 1] and distinct TVLs; the points may be in any
+// order.
+func NewPiecewiseProbability(points []ProbabilityPoint) (*PiecewiseProbability, error) {
+	if len(points) == 0 {
+		return nil, fmt.Errorf("probability table is empty")
+	}
+	sorted := make([]ProbabilityPoint, len(points))
+	for i, point := range points {
+		if point.TVL == nil {
+			return nil, fmt.Errorf("nil TVL at point %d", i)
+		}
+		if point.Probability < 0 || point.Probability > 1 {
+			return nil, fmt.Errorf("%w: %f at point %d (must be in [0,1])", ErrInvalidProbability, point.Probability, i)
+		}
+		sorted[i] = ProbabilityPoint{TVL: new(big.Float).Set(point.TVL), Probability: point.Probability}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].TVL.Cmp(sorted[j].TVL) < 0
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].TVL.Cmp(sorted[i-1].TVL) == 0 {
+			return nil, fmt.Errorf("duplicate TVL %s in probability table", sorted[i].TVL.Text('g', 10))
+		}
+	}
+	return &PiecewiseProbability{points: sorted}, nil
+}
+
+// Probability interpolates the table at tvl.
+func (p *PiecewiseProbability) Probability(tvl *big.Float) float64 {
+	i := sort.Search(len(p.points), func(i int) bool {
+		return p.points[i].TVL.Cmp(tvl) > 0
+	})
+	switch i {
+	case 0:
+		return p.points[0].Probability
+	case len(p.points):
+		return p.points[i-1].Probability
+	}
+
+	lo, hi := p.points[i-1], p.points[i]
+	offset := new(big.Float).Sub(tvl, lo.TVL)
+	width := new(big.Float).Sub(hi.TVL, lo.TVL)
+	t, _ := offset.Quo(offset, width).Float64()
+	return lo.Probability + t*(hi.Probability-lo.Probability)
+}
+
+// evaluateProbability returns p(V), failing outside [0, 1].
+func evaluateProbability(probability ProbabilityModel, tvl *big.Float) (float64, error) {
+	p := probability.Probability(tvl)
+	if math.IsNaN(p) || p < 0 || p > 1 {
+		return 0, fmt.Errorf("%w: success probability %f (must be in [0,1])", ErrInvalidProbability, p)
+	}
+	return p, nil
+}
+
+// breakevenSearchDoublings bounds how far FindBreakevenTVLBy searches:
+// up to 2^64 times the effective cost.
+const breakevenSearchDoublings = 64
+
+// breakevenSearchBisections refines the bracketed threshold to well below
+// a wei for any realistic cost.
+const breakevenSearchBisections = 200
+
+// FindBreakevenTVLBy finds the TVL threshold V* = min { V : p(V)·V > C_c^eff }
+// under a TVL-dependent success probability.
+//
+// Since p ≤ 1, V* ≥ C_c^eff. The search doubles V from C_c^eff until the
+// attack is profitable, then bisects the last interval. This finds the
+// true minimum whenever p(V)·V is non-decreasing, as it is for constant,
+// step-up and increasing logistic or table models; otherwise it returns a
+// profitable threshold that may not be the smallest. A ConstantProbability
+// is solved in closed form, as in FindBreakevenTVL.
+//
+// Fails if the attack is not profitable for any V up to 2^64 · C_c^eff.
+func FindBreakevenTVLBy(bribes []SlotBribe, probability ProbabilityModel, tau uint64, topK int) (*big.Float, float64, error) {
+	if p, ok := probability.(ConstantProbability); ok {
+		return FindBreakevenTVL(bribes, float64(p), tau, topK)
+	}
+
+	ccEff, alpha, err := EffectiveCensorshipCost(bribes, tau, topK)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to compute effective cost: %w", err)
+	}
+
+	profitable := func(tvl *big.Float) (bool, error) {
+		p, err := evaluateProbability(probability, tvl)
+		if err != nil {
+			return false, err
+		}
+		revenue := new(big.Float).Mul(big.NewFloat(p), tvl)
+		return revenue.Cmp(ccEff) > 0, nil
+	}
+
+	// A free attack is profitable at any positive TVL
+	if ccEff.Sign() == 0 {
+		return new(big.Float), alpha, nil
+	}
+
+	// Bracket V* between lo (unprofitable) and hi (profitable)
+	lo := new(big.Float)
+	hi := new(big.Float).Set(ccEff)
+	for i := 0; ; i++ {
+		ok, err := profitable(hi)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			break
+		}
+		if i == breakevenSearchDoublings {
+			return nil, 0, fmt.Errorf("attack not profitable for any TVL up to %s wei", hi.Text('g', 6))
+		}
+		lo.Set(hi)
+		hi.Mul(hi, big.NewFloat(2))
+	}
+
+	for i := 0; i < breakevenSearchBisections; i++ {
+		mid := new(big.Float).Add(lo, hi)
+		mid.Quo(mid, big.NewFloat(2))
+		ok, err := profitable(mid)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi, alpha, nil
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// TestProbabilityModels verifies each model's p(V) at representative TVLs.
+func TestProbabilityModels(t *testing.T) {
+	table, err := NewPiecewiseProbability([]ProbabilityPoint{
+		{TVL: big.NewFloat(300), Probability: 0.2},
+		{TVL: big.NewFloat(100), Probability: 0.8}, // Out of order on purpose
+	})
+	if err != nil {
+		t.Fatalf("NewPiecewiseProbability failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		model ProbabilityModel
+		tvl   float64
+		want  float64
+	}{
+		{"constant", ConstantProbability(0.3), 1e9, 0.3},
+		{"logistic at midpoint", LogisticProbability{Max: 0.9, Midpoint: big.NewFloat(1000), Scale: big.NewFloat(100)}, 1000, 0.45},
+		{"logistic far above", LogisticProbability{Max: 0.9, Midpoint: big.NewFloat(1000), Scale: big.NewFloat(100)}, 1e6, 0.9},
+		{"step below", StepProbability{Threshold: big.NewFloat(500), Below: 0.1, Above: 0.6}, 499, 0.1},
+		{"step at threshold", StepProbability{Threshold: big.NewFloat(500), Below: 0.1, Above: 0.6}, 500, 0.6},
+		{"table before first", table, 50, 0.8},
+		{"table interpolated", table, 150, 0.65},
+		{"table after last", table, 1000, 0.2},
+	}
+	for _, tt := range tests {
+		if got := tt.model.Probability(big.NewFloat(tt.tvl)); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: expected p=%f, got %f", tt.name, tt.want, got)
+		}
+	}
+
+	// An undefined logistic curve yields NaN rather than panicking
+	undefined := []LogisticProbability{
+		{},
+		{Max: 0.9, Scale: big.NewFloat(100)},
+		{Max: 0.9, Midpoint: big.NewFloat(1000)},
+		{Max: 0.9, Midpoint: big.NewFloat(1000), Scale: big.NewFloat(0)},
+	}
+	for _, l := range undefined {
+		if got := l.Probability(big.NewFloat(1000)); !math.IsNaN(got) {
+			t.Errorf("%+v: expected NaN, got %f", l, got)
+		}
+	}
+	falling := LogisticProbability{Max: 0.9, Midpoint: big.NewFloat(1000), Scale: big.NewFloat(-100)}
+	if got := falling.Probability(big.NewFloat(1e6)); math.Abs(got) > 1e-9 {
+		t.Errorf("expected a negative scale to fall to p=0, got %f", got)
+	}
+
+	if _, err := NewPiecewiseProbability(nil); err == nil {
+		t.Error("Expected error for empty table, got nil")
+	}
+	if _, err := NewPiecewiseProbability([]ProbabilityPoint{{TVL: big.NewFloat(1), Probability: 1.5}}); err == nil {
+		t.Error("Expected error for probability above 1, got nil")
+	}
+	duplicate := []ProbabilityPoint{{TVL: big.NewFloat(1), Probability: 0.1}, {TVL: big.NewFloat(1), Probability: 0.2}}
+	if _, err := NewPiecewiseProbability(duplicate); err == nil {
+		t.Error("Expected error for duplicate TVL, got nil")
+	}
+}
+
+// TestAttackerProfit_ProbabilityModel verifies p(V) is evaluated at the
+// bridge TVL and overrides SuccessProbability.
+func TestAttackerProfit_ProbabilityModel(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(100), BuilderPubkey: "0xB"},
+	}
+
+	// α = 0.5 with k=1, so C_c^eff = 100; p(1000) = 0.6 gives profit 500
+	result, err := AttackerProfit(bribes, ProfitParams{
+		BridgeTVL:          big.NewFloat(1000),
+		SuccessProbability: 2, // Ignored
+		Probability:        StepProbability{Threshold: big.NewFloat(500), Below: 0.1, Above: 0.6},
+		Tau:                2,
+		TopK:               1,
+	})
+	if err != nil {
+		t.Fatalf("AttackerProfit failed: %v", err)
+	}
+	if result.SuccessProb != 0.6 {
+		t.Errorf("expected p=0.6, got %f", result.SuccessProb)
+	}
+	if profit, _ := result.Profit.Float64(); math.Abs(profit-500) > 1e-9 {
+		t.Errorf("expected profit 500, got %f", profit)
+	}
+
+	_, err = AttackerProfit(bribes, ProfitParams{
+		BridgeTVL:   big.NewFloat(1000),
+		Probability: ConstantProbability(-0.5),
+		Tau:         2,
+		TopK:        1,
+	})
+	if err == nil {
+		t.Error("Expected error for model returning p < 0, got nil")
+	}
+
+	_, err = AttackerProfit(bribes, ProfitParams{
+		BridgeTVL:   big.NewFloat(1000),
+		Probability: LogisticProbability{Max: 0.9, Midpoint: big.NewFloat(1000), Scale: big.NewFloat(0)},
+		Tau:         2,
+		TopK:        1,
+	})
+	if !errors.Is(err, ErrInvalidProbability) {
+		t.Errorf("expected ErrInvalidProbability for a zero logistic scale, got %v", err)
+	}
+}
+
+// TestFindBreakevenTVLBy verifies the searched threshold against closed
+// forms and that a never-profitable model fails.
+func TestFindBreakevenTVLBy(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(100), BuilderPubkey: "0xB"},
+	}
+
+	// C_c^eff = 100. Constant p=0.5: V* = 200
+	breakeven, _, err := FindBreakevenTVLBy(bribes, ConstantProbability(0.5), 2, 1)
+	if err != nil {
+		t.Fatalf("FindBreakevenTVLBy failed: %v", err)
+	}
+	if v, _ := breakeven.Float64(); v != 200 {
+		t.Errorf("expected V*=200, got %f", v)
+	}
+
+	// p = 0.1 below the step at 5000: 0.1·V exceeds 100 just above
+	// V = 1000, before the step matters
+	step := StepProbability{Threshold: big.NewFloat(5000), Below: 0.1, Above: 0.5}
+	breakeven, _, err = FindBreakevenTVLBy(bribes, step, 2, 1)
+	if err != nil {
+		t.Fatalf("FindBreakevenTVLBy failed: %v", err)
+	}
+	if v, _ := breakeven.Float64(); math.Abs(v-1000) > 1e-6 {
+		t.Errorf("expected V*=1000, got %f", v)
+	}
+
+	if _, _, err := FindBreakevenTVLBy(bribes, StepProbability{Threshold: big.NewFloat(0)}, 2, 1); err == nil {
+		t.Error("Expected error for p=0 everywhere, got nil")
+	}
+}
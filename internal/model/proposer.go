@@ -0,0 +1,89 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ProposerStats contains proposer-level statistics for concentration analysis.
+type ProposerStats struct {
+	Proposer      string   // Proposer pubkey, or fee recipient when grouped by recipient
+	SlotCount     uint64   // Slots proposed
+	TotalValueWei *big.Int // Winning bids paid to the proposer
+	ValueShare    float64  // Fraction of the value paid to all proposers
+}
+
+// ComputeProposerConcentration computes α over proposers rather than
+// builders:
+//
+//	α_p = (slots proposed by top k proposers) / (slots with a known proposer)
+//
+// A censor need not form a builder cartel: bribing the proposers of the
+// targeted slots works too, and is cheaper when a few operators propose a
+// large share of slots. Each proposer is paid at least its slot's winning
+// bid, so the returned stats carry the value each received.
+//
+// Bribes without a ProposerPubkey (e.g. loaded from storage) are skipped;
+// it is an error if none remain. Every counted bribe must have a value.
+func ComputeProposerConcentration(bribes []SlotBribe, topK int) (alpha float64, proposerStats []ProposerStats, err error) {
+	return computeProposerConcentration(bribes, topK, func(bribe SlotBribe) string {
+		return bribe.ProposerPubkey
+	})
+}
+
+// ComputeFeeRecipientConcentration is ComputeProposerConcentration with
+// proposers grouped by fee recipient. Staking pools typically share a fee
+// recipient across many validators, so this approximates concentration by
+// operator, which per-validator pubkeys hide.
+func ComputeFeeRecipientConcentration(bribes []SlotBribe, topK int) (alpha float64, proposerStats []ProposerStats, err error) {
+	return computeProposerConcentration(bribes, topK, func(bribe SlotBribe) string {
+		return bribe.ProposerFeeRecipient
+	})
+}
+
+func computeProposerConcentration(bribes []SlotBribe, topK int, proposer func(SlotBribe) string) (float64, []ProposerStats, error) {
+	counter := newConcentrationCounter(func(bribe SlotBribe) (string, string) {
+		return proposer(bribe), ""
+	})
+	counter.weighValues()
+	for _, bribe := range bribes {
+		if proposer(bribe) == "" {
+			continue
+		}
+		if bribe.ValueWei == nil {
+			return 0, nil, fmt.Errorf("%w for slot %d", ErrNilValue, bribe.Slot)
+		}
+		counter.add(bribe)
+	}
+	if counter.totalBlocks == 0 && len(bribes) > 0 {
+		return 0, nil, fmt.Errorf("%w: no bribes with proposer data", ErrInsufficientData)
+	}
+
+	alpha, stats, err := counter.result(topK)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	total := new(big.Float).SetInt(counter.totalValue)
+	proposerStats := make([]ProposerStats, len(stats))
+	for i, s := range stats {
+		value := counter.values[s.BuilderPubkey]
+		proposerStats[i] = ProposerStats{Proposer: s.BuilderPubkey, SlotCount: s.BlockCount, TotalValueWei: value}
+		if counter.totalValue.Sign() > 0 {
+			proposerStats[i].ValueShare, _ = new(big.Float).Quo(new(big.Float).SetInt(value), total).Float64()
+		}
+	}
+	return alpha, proposerStats, nil
+}
+
+// ProposerDiversity returns the number of distinct proposers among bribes
+// with a known proposer.
+func ProposerDiversity(bribes []SlotBribe) int {
+	proposers := make(map[string]struct{})
+	for _, bribe := range bribes {
+		if bribe.ProposerPubkey != "" {
+			proposers[bribe.ProposerPubkey] = struct{}{}
+		}
+	}
+	return len(proposers)
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestComputeProposerConcentration verifies α over proposers, value
+// totals, and that bribes without proposer data are skipped.
+func TestComputeProposerConcentration(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xB1", ProposerPubkey: "0xP1", ProposerFeeRecipient: "0xPool"},
+		{Slot: 2, ValueWei: big.NewInt(200), BuilderPubkey: "0xB1", ProposerPubkey: "0xP1", ProposerFeeRecipient: "0xPool"},
+		{Slot: 3, ValueWei: big.NewInt(300), BuilderPubkey: "0xB2", ProposerPubkey: "0xP2", ProposerFeeRecipient: "0xPool"},
+		{Slot: 4, ValueWei: big.NewInt(400), BuilderPubkey: "0xB2", ProposerPubkey: "0xP3", ProposerFeeRecipient: "0xSolo"},
+		{Slot: 5, ValueWei: big.NewInt(500), BuilderPubkey: "0xB3"}, // No proposer data
+	}
+
+	// Top 1 proposer: P1 proposed 2 of 4 known slots
+	alpha, stats, err := ComputeProposerConcentration(bribes, 1)
+	if err != nil {
+		t.Fatalf("ComputeProposerConcentration failed: %v", err)
+	}
+	if alpha != 0.5 {
+		t.Errorf("expected alpha=0.5, got %f", alpha)
+	}
+	if len(stats) != 3 || stats[0].Proposer != "0xP1" || stats[0].SlotCount != 2 {
+		t.Fatalf("expected 0xP1 first with 2 slots among 3 proposers, got %+v", stats)
+	}
+	if stats[0].TotalValueWei.Int64() != 300 || stats[0].ValueShare != 0.3 {
+		t.Errorf("expected 0xP1 to receive 300 wei (0.3 share), got %+v", stats[0])
+	}
+
+	// Grouped by fee recipient, the pool proposed 3 of 4
+	alpha, stats, err = ComputeFeeRecipientConcentration(bribes, 1)
+	if err != nil {
+		t.Fatalf("ComputeFeeRecipientConcentration failed: %v", err)
+	}
+	if alpha != 0.75 || stats[0].Proposer != "0xPool" {
+		t.Errorf("expected 0xPool with alpha=0.75, got %f for %+v", alpha, stats)
+	}
+
+	if n := ProposerDiversity(bribes); n != 3 {
+		t.Errorf("expected 3 proposers, got %d", n)
+	}
+
+	if _, _, err := ComputeProposerConcentration(bribes[4:], 1); err == nil {
+		t.Error("Expected error without proposer data, got nil")
+	}
+}
@@ -0,0 +1,232 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// RelayStats contains one relay's share of delivered payloads.
+type RelayStats struct {
+	RelayURL     string  // Relay that delivered the payloads
+	PayloadCount uint64  // Payloads delivered within the window
+	Share        float64 // Fraction of the window's payloads with a known relay
+}
+
+// ComputeRelayConcentration computes α over relays for the slots in
+// [startSlot, endSlot] (endSlot 0 means no upper bound):
+//
+//	α_r = (payloads delivered by top k relays) / (payloads with a known relay)
+//
+// Builder concentration assumes every relay forwards any block. Relays
+// that filter sanctioned transactions break that assumption: a censor only
+// has to outbid the builders reachable through non-filtering relays, so
+// relay shares bound how much of the market the effective cost model
+// should count.
+//
+// Bribes without a RelayURL are skipped, and the same slot reported twice
+// by one relay (e.g. from overlapping snapshots) counts once. It is an
+// error if no payload remains. Stats are ordered by payload count
+// descending, then by relay URL.
+func ComputeRelayConcentration(bribes []SlotBribe, startSlot, endSlot uint64, topK int) (alpha float64, relayStats []RelayStats, err error) {
+	if topK < 1 {
+		return 0, nil, fmt.Errorf("%w: must be at least 1, got %d", ErrInvalidTopK, topK)
+	}
+	if endSlot != 0 && endSlot < startSlot {
+		return 0, nil, fmt.Errorf("invalid slot window %d-%d", startSlot, endSlot)
+	}
+
+	type delivery struct {
+		relay string
+		slot  uint64
+	}
+	seen := make(map[delivery]bool)
+	counts := make(map[string]uint64)
+	var total uint64
+	for _, bribe := range bribes {
+		if bribe.RelayURL == "" || bribe.Slot < startSlot || (endSlot != 0 && bribe.Slot > endSlot) {
+			continue
+		}
+		key := delivery{relay: bribe.RelayURL, slot: bribe.Slot}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		counts[bribe.RelayURL]++
+		total++
+	}
+	if total == 0 {
+		return 0, nil, fmt.Errorf("%w: no payloads with relay data in slots %d-%d", ErrInsufficientData, startSlot, endSlot)
+	}
+
+	relayStats = make([]RelayStats, 0, len(counts))
+	for relay, count := range counts {
+		relayStats = append(relayStats, RelayStats{
+			RelayURL:     relay,
+			PayloadCount: count,
+			Share:        float64(count) / float64(total),
+		})
+	}
+	sort.Slice(relayStats, func(i, j int) bool {
+		if relayStats[i].PayloadCount != relayStats[j].PayloadCount {
+			return relayStats[i].PayloadCount > relayStats[j].PayloadCount
+		}
+		return relayStats[i].RelayURL < relayStats[j].RelayURL
+	})
+
+	var topPayloads uint64
+	for i := 0; i < topK && i < len(relayStats); i++ {
+		topPayloads += relayStats[i].PayloadCount
+	}
+	return float64(topPayloads) / float64(total), relayStats, nil
+}
+
+// RelayComparison sets each relay's reports against every other relay's
+// for the same slots.
+type RelayComparison struct {
+	Slots           uint64 // Slots reported by any relay
+	MultiRelaySlots uint64 // Slots reported by two relays or more
+	DisputedSlots   uint64 // Slots whose relays reported different values or builders
+	Relays          []RelayStatistics
+}
+
+// RelayStatistics summarizes one relay's reports.
+type RelayStatistics struct {
+	RelayURL        string
+	Slots           uint64   // Slots the relay reported
+	Coverage        float64  // Fraction of the slots reported by any relay
+	TotalValueWei   *big.Int // Sum of the relay's reported winning bids
+	MeanBidWei      *big.Int // TotalValueWei / Slots
+	UniqueBuilders  int
+	TopBuilder      string  // Builder of most of the relay's slots, ties broken by pubkey
+	TopBuilderShare float64 // Fraction of the relay's slots built by TopBuilder
+	BuilderHHI      float64 // Herfindahl-Hirschman index of builders over the relay's slots
+
+	SharedSlots      uint64  // Slots another relay also reported
+	Disagreements    uint64  // Shared slots on which another relay reported a different value or builder
+	DisagreementRate float64 // Disagreements / SharedSlots (0 without shared slots)
+
+	// Mean over shared slots of the relay's value minus the mean value the
+	// other relays reported: a relay that systematically over- or
+	// under-reports bids shows a deviation far from zero.
+	MeanDeviationWei *big.Int
+}
+
+// CompareRelays computes per-relay statistics from every relay's report
+// of each slot, as storage's GetRelayReports returns them. Merging relays
+// into one record per slot hides which relay a figure came from; these
+// statistics show relays that miss slots, see a different builder mix or
+// disagree with the others, any of which skews the merged dataset.
+//
+// Reports without a RelayURL are skipped, and a slot reported twice by one
+// relay counts its first report only, as in ComputeRelayConcentration.
+// Relays are ordered by slots reported descending, then by URL.
+func CompareRelays(reports []SlotBribe) (*RelayComparison, error) {
+	type delivery struct {
+		relay string
+		slot  uint64
+	}
+	seen := make(map[delivery]bool)
+	bySlot := make(map[uint64][]SlotBribe)
+	for _, report := range reports {
+		if report.RelayURL == "" {
+			continue
+		}
+		if report.ValueWei == nil {
+			return nil, fmt.Errorf("%w for slot %d from %s", ErrNilValue, report.Slot, report.RelayURL)
+		}
+		key := delivery{relay: report.RelayURL, slot: report.Slot}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		bySlot[report.Slot] = append(bySlot[report.Slot], report)
+	}
+	if len(bySlot) == 0 {
+		return nil, fmt.Errorf("%w: no reports with relay data", ErrInsufficientData)
+	}
+
+	type tally struct {
+		stats     RelayStatistics
+		builders  map[string]uint64
+		deviation *big.Rat
+	}
+	tallies := make(map[string]*tally)
+	comparison := &RelayComparison{Slots: uint64(len(bySlot))}
+	for _, slotReports := range bySlot {
+		if len(slotReports) > 1 {
+			comparison.MultiRelaySlots++
+		}
+		total := new(big.Int)
+		for _, report := range slotReports {
+			total.Add(total, report.ValueWei)
+		}
+		disputed := false
+		for i, report := range slotReports {
+			t := tallies[report.RelayURL]
+			if t == nil {
+				t = &tally{
+					stats:     RelayStatistics{RelayURL: report.RelayURL, TotalValueWei: new(big.Int)},
+					builders:  make(map[string]uint64),
+					deviation: new(big.Rat),
+				}
+				tallies[report.RelayURL] = t
+			}
+			t.stats.Slots++
+			t.stats.TotalValueWei.Add(t.stats.TotalValueWei, report.ValueWei)
+			t.builders[report.BuilderPubkey]++
+			if len(slotReports) == 1 {
+				continue
+			}
+
+			t.stats.SharedSlots++
+			for j, other := range slotReports {
+				if j != i && (other.ValueWei.Cmp(report.ValueWei) != 0 || other.BuilderPubkey != report.BuilderPubkey) {
+					t.stats.Disagreements++
+					disputed = true
+					break
+				}
+			}
+			// Own value minus the others' mean
+			others := new(big.Int).Sub(total, report.ValueWei)
+			othersMean := new(big.Rat).SetFrac(others, big.NewInt(int64(len(slotReports)-1)))
+			t.deviation.Add(t.deviation, new(big.Rat).Sub(new(big.Rat).SetInt(report.ValueWei), othersMean))
+		}
+		if disputed {
+			comparison.DisputedSlots++
+		}
+	}
+
+	comparison.Relays = make([]RelayStatistics, 0, len(tallies))
+	for _, t := range tallies {
+		stats := t.stats
+		n := float64(stats.Slots)
+		stats.Coverage = float64(stats.Slots) / float64(comparison.Slots)
+		stats.MeanBidWei = new(big.Int).Quo(stats.TotalValueWei, new(big.Int).SetUint64(stats.Slots))
+		stats.UniqueBuilders = len(t.builders)
+		var topCount uint64
+		for builder, count := range t.builders {
+			share := float64(count) / n
+			stats.BuilderHHI += share * share
+			if count > topCount || (count == topCount && builder < stats.TopBuilder) {
+				stats.TopBuilder, topCount = builder, count
+			}
+		}
+		stats.TopBuilderShare = float64(topCount) / n
+		stats.MeanDeviationWei = new(big.Int)
+		if stats.SharedSlots > 0 {
+			stats.DisagreementRate = float64(stats.Disagreements) / float64(stats.SharedSlots)
+			mean := t.deviation.Quo(t.deviation, new(big.Rat).SetInt(new(big.Int).SetUint64(stats.SharedSlots)))
+			stats.MeanDeviationWei.Quo(mean.Num(), mean.Denom())
+		}
+		comparison.Relays = append(comparison.Relays, stats)
+	}
+	sort.Slice(comparison.Relays, func(i, j int) bool {
+		a, b := comparison.Relays[i], comparison.Relays[j]
+		if a.Slots != b.Slots {
+			return a.Slots > b.Slots
+		}
+		return a.RelayURL < b.RelayURL
+	})
+	return comparison, nil
+}
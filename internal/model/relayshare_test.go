@@ -0,0 +1,102 @@
+package model
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// TestComputeRelayConcentration verifies relay shares within a window,
+// deduplication of repeated deliveries, and skipping of unknown relays.
+func TestComputeRelayConcentration(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), RelayURL: "https://a"},
+		{Slot: 2, ValueWei: big.NewInt(100), RelayURL: "https://a"},
+		{Slot: 2, ValueWei: big.NewInt(100), RelayURL: "https://a"}, // Overlapping snapshot
+		{Slot: 3, ValueWei: big.NewInt(100), RelayURL: "https://b"},
+		{Slot: 4, ValueWei: big.NewInt(100), RelayURL: "https://c"},
+		{Slot: 5, ValueWei: big.NewInt(100)}, // No relay data
+		{Slot: 9, ValueWei: big.NewInt(100), RelayURL: "https://c"},
+	}
+
+	// Slots 1-5: a delivered 2 of 4 known payloads
+	alpha, stats, err := ComputeRelayConcentration(bribes, 1, 5, 1)
+	if err != nil {
+		t.Fatalf("ComputeRelayConcentration failed: %v", err)
+	}
+	if alpha != 0.5 {
+		t.Errorf("expected alpha=0.5, got %f", alpha)
+	}
+	if len(stats) != 3 || stats[0].RelayURL != "https://a" || stats[0].PayloadCount != 2 {
+		t.Fatalf("expected https://a first with 2 payloads among 3 relays, got %+v", stats)
+	}
+	if stats[1].RelayURL != "https://b" || stats[1].Share != 0.25 {
+		t.Errorf("expected https://b second with share 0.25, got %+v", stats[1])
+	}
+
+	// Unbounded window: c ties a with 2 payloads and sorts after it
+	alpha, stats, err = ComputeRelayConcentration(bribes, 0, 0, 2)
+	if err != nil {
+		t.Fatalf("ComputeRelayConcentration failed: %v", err)
+	}
+	if alpha != 0.8 || stats[1].RelayURL != "https://c" {
+		t.Errorf("expected alpha=0.8 with https://c second, got %f for %+v", alpha, stats)
+	}
+
+	if _, _, err := ComputeRelayConcentration(bribes, 5, 8, 1); err == nil {
+		t.Error("Expected error for window without relay data, got nil")
+	}
+	if _, _, err := ComputeRelayConcentration(bribes, 5, 1, 1); err == nil {
+		t.Error("Expected error for inverted window, got nil")
+	}
+}
+
+// TestCompareRelays verifies coverage, builder mix, disagreements and
+// deviations are computed per relay against the others' reports.
+func TestCompareRelays(t *testing.T) {
+	reports := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA", RelayURL: "https://a"},
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA", RelayURL: "https://b"},
+		{Slot: 2, ValueWei: big.NewInt(300), BuilderPubkey: "0xA", RelayURL: "https://a"},
+		{Slot: 2, ValueWei: big.NewInt(200), BuilderPubkey: "0xA", RelayURL: "https://b"},
+		{Slot: 2, ValueWei: big.NewInt(300), BuilderPubkey: "0xA", RelayURL: "https://a"}, // Overlapping snapshot
+		{Slot: 3, ValueWei: big.NewInt(100), BuilderPubkey: "0xB", RelayURL: "https://a"},
+		{Slot: 4, ValueWei: big.NewInt(100), BuilderPubkey: "0xC"}, // No relay data
+	}
+
+	comparison, err := CompareRelays(reports)
+	if err != nil {
+		t.Fatalf("CompareRelays failed: %v", err)
+	}
+	if comparison.Slots != 3 || comparison.MultiRelaySlots != 2 || comparison.DisputedSlots != 1 {
+		t.Errorf("expected 3 slots, 2 shared and 1 disputed, got %+v", comparison)
+	}
+	if len(comparison.Relays) != 2 || comparison.Relays[0].RelayURL != "https://a" {
+		t.Fatalf("expected https://a first of 2 relays, got %+v", comparison.Relays)
+	}
+
+	a, b := comparison.Relays[0], comparison.Relays[1]
+	if a.Slots != 3 || a.Coverage != 1 || a.MeanBidWei.Int64() != 166 {
+		t.Errorf("expected https://a to cover 3 slots with mean 166 wei, got %+v", a)
+	}
+	if a.UniqueBuilders != 2 || a.TopBuilder != "0xA" || a.TopBuilderShare != 2.0/3 {
+		t.Errorf("expected https://a's top builder 0xA at 2/3, got %+v", a)
+	}
+	if a.SharedSlots != 2 || a.Disagreements != 1 || a.DisagreementRate != 0.5 {
+		t.Errorf("expected https://a to disagree on 1 of 2 shared slots, got %+v", a)
+	}
+	// Slot 1 agrees, slot 2 is 100 wei above and below the other relay
+	if a.MeanDeviationWei.Int64() != 50 || b.MeanDeviationWei.Int64() != -50 {
+		t.Errorf("expected deviations of 50 and -50 wei, got %s and %s", a.MeanDeviationWei, b.MeanDeviationWei)
+	}
+	if b.Coverage != 2.0/3 || b.BuilderHHI != 1 {
+		t.Errorf("expected https://b to cover 2/3 with one builder, got %+v", b)
+	}
+
+	if _, err := CompareRelays(reports[6:]); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+	if _, err := CompareRelays([]SlotBribe{{Slot: 1, RelayURL: "https://a"}}); !errors.Is(err, ErrNilValue) {
+		t.Errorf("expected ErrNilValue, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// RepeatedAttackParams describes an attacker who repeats the attack every
+// IntervalSlots slots for up to Rounds rounds. Each round is priced like
+// AttackerProfit with the embedded ProfitParams, adjusted for the rounds
+// before it:
+//
+//   - Detection: round r is detected with probability
+//     d_r = min(1, InitialDetection + r · DetectionIncrease). A detected
+//     attack fails, and the game ends after any failed attack.
+//   - Reputation: every successful attack costs the cartel builders a
+//     fraction ReputationDecay of their market share, so the cartel's
+//     concentration after r successes is α_r = α · (1 - ReputationDecay)^r
+//     and the bribes it must pay rise accordingly.
+type RepeatedAttackParams struct {
+	ProfitParams
+
+	Rounds            int     // Maximum number of attacks
+	IntervalSlots     uint64  // Slots between the starts of consecutive rounds (at least Tau)
+	InitialDetection  float64 // d_0 ∈ [0, 1]
+	DetectionIncrease float64 // Added to the detection probability each round
+	ReputationDecay   float64 // Share of the cartel's market lost per successful attack, in [0, 1]
+}
+
+// RoundOutcome is one round of a repeated attack. Revenue, cost and profit
+// are nominal and conditional on the round being reached.
+type RoundOutcome struct {
+	Round            int
+	ReachProbability float64 // Probability every earlier round succeeded
+	Detection        float64 // d_r
+	SuccessProb      float64 // p · (1 - d_r)
+	Alpha            float64 // α_r
+	ExpectedRevenue  *big.Float
+	Cost             *big.Float
+	Profit           *big.Float
+}
+
+// RepeatedAttackResult contains the output of RepeatedAttackNPV.
+type RepeatedAttackResult struct {
+	Rounds        []RoundOutcome
+	OneShotProfit *big.Float // Profit of the first round alone
+	NPV           *big.Float // Σ reach_r · discounted round profit
+}
+
+// RepeatedAttackNPV computes the net present value of attacking
+// repeatedly:
+//
+//	NPV = Σ_r reach_r · (e^(-ρ·(t_r+τ)) · p(1-d_r)·V - e^(-ρ·t_r) · C_r)
+//
+// where ρ is the DiscountRate, round r starts at t_r = r · IntervalSlots,
+// reach_r is the probability all earlier rounds succeeded, and
+// C_r = (1 - α_r) · C_c + coordination + penalty. Rising detection and
+// eroding concentration make later rounds less attractive, so a strategy
+// that is profitable once need not be worth repeating; the NPV, not the
+// one-shot profit, is the rational comparison. Under params.InclusionLists
+// the factor 1 - α_r becomes the scenario's (see InclusionListScenario),
+// and α_r is scaled by 1 - params.SelfBuildProbability.
+func RepeatedAttackNPV(bribes []SlotBribe, params RepeatedAttackParams) (*RepeatedAttackResult, error) {
+	if params.Rounds < 1 {
+		return nil, fmt.Errorf("rounds must be at least 1, got %d", params.Rounds)
+	}
+	if params.Rounds > 1 && params.IntervalSlots < params.Tau {
+		return nil, fmt.Errorf("interval of %d slots is shorter than tau (%d)", params.IntervalSlots, params.Tau)
+	}
+	if params.InitialDetection < 0 || params.InitialDetection > 1 {
+		return nil, fmt.Errorf("%w: initial detection probability %f (must be in [0,1])", ErrInvalidProbability, params.InitialDetection)
+	}
+	if params.DetectionIncrease < 0 {
+		return nil, fmt.Errorf("detection increase cannot be negative")
+	}
+	if params.ReputationDecay < 0 || params.ReputationDecay > 1 {
+		return nil, fmt.Errorf("invalid reputation decay: %f (must be in [0,1])", params.ReputationDecay)
+	}
+
+	// The first round is the one-shot attack
+	first, err := AttackerProfit(bribes, params.ProfitParams)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := CensorshipCost(bribes, params.Tau)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute censorship cost: %w", err)
+	}
+	ccFloat := new(big.Float).SetInt(cc)
+	overhead := new(big.Float).Add(first.CoordinationCost, first.ExpectedPenalty)
+	revenue := first.ExpectedRevenue // p · V
+
+	result := &RepeatedAttackResult{
+		Rounds:        make([]RoundOutcome, 0, params.Rounds),
+		OneShotProfit: first.Profit,
+		NPV:           new(big.Float),
+	}
+	reach := 1.0
+	for r := 0; r < params.Rounds; r++ {
+		detection := math.Min(1, params.InitialDetection+float64(r)*params.DetectionIncrease)
+		alpha := first.Alpha * math.Pow(1-params.ReputationDecay, float64(r))
+
+		roundRevenue := new(big.Float).Mul(revenue, big.NewFloat(1-detection))
+		roundCost := new(big.Float).Mul(ccFloat, big.NewFloat(params.InclusionLists.costFactor(selfBuildAlpha(alpha, params.SelfBuildProbability))))
+		roundCost.Add(roundCost, overhead)
+
+		result.Rounds = append(result.Rounds, RoundOutcome{
+			Round:            r,
+			ReachProbability: reach,
+			Detection:        detection,
+			SuccessProb:      first.SuccessProb * (1 - detection),
+			Alpha:            alpha,
+			ExpectedRevenue:  roundRevenue,
+			Cost:             roundCost,
+			Profit:           new(big.Float).Sub(roundRevenue, roundCost),
+		})
+
+		start := uint64(r) * params.IntervalSlots
+		discounted := new(big.Float).Mul(roundRevenue, big.NewFloat(discountFactor(params.DiscountRate, start+params.Tau)))
+		discounted.Sub(discounted, new(big.Float).Mul(roundCost, big.NewFloat(discountFactor(params.DiscountRate, start))))
+		result.NPV.Add(result.NPV, discounted.Mul(discounted, big.NewFloat(reach)))
+
+		reach *= first.SuccessProb * (1 - detection)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestRepeatedAttackNPV verifies rising detection and reputation decay
+// erode the value of later rounds.
+func TestRepeatedAttackNPV(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(2000), BuilderPubkey: "0xB"},
+	}
+	params := RepeatedAttackParams{
+		ProfitParams:      ProfitParams{BridgeTVL: big.NewFloat(4000), SuccessProbability: 1, Tau: 2, TopK: 1},
+		Rounds:            3,
+		IntervalSlots:     2,
+		DetectionIncrease: 0.5,
+		ReputationDecay:   0.5,
+	}
+
+	result, err := RepeatedAttackNPV(bribes, params)
+	if err != nil {
+		t.Fatalf("RepeatedAttackNPV failed: %v", err)
+	}
+	if len(result.Rounds) != 3 {
+		t.Fatalf("expected 3 rounds, got %d", len(result.Rounds))
+	}
+
+	// C_c = 3000, α = 0.5 halving each round, d = 0, 0.5, 1
+	expected := []struct {
+		reach, alpha float64
+		profit       float64
+	}{
+		{1, 0.5, 2500},      // 4000 - 1500
+		{1, 0.25, -250},     // 2000 - 2250
+		{0.5, 0.125, -2625}, // 0 - 2625
+	}
+	for i, want := range expected {
+		round := result.Rounds[i]
+		if round.ReachProbability != want.reach || round.Alpha != want.alpha {
+			t.Errorf("round %d: expected reach %f and alpha %f, got %f and %f", i, want.reach, want.alpha, round.ReachProbability, round.Alpha)
+		}
+		if !floatEqual(round.Profit, big.NewFloat(want.profit), 0.01) {
+			t.Errorf("round %d: expected profit %f, got %s", i, want.profit, round.Profit.String())
+		}
+	}
+
+	// 2500 - 250 - 0.5·2625 = 937.5, below the one-shot 2500
+	if !floatEqual(result.NPV, big.NewFloat(937.5), 0.01) {
+		t.Errorf("expected NPV 937.5, got %s", result.NPV.String())
+	}
+	if !floatEqual(result.OneShotProfit, big.NewFloat(2500), 0.01) {
+		t.Errorf("expected one-shot profit 2500, got %s", result.OneShotProfit.String())
+	}
+}
+
+// TestRepeatedAttackNPV_Validation verifies invalid schedules are rejected.
+func TestRepeatedAttackNPV_Validation(t *testing.T) {
+	bribes := []SlotBribe{{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"}}
+	base := ProfitParams{BridgeTVL: big.NewFloat(4000), SuccessProbability: 1, Tau: 1, TopK: 1}
+
+	cases := []RepeatedAttackParams{
+		{ProfitParams: base, Rounds: 0},
+		{ProfitParams: ProfitParams{BridgeTVL: big.NewFloat(4000), SuccessProbability: 1, Tau: 2, TopK: 1}, Rounds: 2, IntervalSlots: 1},
+		{ProfitParams: base, Rounds: 1, InitialDetection: 1.5},
+		{ProfitParams: base, Rounds: 1, ReputationDecay: -0.1},
+	}
+	for i, params := range cases {
+		if _, err := RepeatedAttackNPV(bribes, params); err == nil {
+			t.Errorf("case %d: Expected error, got nil", i)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// BreakevenSensitivity describes how V* responds to each assumption behind
+// it. Writing C_c = τ · b̄ for the mean bid b̄,
+//
+//	V* = (1 - α) · τ · b̄ / p
+//
+// so the partial derivatives are analytic:
+//
+//	∂V*/∂p = -V*/p        elasticity -1
+//	∂V*/∂α = -τ · b̄ / p   elasticity -α / (1 - α)
+//	∂V*/∂b̄ = (1 - α) · τ / p  elasticity 1
+//
+// An elasticity is the percentage change in V* per percent change in the
+// input, which makes the three comparable.
+type BreakevenSensitivity struct {
+	Breakeven   *big.Float // V*
+	MeanBid     *big.Float // b̄ over the first τ slots
+	Alpha       float64
+	SuccessProb float64
+
+	DSuccessProb *big.Float // ∂V*/∂p
+	DAlpha       *big.Float // ∂V*/∂α
+	DMeanBid     *big.Float // ∂V*/∂b̄
+
+	ElasticitySuccessProb float64
+	ElasticityAlpha       float64 // -Inf at α = 1, where V* = 0
+	ElasticityMeanBid     float64
+
+	// Dominant names the input with the largest absolute elasticity:
+	// "p", "alpha" or "mean_bid"
+	Dominant string
+}
+
+// BreakevenSensitivities computes the sensitivity of FindBreakevenTVL's V*
+// to p, α and the mean bid, so a report can state which assumption
+// dominates the result.
+func BreakevenSensitivities(bribes []SlotBribe, successProb float64, tau uint64, topK int) (*BreakevenSensitivity, error) {
+	if tau < 1 {
+		return nil, fmt.Errorf("tau must be at least 1, got %d", tau)
+	}
+	breakeven, alpha, err := FindBreakevenTVL(bribes, successProb, tau, topK)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := CensorshipCost(bribes, tau)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute censorship cost: %w", err)
+	}
+
+	ccFloat := new(big.Float).SetInt(cc)
+	p := big.NewFloat(successProb)
+	tauFloat := new(big.Float).SetUint64(tau)
+
+	s := &BreakevenSensitivity{
+		Breakeven:   breakeven,
+		MeanBid:     new(big.Float).Quo(ccFloat, tauFloat),
+		Alpha:       alpha,
+		SuccessProb: successProb,
+
+		DSuccessProb: new(big.Float).Neg(new(big.Float).Quo(breakeven, p)),
+		DAlpha:       new(big.Float).Neg(new(big.Float).Quo(ccFloat, p)),
+		DMeanBid:     new(big.Float).Quo(new(big.Float).Mul(tauFloat, big.NewFloat(1-alpha)), p),
+
+		ElasticitySuccessProb: -1,
+		ElasticityAlpha:       math.Inf(-1),
+		ElasticityMeanBid:     1,
+	}
+	if alpha < 1 {
+		s.ElasticityAlpha = -alpha / (1 - alpha)
+	}
+
+	// Ties go to p, then α, in the order they are listed
+	s.Dominant = "p"
+	largest := math.Abs(s.ElasticitySuccessProb)
+	if math.Abs(s.ElasticityAlpha) > largest {
+		s.Dominant, largest = "alpha", math.Abs(s.ElasticityAlpha)
+	}
+	if math.Abs(s.ElasticityMeanBid) > largest {
+		s.Dominant = "mean_bid"
+	}
+
+	return s, nil
+}
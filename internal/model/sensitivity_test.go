@@ -0,0 +1,53 @@
+package model
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// TestBreakevenSensitivities verifies the analytic derivatives against
+// finite differences of FindBreakevenTVL.
+func TestBreakevenSensitivities(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(3000), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(2000), BuilderPubkey: "0xA"},
+		{Slot: 4, ValueWei: big.NewInt(2000), BuilderPubkey: "0xB"},
+	}
+
+	// C_c = 8000, α = 0.75, p = 0.5: V* = 4000
+	s, err := BreakevenSensitivities(bribes, 0.5, 4, 1)
+	if err != nil {
+		t.Fatalf("BreakevenSensitivities failed: %v", err)
+	}
+	if !floatEqual(s.Breakeven, big.NewFloat(4000), 0.01) || !floatEqual(s.MeanBid, big.NewFloat(2000), 0.01) {
+		t.Errorf("expected V* 4000 and mean bid 2000, got %s and %s", s.Breakeven.String(), s.MeanBid.String())
+	}
+
+	// ∂V*/∂p by central difference
+	h := 1e-6
+	up, _, _ := FindBreakevenTVL(bribes, 0.5+h, 4, 1)
+	down, _, _ := FindBreakevenTVL(bribes, 0.5-h, 4, 1)
+	numeric := new(big.Float).Sub(up, down)
+	numeric.Quo(numeric, big.NewFloat(2*h))
+	if !floatEqual(s.DSuccessProb, numeric, 0.01) {
+		t.Errorf("expected ∂V*/∂p ≈ %s, got %s", numeric.String(), s.DSuccessProb.String())
+	}
+
+	if !floatEqual(s.DAlpha, big.NewFloat(-16000), 0.01) {
+		t.Errorf("expected ∂V*/∂α -16000, got %s", s.DAlpha.String())
+	}
+	if !floatEqual(s.DMeanBid, big.NewFloat(2), 0.0001) {
+		t.Errorf("expected ∂V*/∂b̄ 2, got %s", s.DMeanBid.String())
+	}
+
+	// α-elasticity -0.75/0.25 = -3 dominates
+	if math.Abs(s.ElasticityAlpha+3) > 1e-9 || s.Dominant != "alpha" {
+		t.Errorf("expected alpha to dominate with elasticity -3, got %s with %f", s.Dominant, s.ElasticityAlpha)
+	}
+
+	if _, err := BreakevenSensitivities(bribes, 0, 4, 1); err == nil {
+		t.Error("Expected error for zero success probability, got nil")
+	}
+}
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SlidingConcentration maintains builder block counts over the most recent
+// window bribes, updating in constant time per slot. Rolling analyses
+// would otherwise recount every window from scratch, O(n·w) over n slots;
+// with this counter they are O(n).
+//
+// Builders are kept sorted by count: a count only changes by one, so a
+// builder moves by swapping with the first (or last) builder of its equal-
+// count block. α(top k) then reads the first k entries, and the sum of
+// squared counts gives the HHI directly. It is safe for concurrent use.
+type SlidingConcentration struct {
+	mu     sync.Mutex
+	window int
+	ring   []string // Builders of the window's bribes, oldest at next once full
+	next   int
+	filled int
+
+	counts map[string]uint64
+	order  []string       // Builders by descending count
+	pos    map[string]int // Index of each builder in order
+	start  map[uint64]int // First index in order of each count
+	end    map[uint64]int // Last index in order of each count
+	sumSq  uint64         // Σ count²
+}
+
+// NewSlidingConcentration returns a counter over windows of window slots.
+func NewSlidingConcentration(window int) (*SlidingConcentration, error) {
+	if window < 1 {
+		return nil, fmt.Errorf("window must be at least 1, got %d", window)
+	}
+	return &SlidingConcentration{
+		window: window,
+		ring:   make([]string, window),
+		counts: make(map[string]uint64),
+		pos:    make(map[string]int),
+		start:  make(map[uint64]int),
+		end:    make(map[uint64]int),
+	}, nil
+}
+
+// Add counts bribe's builder, evicting the oldest bribe once the window is
+// full.
+func (s *SlidingConcentration) Add(bribe SlotBribe) {
+	key := bribe.BuilderPubkey
+	// Handle empty builder pubkeys as ComputeBuilderConcentration does
+	if key == "" {
+		key = "unknown"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.filled == s.window {
+		s.decrement(s.ring[s.next])
+	} else {
+		s.filled++
+	}
+	s.ring[s.next] = key
+	s.next = (s.next + 1) % s.window
+	s.increment(key)
+}
+
+// Len returns the number of slots in the window, at most its size.
+func (s *SlidingConcentration) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.filled
+}
+
+// Alpha returns α, the share of the window's slots won by the top k
+// builders, as ComputeBuilderConcentration over the same slots.
+func (s *SlidingConcentration) Alpha(topK int) (float64, error) {
+	if topK < 1 {
+		return 0, fmt.Errorf("%w: must be at least 1, got %d", ErrInvalidTopK, topK)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.filled == 0 {
+		return 0, fmt.Errorf("%w: empty window", ErrInsufficientData)
+	}
+	var top uint64
+	for _, key := range s.order[:min(topK, len(s.order))] {
+		top += s.counts[key]
+	}
+	return float64(top) / float64(s.filled), nil
+}
+
+// HerfindahlIndex returns the block-share HHI of the window, as
+// HerfindahlIndex with ConcentrationByBlocks.
+func (s *SlidingConcentration) HerfindahlIndex() (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.filled == 0 {
+		return 0, fmt.Errorf("%w: empty window", ErrInsufficientData)
+	}
+	total := float64(s.filled)
+	return float64(s.sumSq) / (total * total), nil
+}
+
+// UniqueBuilders returns the number of distinct builders in the window.
+func (s *SlidingConcentration) UniqueBuilders() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.order)
+}
+
+// TopBuilders returns the k builders with the most blocks in the window.
+// Builders with equal counts are in no particular order.
+func (s *SlidingConcentration) TopBuilders(topK int) []BuilderStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make([]BuilderStats, 0, min(max(topK, 0), len(s.order)))
+	for _, key := range s.order[:cap(stats)] {
+		stats = append(stats, BuilderStats{BuilderPubkey: key, BlockCount: s.counts[key]})
+	}
+	return stats
+}
+
+func (s *SlidingConcentration) swap(i, j int) {
+	s.order[i], s.order[j] = s.order[j], s.order[i]
+	s.pos[s.order[i]] = i
+	s.pos[s.order[j]] = j
+}
+
+// increment moves key from its count block to the end of the next higher
+// one.
+func (s *SlidingConcentration) increment(key string) {
+	c := s.counts[key]
+	s.sumSq += 2*c + 1
+	s.counts[key] = c + 1
+
+	if c == 0 {
+		// New builders have the lowest count, so they go last
+		i := len(s.order)
+		s.order = append(s.order, key)
+		s.pos[key] = i
+		if _, ok := s.end[1]; ok {
+			s.end[1] = i
+		} else {
+			s.start[1], s.end[1] = i, i
+		}
+		return
+	}
+
+	j := s.start[c]
+	s.swap(s.pos[key], j)
+	if s.end[c] == j {
+		delete(s.start, c)
+		delete(s.end, c)
+	} else {
+		s.start[c] = j + 1
+	}
+	if _, ok := s.end[c+1]; ok {
+		s.end[c+1] = j
+	} else {
+		s.start[c+1], s.end[c+1] = j, j
+	}
+}
+
+// decrement moves key from its count block to the start of the next lower
+// one, dropping it when its count reaches zero.
+func (s *SlidingConcentration) decrement(key string) {
+	c := s.counts[key]
+	s.sumSq -= 2*c - 1
+
+	e := s.end[c]
+	s.swap(s.pos[key], e)
+	if s.start[c] == e {
+		delete(s.start, c)
+		delete(s.end, c)
+	} else {
+		s.end[c] = e - 1
+	}
+
+	if c == 1 {
+		// The count-1 block is last, so e is the final index
+		s.order = s.order[:e]
+		delete(s.pos, key)
+		delete(s.counts, key)
+		return
+	}
+	s.counts[key] = c - 1
+	if _, ok := s.start[c-1]; ok {
+		s.start[c-1] = e
+	} else {
+		s.start[c-1], s.end[c-1] = e, e
+	}
+}
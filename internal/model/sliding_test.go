@@ -0,0 +1,103 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// TestSlidingConcentration verifies α, HHI and builder counts match a
+// recount of every window of a random stream.
+func TestSlidingConcentration(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var bribes []SlotBribe
+	for i := 0; i < 400; i++ {
+		// Skewed builder choice so counts cross each other often
+		builder := fmt.Sprintf("0x%d", int(math.Sqrt(float64(rng.Intn(49)))))
+		if i%37 == 0 {
+			builder = ""
+		}
+		bribes = append(bribes, SlotBribe{Slot: uint64(i), ValueWei: big.NewInt(1), BuilderPubkey: builder})
+	}
+
+	for _, window := range []int{1, 7, 50} {
+		sc, err := NewSlidingConcentration(window)
+		if err != nil {
+			t.Fatalf("NewSlidingConcentration failed: %v", err)
+		}
+		for i, bribe := range bribes {
+			sc.Add(bribe)
+			slots := bribes[max(0, i+1-window) : i+1]
+			if sc.Len() != len(slots) {
+				t.Fatalf("window %d at %d: expected %d slots, got %d", window, i, len(slots), sc.Len())
+			}
+			for _, k := range []int{1, 3, 5} {
+				want, _, _ := ComputeBuilderConcentration(slots, k)
+				got, err := sc.Alpha(k)
+				if err != nil || got != want {
+					t.Fatalf("window %d at %d: expected alpha(top%d) %f, got %f (err %v)", window, i, k, want, got, err)
+				}
+			}
+			wantHHI, _ := HerfindahlIndex(slots, ConcentrationByBlocks)
+			if got, _ := sc.HerfindahlIndex(); math.Abs(got-wantHHI) > 1e-12 {
+				t.Fatalf("window %d at %d: expected HHI %f, got %f", window, i, wantHHI, got)
+			}
+			if got := sc.UniqueBuilders(); got != GetBuilderDiversity(slots) {
+				t.Fatalf("window %d at %d: expected %d builders, got %d", window, i, GetBuilderDiversity(slots), got)
+			}
+		}
+	}
+}
+
+// TestSlidingConcentration_TopBuilders verifies the ranking and edge cases.
+func TestSlidingConcentration_TopBuilders(t *testing.T) {
+	if _, err := NewSlidingConcentration(0); err == nil {
+		t.Error("Expected error for window 0, got nil")
+	}
+
+	sc, _ := NewSlidingConcentration(4)
+	if _, err := sc.Alpha(1); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData for an empty window, got %v", err)
+	}
+	for i, builder := range []string{"0xA", "0xB", "0xB", "0xC", "0xC", "0xC"} {
+		sc.Add(SlotBribe{Slot: uint64(i), BuilderPubkey: builder})
+	}
+	// Window holds B, C, C, C
+	top := sc.TopBuilders(5)
+	if len(top) != 2 || top[0].BuilderPubkey != "0xC" || top[0].BlockCount != 3 || top[1].BlockCount != 1 {
+		t.Errorf("expected 0xC with 3 blocks then 0xB with 1, got %+v", top)
+	}
+	if _, err := sc.Alpha(0); !errors.Is(err, ErrInvalidTopK) {
+		t.Errorf("expected ErrInvalidTopK, got %v", err)
+	}
+}
+
+// TestSlidingConcentration_Concurrent verifies readers and a writer can
+// share a counter (run with -race).
+func TestSlidingConcentration_Concurrent(t *testing.T) {
+	sc, _ := NewSlidingConcentration(10)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			sc.Add(SlotBribe{Slot: uint64(i), BuilderPubkey: fmt.Sprintf("0x%d", i%4)})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			sc.Alpha(2)
+			sc.HerfindahlIndex()
+		}
+	}()
+	wg.Wait()
+
+	if alpha, err := sc.Alpha(4); err != nil || alpha != 1 {
+		t.Errorf("expected alpha 1 for all builders, got %f (err %v)", alpha, err)
+	}
+}
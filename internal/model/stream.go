@@ -0,0 +1,101 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// BribeSource streams bribes in slot order to fn, stopping at and
+// returning the first error fn returns.
+//
+// Sources let cost functions run over ranges too large to hold in memory,
+// e.g. storage.SlotSource over a database query.
+type BribeSource func(fn func(SlotBribe) error) error
+
+// BribesFromSlice returns a BribeSource over an in-memory slice.
+func BribesFromSlice(bribes []SlotBribe) BribeSource {
+	return func(fn func(SlotBribe) error) error {
+		for _, bribe := range bribes {
+			if err := fn(bribe); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// errStopStream ends a stream early once a consumer has what it needs.
+var errStopStream = errors.New("stop stream")
+
+// CensorshipCostFrom is CensorshipCost over a streamed source.
+//
+// Only the first tau bribes are read; the rest of the source is not consumed.
+func CensorshipCostFrom(source BribeSource, tau uint64) (*big.Int, error) {
+	total := new(big.Int)
+	if tau == 0 {
+		return total, nil
+	}
+
+	var seen uint64
+	err := source(func(bribe SlotBribe) error {
+		if bribe.ValueWei == nil {
+			return fmt.Errorf("%w at index %d", ErrNilValue, seen)
+		}
+		total.Add(total, bribe.ValueWei)
+		seen++
+		if seen == tau {
+			return errStopStream
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopStream) {
+		return nil, err
+	}
+
+	if seen < tau {
+		return nil, fmt.Errorf("%w: need %d slots, have %d", ErrInsufficientData, tau, seen)
+	}
+	return total, nil
+}
+
+// EffectiveCensorshipCostFrom is EffectiveCensorshipCost over a streamed source.
+//
+// The source is read once: the first tau bribes are summed while builder
+// concentration is counted over the whole source, matching the slice
+// version. Memory use is proportional to the number of builders.
+func EffectiveCensorshipCostFrom(source BribeSource, tau uint64, topK int) (*big.Float, float64, error) {
+	cc := new(big.Int)
+	counter := newConcentrationCounter(func(bribe SlotBribe) (string, string) {
+		return bribe.BuilderPubkey, ""
+	})
+
+	var seen uint64
+	err := source(func(bribe SlotBribe) error {
+		if seen < tau {
+			if bribe.ValueWei == nil {
+				return fmt.Errorf("%w at index %d", ErrNilValue, seen)
+			}
+			cc.Add(cc, bribe.ValueWei)
+		}
+		seen++
+		counter.add(bribe)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to compute censorship cost: %w", err)
+	}
+	if seen < tau {
+		return nil, 0, fmt.Errorf("failed to compute censorship cost: %w: need %d slots, have %d", ErrInsufficientData, tau, seen)
+	}
+
+	alpha, _, err := counter.result(topK)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to compute concentration: %w", err)
+	}
+
+	// C_c^eff = (1 - α) * C_c
+	ccEff := new(big.Float).Mul(new(big.Float).SetInt(cc), big.NewFloat(1.0-alpha))
+
+	return ccEff, alpha, nil
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// TestCensorshipCostFrom_MatchesSlice verifies the streamed cost equals the slice cost.
+func TestCensorshipCostFrom_MatchesSlice(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "A"},
+		{Slot: 2, ValueWei: big.NewInt(200), BuilderPubkey: "B"},
+		{Slot: 3, ValueWei: big.NewInt(300), BuilderPubkey: "A"},
+	}
+
+	want, err := CensorshipCost(bribes, 2)
+	if err != nil {
+		t.Fatalf("CensorshipCost failed: %v", err)
+	}
+	got, err := CensorshipCostFrom(BribesFromSlice(bribes), 2)
+	if err != nil {
+		t.Fatalf("CensorshipCostFrom failed: %v", err)
+	}
+	if got.Cmp(want) != 0 {
+		t.Errorf("expected cost %s, got %s", want, got)
+	}
+}
+
+// TestCensorshipCostFrom_StopsEarly verifies only tau bribes are consumed.
+func TestCensorshipCostFrom_StopsEarly(t *testing.T) {
+	consumed := 0
+	source := func(fn func(SlotBribe) error) error {
+		for i := 0; i < 1000; i++ {
+			consumed++
+			if err := fn(SlotBribe{Slot: uint64(i), ValueWei: big.NewInt(1)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cost, err := CensorshipCostFrom(source, 10)
+	if err != nil {
+		t.Fatalf("CensorshipCostFrom failed: %v", err)
+	}
+	if cost.Int64() != 10 {
+		t.Errorf("expected cost 10, got %s", cost)
+	}
+	if consumed != 10 {
+		t.Errorf("expected 10 bribes consumed, got %d", consumed)
+	}
+}
+
+// TestCensorshipCostFrom_Errors verifies insufficient data and source errors surface.
+func TestCensorshipCostFrom_Errors(t *testing.T) {
+	bribes := []SlotBribe{{Slot: 1, ValueWei: big.NewInt(1)}}
+	if _, err := CensorshipCostFrom(BribesFromSlice(bribes), 5); err == nil {
+		t.Error("Expected error for insufficient data, got nil")
+	}
+
+	sourceErr := errors.New("connection reset")
+	failing := func(fn func(SlotBribe) error) error { return sourceErr }
+	if _, err := CensorshipCostFrom(failing, 1); !errors.Is(err, sourceErr) {
+		t.Errorf("expected source error, got %v", err)
+	}
+}
+
+// TestEffectiveCensorshipCostFrom_MatchesSlice verifies the single-pass
+// streamed computation matches EffectiveCensorshipCost.
+func TestEffectiveCensorshipCostFrom_MatchesSlice(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1e18), BuilderPubkey: "A"},
+		{Slot: 2, ValueWei: big.NewInt(2e18), BuilderPubkey: "A"},
+		{Slot: 3, ValueWei: big.NewInt(3e18), BuilderPubkey: "B"},
+		{Slot: 4, ValueWei: big.NewInt(4e18), BuilderPubkey: "C"},
+	}
+
+	wantCost, wantAlpha, err := EffectiveCensorshipCost(bribes, 3, 1)
+	if err != nil {
+		t.Fatalf("EffectiveCensorshipCost failed: %v", err)
+	}
+	gotCost, gotAlpha, err := EffectiveCensorshipCostFrom(BribesFromSlice(bribes), 3, 1)
+	if err != nil {
+		t.Fatalf("EffectiveCensorshipCostFrom failed: %v", err)
+	}
+
+	if gotAlpha != wantAlpha {
+		t.Errorf("expected alpha %f, got %f", wantAlpha, gotAlpha)
+	}
+	if !floatEqual(gotCost, wantCost, 1) {
+		t.Errorf("expected cost %s, got %s", wantCost.String(), gotCost.String())
+	}
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// InclusionBehavior is an assumption about which proposers would include
+// the censored transaction if left alone, e.g. because they build locally
+// or connect only to non-filtering relays. A proposer that would exclude
+// it anyway need not be bribed.
+//
+// The probability for a slot is looked up by ProposerPubkey, then by
+// ProposerFeeRecipient, and falls back to Default.
+type InclusionBehavior struct {
+	Default       float64            // For proposers not listed, including slots without proposer data
+	Proposers     map[string]float64 // By proposer pubkey
+	FeeRecipients map[string]float64 // By fee recipient, e.g. a staking pool
+}
+
+// probability returns the probability the proposer of bribe includes the
+// transaction, and whether it came from the proposer data.
+func (b InclusionBehavior) probability(bribe SlotBribe) (float64, bool) {
+	if p, ok := b.Proposers[bribe.ProposerPubkey]; ok && bribe.ProposerPubkey != "" {
+		return p, true
+	}
+	if p, ok := b.FeeRecipients[bribe.ProposerFeeRecipient]; ok && bribe.ProposerFeeRecipient != "" {
+		return p, true
+	}
+	return b.Default, false
+}
+
+// validate rejects probabilities outside [0, 1].
+func (b InclusionBehavior) validate() error {
+	check := func(what string, p float64) error {
+		if p < 0 || p > 1 {
+			return fmt.Errorf("%w: inclusion probability for %s: %f (must be in [0,1])", ErrInvalidProbability, what, p)
+		}
+		return nil
+	}
+	if err := check("default", b.Default); err != nil {
+		return err
+	}
+	for key, p := range b.Proposers {
+		if err := check(key, p); err != nil {
+			return err
+		}
+	}
+	for key, p := range b.FeeRecipients {
+		if err := check(key, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TargetedCost is the cost of censoring only the slots that need it.
+type TargetedCost struct {
+	Tau          uint64
+	FullCostWei  *big.Int   // C_c(τ), bribing every slot
+	BoundWei     *big.Int   // Σ b(t) over slots whose proposer might include
+	ExpectedWei  *big.Float // Σ P(include at t) · b(t)
+	SlotsToBribe uint64     // Slots with a non-zero inclusion probability
+	DefaultSlots uint64     // Slots priced by InclusionBehavior.Default
+}
+
+// TargetedCensorshipCost prices censorship of the first τ slots when only
+// the proposers who would include the transaction must be bribed:
+//
+//	C_c^target = Σ(t ≤ τ) P(proposer(t) includes) · b(t)
+//
+// A fraud proof is only at risk in slots whose proposer would include it,
+// so this is a cheaper bound than C_c. BoundWei is the cost of bribing
+// every slot with a non-zero inclusion probability, for an attacker who
+// cannot tell in advance which of those proposers will include.
+//
+// Proposer identity comes from relay traces; slots without it (e.g.
+// loaded from storage) fall back to behavior.Default and are counted in
+// DefaultSlots, so a result dominated by them rests on the default alone.
+func TargetedCensorshipCost(bribes []SlotBribe, tau uint64, behavior InclusionBehavior) (*TargetedCost, error) {
+	if err := behavior.validate(); err != nil {
+		return nil, err
+	}
+	if uint64(len(bribes)) < tau {
+		return nil, fmt.Errorf("%w: need %d slots, have %d", ErrInsufficientData, tau, len(bribes))
+	}
+
+	cost := &TargetedCost{
+		Tau:         tau,
+		FullCostWei: new(big.Int),
+		BoundWei:    new(big.Int),
+		ExpectedWei: new(big.Float),
+	}
+	for i := uint64(0); i < tau; i++ {
+		bribe := bribes[i]
+		if bribe.ValueWei == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilValue, i)
+		}
+		cost.FullCostWei.Add(cost.FullCostWei, bribe.ValueWei)
+
+		p, known := behavior.probability(bribe)
+		if !known {
+			cost.DefaultSlots++
+		}
+		if p == 0 {
+			continue
+		}
+		cost.SlotsToBribe++
+		cost.BoundWei.Add(cost.BoundWei, bribe.ValueWei)
+		weighted := new(big.Float).SetInt(bribe.ValueWei)
+		cost.ExpectedWei.Add(cost.ExpectedWei, weighted.Mul(weighted, big.NewFloat(p)))
+	}
+
+	return cost, nil
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestTargetedCensorshipCost verifies only includers' slots are priced,
+// looked up by pubkey, then fee recipient, then the default.
+func TestTargetedCensorshipCost(t *testing.T) {
+	bribes := []SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1000), ProposerPubkey: "0xP1"},
+		{Slot: 2, ValueWei: big.NewInt(2000), ProposerPubkey: "0xP2", ProposerFeeRecipient: "0xPool"},
+		{Slot: 3, ValueWei: big.NewInt(3000), ProposerPubkey: "0xP3", ProposerFeeRecipient: "0xPool"},
+		{Slot: 4, ValueWei: big.NewInt(4000)},
+	}
+	behavior := InclusionBehavior{
+		Default:       0.5,
+		Proposers:     map[string]float64{"0xP1": 1, "0xP3": 0.25},
+		FeeRecipients: map[string]float64{"0xPool": 0},
+	}
+
+	cost, err := TargetedCensorshipCost(bribes, 4, behavior)
+	if err != nil {
+		t.Fatalf("TargetedCensorshipCost failed: %v", err)
+	}
+	if cost.FullCostWei.Int64() != 10000 {
+		t.Errorf("expected full cost 10000, got %s", cost.FullCostWei.String())
+	}
+
+	// Slot 2's pool never includes; slot 3's pubkey overrides the pool
+	if cost.SlotsToBribe != 3 || cost.BoundWei.Int64() != 8000 {
+		t.Errorf("expected 3 slots bounded at 8000, got %d at %s", cost.SlotsToBribe, cost.BoundWei.String())
+	}
+	// 1000 + 0.25·3000 + 0.5·4000 = 3750
+	if !floatEqual(cost.ExpectedWei, big.NewFloat(3750), 0.01) {
+		t.Errorf("expected cost 3750, got %s", cost.ExpectedWei.String())
+	}
+	if cost.DefaultSlots != 1 {
+		t.Errorf("expected 1 default-priced slot, got %d", cost.DefaultSlots)
+	}
+
+	behavior.Default = 1.5
+	if _, err := TargetedCensorshipCost(bribes, 4, behavior); err == nil {
+		t.Error("Expected error for a probability above 1, got nil")
+	}
+	if _, err := TargetedCensorshipCost(bribes, 5, InclusionBehavior{}); err == nil {
+		t.Error("Expected error for insufficient slots, got nil")
+	}
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// BridgeTVL is the total value locked in a bridge on one chain at a point
+// in time, as reported by a data source.
+//
+// Breakeven comparisons should use the TVL observed during the analyzed
+// slot range rather than today's value; bridges' TVL moves by multiples
+// over months.
+type BridgeTVL struct {
+	Bridge    string    // Bridge name, e.g. "wormhole"
+	Chain     string    // Chain the value is locked on, e.g. "ethereum"
+	Timestamp time.Time // Snapshot time (second precision)
+	TVLUSD    float64   // Total value locked in USD
+	Source    string    // Data provider, e.g. "defillama"
+}
@@ -1,61 +1,370 @@
 package relay
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"time"
+
+	"insolventbydesign/internal/model"
 )
 
+// deliveredPayloadsPath is the relay data API endpoint for delivered payloads.
+const deliveredPayloadsPath = "/relay/v1/data/bidtraces/proposer_payload_delivered"
+
+// receivedBidsPath is the relay data API endpoint for every builder
+// submission, delivered or not.
+const receivedBidsPath = "/relay/v1/data/bidtraces/builder_blocks_received"
+
+// maxPageSize is the largest page the relay data API returns per request.
+const maxPageSize = 200
+
 // Client represents an HTTP client for fetching relay data.
+//
+// The underlying http.Client (and its connection pool) is safe for
+// concurrent use and is shared by every fetch path, including clones
+// created with WithBaseURL.
 type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 }
 
-// NewClient creates a new relay client with the specified base URL.
+// ClientConfig configures the HTTP transport used by a relay Client.
+type ClientConfig struct {
+	Timeout             time.Duration // Overall per-request timeout
+	DialTimeout         time.Duration // TCP connect timeout
+	KeepAlive           time.Duration // TCP keep-alive period (negative disables)
+	MaxIdleConns        int           // Idle connections kept across all hosts
+	MaxIdleConnsPerHost int           // Idle connections kept per relay
+	MaxConnsPerHost     int           // Hard cap on connections per relay (0 = unlimited)
+	IdleConnTimeout     time.Duration // How long idle connections are kept
+	HTTP2               bool          // Attempt HTTP/2 over TLS
+	LogRequests         bool          // Log every request at debug level
+}
+
+// DefaultClientConfig returns defaults sized for the parallel fetcher's worker pool.
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		Timeout:             10 * time.Second,
+		DialTimeout:         5 * time.Second,
+		KeepAlive:           30 * time.Second,
+		MaxIdleConns:        200,
+		MaxIdleConnsPerHost: 64,
+		IdleConnTimeout:     90 * time.Second,
+		HTTP2:               true,
+	}
+}
+
+// NewClient creates a new relay client with the specified base URL
+// and default configuration.
 func NewClient(baseURL string) *Client {
+	return NewClientWithConfig(baseURL, DefaultClientConfig())
+}
+
+// NewClientWithConfig creates a relay client with a pooled, configurable transport.
+func NewClientWithConfig(baseURL string, config ClientConfig) *Client {
+	dialer := &net.Dialer{
+		Timeout:   config.DialTimeout,
+		KeepAlive: config.KeepAlive,
+	}
+
+	var transport http.RoundTripper = &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     config.HTTP2,
+		MaxIdleConns:          config.MaxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       config.MaxConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	if config.LogRequests {
+		transport = &loggingTransport{next: transport}
+	}
+
 	return &Client{
 		BaseURL: baseURL,
 		HTTPClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   config.Timeout,
+			Transport: transport,
 		},
 	}
 }
 
+// WithBaseURL returns a client for another relay that shares this
+// client's connection pool and configuration.
+func (c *Client) WithBaseURL(baseURL string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: c.HTTPClient,
+	}
+}
+
+// loggingTransport logs each request with its status and latency.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		Logger().Debug("relay http request failed",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"latency", time.Since(start),
+			"error", err)
+		return nil, err
+	}
+	Logger().Debug("relay http request",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"status", resp.StatusCode,
+		"proto", resp.Proto,
+		"latency", time.Since(start))
+	return resp, nil
+}
+
 type RelayBid struct {
 	Slot     string `json:"slot"`
 	ValueWei string `json:"value"`
 }
 
-func FetchAndStore(baseURL, outDir string) error {
-	endpoint := fmt.Sprintf(
-		"%s/relay/v1/data/bidtraces/proposer_payload_delivered",
-		baseURL,
-	)
+// FetchAndStore fetches the most recent delivered payloads from a relay
+// and writes the raw response to outDir. Returns the written file path.
+func FetchAndStore(baseURL, outDir string) (string, error) {
+	return NewClient(baseURL).FetchAndStore(context.Background(), outDir)
+}
+
+// FetchAndStore fetches the most recent delivered payloads from the relay
+// and writes the raw response to outDir. Returns the written file path.
+func (c *Client) FetchAndStore(ctx context.Context, outDir string) (string, error) {
+	endpoint := c.BaseURL + deliveredPayloadsPath
+
+	log := Logger().With("relay", c.BaseURL)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		log.Error("relay request failed", "error", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return "", err
 	}
 	var bids []RelayBid
 	if err := json.Unmarshal(body, &bids); err != nil {
-		return err
+		return "", err
 	}
 
 	ts := time.Now().Unix()
-	file := fmt.Sprintf("%s/%s_%d.json", outDir, sanitize(baseURL), ts)
+	file := fmt.Sprintf("%s/%s_%d.json", outDir, sanitize(c.BaseURL), ts)
+
+	log.Info("relay payloads fetched",
+		"status", resp.StatusCode,
+		"payloads", len(bids),
+		"bytes", len(body),
+		"latency", time.Since(start),
+		"file", file)
+
+	return file, os.WriteFile(file, body, 0644)
+}
+
+// FetchDeliveredPayloads fetches one page of delivered payloads whose slot
+// is at or below cursor, newest first.
+func (c *Client) FetchDeliveredPayloads(ctx context.Context, cursor uint64, limit int) ([]RelayBidTrace, error) {
+	query := url.Values{}
+	query.Set("cursor", strconv.FormatUint(cursor, 10))
+	query.Set("limit", strconv.Itoa(limit))
+	endpoint := c.BaseURL + deliveredPayloadsPath + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", c.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("relay %s returned status %d", c.BaseURL, resp.StatusCode)
+	}
+
+	var traces []RelayBidTrace
+	if err := json.NewDecoder(resp.Body).Decode(&traces); err != nil {
+		return nil, fmt.Errorf("failed to decode response from %s: %w", c.BaseURL, err)
+	}
+
+	Logger().Debug("relay page fetched",
+		"relay", c.BaseURL,
+		"cursor", cursor,
+		"payloads", len(traces),
+		"latency", time.Since(start))
+
+	return traces, nil
+}
+
+// FetchReceivedBids fetches every bid the relay received for slot.
+//
+// Relays return all submissions for a slot in one response when queried
+// by slot, so no paging is needed.
+func (c *Client) FetchReceivedBids(ctx context.Context, slot uint64) ([]ReceivedBidTrace, error) {
+	endpoint := c.BaseURL + receivedBidsPath + "?slot=" + strconv.FormatUint(slot, 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", c.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("relay %s returned status %d", c.BaseURL, resp.StatusCode)
+	}
+
+	var traces []ReceivedBidTrace
+	if err := json.NewDecoder(resp.Body).Decode(&traces); err != nil {
+		return nil, fmt.Errorf("failed to decode response from %s: %w", c.BaseURL, err)
+	}
+
+	Logger().Debug("relay bids fetched",
+		"relay", c.BaseURL,
+		"slot", slot,
+		"bids", len(traces),
+		"latency", time.Since(start))
+
+	return traces, nil
+}
+
+// FetchReceivedBidRange fetches and converts the received bids of every
+// slot in slotRange, calling fn once per slot that has bids so callers
+// can store them incrementally.
+func (c *Client) FetchReceivedBidRange(ctx context.Context, slotRange SlotRange, fn func([]model.ReceivedBid) error) error {
+	if slotRange.End < slotRange.Start {
+		return fmt.Errorf("invalid slot range: end %d < start %d", slotRange.End, slotRange.Start)
+	}
+
+	for slot := slotRange.Start; ; slot++ {
+		traces, err := c.FetchReceivedBids(ctx, slot)
+		if err != nil {
+			return fmt.Errorf("failed to fetch bids for slot %d: %w", slot, err)
+		}
+		if len(traces) > 0 {
+			bids, err := ConvertReceivedBids(traces, c.BaseURL)
+			if err != nil {
+				return fmt.Errorf("slot %d: %w", slot, err)
+			}
+			if err := fn(bids); err != nil {
+				return err
+			}
+		}
+		if slot == slotRange.End {
+			return nil
+		}
+	}
+}
+
+// FetchSlotRange pages backwards through the delivered payloads of a relay,
+// starting at slotRange.End, until slotRange.Start is reached.
+//
+// Returns every trace whose slot lies within the inclusive range.
+func (c *Client) FetchSlotRange(ctx context.Context, slotRange SlotRange) ([]RelayBidTrace, error) {
+	if slotRange.End < slotRange.Start {
+		return nil, fmt.Errorf("invalid slot range: end %d < start %d", slotRange.End, slotRange.Start)
+	}
+
+	collected := make([]RelayBidTrace, 0)
+	cursor := slotRange.End
+	for {
+		page, err := c.FetchDeliveredPayloads(ctx, cursor, maxPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		lowest := cursor
+		for _, trace := range page {
+			slot, err := strconv.ParseUint(trace.Slot, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid slot format '%s' from %s: %w", trace.Slot, c.BaseURL, err)
+			}
+			if slot < lowest {
+				lowest = slot
+			}
+			if slot >= slotRange.Start && slot <= slotRange.End {
+				collected = append(collected, trace)
+			}
+		}
+
+		// A short page means the relay has no older data
+		if len(page) < maxPageSize || lowest <= slotRange.Start || lowest == 0 {
+			break
+		}
+		cursor = lowest - 1
+	}
+
+	return collected, nil
+}
+
+// FetchRangeAndStore fetches all delivered payloads within slotRange from a
+// relay and writes them to outDir as a JSON array readable by ParseRelayFile.
+// Returns the written file path.
+func FetchRangeAndStore(ctx context.Context, baseURL, outDir string, slotRange SlotRange) (string, error) {
+	return NewClient(baseURL).FetchRangeAndStore(ctx, outDir, slotRange)
+}
+
+// FetchRangeAndStore fetches all delivered payloads within slotRange from the
+// relay and writes them to outDir as a JSON array readable by ParseRelayFile.
+// Returns the written file path.
+func (c *Client) FetchRangeAndStore(ctx context.Context, outDir string, slotRange SlotRange) (string, error) {
+	log := Logger().With("relay", c.BaseURL, "slot_start", slotRange.Start, "slot_end", slotRange.End)
+
+	start := time.Now()
+	traces, err := c.FetchSlotRange(ctx, slotRange)
+	if err != nil {
+		log.Error("relay range fetch failed", "error", err)
+		return "", err
+	}
+
+	body, err := json.Marshal(traces)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode traces: %w", err)
+	}
+
+	file := fmt.Sprintf("%s/%s_%d-%d.json", outDir, sanitize(c.BaseURL), slotRange.Start, slotRange.End)
+
+	log.Info("relay range fetched",
+		"payloads", len(traces),
+		"latency", time.Since(start),
+		"file", file)
 
-	return os.WriteFile(file, body, 0644)
+	return file, os.WriteFile(file, body, 0644)
 }
 
 func sanitize(s string) string {
@@ -0,0 +1,280 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// newPagingRelay serves delivered payloads for slots [lo, hi] using
+// cursor/limit pagination, newest first, like the real relay data API.
+func newPagingRelay(t *testing.T, lo, hi uint64) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != deliveredPayloadsPath {
+			http.NotFound(w, r)
+			return
+		}
+		cursor, err := strconv.ParseUint(r.URL.Query().Get("cursor"), 10, 64)
+		if err != nil || cursor > hi {
+			cursor = hi
+		}
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			limit = 100
+		}
+
+		page := make([]RelayBidTrace, 0, limit)
+		for slot := cursor; slot >= lo && len(page) < limit; slot-- {
+			page = append(page, RelayBidTrace{
+				Slot:          strconv.FormatUint(slot, 10),
+				Value:         strconv.FormatUint(slot*1000, 10),
+				BuilderPubkey: "0xbuilder",
+			})
+			if slot == 0 {
+				break
+			}
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+}
+
+// TestFetchSlotRange_Pagination verifies multi-page fetches return exactly the range.
+func TestFetchSlotRange_Pagination(t *testing.T) {
+	srv := newPagingRelay(t, 0, 2000)
+	defer srv.Close()
+
+	traces, err := NewClient(srv.URL).FetchSlotRange(context.Background(), SlotRange{Start: 1000, End: 1499})
+	if err != nil {
+		t.Fatalf("FetchSlotRange failed: %v", err)
+	}
+
+	if len(traces) != 500 {
+		t.Fatalf("Expected 500 traces, got %d", len(traces))
+	}
+	seen := make(map[string]bool)
+	for _, trace := range traces {
+		slot, _ := strconv.ParseUint(trace.Slot, 10, 64)
+		if slot < 1000 || slot > 1499 {
+			t.Errorf("Trace for slot %d outside requested range", slot)
+		}
+		if seen[trace.Slot] {
+			t.Errorf("Duplicate trace for slot %s", trace.Slot)
+		}
+		seen[trace.Slot] = true
+	}
+}
+
+// TestFetchSlotRange_InvalidRange verifies inverted ranges fail.
+func TestFetchSlotRange_InvalidRange(t *testing.T) {
+	_, err := NewClient("http://unused").FetchSlotRange(context.Background(), SlotRange{Start: 10, End: 5})
+	if err == nil {
+		t.Error("Expected error for inverted range, got nil")
+	}
+}
+
+// TestFetchReceivedBidRange verifies per-slot bid fetches are converted
+// with exact values and receive times, and empty slots are skipped.
+func TestFetchReceivedBidRange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != receivedBidsPath {
+			http.NotFound(w, r)
+			return
+		}
+		traces := []ReceivedBidTrace{}
+		if slot := r.URL.Query().Get("slot"); slot != "11" {
+			for _, v := range []string{"123456789012345678901234567890", "5"} {
+				traces = append(traces, ReceivedBidTrace{
+					RelayBidTrace: RelayBidTrace{Slot: slot, Value: v, BuilderPubkey: "0xA", BlockHash: "0x" + v},
+					TimestampMs:   "1700000000" + v[:1] + "00",
+				})
+			}
+		}
+		json.NewEncoder(w).Encode(traces)
+	}))
+	defer srv.Close()
+
+	var slots []uint64
+	err := NewClient(srv.URL).FetchReceivedBidRange(context.Background(), SlotRange{Start: 10, End: 12},
+		func(bids []model.ReceivedBid) error {
+			if len(bids) != 2 {
+				t.Fatalf("Expected 2 bids per slot, got %d", len(bids))
+			}
+			if bids[0].ValueWei.String() != "123456789012345678901234567890" || bids[0].RelayURL != srv.URL {
+				t.Errorf("Unexpected first bid: %+v", bids[0])
+			}
+			if !bids[0].ReceivedAt.Before(bids[1].ReceivedAt) {
+				t.Errorf("Expected bids ordered by receive time, got %v then %v", bids[0].ReceivedAt, bids[1].ReceivedAt)
+			}
+			slots = append(slots, bids[0].Slot)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("FetchReceivedBidRange failed: %v", err)
+	}
+	if len(slots) != 2 || slots[0] != 10 || slots[1] != 12 {
+		t.Errorf("Expected bids for slots 10 and 12, got %v", slots)
+	}
+}
+
+// TestFetchRangeAndStore_Parseable verifies stored range files round-trip through the parser.
+func TestFetchRangeAndStore_Parseable(t *testing.T) {
+	srv := newPagingRelay(t, 0, 300)
+	defer srv.Close()
+
+	tmpDir := t.TempDir()
+	file, err := FetchRangeAndStore(context.Background(), srv.URL, tmpDir, SlotRange{Start: 100, End: 109})
+	if err != nil {
+		t.Fatalf("FetchRangeAndStore failed: %v", err)
+	}
+	if filepath.Dir(file) != tmpDir {
+		t.Errorf("Expected file in %s, got %s", tmpDir, file)
+	}
+
+	bribes, err := ParseRelayFile(file)
+	if err != nil {
+		t.Fatalf("ParseRelayFile failed: %v", err)
+	}
+	if len(bribes) != 10 {
+		t.Fatalf("Expected 10 bribes, got %d", len(bribes))
+	}
+	if bribes[0].Slot != 100 || bribes[9].Slot != 109 {
+		t.Errorf("Expected slots 100..109, got %d..%d", bribes[0].Slot, bribes[9].Slot)
+	}
+}
+
+// TestRunManifest_RoundTrip verifies the resolved slot range is persisted.
+func TestRunManifest_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	m := &RunManifest{
+		Network:   "mainnet",
+		SlotRange: &SlotRange{Start: 10, End: 20},
+		Relays:    []string{"https://relay.example"},
+		Files:     []string{"a.json"},
+	}
+	if err := m.Write(path); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	loaded, err := ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if loaded.SlotRange == nil || loaded.SlotRange.Start != 10 || loaded.SlotRange.End != 20 {
+		t.Errorf("Expected slot range 10..20, got %+v", loaded.SlotRange)
+	}
+}
+
+// TestClient_WithBaseURLSharesPool verifies relay clones share one http.Client.
+func TestClient_WithBaseURLSharesPool(t *testing.T) {
+	base := NewClientWithConfig("", DefaultClientConfig())
+	a := base.WithBaseURL("https://relay-a.example")
+	b := base.WithBaseURL("https://relay-b.example")
+
+	if a.HTTPClient != b.HTTPClient || a.HTTPClient != base.HTTPClient {
+		t.Error("Expected clones to share the same http.Client")
+	}
+	if a.BaseURL != "https://relay-a.example" {
+		t.Errorf("Expected base URL to be set, got %s", a.BaseURL)
+	}
+}
+
+// TestClient_ConfigApplied verifies transport settings come from ClientConfig.
+func TestClient_ConfigApplied(t *testing.T) {
+	config := DefaultClientConfig()
+	config.Timeout = 3 * time.Second
+	config.MaxIdleConnsPerHost = 7
+	config.HTTP2 = false
+
+	c := NewClientWithConfig("https://relay.example", config)
+	if c.HTTPClient.Timeout != 3*time.Second {
+		t.Errorf("Expected timeout 3s, got %v", c.HTTPClient.Timeout)
+	}
+	transport, ok := c.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", c.HTTPClient.Transport)
+	}
+	if transport.MaxIdleConnsPerHost != 7 || transport.ForceAttemptHTTP2 {
+		t.Errorf("Transport not configured: idle/host=%d http2=%v",
+			transport.MaxIdleConnsPerHost, transport.ForceAttemptHTTP2)
+	}
+
+	config.LogRequests = true
+	logged := NewClientWithConfig("https://relay.example", config)
+	if _, ok := logged.HTTPClient.Transport.(*loggingTransport); !ok {
+		t.Errorf("Expected logging transport, got %T", logged.HTTPClient.Transport)
+	}
+}
+
+// TestClient_FetchAndStore verifies the latest-payload path uses the client.
+func TestClient_FetchAndStore(t *testing.T) {
+	srv := newPagingRelay(t, 0, 50)
+	defer srv.Close()
+
+	config := DefaultClientConfig()
+	config.LogRequests = true
+	file, err := NewClientWithConfig(srv.URL, config).FetchAndStore(context.Background(), t.TempDir())
+	if err != nil {
+		t.Fatalf("FetchAndStore failed: %v", err)
+	}
+
+	bribes, err := ParseRelayFile(file)
+	if err != nil {
+		t.Fatalf("ParseRelayFile failed: %v", err)
+	}
+	if len(bribes) == 0 {
+		t.Error("Expected stored payloads, got none")
+	}
+}
+
+// countingTransport counts the requests passed to the next transport.
+type countingTransport struct {
+	requests int
+	next     http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return t.next.RoundTrip(req)
+}
+
+// TestClient_LoadBuilderRegistry verifies registries served over HTTP are
+// fetched through the client's shared http.Client.
+func TestClient_LoadBuilderRegistry(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/labels.json" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(model.BuilderRegistryFile{
+			Entities: map[string][]string{"Titan": {"0xaa", "0xbb"}},
+		})
+	}))
+	defer srv.Close()
+
+	c := NewClient("")
+	counting := &countingTransport{next: c.HTTPClient.Transport}
+	c.HTTPClient.Transport = counting
+
+	registry, err := c.LoadBuilderRegistry(context.Background(), srv.URL+"/labels.json")
+	if err != nil {
+		t.Fatalf("LoadBuilderRegistry failed: %v", err)
+	}
+	if registry.Len() != 2 || registry.Entity("0xaa") != "Titan" {
+		t.Errorf("Expected 2 pubkeys labeled Titan, got %d and %q", registry.Len(), registry.Entity("0xaa"))
+	}
+	if counting.requests != 1 {
+		t.Errorf("Expected 1 request through the client, got %d", counting.requests)
+	}
+
+	if _, err := c.FetchBuilderRegistry(context.Background(), srv.URL+"/missing.json"); err == nil {
+		t.Error("Expected error for a missing registry, got nil")
+	}
+}
@@ -0,0 +1,69 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"insolventbydesign/internal/model"
+)
+
+// FetchBuilderRegistry downloads a builder label registry served as
+// model.BuilderRegistryFile JSON over the client's shared connection pool.
+func (c *Client) FetchBuilderRegistry(ctx context.Context, url string) (*model.BuilderRegistry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch builder registry from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("builder registry %s returned status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read builder registry from %s: %w", url, err)
+	}
+
+	registry, err := model.ParseBuilderRegistry(data)
+	if err != nil {
+		return nil, err
+	}
+
+	Logger().Info("builder registry loaded", "source", url, "pubkeys", registry.Len())
+	return registry, nil
+}
+
+// LoadBuilderRegistry loads a registry from an http(s) URL or a local file path
+// with a default client.
+func LoadBuilderRegistry(ctx context.Context, source string) (*model.BuilderRegistry, error) {
+	return NewClient("").LoadBuilderRegistry(ctx, source)
+}
+
+// LoadBuilderRegistry loads a registry from an http(s) URL, fetched with
+// the client, or a local file path.
+func (c *Client) LoadBuilderRegistry(ctx context.Context, source string) (*model.BuilderRegistry, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return c.FetchBuilderRegistry(ctx, source)
+	}
+	return model.LoadBuilderRegistry(source)
+}
+
+// ParseRelayDirectoryLabeled parses a relay directory like ParseRelayDirectory
+// and resolves every builder pubkey to its entity using registry.
+func ParseRelayDirectoryLabeled(dirpath string, registry *model.BuilderRegistry) ([]model.SlotBribe, error) {
+	bribes, err := ParseRelayDirectory(dirpath)
+	if err != nil {
+		return nil, err
+	}
+	registry.LabelBribes(bribes)
+	return bribes, nil
+}
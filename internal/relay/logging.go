@@ -0,0 +1,58 @@
+package relay
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+// logger is the package-wide structured logger.
+//
+// All relay operations (fetching, retries, progress reporting) emit
+// structured records through it so that long backfill jobs can be
+// monitored by log aggregators. Defaults to slog.Default().
+var logger atomic.Pointer[slog.Logger]
+
+func init() {
+	logger.Store(slog.Default())
+}
+
+// SetLogger replaces the logger used by the relay package.
+// Passing nil restores slog.Default().
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		l = slog.Default()
+	}
+	logger.Store(l)
+}
+
+// Logger returns the logger currently used by the relay package.
+func Logger() *slog.Logger {
+	return logger.Load()
+}
+
+// LogConfig configures structured log output.
+type LogConfig struct {
+	Level slog.Level // Minimum level emitted
+	JSON  bool       // Emit JSON records instead of key=value text
+}
+
+// NewLogger builds a structured logger writing to w.
+func NewLogger(w io.Writer, config LogConfig) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: config.Level}
+	if config.JSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// ParseLogLevel converts a level name (debug, info, warn, error) to a slog.Level.
+func ParseLogLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level '%s': %w", s, err)
+	}
+	return level, nil
+}
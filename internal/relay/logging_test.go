@@ -0,0 +1,65 @@
+package relay
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// TestParseLogLevel verifies level names map to slog levels.
+func TestParseLogLevel(t *testing.T) {
+	cases := map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	}
+
+	for name, expected := range cases {
+		level, err := ParseLogLevel(name)
+		if err != nil {
+			t.Fatalf("ParseLogLevel(%q) failed: %v", name, err)
+		}
+		if level != expected {
+			t.Errorf("ParseLogLevel(%q): expected %v, got %v", name, expected, level)
+		}
+	}
+
+	if _, err := ParseLogLevel("verbose"); err == nil {
+		t.Error("Expected error for unknown level, got nil")
+	}
+}
+
+// TestNewLogger_JSON verifies JSON output carries structured fields.
+func TestNewLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, LogConfig{Level: slog.LevelInfo, JSON: true})
+
+	l.Debug("suppressed")
+	l.Info("fetch progress", "relay", "https://relay.example", "worker_id", 3)
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a single JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "fetch progress" {
+		t.Errorf("Expected msg 'fetch progress', got %v", record["msg"])
+	}
+	if record["relay"] != "https://relay.example" {
+		t.Errorf("Expected relay field, got %v", record["relay"])
+	}
+	if record["worker_id"] != float64(3) {
+		t.Errorf("Expected worker_id 3, got %v", record["worker_id"])
+	}
+}
+
+// TestSetLogger_NilRestoresDefault verifies SetLogger(nil) falls back to slog.Default().
+func TestSetLogger_NilRestoresDefault(t *testing.T) {
+	SetLogger(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+	SetLogger(nil)
+
+	if Logger() != slog.Default() {
+		t.Error("Expected SetLogger(nil) to restore slog.Default()")
+	}
+}
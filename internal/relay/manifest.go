@@ -0,0 +1,49 @@
+package relay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RunManifest records what a fetch run requested and produced,
+// so that datasets can be traced back to their exact inputs.
+type RunManifest struct {
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt time.Time  `json:"finished_at"`
+	Network    string     `json:"network"`
+	From       *time.Time `json:"from,omitempty"`       // Requested start time (inclusive)
+	To         *time.Time `json:"to,omitempty"`         // Requested end time (exclusive)
+	SlotRange  *SlotRange `json:"slot_range,omitempty"` // Resolved inclusive slot range
+	Relays     []string   `json:"relays"`
+	Files      []string   `json:"files"`
+	Errors     []string   `json:"errors,omitempty"`
+
+	Pseudonymized bool `json:"pseudonymized"` // Pubkeys replaced with keyed anonymous IDs
+}
+
+// Write serializes the manifest as indented JSON to path.
+func (m *RunManifest) Write(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadManifest loads a manifest previously written with Write.
+func ReadManifest(path string) (*RunManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+	var m RunManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
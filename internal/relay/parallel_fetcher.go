@@ -3,6 +3,7 @@ package relay
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -48,8 +49,8 @@ func NewParallelFetcher(client *Client, config FetchConfig) *ParallelFetcher {
 
 // SlotRange represents a range of slots to fetch.
 type SlotRange struct {
-	Start uint64
-	End   uint64
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
 }
 
 // FetchResult contains fetched data and metadata.
@@ -67,6 +68,13 @@ func (f *ParallelFetcher) FetchSlotsParallel(ctx context.Context, slotRange Slot
 	startTime := time.Now()
 	totalSlots := slotRange.End - slotRange.Start + 1
 
+	log := Logger().With(
+		"relay", f.client.BaseURL,
+		"slot_start", slotRange.Start,
+		"slot_end", slotRange.End,
+	)
+	log.Info("starting parallel fetch", "slots", totalSlots, "workers", config.WorkerCount)
+
 	// Create work queue
 	slotQueue := make(chan uint64, totalSlots)
 	for slot := slotRange.Start; slot <= slotRange.End; slot++ {
@@ -89,6 +97,8 @@ func (f *ParallelFetcher) FetchSlotsParallel(ctx context.Context, slotRange Slot
 		go func(workerID int) {
 			defer wg.Done()
 
+			workerLog := log.With("worker_id", workerID)
+
 			for slot := range slotQueue {
 				select {
 				case <-ctx.Done():
@@ -100,8 +110,9 @@ func (f *ParallelFetcher) FetchSlotsParallel(ctx context.Context, slotRange Slot
 				<-f.rateLimiter
 
 				// Fetch with retry logic
-				bribe, err := f.fetchWithRetry(ctx, slot, config.RetryAttempts, config.RetryBackoff)
+				bribe, err := f.fetchWithRetry(ctx, workerLog, slot, config.RetryAttempts, config.RetryBackoff)
 				if err != nil {
+					workerLog.Warn("slot fetch failed", "slot", slot, "error", err)
 					errors <- slot
 					continue
 				}
@@ -116,8 +127,12 @@ func (f *ParallelFetcher) FetchSlotsParallel(ctx context.Context, slotRange Slot
 						pct := float64(processed) / float64(totalSlots) * 100
 						elapsed := time.Since(startTime)
 						rps := float64(processed) / elapsed.Seconds()
-						fmt.Printf("[Worker Pool] Progress: %d/%d (%.1f%%) | RPS: %.1f | Elapsed: %v\n",
-							processed, totalSlots, pct, rps, elapsed.Round(time.Second))
+						log.Info("fetch progress",
+							"processed", processed,
+							"total", totalSlots,
+							"percent", pct,
+							"rps", rps,
+							"elapsed", elapsed.Round(time.Second))
 					}
 					progressMu.Unlock()
 				}
@@ -144,6 +159,12 @@ func (f *ParallelFetcher) FetchSlotsParallel(ctx context.Context, slotRange Slot
 	duration := time.Since(startTime)
 	rps := float64(len(bribes)) / duration.Seconds()
 
+	log.Info("parallel fetch complete",
+		"fetched", len(bribes),
+		"failed", len(failedSlots),
+		"duration", duration,
+		"rps", rps)
+
 	return &FetchResult{
 		Bribes:        bribes,
 		TotalFetched:  uint64(len(bribes)),
@@ -154,7 +175,7 @@ func (f *ParallelFetcher) FetchSlotsParallel(ctx context.Context, slotRange Slot
 }
 
 // fetchWithRetry attempts to fetch a slot with exponential backoff.
-func (f *ParallelFetcher) fetchWithRetry(ctx context.Context, slot uint64, attempts int, backoff time.Duration) (model.SlotBribe, error) {
+func (f *ParallelFetcher) fetchWithRetry(ctx context.Context, log *slog.Logger, slot uint64, attempts int, backoff time.Duration) (model.SlotBribe, error) {
 	var lastErr error
 
 	for i := 0; i < attempts; i++ {
@@ -165,11 +186,20 @@ func (f *ParallelFetcher) fetchWithRetry(ctx context.Context, slot uint64, attem
 		}
 
 		// Simulate fetch (replace with actual HTTP call)
+		start := time.Now()
 		bribe, err := f.fetchSlot(ctx, slot)
+		latency := time.Since(start)
 		if err == nil {
+			log.Debug("slot fetched", "slot", slot, "attempt", i+1, "latency", latency)
 			return bribe, nil
 		}
 
+		log.Debug("slot fetch attempt failed",
+			"slot", slot,
+			"attempt", i+1,
+			"latency", latency,
+			"error", err)
+
 		lastErr = err
 		if i < attempts-1 {
 			time.Sleep(backoff * time.Duration(1<<i)) // Exponential backoff
@@ -201,9 +231,8 @@ func (f *ParallelFetcher) BatchFetchMultipleRelays(ctx context.Context, relayURL
 		go func(url string) {
 			defer wg.Done()
 
-			// Create client for this relay
-			client := NewClient(url)
-			fetcher := NewParallelFetcher(client, config)
+			// Reuse the shared connection pool for this relay
+			fetcher := NewParallelFetcher(f.client.WithBaseURL(url), config)
 
 			result, err := fetcher.FetchSlotsParallel(ctx, slotRange, config)
 			if err != nil {
@@ -1,11 +1,16 @@
 package relay
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"insolventbydesign/internal/model"
 )
@@ -26,6 +31,56 @@ type RelayBidTrace struct {
 	BlockNumber          string `json:"block_number"`
 }
 
+// ReceivedBidTrace is a single builder submission from the relay API.
+// This matches the schema of /relay/v1/data/bidtraces/builder_blocks_received
+type ReceivedBidTrace struct {
+	RelayBidTrace
+	Timestamp   string `json:"timestamp"`
+	TimestampMs string `json:"timestamp_ms"`
+}
+
+// ConvertReceivedBids converts received bid traces from relayURL into
+// model.ReceivedBid values, sorted by slot and then receive time.
+//
+// Conversion follows the same rules as ParseRelayFile: exact wei values
+// and a hard failure on any malformed trace.
+func ConvertReceivedBids(traces []ReceivedBidTrace, relayURL string) ([]model.ReceivedBid, error) {
+	bids := make([]model.ReceivedBid, 0, len(traces))
+	for i, trace := range traces {
+		bribe, err := convertTraceToBribe(trace.RelayBidTrace, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert bid at index %d: %w", i, err)
+		}
+
+		var receivedAt time.Time
+		if trace.TimestampMs != "" {
+			ms, err := strconv.ParseInt(trace.TimestampMs, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timestamp_ms '%s' at index %d: %w", trace.TimestampMs, i, err)
+			}
+			receivedAt = time.UnixMilli(ms).UTC()
+		}
+
+		bids = append(bids, model.ReceivedBid{
+			Slot:          bribe.Slot,
+			RelayURL:      relayURL,
+			BuilderPubkey: trace.BuilderPubkey,
+			BlockHash:     trace.BlockHash,
+			ValueWei:      bribe.ValueWei,
+			ReceivedAt:    receivedAt,
+		})
+	}
+
+	sort.SliceStable(bids, func(i, j int) bool {
+		if bids[i].Slot != bids[j].Slot {
+			return bids[i].Slot < bids[j].Slot
+		}
+		return bids[i].ReceivedAt.Before(bids[j].ReceivedAt)
+	})
+
+	return bids, nil
+}
+
 // ParseRelayFile loads a relay JSON file and extracts slot-level bribe data.
 //
 // This function is the PRIMARY data ingestion point for the entire project.
@@ -57,12 +112,14 @@ func ParseRelayFile(filepath string) ([]model.SlotBribe, error) {
 	}
 
 	// Convert to model.SlotBribe format
+	relayURL := relayFromFilename(filepath)
 	bribes := make([]model.SlotBribe, 0, len(traces))
 	for i, trace := range traces {
 		bribe, err := convertTraceToBribe(trace, i)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert trace at index %d: %w", i, err)
 		}
+		bribe.RelayURL = relayURL
 		bribes = append(bribes, bribe)
 	}
 
@@ -74,6 +131,21 @@ func ParseRelayFile(filepath string) ([]model.SlotBribe, error) {
 	return bribes, nil
 }
 
+// relayFromFilename recovers the relay URL from a file written by
+// FetchAndStore or FetchRangeAndStore, whose names start with the
+// hex-encoded base URL. It returns "" for files named otherwise.
+func relayFromFilename(path string) string {
+	prefix, _, ok := strings.Cut(filepath.Base(path), "_")
+	if !ok {
+		return ""
+	}
+	url, err := hex.DecodeString(prefix)
+	if err != nil || !strings.Contains(string(url), "://") {
+		return ""
+	}
+	return string(url)
+}
+
 // convertTraceToBribe extracts the minimal economic data from a relay trace.
 //
 // Critical conversion rules:
@@ -101,9 +173,11 @@ func convertTraceToBribe(trace RelayBidTrace, index int) (model.SlotBribe, error
 	}
 
 	return model.SlotBribe{
-		Slot:          slot,
-		ValueWei:      valueWei,
-		BuilderPubkey: trace.BuilderPubkey,
+		Slot:                 slot,
+		ValueWei:             valueWei,
+		BuilderPubkey:        trace.BuilderPubkey,
+		ProposerPubkey:       trace.ProposerPubkey,
+		ProposerFeeRecipient: trace.ProposerFeeRecipient,
 	}, nil
 }
 
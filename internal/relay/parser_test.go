@@ -74,6 +74,9 @@ func TestParseRelayFile_ValidData(t *testing.T) {
 	if bribes[0].BuilderPubkey != "0xbuilder1" {
 		t.Errorf("Expected builder 0xbuilder1, got %s", bribes[0].BuilderPubkey)
 	}
+	if bribes[0].ProposerPubkey != "0xproposer1" || bribes[0].ProposerFeeRecipient != "0xfee1" {
+		t.Errorf("Expected proposer 0xproposer1 paying 0xfee1, got %s paying %s", bribes[0].ProposerPubkey, bribes[0].ProposerFeeRecipient)
+	}
 
 	// Verify second bribe
 	if bribes[1].Slot != 1001 {
@@ -433,7 +436,7 @@ func TestParseRelayDirectory(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Create multiple test files
-	file1 := filepath.Join(tmpDir, "relay1.json")
+	file1 := filepath.Join(tmpDir, sanitize("https://relay-a")+"_1000.json") // Named as by FetchAndStore
 	file2 := filepath.Join(tmpDir, "relay2.json")
 
 	json1 := `[{"slot": "1000", "parent_hash": "0x0", "block_hash": "0x0", "builder_pubkey": "0xb1", "proposer_pubkey": "0xp", "proposer_fee_recipient": "0xf", "gas_limit": "30000000", "gas_used": "29000000", "value": "100", "block_number": "100"}]`
@@ -463,4 +466,9 @@ func TestParseRelayDirectory(t *testing.T) {
 	if len(bribes) == 2 && bribes[0].Slot > bribes[1].Slot {
 		t.Error("Directory parsing did not maintain global slot order")
 	}
+
+	// Relay provenance comes from the file name when it has one
+	if len(bribes) == 2 && (bribes[0].RelayURL != "https://relay-a" || bribes[1].RelayURL != "") {
+		t.Errorf("expected relays https://relay-a and \"\", got %q and %q", bribes[0].RelayURL, bribes[1].RelayURL)
+	}
 }
@@ -0,0 +1,105 @@
+package relay
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"insolventbydesign/internal/model"
+)
+
+// pseudonymPrefix marks identifiers produced by a Pseudonymizer.
+const pseudonymPrefix = "anon_"
+
+// Pseudonymizer replaces builder/proposer identities with stable anonymous IDs.
+//
+// IDs are derived with HMAC-SHA256 under a secret key, so:
+// - The same pubkey always maps to the same ID (concentration is preserved)
+// - Distinct pubkeys map to distinct IDs (up to 2^-64 collision probability)
+// - IDs cannot be reversed or recomputed without the key
+//
+// Empty identities are left empty so "unknown" builders stay unknown.
+type Pseudonymizer struct {
+	key []byte
+}
+
+// NewPseudonymizer creates a pseudonymizer from a secret key.
+//
+// Fails if the key is shorter than 16 bytes.
+func NewPseudonymizer(key []byte) (*Pseudonymizer, error) {
+	if len(key) < 16 {
+		return nil, fmt.Errorf("pseudonymization key must be at least 16 bytes, got %d", len(key))
+	}
+	return &Pseudonymizer{key: append([]byte(nil), key...)}, nil
+}
+
+// ID returns the anonymous identifier for an identity string.
+func (p *Pseudonymizer) ID(identity string) string {
+	if identity == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(identity))
+	return pseudonymPrefix + hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// Traces returns a copy of traces with builder, proposer and fee
+// recipient identities replaced by anonymous IDs.
+func (p *Pseudonymizer) Traces(traces []RelayBidTrace) []RelayBidTrace {
+	out := make([]RelayBidTrace, len(traces))
+	for i, trace := range traces {
+		trace.BuilderPubkey = p.ID(trace.BuilderPubkey)
+		trace.ProposerPubkey = p.ID(trace.ProposerPubkey)
+		trace.ProposerFeeRecipient = p.ID(trace.ProposerFeeRecipient)
+		out[i] = trace
+	}
+	return out
+}
+
+// Bribes returns a copy of bribes with builder, proposer and fee recipient
+// identities replaced by anonymous IDs.
+func (p *Pseudonymizer) Bribes(bribes []model.SlotBribe) []model.SlotBribe {
+	out := make([]model.SlotBribe, len(bribes))
+	for i, bribe := range bribes {
+		bribe.BuilderPubkey = p.ID(bribe.BuilderPubkey)
+		bribe.ProposerPubkey = p.ID(bribe.ProposerPubkey)
+		bribe.ProposerFeeRecipient = p.ID(bribe.ProposerFeeRecipient)
+		out[i] = bribe
+	}
+	return out
+}
+
+// Bids returns a copy of bids with builder identities replaced by anonymous IDs.
+func (p *Pseudonymizer) Bids(bids []model.ReceivedBid) []model.ReceivedBid {
+	out := make([]model.ReceivedBid, len(bids))
+	for i, bid := range bids {
+		bid.BuilderPubkey = p.ID(bid.BuilderPubkey)
+		out[i] = bid
+	}
+	return out
+}
+
+// PseudonymizeFile rewrites a relay JSON file with anonymized identities.
+//
+// inPath and outPath may be the same file.
+func PseudonymizeFile(inPath, outPath string, p *Pseudonymizer) error {
+	data, err := os.ReadFile(inPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", inPath, err)
+	}
+
+	var traces []RelayBidTrace
+	if err := json.Unmarshal(data, &traces); err != nil {
+		return fmt.Errorf("failed to parse JSON from %s: %w", inPath, err)
+	}
+
+	out, err := json.Marshal(p.Traces(traces))
+	if err != nil {
+		return fmt.Errorf("failed to encode traces: %w", err)
+	}
+
+	return os.WriteFile(outPath, out, 0644)
+}
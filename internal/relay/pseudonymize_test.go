@@ -0,0 +1,110 @@
+package relay
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+var testPseudonymKey = []byte("0123456789abcdef-test-key")
+
+// TestPseudonymizer_Deterministic verifies stable, distinct, keyed IDs.
+func TestPseudonymizer_Deterministic(t *testing.T) {
+	p, err := NewPseudonymizer(testPseudonymKey)
+	if err != nil {
+		t.Fatalf("NewPseudonymizer failed: %v", err)
+	}
+
+	a1 := p.ID("0xbuilderA")
+	a2 := p.ID("0xbuilderA")
+	b := p.ID("0xbuilderB")
+
+	if a1 != a2 {
+		t.Errorf("Expected stable ID, got %s and %s", a1, a2)
+	}
+	if a1 == b {
+		t.Errorf("Expected distinct IDs for distinct pubkeys, both %s", a1)
+	}
+	if !strings.HasPrefix(a1, pseudonymPrefix) || strings.Contains(a1, "builderA") {
+		t.Errorf("ID %s does not look anonymized", a1)
+	}
+	if p.ID("") != "" {
+		t.Error("Expected empty identity to stay empty")
+	}
+
+	other, _ := NewPseudonymizer([]byte("another-secret-key-xyz"))
+	if other.ID("0xbuilderA") == a1 {
+		t.Error("Expected different keys to produce different IDs")
+	}
+}
+
+// TestPseudonymizer_ShortKey verifies weak keys are rejected.
+func TestPseudonymizer_ShortKey(t *testing.T) {
+	if _, err := NewPseudonymizer([]byte("short")); err == nil {
+		t.Error("Expected error for short key, got nil")
+	}
+}
+
+// TestPseudonymizer_PreservesConcentration verifies α is unchanged by anonymization.
+func TestPseudonymizer_PreservesConcentration(t *testing.T) {
+	p, _ := NewPseudonymizer(testPseudonymKey)
+
+	bribes := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1), BuilderPubkey: "0xA", ProposerPubkey: "0xP"},
+		{Slot: 2, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(1), BuilderPubkey: "0xB"},
+		{Slot: 4, ValueWei: big.NewInt(1), BuilderPubkey: ""},
+	}
+
+	alphaRaw, _, _ := model.ComputeBuilderConcentration(bribes, 1)
+	anon := p.Bribes(bribes)
+	alphaAnon, _, _ := model.ComputeBuilderConcentration(anon, 1)
+
+	if alphaRaw != alphaAnon {
+		t.Errorf("Expected α %f, got %f after anonymization", alphaRaw, alphaAnon)
+	}
+	if bribes[0].BuilderPubkey != "0xA" {
+		t.Error("Input slice was modified")
+	}
+	if anon[3].BuilderPubkey != "" {
+		t.Error("Expected empty builder to stay empty")
+	}
+	if anon[0].ProposerPubkey != p.ID("0xP") {
+		t.Errorf("Expected proposer %s, got %s", p.ID("0xP"), anon[0].ProposerPubkey)
+	}
+}
+
+// TestPseudonymizeFile verifies files are rewritten without raw pubkeys.
+func TestPseudonymizeFile(t *testing.T) {
+	p, _ := NewPseudonymizer(testPseudonymKey)
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "relay.json")
+
+	raw := `[{"slot":"5","value":"42","builder_pubkey":"0xbuilder1","proposer_pubkey":"0xproposer1","proposer_fee_recipient":"0xfee1"}]`
+	if err := os.WriteFile(path, []byte(raw), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	if err := PseudonymizeFile(path, path, p); err != nil {
+		t.Fatalf("PseudonymizeFile failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	for _, secret := range []string{"0xbuilder1", "0xproposer1", "0xfee1"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Output still contains %s", secret)
+		}
+	}
+
+	bribes, err := ParseRelayFile(path)
+	if err != nil {
+		t.Fatalf("ParseRelayFile failed: %v", err)
+	}
+	if bribes[0].BuilderPubkey != p.ID("0xbuilder1") {
+		t.Errorf("Expected builder %s, got %s", p.ID("0xbuilder1"), bribes[0].BuilderPubkey)
+	}
+}
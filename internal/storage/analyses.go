@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// AnalysisQuery filters stored analyses.
+type AnalysisQuery struct {
+	StartSlot uint64 // Only analyses starting at or after this slot
+	EndSlot   uint64 // Only analyses ending at or before this slot; 0 means no bound
+	TopK      int    // Only analyses with this cartel size; 0 means any
+	Limit     int    // Maximum records to return; 0 means no limit
+}
+
+// storedBuilder is the persisted form of a top builder. It is decoupled
+// from model.BuilderStats so the stored JSON stays stable.
+type storedBuilder struct {
+	Pubkey     string `json:"pubkey"`
+	Entity     string `json:"entity,omitempty"`
+	BlockCount uint64 `json:"block_count"`
+}
+
+func encodeTopBuilders(stats []model.BuilderStats) (string, error) {
+	builders := make([]storedBuilder, len(stats))
+	for i, s := range stats {
+		builders[i] = storedBuilder{Pubkey: s.BuilderPubkey, Entity: s.Entity, BlockCount: s.BlockCount}
+	}
+	data, err := json.Marshal(builders)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode top builders: %w", err)
+	}
+	return string(data), nil
+}
+
+func decodeTopBuilders(data string) ([]model.BuilderStats, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var builders []storedBuilder
+	if err := json.Unmarshal([]byte(data), &builders); err != nil {
+		return nil, fmt.Errorf("failed to decode top builders: %w", err)
+	}
+	stats := make([]model.BuilderStats, len(builders))
+	for i, b := range builders {
+		stats[i] = model.BuilderStats{BuilderPubkey: b.Pubkey, Entity: b.Entity, BlockCount: b.BlockCount}
+	}
+	return stats, nil
+}
+
+// analysisColumns is the column list read by scanAnalysis.
+const analysisColumns = `start_slot, end_slot, duration_slots, total_cost_wei, total_cost_eth, total_cost_usd,
+	builder_concentration, top_k_builders, effective_cost_eth, breakeven_tvl_usd,
+	success_probability, computed_at, top_builders`
+
+// scanAnalysis reads one analysisColumns row. computedAt receives the
+// backend's timestamp representation and is converted by toTime.
+func scanAnalysis(row interface{ Scan(...interface{}) error }, computedAt interface{}, toTime func() time.Time) (AnalysisRecord, error) {
+	var record AnalysisRecord
+	var costWei, topBuilders string
+	var costUSD, breakevenUSD, successProb sql.NullFloat64
+
+	if err := row.Scan(&record.StartSlot, &record.EndSlot, &record.DurationSlots, &costWei,
+		&record.TotalCostETH, &costUSD, &record.BuilderConcentration, &record.TopKBuilders,
+		&record.EffectiveCostETH, &breakevenUSD, &successProb, computedAt, &topBuilders); err != nil {
+		return AnalysisRecord{}, err
+	}
+
+	wei, ok := new(big.Int).SetString(costWei, 10)
+	if !ok {
+		return AnalysisRecord{}, fmt.Errorf("invalid stored cost '%s'", costWei)
+	}
+	record.TotalCostWei = wei
+	record.TotalCostUSD = costUSD.Float64
+	record.BreakevenTVLUSD = breakevenUSD.Float64
+	record.SuccessProbability = successProb.Float64
+	record.ComputedAt = toTime()
+	record.TotalBlocks = record.DurationSlots // Only complete ranges are stored
+
+	stats, err := decodeTopBuilders(topBuilders)
+	if err != nil {
+		return AnalysisRecord{}, err
+	}
+	record.TopBuilders = stats
+
+	return record, nil
+}
+
+// analysisFilter renders the WHERE clause, arguments and LIMIT for q.
+func analysisFilter(q AnalysisQuery, ph func(n int) string) (where string, args []interface{}, limit string) {
+	args = append(args, q.StartSlot)
+	where = "start_slot >= " + ph(len(args))
+	if q.EndSlot > 0 {
+		args = append(args, q.EndSlot)
+		where += " AND end_slot <= " + ph(len(args))
+	}
+	if q.TopK > 0 {
+		args = append(args, q.TopK)
+		where += " AND top_k_builders = " + ph(len(args))
+	}
+	if q.Limit > 0 {
+		limit = " LIMIT " + strconv.Itoa(q.Limit)
+	}
+	return where, args, limit
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// receivedAtMillis maps a bid's receive time to Unix milliseconds, or NULL
+// when the relay did not report one.
+func receivedAtMillis(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UnixMilli()
+}
+
+// newReceivedBid builds a bid from stored columns; ms <= 0 means no
+// receive time was stored.
+func newReceivedBid(slot uint64, relayURL, builderPubkey, blockHash, valueWei string, ms int64) (model.ReceivedBid, error) {
+	value, ok := new(big.Int).SetString(valueWei, 10)
+	if !ok {
+		return model.ReceivedBid{}, fmt.Errorf("invalid stored bid value '%s' for slot %d", valueWei, slot)
+	}
+	bid := model.ReceivedBid{
+		Slot:          slot,
+		RelayURL:      relayURL,
+		BuilderPubkey: builderPubkey,
+		BlockHash:     blockHash,
+		ValueWei:      value,
+	}
+	if ms > 0 {
+		bid.ReceivedAt = time.UnixMilli(ms).UTC()
+	}
+	return bid, nil
+}
+
+// scanBidRows reads (slot_number, relay_url, builder_pubkey, block_hash,
+// value_wei, received_at_ms) rows.
+func scanBidRows(rows *sql.Rows) ([]model.ReceivedBid, error) {
+	defer rows.Close()
+
+	var bids []model.ReceivedBid
+	for rows.Next() {
+		var slot uint64
+		var relayURL, builderPubkey, blockHash, valueWei string
+		var ms sql.NullInt64
+		if err := rows.Scan(&slot, &relayURL, &builderPubkey, &blockHash, &valueWei, &ms); err != nil {
+			return nil, err
+		}
+		bid, err := newReceivedBid(slot, relayURL, builderPubkey, blockHash, valueWei, ms.Int64)
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+	return bids, rows.Err()
+}
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"insolventbydesign/internal/model"
+)
+
+// Builder is one row of the builders table.
+type Builder struct {
+	Pubkey    string
+	Entity    string   // Operating entity, "" if unlabeled
+	Labels    []string // Free-form tags, e.g. "ofac-compliant"
+	FirstSeen uint64   // First slot any relay reported the builder; 0 if never ingested
+	LastSeen  uint64   // Last slot any relay reported the builder
+}
+
+// BuilderLabelsFromRegistry converts a label registry into builders for
+// SaveBuilderLabels, sorted by pubkey.
+func BuilderLabelsFromRegistry(registry *model.BuilderRegistry) []Builder {
+	labels := registry.Labels()
+	builders := make([]Builder, 0, len(labels))
+	for pubkey, entity := range labels {
+		builders = append(builders, Builder{Pubkey: pubkey, Entity: entity})
+	}
+	sort.Slice(builders, func(i, j int) bool { return builders[i].Pubkey < builders[j].Pubkey })
+	return builders
+}
+
+// slotSpan is the first and last slot in which a builder appears.
+type slotSpan struct {
+	first, last uint64
+}
+
+// builderSpans returns the slot span of every builder in bribes.
+func builderSpans(bribes []model.SlotBribe) map[string]slotSpan {
+	spans := make(map[string]slotSpan)
+	for _, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			continue
+		}
+		span, ok := spans[bribe.BuilderPubkey]
+		if !ok {
+			span = slotSpan{first: bribe.Slot, last: bribe.Slot}
+		}
+		span.first = min(span.first, bribe.Slot)
+		span.last = max(span.last, bribe.Slot)
+		spans[bribe.BuilderPubkey] = span
+	}
+	return spans
+}
+
+func encodeLabels(labels []string) (string, error) {
+	if labels == nil {
+		labels = []string{}
+	}
+	data, err := json.Marshal(labels)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode builder labels: %w", err)
+	}
+	return string(data), nil
+}
+
+func decodeLabels(data string) ([]string, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var labels []string
+	if err := json.Unmarshal([]byte(data), &labels); err != nil {
+		return nil, fmt.Errorf("failed to decode builder labels: %w", err)
+	}
+	return labels, nil
+}
+
+// builderColumns is the column list read by scanBuilder.
+const builderColumns = `pubkey, COALESCE(entity_name, ''), COALESCE(first_seen, 0), COALESCE(last_seen, 0), labels`
+
+// scanBuilder reads one row selected with builderColumns.
+func scanBuilder(row interface{ Scan(...interface{}) error }) (Builder, error) {
+	var b Builder
+	var labels string
+	if err := row.Scan(&b.Pubkey, &b.Entity, &b.FirstSeen, &b.LastSeen, &labels); err != nil {
+		return Builder{}, err
+	}
+	var err error
+	if b.Labels, err = decodeLabels(labels); err != nil {
+		return Builder{}, err
+	}
+	return b, nil
+}
+
+// queryBuilder implements GetBuilder for database/sql backends.
+func queryBuilder(row *sql.Row) (Builder, error) {
+	b, err := scanBuilder(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Builder{}, ErrNotFound
+	}
+	if err != nil {
+		return Builder{}, fmt.Errorf("failed to get builder: %w", err)
+	}
+	return b, nil
+}
+
+// scanBuilderStats reads (builder_pubkey, block_count, entity,
+// total_value_wei, value_share, first_slot, last_slot) rows. A NULL total
+// leaves the value fields for the caller to fill in.
+func scanBuilderStats(rows *sql.Rows) ([]model.BuilderStats, error) {
+	defer rows.Close()
+
+	var stats []model.BuilderStats
+	for rows.Next() {
+		var s model.BuilderStats
+		var total sql.NullString
+		if err := rows.Scan(&s.BuilderPubkey, &s.BlockCount, &s.Entity, &total, &s.ValueShare, &s.FirstSeenSlot, &s.LastSeenSlot); err != nil {
+			return nil, err
+		}
+		if total.Valid {
+			value, ok := new(big.Int).SetString(total.String, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid total value '%s' for builder %s", total.String, s.BuilderPubkey)
+			}
+			setTotalValue(&s, value)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// setTotalValue sets a builder's total value and the mean bid derived from it.
+func setTotalValue(s *model.BuilderStats, total *big.Int) {
+	s.TotalValueWei = total
+	s.MeanBidWei = new(big.Int)
+	if s.BlockCount > 0 {
+		s.MeanBidWei.Quo(total, new(big.Int).SetUint64(s.BlockCount))
+	}
+}
+
+// BuilderConcentration is the top-k builder concentration of a slot range.
+type BuilderConcentration struct {
+	Alpha       float64              // Share of the range's blocks built by TopBuilders
+	TotalBlocks uint64               // Stored slots in the range
+	Builders    int                  // Distinct builders in the range
+	TopBuilders []model.BuilderStats // At most top-k, most blocks first; ties by pubkey
+}
+
+// newBuilderConcentration derives α from the top builders' block counts.
+func newBuilderConcentration(top []model.BuilderStats, totalBlocks uint64, builders int) BuilderConcentration {
+	c := BuilderConcentration{TotalBlocks: totalBlocks, Builders: builders, TopBuilders: top}
+	if totalBlocks > 0 {
+		var topBlocks uint64
+		for _, b := range top {
+			topBlocks += b.BlockCount
+		}
+		c.Alpha = float64(topBlocks) / float64(totalBlocks)
+	}
+	return c
+}
+
+// builderConcentration implements GetBuilderConcentration on top of a
+// windowed GetBuilderStats; count returns the range's stored slots and
+// distinct builders.
+func builderConcentration(ctx context.Context, store Store, startSlot, endSlot uint64, topK int, count func() (uint64, int, error)) (BuilderConcentration, error) {
+	if topK < 1 {
+		return BuilderConcentration{}, fmt.Errorf("%w: must be at least 1, got %d", model.ErrInvalidTopK, topK)
+	}
+	if startSlot > endSlot {
+		return BuilderConcentration{}, fmt.Errorf("start slot %d is after end slot %d", startSlot, endSlot)
+	}
+
+	totalBlocks, builders, err := count()
+	if err != nil {
+		return BuilderConcentration{}, fmt.Errorf("failed to count builders: %w", err)
+	}
+	if totalBlocks == 0 {
+		return BuilderConcentration{}, nil
+	}
+
+	top, err := store.GetBuilderStats(ctx, BuilderStatsQuery{StartSlot: startSlot, EndSlot: endSlot, Limit: topK})
+	if err != nil {
+		return BuilderConcentration{}, fmt.Errorf("failed to query top builders: %w", err)
+	}
+	return newBuilderConcentration(top, totalBlocks, builders), nil
+}
+
+// builderConcentrationSQL implements GetBuilderConcentration for
+// database/sql backends.
+func builderConcentrationSQL(ctx context.Context, store Store, db *sql.DB, ph func(n int) string, startSlot, endSlot uint64, topK int) (BuilderConcentration, error) {
+	return builderConcentration(ctx, store, startSlot, endSlot, topK, func() (uint64, int, error) {
+		var totalBlocks uint64
+		var builders int
+		err := db.QueryRowContext(ctx, `
+			SELECT COUNT(*), COUNT(DISTINCT builder_pubkey)
+			FROM slot_bribes
+			WHERE slot_number BETWEEN `+ph(1)+` AND `+ph(2), startSlot, endSlot).Scan(&totalBlocks, &builders)
+		return totalBlocks, builders, err
+	})
+}
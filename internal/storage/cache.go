@@ -0,0 +1,340 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// Cache is a byte-oriented key/value cache with per-entry TTLs.
+//
+// Implementations: MemoryCache (in-process) and RedisCache (shared
+// between API replicas).
+type Cache interface {
+	// Get returns the value for key and whether it was present.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value under key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Close releases cache resources.
+	Close() error
+}
+
+// CacheTTLs controls how long each kind of query result is cached.
+// A zero TTL disables caching for that query.
+type CacheTTLs struct {
+	SlotRange    time.Duration // Bribes for a slot range
+	BuilderStats time.Duration // Builder stats pages
+	Analysis     time.Duration // Stored analysis lookups
+}
+
+// DefaultCacheTTLs suits a dashboard polling the API: stored analyses
+// change only when their range is rewritten, while builder stats move with
+// ingestion.
+func DefaultCacheTTLs() CacheTTLs {
+	return CacheTTLs{
+		SlotRange:    5 * time.Minute,
+		BuilderStats: 30 * time.Second,
+		Analysis:     time.Hour,
+	}
+}
+
+// CachedStore wraps a Store with a cache-aside layer for hot read queries:
+// GetSlotRange, GetSlotRangeStats, GetBuilderStats, GetBuilderConcentration
+// and GetAnalysis. All other methods pass through to the underlying store.
+//
+// Writes made through the CachedStore invalidate this process's cached
+// slot ranges and builder stats; writes by other processes become visible
+// when entries expire. Cache failures fall back to the store.
+type CachedStore struct {
+	Store
+	cache      Cache
+	ttls       CacheTTLs
+	generation atomic.Uint64 // Bumped on writes to invalidate derived entries
+}
+
+// NewCachedStore wraps store with cache using the given TTLs.
+func NewCachedStore(store Store, cache Cache, ttls CacheTTLs) *CachedStore {
+	return &CachedStore{Store: store, cache: cache, ttls: ttls}
+}
+
+// cacheKeyPrefix namespaces keys and versions the encoded value format.
+const cacheKeyPrefix = "ibd:v1:"
+
+// BatchInsertBribes inserts through to the store and invalidates cached
+// slot ranges and builder stats.
+func (s *CachedStore) BatchInsertBribes(ctx context.Context, bribes []model.SlotBribe, relayURL string) (InsertReport, error) {
+	report, err := s.Store.BatchInsertBribes(ctx, bribes, relayURL)
+	s.generation.Add(1)
+	return report, err
+}
+
+// SaveBuilderLabels saves through to the store and invalidates cached
+// builder stats, which carry entity names.
+func (s *CachedStore) SaveBuilderLabels(ctx context.Context, builders []Builder) error {
+	err := s.Store.SaveBuilderLabels(ctx, builders)
+	s.generation.Add(1)
+	return err
+}
+
+// GetSlotRange returns cached bribes for the range, loading them on a miss.
+func (s *CachedStore) GetSlotRange(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	key := fmt.Sprintf("%sg%d:slots:%d:%d", cacheKeyPrefix, s.generation.Load(), startSlot, endSlot)
+	var bribes []model.SlotBribe
+	err := s.cached(ctx, key, s.ttls.SlotRange, &bribes, func() (interface{}, error) {
+		return s.Store.GetSlotRange(ctx, startSlot, endSlot)
+	})
+	return bribes, err
+}
+
+// GetSlotRangeStats returns cached aggregates for the range, loading them
+// on a miss. They expire with slot ranges.
+func (s *CachedStore) GetSlotRangeStats(ctx context.Context, startSlot, endSlot uint64) (SlotRangeStats, error) {
+	key := fmt.Sprintf("%sg%d:rangestats:%d:%d", cacheKeyPrefix, s.generation.Load(), startSlot, endSlot)
+	var stats SlotRangeStats
+	err := s.cached(ctx, key, s.ttls.SlotRange, &stats, func() (interface{}, error) {
+		return s.Store.GetSlotRangeStats(ctx, startSlot, endSlot)
+	})
+	return stats, err
+}
+
+// GetBuilderStats returns a cached page of builder stats, loading it on a miss.
+func (s *CachedStore) GetBuilderStats(ctx context.Context, query BuilderStatsQuery) ([]model.BuilderStats, error) {
+	key := fmt.Sprintf("%sg%d:builders:%s:%d:%d:%d:%d", cacheKeyPrefix, s.generation.Load(),
+		query.SortBy, query.Limit, query.Offset, query.StartSlot, query.EndSlot)
+	var stats []model.BuilderStats
+	err := s.cached(ctx, key, s.ttls.BuilderStats, &stats, func() (interface{}, error) {
+		return s.Store.GetBuilderStats(ctx, query)
+	})
+	return stats, err
+}
+
+// GetBuilderConcentration returns cached concentration for the range,
+// loading it on a miss. It expires with builder stats.
+func (s *CachedStore) GetBuilderConcentration(ctx context.Context, startSlot, endSlot uint64, topK int) (BuilderConcentration, error) {
+	key := fmt.Sprintf("%sg%d:concentration:%d:%d:%d", cacheKeyPrefix, s.generation.Load(), startSlot, endSlot, topK)
+	var concentration BuilderConcentration
+	err := s.cached(ctx, key, s.ttls.BuilderStats, &concentration, func() (interface{}, error) {
+		return s.Store.GetBuilderConcentration(ctx, startSlot, endSlot, topK)
+	})
+	return concentration, err
+}
+
+// GetAnalysis returns a cached stored analysis. Misses (ErrNotFound) are
+// not cached, so a later SaveAnalysis is seen immediately.
+func (s *CachedStore) GetAnalysis(ctx context.Context, startSlot, endSlot uint64, topK int) (AnalysisRecord, error) {
+	var record AnalysisRecord
+	err := s.cached(ctx, analysisCacheKey(startSlot, endSlot, topK), s.ttls.Analysis, &record, func() (interface{}, error) {
+		return s.Store.GetAnalysis(ctx, startSlot, endSlot, topK)
+	})
+	return record, err
+}
+
+// SaveAnalysis writes through to the store and the cache.
+func (s *CachedStore) SaveAnalysis(ctx context.Context, record AnalysisRecord) error {
+	if err := s.Store.SaveAnalysis(ctx, record); err != nil {
+		return err
+	}
+	if s.ttls.Analysis > 0 {
+		if data, err := json.Marshal(record); err == nil {
+			s.cache.Set(ctx, analysisCacheKey(record.StartSlot, record.EndSlot, record.TopKBuilders), data, s.ttls.Analysis)
+		}
+	}
+	return nil
+}
+
+// Health reports the wrapped store's health; it is never cached.
+func (s *CachedStore) Health(ctx context.Context) (Health, error) {
+	return CheckHealth(ctx, s.Store)
+}
+
+// SubscribeSlots subscribes to the wrapped store's slot notifications.
+func (s *CachedStore) SubscribeSlots(ctx context.Context) (<-chan SlotNotification, error) {
+	return SubscribeSlots(ctx, s.Store)
+}
+
+// EnqueueJob enqueues on the wrapped store's job queue.
+func (s *CachedStore) EnqueueJob(ctx context.Context, kind string, params json.RawMessage) (Job, error) {
+	queue, err := Jobs(s.Store)
+	if err != nil {
+		return Job{}, err
+	}
+	return queue.EnqueueJob(ctx, kind, params)
+}
+
+// ClaimJob claims from the wrapped store's job queue.
+func (s *CachedStore) ClaimJob(ctx context.Context, workerID string) (Job, error) {
+	queue, err := Jobs(s.Store)
+	if err != nil {
+		return Job{}, err
+	}
+	return queue.ClaimJob(ctx, workerID)
+}
+
+// CompleteJob completes a job in the wrapped store's job queue.
+func (s *CachedStore) CompleteJob(ctx context.Context, id int64, workerID, resultRef string, jobErr error) error {
+	queue, err := Jobs(s.Store)
+	if err != nil {
+		return err
+	}
+	return queue.CompleteJob(ctx, id, workerID, resultRef, jobErr)
+}
+
+// GetJob reads a job from the wrapped store's job queue; jobs are never
+// cached.
+func (s *CachedStore) GetJob(ctx context.Context, id int64) (Job, error) {
+	queue, err := Jobs(s.Store)
+	if err != nil {
+		return Job{}, err
+	}
+	return queue.GetJob(ctx, id)
+}
+
+// RequeueStaleJobs requeues stale jobs in the wrapped store's job queue.
+func (s *CachedStore) RequeueStaleJobs(ctx context.Context, startedBefore time.Time) (int64, error) {
+	queue, err := Jobs(s.Store)
+	if err != nil {
+		return 0, err
+	}
+	return queue.RequeueStaleJobs(ctx, startedBefore)
+}
+
+// Close closes the cache and the underlying store.
+func (s *CachedStore) Close() error {
+	cacheErr := s.cache.Close()
+	if err := s.Store.Close(); err != nil {
+		return err
+	}
+	return cacheErr
+}
+
+func analysisCacheKey(startSlot, endSlot uint64, topK int) string {
+	return fmt.Sprintf("%sanalysis:%d:%d:%d", cacheKeyPrefix, startSlot, endSlot, topK)
+}
+
+// cached implements cache-aside: decode a hit into dst, or call load,
+// store its JSON encoding for ttl and copy it into dst.
+func (s *CachedStore) cached(ctx context.Context, key string, ttl time.Duration, dst interface{}, load func() (interface{}, error)) error {
+	if ttl > 0 {
+		if data, ok, err := s.cache.Get(ctx, key); err == nil && ok {
+			if json.Unmarshal(data, dst) == nil {
+				return nil
+			}
+		}
+	}
+
+	value, err := load()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cached value: %w", err)
+	}
+	if ttl > 0 {
+		s.cache.Set(ctx, key, data, ttl)
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// MemoryCache is an in-process Cache bounded to a maximum number of entries.
+type MemoryCache struct {
+	mu         sync.Mutex
+	entries    map[string]memoryEntry
+	maxEntries int
+	now        func() time.Time
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// DefaultMemoryCacheEntries bounds MemoryCache when no size is given.
+const DefaultMemoryCacheEntries = 10_000
+
+// NewMemoryCache creates an in-process cache holding at most maxEntries
+// values (DefaultMemoryCacheEntries if maxEntries <= 0).
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryCacheEntries
+	}
+	return &MemoryCache{
+		entries:    make(map[string]memoryEntry),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Get returns the value for key if present and not expired.
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set stores value under key for ttl, evicting entries when full.
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.New("cache TTL must be positive")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = memoryEntry{value: value, expires: now.Add(ttl)}
+	return nil
+}
+
+// evict drops expired entries, or an arbitrary entry if none have expired.
+// Callers must hold c.mu.
+func (c *MemoryCache) evict(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) < c.maxEntries {
+		return
+	}
+	for key := range c.entries {
+		delete(c.entries, key)
+		return
+	}
+}
+
+// Len returns the number of stored entries, including expired ones not
+// yet evicted.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Close drops all entries.
+func (c *MemoryCache) Close() error {
+	c.mu.Lock()
+	c.entries = make(map[string]memoryEntry)
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// countingStore counts GetSlotRange calls reaching the underlying store.
+type countingStore struct {
+	Store
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *countingStore) GetSlotRange(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	return s.Store.GetSlotRange(ctx, startSlot, endSlot)
+}
+
+// TestMemoryCache_Expiry verifies entries expire after their TTL.
+func TestMemoryCache_Expiry(t *testing.T) {
+	cache := NewMemoryCache(10)
+	now := time.Unix(1000, 0)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, "k", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if v, ok, _ := cache.Get(ctx, "k"); !ok || string(v) != "v" {
+		t.Fatalf("expected hit with value v, got %q ok=%v", v, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := cache.Get(ctx, "k"); ok {
+		t.Error("expected entry to expire")
+	}
+}
+
+// TestMemoryCache_Bounded verifies the cache never exceeds maxEntries.
+func TestMemoryCache_Bounded(t *testing.T) {
+	cache := NewMemoryCache(3)
+	ctx := context.Background()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		cache.Set(ctx, key, []byte(key), time.Hour)
+	}
+	if cache.Len() != 3 {
+		t.Errorf("expected 3 entries, got %d", cache.Len())
+	}
+	if _, ok, _ := cache.Get(ctx, "e"); !ok {
+		t.Error("expected most recent entry to be present")
+	}
+}
+
+// TestCachedStore_SlotRange verifies hits skip the store and inserts invalidate.
+func TestCachedStore_SlotRange(t *testing.T) {
+	inner := &countingStore{Store: newTestSQLiteStore(t)}
+	store := NewCachedStore(inner, NewMemoryCache(0), DefaultCacheTTLs())
+	ctx := context.Background()
+
+	insert := func(slot uint64) {
+		t.Helper()
+		bribe := model.SlotBribe{Slot: slot, ValueWei: big.NewInt(int64(slot)), BuilderPubkey: "0xA"}
+		if _, err := store.BatchInsertBribes(ctx, []model.SlotBribe{bribe}, "relay"); err != nil {
+			t.Fatalf("BatchInsertBribes failed: %v", err)
+		}
+	}
+
+	insert(1)
+	for i := 0; i < 3; i++ {
+		bribes, err := store.GetSlotRange(ctx, 1, 2)
+		if err != nil {
+			t.Fatalf("GetSlotRange failed: %v", err)
+		}
+		if len(bribes) != 1 || bribes[0].ValueWei.Int64() != 1 {
+			t.Fatalf("Unexpected bribes: %+v", bribes)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("expected 1 store call, got %d", inner.calls)
+	}
+
+	insert(2)
+	bribes, err := store.GetSlotRange(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("GetSlotRange failed: %v", err)
+	}
+	if len(bribes) != 2 || inner.calls != 2 {
+		t.Errorf("expected fresh read of 2 bribes after insert, got %d bribes and %d calls", len(bribes), inner.calls)
+	}
+}
+
+// TestCachedStore_Analysis verifies write-through and uncached misses.
+func TestCachedStore_Analysis(t *testing.T) {
+	store := NewCachedStore(newTestSQLiteStore(t), NewMemoryCache(0), DefaultCacheTTLs())
+	ctx := context.Background()
+
+	if _, err := store.GetAnalysis(ctx, 1, 10, 3); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	record := AnalysisRecord{StartSlot: 1, EndSlot: 10, DurationSlots: 10, TotalCostWei: big.NewInt(42), TopKBuilders: 3}
+	if err := store.SaveAnalysis(ctx, record); err != nil {
+		t.Fatalf("SaveAnalysis failed: %v", err)
+	}
+
+	got, err := store.GetAnalysis(ctx, 1, 10, 3)
+	if err != nil {
+		t.Fatalf("GetAnalysis failed: %v", err)
+	}
+	if got.TotalCostWei.Int64() != 42 {
+		t.Errorf("expected cost 42, got %s", got.TotalCostWei)
+	}
+}
+
+// fakeRedis serves GET/SET/PING from a map over RESP.
+func fakeRedis(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	data := make(map[string]string)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					reply, err := readRESP(r)
+					if err != nil {
+						return
+					}
+					var args []string
+					for _, item := range reply.([]interface{}) {
+						args = append(args, string(item.([]byte)))
+					}
+					mu.Lock()
+					switch strings.ToUpper(args[0]) {
+					case "PING":
+						conn.Write([]byte("+PONG\r\n"))
+					case "SET":
+						data[args[1]] = args[2]
+						conn.Write([]byte("+OK\r\n"))
+					case "GET":
+						if v, ok := data[args[1]]; ok {
+							conn.Write([]byte("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"))
+						} else {
+							conn.Write([]byte("$-1\r\n"))
+						}
+					default:
+						conn.Write([]byte("-ERR unknown command\r\n"))
+					}
+					mu.Unlock()
+				}
+			}(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+// TestRedisCache_RoundTrip verifies RESP encoding against a fake server.
+func TestRedisCache_RoundTrip(t *testing.T) {
+	cache, err := NewRedisCache(RedisConfig{Addr: fakeRedis(t)})
+	if err != nil {
+		t.Fatalf("NewRedisCache failed: %v", err)
+	}
+	defer cache.Close()
+	ctx := context.Background()
+
+	if _, ok, err := cache.Get(ctx, "missing"); err != nil || ok {
+		t.Fatalf("expected miss, got ok=%v err=%v", ok, err)
+	}
+
+	value := []byte("line1\r\nline2 with spaces")
+	if err := cache.Set(ctx, "key", value, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	got, ok, err := cache.Get(ctx, "key")
+	if err != nil || !ok || string(got) != string(value) {
+		t.Errorf("expected %q, got %q ok=%v err=%v", value, got, ok, err)
+	}
+
+	if _, err := cache.do(ctx, "FLUSHALL"); err == nil {
+		t.Error("Expected error reply for unknown command, got nil")
+	}
+	// Error replies leave the connection usable
+	if _, ok, err := cache.Get(ctx, "key"); err != nil || !ok {
+		t.Errorf("expected hit after error reply, got ok=%v err=%v", ok, err)
+	}
+}
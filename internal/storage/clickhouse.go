@@ -0,0 +1,1243 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+)
+
+// DefaultClickHouseBatchSize is the number of rows sent per INSERT request.
+const DefaultClickHouseBatchSize = 100_000
+
+// ClickHouseStore provides columnar storage for multi-year, multi-relay datasets.
+//
+// It talks to ClickHouse over its HTTP interface, so no native driver is
+// required. Aggregations (builder stats, rolling windows) are pushed down
+// into ClickHouse instead of streaming raw rows to Go.
+//
+// Wei values are stored as UInt256, so sums computed in the database are exact.
+type ClickHouseStore struct {
+	endpoint   string
+	database   string
+	user       string
+	password   string
+	client     *http.Client
+	batchSize  int
+	onConflict ConflictPolicy
+}
+
+// NewClickHouseStore connects to the ClickHouse HTTP interface described by config.
+//
+// Port defaults to 8123; SSLMode other than "" or "disable" selects HTTPS.
+func NewClickHouseStore(config Config) (*ClickHouseStore, error) {
+	onConflict, err := ParseConflictPolicy(string(config.OnConflict))
+	if err != nil {
+		return nil, err
+	}
+
+	port := config.Port
+	if port == 0 {
+		port = 8123
+	}
+	scheme := "http"
+	if config.SSLMode != "" && config.SSLMode != "disable" {
+		scheme = "https"
+	}
+	database := config.Database
+	if database == "" {
+		database = "default"
+	}
+
+	s := &ClickHouseStore{
+		endpoint:   fmt.Sprintf("%s://%s:%d/", scheme, config.Host, port),
+		database:   database,
+		user:       config.User,
+		password:   config.Password,
+		client:     &http.Client{Timeout: 5 * time.Minute},
+		batchSize:  DefaultClickHouseBatchSize,
+		onConflict: onConflict,
+	}
+	s.SetBatchSize(config.BatchSize)
+
+	// Verify connection
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.exec(ctx, "SELECT 1", nil); err != nil {
+		return nil, fmt.Errorf("failed to ping clickhouse: %w", err)
+	}
+
+	return s, nil
+}
+
+// SetBatchSize overrides the number of rows sent per INSERT request.
+func (s *ClickHouseStore) SetBatchSize(n int) {
+	if n > 0 {
+		s.batchSize = n
+	}
+}
+
+// InitSchema creates the ClickHouse tables.
+//
+// slot_bribes uses ReplacingMergeTree keyed by slot so that duplicate
+// inserts racing past the pre-insert check collapse during merges;
+// reads use FINAL to see the deduplicated view.
+func (s *ClickHouseStore) InitSchema(ctx context.Context) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS slot_bribes (
+			slot_number UInt64,
+			slot_time DateTime('UTC'),
+			value_wei UInt256,
+			value_eth Float64,
+			builder_pubkey String,
+			block_hash String,
+			relay_url LowCardinality(String),
+			fetched_at DateTime('UTC') DEFAULT now()
+		)
+		ENGINE = ReplacingMergeTree
+		PARTITION BY toYYYYMM(slot_time)
+		ORDER BY slot_number`,
+
+		// Every relay's report; a relay re-reporting a slot replaces its earlier report
+		`CREATE TABLE IF NOT EXISTS relay_bribes (
+			slot_number UInt64,
+			slot_time DateTime('UTC'),
+			relay_url LowCardinality(String),
+			value_wei UInt256,
+			builder_pubkey String,
+			block_hash String,
+			fetched_at DateTime('UTC') DEFAULT now()
+		)
+		ENGINE = ReplacingMergeTree(fetched_at)
+		PARTITION BY toYYYYMM(slot_time)
+		ORDER BY (slot_number, relay_url)`,
+
+		`CREATE VIEW IF NOT EXISTS slot_relay_discrepancies AS
+		SELECT
+			slot_number,
+			count() AS relay_count,
+			uniqExact(value_wei) AS distinct_values,
+			uniqExact(builder_pubkey) AS distinct_builders
+		FROM relay_bribes FINAL
+		GROUP BY slot_number
+		HAVING distinct_values > 1 OR distinct_builders > 1`,
+
+		// Every bid a relay received; a block re-reported by the same relay
+		// collapses on merge
+		`CREATE TABLE IF NOT EXISTS received_bids (
+			slot_number UInt64,
+			slot_time DateTime('UTC'),
+			relay_url LowCardinality(String),
+			block_hash String,
+			builder_pubkey String,
+			value_wei UInt256,
+			received_at Nullable(DateTime64(3, 'UTC')),
+			fetched_at DateTime('UTC') DEFAULT now()
+		)
+		ENGINE = ReplacingMergeTree
+		PARTITION BY toYYYYMM(slot_time)
+		ORDER BY (slot_number, relay_url, block_hash)`,
+
+		// Bridge TVL snapshots per source; a re-saved snapshot replaces the
+		// earlier fetch on merge
+		`CREATE TABLE IF NOT EXISTS bridge_tvl (
+			bridge LowCardinality(String),
+			chain LowCardinality(String),
+			snapshot_time DateTime('UTC'),
+			tvl_usd Float64,
+			source LowCardinality(String),
+			fetched_at DateTime64(3, 'UTC') DEFAULT now64(3)
+		)
+		ENGINE = ReplacingMergeTree(fetched_at)
+		ORDER BY (bridge, chain, snapshot_time, source)`,
+
+		// ETH/USD quotes per source; a re-saved quote replaces the earlier
+		// fetch on merge
+		`CREATE TABLE IF NOT EXISTS prices (
+			quote_time DateTime('UTC'),
+			source LowCardinality(String),
+			price_usd Float64,
+			fetched_at DateTime64(3, 'UTC') DEFAULT now64(3)
+		)
+		ENGINE = ReplacingMergeTree(fetched_at)
+		ORDER BY (quote_time, source)`,
+
+		// Ingestion rows carry slot spans and NULL labels; label rows carry
+		// labels and NULL spans. Merges fold them with min/max/anyLast,
+		// which skip NULLs.
+		`CREATE TABLE IF NOT EXISTS builders (
+			pubkey String,
+			entity_name SimpleAggregateFunction(anyLast, Nullable(String)),
+			first_seen SimpleAggregateFunction(min, Nullable(UInt64)),
+			last_seen SimpleAggregateFunction(max, Nullable(UInt64)),
+			labels SimpleAggregateFunction(anyLast, Nullable(String))
+		)
+		ENGINE = AggregatingMergeTree
+		ORDER BY pubkey`,
+
+		`CREATE TABLE IF NOT EXISTS censorship_analysis (
+			start_slot UInt64,
+			end_slot UInt64,
+			duration_slots UInt64,
+			total_cost_wei UInt256,
+			total_cost_eth Float64,
+			total_cost_usd Nullable(Float64),
+			builder_concentration Float64,
+			top_k_builders Int32,
+			effective_cost_eth Float64,
+			breakeven_tvl_usd Nullable(Float64),
+			success_probability Nullable(Float64),
+			computed_at DateTime('UTC') DEFAULT now(),
+			top_builders String DEFAULT '[]'
+		)
+		ENGINE = ReplacingMergeTree(computed_at)
+		ORDER BY (start_slot, end_slot, top_k_builders)`,
+
+		// Tables created before top builders were stored
+		`ALTER TABLE censorship_analysis ADD COLUMN IF NOT EXISTS top_builders String DEFAULT '[]'`,
+	}
+
+	for _, stmt := range statements {
+		if err := s.exec(ctx, stmt, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetConflictPolicy changes how duplicate slots are resolved on insert.
+func (s *ClickHouseStore) SetConflictPolicy(p ConflictPolicy) error {
+	policy, err := ParseConflictPolicy(string(p))
+	if err != nil {
+		return err
+	}
+	s.onConflict = policy
+	return nil
+}
+
+// BatchInsertBribes inserts bribes in chunks of the configured batch size.
+//
+// Stored values are checked before inserting: under ConflictKeepFirst
+// stored slots are skipped; under ConflictKeepMax a row is only written
+// when it beats the stored value, and since FINAL keeps the last inserted
+// row, the maximum wins. Every row is also recorded as relayURL's report
+// in relay_bribes, and builder slot spans are appended to builders.
+//
+// ClickHouse has no transactions: a chunk that fails part-way may leave its
+// relay reports or builder spans inserted, but never its slot_bribes rows.
+func (s *ClickHouseStore) BatchInsertBribes(ctx context.Context, bribes []model.SlotBribe, relayURL string) (InsertReport, error) {
+	return insertChunks(ctx, len(bribes), s.batchSize, func(start, end int) (int, error) {
+		return s.insertChunk(ctx, bribes[start:end], relayURL)
+	})
+}
+
+// insertChunk inserts one chunk and returns the number of slot_bribes rows written.
+func (s *ClickHouseStore) insertChunk(ctx context.Context, bribes []model.SlotBribe, relayURL string) (int, error) {
+	if len(bribes) == 0 {
+		return 0, nil
+	}
+
+	// Find slots already stored within this chunk's span
+	minSlot, maxSlot := bribes[0].Slot, bribes[0].Slot
+	for _, bribe := range bribes {
+		if bribe.Slot < minSlot {
+			minSlot = bribe.Slot
+		}
+		if bribe.Slot > maxSlot {
+			maxSlot = bribe.Slot
+		}
+	}
+
+	existing := make(map[uint64]*big.Int)
+	err := s.query(ctx, `
+		SELECT slot_number, toString(max(value_wei)) FROM slot_bribes
+		WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+		GROUP BY slot_number
+		FORMAT TabSeparated`,
+		map[string]string{"start": strconv.FormatUint(minSlot, 10), "end": strconv.FormatUint(maxSlot, 10)},
+		func(fields []string) error {
+			slot, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			value, ok := new(big.Int).SetString(fields[1], 10)
+			if !ok {
+				return fmt.Errorf("invalid stored value '%s' for slot %d", fields[1], slot)
+			}
+			existing[slot] = value
+			return nil
+		})
+	if err != nil {
+		return 0, err
+	}
+
+	var body, reports strings.Builder
+	rows := 0
+	for _, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			continue
+		}
+
+		slotTime := model.Mainnet.SlotTime(bribe.Slot).UTC().Format("2006-01-02 15:04:05")
+		fmt.Fprintf(&reports, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			bribe.Slot, slotTime, tsvEscape(relayURL), bribe.ValueWei.String(),
+			tsvEscape(bribe.BuilderPubkey), "")
+
+		if stored, ok := existing[bribe.Slot]; ok {
+			if s.onConflict != ConflictKeepMax || bribe.ValueWei.Cmp(stored) <= 0 {
+				continue
+			}
+		}
+		existing[bribe.Slot] = bribe.ValueWei
+
+		fmt.Fprintf(&body, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			bribe.Slot, slotTime, bribe.ValueWei.String(),
+			strconv.FormatFloat(currency.WeiToETHFloat64(bribe.ValueWei), 'g', -1, 64),
+			tsvEscape(bribe.BuilderPubkey), "", tsvEscape(relayURL))
+		rows++
+	}
+	if reports.Len() == 0 {
+		return 0, nil
+	}
+
+	if err := s.execBody(ctx,
+		"INSERT INTO relay_bribes (slot_number, slot_time, relay_url, value_wei, builder_pubkey, block_hash) FORMAT TabSeparated",
+		nil, strings.NewReader(reports.String())); err != nil {
+		return 0, fmt.Errorf("failed to insert relay reports: %w", err)
+	}
+
+	var spans strings.Builder
+	for pubkey, span := range builderSpans(bribes) {
+		fmt.Fprintf(&spans, "%s\t%d\t%d\n", tsvEscape(pubkey), span.first, span.last)
+	}
+	if err := s.execBody(ctx,
+		"INSERT INTO builders (pubkey, first_seen, last_seen) FORMAT TabSeparated",
+		nil, strings.NewReader(spans.String())); err != nil {
+		return 0, fmt.Errorf("failed to update builders: %w", err)
+	}
+	if rows == 0 {
+		return 0, nil
+	}
+
+	if err := s.execBody(ctx,
+		"INSERT INTO slot_bribes (slot_number, slot_time, value_wei, value_eth, builder_pubkey, block_hash, relay_url) FORMAT TabSeparated",
+		nil, strings.NewReader(body.String())); err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// BatchInsertBids inserts received bids in chunks of the configured batch
+// size. Duplicates are left for ReplacingMergeTree to collapse.
+func (s *ClickHouseStore) BatchInsertBids(ctx context.Context, bids []model.ReceivedBid) error {
+	for start := 0; start < len(bids); start += s.batchSize {
+		end := min(start+s.batchSize, len(bids))
+
+		var body strings.Builder
+		for _, bid := range bids[start:end] {
+			if bid.ValueWei == nil {
+				continue
+			}
+			receivedAt := "\\N"
+			if !bid.ReceivedAt.IsZero() {
+				receivedAt = bid.ReceivedAt.UTC().Format("2006-01-02 15:04:05.000")
+			}
+			fmt.Fprintf(&body, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				bid.Slot, model.Mainnet.SlotTime(bid.Slot).UTC().Format("2006-01-02 15:04:05"),
+				tsvEscape(bid.RelayURL), tsvEscape(bid.BlockHash), tsvEscape(bid.BuilderPubkey),
+				bid.ValueWei.String(), receivedAt)
+		}
+		if body.Len() == 0 {
+			continue
+		}
+
+		if err := s.execBody(ctx,
+			"INSERT INTO received_bids (slot_number, slot_time, relay_url, block_hash, builder_pubkey, value_wei, received_at) FORMAT TabSeparated",
+			nil, strings.NewReader(body.String())); err != nil {
+			return fmt.Errorf("failed to insert bids %d-%d: %w", start, end-1, err)
+		}
+	}
+	return nil
+}
+
+// GetSlotBids retrieves received bids for a specific slot range.
+func (s *ClickHouseStore) GetSlotBids(ctx context.Context, startSlot, endSlot uint64) ([]model.ReceivedBid, error) {
+	var bids []model.ReceivedBid
+	err := s.query(ctx, `
+		SELECT slot_number, relay_url, builder_pubkey, block_hash, toString(value_wei),
+			ifNull(toUnixTimestamp64Milli(received_at), 0)
+		FROM received_bids FINAL
+		WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+		ORDER BY slot_number ASC, received_at ASC, relay_url ASC, block_hash ASC
+		FORMAT TabSeparated`,
+		map[string]string{"start": strconv.FormatUint(startSlot, 10), "end": strconv.FormatUint(endSlot, 10)},
+		func(fields []string) error {
+			slot, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			ms, err := strconv.ParseInt(fields[5], 10, 64)
+			if err != nil {
+				return err
+			}
+			bid, err := newReceivedBid(slot, tsvUnescape(fields[1]), tsvUnescape(fields[2]),
+				tsvUnescape(fields[3]), fields[4], ms)
+			if err != nil {
+				return err
+			}
+			bids = append(bids, bid)
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bids: %w", err)
+	}
+	return bids, nil
+}
+
+// GetSlotRange retrieves bribes for a specific slot range.
+func (s *ClickHouseStore) GetSlotRange(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	return collectSlots(func(fn func(model.SlotBribe) error) error {
+		return s.ForEachSlot(ctx, startSlot, endSlot, fn)
+	})
+}
+
+// SaveBridgeTVL inserts TVL snapshots in one request. Replaced snapshots
+// are collapsed by ReplacingMergeTree and hidden by FINAL until then.
+func (s *ClickHouseStore) SaveBridgeTVL(ctx context.Context, snapshots []model.BridgeTVL) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	var body strings.Builder
+	for _, snapshot := range snapshots {
+		if err := validateTVL(snapshot); err != nil {
+			return err
+		}
+		fmt.Fprintf(&body, "%s\t%s\t%s\t%s\t%s\n",
+			tsvEscape(snapshot.Bridge), tsvEscape(snapshot.Chain),
+			snapshot.Timestamp.UTC().Format(clickHouseTimeLayout), tsvFloat(snapshot.TVLUSD), tsvEscape(snapshot.Source))
+	}
+
+	if err := s.execBody(ctx, "INSERT INTO bridge_tvl (bridge, chain, snapshot_time, tvl_usd, source) FORMAT TabSeparated",
+		nil, strings.NewReader(body.String())); err != nil {
+		return fmt.Errorf("failed to save bridge TVL: %w", err)
+	}
+	return nil
+}
+
+// GetBridgeTVL retrieves a bridge's TVL snapshots taken in [from, to).
+func (s *ClickHouseStore) GetBridgeTVL(ctx context.Context, bridge, chain string, from, to time.Time) ([]model.BridgeTVL, error) {
+	snapshots, err := s.queryTVL(ctx,
+		"snapshot_time >= toDateTime({from:Int64}, 'UTC') AND snapshot_time < toDateTime({to:Int64}, 'UTC')",
+		"ORDER BY snapshot_time ASC, chain ASC, source ASC",
+		map[string]string{
+			"bridge": bridge,
+			"chain":  chain,
+			"from":   strconv.FormatInt(from.Unix(), 10),
+			"to":     strconv.FormatInt(to.Unix(), 10),
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bridge TVL: %w", err)
+	}
+	return snapshots, nil
+}
+
+// GetBridgeTVLAt retrieves a bridge's latest TVL snapshot taken at or before at.
+func (s *ClickHouseStore) GetBridgeTVLAt(ctx context.Context, bridge, chain string, at time.Time) (model.BridgeTVL, error) {
+	snapshots, err := s.queryTVL(ctx,
+		"snapshot_time <= toDateTime({at:Int64}, 'UTC')",
+		"ORDER BY snapshot_time DESC, chain ASC, source ASC LIMIT 1",
+		map[string]string{
+			"bridge": bridge,
+			"chain":  chain,
+			"at":     strconv.FormatInt(at.Unix(), 10),
+		})
+	if err != nil {
+		return model.BridgeTVL{}, fmt.Errorf("failed to query bridge TVL: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return model.BridgeTVL{}, ErrNotFound
+	}
+	return snapshots[0], nil
+}
+
+// queryTVL reads snapshots of the {bridge} parameter on {chain} ("" for
+// any) matching where.
+func (s *ClickHouseStore) queryTVL(ctx context.Context, where, order string, params map[string]string) ([]model.BridgeTVL, error) {
+	var snapshots []model.BridgeTVL
+	err := s.query(ctx, `
+		SELECT bridge, chain, toUnixTimestamp(snapshot_time), tvl_usd, source
+		FROM bridge_tvl FINAL
+		WHERE bridge = {bridge:String} AND ({chain:String} = '' OR chain = {chain:String}) AND `+where+`
+		`+order+`
+		FORMAT TabSeparated`,
+		params,
+		func(fields []string) error {
+			unix, err := strconv.ParseInt(fields[2], 10, 64)
+			if err != nil {
+				return err
+			}
+			tvl, err := strconv.ParseFloat(fields[3], 64)
+			if err != nil {
+				return err
+			}
+			snapshots = append(snapshots, model.BridgeTVL{
+				Bridge:    tsvUnescape(fields[0]),
+				Chain:     tsvUnescape(fields[1]),
+				Timestamp: time.Unix(unix, 0).UTC(),
+				TVLUSD:    tvl,
+				Source:    tsvUnescape(fields[4]),
+			})
+			return nil
+		})
+	return snapshots, err
+}
+
+// SavePrices inserts ETH/USD quotes in one request. Replaced quotes are
+// collapsed by ReplacingMergeTree and hidden by FINAL until then.
+func (s *ClickHouseStore) SavePrices(ctx context.Context, quotes []model.PriceQuote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	var body strings.Builder
+	for _, quote := range quotes {
+		if err := validatePrice(quote); err != nil {
+			return err
+		}
+		fmt.Fprintf(&body, "%s\t%s\t%s\n",
+			quote.Timestamp.UTC().Format(clickHouseTimeLayout), tsvEscape(quote.Source), tsvFloat(quote.USD))
+	}
+
+	if err := s.execBody(ctx, "INSERT INTO prices (quote_time, source, price_usd) FORMAT TabSeparated",
+		nil, strings.NewReader(body.String())); err != nil {
+		return fmt.Errorf("failed to save prices: %w", err)
+	}
+	return nil
+}
+
+// PriceAt retrieves the latest ETH/USD quote taken at or before at.
+func (s *ClickHouseStore) PriceAt(ctx context.Context, at time.Time) (model.PriceQuote, error) {
+	var quotes []model.PriceQuote
+	err := s.query(ctx, `
+		SELECT toUnixTimestamp(quote_time), price_usd, source
+		FROM prices FINAL
+		WHERE quote_time <= toDateTime({at:Int64}, 'UTC')
+		ORDER BY quote_time DESC, source ASC
+		LIMIT 1
+		FORMAT TabSeparated`,
+		map[string]string{"at": strconv.FormatInt(at.Unix(), 10)},
+		func(fields []string) error {
+			unix, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			usd, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return err
+			}
+			quotes = append(quotes, model.PriceQuote{
+				Timestamp: time.Unix(unix, 0).UTC(),
+				USD:       usd,
+				Source:    tsvUnescape(fields[2]),
+			})
+			return nil
+		})
+	if err != nil {
+		return model.PriceQuote{}, fmt.Errorf("failed to query price: %w", err)
+	}
+	if len(quotes) == 0 {
+		return model.PriceQuote{}, ErrNotFound
+	}
+	return quotes[0], nil
+}
+
+// GetTimeRange retrieves bribes for slots starting in [from, to).
+func (s *ClickHouseStore) GetTimeRange(ctx context.Context, from, to time.Time) ([]model.SlotBribe, error) {
+	startSlot, endSlot, err := model.Mainnet.SlotRangeForTimes(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSlotRange(ctx, startSlot, endSlot)
+}
+
+// ForEachSlot streams bribes for a slot range to fn in slot order,
+// decoding the HTTP response row by row.
+func (s *ClickHouseStore) ForEachSlot(ctx context.Context, startSlot, endSlot uint64, fn func(model.SlotBribe) error) error {
+	return s.query(ctx, `
+		SELECT slot_number, toString(value_wei), builder_pubkey, relay_url
+		FROM slot_bribes FINAL
+		WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+		ORDER BY slot_number ASC
+		FORMAT TabSeparated`,
+		map[string]string{"start": strconv.FormatUint(startSlot, 10), "end": strconv.FormatUint(endSlot, 10)},
+		func(fields []string) error {
+			slot, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			valueWei, ok := new(big.Int).SetString(fields[1], 10)
+			if !ok {
+				return fmt.Errorf("invalid stored value '%s' for slot %d", fields[1], slot)
+			}
+			return fn(model.SlotBribe{
+				Slot:          slot,
+				ValueWei:      valueWei,
+				BuilderPubkey: tsvUnescape(fields[2]),
+				RelayURL:      tsvUnescape(fields[3]),
+			})
+		})
+}
+
+// GetSlotRangeStats aggregates the range as UInt256 inside ClickHouse.
+func (s *ClickHouseStore) GetSlotRangeStats(ctx context.Context, startSlot, endSlot uint64) (SlotRangeStats, error) {
+	if startSlot > endSlot {
+		return SlotRangeStats{}, fmt.Errorf("start slot %d is after end slot %d", startSlot, endSlot)
+	}
+
+	var stats SlotRangeStats
+	err := s.query(ctx, `
+		SELECT count(), toString(sum(value_wei)), toString(min(value_wei)), toString(max(value_wei))
+		FROM slot_bribes FINAL
+		WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+		FORMAT TabSeparated`,
+		map[string]string{"start": strconv.FormatUint(startSlot, 10), "end": strconv.FormatUint(endSlot, 10)},
+		func(fields []string) error {
+			count, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			stats, err = newSlotRangeStats(count, fields[1], fields[2], fields[3])
+			return err
+		})
+	if err != nil {
+		return SlotRangeStats{}, fmt.Errorf("failed to aggregate slot range: %w", err)
+	}
+	return stats, nil
+}
+
+// GetBuilderStats returns aggregated statistics for builders, including
+// exact UInt256 value totals, computed inside ClickHouse.
+func (s *ClickHouseStore) GetBuilderStats(ctx context.Context, query BuilderStatsQuery) ([]model.BuilderStats, error) {
+	order, err := query.orderExpr(map[BuilderSort]string{
+		SortByBlockCount: "count()",
+		SortByTotalValue: "sum(value_wei)",
+		SortByLastSeen:   "max(slot_number)",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	filter, params := "1", map[string]string(nil)
+	if query.windowed() {
+		filter = "slot_number BETWEEN {start:UInt64} AND {end:UInt64}"
+		params = map[string]string{"start": strconv.FormatUint(query.StartSlot, 10), "end": strconv.FormatUint(query.EndSlot, 10)}
+	}
+
+	var stats []model.BuilderStats
+	err = s.query(ctx, `
+		SELECT builder_pubkey, count() AS block_count, any(b.entity), toString(sum(value_wei)),
+			ifNotFinite(sum(value_wei) / (sum(sum(value_wei)) OVER ()), 0),
+			min(slot_number), max(slot_number)
+		FROM slot_bribes AS s FINAL
+		LEFT JOIN (
+			SELECT pubkey, ifNull(anyLast(entity_name), '') AS entity
+			FROM builders
+			GROUP BY pubkey
+		) AS b ON b.pubkey = s.builder_pubkey
+		WHERE `+filter+`
+		GROUP BY builder_pubkey
+		ORDER BY `+order+query.limitClause("18446744073709551615")+`
+		FORMAT TabSeparated`,
+		params,
+		func(fields []string) error {
+			count, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			total, ok := new(big.Int).SetString(fields[3], 10)
+			if !ok {
+				return fmt.Errorf("invalid total value '%s' for builder %s", fields[3], fields[0])
+			}
+			share, err := strconv.ParseFloat(fields[4], 64)
+			if err != nil {
+				return err
+			}
+			first, err := strconv.ParseUint(fields[5], 10, 64)
+			if err != nil {
+				return err
+			}
+			last, err := strconv.ParseUint(fields[6], 10, 64)
+			if err != nil {
+				return err
+			}
+			stat := model.BuilderStats{
+				BuilderPubkey: tsvUnescape(fields[0]),
+				BlockCount:    count,
+				Entity:        tsvUnescape(fields[2]),
+				ValueShare:    share,
+				FirstSeenSlot: first,
+				LastSeenSlot:  last,
+			}
+			setTotalValue(&stat, total)
+			stats = append(stats, stat)
+			return nil
+		})
+	return stats, err
+}
+
+// GetBuilderConcentration computes top-k concentration over raw slots.
+func (s *ClickHouseStore) GetBuilderConcentration(ctx context.Context, startSlot, endSlot uint64, topK int) (BuilderConcentration, error) {
+	return builderConcentration(ctx, s, startSlot, endSlot, topK, func() (uint64, int, error) {
+		var totalBlocks uint64
+		var builders int
+		err := s.query(ctx, `
+			SELECT count(), uniqExact(builder_pubkey)
+			FROM slot_bribes FINAL
+			WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+			FORMAT TabSeparated`,
+			map[string]string{"start": strconv.FormatUint(startSlot, 10), "end": strconv.FormatUint(endSlot, 10)},
+			func(fields []string) error {
+				var err error
+				if totalBlocks, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+					return err
+				}
+				builders, err = strconv.Atoi(fields[1])
+				return err
+			})
+		return totalBlocks, builders, err
+	})
+}
+
+// GetBuilder returns the builders table row for pubkey, folding rows not
+// yet merged.
+func (s *ClickHouseStore) GetBuilder(ctx context.Context, pubkey string) (Builder, error) {
+	var builder Builder
+	found := false
+	err := s.query(ctx, `
+		SELECT pubkey, ifNull(anyLast(entity_name), ''), ifNull(min(first_seen), 0),
+			ifNull(max(last_seen), 0), ifNull(anyLast(labels), '[]')
+		FROM builders
+		WHERE pubkey = {pubkey:String}
+		GROUP BY pubkey
+		FORMAT TabSeparated`,
+		map[string]string{"pubkey": pubkey},
+		func(fields []string) error {
+			var err error
+			builder.Pubkey, builder.Entity = tsvUnescape(fields[0]), tsvUnescape(fields[1])
+			if builder.FirstSeen, err = strconv.ParseUint(fields[2], 10, 64); err != nil {
+				return err
+			}
+			if builder.LastSeen, err = strconv.ParseUint(fields[3], 10, 64); err != nil {
+				return err
+			}
+			builder.Labels, err = decodeLabels(tsvUnescape(fields[4]))
+			found = true
+			return err
+		})
+	if err != nil {
+		return Builder{}, fmt.Errorf("failed to get builder: %w", err)
+	}
+	if !found {
+		return Builder{}, ErrNotFound
+	}
+	return builder, nil
+}
+
+// SaveBuilderLabels appends label rows to builders; the latest row wins
+// once merged.
+func (s *ClickHouseStore) SaveBuilderLabels(ctx context.Context, builders []Builder) error {
+	if len(builders) == 0 {
+		return nil
+	}
+
+	var body strings.Builder
+	for _, b := range builders {
+		labels, err := encodeLabels(b.Labels)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&body, "%s\t%s\t%s\n", tsvEscape(b.Pubkey), tsvEscape(b.Entity), tsvEscape(labels))
+	}
+
+	if err := s.execBody(ctx, "INSERT INTO builders (pubkey, entity_name, labels) FORMAT TabSeparated",
+		nil, strings.NewReader(body.String())); err != nil {
+		return fmt.Errorf("failed to save builder labels: %w", err)
+	}
+	return nil
+}
+
+// GetRollingStats computes sliding-window statistics over value_eth inside
+// ClickHouse using window functions. Only complete windows are returned,
+// matching analysis.Statistics.ComputeRollingStats.
+func (s *ClickHouseStore) GetRollingStats(ctx context.Context, startSlot, endSlot uint64, windowSize int) ([]RollingWindow, error) {
+	if windowSize < 1 {
+		return nil, fmt.Errorf("window size must be at least 1, got %d", windowSize)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT slot_number, mean, stddev, lo, hi, toString(total)
+		FROM (
+			SELECT
+				slot_number,
+				avg(value_eth) OVER w AS mean,
+				stddevPop(value_eth) OVER w AS stddev,
+				min(value_eth) OVER w AS lo,
+				max(value_eth) OVER w AS hi,
+				sum(value_wei) OVER w AS total,
+				count() OVER w AS n
+			FROM slot_bribes FINAL
+			WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+			WINDOW w AS (ORDER BY slot_number ROWS BETWEEN %d PRECEDING AND CURRENT ROW)
+		)
+		WHERE n = %d
+		ORDER BY slot_number
+		FORMAT TabSeparated`, windowSize-1, windowSize)
+
+	var windows []RollingWindow
+	err := s.query(ctx, query,
+		map[string]string{"start": strconv.FormatUint(startSlot, 10), "end": strconv.FormatUint(endSlot, 10)},
+		func(fields []string) error {
+			w, err := parseRollingWindow(fields)
+			if err != nil {
+				return err
+			}
+			windows = append(windows, w)
+			return nil
+		})
+	return windows, err
+}
+
+// FindDiscrepancies returns the slots in [startSlot, endSlot] on which
+// relays disagree, using the slot_relay_discrepancies view.
+func (s *ClickHouseStore) FindDiscrepancies(ctx context.Context, startSlot, endSlot uint64) ([]SlotDiscrepancy, error) {
+	if startSlot > endSlot {
+		return nil, fmt.Errorf("start slot %d is after end slot %d", startSlot, endSlot)
+	}
+
+	var discrepancies []SlotDiscrepancy
+	err := s.query(ctx, `
+		SELECT r.slot_number, r.relay_url, toString(r.value_wei), r.builder_pubkey, r.relay_url = c.relay_url
+		FROM (
+			SELECT slot_number, relay_url, value_wei, builder_pubkey
+			FROM relay_bribes FINAL
+			WHERE slot_number IN (
+				SELECT slot_number FROM slot_relay_discrepancies
+				WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+			)
+		) AS r
+		LEFT JOIN (
+			SELECT slot_number, relay_url
+			FROM slot_bribes FINAL
+			WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+		) AS c ON c.slot_number = r.slot_number
+		ORDER BY r.slot_number ASC, r.relay_url ASC
+		FORMAT TabSeparated`,
+		map[string]string{"start": strconv.FormatUint(startSlot, 10), "end": strconv.FormatUint(endSlot, 10)},
+		func(fields []string) error {
+			slot, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			valueWei, ok := new(big.Int).SetString(fields[2], 10)
+			if !ok {
+				return fmt.Errorf("invalid stored value '%s' for slot %d", fields[2], slot)
+			}
+			discrepancies = addReport(discrepancies, slot, RelayReport{
+				RelayURL:      tsvUnescape(fields[1]),
+				ValueWei:      valueWei,
+				BuilderPubkey: tsvUnescape(fields[3]),
+				Canonical:     fields[4] == "1" || fields[4] == "true",
+			})
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query discrepancies: %w", err)
+	}
+	return discrepancies, nil
+}
+
+// GetRelayReports returns every relay's report for the slots in
+// [startSlot, endSlot], collapsed by FINAL to each relay's latest.
+func (s *ClickHouseStore) GetRelayReports(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	if startSlot > endSlot {
+		return nil, fmt.Errorf("start slot %d is after end slot %d", startSlot, endSlot)
+	}
+
+	var reports []model.SlotBribe
+	err := s.query(ctx, `
+		SELECT slot_number, toString(value_wei), builder_pubkey, relay_url
+		FROM relay_bribes FINAL
+		WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+		ORDER BY slot_number ASC, relay_url ASC
+		FORMAT TabSeparated`,
+		map[string]string{"start": strconv.FormatUint(startSlot, 10), "end": strconv.FormatUint(endSlot, 10)},
+		func(fields []string) error {
+			slot, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			valueWei, ok := new(big.Int).SetString(fields[1], 10)
+			if !ok {
+				return fmt.Errorf("invalid stored value '%s' for slot %d", fields[1], slot)
+			}
+			reports = append(reports, model.SlotBribe{
+				Slot:          slot,
+				ValueWei:      valueWei,
+				BuilderPubkey: tsvUnescape(fields[2]),
+				RelayURL:      tsvUnescape(fields[3]),
+			})
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query relay reports: %w", err)
+	}
+	return reports, nil
+}
+
+// FindGaps returns the ranges of slots in [startSlot, endSlot] with no
+// stored bribe, detected inside ClickHouse with a window function.
+func (s *ClickHouseStore) FindGaps(ctx context.Context, startSlot, endSlot uint64) ([]SlotGap, error) {
+	if startSlot > endSlot {
+		return nil, fmt.Errorf("start slot %d is after end slot %d", startSlot, endSlot)
+	}
+	params := map[string]string{"start": strconv.FormatUint(startSlot, 10), "end": strconv.FormatUint(endSlot, 10)}
+
+	var first, last, count uint64
+	err := s.query(ctx, `
+		SELECT min(slot_number), max(slot_number), count()
+		FROM slot_bribes FINAL
+		WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+		FORMAT TabSeparated`,
+		params,
+		func(fields []string) error {
+			var err error
+			if first, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+				return err
+			}
+			if last, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+				return err
+			}
+			count, err = strconv.ParseUint(fields[2], 10, 64)
+			return err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slot bounds: %w", err)
+	}
+
+	var inner []SlotGap
+	err = s.query(ctx, `
+		SELECT prev + 1, slot_number - 1
+		FROM (
+			SELECT slot_number,
+				lagInFrame(toNullable(slot_number)) OVER (ORDER BY slot_number ROWS BETWEEN 1 PRECEDING AND CURRENT ROW) AS prev
+			FROM slot_bribes FINAL
+			WHERE slot_number BETWEEN {start:UInt64} AND {end:UInt64}
+		)
+		WHERE prev IS NOT NULL AND slot_number - prev > 1
+		ORDER BY slot_number ASC
+		FORMAT TabSeparated`,
+		params,
+		func(fields []string) error {
+			var gap SlotGap
+			var err error
+			if gap.Start, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+				return err
+			}
+			if gap.End, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+				return err
+			}
+			inner = append(inner, gap)
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query gaps: %w", err)
+	}
+
+	return boundGaps(startSlot, endSlot, first, last, count > 0, inner), nil
+}
+
+// SaveAnalysis persists a censorship cost computation.
+//
+// Re-running the same (start, end, top-k) analysis supersedes the stored row.
+func (s *ClickHouseStore) SaveAnalysis(ctx context.Context, record AnalysisRecord) error {
+	if record.TotalCostWei == nil {
+		return fmt.Errorf("TotalCostWei cannot be nil")
+	}
+	computedAt := record.ComputedAt
+	if computedAt.IsZero() {
+		computedAt = time.Now()
+	}
+
+	topBuilders, err := encodeTopBuilders(record.TopBuilders)
+	if err != nil {
+		return err
+	}
+
+	row := fmt.Sprintf("%d\t%d\t%d\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+		record.StartSlot, record.EndSlot, record.DurationSlots, record.TotalCostWei.String(),
+		tsvFloat(record.TotalCostETH), tsvNullableFloat(record.TotalCostUSD),
+		tsvFloat(record.BuilderConcentration), record.TopKBuilders,
+		tsvFloat(record.EffectiveCostETH), tsvNullableFloat(record.BreakevenTVLUSD),
+		tsvFloat(record.SuccessProbability), computedAt.UTC().Format(clickHouseTimeLayout), tsvEscape(topBuilders))
+
+	err = s.execBody(ctx, `INSERT INTO censorship_analysis (
+			start_slot, end_slot, duration_slots, total_cost_wei, total_cost_eth, total_cost_usd,
+			builder_concentration, top_k_builders, effective_cost_eth, breakeven_tvl_usd,
+			success_probability, computed_at, top_builders
+		) FORMAT TabSeparated`, nil, strings.NewReader(row))
+	if err != nil {
+		return fmt.Errorf("failed to save analysis: %w", err)
+	}
+	return nil
+}
+
+// GetAnalysis returns the stored analysis for (startSlot, endSlot, topK).
+func (s *ClickHouseStore) GetAnalysis(ctx context.Context, startSlot, endSlot uint64, topK int) (AnalysisRecord, error) {
+	records, err := s.queryAnalyses(ctx,
+		"start_slot = {start:UInt64} AND end_slot = {end:UInt64} AND top_k_builders = {k:Int32}", "",
+		map[string]string{
+			"start": strconv.FormatUint(startSlot, 10),
+			"end":   strconv.FormatUint(endSlot, 10),
+			"k":     strconv.Itoa(topK),
+		})
+	if err != nil {
+		return AnalysisRecord{}, fmt.Errorf("failed to get analysis: %w", err)
+	}
+	if len(records) == 0 {
+		return AnalysisRecord{}, ErrNotFound
+	}
+	return records[0], nil
+}
+
+// GetAnalyses returns stored analyses matching query, most recent first.
+func (s *ClickHouseStore) GetAnalyses(ctx context.Context, query AnalysisQuery) ([]AnalysisRecord, error) {
+	where := "start_slot >= {start:UInt64}"
+	params := map[string]string{"start": strconv.FormatUint(query.StartSlot, 10)}
+	if query.EndSlot > 0 {
+		where += " AND end_slot <= {end:UInt64}"
+		params["end"] = strconv.FormatUint(query.EndSlot, 10)
+	}
+	if query.TopK > 0 {
+		where += " AND top_k_builders = {k:Int32}"
+		params["k"] = strconv.Itoa(query.TopK)
+	}
+	limit := ""
+	if query.Limit > 0 {
+		limit = " LIMIT " + strconv.Itoa(query.Limit)
+	}
+
+	records, err := s.queryAnalyses(ctx, where, limit, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query analyses: %w", err)
+	}
+	return records, nil
+}
+
+func (s *ClickHouseStore) queryAnalyses(ctx context.Context, where, limit string, params map[string]string) ([]AnalysisRecord, error) {
+	var records []AnalysisRecord
+	err := s.query(ctx, `
+		SELECT start_slot, end_slot, duration_slots, toString(total_cost_wei), total_cost_eth, total_cost_usd,
+			builder_concentration, top_k_builders, effective_cost_eth, breakeven_tvl_usd,
+			success_probability, computed_at, top_builders
+		FROM censorship_analysis FINAL
+		WHERE `+where+`
+		ORDER BY computed_at DESC, start_slot ASC`+limit+`
+		FORMAT TabSeparated`,
+		params,
+		func(fields []string) error {
+			record, err := parseAnalysisRow(fields)
+			if err != nil {
+				return err
+			}
+			records = append(records, record)
+			return nil
+		})
+	return records, err
+}
+
+// clickHouseTimeLayout is the text format of DateTime values.
+const clickHouseTimeLayout = "2006-01-02 15:04:05"
+
+func parseAnalysisRow(fields []string) (AnalysisRecord, error) {
+	if len(fields) != 13 {
+		return AnalysisRecord{}, fmt.Errorf("expected 13 analysis columns, got %d", len(fields))
+	}
+
+	var record AnalysisRecord
+	var err error
+	if record.StartSlot, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+		return record, err
+	}
+	if record.EndSlot, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+		return record, err
+	}
+	if record.DurationSlots, err = strconv.ParseUint(fields[2], 10, 64); err != nil {
+		return record, err
+	}
+	wei, ok := new(big.Int).SetString(fields[3], 10)
+	if !ok {
+		return record, fmt.Errorf("invalid stored cost '%s'", fields[3])
+	}
+	record.TotalCostWei = wei
+	record.TotalBlocks = record.DurationSlots // Only complete ranges are stored
+
+	floats := []struct {
+		field string
+		dst   *float64
+	}{
+		{fields[4], &record.TotalCostETH},
+		{fields[5], &record.TotalCostUSD},
+		{fields[6], &record.BuilderConcentration},
+		{fields[8], &record.EffectiveCostETH},
+		{fields[9], &record.BreakevenTVLUSD},
+		{fields[10], &record.SuccessProbability},
+	}
+	for _, f := range floats {
+		if f.field == `\N` {
+			continue // NULL
+		}
+		if *f.dst, err = strconv.ParseFloat(f.field, 64); err != nil {
+			return record, err
+		}
+	}
+
+	if record.TopKBuilders, err = strconv.Atoi(fields[7]); err != nil {
+		return record, err
+	}
+	if record.ComputedAt, err = time.Parse(clickHouseTimeLayout, fields[11]); err != nil {
+		return record, err
+	}
+	if record.TopBuilders, err = decodeTopBuilders(tsvUnescape(fields[12])); err != nil {
+		return record, err
+	}
+
+	return record, nil
+}
+
+// Close releases idle HTTP connections.
+func (s *ClickHouseStore) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// exec runs a statement that returns no rows.
+func (s *ClickHouseStore) exec(ctx context.Context, query string, params map[string]string) error {
+	return s.execBody(ctx, query, params, nil)
+}
+
+// execBody runs a statement whose data (e.g. INSERT rows) is sent as the request body.
+func (s *ClickHouseStore) execBody(ctx context.Context, query string, params map[string]string, body io.Reader) error {
+	resp, err := s.do(ctx, query, params, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+	_, err = io.Copy(io.Discard, resp)
+	return err
+}
+
+// query runs a TabSeparated SELECT and calls fn for every row.
+func (s *ClickHouseStore) query(ctx context.Context, query string, params map[string]string, fn func(fields []string) error) error {
+	resp, err := s.do(ctx, query, params, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	scanner := bufio.NewScanner(resp)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err := fn(strings.Split(scanner.Text(), "\t")); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *ClickHouseStore) do(ctx context.Context, query string, params map[string]string, body io.Reader) (io.ReadCloser, error) {
+	values := url.Values{}
+	values.Set("database", s.database)
+	for name, value := range params {
+		values.Set("param_"+name, value)
+	}
+
+	// Statements travel in the URL when the body carries INSERT data
+	var reqBody io.Reader = strings.NewReader(query)
+	if body != nil {
+		values.Set("query", query)
+		reqBody = body
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"?"+values.Encode(), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	if s.user != "" {
+		req.SetBasicAuth(s.user, s.password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("clickhouse returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, nil
+}
+
+func parseRollingWindow(fields []string) (RollingWindow, error) {
+	if len(fields) != 6 {
+		return RollingWindow{}, fmt.Errorf("expected 6 columns, got %d", len(fields))
+	}
+	slot, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return RollingWindow{}, err
+	}
+	floats := make([]float64, 4)
+	for i := range floats {
+		if floats[i], err = strconv.ParseFloat(fields[i+1], 64); err != nil {
+			return RollingWindow{}, err
+		}
+	}
+	total, ok := new(big.Int).SetString(fields[5], 10)
+	if !ok {
+		return RollingWindow{}, fmt.Errorf("invalid window sum '%s'", fields[5])
+	}
+	return RollingWindow{
+		Slot:      slot,
+		MeanETH:   floats[0],
+		StdDevETH: floats[1],
+		MinETH:    floats[2],
+		MaxETH:    floats[3],
+		TotalWei:  total,
+	}, nil
+}
+
+var tsvReplacer = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n")
+var tsvUnreplacer = strings.NewReplacer("\\\\", "\\", "\\t", "\t", "\\n", "\n", "\\'", "'")
+
+func tsvEscape(s string) string   { return tsvReplacer.Replace(s) }
+func tsvUnescape(s string) string { return tsvUnreplacer.Replace(s) }
+
+func tsvFloat(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+
+func tsvNullableFloat(v float64) string {
+	if v == 0 {
+		return "\\N"
+	}
+	return tsvFloat(v)
+}
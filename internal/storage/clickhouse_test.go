@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"insolventbydesign/internal/model"
+)
+
+// fakeClickHouse records INSERT bodies and answers slot lookups from them.
+type fakeClickHouse struct {
+	mu      sync.Mutex
+	inserts []string
+	slots   map[uint64]*big.Int // Max inserted value per slot
+}
+
+func (f *fakeClickHouse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	query := r.URL.Query().Get("query")
+	if query == "" {
+		query = string(body)
+	}
+
+	switch {
+	case strings.HasPrefix(query, "INSERT INTO slot_bribes"):
+		f.inserts = append(f.inserts, string(body))
+		for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+			fields := strings.Split(line, "\t")
+			slot, _ := strconv.ParseUint(fields[0], 10, 64)
+			value, _ := new(big.Int).SetString(fields[2], 10)
+			if stored, ok := f.slots[slot]; !ok || value.Cmp(stored) > 0 {
+				f.slots[slot] = value
+			}
+		}
+	case strings.Contains(query, "toString(max(value_wei))"):
+		start, _ := strconv.ParseUint(r.URL.Query().Get("param_start"), 10, 64)
+		end, _ := strconv.ParseUint(r.URL.Query().Get("param_end"), 10, 64)
+		for slot, value := range f.slots {
+			if slot >= start && slot <= end {
+				io.WriteString(w, strconv.FormatUint(slot, 10)+"\t"+value.String()+"\n")
+			}
+		}
+	case query == "SELECT 1":
+		io.WriteString(w, "1\n")
+	}
+}
+
+func newFakeClickHouseStore(t *testing.T) (*ClickHouseStore, *fakeClickHouse) {
+	t.Helper()
+	fake := &fakeClickHouse{slots: make(map[uint64]*big.Int)}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	port, _ := strconv.Atoi(u.Port())
+	store, err := NewClickHouseStore(Config{Host: u.Hostname(), Port: port, Database: "test"})
+	if err != nil {
+		t.Fatalf("NewClickHouseStore failed: %v", err)
+	}
+	return store, fake
+}
+
+// TestClickHouseStore_BatchedInsert verifies chunking and first-write-wins dedup.
+func TestClickHouseStore_BatchedInsert(t *testing.T) {
+	store, fake := newFakeClickHouseStore(t)
+	store.SetBatchSize(2)
+	ctx := context.Background()
+
+	bribes := make([]model.SlotBribe, 5)
+	for i := range bribes {
+		bribes[i] = model.SlotBribe{Slot: uint64(10 + i), ValueWei: big.NewInt(int64(i + 1)), BuilderPubkey: "0xA"}
+	}
+
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+	if len(fake.inserts) != 3 {
+		t.Fatalf("Expected 3 INSERT requests for 5 rows at batch size 2, got %d", len(fake.inserts))
+	}
+
+	// Re-inserting the same slots sends nothing
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay-2"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+	if len(fake.inserts) != 3 {
+		t.Errorf("Expected duplicate slots to be skipped, got %d INSERT requests", len(fake.inserts))
+	}
+}
+
+// TestClickHouseStore_KeepMax verifies only higher values are re-inserted.
+func TestClickHouseStore_KeepMax(t *testing.T) {
+	store, fake := newFakeClickHouseStore(t)
+	if err := store.SetConflictPolicy(ConflictKeepMax); err != nil {
+		t.Fatalf("SetConflictPolicy failed: %v", err)
+	}
+	ctx := context.Background()
+
+	first := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+	}
+	if _, err := store.BatchInsertBribes(ctx, first, "relay-a"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	second := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(50), BuilderPubkey: "0xB"},  // Lower: skipped
+		{Slot: 2, ValueWei: big.NewInt(150), BuilderPubkey: "0xB"}, // Higher: written
+	}
+	if _, err := store.BatchInsertBribes(ctx, second, "relay-b"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	if len(fake.inserts) != 2 {
+		t.Fatalf("Expected 2 INSERT requests, got %d", len(fake.inserts))
+	}
+	if rows := strings.Count(fake.inserts[1], "\n"); rows != 1 || !strings.HasPrefix(fake.inserts[1], "2\t") {
+		t.Errorf("Expected only slot 2 re-inserted, got %q", fake.inserts[1])
+	}
+}
+
+// TestTSVEscape verifies round-tripping of control characters.
+func TestTSVEscape(t *testing.T) {
+	raw := "a\tb\\c\nd"
+	if got := tsvUnescape(tsvEscape(raw)); got != raw {
+		t.Errorf("Expected %q, got %q", raw, got)
+	}
+	if strings.ContainsAny(tsvEscape(raw), "\t\n") {
+		t.Error("Escaped value still contains tab or newline")
+	}
+}
+
+// TestParseAnalysisRow verifies TabSeparated analysis rows decode, including NULLs.
+func TestParseAnalysisRow(t *testing.T) {
+	line := "100\t199\t100\t123456789012345678901234567890\t1.5\t\\N\t0.25\t3\t1.125\t\\N\t0.8\t2024-01-02 03:04:05\t" +
+		`[{"pubkey":"0xA","entity":"O\'Brien","block_count":40}]`
+
+	record, err := parseAnalysisRow(strings.Split(line, "\t"))
+	if err != nil {
+		t.Fatalf("parseAnalysisRow failed: %v", err)
+	}
+	if record.TotalCostWei.String() != "123456789012345678901234567890" {
+		t.Errorf("expected exact wei, got %s", record.TotalCostWei)
+	}
+	if record.TotalCostUSD != 0 || record.BreakevenTVLUSD != 0 {
+		t.Errorf("expected NULL USD fields as 0, got %f and %f", record.TotalCostUSD, record.BreakevenTVLUSD)
+	}
+	if record.TopKBuilders != 3 || record.SuccessProbability != 0.8 {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+	if record.ComputedAt.Year() != 2024 || record.ComputedAt.Second() != 5 {
+		t.Errorf("Unexpected computed_at: %v", record.ComputedAt)
+	}
+	if len(record.TopBuilders) != 1 || record.TopBuilders[0].Entity != "O'Brien" {
+		t.Errorf("Unexpected top builders: %+v", record.TopBuilders)
+	}
+
+	if _, err := parseAnalysisRow([]string{"1", "2"}); err == nil {
+		t.Error("Expected error for short row, got nil")
+	}
+}
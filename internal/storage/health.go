@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// Health describes whether a store can serve fresh data.
+type Health struct {
+	PingLatency   time.Duration // Round trip of the slowest pool's ping
+	OpenConns     int           // Connections open across all pools (0 for HTTP backends)
+	IdleConns     int
+	InUseConns    int
+	NewestSlot    uint64        // Highest stored slot; 0 when nothing is stored
+	NewestSlotAge time.Duration // Time since NewestSlot started (mainnet timing)
+}
+
+// HasData reports whether any slot is stored.
+func (h Health) HasData() bool {
+	return h.NewestSlot > 0
+}
+
+// HealthChecker is implemented by stores that report their health.
+type HealthChecker interface {
+	// Health pings the database and reports connection pool usage and
+	// the newest stored slot. An error means the database is unreachable.
+	Health(ctx context.Context) (Health, error)
+}
+
+// CheckHealth returns store's health, failing for stores that cannot report it.
+func CheckHealth(ctx context.Context, store Store) (Health, error) {
+	checker, ok := store.(HealthChecker)
+	if !ok {
+		return Health{}, fmt.Errorf("storage: %T does not report health", store)
+	}
+	return checker.Health(ctx)
+}
+
+// setNewestSlot records the newest stored slot and its age at now.
+func (h *Health) setNewestSlot(slot uint64, now time.Time) {
+	h.NewestSlot = slot
+	if slot > 0 {
+		h.NewestSlotAge = now.Sub(model.Mainnet.SlotTime(slot))
+	}
+}
+
+// sqlHealth implements Health for database/sql backends. Every pool is
+// pinged and its statistics summed; the newest slot is read from readDB.
+func sqlHealth(ctx context.Context, readDB *sql.DB, pools ...*sql.DB) (Health, error) {
+	var h Health
+	for _, db := range pools {
+		start := time.Now()
+		if err := db.PingContext(ctx); err != nil {
+			return Health{}, fmt.Errorf("failed to ping database: %w", err)
+		}
+		h.PingLatency = max(h.PingLatency, time.Since(start))
+
+		stats := db.Stats()
+		h.OpenConns += stats.OpenConnections
+		h.IdleConns += stats.Idle
+		h.InUseConns += stats.InUse
+	}
+
+	var newest sql.NullInt64
+	if err := readDB.QueryRowContext(ctx, "SELECT MAX(slot_number) FROM slot_bribes").Scan(&newest); err != nil {
+		return Health{}, fmt.Errorf("failed to query newest slot: %w", err)
+	}
+	h.setNewestSlot(uint64(newest.Int64), time.Now())
+	return h, nil
+}
+
+// Health pings both pools and reports their combined usage and the
+// newest slot visible to reads (on the replica, when configured).
+func (s *PostgresStore) Health(ctx context.Context) (Health, error) {
+	return sqlHealth(ctx, s.readDB, s.db, s.readDB)
+}
+
+// Health pings the database and reports the newest stored slot.
+func (s *SQLiteStore) Health(ctx context.Context) (Health, error) {
+	return sqlHealth(ctx, s.db, s.db)
+}
+
+// Health pings ClickHouse and reports the newest stored slot. Connection
+// counts are zero: the HTTP client does not expose them.
+func (s *ClickHouseStore) Health(ctx context.Context) (Health, error) {
+	var h Health
+	start := time.Now()
+	var newest uint64
+	err := s.query(ctx, "SELECT max(slot_number) FROM slot_bribes FORMAT TabSeparated", nil,
+		func(fields []string) error {
+			var err error
+			newest, err = strconv.ParseUint(fields[0], 10, 64)
+			return err
+		})
+	if err != nil {
+		return Health{}, fmt.Errorf("failed to query newest slot: %w", err)
+	}
+	h.PingLatency = time.Since(start)
+	h.setNewestSlot(newest, time.Now())
+	return h, nil
+}
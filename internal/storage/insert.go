@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ChunkReport is the outcome of one chunk of a batch insert. Each chunk
+// commits or rolls back on its own.
+type ChunkReport struct {
+	Start    int   // Index of the chunk's first row in the batch
+	End      int   // Index one past the chunk's last row
+	Inserted int   // Rows that created or replaced a slot's canonical record
+	Skipped  int   // Rows kept out by the ConflictPolicy, duplicates or nil values
+	Failed   int   // Rows rolled back because the chunk failed
+	Err      error // Why the chunk failed, nil on success
+}
+
+// InsertReport summarizes a batch insert chunk by chunk. Rows after the
+// last reported chunk were not attempted (the context was cancelled).
+type InsertReport struct {
+	Chunks []ChunkReport
+}
+
+// Inserted returns the number of rows inserted across all chunks.
+func (r InsertReport) Inserted() int {
+	n := 0
+	for _, c := range r.Chunks {
+		n += c.Inserted
+	}
+	return n
+}
+
+// Skipped returns the number of rows skipped across all chunks.
+func (r InsertReport) Skipped() int {
+	n := 0
+	for _, c := range r.Chunks {
+		n += c.Skipped
+	}
+	return n
+}
+
+// Failed returns the number of rows in failed chunks.
+func (r InsertReport) Failed() int {
+	n := 0
+	for _, c := range r.Chunks {
+		n += c.Failed
+	}
+	return n
+}
+
+// FailedChunks returns the chunks that rolled back, e.g. to retry their rows.
+func (r InsertReport) FailedChunks() []ChunkReport {
+	var failed []ChunkReport
+	for _, c := range r.Chunks {
+		if c.Err != nil {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// insertChunks splits n rows into chunks of size rows and calls insert for
+// each, continuing past failed chunks so one bad row only loses its own
+// chunk. insert returns how many of the chunk's rows it inserted; the rest
+// count as skipped. Iteration stops early only when ctx is done.
+//
+// The returned error joins every chunk failure, and is nil when all
+// chunks committed.
+func insertChunks(ctx context.Context, n, size int, insert func(start, end int) (int, error)) (InsertReport, error) {
+	var report InsertReport
+	var errs []error
+	for start := 0; start < n; start += size {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("stopped before row %d: %w", start, err))
+			break
+		}
+
+		end := min(start+size, n)
+		chunk := ChunkReport{Start: start, End: end}
+		inserted, err := insert(start, end)
+		if err != nil {
+			chunk.Failed = end - start
+			chunk.Err = err
+			errs = append(errs, fmt.Errorf("rows %d-%d: %w", start, end-1, err))
+		} else {
+			chunk.Inserted = inserted
+			chunk.Skipped = end - start - inserted
+		}
+		report.Chunks = append(report.Chunks, chunk)
+	}
+
+	if len(errs) > 0 {
+		return report, fmt.Errorf("batch insert failed for %d of %d rows: %w", n-report.Inserted()-report.Skipped(), n, errors.Join(errs...))
+	}
+	return report, nil
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestInsertChunks_PartialFailure verifies a failed chunk is reported
+// without stopping later chunks.
+func TestInsertChunks_PartialFailure(t *testing.T) {
+	bad := errors.New("bad row")
+	report, err := insertChunks(context.Background(), 10, 4, func(start, end int) (int, error) {
+		if start == 4 {
+			return 0, bad
+		}
+		return end - start - 1, nil // One duplicate per chunk
+	})
+	if !errors.Is(err, bad) {
+		t.Fatalf("expected error wrapping chunk failure, got %v", err)
+	}
+
+	if len(report.Chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(report.Chunks))
+	}
+	if report.Inserted() != 4 || report.Skipped() != 2 || report.Failed() != 4 {
+		t.Errorf("expected 4 inserted, 2 skipped, 4 failed, got %d, %d, %d",
+			report.Inserted(), report.Skipped(), report.Failed())
+	}
+	failed := report.FailedChunks()
+	if len(failed) != 1 || failed[0].Start != 4 || failed[0].End != 8 {
+		t.Errorf("expected rows 4-7 to fail, got %+v", failed)
+	}
+}
+
+// TestInsertChunks_Cancelled verifies no chunks run after cancellation.
+func TestInsertChunks_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	report, err := insertChunks(ctx, 10, 4, func(start, end int) (int, error) {
+		calls++
+		cancel()
+		return end - start, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if calls != 1 || report.Inserted() != 4 {
+		t.Errorf("expected one chunk of 4 rows before stopping, got %d calls and %d rows", calls, report.Inserted())
+	}
+}
@@ -0,0 +1,338 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"insolventbydesign/internal/model"
+)
+
+// InstrumentConfig configures an InstrumentedStore.
+type InstrumentConfig struct {
+	Backend       string                // "backend" label value, e.g. DriverPostgres
+	SlowThreshold time.Duration         // Log calls at least this slow; 0 disables
+	Logger        *slog.Logger          // Slow call log; defaults to slog.Default()
+	Registerer    prometheus.Registerer // Defaults to prometheus.DefaultRegisterer
+}
+
+// InstrumentedStore wraps a Store and records the duration and outcome of
+// every call in the storage_query_duration_seconds histogram, labelled by
+// backend, query (the method name) and status (ok, not_found or error).
+//
+// Wrap the database store directly, beneath any CachedStore, so cache hits
+// are not counted as database queries. ForEachSlot durations include the
+// time spent in the callback.
+type InstrumentedStore struct {
+	Store
+	backend  string
+	duration *prometheus.HistogramVec
+	slow     time.Duration
+	logger   *slog.Logger
+}
+
+// NewInstrumentedStore wraps store, registering the query histogram with
+// config.Registerer. Wrapping several stores shares one histogram.
+func NewInstrumentedStore(store Store, config InstrumentConfig) (*InstrumentedStore, error) {
+	registerer := config.Registerer
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	duration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "storage_query_duration_seconds",
+			Help:    "Storage call duration in seconds by backend, query and status",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"backend", "query", "status"},
+	)
+	if err := registerer.Register(duration); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if !errors.As(err, &already) {
+			return nil, fmt.Errorf("failed to register storage metrics: %w", err)
+		}
+		existing, ok := already.ExistingCollector.(*prometheus.HistogramVec)
+		if !ok {
+			return nil, fmt.Errorf("failed to register storage metrics: %w", err)
+		}
+		duration = existing
+	}
+
+	return &InstrumentedStore{
+		Store:    store,
+		backend:  config.Backend,
+		duration: duration,
+		slow:     config.SlowThreshold,
+		logger:   logger,
+	}, nil
+}
+
+// observe records one call that started at start. attrs describe the
+// call's arguments in the slow call log.
+func (s *InstrumentedStore) observe(query string, start time.Time, err error, attrs ...any) {
+	elapsed := time.Since(start)
+
+	status := "ok"
+	switch {
+	case errors.Is(err, ErrNotFound):
+		status = "not_found"
+	case err != nil:
+		status = "error"
+	}
+	s.duration.WithLabelValues(s.backend, query, status).Observe(elapsed.Seconds())
+
+	if s.slow > 0 && elapsed >= s.slow {
+		attrs = append([]any{"backend", s.backend, "query", query, "duration", elapsed, "status", status}, attrs...)
+		if err != nil && status == "error" {
+			attrs = append(attrs, "error", err)
+		}
+		s.logger.Warn("slow storage query", attrs...)
+	}
+}
+
+// The Store methods below time the call to the wrapped store under their
+// own method name.
+
+func (s *InstrumentedStore) InitSchema(ctx context.Context) error {
+	start := time.Now()
+	err := s.Store.InitSchema(ctx)
+	s.observe("InitSchema", start, err)
+	return err
+}
+
+func (s *InstrumentedStore) BatchInsertBribes(ctx context.Context, bribes []model.SlotBribe, relayURL string) (InsertReport, error) {
+	start := time.Now()
+	report, err := s.Store.BatchInsertBribes(ctx, bribes, relayURL)
+	s.observe("BatchInsertBribes", start, err, "rows", len(bribes), "relay", relayURL)
+	return report, err
+}
+
+func (s *InstrumentedStore) BatchInsertBids(ctx context.Context, bids []model.ReceivedBid) error {
+	start := time.Now()
+	err := s.Store.BatchInsertBids(ctx, bids)
+	s.observe("BatchInsertBids", start, err, "rows", len(bids))
+	return err
+}
+
+func (s *InstrumentedStore) GetSlotBids(ctx context.Context, startSlot, endSlot uint64) ([]model.ReceivedBid, error) {
+	start := time.Now()
+	bids, err := s.Store.GetSlotBids(ctx, startSlot, endSlot)
+	s.observe("GetSlotBids", start, err, "start_slot", startSlot, "end_slot", endSlot)
+	return bids, err
+}
+
+func (s *InstrumentedStore) SaveBridgeTVL(ctx context.Context, snapshots []model.BridgeTVL) error {
+	start := time.Now()
+	err := s.Store.SaveBridgeTVL(ctx, snapshots)
+	s.observe("SaveBridgeTVL", start, err, "rows", len(snapshots))
+	return err
+}
+
+func (s *InstrumentedStore) GetBridgeTVL(ctx context.Context, bridge, chain string, from, to time.Time) ([]model.BridgeTVL, error) {
+	start := time.Now()
+	snapshots, err := s.Store.GetBridgeTVL(ctx, bridge, chain, from, to)
+	s.observe("GetBridgeTVL", start, err, "bridge", bridge, "chain", chain, "from", from, "to", to)
+	return snapshots, err
+}
+
+func (s *InstrumentedStore) GetBridgeTVLAt(ctx context.Context, bridge, chain string, at time.Time) (model.BridgeTVL, error) {
+	start := time.Now()
+	snapshot, err := s.Store.GetBridgeTVLAt(ctx, bridge, chain, at)
+	s.observe("GetBridgeTVLAt", start, err, "bridge", bridge, "chain", chain, "at", at)
+	return snapshot, err
+}
+
+func (s *InstrumentedStore) SavePrices(ctx context.Context, quotes []model.PriceQuote) error {
+	start := time.Now()
+	err := s.Store.SavePrices(ctx, quotes)
+	s.observe("SavePrices", start, err, "rows", len(quotes))
+	return err
+}
+
+func (s *InstrumentedStore) PriceAt(ctx context.Context, at time.Time) (model.PriceQuote, error) {
+	start := time.Now()
+	quote, err := s.Store.PriceAt(ctx, at)
+	s.observe("PriceAt", start, err, "at", at)
+	return quote, err
+}
+
+func (s *InstrumentedStore) GetSlotRange(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	start := time.Now()
+	bribes, err := s.Store.GetSlotRange(ctx, startSlot, endSlot)
+	s.observe("GetSlotRange", start, err, "start_slot", startSlot, "end_slot", endSlot)
+	return bribes, err
+}
+
+func (s *InstrumentedStore) GetTimeRange(ctx context.Context, from, to time.Time) ([]model.SlotBribe, error) {
+	start := time.Now()
+	bribes, err := s.Store.GetTimeRange(ctx, from, to)
+	s.observe("GetTimeRange", start, err, "from", from, "to", to)
+	return bribes, err
+}
+
+func (s *InstrumentedStore) ForEachSlot(ctx context.Context, startSlot, endSlot uint64, fn func(model.SlotBribe) error) error {
+	start := time.Now()
+	err := s.Store.ForEachSlot(ctx, startSlot, endSlot, fn)
+	s.observe("ForEachSlot", start, err, "start_slot", startSlot, "end_slot", endSlot)
+	return err
+}
+
+func (s *InstrumentedStore) GetSlotRangeStats(ctx context.Context, startSlot, endSlot uint64) (SlotRangeStats, error) {
+	start := time.Now()
+	stats, err := s.Store.GetSlotRangeStats(ctx, startSlot, endSlot)
+	s.observe("GetSlotRangeStats", start, err, "start_slot", startSlot, "end_slot", endSlot)
+	return stats, err
+}
+
+func (s *InstrumentedStore) GetBuilderStats(ctx context.Context, query BuilderStatsQuery) ([]model.BuilderStats, error) {
+	start := time.Now()
+	stats, err := s.Store.GetBuilderStats(ctx, query)
+	s.observe("GetBuilderStats", start, err, "sort", query.SortBy, "limit", query.Limit, "offset", query.Offset,
+		"start_slot", query.StartSlot, "end_slot", query.EndSlot)
+	return stats, err
+}
+
+func (s *InstrumentedStore) GetBuilderConcentration(ctx context.Context, startSlot, endSlot uint64, topK int) (BuilderConcentration, error) {
+	start := time.Now()
+	concentration, err := s.Store.GetBuilderConcentration(ctx, startSlot, endSlot, topK)
+	s.observe("GetBuilderConcentration", start, err, "start_slot", startSlot, "end_slot", endSlot, "top_k", topK)
+	return concentration, err
+}
+
+func (s *InstrumentedStore) GetBuilder(ctx context.Context, pubkey string) (Builder, error) {
+	start := time.Now()
+	builder, err := s.Store.GetBuilder(ctx, pubkey)
+	s.observe("GetBuilder", start, err, "pubkey", pubkey)
+	return builder, err
+}
+
+func (s *InstrumentedStore) SaveBuilderLabels(ctx context.Context, builders []Builder) error {
+	start := time.Now()
+	err := s.Store.SaveBuilderLabels(ctx, builders)
+	s.observe("SaveBuilderLabels", start, err, "builders", len(builders))
+	return err
+}
+
+func (s *InstrumentedStore) FindGaps(ctx context.Context, startSlot, endSlot uint64) ([]SlotGap, error) {
+	start := time.Now()
+	gaps, err := s.Store.FindGaps(ctx, startSlot, endSlot)
+	s.observe("FindGaps", start, err, "start_slot", startSlot, "end_slot", endSlot)
+	return gaps, err
+}
+
+func (s *InstrumentedStore) FindDiscrepancies(ctx context.Context, startSlot, endSlot uint64) ([]SlotDiscrepancy, error) {
+	start := time.Now()
+	discrepancies, err := s.Store.FindDiscrepancies(ctx, startSlot, endSlot)
+	s.observe("FindDiscrepancies", start, err, "start_slot", startSlot, "end_slot", endSlot)
+	return discrepancies, err
+}
+
+func (s *InstrumentedStore) GetRelayReports(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	start := time.Now()
+	reports, err := s.Store.GetRelayReports(ctx, startSlot, endSlot)
+	s.observe("GetRelayReports", start, err, "start_slot", startSlot, "end_slot", endSlot)
+	return reports, err
+}
+
+func (s *InstrumentedStore) SaveAnalysis(ctx context.Context, record AnalysisRecord) error {
+	start := time.Now()
+	err := s.Store.SaveAnalysis(ctx, record)
+	s.observe("SaveAnalysis", start, err, "start_slot", record.StartSlot, "end_slot", record.EndSlot)
+	return err
+}
+
+func (s *InstrumentedStore) GetAnalysis(ctx context.Context, startSlot, endSlot uint64, topK int) (AnalysisRecord, error) {
+	start := time.Now()
+	record, err := s.Store.GetAnalysis(ctx, startSlot, endSlot, topK)
+	s.observe("GetAnalysis", start, err, "start_slot", startSlot, "end_slot", endSlot, "top_k", topK)
+	return record, err
+}
+
+func (s *InstrumentedStore) GetAnalyses(ctx context.Context, query AnalysisQuery) ([]AnalysisRecord, error) {
+	start := time.Now()
+	records, err := s.Store.GetAnalyses(ctx, query)
+	s.observe("GetAnalyses", start, err)
+	return records, err
+}
+
+// Health times the wrapped store's health check.
+func (s *InstrumentedStore) Health(ctx context.Context) (Health, error) {
+	start := time.Now()
+	health, err := CheckHealth(ctx, s.Store)
+	s.observe("Health", start, err)
+	return health, err
+}
+
+// SubscribeSlots subscribes to the wrapped store's slot notifications;
+// notifications themselves are not timed.
+func (s *InstrumentedStore) SubscribeSlots(ctx context.Context) (<-chan SlotNotification, error) {
+	start := time.Now()
+	notifications, err := SubscribeSlots(ctx, s.Store)
+	s.observe("SubscribeSlots", start, err)
+	return notifications, err
+}
+
+func (s *InstrumentedStore) EnqueueJob(ctx context.Context, kind string, params json.RawMessage) (Job, error) {
+	start := time.Now()
+	queue, err := Jobs(s.Store)
+	var job Job
+	if err == nil {
+		job, err = queue.EnqueueJob(ctx, kind, params)
+	}
+	s.observe("EnqueueJob", start, err, "kind", kind)
+	return job, err
+}
+
+// ClaimJob times a claim; an empty queue is observed as not_found.
+func (s *InstrumentedStore) ClaimJob(ctx context.Context, workerID string) (Job, error) {
+	start := time.Now()
+	queue, err := Jobs(s.Store)
+	var job Job
+	if err == nil {
+		job, err = queue.ClaimJob(ctx, workerID)
+	}
+	s.observe("ClaimJob", start, err, "worker", workerID)
+	return job, err
+}
+
+func (s *InstrumentedStore) CompleteJob(ctx context.Context, id int64, workerID, resultRef string, jobErr error) error {
+	start := time.Now()
+	queue, err := Jobs(s.Store)
+	if err == nil {
+		err = queue.CompleteJob(ctx, id, workerID, resultRef, jobErr)
+	}
+	s.observe("CompleteJob", start, err, "job", id, "worker", workerID)
+	return err
+}
+
+func (s *InstrumentedStore) GetJob(ctx context.Context, id int64) (Job, error) {
+	start := time.Now()
+	queue, err := Jobs(s.Store)
+	var job Job
+	if err == nil {
+		job, err = queue.GetJob(ctx, id)
+	}
+	s.observe("GetJob", start, err, "job", id)
+	return job, err
+}
+
+func (s *InstrumentedStore) RequeueStaleJobs(ctx context.Context, startedBefore time.Time) (int64, error) {
+	start := time.Now()
+	queue, err := Jobs(s.Store)
+	var n int64
+	if err == nil {
+		n, err = queue.RequeueStaleJobs(ctx, startedBefore)
+	}
+	s.observe("RequeueStaleJobs", start, err, "started_before", startedBefore)
+	return n, err
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"insolventbydesign/internal/model"
+)
+
+// sampleCounts returns the histogram sample count per "query/status".
+func sampleCounts(t *testing.T, registry *prometheus.Registry) map[string]uint64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	counts := make(map[string]uint64)
+	for _, family := range families {
+		if family.GetName() != "storage_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["backend"] != DriverSQLite {
+				t.Errorf("expected backend label %q, got %q", DriverSQLite, labels["backend"])
+			}
+			counts[labels["query"]+"/"+labels["status"]] += metric.GetHistogram().GetSampleCount()
+		}
+	}
+	return counts
+}
+
+// TestInstrumentedStore_Metrics verifies calls are counted by query and
+// status, and that a missing analysis is not_found rather than error.
+func TestInstrumentedStore_Metrics(t *testing.T) {
+	ctx := context.Background()
+	registry := prometheus.NewRegistry()
+	store, err := NewInstrumentedStore(newTestSQLiteStore(t), InstrumentConfig{
+		Backend:    DriverSQLite,
+		Registerer: registry,
+	})
+	if err != nil {
+		t.Fatalf("NewInstrumentedStore failed: %v", err)
+	}
+
+	bribes := []model.SlotBribe{{Slot: 1, ValueWei: big.NewInt(10), BuilderPubkey: "0xA"}}
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := store.GetSlotRange(ctx, 0, 10); err != nil {
+			t.Fatalf("GetSlotRange failed: %v", err)
+		}
+	}
+	if _, err := store.GetAnalysis(ctx, 0, 10, 5); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	counts := sampleCounts(t, registry)
+	want := map[string]uint64{
+		"BatchInsertBribes/ok":  1,
+		"GetSlotRange/ok":       2,
+		"GetAnalysis/not_found": 1,
+	}
+	for key, n := range want {
+		if counts[key] != n {
+			t.Errorf("expected %d samples for %s, got %d", n, key, counts[key])
+		}
+	}
+
+	// A second wrapper shares the registered histogram
+	if _, err := NewInstrumentedStore(store.Store, InstrumentConfig{Backend: DriverSQLite, Registerer: registry}); err != nil {
+		t.Errorf("expected re-registration to reuse the histogram, got %v", err)
+	}
+}
+
+// TestInstrumentedStore_SlowLog verifies calls over the threshold are
+// logged with their arguments, and that no threshold logs nothing.
+func TestInstrumentedStore_SlowLog(t *testing.T) {
+	ctx := context.Background()
+	sqlite := newTestSQLiteStore(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	quiet, err := NewInstrumentedStore(sqlite, InstrumentConfig{
+		Backend:    DriverSQLite,
+		Logger:     logger,
+		Registerer: prometheus.NewRegistry(),
+	})
+	if err != nil {
+		t.Fatalf("NewInstrumentedStore failed: %v", err)
+	}
+	if _, err := quiet.GetSlotRange(ctx, 100, 200); err != nil {
+		t.Fatalf("GetSlotRange failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no log without a threshold, got %q", buf.String())
+	}
+
+	slow, err := NewInstrumentedStore(sqlite, InstrumentConfig{
+		Backend:       DriverSQLite,
+		SlowThreshold: 1, // Every call is slow
+		Logger:        logger,
+		Registerer:    prometheus.NewRegistry(),
+	})
+	if err != nil {
+		t.Fatalf("NewInstrumentedStore failed: %v", err)
+	}
+	if _, err := slow.GetSlotRange(ctx, 100, 200); err != nil {
+		t.Fatalf("GetSlotRange failed: %v", err)
+	}
+
+	line := buf.String()
+	for _, want := range []string{"slow storage query", "query=GetSlotRange", "start_slot=100", "end_slot=200"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected log to contain %q, got %q", want, line)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// JobStatus is the lifecycle state of a queued job.
+type JobStatus string
+
+// Job statuses. A job moves from queued to running when a worker claims it,
+// then to succeeded or failed; RequeueStaleJobs returns abandoned running
+// jobs to queued.
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// MaxJobAttempts bounds how often an abandoned job is requeued before it
+// is failed instead, so a job that crashes its workers cannot loop.
+const MaxJobAttempts = 3
+
+// Job is one row of the jobs table.
+type Job struct {
+	ID         int64
+	Kind       string          // What to run, e.g. "montecarlo"
+	Params     json.RawMessage // Kind-specific parameters
+	Status     JobStatus
+	ResultRef  string // Where the worker stored the result, e.g. an analysis key
+	Error      string // Failure reason when Status is JobFailed
+	WorkerID   string // Worker that claimed the job last
+	Attempts   int    // Times the job has been claimed
+	CreatedAt  time.Time
+	StartedAt  time.Time // Zero until claimed
+	FinishedAt time.Time // Zero until finished
+}
+
+// ErrJobNotClaimed is returned when completing a job that is not running
+// on the completing worker, e.g. because it was requeued as stale.
+var ErrJobNotClaimed = errors.New("storage: job not claimed by worker")
+
+// JobQueue is implemented by stores that persist asynchronous analysis
+// jobs. Any number of workers may claim jobs concurrently; each queued job
+// is handed to exactly one of them.
+type JobQueue interface {
+	// EnqueueJob adds a queued job. Params must be valid JSON; nil is
+	// stored as an empty object.
+	EnqueueJob(ctx context.Context, kind string, params json.RawMessage) (Job, error)
+
+	// ClaimJob marks the oldest queued job as running on workerID and
+	// returns it, or ErrNotFound when the queue is empty.
+	ClaimJob(ctx context.Context, workerID string) (Job, error)
+
+	// CompleteJob finishes a job claimed by workerID: succeeded with
+	// resultRef when jobErr is nil, else failed with jobErr's message.
+	CompleteJob(ctx context.Context, id int64, workerID, resultRef string, jobErr error) error
+
+	// GetJob returns the job with id, or ErrNotFound.
+	GetJob(ctx context.Context, id int64) (Job, error)
+
+	// RequeueStaleJobs returns jobs still running since before
+	// startedBefore to the queue, failing those already claimed
+	// MaxJobAttempts times. It returns the number of jobs changed.
+	RequeueStaleJobs(ctx context.Context, startedBefore time.Time) (int64, error)
+}
+
+// Jobs returns store's job queue, failing for stores that cannot persist
+// jobs.
+func Jobs(store Store) (JobQueue, error) {
+	queue, ok := store.(JobQueue)
+	if !ok {
+		return nil, fmt.Errorf("storage: %T does not support job queues", store)
+	}
+	return queue, nil
+}
+
+// jobParams validates params for storage.
+func jobParams(kind string, params json.RawMessage) (string, error) {
+	if kind == "" {
+		return "", fmt.Errorf("job has no kind")
+	}
+	if params == nil {
+		return "{}", nil
+	}
+	if !json.Valid(params) {
+		return "", fmt.Errorf("invalid params for %s job", kind)
+	}
+	return string(params), nil
+}
+
+// jobOutcome returns the final status and error text for CompleteJob.
+func jobOutcome(jobErr error) (JobStatus, string) {
+	if jobErr != nil {
+		return JobFailed, jobErr.Error()
+	}
+	return JobSucceeded, ""
+}
+
+// abandonedJobError is recorded on jobs failed by RequeueStaleJobs.
+var abandonedJobError = fmt.Sprintf("abandoned by its worker %d times", MaxJobAttempts)
+
+// scanJob reads a row of jobColumns with times as Unix seconds, returning
+// ErrNotFound for no row.
+func scanJob(row *sql.Row) (Job, error) {
+	var job Job
+	var params, status string
+	var created int64
+	var started, finished sql.NullInt64
+	err := row.Scan(&job.ID, &job.Kind, &params, &status, &job.ResultRef, &job.Error, &job.WorkerID,
+		&job.Attempts, &created, &started, &finished)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Job{}, ErrNotFound
+	}
+	if err != nil {
+		return Job{}, fmt.Errorf("failed to read job: %w", err)
+	}
+	job.Params = json.RawMessage(params)
+	job.Status = JobStatus(status)
+	job.CreatedAt = time.Unix(created, 0).UTC()
+	if started.Valid {
+		job.StartedAt = time.Unix(started.Int64, 0).UTC()
+	}
+	if finished.Valid {
+		job.FinishedAt = time.Unix(finished.Int64, 0).UTC()
+	}
+	return job, nil
+}
+
+// postgresJobColumns is the column list read by scanJob.
+const postgresJobColumns = `id, kind, params::TEXT, status, result_ref, error, worker_id, attempts,
+	EXTRACT(EPOCH FROM created_at)::BIGINT, EXTRACT(EPOCH FROM started_at)::BIGINT,
+	EXTRACT(EPOCH FROM finished_at)::BIGINT`
+
+// EnqueueJob adds a queued job on the primary.
+func (s *PostgresStore) EnqueueJob(ctx context.Context, kind string, params json.RawMessage) (Job, error) {
+	encoded, err := jobParams(kind, params)
+	if err != nil {
+		return Job{}, err
+	}
+	return scanJob(s.db.QueryRowContext(ctx, `
+		INSERT INTO jobs (kind, params) VALUES ($1, $2::JSONB)
+		RETURNING `+postgresJobColumns, kind, encoded))
+}
+
+// ClaimJob claims the oldest queued job. SKIP LOCKED lets concurrent
+// workers claim different jobs without waiting on each other.
+func (s *PostgresStore) ClaimJob(ctx context.Context, workerID string) (Job, error) {
+	return scanJob(s.db.QueryRowContext(ctx, `
+		UPDATE jobs
+		SET status = 'running', worker_id = $1, attempts = attempts + 1, started_at = NOW()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE status = 'queued'
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+postgresJobColumns, workerID))
+}
+
+// CompleteJob records a claimed job's outcome.
+func (s *PostgresStore) CompleteJob(ctx context.Context, id int64, workerID, resultRef string, jobErr error) error {
+	status, message := jobOutcome(jobErr)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = $3, result_ref = $4, error = $5, finished_at = NOW()
+		WHERE id = $1 AND worker_id = $2 AND status = 'running'
+	`, id, workerID, string(status), resultRef, message)
+	return checkJobCompleted(result, err, id, workerID)
+}
+
+// GetJob reads a job from the primary, so a just-enqueued job is visible
+// regardless of replica lag.
+func (s *PostgresStore) GetJob(ctx context.Context, id int64) (Job, error) {
+	return scanJob(s.db.QueryRowContext(ctx, `SELECT `+postgresJobColumns+` FROM jobs WHERE id = $1`, id))
+}
+
+// RequeueStaleJobs requeues or fails jobs abandoned by their workers.
+func (s *PostgresStore) RequeueStaleJobs(ctx context.Context, startedBefore time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = CASE WHEN attempts >= $2 THEN 'failed' ELSE 'queued' END,
+			error = CASE WHEN attempts >= $2 THEN $3 ELSE '' END,
+			finished_at = CASE WHEN attempts >= $2 THEN NOW() END
+		WHERE status = 'running' AND started_at < $1
+	`, startedBefore, MaxJobAttempts, abandonedJobError)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// sqliteJobColumns is the column list read by scanJob.
+const sqliteJobColumns = `id, kind, params, status, result_ref, error, worker_id, attempts,
+	created_at, started_at, finished_at`
+
+// EnqueueJob adds a queued job.
+func (s *SQLiteStore) EnqueueJob(ctx context.Context, kind string, params json.RawMessage) (Job, error) {
+	encoded, err := jobParams(kind, params)
+	if err != nil {
+		return Job{}, err
+	}
+	return scanJob(s.db.QueryRowContext(ctx, `
+		INSERT INTO jobs (kind, params, created_at) VALUES (?, ?, ?)
+		RETURNING `+sqliteJobColumns, kind, encoded, time.Now().Unix()))
+}
+
+// ClaimJob claims the oldest queued job. SQLite serializes writers, so the
+// single UPDATE cannot hand a job to two workers.
+func (s *SQLiteStore) ClaimJob(ctx context.Context, workerID string) (Job, error) {
+	return scanJob(s.db.QueryRowContext(ctx, `
+		UPDATE jobs
+		SET status = 'running', worker_id = ?, attempts = attempts + 1, started_at = ?
+		WHERE id = (SELECT id FROM jobs WHERE status = 'queued' ORDER BY id LIMIT 1)
+		RETURNING `+sqliteJobColumns, workerID, time.Now().Unix()))
+}
+
+// CompleteJob records a claimed job's outcome.
+func (s *SQLiteStore) CompleteJob(ctx context.Context, id int64, workerID, resultRef string, jobErr error) error {
+	status, message := jobOutcome(jobErr)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = ?, result_ref = ?, error = ?, finished_at = ?
+		WHERE id = ? AND worker_id = ? AND status = 'running'
+	`, string(status), resultRef, message, time.Now().Unix(), id, workerID)
+	return checkJobCompleted(result, err, id, workerID)
+}
+
+// GetJob returns the job with id.
+func (s *SQLiteStore) GetJob(ctx context.Context, id int64) (Job, error) {
+	return scanJob(s.db.QueryRowContext(ctx, `SELECT `+sqliteJobColumns+` FROM jobs WHERE id = ?`, id))
+}
+
+// RequeueStaleJobs requeues or fails jobs abandoned by their workers.
+func (s *SQLiteStore) RequeueStaleJobs(ctx context.Context, startedBefore time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = CASE WHEN attempts >= ?1 THEN 'failed' ELSE 'queued' END,
+			error = CASE WHEN attempts >= ?1 THEN ?2 ELSE '' END,
+			finished_at = CASE WHEN attempts >= ?1 THEN ?3 END
+		WHERE status = 'running' AND started_at < ?4
+	`, MaxJobAttempts, abandonedJobError, time.Now().Unix(), startedBefore.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// checkJobCompleted maps a CompleteJob update that matched no row to
+// ErrJobNotClaimed.
+func checkJobCompleted(result sql.Result, err error, id int64, workerID string) error {
+	if err != nil {
+		return fmt.Errorf("failed to complete job %d: %w", id, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to complete job %d: %w", id, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("job %d on worker %s: %w", id, workerID, ErrJobNotClaimed)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationFiles holds versioned schema migrations per backend:
+//
+//	migrations/<backend>/NNNN_name.up.sql
+//	migrations/<backend>/NNNN_name.down.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrator is implemented by stores whose schema evolves through
+// versioned migrations.
+type Migrator interface {
+	// SchemaVersion returns the applied and latest available versions.
+	SchemaVersion(ctx context.Context) (current, latest int, err error)
+
+	// MigrateTo applies up or down migrations until the schema is at version.
+	MigrateTo(ctx context.Context, version int) error
+}
+
+// MigratableStore is a Store whose schema can be migrated explicitly.
+type MigratableStore interface {
+	Store
+	Migrator
+}
+
+// OpenForMigration connects to the backend selected by config without
+// applying any migrations, so callers can inspect or roll back the schema.
+func OpenForMigration(config Config) (MigratableStore, error) {
+	switch config.Driver {
+	case DriverPostgres, "":
+		return NewPostgresStore(config)
+	case DriverSQLite:
+		return openSQLiteStore(config.Path)
+	default:
+		return nil, fmt.Errorf("storage driver '%s' does not support migrations", config.Driver)
+	}
+}
+
+// loadMigrations reads and validates the migrations embedded for a backend.
+func loadMigrations(backend string) ([]Migration, error) {
+	dir := path.Join("migrations", backend)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations for %s: %w", backend, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, label, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %s", name)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("invalid migration version in %s", name)
+		}
+
+		data, err := fs.ReadFile(migrationFiles, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("migration %d has conflicting names %s and %s", version, m.Name, label)
+		}
+		if direction == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d (%s) has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	// Versions must be contiguous from 1
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("missing migration version %d for %s", i+1, backend)
+		}
+	}
+
+	return migrations, nil
+}
+
+// sqlMigrator applies migrations to a database/sql connection,
+// tracking progress in the schema_version table.
+type sqlMigrator struct {
+	db          *sql.DB
+	migrations  []Migration
+	placeholder func(n int) string // Bind parameter syntax of the dialect
+}
+
+func newSQLMigrator(db *sql.DB, backend string, placeholder func(n int) string) (*sqlMigrator, error) {
+	migrations, err := loadMigrations(backend)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlMigrator{db: db, migrations: migrations, placeholder: placeholder}, nil
+}
+
+func (m *sqlMigrator) latest() int {
+	return len(m.migrations)
+}
+
+func (m *sqlMigrator) ensureVersionTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_version table: %w", err)
+	}
+	return nil
+}
+
+func (m *sqlMigrator) current(ctx context.Context) (int, error) {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return 0, err
+	}
+	var version sql.NullInt64
+	if err := m.db.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+// migrateTo moves the schema to target, one transaction per migration.
+func (m *sqlMigrator) migrateTo(ctx context.Context, target int) error {
+	if target < 0 || target > m.latest() {
+		return fmt.Errorf("invalid target version %d (latest is %d)", target, m.latest())
+	}
+
+	current, err := m.current(ctx)
+	if err != nil {
+		return err
+	}
+	if current > m.latest() {
+		return fmt.Errorf("database schema version %d is newer than this binary supports (%d)", current, m.latest())
+	}
+
+	for current < target {
+		next := m.migrations[current]
+		err := m.apply(ctx, next.Up,
+			"INSERT INTO schema_version (version, name) VALUES ("+m.placeholder(1)+", "+m.placeholder(2)+")",
+			next.Version, next.Name)
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) up failed: %w", next.Version, next.Name, err)
+		}
+		current++
+	}
+
+	for current > target {
+		prev := m.migrations[current-1]
+		if prev.Down == "" {
+			return fmt.Errorf("migration %d (%s) cannot be reverted: no down script", prev.Version, prev.Name)
+		}
+		err := m.apply(ctx, prev.Down,
+			"DELETE FROM schema_version WHERE version = "+m.placeholder(1),
+			prev.Version)
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) down failed: %w", prev.Version, prev.Name, err)
+		}
+		current--
+	}
+
+	return nil
+}
+
+func (m *sqlMigrator) apply(ctx context.Context, script, bookkeeping string, args ...interface{}) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return tx.Commit()
+}
+
+func postgresPlaceholder(n int) string { return "$" + strconv.Itoa(n) }
+func sqlitePlaceholder(int) string     { return "?" }
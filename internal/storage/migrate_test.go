@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadMigrations_Contiguous verifies embedded migrations are numbered
+// from 1 without gaps and have down scripts.
+func TestLoadMigrations_Contiguous(t *testing.T) {
+	for _, backend := range []string{"postgres", "sqlite"} {
+		migrations, err := loadMigrations(backend)
+		if err != nil {
+			t.Fatalf("loadMigrations(%s) failed: %v", backend, err)
+		}
+		if len(migrations) == 0 {
+			t.Fatalf("expected migrations for %s, got none", backend)
+		}
+		for i, m := range migrations {
+			if m.Version != i+1 {
+				t.Errorf("%s: expected version %d, got %d", backend, i+1, m.Version)
+			}
+			if m.Down == "" {
+				t.Errorf("%s: migration %d has no down script", backend, m.Version)
+			}
+		}
+	}
+}
+
+// TestSQLiteStore_MigrateUpDown verifies migrations apply, revert and
+// re-apply cleanly while tracking the schema version.
+func TestSQLiteStore_MigrateUpDown(t *testing.T) {
+	ctx := context.Background()
+	store, err := openSQLiteStore(filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatalf("openSQLiteStore failed: %v", err)
+	}
+	defer store.Close()
+
+	current, latest, err := store.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatalf("SchemaVersion failed: %v", err)
+	}
+	if current != 0 {
+		t.Fatalf("expected fresh database at version 0, got %d", current)
+	}
+
+	if err := store.MigrateTo(ctx, latest); err != nil {
+		t.Fatalf("MigrateTo(%d) failed: %v", latest, err)
+	}
+	if current, _, _ = store.SchemaVersion(ctx); current != latest {
+		t.Fatalf("expected version %d after up, got %d", latest, current)
+	}
+
+	if err := store.MigrateTo(ctx, 0); err != nil {
+		t.Fatalf("MigrateTo(0) failed: %v", err)
+	}
+	var tables int
+	if err := store.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'slot_bribes'").Scan(&tables); err != nil {
+		t.Fatalf("failed to inspect schema: %v", err)
+	}
+	if tables != 0 {
+		t.Errorf("expected slot_bribes to be dropped after down migration")
+	}
+
+	if err := store.InitSchema(ctx); err != nil {
+		t.Fatalf("InitSchema after down failed: %v", err)
+	}
+	if current, _, _ = store.SchemaVersion(ctx); current != latest {
+		t.Errorf("expected version %d after re-apply, got %d", latest, current)
+	}
+}
+
+// TestSQLiteStore_MigrateInvalidTarget verifies out-of-range versions are rejected.
+func TestSQLiteStore_MigrateInvalidTarget(t *testing.T) {
+	store := newTestSQLiteStore(t)
+
+	_, latest, err := store.SchemaVersion(context.Background())
+	if err != nil {
+		t.Fatalf("SchemaVersion failed: %v", err)
+	}
+	if err := store.MigrateTo(context.Background(), latest+1); err == nil {
+		t.Error("Expected error for version beyond latest, got nil")
+	}
+}
+
+// TestVerifySchema verifies a partially migrated database reports pending
+// migrations and missing tables, and a fully migrated one passes.
+func TestVerifySchema(t *testing.T) {
+	ctx := context.Background()
+	store, err := openSQLiteStore(filepath.Join(t.TempDir(), "verify.db"))
+	if err != nil {
+		t.Fatalf("openSQLiteStore failed: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.MigrateTo(ctx, 1); err != nil {
+		t.Fatalf("MigrateTo(1) failed: %v", err)
+	}
+	var schemaErr *SchemaError
+	if err := VerifySchema(ctx, store); !errors.As(err, &schemaErr) {
+		t.Fatalf("expected SchemaError, got %v", err)
+	}
+	message := schemaErr.Error()
+	if !strings.Contains(message, "pending migrations") || !strings.Contains(message, "received_bids") {
+		t.Errorf("expected pending migrations and missing received_bids reported, got %q", message)
+	}
+	if strings.Contains(message, "slot_bribes") {
+		t.Errorf("expected slot_bribes present after migration 1, got %q", message)
+	}
+
+	if err := store.InitSchema(ctx); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+	if err := VerifySchema(ctx, store); err != nil {
+		t.Errorf("expected migrated schema to verify, got %v", err)
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS censorship_analysis;
+DROP MATERIALIZED VIEW IF EXISTS builder_stats;
+DROP TABLE IF EXISTS slot_bribes;
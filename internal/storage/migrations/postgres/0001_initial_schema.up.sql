@@ -0,0 +1,59 @@
+-- Enable TimescaleDB extension
+CREATE EXTENSION IF NOT EXISTS timescaledb;
+
+-- Slot bribes table (time-series data)
+CREATE TABLE IF NOT EXISTS slot_bribes (
+	slot_number BIGINT NOT NULL,
+	slot_time TIMESTAMPTZ NOT NULL,
+	value_wei NUMERIC(78, 0) NOT NULL,  -- Supports up to 2^256
+	value_eth DOUBLE PRECISION NOT NULL,
+	builder_pubkey TEXT NOT NULL,
+	block_hash TEXT NOT NULL,
+	relay_url TEXT NOT NULL,
+	fetched_at TIMESTAMPTZ DEFAULT NOW(),
+	PRIMARY KEY (slot_time, slot_number)
+);
+
+-- Convert to hypertable for time-series optimization
+SELECT create_hypertable('slot_bribes', 'slot_time', if_not_exists => TRUE);
+
+-- Indexes for common queries
+CREATE INDEX IF NOT EXISTS idx_slot_bribes_slot ON slot_bribes (slot_number);
+CREATE INDEX IF NOT EXISTS idx_slot_bribes_builder ON slot_bribes (builder_pubkey);
+CREATE INDEX IF NOT EXISTS idx_slot_bribes_value ON slot_bribes (value_eth DESC);
+
+-- Builder statistics materialized view (auto-refreshing)
+CREATE MATERIALIZED VIEW IF NOT EXISTS builder_stats AS
+SELECT
+	builder_pubkey,
+	COUNT(*) as block_count,
+	SUM(value_eth) as total_value_eth,
+	AVG(value_eth) as avg_value_eth,
+	MAX(value_eth) as max_value_eth,
+	MIN(value_eth) as min_value_eth,
+	STDDEV(value_eth) as stddev_value_eth
+FROM slot_bribes
+GROUP BY builder_pubkey
+ORDER BY block_count DESC;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_builder_stats_pubkey ON builder_stats (builder_pubkey);
+
+-- Censorship cost analysis table
+CREATE TABLE IF NOT EXISTS censorship_analysis (
+	id SERIAL PRIMARY KEY,
+	start_slot BIGINT NOT NULL,
+	end_slot BIGINT NOT NULL,
+	duration_slots INT NOT NULL,
+	total_cost_wei NUMERIC(78, 0) NOT NULL,
+	total_cost_eth DOUBLE PRECISION NOT NULL,
+	total_cost_usd DOUBLE PRECISION,
+	builder_concentration DOUBLE PRECISION NOT NULL,
+	top_k_builders INT NOT NULL,
+	effective_cost_eth DOUBLE PRECISION NOT NULL,
+	breakeven_tvl_usd DOUBLE PRECISION,
+	success_probability DOUBLE PRECISION,
+	computed_at TIMESTAMPTZ DEFAULT NOW(),
+	UNIQUE(start_slot, end_slot, top_k_builders)
+);
+
+CREATE INDEX IF NOT EXISTS idx_censorship_analysis_slots ON censorship_analysis (start_slot, end_slot);
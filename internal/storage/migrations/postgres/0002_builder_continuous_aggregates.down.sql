@@ -0,0 +1,17 @@
+DROP MATERIALIZED VIEW IF EXISTS builder_stats_daily;
+DROP MATERIALIZED VIEW IF EXISTS builder_stats_hourly;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS builder_stats AS
+SELECT
+	builder_pubkey,
+	COUNT(*) as block_count,
+	SUM(value_eth) as total_value_eth,
+	AVG(value_eth) as avg_value_eth,
+	MAX(value_eth) as max_value_eth,
+	MIN(value_eth) as min_value_eth,
+	STDDEV(value_eth) as stddev_value_eth
+FROM slot_bribes
+GROUP BY builder_pubkey
+ORDER BY block_count DESC;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_builder_stats_pubkey ON builder_stats (builder_pubkey);
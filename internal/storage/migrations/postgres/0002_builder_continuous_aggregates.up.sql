@@ -0,0 +1,50 @@
+-- Replace the manually refreshed builder_stats view with TimescaleDB
+-- continuous aggregates that are maintained incrementally in the background.
+DROP MATERIALIZED VIEW IF EXISTS builder_stats;
+
+-- Hourly per-builder block counts and value sums
+CREATE MATERIALIZED VIEW IF NOT EXISTS builder_stats_hourly
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT
+	time_bucket(INTERVAL '1 hour', slot_time) AS bucket,
+	builder_pubkey,
+	COUNT(*) AS block_count,
+	SUM(value_wei) AS total_value_wei,
+	SUM(value_eth) AS total_value_eth,
+	MAX(value_eth) AS max_value_eth,
+	MIN(value_eth) AS min_value_eth
+FROM slot_bribes
+GROUP BY bucket, builder_pubkey
+WITH NO DATA;
+
+-- Daily rollup used for all-time builder statistics
+CREATE MATERIALIZED VIEW IF NOT EXISTS builder_stats_daily
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT
+	time_bucket(INTERVAL '1 day', slot_time) AS bucket,
+	builder_pubkey,
+	COUNT(*) AS block_count,
+	SUM(value_wei) AS total_value_wei,
+	SUM(value_eth) AS total_value_eth,
+	MAX(value_eth) AS max_value_eth,
+	MIN(value_eth) AS min_value_eth
+FROM slot_bribes
+GROUP BY bucket, builder_pubkey
+WITH NO DATA;
+
+CREATE INDEX IF NOT EXISTS idx_builder_stats_hourly_builder ON builder_stats_hourly (builder_pubkey, bucket);
+CREATE INDEX IF NOT EXISTS idx_builder_stats_daily_builder ON builder_stats_daily (builder_pubkey, bucket);
+
+-- Background refresh; rows newer than end_offset are aggregated at query
+-- time because materialized_only is false.
+SELECT add_continuous_aggregate_policy('builder_stats_hourly',
+	start_offset => NULL,
+	end_offset => INTERVAL '1 hour',
+	schedule_interval => INTERVAL '15 minutes',
+	if_not_exists => TRUE);
+
+SELECT add_continuous_aggregate_policy('builder_stats_daily',
+	start_offset => NULL,
+	end_offset => INTERVAL '1 day',
+	schedule_interval => INTERVAL '1 hour',
+	if_not_exists => TRUE);
@@ -0,0 +1,26 @@
+-- Track each builder's most recent slot so stats can be ordered by last seen.
+-- Continuous aggregates cannot gain columns in place, so the daily rollup is rebuilt.
+DROP MATERIALIZED VIEW IF EXISTS builder_stats_daily;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS builder_stats_daily
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT
+	time_bucket(INTERVAL '1 day', slot_time) AS bucket,
+	builder_pubkey,
+	COUNT(*) AS block_count,
+	SUM(value_wei) AS total_value_wei,
+	SUM(value_eth) AS total_value_eth,
+	MAX(value_eth) AS max_value_eth,
+	MIN(value_eth) AS min_value_eth,
+	MAX(slot_number) AS last_slot
+FROM slot_bribes
+GROUP BY bucket, builder_pubkey
+WITH NO DATA;
+
+CREATE INDEX IF NOT EXISTS idx_builder_stats_daily_builder ON builder_stats_daily (builder_pubkey, bucket);
+
+SELECT add_continuous_aggregate_policy('builder_stats_daily',
+	start_offset => NULL,
+	end_offset => INTERVAL '1 day',
+	schedule_interval => INTERVAL '1 hour',
+	if_not_exists => TRUE);
@@ -0,0 +1,5 @@
+-- Top builders of each stored analysis, as a JSON array, so cached
+-- results can be served without reloading the slot range.
+ALTER TABLE censorship_analysis ADD COLUMN IF NOT EXISTS top_builders JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_censorship_analysis_computed ON censorship_analysis (computed_at DESC);
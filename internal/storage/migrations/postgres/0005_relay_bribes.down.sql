@@ -0,0 +1,2 @@
+DROP VIEW IF EXISTS slot_relay_discrepancies;
+DROP TABLE IF EXISTS relay_bribes;
@@ -0,0 +1,34 @@
+-- Every relay's report for a slot. slot_bribes keeps one canonical record
+-- per slot (chosen by the ingest ConflictPolicy); relay_bribes keeps the
+-- provenance needed to audit where relays disagree.
+CREATE TABLE IF NOT EXISTS relay_bribes (
+	slot_number BIGINT NOT NULL,
+	slot_time TIMESTAMPTZ NOT NULL,
+	relay_url TEXT NOT NULL,
+	value_wei NUMERIC(78, 0) NOT NULL,
+	builder_pubkey TEXT NOT NULL,
+	block_hash TEXT NOT NULL,
+	fetched_at TIMESTAMPTZ DEFAULT NOW(),
+	PRIMARY KEY (slot_time, slot_number, relay_url)
+);
+
+SELECT create_hypertable('relay_bribes', 'slot_time', if_not_exists => TRUE);
+
+CREATE INDEX IF NOT EXISTS idx_relay_bribes_slot ON relay_bribes (slot_number);
+
+-- Existing canonical rows are the only reports known so far
+INSERT INTO relay_bribes (slot_number, slot_time, relay_url, value_wei, builder_pubkey, block_hash, fetched_at)
+SELECT slot_number, slot_time, relay_url, value_wei, builder_pubkey, block_hash, fetched_at
+FROM slot_bribes
+ON CONFLICT DO NOTHING;
+
+-- Slots where relays reported different values or builders
+CREATE OR REPLACE VIEW slot_relay_discrepancies AS
+SELECT
+	slot_number,
+	COUNT(*) AS relay_count,
+	COUNT(DISTINCT value_wei) AS distinct_values,
+	COUNT(DISTINCT builder_pubkey) AS distinct_builders
+FROM relay_bribes
+GROUP BY slot_number
+HAVING COUNT(DISTINCT value_wei) > 1 OR COUNT(DISTINCT builder_pubkey) > 1;
@@ -0,0 +1,18 @@
+-- One row per builder pubkey: the slots any relay first and last reported
+-- it (maintained on ingestion) and its operating entity and free-form
+-- labels (maintained from the builder registry).
+CREATE TABLE IF NOT EXISTS builders (
+	pubkey TEXT PRIMARY KEY,
+	entity_name TEXT,
+	first_seen BIGINT,                   -- Slot numbers; NULL until ingested
+	last_seen BIGINT,
+	labels JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX IF NOT EXISTS idx_builders_entity ON builders (entity_name);
+
+INSERT INTO builders (pubkey, first_seen, last_seen)
+SELECT builder_pubkey, MIN(slot_number), MAX(slot_number)
+FROM relay_bribes
+GROUP BY builder_pubkey
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,20 @@
+-- Every bid a relay received (builder_blocks_received), not only the
+-- delivered winner in slot_bribes. A block submitted to several relays
+-- appears once per relay.
+CREATE TABLE IF NOT EXISTS received_bids (
+	slot_number BIGINT NOT NULL,
+	slot_time TIMESTAMPTZ NOT NULL,
+	relay_url TEXT NOT NULL,
+	block_hash TEXT NOT NULL,
+	builder_pubkey TEXT NOT NULL,
+	value_wei NUMERIC(78, 0) NOT NULL,
+	received_at TIMESTAMPTZ,              -- Relay receive time; NULL if not reported
+	fetched_at TIMESTAMPTZ DEFAULT NOW(),
+	PRIMARY KEY (slot_time, slot_number, relay_url, block_hash)
+);
+
+SELECT create_hypertable('received_bids', 'slot_time', if_not_exists => TRUE);
+
+-- Second-price and depth queries rank the bids of a slot by value
+CREATE INDEX IF NOT EXISTS idx_received_bids_slot_value ON received_bids (slot_number, value_wei DESC);
+CREATE INDEX IF NOT EXISTS idx_received_bids_builder ON received_bids (builder_pubkey, slot_time DESC);
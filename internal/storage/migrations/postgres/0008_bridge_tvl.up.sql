@@ -0,0 +1,15 @@
+-- Historical bridge TVL, so breakeven comparisons can use the value at
+-- risk during the analyzed slot range. Sources may disagree, so each
+-- source's snapshot is kept.
+CREATE TABLE IF NOT EXISTS bridge_tvl (
+	bridge TEXT NOT NULL,
+	chain TEXT NOT NULL,
+	snapshot_time TIMESTAMPTZ NOT NULL,
+	tvl_usd DOUBLE PRECISION NOT NULL,
+	source TEXT NOT NULL,
+	fetched_at TIMESTAMPTZ DEFAULT NOW(),
+	PRIMARY KEY (bridge, chain, snapshot_time, source)
+);
+
+-- Point-in-time lookups across chains
+CREATE INDEX IF NOT EXISTS idx_bridge_tvl_time ON bridge_tvl (bridge, snapshot_time DESC);
@@ -0,0 +1,9 @@
+-- Periodic ETH/USD quotes, so USD conversions use the price at the
+-- analyzed slot range instead of a fixed assumption.
+CREATE TABLE IF NOT EXISTS prices (
+	quote_time TIMESTAMPTZ NOT NULL,
+	source TEXT NOT NULL,
+	price_usd DOUBLE PRECISION NOT NULL,
+	fetched_at TIMESTAMPTZ DEFAULT NOW(),
+	PRIMARY KEY (quote_time, source)
+);
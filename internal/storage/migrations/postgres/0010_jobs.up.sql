@@ -0,0 +1,20 @@
+-- Durable queue of asynchronous analysis jobs (Monte Carlo runs, parameter
+-- sweeps), so long work survives API restarts and can run on any worker.
+CREATE TABLE IF NOT EXISTS jobs (
+	id BIGSERIAL PRIMARY KEY,
+	kind TEXT NOT NULL,
+	params JSONB NOT NULL DEFAULT '{}',
+	status TEXT NOT NULL DEFAULT 'queued'
+		CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+	result_ref TEXT NOT NULL DEFAULT '',  -- Where the worker stored the result
+	error TEXT NOT NULL DEFAULT '',
+	worker_id TEXT NOT NULL DEFAULT '',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	started_at TIMESTAMPTZ,
+	finished_at TIMESTAMPTZ
+);
+
+-- Claims scan only queued jobs, oldest first
+CREATE INDEX IF NOT EXISTS idx_jobs_queued ON jobs (id) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_jobs_running ON jobs (started_at) WHERE status = 'running';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS censorship_analysis;
+DROP TABLE IF EXISTS slot_bribes;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS slot_bribes (
+	slot_number INTEGER NOT NULL,
+	slot_time INTEGER NOT NULL,          -- Unix seconds
+	value_wei TEXT NOT NULL,             -- Exact decimal string
+	value_eth REAL NOT NULL,
+	builder_pubkey TEXT NOT NULL,
+	block_hash TEXT NOT NULL,
+	relay_url TEXT NOT NULL,
+	fetched_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	PRIMARY KEY (slot_time, slot_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_slot_bribes_slot ON slot_bribes (slot_number);
+CREATE INDEX IF NOT EXISTS idx_slot_bribes_builder ON slot_bribes (builder_pubkey);
+
+CREATE TABLE IF NOT EXISTS censorship_analysis (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	start_slot INTEGER NOT NULL,
+	end_slot INTEGER NOT NULL,
+	duration_slots INTEGER NOT NULL,
+	total_cost_wei TEXT NOT NULL,
+	total_cost_eth REAL NOT NULL,
+	total_cost_usd REAL,
+	builder_concentration REAL NOT NULL,
+	top_k_builders INTEGER NOT NULL,
+	effective_cost_eth REAL NOT NULL,
+	breakeven_tvl_usd REAL,
+	success_probability REAL,
+	computed_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	UNIQUE(start_slot, end_slot, top_k_builders)
+);
+
+CREATE INDEX IF NOT EXISTS idx_censorship_analysis_slots ON censorship_analysis (start_slot, end_slot);
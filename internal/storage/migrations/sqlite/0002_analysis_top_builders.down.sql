@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_censorship_analysis_computed;
+ALTER TABLE censorship_analysis DROP COLUMN top_builders;
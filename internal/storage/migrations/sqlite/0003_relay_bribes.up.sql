@@ -0,0 +1,28 @@
+-- Every relay's report for a slot. slot_bribes keeps one canonical record
+-- per slot (chosen by the ingest ConflictPolicy); relay_bribes keeps the
+-- provenance needed to audit where relays disagree.
+CREATE TABLE IF NOT EXISTS relay_bribes (
+	slot_number INTEGER NOT NULL,
+	relay_url TEXT NOT NULL,
+	value_wei TEXT NOT NULL,             -- Exact decimal string
+	builder_pubkey TEXT NOT NULL,
+	block_hash TEXT NOT NULL,
+	fetched_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	PRIMARY KEY (slot_number, relay_url)
+);
+
+-- Existing canonical rows are the only reports known so far
+INSERT OR IGNORE INTO relay_bribes (slot_number, relay_url, value_wei, builder_pubkey, block_hash, fetched_at)
+SELECT slot_number, relay_url, value_wei, builder_pubkey, block_hash, fetched_at
+FROM slot_bribes;
+
+-- Slots where relays reported different values or builders
+CREATE VIEW IF NOT EXISTS slot_relay_discrepancies AS
+SELECT
+	slot_number,
+	COUNT(*) AS relay_count,
+	COUNT(DISTINCT value_wei) AS distinct_values,
+	COUNT(DISTINCT builder_pubkey) AS distinct_builders
+FROM relay_bribes
+GROUP BY slot_number
+HAVING COUNT(DISTINCT value_wei) > 1 OR COUNT(DISTINCT builder_pubkey) > 1;
@@ -0,0 +1,17 @@
+-- One row per builder pubkey: the slots any relay first and last reported
+-- it (maintained on ingestion) and its operating entity and free-form
+-- labels (maintained from the builder registry).
+CREATE TABLE IF NOT EXISTS builders (
+	pubkey TEXT PRIMARY KEY,
+	entity_name TEXT,
+	first_seen INTEGER,                  -- Slot numbers; NULL until ingested
+	last_seen INTEGER,
+	labels TEXT NOT NULL DEFAULT '[]'    -- JSON array
+);
+
+CREATE INDEX IF NOT EXISTS idx_builders_entity ON builders (entity_name);
+
+INSERT OR IGNORE INTO builders (pubkey, first_seen, last_seen)
+SELECT builder_pubkey, MIN(slot_number), MAX(slot_number)
+FROM relay_bribes
+GROUP BY builder_pubkey;
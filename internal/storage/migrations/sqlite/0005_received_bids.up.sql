@@ -0,0 +1,15 @@
+-- Every bid a relay received (builder_blocks_received), not only the
+-- delivered winner in slot_bribes. A block submitted to several relays
+-- appears once per relay.
+CREATE TABLE IF NOT EXISTS received_bids (
+	slot_number INTEGER NOT NULL,
+	relay_url TEXT NOT NULL,
+	block_hash TEXT NOT NULL,
+	builder_pubkey TEXT NOT NULL,
+	value_wei TEXT NOT NULL,             -- Exact decimal string
+	received_at INTEGER,                 -- Relay receive time, Unix milliseconds
+	fetched_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	PRIMARY KEY (slot_number, relay_url, block_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_received_bids_builder ON received_bids (builder_pubkey, slot_number);
@@ -0,0 +1,14 @@
+-- Historical bridge TVL, so breakeven comparisons can use the value at
+-- risk during the analyzed slot range. Sources may disagree, so each
+-- source's snapshot is kept.
+CREATE TABLE IF NOT EXISTS bridge_tvl (
+	bridge TEXT NOT NULL,
+	chain TEXT NOT NULL,
+	snapshot_time INTEGER NOT NULL,      -- Unix seconds
+	tvl_usd REAL NOT NULL,
+	source TEXT NOT NULL,
+	fetched_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	PRIMARY KEY (bridge, chain, snapshot_time, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bridge_tvl_time ON bridge_tvl (bridge, snapshot_time);
@@ -0,0 +1,9 @@
+-- Periodic ETH/USD quotes, so USD conversions use the price at the
+-- analyzed slot range instead of a fixed assumption.
+CREATE TABLE IF NOT EXISTS prices (
+	quote_time INTEGER NOT NULL,         -- Unix seconds
+	source TEXT NOT NULL,
+	price_usd REAL NOT NULL,
+	fetched_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	PRIMARY KEY (quote_time, source)
+);
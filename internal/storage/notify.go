@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SlotChannel is the Postgres NOTIFY channel announcing slot_bribes writes.
+const SlotChannel = "slot_bribes"
+
+// SlotNotification announces the slots one committed insert transaction
+// added to or replaced in slot_bribes.
+type SlotNotification struct {
+	StartSlot uint64 `json:"start_slot"` // Lowest slot written
+	EndSlot   uint64 `json:"end_slot"`   // Highest slot written
+	Count     int    `json:"count"`      // Rows written; the range may have gaps
+
+	// Resync is set instead of a range when notifications may have been
+	// missed, e.g. after the listener reconnected. Subscribers should
+	// re-read whatever state they derive from stored slots.
+	Resync bool `json:"-"`
+}
+
+// SlotSubscriber is implemented by stores that push notifications of
+// newly stored slots, so consumers need not poll.
+type SlotSubscriber interface {
+	// SubscribeSlots delivers a notification for every committed insert
+	// until ctx is done, then closes the channel. A slow consumer delays
+	// delivery but does not lose notifications until the server's queue
+	// fills.
+	SubscribeSlots(ctx context.Context) (<-chan SlotNotification, error)
+}
+
+// SubscribeSlots subscribes to store's slot notifications, failing for
+// stores that cannot publish them.
+func SubscribeSlots(ctx context.Context, store Store) (<-chan SlotNotification, error) {
+	subscriber, ok := store.(SlotSubscriber)
+	if !ok {
+		return nil, fmt.Errorf("storage: %T does not publish slot notifications", store)
+	}
+	return subscriber.SubscribeSlots(ctx)
+}
+
+// notifySlots queues a notification on tx; Postgres delivers it only if
+// and when tx commits.
+func notifySlots(ctx context.Context, tx *sql.Tx, n SlotNotification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "SELECT pg_notify($1, $2)", SlotChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify %s: %w", SlotChannel, err)
+	}
+	return nil
+}
+
+// listenerPingInterval is how often an idle listener checks its connection.
+const listenerPingInterval = 90 * time.Second
+
+// SubscribeSlots listens on SlotChannel over a dedicated connection to the
+// primary, reconnecting with backoff if it drops.
+func (s *PostgresStore) SubscribeSlots(ctx context.Context) (<-chan SlotNotification, error) {
+	listener := pq.NewListener(s.connStr, time.Second, time.Minute, nil)
+	if err := listener.Listen(SlotChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", SlotChannel, err)
+	}
+
+	notifications := make(chan SlotNotification)
+	go func() {
+		defer close(notifications)
+		defer listener.Close()
+
+		ping := time.NewTicker(listenerPingInterval)
+		defer ping.Stop()
+
+		for {
+			var n SlotNotification
+			select {
+			case <-ctx.Done():
+				return
+			case <-ping.C:
+				go listener.Ping()
+				continue
+			case event := <-listener.Notify:
+				// A nil event marks a reconnect; an unreadable payload is
+				// treated the same way rather than dropped silently
+				if event == nil || json.Unmarshal([]byte(event.Extra), &n) != nil {
+					n = SlotNotification{Resync: true}
+				}
+			}
+
+			select {
+			case notifications <- n:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return notifications, nil
+}
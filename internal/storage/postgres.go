@@ -3,236 +3,949 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"math/big"
+	"net/url"
+	"strings"
 	"time"
 
+	"insolventbydesign/internal/currency"
 	"insolventbydesign/internal/model"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 // PostgresStore provides TimescaleDB-optimized storage for censorship data.
+//
+// Writes and reads use separate connection pools, so a long-running
+// backfill cannot exhaust the connections serving API queries. Reads go
+// to a replica when one is configured.
 type PostgresStore struct {
-	db *sql.DB
+	db         *sql.DB // Primary: writes, migrations
+	readDB     *sql.DB // Replica, or a second pool on the primary
+	connStr    string  // Primary, for LISTEN connections
+	migrator   *sqlMigrator
+	batchSize  int
+	onConflict ConflictPolicy
 }
 
+// DefaultPostgresBatchSize is the number of rows copied per transaction.
+const DefaultPostgresBatchSize = 50_000
+
+// Connection pool sizes. Writers are capped well below readers: COPY
+// throughput saturates with a few connections, while API reads are many
+// and short. Config.MaxOpenConns and Config.MaxIdleConns are split between
+// the pools in the same 1:4 ratio.
+const (
+	DefaultPostgresWriteConns = 20
+	DefaultPostgresReadConns  = 80
+
+	DefaultConnMaxLifetime = 5 * time.Minute
+)
+
 // Config contains database connection parameters.
 type Config struct {
+	Driver   string // DriverPostgres (default), DriverSQLite or DriverClickHouse
+	Path     string // Database file path (SQLite only)
 	Host     string
 	Port     int
 	User     string
 	Password string
 	Database string
 	SSLMode  string
+
+	OnConflict ConflictPolicy // Duplicate slot handling; defaults to ConflictKeepFirst
+	BatchSize  int            // Rows per insert transaction; 0 selects the backend default
+
+	// Postgres connection pools; zero values select the defaults. The
+	// limits are totals across the write and read pools.
+	MaxOpenConns     int           // Defaults to DefaultPostgresWriteConns + DefaultPostgresReadConns
+	MaxIdleConns     int           // Defaults to a quarter of MaxOpenConns
+	ConnMaxLifetime  time.Duration // Defaults to DefaultConnMaxLifetime
+	StatementTimeout time.Duration // Server-side limit per statement; 0 means none
+
+	// Optional Postgres read replica for analytical queries, as a lib/pq
+	// connection string: a postgres:// URL or key=value pairs with its own
+	// host, port, credentials, database and sslmode. StatementTimeout
+	// applies to it too.
+	ReplicaDSN string
 }
 
 // NewPostgresStore creates a new database connection with connection pooling.
 func NewPostgresStore(config Config) (*PostgresStore, error) {
-	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+	onConflict, err := ParseConflictPolicy(string(config.OnConflict))
+	if err != nil {
+		return nil, err
+	}
+
+	writePool, readPool, err := splitPools(config)
+	if err != nil {
+		return nil, err
+	}
+
+	primary, replica, err := postgresConnStrings(config)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := openPostgresPool(config, "primary", primary, writePool)
+	if err != nil {
+		return nil, err
+	}
+	readName := "primary"
+	if config.ReplicaDSN != "" {
+		readName = "replica"
+	}
+	readDB, err := openPostgresPool(config, readName, replica, readPool)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("read pool: %w", err)
+	}
+
+	migrator, err := newSQLMigrator(db, "postgres", postgresPlaceholder)
+	if err != nil {
+		db.Close()
+		readDB.Close()
+		return nil, err
+	}
+
+	store := &PostgresStore{
+		db:         db,
+		readDB:     readDB,
+		connStr:    primary,
+		migrator:   migrator,
+		batchSize:  DefaultPostgresBatchSize,
+		onConflict: onConflict,
+	}
+	store.SetBatchSize(config.BatchSize)
+	return store, nil
+}
+
+// poolSize limits one connection pool.
+type poolSize struct {
+	maxOpen int
+	maxIdle int
+}
+
+// splitPools divides config's connection limits between the write and read
+// pools, 1:4, giving each pool at least one open connection.
+func splitPools(config Config) (write, read poolSize, err error) {
+	if config.MaxOpenConns < 0 || config.MaxIdleConns < 0 || config.ConnMaxLifetime < 0 || config.StatementTimeout < 0 {
+		return poolSize{}, poolSize{}, fmt.Errorf("invalid pool config: limits must not be negative")
+	}
+
+	maxOpen := config.MaxOpenConns
+	if maxOpen == 0 {
+		maxOpen = DefaultPostgresWriteConns + DefaultPostgresReadConns
+	}
+	write.maxOpen = max(1, maxOpen*DefaultPostgresWriteConns/(DefaultPostgresWriteConns+DefaultPostgresReadConns))
+	read.maxOpen = max(1, maxOpen-write.maxOpen)
+
+	if config.MaxIdleConns == 0 {
+		write.maxIdle = write.maxOpen / 4
+		read.maxIdle = read.maxOpen / 4
+		return write, read, nil
+	}
+	write.maxIdle = min(write.maxOpen, config.MaxIdleConns*write.maxOpen/(write.maxOpen+read.maxOpen))
+	read.maxIdle = min(read.maxOpen, config.MaxIdleConns-write.maxIdle)
+	return write, read, nil
+}
+
+// postgresConnStrings builds lib/pq connection strings for the primary and
+// for reads: the replica when config has one, else the primary again.
+func postgresConnStrings(config Config) (primary, replica string, err error) {
+	primary = fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		config.Host, config.Port, config.User, config.Password, config.Database, config.SSLMode)
+	replica = config.ReplicaDSN
+	if replica == "" {
+		replica = primary
+	} else if strings.HasPrefix(replica, "postgres://") || strings.HasPrefix(replica, "postgresql://") {
+		if replica, err = pq.ParseURL(replica); err != nil {
+			// The URL may hold a password, so it is not echoed
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return "", "", fmt.Errorf("invalid replica DSN: %w", err)
+		}
+	}
+
+	if config.StatementTimeout > 0 {
+		// Unrecognized keys are sent as session parameters
+		timeout := fmt.Sprintf(" statement_timeout=%d", config.StatementTimeout.Milliseconds())
+		primary += timeout
+		replica += timeout
+	}
+	return primary, replica, nil
+}
 
+// openPostgresPool opens and pings a pool of the given size on connStr
+// with config's lifetime. name identifies the server in errors without
+// exposing credentials.
+func openPostgresPool(config Config, name, connStr string, size poolSize) (*sql.DB, error) {
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Connection pool configuration for high throughput
-	db.SetMaxOpenConns(100)
-	db.SetMaxIdleConns(25)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	lifetime := config.ConnMaxLifetime
+	if lifetime == 0 {
+		lifetime = DefaultConnMaxLifetime
+	}
+	db.SetMaxOpenConns(size.maxOpen)
+	db.SetMaxIdleConns(size.maxIdle)
+	db.SetConnMaxLifetime(lifetime)
 
 	// Verify connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		db.Close()
+		return nil, fmt.Errorf("failed to ping %s database: %w", name, err)
 	}
 
-	return &PostgresStore{db: db}, nil
+	return db, nil
 }
 
-// InitSchema creates the database schema with TimescaleDB hypertable.
+// InitSchema brings the database schema to the latest migration.
 func (s *PostgresStore) InitSchema(ctx context.Context) error {
-	schema := `
-	-- Enable TimescaleDB extension
-	CREATE EXTENSION IF NOT EXISTS timescaledb;
-	
-	-- Slot bribes table (time-series data)
-	CREATE TABLE IF NOT EXISTS slot_bribes (
-		slot_number BIGINT NOT NULL,
-		slot_time TIMESTAMPTZ NOT NULL,
-		value_wei NUMERIC(78, 0) NOT NULL,  -- Supports up to 2^256
-		value_eth DOUBLE PRECISION NOT NULL,
-		builder_pubkey TEXT NOT NULL,
-		block_hash TEXT NOT NULL,
-		relay_url TEXT NOT NULL,
-		fetched_at TIMESTAMPTZ DEFAULT NOW(),
-		PRIMARY KEY (slot_time, slot_number)
-	);
-	
-	-- Convert to hypertable for time-series optimization
-	SELECT create_hypertable('slot_bribes', 'slot_time', if_not_exists => TRUE);
-	
-	-- Indexes for common queries
-	CREATE INDEX IF NOT EXISTS idx_slot_bribes_slot ON slot_bribes (slot_number);
-	CREATE INDEX IF NOT EXISTS idx_slot_bribes_builder ON slot_bribes (builder_pubkey);
-	CREATE INDEX IF NOT EXISTS idx_slot_bribes_value ON slot_bribes (value_eth DESC);
-	
-	-- Builder statistics materialized view (auto-refreshing)
-	CREATE MATERIALIZED VIEW IF NOT EXISTS builder_stats AS
-	SELECT 
-		builder_pubkey,
-		COUNT(*) as block_count,
-		SUM(value_eth) as total_value_eth,
-		AVG(value_eth) as avg_value_eth,
-		MAX(value_eth) as max_value_eth,
-		MIN(value_eth) as min_value_eth,
-		STDDEV(value_eth) as stddev_value_eth
-	FROM slot_bribes
-	GROUP BY builder_pubkey
-	ORDER BY block_count DESC;
-	
-	CREATE UNIQUE INDEX IF NOT EXISTS idx_builder_stats_pubkey ON builder_stats (builder_pubkey);
-	
-	-- Censorship cost analysis table
-	CREATE TABLE IF NOT EXISTS censorship_analysis (
-		id SERIAL PRIMARY KEY,
-		start_slot BIGINT NOT NULL,
-		end_slot BIGINT NOT NULL,
-		duration_slots INT NOT NULL,
-		total_cost_wei NUMERIC(78, 0) NOT NULL,
-		total_cost_eth DOUBLE PRECISION NOT NULL,
-		total_cost_usd DOUBLE PRECISION,
-		builder_concentration DOUBLE PRECISION NOT NULL,
-		top_k_builders INT NOT NULL,
-		effective_cost_eth DOUBLE PRECISION NOT NULL,
-		breakeven_tvl_usd DOUBLE PRECISION,
-		success_probability DOUBLE PRECISION,
-		computed_at TIMESTAMPTZ DEFAULT NOW(),
-		UNIQUE(start_slot, end_slot, top_k_builders)
-	);
-	
-	CREATE INDEX IF NOT EXISTS idx_censorship_analysis_slots ON censorship_analysis (start_slot, end_slot);
-	`
-
-	_, err := s.db.ExecContext(ctx, schema)
-	return err
+	_, latest, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	return s.MigrateTo(ctx, latest)
+}
+
+// SchemaVersion returns the applied and latest available schema versions.
+func (s *PostgresStore) SchemaVersion(ctx context.Context) (current, latest int, err error) {
+	current, err = s.migrator.current(ctx)
+	return current, s.migrator.latest(), err
+}
+
+// migrationLockID is the advisory lock key serializing concurrent migrators.
+const migrationLockID = 7_355_608
+
+// MigrateTo applies up or down migrations until the schema is at version.
+//
+// A session-level advisory lock ensures that replicas starting at the
+// same time do not run migrations concurrently.
+func (s *PostgresStore) MigrateTo(ctx context.Context, version int) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	return s.migrator.migrateTo(ctx, version)
+}
+
+// SetBatchSize overrides the number of rows copied per transaction.
+func (s *PostgresStore) SetBatchSize(n int) {
+	if n > 0 {
+		s.batchSize = n
+	}
+}
+
+// SetConflictPolicy changes how duplicate slots are resolved on insert.
+func (s *PostgresStore) SetConflictPolicy(p ConflictPolicy) error {
+	policy, err := ParseConflictPolicy(string(p))
+	if err != nil {
+		return err
+	}
+	s.onConflict = policy
+	return nil
 }
 
 // BatchInsertBribes inserts multiple slot bribes efficiently using COPY.
-func (s *PostgresStore) BatchInsertBribes(ctx context.Context, bribes []model.SlotBribe, relayURL string) error {
+//
+// Rows are streamed with the COPY protocol into a temporary staging table
+// and then merged with INSERT ... ON CONFLICT. Under ConflictKeepFirst the
+// stored row and the first duplicate in the batch win; under
+// ConflictKeepMax the highest value wins. Every row is also recorded as
+// relayURL's report in relay_bribes, and builder slot spans are extended
+// in builders. Each chunk of batchSize rows commits in its own
+// transaction.
+func (s *PostgresStore) BatchInsertBribes(ctx context.Context, bribes []model.SlotBribe, relayURL string) (InsertReport, error) {
+	return insertChunks(ctx, len(bribes), s.batchSize, func(start, end int) (int, error) {
+		return s.copyChunk(ctx, bribes[start:end], relayURL)
+	})
+}
+
+// postgresMergeStaged moves staged rows into slot_bribes per ConflictPolicy.
+var postgresMergeStaged = map[ConflictPolicy]string{
+	ConflictKeepFirst: `
+		INSERT INTO slot_bribes (slot_number, slot_time, value_wei, value_eth, builder_pubkey, block_hash, relay_url)
+		SELECT DISTINCT ON (slot_time, slot_number)
+			slot_number, slot_time, value_wei, value_eth, builder_pubkey, block_hash, relay_url
+		FROM slot_bribes_staging
+		ORDER BY slot_time, slot_number, seq
+		ON CONFLICT (slot_time, slot_number) DO NOTHING
+	`,
+	ConflictKeepMax: `
+		INSERT INTO slot_bribes (slot_number, slot_time, value_wei, value_eth, builder_pubkey, block_hash, relay_url)
+		SELECT DISTINCT ON (slot_time, slot_number)
+			slot_number, slot_time, value_wei, value_eth, builder_pubkey, block_hash, relay_url
+		FROM slot_bribes_staging
+		ORDER BY slot_time, slot_number, value_wei DESC, seq
+		ON CONFLICT (slot_time, slot_number) DO UPDATE SET
+			value_wei = EXCLUDED.value_wei,
+			value_eth = EXCLUDED.value_eth,
+			builder_pubkey = EXCLUDED.builder_pubkey,
+			block_hash = EXCLUDED.block_hash,
+			relay_url = EXCLUDED.relay_url,
+			fetched_at = NOW()
+		WHERE EXCLUDED.value_wei > slot_bribes.value_wei
+	`,
+}
+
+// postgresMergeReports records staged rows as relay reports. A relay
+// re-reporting a slot replaces its earlier report; within a batch the
+// last duplicate wins.
+const postgresMergeReports = `
+	INSERT INTO relay_bribes (slot_number, slot_time, relay_url, value_wei, builder_pubkey, block_hash)
+	SELECT DISTINCT ON (slot_time, slot_number)
+		slot_number, slot_time, relay_url, value_wei, builder_pubkey, block_hash
+	FROM slot_bribes_staging
+	ORDER BY slot_time, slot_number, seq DESC
+	ON CONFLICT (slot_time, slot_number, relay_url) DO UPDATE SET
+		value_wei = EXCLUDED.value_wei,
+		builder_pubkey = EXCLUDED.builder_pubkey,
+		block_hash = EXCLUDED.block_hash,
+		fetched_at = NOW()
+`
+
+// postgresMergeBuilders extends the slot spans of the staged builders.
+const postgresMergeBuilders = `
+	INSERT INTO builders (pubkey, first_seen, last_seen)
+	SELECT builder_pubkey, MIN(slot_number), MAX(slot_number)
+	FROM slot_bribes_staging
+	GROUP BY builder_pubkey
+	ON CONFLICT (pubkey) DO UPDATE SET
+		first_seen = LEAST(builders.first_seen, EXCLUDED.first_seen),
+		last_seen = GREATEST(builders.last_seen, EXCLUDED.last_seen)
+`
+
+// copyChunk copies one chunk of bribes through the staging table and
+// returns the number of slot_bribes rows inserted or replaced.
+func (s *PostgresStore) copyChunk(ctx context.Context, bribes []model.SlotBribe, relayURL string) (int, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO slot_bribes (slot_number, slot_time, value_wei, value_eth, builder_pubkey, block_hash, relay_url)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		ON CONFLICT (slot_time, slot_number) DO NOTHING
-	`)
+	// seq preserves input order so duplicates resolve deterministically
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TEMP TABLE slot_bribes_staging (
+			seq BIGINT NOT NULL,
+			slot_number BIGINT NOT NULL,
+			slot_time TIMESTAMPTZ NOT NULL,
+			value_wei NUMERIC(78, 0) NOT NULL,
+			value_eth DOUBLE PRECISION NOT NULL,
+			builder_pubkey TEXT NOT NULL,
+			block_hash TEXT NOT NULL,
+			relay_url TEXT NOT NULL
+		) ON COMMIT DROP
+	`); err != nil {
+		return 0, fmt.Errorf("failed to create staging table: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("slot_bribes_staging",
+		"seq", "slot_number", "slot_time", "value_wei", "value_eth", "builder_pubkey", "block_hash", "relay_url"))
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return 0, fmt.Errorf("failed to prepare COPY: %w", err)
 	}
-	defer stmt.Close()
 
-	for _, bribe := range bribes {
+	for i, bribe := range bribes {
 		if bribe.ValueWei == nil {
 			continue
 		}
 
-		// Convert slot to approximate timestamp (12s per slot)
-		slotTime := time.Unix(1606824023, 0).Add(time.Duration(bribe.Slot*12) * time.Second)
+		// Convert slot to its mainnet start time
+		slotTime := model.Mainnet.SlotTime(bribe.Slot)
 
-		// Convert wei to ETH
-		weiPerEth := new(big.Float).SetInt(big.NewInt(1e18))
-		valueEth, _ := new(big.Float).Quo(new(big.Float).SetInt(bribe.ValueWei), weiPerEth).Float64()
+		if _, err := stmt.ExecContext(ctx, i, bribe.Slot, slotTime, bribe.ValueWei.String(),
+			currency.WeiToETHFloat64(bribe.ValueWei), bribe.BuilderPubkey, "" /* block hash */, relayURL); err != nil {
+			stmt.Close()
+			return 0, fmt.Errorf("failed to copy bribe: %w", err)
+		}
+	}
 
-		_, err := stmt.ExecContext(ctx, bribe.Slot, slotTime, bribe.ValueWei.String(), valueEth,
-			bribe.BuilderPubkey, "" /* block hash */, relayURL)
-		if err != nil {
-			return fmt.Errorf("failed to insert bribe: %w", err)
+	// Flush buffered rows
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return 0, fmt.Errorf("failed to flush COPY: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return 0, fmt.Errorf("failed to finish COPY: %w", err)
+	}
+
+	var merged SlotNotification
+	var firstSlot, lastSlot sql.NullInt64
+	if err := tx.QueryRowContext(ctx, `
+		WITH merged AS (`+postgresMergeStaged[s.onConflict]+` RETURNING slot_number)
+		SELECT COUNT(*), MIN(slot_number), MAX(slot_number) FROM merged
+	`).Scan(&merged.Count, &firstSlot, &lastSlot); err != nil {
+		return 0, fmt.Errorf("failed to merge staged bribes: %w", err)
+	}
+	merged.StartSlot, merged.EndSlot = uint64(firstSlot.Int64), uint64(lastSlot.Int64)
+	if _, err := tx.ExecContext(ctx, postgresMergeReports); err != nil {
+		return 0, fmt.Errorf("failed to merge relay reports: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, postgresMergeBuilders); err != nil {
+		return 0, fmt.Errorf("failed to update builders: %w", err)
+	}
+	if merged.Count > 0 {
+		if err := notifySlots(ctx, tx, merged); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return merged.Count, nil
+}
+
+// BatchInsertBids inserts received bids using COPY through a staging
+// table, one transaction per chunk of batchSize rows.
+func (s *PostgresStore) BatchInsertBids(ctx context.Context, bids []model.ReceivedBid) error {
+	for start := 0; start < len(bids); start += s.batchSize {
+		end := min(start+s.batchSize, len(bids))
+		if err := s.copyBidChunk(ctx, bids[start:end]); err != nil {
+			return fmt.Errorf("failed to insert bids %d-%d of batch: %w", start, end-1, err)
+		}
+	}
+	return nil
+}
+
+// copyBidChunk copies one chunk of bids through the staging table.
+func (s *PostgresStore) copyBidChunk(ctx context.Context, bids []model.ReceivedBid) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TEMP TABLE received_bids_staging (
+			slot_number BIGINT NOT NULL,
+			slot_time TIMESTAMPTZ NOT NULL,
+			relay_url TEXT NOT NULL,
+			block_hash TEXT NOT NULL,
+			builder_pubkey TEXT NOT NULL,
+			value_wei NUMERIC(78, 0) NOT NULL,
+			received_at TIMESTAMPTZ
+		) ON COMMIT DROP
+	`); err != nil {
+		return fmt.Errorf("failed to create staging table: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("received_bids_staging",
+		"slot_number", "slot_time", "relay_url", "block_hash", "builder_pubkey", "value_wei", "received_at"))
+	if err != nil {
+		return fmt.Errorf("failed to prepare COPY: %w", err)
+	}
+
+	for _, bid := range bids {
+		if bid.ValueWei == nil {
+			continue
+		}
+
+		var receivedAt interface{}
+		if !bid.ReceivedAt.IsZero() {
+			receivedAt = bid.ReceivedAt
+		}
+		if _, err := stmt.ExecContext(ctx, bid.Slot, model.Mainnet.SlotTime(bid.Slot), bid.RelayURL,
+			bid.BlockHash, bid.BuilderPubkey, bid.ValueWei.String(), receivedAt); err != nil {
+			stmt.Close()
+			return fmt.Errorf("failed to copy bid: %w", err)
 		}
 	}
 
+	// Flush buffered rows
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return fmt.Errorf("failed to flush COPY: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("failed to finish COPY: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO received_bids (slot_number, slot_time, relay_url, block_hash, builder_pubkey, value_wei, received_at)
+		SELECT slot_number, slot_time, relay_url, block_hash, builder_pubkey, value_wei, received_at
+		FROM received_bids_staging
+		ON CONFLICT (slot_time, slot_number, relay_url, block_hash) DO NOTHING
+	`); err != nil {
+		return fmt.Errorf("failed to merge staged bids: %w", err)
+	}
+
 	return tx.Commit()
 }
 
+// GetSlotBids retrieves received bids for a specific slot range.
+func (s *PostgresStore) GetSlotBids(ctx context.Context, startSlot, endSlot uint64) ([]model.ReceivedBid, error) {
+	rows, err := s.readDB.QueryContext(ctx, `
+		SELECT slot_number, relay_url, builder_pubkey, block_hash, value_wei::TEXT,
+			(EXTRACT(EPOCH FROM received_at) * 1000)::BIGINT
+		FROM received_bids
+		WHERE slot_number BETWEEN $1 AND $2
+		ORDER BY slot_number ASC, received_at ASC, relay_url ASC, block_hash ASC
+	`, startSlot, endSlot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bids: %w", err)
+	}
+	return scanBidRows(rows)
+}
+
+// SaveBridgeTVL upserts TVL snapshots in a single statement.
+func (s *PostgresStore) SaveBridgeTVL(ctx context.Context, snapshots []model.BridgeTVL) error {
+	for _, snapshot := range snapshots {
+		if err := validateTVL(snapshot); err != nil {
+			return err
+		}
+	}
+	snapshots = dedupeTVL(snapshots)
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	bridges := make([]string, len(snapshots))
+	chains := make([]string, len(snapshots))
+	times := make([]int64, len(snapshots))
+	tvls := make([]float64, len(snapshots))
+	sources := make([]string, len(snapshots))
+	for i, snapshot := range snapshots {
+		bridges[i], chains[i], sources[i] = snapshot.Bridge, snapshot.Chain, snapshot.Source
+		times[i], tvls[i] = snapshot.Timestamp.Unix(), snapshot.TVLUSD
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO bridge_tvl (bridge, chain, snapshot_time, tvl_usd, source)
+		SELECT bridge, chain, TO_TIMESTAMP(unix), tvl, source
+		FROM UNNEST($1::TEXT[], $2::TEXT[], $3::BIGINT[], $4::DOUBLE PRECISION[], $5::TEXT[])
+			AS t(bridge, chain, unix, tvl, source)
+		ON CONFLICT (bridge, chain, snapshot_time, source) DO UPDATE SET
+			tvl_usd = EXCLUDED.tvl_usd,
+			fetched_at = NOW()
+	`, pq.Array(bridges), pq.Array(chains), pq.Array(times), pq.Array(tvls), pq.Array(sources))
+	if err != nil {
+		return fmt.Errorf("failed to save bridge TVL: %w", err)
+	}
+	return nil
+}
+
+// GetBridgeTVL retrieves a bridge's TVL snapshots taken in [from, to).
+func (s *PostgresStore) GetBridgeTVL(ctx context.Context, bridge, chain string, from, to time.Time) ([]model.BridgeTVL, error) {
+	rows, err := s.readDB.QueryContext(ctx, `
+		SELECT bridge, chain, EXTRACT(EPOCH FROM snapshot_time)::BIGINT, tvl_usd, source
+		FROM bridge_tvl
+		WHERE bridge = $1 AND ($2 = '' OR chain = $2) AND snapshot_time >= $3 AND snapshot_time < $4
+		ORDER BY snapshot_time ASC, chain ASC, source ASC
+	`, bridge, chain, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bridge TVL: %w", err)
+	}
+	return scanTVLRows(rows)
+}
+
+// GetBridgeTVLAt retrieves a bridge's latest TVL snapshot taken at or before at.
+func (s *PostgresStore) GetBridgeTVLAt(ctx context.Context, bridge, chain string, at time.Time) (model.BridgeTVL, error) {
+	rows, err := s.readDB.QueryContext(ctx, `
+		SELECT bridge, chain, EXTRACT(EPOCH FROM snapshot_time)::BIGINT, tvl_usd, source
+		FROM bridge_tvl
+		WHERE bridge = $1 AND ($2 = '' OR chain = $2) AND snapshot_time <= $3
+		ORDER BY snapshot_time DESC, chain ASC, source ASC
+		LIMIT 1
+	`, bridge, chain, at)
+	if err != nil {
+		return model.BridgeTVL{}, fmt.Errorf("failed to query bridge TVL: %w", err)
+	}
+	snapshots, err := scanTVLRows(rows)
+	if err != nil {
+		return model.BridgeTVL{}, fmt.Errorf("failed to query bridge TVL: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return model.BridgeTVL{}, ErrNotFound
+	}
+	return snapshots[0], nil
+}
+
+// SavePrices upserts ETH/USD quotes in a single statement.
+func (s *PostgresStore) SavePrices(ctx context.Context, quotes []model.PriceQuote) error {
+	for _, quote := range quotes {
+		if err := validatePrice(quote); err != nil {
+			return err
+		}
+	}
+	quotes = dedupePrices(quotes)
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	times := make([]int64, len(quotes))
+	sources := make([]string, len(quotes))
+	prices := make([]float64, len(quotes))
+	for i, quote := range quotes {
+		times[i], sources[i], prices[i] = quote.Timestamp.Unix(), quote.Source, quote.USD
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO prices (quote_time, source, price_usd)
+		SELECT TO_TIMESTAMP(unix), source, price
+		FROM UNNEST($1::BIGINT[], $2::TEXT[], $3::DOUBLE PRECISION[]) AS q(unix, source, price)
+		ON CONFLICT (quote_time, source) DO UPDATE SET
+			price_usd = EXCLUDED.price_usd,
+			fetched_at = NOW()
+	`, pq.Array(times), pq.Array(sources), pq.Array(prices))
+	if err != nil {
+		return fmt.Errorf("failed to save prices: %w", err)
+	}
+	return nil
+}
+
+// PriceAt retrieves the latest ETH/USD quote taken at or before at.
+func (s *PostgresStore) PriceAt(ctx context.Context, at time.Time) (model.PriceQuote, error) {
+	return scanPriceRow(s.readDB.QueryRowContext(ctx, `
+		SELECT EXTRACT(EPOCH FROM quote_time)::BIGINT, price_usd, source
+		FROM prices
+		WHERE quote_time <= $1
+		ORDER BY quote_time DESC, source ASC
+		LIMIT 1
+	`, at))
+}
+
 // GetSlotRange retrieves bribes for a specific slot range.
 func (s *PostgresStore) GetSlotRange(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT slot_number, value_wei, builder_pubkey
+	return collectSlots(func(fn func(model.SlotBribe) error) error {
+		return s.ForEachSlot(ctx, startSlot, endSlot, fn)
+	})
+}
+
+// GetTimeRange retrieves bribes for slots starting in [from, to).
+//
+// Filtering on slot_time lets TimescaleDB skip hypertable chunks outside
+// the window.
+func (s *PostgresStore) GetTimeRange(ctx context.Context, from, to time.Time) ([]model.SlotBribe, error) {
+	if _, _, err := model.Mainnet.SlotRangeForTimes(from, to); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.readDB.QueryContext(ctx, `
+		SELECT slot_number, value_wei, builder_pubkey, relay_url
+		FROM slot_bribes
+		WHERE slot_time >= $1 AND slot_time < $2
+		ORDER BY slot_number ASC
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return collectSlots(func(fn func(model.SlotBribe) error) error {
+		return scanSlotRows(rows, fn)
+	})
+}
+
+// ForEachSlot streams bribes for a slot range to fn in slot order.
+func (s *PostgresStore) ForEachSlot(ctx context.Context, startSlot, endSlot uint64, fn func(model.SlotBribe) error) error {
+	rows, err := s.readDB.QueryContext(ctx, `
+		SELECT slot_number, value_wei, builder_pubkey, relay_url
 		FROM slot_bribes
 		WHERE slot_number BETWEEN $1 AND $2
 		ORDER BY slot_number ASC
 	`, startSlot, endSlot)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
+	return scanSlotRows(rows, fn)
+}
 
-	var bribes []model.SlotBribe
-	for rows.Next() {
-		var slot uint64
-		var valueWeiStr string
-		var builderPubkey string
+// postgresValueShare is a builder's share of the value summed over every
+// grouped builder. The window runs before LIMIT, so pages share one total.
+const postgresValueShare = `COALESCE(SUM(total_value_wei) / NULLIF(SUM(SUM(total_value_wei)) OVER (), 0), 0)::DOUBLE PRECISION`
 
-		if err := rows.Scan(&slot, &valueWeiStr, &builderPubkey); err != nil {
-			return nil, err
-		}
+// GetSlotRangeStats sums the range as NUMERIC, so only one row leaves
+// the database.
+func (s *PostgresStore) GetSlotRangeStats(ctx context.Context, startSlot, endSlot uint64) (SlotRangeStats, error) {
+	if startSlot > endSlot {
+		return SlotRangeStats{}, fmt.Errorf("start slot %d is after end slot %d", startSlot, endSlot)
+	}
 
-		valueWei := new(big.Int)
-		valueWei.SetString(valueWeiStr, 10)
+	var count uint64
+	var total string
+	var minWei, maxWei sql.NullString
+	err := s.readDB.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(value_wei), 0)::TEXT, MIN(value_wei)::TEXT, MAX(value_wei)::TEXT
+		FROM slot_bribes
+		WHERE slot_number BETWEEN $1 AND $2
+	`, startSlot, endSlot).Scan(&count, &total, &minWei, &maxWei)
+	if err != nil {
+		return SlotRangeStats{}, fmt.Errorf("failed to aggregate slot range: %w", err)
+	}
+	return newSlotRangeStats(count, total, minWei.String, maxWei.String)
+}
+
+// GetBuilderStats returns aggregated statistics for builders.
+//
+// Counts and exact wei totals come from the builder_stats_daily continuous
+// aggregate, which TimescaleDB keeps current in the background, so no
+// refresh is needed. A slot window does not align with the daily buckets,
+// so windowed queries aggregate slot_bribes directly; chunk exclusion keeps
+// that proportional to the window.
+func (s *PostgresStore) GetBuilderStats(ctx context.Context, query BuilderStatsQuery) ([]model.BuilderStats, error) {
+	if query.windowed() {
+		return s.getBuilderStatsWindow(ctx, query)
+	}
 
-		bribes = append(bribes, model.SlotBribe{
-			Slot:          slot,
-			ValueWei:      valueWei,
-			BuilderPubkey: builderPubkey,
-		})
+	order, err := query.orderExpr(map[BuilderSort]string{
+		SortByBlockCount: "SUM(block_count)",
+		SortByTotalValue: "SUM(total_value_wei)",
+		SortByLastSeen:   "MAX(last_slot)",
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return bribes, rows.Err()
+	return s.queryBuilderStats(ctx, `
+		SELECT builder_pubkey, SUM(block_count)::BIGINT AS block_count, COALESCE(MAX(b.entity_name), ''),
+			SUM(total_value_wei)::TEXT, `+postgresValueShare+`, MIN(first_slot), MAX(last_slot)
+		FROM builder_stats_daily
+		LEFT JOIN builders b ON b.pubkey = builder_stats_daily.builder_pubkey
+		GROUP BY builder_pubkey
+		ORDER BY `+order+query.limitClause("ALL"))
 }
 
-// GetBuilderStats returns aggregated statistics for all builders.
-func (s *PostgresStore) GetBuilderStats(ctx context.Context) ([]model.BuilderStats, error) {
-	// Refresh materialized view
-	if _, err := s.db.ExecContext(ctx, "REFRESH MATERIALIZED VIEW builder_stats"); err != nil {
+// getBuilderStatsWindow implements GetBuilderStats over a slot window.
+func (s *PostgresStore) getBuilderStatsWindow(ctx context.Context, query BuilderStatsQuery) ([]model.BuilderStats, error) {
+	order, err := query.orderExpr(map[BuilderSort]string{
+		SortByBlockCount: "COUNT(*)",
+		SortByTotalValue: "SUM(value_wei)",
+		SortByLastSeen:   "MAX(slot_number)",
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT builder_pubkey, block_count
-		FROM builder_stats
-		ORDER BY block_count DESC
-	`)
+	filter, args := query.slotFilter(postgresPlaceholder)
+	return s.queryBuilderStats(ctx, `
+		SELECT builder_pubkey, COUNT(*) AS block_count, COALESCE(MAX(b.entity_name), ''),
+			SUM(value_wei)::TEXT, COALESCE(SUM(value_wei) / NULLIF(SUM(SUM(value_wei)) OVER (), 0), 0)::DOUBLE PRECISION,
+			MIN(slot_number), MAX(slot_number)
+		FROM slot_bribes
+		LEFT JOIN builders b ON b.pubkey = slot_bribes.builder_pubkey
+		WHERE `+filter+`
+		GROUP BY builder_pubkey
+		ORDER BY `+order+query.limitClause("ALL"), args...)
+}
+
+// GetBuilderConcentration computes top-k concentration over raw slots.
+func (s *PostgresStore) GetBuilderConcentration(ctx context.Context, startSlot, endSlot uint64, topK int) (BuilderConcentration, error) {
+	return builderConcentrationSQL(ctx, s, s.readDB, postgresPlaceholder, startSlot, endSlot, topK)
+}
+
+// GetBuilderStatsBetween returns per-builder block counts and values for
+// slots whose start time falls in [from, to). Whole hours inside the
+// window come from the hourly rollup and the partial hours at either edge
+// from raw slots. Value shares are relative to the window. The rollup
+// keeps no slots, so the first and last seen slots are left 0.
+func (s *PostgresStore) GetBuilderStatsBetween(ctx context.Context, from, to time.Time) ([]model.BuilderStats, error) {
+	lo, hi := wholeHours(from, to)
+	return s.queryBuilderStats(ctx, `
+		WITH parts AS (
+			SELECT builder_pubkey, block_count, total_value_wei
+			FROM builder_stats_hourly
+			WHERE bucket >= $3 AND bucket < $4
+			UNION ALL
+			SELECT builder_pubkey, 1::BIGINT, value_wei
+			FROM slot_bribes
+			WHERE (slot_time >= $1 AND slot_time < $3) OR (slot_time >= $4 AND slot_time < $2)
+		)
+		SELECT builder_pubkey, SUM(block_count)::BIGINT AS block_count, COALESCE(MAX(b.entity_name), ''),
+			SUM(total_value_wei)::TEXT, `+postgresValueShare+`, 0::BIGINT, 0::BIGINT
+		FROM parts
+		LEFT JOIN builders b ON b.pubkey = parts.builder_pubkey
+		GROUP BY builder_pubkey
+		ORDER BY block_count DESC, builder_pubkey ASC
+	`, from, to, lo, hi)
+}
+
+// wholeHours returns the span [lo, hi) of whole UTC hours inside
+// [from, to), so that [from, lo) and [hi, to) are the partial edge hours.
+// A window with no whole hour gets lo == hi == to, leaving it all in the
+// first edge.
+func wholeHours(from, to time.Time) (lo, hi time.Time) {
+	lo = from.Truncate(time.Hour)
+	if lo.Before(from) {
+		lo = lo.Add(time.Hour)
+	}
+	hi = to.Truncate(time.Hour)
+	if !lo.Before(hi) {
+		return to, to
+	}
+	return lo, hi
+}
+
+func (s *PostgresStore) queryBuilderStats(ctx context.Context, query string, args ...interface{}) ([]model.BuilderStats, error) {
+	rows, err := s.readDB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return scanBuilderStats(rows)
+}
 
-	var stats []model.BuilderStats
-	for rows.Next() {
-		var pubkey string
-		var count uint64
+// GetBuilder returns the builders table row for pubkey.
+func (s *PostgresStore) GetBuilder(ctx context.Context, pubkey string) (Builder, error) {
+	return queryBuilder(s.readDB.QueryRowContext(ctx, `
+		SELECT `+builderColumns+`
+		FROM builders
+		WHERE pubkey = $1
+	`, pubkey))
+}
 
-		if err := rows.Scan(&pubkey, &count); err != nil {
-			return nil, err
+// SaveBuilderLabels sets entities and labels in a single statement.
+func (s *PostgresStore) SaveBuilderLabels(ctx context.Context, builders []Builder) error {
+	if len(builders) == 0 {
+		return nil
+	}
+
+	pubkeys := make([]string, len(builders))
+	entities := make([]string, len(builders))
+	labels := make([]string, len(builders))
+	for i, b := range builders {
+		encoded, err := encodeLabels(b.Labels)
+		if err != nil {
+			return err
 		}
+		pubkeys[i], entities[i], labels[i] = b.Pubkey, b.Entity, encoded
+	}
 
-		stats = append(stats, model.BuilderStats{
-			BuilderPubkey: pubkey,
-			BlockCount:    count,
-		})
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO builders (pubkey, entity_name, labels)
+		SELECT pubkey, NULLIF(entity, ''), labels::JSONB
+		FROM UNNEST($1::TEXT[], $2::TEXT[], $3::TEXT[]) AS l(pubkey, entity, labels)
+		ON CONFLICT (pubkey) DO UPDATE SET
+			entity_name = EXCLUDED.entity_name,
+			labels = EXCLUDED.labels
+	`, pq.Array(pubkeys), pq.Array(entities), pq.Array(labels))
+	if err != nil {
+		return fmt.Errorf("failed to save builder labels: %w", err)
 	}
+	return nil
+}
+
+// FindGaps returns the ranges of slots in [startSlot, endSlot] with no stored bribe.
+func (s *PostgresStore) FindGaps(ctx context.Context, startSlot, endSlot uint64) ([]SlotGap, error) {
+	return findGapsSQL(ctx, s.readDB, postgresPlaceholder, startSlot, endSlot)
+}
 
-	return stats, rows.Err()
+// FindDiscrepancies returns the slots in [startSlot, endSlot] on which relays disagree.
+func (s *PostgresStore) FindDiscrepancies(ctx context.Context, startSlot, endSlot uint64) ([]SlotDiscrepancy, error) {
+	return findDiscrepanciesSQL(ctx, s.readDB, postgresPlaceholder, startSlot, endSlot)
 }
 
-// Close closes the database connection.
+// GetRelayReports returns every relay's report for the slots in [startSlot, endSlot].
+func (s *PostgresStore) GetRelayReports(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	return getRelayReportsSQL(ctx, s.readDB, postgresPlaceholder, startSlot, endSlot)
+}
+
+// SaveAnalysis persists a censorship cost computation.
+//
+// Re-running the same (start, end, top-k) analysis replaces the stored row.
+func (s *PostgresStore) SaveAnalysis(ctx context.Context, record AnalysisRecord) error {
+	if record.TotalCostWei == nil {
+		return fmt.Errorf("TotalCostWei cannot be nil")
+	}
+	computedAt := record.ComputedAt
+	if computedAt.IsZero() {
+		computedAt = time.Now()
+	}
+
+	topBuilders, err := encodeTopBuilders(record.TopBuilders)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO censorship_analysis (
+			start_slot, end_slot, duration_slots, total_cost_wei, total_cost_eth, total_cost_usd,
+			builder_concentration, top_k_builders, effective_cost_eth, breakeven_tvl_usd,
+			success_probability, computed_at, top_builders
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (start_slot, end_slot, top_k_builders) DO UPDATE SET
+			duration_slots = EXCLUDED.duration_slots,
+			total_cost_wei = EXCLUDED.total_cost_wei,
+			total_cost_eth = EXCLUDED.total_cost_eth,
+			total_cost_usd = EXCLUDED.total_cost_usd,
+			builder_concentration = EXCLUDED.builder_concentration,
+			effective_cost_eth = EXCLUDED.effective_cost_eth,
+			breakeven_tvl_usd = EXCLUDED.breakeven_tvl_usd,
+			success_probability = EXCLUDED.success_probability,
+			computed_at = EXCLUDED.computed_at,
+			top_builders = EXCLUDED.top_builders
+	`, record.StartSlot, record.EndSlot, record.DurationSlots, record.TotalCostWei.String(),
+		record.TotalCostETH, nullableFloat(record.TotalCostUSD), record.BuilderConcentration,
+		record.TopKBuilders, record.EffectiveCostETH, nullableFloat(record.BreakevenTVLUSD),
+		record.SuccessProbability, computedAt, topBuilders)
+	if err != nil {
+		return fmt.Errorf("failed to save analysis: %w", err)
+	}
+	return nil
+}
+
+// GetAnalysis returns the stored analysis for (startSlot, endSlot, topK).
+func (s *PostgresStore) GetAnalysis(ctx context.Context, startSlot, endSlot uint64, topK int) (AnalysisRecord, error) {
+	var computedAt time.Time
+	record, err := scanAnalysis(s.readDB.QueryRowContext(ctx, `
+		SELECT `+analysisColumns+`
+		FROM censorship_analysis
+		WHERE start_slot = $1 AND end_slot = $2 AND top_k_builders = $3
+	`, startSlot, endSlot, topK), &computedAt, func() time.Time { return computedAt })
+	if errors.Is(err, sql.ErrNoRows) {
+		return AnalysisRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return AnalysisRecord{}, fmt.Errorf("failed to get analysis: %w", err)
+	}
+	return record, nil
+}
+
+// GetAnalyses returns stored analyses matching query, most recent first.
+func (s *PostgresStore) GetAnalyses(ctx context.Context, query AnalysisQuery) ([]AnalysisRecord, error) {
+	where, args, limit := analysisFilter(query, postgresPlaceholder)
+	rows, err := s.readDB.QueryContext(ctx, `
+		SELECT `+analysisColumns+`
+		FROM censorship_analysis
+		WHERE `+where+`
+		ORDER BY computed_at DESC, start_slot ASC`+limit, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query analyses: %w", err)
+	}
+	defer rows.Close()
+
+	var records []AnalysisRecord
+	for rows.Next() {
+		var computedAt time.Time
+		record, err := scanAnalysis(rows, &computedAt, func() time.Time { return computedAt })
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// Close closes both connection pools.
 func (s *PostgresStore) Close() error {
-	return s.db.Close()
+	readErr := s.readDB.Close()
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+	return readErr
 }
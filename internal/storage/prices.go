@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// validatePrice rejects quotes that cannot be keyed or used for conversion.
+func validatePrice(quote model.PriceQuote) error {
+	switch {
+	case quote.Source == "":
+		return fmt.Errorf("price quote has no source: %+v", quote)
+	case quote.Timestamp.IsZero():
+		return fmt.Errorf("price quote from %s has no timestamp", quote.Source)
+	case math.IsNaN(quote.USD) || math.IsInf(quote.USD, 0) || quote.USD <= 0:
+		return fmt.Errorf("invalid ETH price %v from %s", quote.USD, quote.Source)
+	}
+	return nil
+}
+
+// dedupePrices keeps the last of several quotes sharing a time and source,
+// so a single upsert statement never touches a row twice.
+func dedupePrices(quotes []model.PriceQuote) []model.PriceQuote {
+	type key struct {
+		unix   int64
+		source string
+	}
+	index := make(map[key]int, len(quotes))
+	deduped := make([]model.PriceQuote, 0, len(quotes))
+	for _, quote := range quotes {
+		k := key{quote.Timestamp.Unix(), quote.Source}
+		if i, ok := index[k]; ok {
+			deduped[i] = quote
+			continue
+		}
+		index[k] = len(deduped)
+		deduped = append(deduped, quote)
+	}
+	return deduped
+}
+
+// scanPriceRow reads a (quote_time_unix, price_usd, source) row, returning
+// ErrNotFound for no row.
+func scanPriceRow(row *sql.Row) (model.PriceQuote, error) {
+	var quote model.PriceQuote
+	var unix int64
+	if err := row.Scan(&unix, &quote.USD, &quote.Source); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.PriceQuote{}, ErrNotFound
+		}
+		return model.PriceQuote{}, fmt.Errorf("failed to query price: %w", err)
+	}
+	quote.Timestamp = time.Unix(unix, 0).UTC()
+	return quote, nil
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// newSlotRangeStats parses aggregates returned as base-10 strings; empty
+// minimum and maximum mean the range held no rows.
+func newSlotRangeStats(count uint64, total, minWei, maxWei string) (SlotRangeStats, error) {
+	stats := SlotRangeStats{Count: count}
+	var ok bool
+	if stats.TotalWei, ok = new(big.Int).SetString(total, 10); !ok {
+		return SlotRangeStats{}, fmt.Errorf("invalid range total '%s'", total)
+	}
+	if count == 0 {
+		return stats, nil
+	}
+	if stats.MinWei, ok = new(big.Int).SetString(minWei, 10); !ok {
+		return SlotRangeStats{}, fmt.Errorf("invalid range minimum '%s'", minWei)
+	}
+	if stats.MaxWei, ok = new(big.Int).SetString(maxWei, 10); !ok {
+		return SlotRangeStats{}, fmt.Errorf("invalid range maximum '%s'", maxWei)
+	}
+	return stats, nil
+}
+
+// add folds one bribe value into the stats.
+func (s *SlotRangeStats) add(value *big.Int) {
+	s.Count++
+	s.TotalWei.Add(s.TotalWei, value)
+	if s.MinWei == nil || value.Cmp(s.MinWei) < 0 {
+		s.MinWei = new(big.Int).Set(value)
+	}
+	if s.MaxWei == nil || value.Cmp(s.MaxWei) > 0 {
+		s.MaxWei = new(big.Int).Set(value)
+	}
+}
@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// RedisCache is a Cache backed by Redis, shared between API replicas.
+//
+// It speaks the RESP protocol directly (GET, SET PX, PING) over a small
+// connection pool, avoiding a client library dependency.
+type RedisCache struct {
+	addr     string
+	password string
+	timeout  time.Duration
+	pool     chan *redisConn
+}
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// RedisConfig contains Redis connection parameters.
+type RedisConfig struct {
+	Addr     string        // host:port, default localhost:6379
+	Password string        // Optional AUTH password
+	PoolSize int           // Idle connections kept open, default 10
+	Timeout  time.Duration // Per-command timeout, default 500ms
+}
+
+// NewRedisCache connects to Redis and verifies the connection with PING.
+func NewRedisCache(config RedisConfig) (*RedisCache, error) {
+	if config.Addr == "" {
+		config.Addr = "localhost:6379"
+	}
+	if config.PoolSize <= 0 {
+		config.PoolSize = 10
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 500 * time.Millisecond
+	}
+
+	c := &RedisCache{
+		addr:     config.Addr,
+		password: config.Password,
+		timeout:  config.Timeout,
+		pool:     make(chan *redisConn, config.PoolSize),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reply, err := c.do(ctx, "PING")
+	if err != nil {
+		return nil, fmt.Errorf("failed to ping redis at %s: %w", config.Addr, err)
+	}
+	if s, _ := reply.(string); s != "PONG" {
+		return nil, fmt.Errorf("unexpected PING reply from redis: %v", reply)
+	}
+	return c, nil
+}
+
+// Get returns the value for key and whether it was present.
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected GET reply type %T", reply)
+	}
+	return value, true, nil
+}
+
+// Set stores value under key for ttl.
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.New("cache TTL must be positive")
+	}
+	_, err := c.do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+// Close closes all pooled connections.
+func (c *RedisCache) Close() error {
+	for {
+		select {
+		case rc := <-c.pool:
+			rc.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do runs one command on a pooled connection. Connections that fail are
+// discarded rather than returned to the pool.
+func (c *RedisCache) do(ctx context.Context, args ...string) (interface{}, error) {
+	rc, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	rc.conn.SetDeadline(deadline)
+
+	reply, err := rc.roundTrip(args)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		rc.conn.Close()
+		return nil, err
+	}
+
+	select {
+	case c.pool <- rc:
+	default:
+		rc.conn.Close()
+	}
+	return reply, err
+}
+
+func (c *RedisCache) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case rc := <-c.pool:
+		return rc, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	rc := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+
+	if c.password != "" {
+		conn.SetDeadline(time.Now().Add(c.timeout))
+		if _, err := rc.roundTrip([]string{"AUTH", c.password}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis AUTH failed: %w", err)
+		}
+	}
+	return rc, nil
+}
+
+// redisError is an error reply sent by the server; the connection stays usable.
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// roundTrip writes a RESP array command and reads one reply.
+func (rc *redisConn) roundTrip(args []string) (interface{}, error) {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	if _, err := rc.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return readRESP(rc.r)
+}
+
+// readRESP decodes one reply: simple strings as string, errors as
+// redisError, integers as int64, bulk strings as []byte (nil when absent)
+// and arrays as []interface{}.
+func readRESP(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed redis reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readRESP(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown redis reply type %q", kind)
+	}
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// SchemaError reports why a database is not ready to serve queries.
+type SchemaError struct {
+	Problems []string // e.g. "missing table received_bids"
+}
+
+func (e *SchemaError) Error() string {
+	return "storage: schema check failed: " + strings.Join(e.Problems, "; ")
+}
+
+// schemaInspector is implemented by stores that can list the tables,
+// views and extensions their queries depend on but which are absent.
+type schemaInspector interface {
+	missingSchema(ctx context.Context) ([]string, error)
+}
+
+// VerifySchema checks that store's schema is fully migrated and that every
+// table, view and extension the store queries exists. It makes no changes
+// beyond creating the empty schema_version table on first use.
+//
+// A database that is not ready yields a *SchemaError listing every problem;
+// other errors mean the check itself could not run.
+func VerifySchema(ctx context.Context, store Store) error {
+	var problems []string
+
+	if m, ok := store.(Migrator); ok {
+		current, latest, err := m.SchemaVersion(ctx)
+		if err != nil {
+			return err
+		}
+		switch {
+		case current < latest:
+			problems = append(problems, fmt.Sprintf("schema version %d is behind %d (%d pending migrations)", current, latest, latest-current))
+		case current > latest:
+			problems = append(problems, fmt.Sprintf("schema version %d is newer than this binary supports (%d)", current, latest))
+		}
+	}
+
+	if inspector, ok := store.(schemaInspector); ok {
+		missing, err := inspector.missingSchema(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to inspect schema: %w", err)
+		}
+		problems = append(problems, missing...)
+	}
+
+	if len(problems) > 0 {
+		return &SchemaError{Problems: problems}
+	}
+	return nil
+}
+
+// Relations every backend's queries read or write.
+var requiredRelations = []string{
+	"slot_bribes", "relay_bribes", "slot_relay_discrepancies", "received_bids", "bridge_tvl", "prices", "builders", "censorship_analysis",
+}
+
+// postgresRequiredRelations adds the TimescaleDB continuous aggregates and
+// the job queue.
+var postgresRequiredRelations = append([]string{"builder_stats_hourly", "builder_stats_daily", "jobs"}, requiredRelations...)
+
+// sqliteRequiredRelations adds the job queue.
+var sqliteRequiredRelations = append([]string{"jobs"}, requiredRelations...)
+
+// missingRelations describes each required relation absent from present.
+func missingRelations(required []string, present map[string]bool) []string {
+	var missing []string
+	for _, name := range required {
+		if !present[name] {
+			missing = append(missing, "missing table or view "+name)
+		}
+	}
+	return missing
+}
+
+// collectNames reads single-column rows of names into a set.
+func collectNames(rows *sql.Rows) (map[string]bool, error) {
+	defer rows.Close()
+
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names[name] = true
+	}
+	return names, rows.Err()
+}
+
+func (s *PostgresStore) missingSchema(ctx context.Context) ([]string, error) {
+	var missing []string
+
+	var timescale bool
+	if err := s.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb')").Scan(&timescale); err != nil {
+		return nil, err
+	}
+	if !timescale {
+		missing = append(missing, "missing extension timescaledb")
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT name FROM UNNEST($1::TEXT[]) AS name WHERE to_regclass(name) IS NOT NULL",
+		pq.Array(postgresRequiredRelations))
+	if err != nil {
+		return nil, err
+	}
+	present, err := collectNames(rows)
+	if err != nil {
+		return nil, err
+	}
+	return append(missing, missingRelations(postgresRequiredRelations, present)...), nil
+}
+
+func (s *SQLiteStore) missingSchema(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type IN ('table', 'view')")
+	if err != nil {
+		return nil, err
+	}
+	present, err := collectNames(rows)
+	if err != nil {
+		return nil, err
+	}
+	return missingRelations(sqliteRequiredRelations, present), nil
+}
+
+func (s *ClickHouseStore) missingSchema(ctx context.Context) ([]string, error) {
+	present := make(map[string]bool)
+	err := s.query(ctx, `
+		SELECT name FROM system.tables
+		WHERE database = currentDatabase()
+		FORMAT TabSeparated`,
+		nil,
+		func(fields []string) error {
+			present[tsvUnescape(fields[0])] = true
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return missingRelations(requiredRelations, present), nil
+}
@@ -0,0 +1,687 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"insolventbydesign/internal/currency"
+	"insolventbydesign/internal/model"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore provides single-file storage for local research.
+//
+// It implements the same Store interface as PostgresStore without requiring
+// a database server. Wei values are stored as decimal TEXT to stay exact;
+// aggregates needing exact sums are computed in Go.
+type SQLiteStore struct {
+	db         *sql.DB
+	migrator   *sqlMigrator
+	batchSize  int
+	onConflict ConflictPolicy
+}
+
+// DefaultSQLiteBatchSize is the number of rows inserted per transaction.
+const DefaultSQLiteBatchSize = 10_000
+
+// NewSQLiteStore opens (or creates) the SQLite database at path and
+// ensures the schema exists. Use ":memory:" for a throwaway database.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	store, err := openSQLiteStore(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := store.InitSchema(ctx); err != nil {
+		store.Close()
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	return store, nil
+}
+
+// openSQLiteStore opens the database without touching its schema.
+func openSQLiteStore(path string) (*SQLiteStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite database path cannot be empty")
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite serializes writers; a single connection avoids SQLITE_BUSY
+	// and keeps ":memory:" databases consistent across queries.
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := db.ExecContext(ctx, "PRAGMA journal_mode=WAL; PRAGMA busy_timeout=5000;"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to configure database: %w", err)
+	}
+
+	migrator, err := newSQLMigrator(db, "sqlite", sqlitePlaceholder)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{db: db, migrator: migrator, batchSize: DefaultSQLiteBatchSize, onConflict: ConflictKeepFirst}, nil
+}
+
+// InitSchema brings the database schema to the latest migration.
+func (s *SQLiteStore) InitSchema(ctx context.Context) error {
+	return s.MigrateTo(ctx, s.migrator.latest())
+}
+
+// SchemaVersion returns the applied and latest available schema versions.
+func (s *SQLiteStore) SchemaVersion(ctx context.Context) (current, latest int, err error) {
+	current, err = s.migrator.current(ctx)
+	return current, s.migrator.latest(), err
+}
+
+// MigrateTo applies up or down migrations until the schema is at version.
+func (s *SQLiteStore) MigrateTo(ctx context.Context, version int) error {
+	return s.migrator.migrateTo(ctx, version)
+}
+
+// SetBatchSize overrides the number of rows inserted per transaction.
+func (s *SQLiteStore) SetBatchSize(n int) {
+	if n > 0 {
+		s.batchSize = n
+	}
+}
+
+// SetConflictPolicy changes how duplicate slots are resolved on insert.
+func (s *SQLiteStore) SetConflictPolicy(p ConflictPolicy) error {
+	policy, err := ParseConflictPolicy(string(p))
+	if err != nil {
+		return err
+	}
+	s.onConflict = policy
+	return nil
+}
+
+// sqliteOnConflict is the ON CONFLICT clause per ConflictPolicy. value_wei
+// is canonical decimal TEXT, so a longer string is a larger number and
+// equal-length strings compare lexically.
+var sqliteOnConflict = map[ConflictPolicy]string{
+	ConflictKeepFirst: `ON CONFLICT (slot_time, slot_number) DO NOTHING`,
+	ConflictKeepMax: `ON CONFLICT (slot_time, slot_number) DO UPDATE SET
+			value_wei = excluded.value_wei,
+			value_eth = excluded.value_eth,
+			builder_pubkey = excluded.builder_pubkey,
+			block_hash = excluded.block_hash,
+			relay_url = excluded.relay_url,
+			fetched_at = unixepoch()
+		WHERE length(excluded.value_wei) > length(slot_bribes.value_wei)
+			OR (length(excluded.value_wei) = length(slot_bribes.value_wei) AND excluded.value_wei > slot_bribes.value_wei)`,
+}
+
+// BatchInsertBribes inserts slot bribes one transaction per chunk of
+// batchSize rows, recording each as relayURL's report in relay_bribes and
+// extending the slot spans in builders.
+func (s *SQLiteStore) BatchInsertBribes(ctx context.Context, bribes []model.SlotBribe, relayURL string) (InsertReport, error) {
+	return insertChunks(ctx, len(bribes), s.batchSize, func(start, end int) (int, error) {
+		return s.insertChunk(ctx, bribes[start:end], relayURL)
+	})
+}
+
+// insertChunk inserts one chunk of bribes in a transaction and returns the
+// number of slot_bribes rows inserted or replaced.
+func (s *SQLiteStore) insertChunk(ctx context.Context, bribes []model.SlotBribe, relayURL string) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO slot_bribes (slot_number, slot_time, value_wei, value_eth, builder_pubkey, block_hash, relay_url)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		`+sqliteOnConflict[s.onConflict])
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	// A relay re-reporting a slot replaces its earlier report
+	reportStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO relay_bribes (slot_number, relay_url, value_wei, builder_pubkey, block_hash)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (slot_number, relay_url) DO UPDATE SET
+			value_wei = excluded.value_wei,
+			builder_pubkey = excluded.builder_pubkey,
+			block_hash = excluded.block_hash,
+			fetched_at = unixepoch()
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer reportStmt.Close()
+
+	for pubkey, span := range builderSpans(bribes) {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO builders (pubkey, first_seen, last_seen)
+			VALUES (?, ?, ?)
+			ON CONFLICT (pubkey) DO UPDATE SET
+				first_seen = MIN(COALESCE(builders.first_seen, excluded.first_seen), excluded.first_seen),
+				last_seen = MAX(COALESCE(builders.last_seen, excluded.last_seen), excluded.last_seen)
+		`, pubkey, span.first, span.last); err != nil {
+			return 0, fmt.Errorf("failed to update builder %s: %w", pubkey, err)
+		}
+	}
+
+	inserted := 0
+	for _, bribe := range bribes {
+		if bribe.ValueWei == nil {
+			continue
+		}
+
+		slotTime := model.Mainnet.SlotTime(bribe.Slot).Unix()
+		valueWei := bribe.ValueWei.String()
+
+		result, err := stmt.ExecContext(ctx, bribe.Slot, slotTime, valueWei, currency.WeiToETHFloat64(bribe.ValueWei),
+			bribe.BuilderPubkey, "" /* block hash */, relayURL)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert bribe for slot %d: %w", bribe.Slot, err)
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			inserted += int(n)
+		}
+
+		if _, err := reportStmt.ExecContext(ctx, bribe.Slot, relayURL, valueWei, bribe.BuilderPubkey, ""); err != nil {
+			return 0, fmt.Errorf("failed to insert relay report: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// BatchInsertBids inserts received bids in a single transaction.
+func (s *SQLiteStore) BatchInsertBids(ctx context.Context, bids []model.ReceivedBid) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO received_bids (slot_number, relay_url, block_hash, builder_pubkey, value_wei, received_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (slot_number, relay_url, block_hash) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, bid := range bids {
+		if bid.ValueWei == nil {
+			continue
+		}
+		if _, err := stmt.ExecContext(ctx, bid.Slot, bid.RelayURL, bid.BlockHash, bid.BuilderPubkey,
+			bid.ValueWei.String(), receivedAtMillis(bid.ReceivedAt)); err != nil {
+			return fmt.Errorf("failed to insert bid: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetSlotBids retrieves received bids for a specific slot range.
+func (s *SQLiteStore) GetSlotBids(ctx context.Context, startSlot, endSlot uint64) ([]model.ReceivedBid, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT slot_number, relay_url, builder_pubkey, block_hash, value_wei, received_at
+		FROM received_bids
+		WHERE slot_number BETWEEN ? AND ?
+		ORDER BY slot_number ASC, received_at ASC, relay_url ASC, block_hash ASC
+	`, startSlot, endSlot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bids: %w", err)
+	}
+	return scanBidRows(rows)
+}
+
+// SaveBridgeTVL upserts TVL snapshots in a single transaction.
+func (s *SQLiteStore) SaveBridgeTVL(ctx context.Context, snapshots []model.BridgeTVL) error {
+	for _, snapshot := range snapshots {
+		if err := validateTVL(snapshot); err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO bridge_tvl (bridge, chain, snapshot_time, tvl_usd, source)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (bridge, chain, snapshot_time, source) DO UPDATE SET
+			tvl_usd = excluded.tvl_usd,
+			fetched_at = unixepoch()
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, snapshot := range snapshots {
+		if _, err := stmt.ExecContext(ctx, snapshot.Bridge, snapshot.Chain, snapshot.Timestamp.Unix(),
+			snapshot.TVLUSD, snapshot.Source); err != nil {
+			return fmt.Errorf("failed to save TVL for %s: %w", snapshot.Bridge, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetBridgeTVL retrieves a bridge's TVL snapshots taken in [from, to).
+func (s *SQLiteStore) GetBridgeTVL(ctx context.Context, bridge, chain string, from, to time.Time) ([]model.BridgeTVL, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT bridge, chain, snapshot_time, tvl_usd, source
+		FROM bridge_tvl
+		WHERE bridge = ? AND (? = '' OR chain = ?) AND snapshot_time >= ? AND snapshot_time < ?
+		ORDER BY snapshot_time ASC, chain ASC, source ASC
+	`, bridge, chain, chain, from.Unix(), to.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bridge TVL: %w", err)
+	}
+	return scanTVLRows(rows)
+}
+
+// GetBridgeTVLAt retrieves a bridge's latest TVL snapshot taken at or before at.
+func (s *SQLiteStore) GetBridgeTVLAt(ctx context.Context, bridge, chain string, at time.Time) (model.BridgeTVL, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT bridge, chain, snapshot_time, tvl_usd, source
+		FROM bridge_tvl
+		WHERE bridge = ? AND (? = '' OR chain = ?) AND snapshot_time <= ?
+		ORDER BY snapshot_time DESC, chain ASC, source ASC
+		LIMIT 1
+	`, bridge, chain, chain, at.Unix())
+	if err != nil {
+		return model.BridgeTVL{}, fmt.Errorf("failed to query bridge TVL: %w", err)
+	}
+	snapshots, err := scanTVLRows(rows)
+	if err != nil {
+		return model.BridgeTVL{}, fmt.Errorf("failed to query bridge TVL: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return model.BridgeTVL{}, ErrNotFound
+	}
+	return snapshots[0], nil
+}
+
+// SavePrices upserts ETH/USD quotes in a single transaction.
+func (s *SQLiteStore) SavePrices(ctx context.Context, quotes []model.PriceQuote) error {
+	for _, quote := range quotes {
+		if err := validatePrice(quote); err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO prices (quote_time, source, price_usd)
+		VALUES (?, ?, ?)
+		ON CONFLICT (quote_time, source) DO UPDATE SET
+			price_usd = excluded.price_usd,
+			fetched_at = unixepoch()
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, quote := range quotes {
+		if _, err := stmt.ExecContext(ctx, quote.Timestamp.Unix(), quote.Source, quote.USD); err != nil {
+			return fmt.Errorf("failed to save price: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// PriceAt retrieves the latest ETH/USD quote taken at or before at.
+func (s *SQLiteStore) PriceAt(ctx context.Context, at time.Time) (model.PriceQuote, error) {
+	return scanPriceRow(s.db.QueryRowContext(ctx, `
+		SELECT quote_time, price_usd, source
+		FROM prices
+		WHERE quote_time <= ?
+		ORDER BY quote_time DESC, source ASC
+		LIMIT 1
+	`, at.Unix()))
+}
+
+// GetSlotRange retrieves bribes for a specific slot range.
+func (s *SQLiteStore) GetSlotRange(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	return collectSlots(func(fn func(model.SlotBribe) error) error {
+		return s.ForEachSlot(ctx, startSlot, endSlot, fn)
+	})
+}
+
+// GetTimeRange retrieves bribes for slots starting in [from, to).
+func (s *SQLiteStore) GetTimeRange(ctx context.Context, from, to time.Time) ([]model.SlotBribe, error) {
+	startSlot, endSlot, err := model.Mainnet.SlotRangeForTimes(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSlotRange(ctx, startSlot, endSlot)
+}
+
+// ForEachSlot streams bribes for a slot range to fn in slot order.
+func (s *SQLiteStore) ForEachSlot(ctx context.Context, startSlot, endSlot uint64, fn func(model.SlotBribe) error) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT slot_number, value_wei, builder_pubkey, relay_url
+		FROM slot_bribes
+		WHERE slot_number BETWEEN ? AND ?
+		ORDER BY slot_number ASC
+	`, startSlot, endSlot)
+	if err != nil {
+		return err
+	}
+	return scanSlotRows(rows, fn)
+}
+
+// GetSlotRangeStats aggregates the range. value_wei is TEXT, which SQLite
+// can neither sum exactly nor order numerically, so values are folded in
+// Go; only the one column is read, in-process.
+func (s *SQLiteStore) GetSlotRangeStats(ctx context.Context, startSlot, endSlot uint64) (SlotRangeStats, error) {
+	if startSlot > endSlot {
+		return SlotRangeStats{}, fmt.Errorf("start slot %d is after end slot %d", startSlot, endSlot)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT slot_number, value_wei
+		FROM slot_bribes
+		WHERE slot_number BETWEEN ? AND ?
+	`, startSlot, endSlot)
+	if err != nil {
+		return SlotRangeStats{}, fmt.Errorf("failed to aggregate slot range: %w", err)
+	}
+	defer rows.Close()
+
+	stats := SlotRangeStats{TotalWei: new(big.Int)}
+	value := new(big.Int)
+	for rows.Next() {
+		var slot uint64
+		var valueWei string
+		if err := rows.Scan(&slot, &valueWei); err != nil {
+			return SlotRangeStats{}, err
+		}
+		if _, ok := value.SetString(valueWei, 10); !ok {
+			return SlotRangeStats{}, fmt.Errorf("invalid stored value '%s' for slot %d", valueWei, slot)
+		}
+		stats.add(value)
+	}
+	if err := rows.Err(); err != nil {
+		return SlotRangeStats{}, err
+	}
+	return stats, nil
+}
+
+// GetBuilderStats returns aggregated statistics for builders.
+//
+// Value shares come from value_eth; exact wei totals for the returned page
+// are summed in Go.
+func (s *SQLiteStore) GetBuilderStats(ctx context.Context, query BuilderStatsQuery) ([]model.BuilderStats, error) {
+	order, err := query.orderExpr(map[BuilderSort]string{
+		SortByBlockCount: "COUNT(*)",
+		SortByTotalValue: "SUM(value_eth)", // value_wei is TEXT; ETH is exact enough to order by
+		SortByLastSeen:   "MAX(slot_number)",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	filter, args := query.slotFilter(sqlitePlaceholder)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT builder_pubkey, COUNT(*) AS block_count, COALESCE(MAX(b.entity_name), ''),
+			NULL, COALESCE(SUM(value_eth) / NULLIF(SUM(SUM(value_eth)) OVER (), 0), 0),
+			MIN(slot_number), MAX(slot_number)
+		FROM slot_bribes
+		LEFT JOIN builders b ON b.pubkey = slot_bribes.builder_pubkey
+		WHERE `+filter+`
+		GROUP BY builder_pubkey
+		ORDER BY `+order+query.limitClause("-1"), args...)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := scanBuilderStats(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sumBuilderValues(ctx, stats, query); err != nil {
+		return nil, fmt.Errorf("failed to sum builder values: %w", err)
+	}
+	return stats, nil
+}
+
+// GetBuilderConcentration computes top-k concentration over raw slots.
+func (s *SQLiteStore) GetBuilderConcentration(ctx context.Context, startSlot, endSlot uint64, topK int) (BuilderConcentration, error) {
+	return builderConcentrationSQL(ctx, s, s.db, sqlitePlaceholder, startSlot, endSlot, topK)
+}
+
+// sqliteMaxParams bounds the pubkeys bound into one IN list.
+const sqliteMaxParams = 500
+
+// sumBuilderValues sets the exact total value of each builder in stats,
+// over the query's slot window.
+func (s *SQLiteStore) sumBuilderValues(ctx context.Context, stats []model.BuilderStats, query BuilderStatsQuery) error {
+	filter, window := query.slotFilter(sqlitePlaceholder)
+	totals := make(map[string]*big.Int, len(stats))
+	for start := 0; start < len(stats); start += sqliteMaxParams {
+		end := min(start+sqliteMaxParams, len(stats))
+
+		args := append(make([]interface{}, 0, len(window)+end-start), window...)
+		for _, st := range stats[start:end] {
+			args = append(args, st.BuilderPubkey)
+			totals[st.BuilderPubkey] = new(big.Int)
+		}
+
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT builder_pubkey, value_wei
+			FROM slot_bribes
+			WHERE `+filter+` AND builder_pubkey IN (?`+strings.Repeat(", ?", end-start-1)+`)
+		`, args...)
+		if err != nil {
+			return err
+		}
+		err = func() error {
+			defer rows.Close()
+			value := new(big.Int)
+			for rows.Next() {
+				var pubkey, valueWei string
+				if err := rows.Scan(&pubkey, &valueWei); err != nil {
+					return err
+				}
+				if _, ok := value.SetString(valueWei, 10); !ok {
+					return fmt.Errorf("invalid stored value '%s' for builder %s", valueWei, pubkey)
+				}
+				totals[pubkey].Add(totals[pubkey], value)
+			}
+			return rows.Err()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range stats {
+		setTotalValue(&stats[i], totals[stats[i].BuilderPubkey])
+	}
+	return nil
+}
+
+// GetBuilder returns the builders table row for pubkey.
+func (s *SQLiteStore) GetBuilder(ctx context.Context, pubkey string) (Builder, error) {
+	return queryBuilder(s.db.QueryRowContext(ctx, `
+		SELECT `+builderColumns+`
+		FROM builders
+		WHERE pubkey = ?
+	`, pubkey))
+}
+
+// SaveBuilderLabels sets entities and labels in a single transaction.
+func (s *SQLiteStore) SaveBuilderLabels(ctx context.Context, builders []Builder) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO builders (pubkey, entity_name, labels)
+		VALUES (?, NULLIF(?, ''), ?)
+		ON CONFLICT (pubkey) DO UPDATE SET
+			entity_name = excluded.entity_name,
+			labels = excluded.labels
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, b := range builders {
+		labels, err := encodeLabels(b.Labels)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, b.Pubkey, b.Entity, labels); err != nil {
+			return fmt.Errorf("failed to save labels for builder %s: %w", b.Pubkey, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindGaps returns the ranges of slots in [startSlot, endSlot] with no stored bribe.
+func (s *SQLiteStore) FindGaps(ctx context.Context, startSlot, endSlot uint64) ([]SlotGap, error) {
+	return findGapsSQL(ctx, s.db, sqlitePlaceholder, startSlot, endSlot)
+}
+
+// FindDiscrepancies returns the slots in [startSlot, endSlot] on which relays disagree.
+func (s *SQLiteStore) FindDiscrepancies(ctx context.Context, startSlot, endSlot uint64) ([]SlotDiscrepancy, error) {
+	return findDiscrepanciesSQL(ctx, s.db, sqlitePlaceholder, startSlot, endSlot)
+}
+
+// GetRelayReports returns every relay's report for the slots in [startSlot, endSlot].
+func (s *SQLiteStore) GetRelayReports(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	return getRelayReportsSQL(ctx, s.db, sqlitePlaceholder, startSlot, endSlot)
+}
+
+// SaveAnalysis persists a censorship cost computation.
+//
+// Re-running the same (start, end, top-k) analysis replaces the stored row.
+func (s *SQLiteStore) SaveAnalysis(ctx context.Context, record AnalysisRecord) error {
+	if record.TotalCostWei == nil {
+		return fmt.Errorf("TotalCostWei cannot be nil")
+	}
+	computedAt := record.ComputedAt
+	if computedAt.IsZero() {
+		computedAt = time.Now()
+	}
+
+	topBuilders, err := encodeTopBuilders(record.TopBuilders)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO censorship_analysis (
+			start_slot, end_slot, duration_slots, total_cost_wei, total_cost_eth, total_cost_usd,
+			builder_concentration, top_k_builders, effective_cost_eth, breakeven_tvl_usd,
+			success_probability, computed_at, top_builders
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (start_slot, end_slot, top_k_builders) DO UPDATE SET
+			duration_slots = excluded.duration_slots,
+			total_cost_wei = excluded.total_cost_wei,
+			total_cost_eth = excluded.total_cost_eth,
+			total_cost_usd = excluded.total_cost_usd,
+			builder_concentration = excluded.builder_concentration,
+			effective_cost_eth = excluded.effective_cost_eth,
+			breakeven_tvl_usd = excluded.breakeven_tvl_usd,
+			success_probability = excluded.success_probability,
+			computed_at = excluded.computed_at,
+			top_builders = excluded.top_builders
+	`, record.StartSlot, record.EndSlot, record.DurationSlots, record.TotalCostWei.String(),
+		record.TotalCostETH, nullableFloat(record.TotalCostUSD), record.BuilderConcentration,
+		record.TopKBuilders, record.EffectiveCostETH, nullableFloat(record.BreakevenTVLUSD),
+		record.SuccessProbability, computedAt.Unix(), topBuilders)
+	if err != nil {
+		return fmt.Errorf("failed to save analysis: %w", err)
+	}
+	return nil
+}
+
+// GetAnalysis returns the stored analysis for (startSlot, endSlot, topK).
+func (s *SQLiteStore) GetAnalysis(ctx context.Context, startSlot, endSlot uint64, topK int) (AnalysisRecord, error) {
+	var computedAt int64
+	record, err := scanAnalysis(s.db.QueryRowContext(ctx, `
+		SELECT `+analysisColumns+`
+		FROM censorship_analysis
+		WHERE start_slot = ? AND end_slot = ? AND top_k_builders = ?
+	`, startSlot, endSlot, topK), &computedAt, func() time.Time { return time.Unix(computedAt, 0) })
+	if errors.Is(err, sql.ErrNoRows) {
+		return AnalysisRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return AnalysisRecord{}, fmt.Errorf("failed to get analysis: %w", err)
+	}
+	return record, nil
+}
+
+// GetAnalyses returns stored analyses matching query, most recent first.
+func (s *SQLiteStore) GetAnalyses(ctx context.Context, query AnalysisQuery) ([]AnalysisRecord, error) {
+	where, args, limit := analysisFilter(query, sqlitePlaceholder)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+analysisColumns+`
+		FROM censorship_analysis
+		WHERE `+where+`
+		ORDER BY computed_at DESC, start_slot ASC`+limit, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query analyses: %w", err)
+	}
+	defer rows.Close()
+
+	var records []AnalysisRecord
+	for rows.Next() {
+		var computedAt int64
+		record, err := scanAnalysis(rows, &computedAt, func() time.Time { return time.Unix(computedAt, 0) })
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// Close closes the database connection.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,1028 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+func newTestSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// TestSQLiteStore_RoundTrip verifies exact wei values survive storage.
+func TestSQLiteStore_RoundTrip(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	bribes := []model.SlotBribe{
+		{Slot: 102, ValueWei: big.NewInt(300), BuilderPubkey: "0xB"},
+		{Slot: 100, ValueWei: huge, BuilderPubkey: "0xA"},
+		{Slot: 101, ValueWei: big.NewInt(200), BuilderPubkey: "0xA"},
+		{Slot: 103, ValueWei: nil, BuilderPubkey: "0xC"}, // Skipped
+	}
+
+	if _, err := store.BatchInsertBribes(ctx, bribes, "https://relay.example"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	got, err := store.GetSlotRange(ctx, 100, 103)
+	if err != nil {
+		t.Fatalf("GetSlotRange failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 bribes, got %d", len(got))
+	}
+	if got[0].Slot != 100 || got[2].Slot != 102 {
+		t.Errorf("Expected ascending slots 100..102, got %d..%d", got[0].Slot, got[2].Slot)
+	}
+	if got[0].ValueWei.Cmp(huge) != 0 {
+		t.Errorf("Expected exact value %s, got %s", huge, got[0].ValueWei)
+	}
+	if got[0].RelayURL != "https://relay.example" {
+		t.Errorf("Expected relay https://relay.example, got %q", got[0].RelayURL)
+	}
+}
+
+// TestSQLiteStore_DuplicateSlotsIgnored verifies first-write-wins on conflicts.
+func TestSQLiteStore_DuplicateSlotsIgnored(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	first := []model.SlotBribe{{Slot: 5, ValueWei: big.NewInt(10), BuilderPubkey: "0xA"}}
+	second := []model.SlotBribe{{Slot: 5, ValueWei: big.NewInt(99), BuilderPubkey: "0xB"}}
+
+	if _, err := store.BatchInsertBribes(ctx, first, "relay-1"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+	if _, err := store.BatchInsertBribes(ctx, second, "relay-2"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	got, _ := store.GetSlotRange(ctx, 5, 5)
+	if len(got) != 1 || got[0].ValueWei.Int64() != 10 {
+		t.Errorf("Expected single row with value 10, got %+v", got)
+	}
+}
+
+// TestSQLiteStore_BuilderStats verifies per-builder counts.
+func TestSQLiteStore_BuilderStats(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	bribes := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(1), BuilderPubkey: "0xB"},
+	}
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	stats, err := store.GetBuilderStats(ctx, BuilderStatsQuery{})
+	if err != nil {
+		t.Fatalf("GetBuilderStats failed: %v", err)
+	}
+	if len(stats) != 2 || stats[0].BuilderPubkey != "0xA" || stats[0].BlockCount != 2 {
+		t.Errorf("Unexpected builder stats: %+v", stats)
+	}
+}
+
+// TestSQLiteStore_GetBuilderStatsPaged verifies sort orders and limit/offset paging.
+func TestSQLiteStore_GetBuilderStatsPaged(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	bribes := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1e18), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(1e18), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(1e18), BuilderPubkey: "0xA"},
+		{Slot: 4, ValueWei: big.NewInt(5e18), BuilderPubkey: "0xB"},
+		{Slot: 5, ValueWei: big.NewInt(1e17), BuilderPubkey: "0xC"},
+		{Slot: 6, ValueWei: big.NewInt(1e17), BuilderPubkey: "0xC"},
+	}
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	tests := []struct {
+		query BuilderStatsQuery
+		want  []string
+	}{
+		{BuilderStatsQuery{}, []string{"0xA", "0xC", "0xB"}},
+		{BuilderStatsQuery{SortBy: SortByTotalValue}, []string{"0xB", "0xA", "0xC"}},
+		{BuilderStatsQuery{SortBy: SortByLastSeen}, []string{"0xC", "0xB", "0xA"}},
+		{BuilderStatsQuery{Limit: 2}, []string{"0xA", "0xC"}},
+		{BuilderStatsQuery{Limit: 1, Offset: 1}, []string{"0xC"}},
+		{BuilderStatsQuery{Offset: 2}, []string{"0xB"}},
+		{BuilderStatsQuery{StartSlot: 3, EndSlot: 5}, []string{"0xA", "0xB", "0xC"}},
+		{BuilderStatsQuery{StartSlot: 5, EndSlot: 9}, []string{"0xC"}},
+	}
+
+	for _, tt := range tests {
+		stats, err := store.GetBuilderStats(ctx, tt.query)
+		if err != nil {
+			t.Fatalf("GetBuilderStats(%+v) failed: %v", tt.query, err)
+		}
+		var got []string
+		for _, s := range stats {
+			got = append(got, s.BuilderPubkey)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("GetBuilderStats(%+v): expected %v, got %v", tt.query, tt.want, got)
+		}
+	}
+
+	if _, err := store.GetBuilderStats(ctx, BuilderStatsQuery{SortBy: "fee"}); err == nil {
+		t.Error("Expected error for unknown sort, got nil")
+	}
+
+	// Totals and shares are relative to the window
+	stats, err := store.GetBuilderStats(ctx, BuilderStatsQuery{StartSlot: 3, EndSlot: 4})
+	if err != nil {
+		t.Fatalf("GetBuilderStats failed: %v", err)
+	}
+	if len(stats) != 2 || stats[1].TotalValueWei.String() != "5000000000000000000" || math.Abs(stats[1].ValueShare-5.0/6) > 1e-9 {
+		t.Errorf("expected 0xB with 5 ETH and 5/6 of the window's value, got %+v", stats)
+	}
+	if stats[0].FirstSeenSlot != 3 || stats[0].LastSeenSlot != 3 {
+		t.Errorf("expected 0xA seen only in slot 3 within the window, got %+v", stats[0])
+	}
+	if _, err := store.GetBuilderStats(ctx, BuilderStatsQuery{StartSlot: 5, EndSlot: 4}); err == nil {
+		t.Error("Expected error for inverted window, got nil")
+	}
+}
+
+// TestSQLiteStore_GetBuilderConcentration verifies the SQL computation
+// matches model.ComputeBuilderConcentration over the same range.
+func TestSQLiteStore_GetBuilderConcentration(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	var bribes []model.SlotBribe
+	for slot := uint64(100); slot < 200; slot++ {
+		bribes = append(bribes, model.SlotBribe{
+			Slot:          slot,
+			ValueWei:      big.NewInt(int64(slot) * 1e15),
+			BuilderPubkey: fmt.Sprintf("0x%02d", slot*slot%7),
+		})
+	}
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	for _, topK := range []int{1, 3, 10} {
+		got, err := store.GetBuilderConcentration(ctx, 120, 179, topK)
+		if err != nil {
+			t.Fatalf("GetBuilderConcentration failed: %v", err)
+		}
+		alpha, stats, err := model.ComputeBuilderConcentration(bribes[20:80], topK)
+		if err != nil {
+			t.Fatalf("ComputeBuilderConcentration failed: %v", err)
+		}
+		if math.Abs(got.Alpha-alpha) > 1e-12 || got.TotalBlocks != 60 {
+			t.Errorf("top-%d: expected α %v over 60 blocks, got %v over %d", topK, alpha, got.Alpha, got.TotalBlocks)
+		}
+		if len(got.TopBuilders) != min(topK, got.Builders) {
+			t.Errorf("top-%d: expected %d builders, got %d", topK, min(topK, got.Builders), len(got.TopBuilders))
+		}
+		// The model breaks count ties arbitrarily, so only counts are compared
+		for i, b := range got.TopBuilders {
+			if b.BlockCount != stats[i].BlockCount {
+				t.Errorf("top-%d: expected builder %d to have %d blocks, got %d", topK, i, stats[i].BlockCount, b.BlockCount)
+			}
+		}
+	}
+
+	empty, err := store.GetBuilderConcentration(ctx, 500, 600, 3)
+	if err != nil {
+		t.Fatalf("GetBuilderConcentration failed: %v", err)
+	}
+	if empty.TotalBlocks != 0 || empty.Alpha != 0 || len(empty.TopBuilders) != 0 {
+		t.Errorf("expected empty concentration, got %+v", empty)
+	}
+	if _, err := store.GetBuilderConcentration(ctx, 100, 200, 0); err == nil {
+		t.Error("Expected error for top-k 0, got nil")
+	}
+}
+
+// TestSQLiteStore_GetSlotRangeStats verifies exact aggregates beyond 64
+// bits and the empty range.
+func TestSQLiteStore_GetSlotRangeStats(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	bribes := []model.SlotBribe{
+		{Slot: 10, ValueWei: big.NewInt(9), BuilderPubkey: "0xA"},
+		{Slot: 11, ValueWei: huge, BuilderPubkey: "0xB"},
+		{Slot: 13, ValueWei: big.NewInt(10), BuilderPubkey: "0xA"},
+	}
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	stats, err := store.GetSlotRangeStats(ctx, 10, 13)
+	if err != nil {
+		t.Fatalf("GetSlotRangeStats failed: %v", err)
+	}
+	want := new(big.Int).Add(huge, big.NewInt(19))
+	if stats.Count != 3 || stats.TotalWei.Cmp(want) != 0 {
+		t.Errorf("expected 3 slots totalling %s, got %d totalling %s", want, stats.Count, stats.TotalWei)
+	}
+	// Numeric, not lexicographic, order
+	if stats.MinWei.Int64() != 9 || stats.MaxWei.Cmp(huge) != 0 {
+		t.Errorf("expected min 9 and max %s, got %s and %s", huge, stats.MinWei, stats.MaxWei)
+	}
+
+	empty, err := store.GetSlotRangeStats(ctx, 20, 30)
+	if err != nil {
+		t.Fatalf("GetSlotRangeStats failed: %v", err)
+	}
+	if empty.Count != 0 || empty.TotalWei.Sign() != 0 || empty.MinWei != nil || empty.MaxWei != nil {
+		t.Errorf("expected empty stats, got %+v", empty)
+	}
+}
+
+// TestSQLiteStore_SaveAnalysis verifies analysis rows upsert on their unique key.
+func TestSQLiteStore_SaveAnalysis(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	record := AnalysisRecord{
+		StartSlot:            10,
+		EndSlot:              20,
+		DurationSlots:        11,
+		TotalCostWei:         big.NewInt(1000),
+		BuilderConcentration: 0.5,
+		TopKBuilders:         3,
+		SuccessProbability:   0.8,
+	}
+	if err := store.SaveAnalysis(ctx, record); err != nil {
+		t.Fatalf("SaveAnalysis failed: %v", err)
+	}
+	record.BuilderConcentration = 0.6
+	if err := store.SaveAnalysis(ctx, record); err != nil {
+		t.Fatalf("SaveAnalysis (update) failed: %v", err)
+	}
+
+	var count int
+	var alpha float64
+	row := store.db.QueryRowContext(ctx, "SELECT COUNT(*), MAX(builder_concentration) FROM censorship_analysis")
+	if err := row.Scan(&count, &alpha); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if count != 1 || alpha != 0.6 {
+		t.Errorf("Expected one row with α=0.6, got %d rows α=%f", count, alpha)
+	}
+
+	record.TotalCostWei = nil
+	if err := store.SaveAnalysis(ctx, record); err == nil {
+		t.Error("Expected error for nil TotalCostWei, got nil")
+	}
+}
+
+// TestSQLiteStore_GetAnalyses verifies stored analyses round-trip and filter.
+func TestSQLiteStore_GetAnalyses(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	if _, err := store.GetAnalysis(ctx, 1, 2, 3); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	huge, _ := new(big.Int).SetString("98765432109876543210987654321", 10)
+	base := time.Unix(1_700_000_000, 0)
+	records := []AnalysisRecord{
+		{StartSlot: 100, EndSlot: 199, DurationSlots: 100, TotalCostWei: huge, TopKBuilders: 3,
+			BuilderConcentration: 0.5, TotalCostUSD: 1234.5, ComputedAt: base,
+			TopBuilders: []model.BuilderStats{{BuilderPubkey: "0xA", BlockCount: 30, Entity: "titan"}}},
+		{StartSlot: 100, EndSlot: 199, DurationSlots: 100, TotalCostWei: big.NewInt(1), TopKBuilders: 5,
+			ComputedAt: base.Add(time.Hour)},
+		{StartSlot: 300, EndSlot: 399, DurationSlots: 100, TotalCostWei: big.NewInt(2), TopKBuilders: 3,
+			ComputedAt: base.Add(2 * time.Hour)},
+	}
+	for _, r := range records {
+		if err := store.SaveAnalysis(ctx, r); err != nil {
+			t.Fatalf("SaveAnalysis failed: %v", err)
+		}
+	}
+
+	got, err := store.GetAnalysis(ctx, 100, 199, 3)
+	if err != nil {
+		t.Fatalf("GetAnalysis failed: %v", err)
+	}
+	if got.TotalCostWei.Cmp(huge) != 0 || got.TotalCostUSD != 1234.5 || !got.ComputedAt.Equal(base) {
+		t.Errorf("Unexpected record: %+v", got)
+	}
+	if len(got.TopBuilders) != 1 || got.TopBuilders[0].BuilderPubkey != "0xA" || got.TopBuilders[0].Entity != "titan" {
+		t.Errorf("Unexpected top builders: %+v", got.TopBuilders)
+	}
+
+	all, err := store.GetAnalyses(ctx, AnalysisQuery{})
+	if err != nil {
+		t.Fatalf("GetAnalyses failed: %v", err)
+	}
+	if len(all) != 3 || all[0].StartSlot != 300 {
+		t.Errorf("expected 3 analyses, newest first, got %+v", all)
+	}
+
+	filtered, err := store.GetAnalyses(ctx, AnalysisQuery{StartSlot: 50, EndSlot: 250, TopK: 3})
+	if err != nil {
+		t.Fatalf("GetAnalyses failed: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].TopKBuilders != 3 || filtered[0].EndSlot != 199 {
+		t.Errorf("expected one filtered analysis, got %+v", filtered)
+	}
+
+	limited, _ := store.GetAnalyses(ctx, AnalysisQuery{Limit: 2})
+	if len(limited) != 2 {
+		t.Errorf("expected 2 analyses with limit, got %d", len(limited))
+	}
+}
+
+// TestOpen_Drivers verifies driver selection.
+func TestOpen_Drivers(t *testing.T) {
+	store, err := Open(Config{Driver: DriverSQLite, Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("Open(sqlite) failed: %v", err)
+	}
+	store.Close()
+
+	if _, err := Open(Config{Driver: "oracle"}); err == nil {
+		t.Error("Expected error for unknown driver, got nil")
+	}
+}
+
+// TestSQLiteStore_ForEachSlot verifies streaming order, early stop and the
+// model.BribeSource adapter.
+func TestSQLiteStore_ForEachSlot(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	var bribes []model.SlotBribe
+	for slot := uint64(10); slot < 20; slot++ {
+		bribes = append(bribes, model.SlotBribe{Slot: slot, ValueWei: big.NewInt(int64(slot)), BuilderPubkey: "0xA"})
+	}
+	if _, err := store.BatchInsertBribes(ctx, bribes, "https://relay.example"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	var seen []uint64
+	stop := errors.New("stop")
+	err := store.ForEachSlot(ctx, 12, 19, func(b model.SlotBribe) error {
+		seen = append(seen, b.Slot)
+		if len(seen) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if len(seen) != 3 || seen[0] != 12 || seen[2] != 14 {
+		t.Errorf("expected slots [12 13 14], got %v", seen)
+	}
+
+	cost, err := model.CensorshipCostFrom(SlotSource(ctx, store, 10, 19), 4)
+	if err != nil {
+		t.Fatalf("CensorshipCostFrom failed: %v", err)
+	}
+	if cost.Int64() != 10+11+12+13 {
+		t.Errorf("expected cost 46, got %s", cost)
+	}
+}
+
+// TestSQLiteStore_FindGaps verifies leading, inner and trailing gaps are reported.
+func TestSQLiteStore_FindGaps(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	var bribes []model.SlotBribe
+	for _, slot := range []uint64{12, 13, 14, 17, 20, 21} {
+		bribes = append(bribes, model.SlotBribe{Slot: slot, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"})
+	}
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	gaps, err := store.FindGaps(ctx, 10, 25)
+	if err != nil {
+		t.Fatalf("FindGaps failed: %v", err)
+	}
+	want := []SlotGap{{10, 11}, {15, 16}, {18, 19}, {22, 25}}
+	if len(gaps) != len(want) {
+		t.Fatalf("expected gaps %v, got %v", want, gaps)
+	}
+	for i := range want {
+		if gaps[i] != want[i] {
+			t.Errorf("gap %d: expected %v, got %v", i, want[i], gaps[i])
+		}
+	}
+	if n := MissingSlots(gaps); n != 10 {
+		t.Errorf("expected 10 missing slots, got %d", n)
+	}
+
+	if gaps, _ := store.FindGaps(ctx, 12, 14); len(gaps) != 0 {
+		t.Errorf("expected no gaps in complete range, got %v", gaps)
+	}
+	if gaps, _ := store.FindGaps(ctx, 100, 200); len(gaps) != 1 || gaps[0] != (SlotGap{100, 200}) {
+		t.Errorf("expected whole empty range as one gap, got %v", gaps)
+	}
+	if _, err := store.FindGaps(ctx, 5, 1); err == nil {
+		t.Error("Expected error for inverted range, got nil")
+	}
+}
+
+// TestSQLiteStore_ConflictPolicies verifies first-wins and max-value upserts.
+func TestSQLiteStore_ConflictPolicies(t *testing.T) {
+	ctx := context.Background()
+	huge, _ := new(big.Int).SetString("100000000000000000000", 10) // Longer string than 99...9
+	first := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(500), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(99999999), BuilderPubkey: "0xA"},
+	}
+	second := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(90), BuilderPubkey: "0xB"}, // Lexically larger, numerically smaller
+		{Slot: 2, ValueWei: huge, BuilderPubkey: "0xB"},
+	}
+
+	tests := []struct {
+		policy   ConflictPolicy
+		wantWei  []string
+		wantPubs []string
+	}{
+		{ConflictKeepFirst, []string{"500", "99999999"}, []string{"0xA", "0xA"}},
+		{ConflictKeepMax, []string{"500", huge.String()}, []string{"0xA", "0xB"}},
+	}
+
+	for _, tt := range tests {
+		store := newTestSQLiteStore(t)
+		if err := store.SetConflictPolicy(tt.policy); err != nil {
+			t.Fatalf("SetConflictPolicy failed: %v", err)
+		}
+		if _, err := store.BatchInsertBribes(ctx, first, "relay-a"); err != nil {
+			t.Fatalf("BatchInsertBribes failed: %v", err)
+		}
+		if _, err := store.BatchInsertBribes(ctx, second, "relay-b"); err != nil {
+			t.Fatalf("BatchInsertBribes failed: %v", err)
+		}
+
+		got, err := store.GetSlotRange(ctx, 1, 2)
+		if err != nil {
+			t.Fatalf("GetSlotRange failed: %v", err)
+		}
+		for i, b := range got {
+			if b.ValueWei.String() != tt.wantWei[i] || b.BuilderPubkey != tt.wantPubs[i] {
+				t.Errorf("%s: slot %d expected %s from %s, got %s from %s",
+					tt.policy, b.Slot, tt.wantWei[i], tt.wantPubs[i], b.ValueWei, b.BuilderPubkey)
+			}
+		}
+	}
+
+	if _, err := ParseConflictPolicy("last"); err == nil {
+		t.Error("Expected error for unknown policy, got nil")
+	}
+}
+
+// TestSQLiteStore_FindDiscrepancies verifies per-relay reports are kept and
+// slots where relays disagree are reported with the canonical record marked.
+func TestSQLiteStore_FindDiscrepancies(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	relayA := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+	}
+	relayB := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"}, // Agrees
+		{Slot: 2, ValueWei: big.NewInt(200), BuilderPubkey: "0xA"}, // Different value
+		{Slot: 3, ValueWei: big.NewInt(100), BuilderPubkey: "0xB"}, // Different builder
+	}
+	if _, err := store.BatchInsertBribes(ctx, relayA, "relay-a"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+	if _, err := store.BatchInsertBribes(ctx, relayB, "relay-b"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	discrepancies, err := store.FindDiscrepancies(ctx, 1, 3)
+	if err != nil {
+		t.Fatalf("FindDiscrepancies failed: %v", err)
+	}
+	if len(discrepancies) != 2 || discrepancies[0].Slot != 2 || discrepancies[1].Slot != 3 {
+		t.Fatalf("expected discrepancies at slots 2 and 3, got %+v", discrepancies)
+	}
+
+	reports := discrepancies[0].Reports
+	if len(reports) != 2 || reports[0].RelayURL != "relay-a" || reports[1].ValueWei.Int64() != 200 {
+		t.Fatalf("Unexpected reports for slot 2: %+v", reports)
+	}
+	if !reports[0].Canonical || reports[1].Canonical {
+		t.Errorf("expected first relay's report to be canonical, got %+v", reports)
+	}
+
+	// The canonical record is unaffected by the disagreeing relay
+	bribes, err := store.GetSlotRange(ctx, 2, 2)
+	if err != nil {
+		t.Fatalf("GetSlotRange failed: %v", err)
+	}
+	if len(bribes) != 1 || bribes[0].ValueWei.Int64() != 100 {
+		t.Errorf("expected one canonical bribe of 100, got %+v", bribes)
+	}
+
+	// A relay correcting its report resolves the discrepancy
+	corrected := []model.SlotBribe{{Slot: 3, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"}}
+	if _, err := store.BatchInsertBribes(ctx, corrected, "relay-b"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+	if discrepancies, err = store.FindDiscrepancies(ctx, 3, 3); err != nil || len(discrepancies) != 0 {
+		t.Errorf("expected no discrepancy after correction, got %+v (err %v)", discrepancies, err)
+	}
+}
+
+// TestSQLiteStore_GetRelayReports verifies every relay's report is returned
+// by slot and relay, not just the canonical record.
+func TestSQLiteStore_GetRelayReports(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	relayA := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: big.NewInt(100), BuilderPubkey: "0xA"},
+	}
+	relayB := []model.SlotBribe{
+		{Slot: 2, ValueWei: big.NewInt(200), BuilderPubkey: "0xB"},
+		{Slot: 3, ValueWei: big.NewInt(300), BuilderPubkey: "0xB"},
+	}
+	if _, err := store.BatchInsertBribes(ctx, relayB, "relay-b"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+	if _, err := store.BatchInsertBribes(ctx, relayA, "relay-a"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	reports, err := store.GetRelayReports(ctx, 2, 3)
+	if err != nil {
+		t.Fatalf("GetRelayReports failed: %v", err)
+	}
+	want := []struct {
+		slot  uint64
+		relay string
+		value int64
+	}{{2, "relay-a", 100}, {2, "relay-b", 200}, {3, "relay-b", 300}}
+	if len(reports) != len(want) {
+		t.Fatalf("expected %d reports, got %+v", len(want), reports)
+	}
+	for i, w := range want {
+		r := reports[i]
+		if r.Slot != w.slot || r.RelayURL != w.relay || r.ValueWei.Int64() != w.value {
+			t.Errorf("expected report %d to be %v, got %+v", i, w, r)
+		}
+	}
+
+	if _, err := store.GetRelayReports(ctx, 3, 2); err == nil {
+		t.Error("Expected error for an inverted range, got nil")
+	}
+}
+
+// TestSQLiteStore_Builders verifies slot spans are tracked on ingestion and
+// labels are joined into builder stats.
+func TestSQLiteStore_Builders(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	if _, err := store.GetBuilder(ctx, "0xA"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	insert := func(relayURL string, slots ...uint64) {
+		t.Helper()
+		bribes := make([]model.SlotBribe, len(slots))
+		for i, slot := range slots {
+			bribes[i] = model.SlotBribe{Slot: slot, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"}
+		}
+		if _, err := store.BatchInsertBribes(ctx, bribes, relayURL); err != nil {
+			t.Fatalf("BatchInsertBribes failed: %v", err)
+		}
+	}
+	insert("relay-a", 20, 10, 30)
+	insert("relay-b", 5)
+
+	labels := []Builder{
+		{Pubkey: "0xA", Entity: "beaverbuild", Labels: []string{"ofac-compliant"}},
+		{Pubkey: "0xB", Entity: "titan"}, // Not yet ingested
+	}
+	if err := store.SaveBuilderLabels(ctx, labels); err != nil {
+		t.Fatalf("SaveBuilderLabels failed: %v", err)
+	}
+	insert("relay-a", 40)
+
+	builder, err := store.GetBuilder(ctx, "0xA")
+	if err != nil {
+		t.Fatalf("GetBuilder failed: %v", err)
+	}
+	if builder.FirstSeen != 5 || builder.LastSeen != 40 {
+		t.Errorf("expected slots 5-40, got %d-%d", builder.FirstSeen, builder.LastSeen)
+	}
+	if builder.Entity != "beaverbuild" || len(builder.Labels) != 1 || builder.Labels[0] != "ofac-compliant" {
+		t.Errorf("Unexpected labels: %+v", builder)
+	}
+
+	unseen, err := store.GetBuilder(ctx, "0xB")
+	if err != nil {
+		t.Fatalf("GetBuilder failed: %v", err)
+	}
+	if unseen.Entity != "titan" || unseen.FirstSeen != 0 {
+		t.Errorf("expected labeled builder with no slots, got %+v", unseen)
+	}
+
+	stats, err := store.GetBuilderStats(ctx, BuilderStatsQuery{})
+	if err != nil {
+		t.Fatalf("GetBuilderStats failed: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Entity != "beaverbuild" {
+		t.Errorf("expected entity joined into stats, got %+v", stats)
+	}
+}
+
+// TestSQLiteStore_GetTimeRange verifies wall-clock windows select slots by start time.
+func TestSQLiteStore_GetTimeRange(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	bribes := make([]model.SlotBribe, 10)
+	for i := range bribes {
+		bribes[i] = model.SlotBribe{Slot: uint64(100 + i), ValueWei: big.NewInt(1), BuilderPubkey: "0xA"}
+	}
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	// [start of slot 102, one second into slot 105): slots 102-105
+	from := model.Mainnet.SlotTime(102)
+	to := model.Mainnet.SlotTime(105).Add(time.Second)
+	got, err := store.GetTimeRange(ctx, from, to)
+	if err != nil {
+		t.Fatalf("GetTimeRange failed: %v", err)
+	}
+	if len(got) != 4 || got[0].Slot != 102 || got[3].Slot != 105 {
+		t.Errorf("expected slots 102-105, got %+v", got)
+	}
+
+	if _, err := store.GetTimeRange(ctx, to, from); err == nil {
+		t.Error("Expected error for inverted window, got nil")
+	}
+}
+
+// TestSQLiteStore_BuilderValueStats verifies exact totals, mean bids and value shares.
+func TestSQLiteStore_BuilderValueStats(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	eth, _ := new(big.Int).SetString("1000000000000000001", 10) // 1 ETH + 1 wei
+	bribes := []model.SlotBribe{
+		{Slot: 1, ValueWei: eth, BuilderPubkey: "0xA"},
+		{Slot: 2, ValueWei: eth, BuilderPubkey: "0xA"},
+		{Slot: 3, ValueWei: new(big.Int).Mul(eth, big.NewInt(2)), BuilderPubkey: "0xB"},
+		{Slot: 4, ValueWei: big.NewInt(0), BuilderPubkey: "0xC"},
+	}
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	stats, err := store.GetBuilderStats(ctx, BuilderStatsQuery{SortBy: SortByTotalValue, Limit: 2})
+	if err != nil {
+		t.Fatalf("GetBuilderStats failed: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 builders, got %d", len(stats))
+	}
+
+	a := stats[0] // Ties with 0xB on value; pubkey breaks the tie
+	if a.BuilderPubkey != "0xA" || a.TotalValueWei.String() != "2000000000000000002" {
+		t.Errorf("expected 0xA with exact total 2000000000000000002, got %s with %s", a.BuilderPubkey, a.TotalValueWei)
+	}
+	if a.MeanBidWei.Cmp(eth) != 0 {
+		t.Errorf("expected mean bid %s, got %s", eth, a.MeanBidWei)
+	}
+	// Shares are relative to all builders, not just the page
+	if a.ValueShare < 0.49 || a.ValueShare > 0.51 || stats[1].ValueShare < 0.49 || stats[1].ValueShare > 0.51 {
+		t.Errorf("expected shares of 0.5, got %f and %f", a.ValueShare, stats[1].ValueShare)
+	}
+}
+
+// TestSQLiteStore_ReceivedBids verifies bids round-trip exactly, duplicate
+// relay reports are ignored and the same block from another relay is kept.
+func TestSQLiteStore_ReceivedBids(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	big1, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	received := time.UnixMilli(1700000000123).UTC()
+	bids := []model.ReceivedBid{
+		{Slot: 5, RelayURL: "relay-a", BuilderPubkey: "0xA", BlockHash: "0x1", ValueWei: big1, ReceivedAt: received},
+		{Slot: 5, RelayURL: "relay-b", BuilderPubkey: "0xA", BlockHash: "0x1", ValueWei: big1, ReceivedAt: received},
+		{Slot: 5, RelayURL: "relay-a", BuilderPubkey: "0xB", BlockHash: "0x2", ValueWei: big.NewInt(7)},
+		{Slot: 9, RelayURL: "relay-a", BuilderPubkey: "0xB", BlockHash: "0x3", ValueWei: big.NewInt(8)},
+	}
+	if err := store.BatchInsertBids(ctx, bids); err != nil {
+		t.Fatalf("BatchInsertBids failed: %v", err)
+	}
+	// Re-ingesting is a no-op
+	if err := store.BatchInsertBids(ctx, bids[:1]); err != nil {
+		t.Fatalf("BatchInsertBids failed: %v", err)
+	}
+
+	got, err := store.GetSlotBids(ctx, 5, 5)
+	if err != nil {
+		t.Fatalf("GetSlotBids failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 bids for slot 5, got %d", len(got))
+	}
+	// NULL receive times sort first in SQLite
+	if !got[0].ReceivedAt.IsZero() || got[0].BlockHash != "0x2" {
+		t.Errorf("expected bid without receive time first, got %+v", got[0])
+	}
+	if got[1].ValueWei.Cmp(big1) != 0 || !got[1].ReceivedAt.Equal(received) || got[1].RelayURL != "relay-a" {
+		t.Errorf("expected exact value and receive time, got %+v", got[1])
+	}
+
+	summaries, err := model.SummarizeSlotBids(got)
+	if err != nil {
+		t.Fatalf("SummarizeSlotBids failed: %v", err)
+	}
+	if summaries[0].Depth != 2 || summaries[0].SecondPriceWei.Int64() != 7 {
+		t.Errorf("expected depth 2 and second price 7, got %+v", summaries[0])
+	}
+}
+
+// TestSQLiteStore_Health verifies the newest slot and its age are reported.
+func TestSQLiteStore_Health(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	h, err := store.Health(ctx)
+	if err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	if h.HasData() || h.OpenConns != 1 {
+		t.Errorf("expected empty store with 1 open connection, got %+v", h)
+	}
+
+	bribes := []model.SlotBribe{
+		{Slot: 100, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"},
+		{Slot: 250, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"},
+	}
+	if _, err := store.BatchInsertBribes(ctx, bribes, "relay"); err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+
+	h, err = CheckHealth(ctx, NewCachedStore(store, NewMemoryCache(0), DefaultCacheTTLs()))
+	if err != nil {
+		t.Fatalf("CheckHealth failed: %v", err)
+	}
+	if h.NewestSlot != 250 {
+		t.Errorf("expected newest slot 250, got %d", h.NewestSlot)
+	}
+	expectedAge := time.Since(model.Mainnet.SlotTime(250))
+	if diff := expectedAge - h.NewestSlotAge; diff < 0 || diff > time.Minute {
+		t.Errorf("expected age near %v, got %v", expectedAge, h.NewestSlotAge)
+	}
+}
+
+// TestSQLiteStore_InsertReport verifies per-chunk inserted and skipped counts.
+func TestSQLiteStore_InsertReport(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	store.SetBatchSize(2)
+	ctx := context.Background()
+
+	bribes := []model.SlotBribe{
+		{Slot: 1, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"},
+		{Slot: 1, ValueWei: big.NewInt(2), BuilderPubkey: "0xB"}, // Duplicate: kept out under first
+		{Slot: 2, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"},
+		{Slot: 3}, // Nil value
+		{Slot: 4, ValueWei: big.NewInt(1), BuilderPubkey: "0xA"},
+	}
+	report, err := store.BatchInsertBribes(ctx, bribes, "relay")
+	if err != nil {
+		t.Fatalf("BatchInsertBribes failed: %v", err)
+	}
+	if len(report.Chunks) != 3 {
+		t.Fatalf("expected 3 chunks at batch size 2, got %d", len(report.Chunks))
+	}
+	if report.Inserted() != 3 || report.Skipped() != 2 || report.Failed() != 0 {
+		t.Errorf("expected 3 inserted and 2 skipped, got %+v", report.Chunks)
+	}
+	if c := report.Chunks[0]; c.Inserted != 1 || c.Skipped != 1 {
+		t.Errorf("expected first chunk to insert 1 and skip 1, got %+v", c)
+	}
+}
+
+// TestSQLiteStore_BridgeTVL verifies snapshots are replaced per source,
+// filtered by chain and time, and looked up as of a point in time.
+func TestSQLiteStore_BridgeTVL(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	snapshots := []model.BridgeTVL{
+		{Bridge: "wormhole", Chain: "ethereum", Timestamp: day(1), TVLUSD: 100, Source: "defillama"},
+		{Bridge: "wormhole", Chain: "ethereum", Timestamp: day(2), TVLUSD: 200, Source: "defillama"},
+		{Bridge: "wormhole", Chain: "solana", Timestamp: day(2), TVLUSD: 50, Source: "defillama"},
+		{Bridge: "ronin", Chain: "ethereum", Timestamp: day(1), TVLUSD: 900, Source: "defillama"},
+	}
+	if err := store.SaveBridgeTVL(ctx, snapshots); err != nil {
+		t.Fatalf("SaveBridgeTVL failed: %v", err)
+	}
+	// A corrected value replaces the stored snapshot
+	corrected := model.BridgeTVL{Bridge: "wormhole", Chain: "ethereum", Timestamp: day(2), TVLUSD: 250, Source: "defillama"}
+	if err := store.SaveBridgeTVL(ctx, []model.BridgeTVL{corrected}); err != nil {
+		t.Fatalf("SaveBridgeTVL failed: %v", err)
+	}
+
+	all, err := store.GetBridgeTVL(ctx, "wormhole", "", day(1), day(3))
+	if err != nil {
+		t.Fatalf("GetBridgeTVL failed: %v", err)
+	}
+	if len(all) != 3 || all[1] != corrected || all[2].Chain != "solana" {
+		t.Errorf("expected 3 wormhole snapshots with the corrected value, got %+v", all)
+	}
+
+	ethereum, err := store.GetBridgeTVL(ctx, "wormhole", "ethereum", day(2), day(3))
+	if err != nil {
+		t.Fatalf("GetBridgeTVL failed: %v", err)
+	}
+	if len(ethereum) != 1 || ethereum[0].TVLUSD != 250 {
+		t.Errorf("expected one ethereum snapshot on day 2, got %+v", ethereum)
+	}
+
+	at, err := store.GetBridgeTVLAt(ctx, "wormhole", "ethereum", day(1).Add(12*time.Hour))
+	if err != nil {
+		t.Fatalf("GetBridgeTVLAt failed: %v", err)
+	}
+	if at.TVLUSD != 100 || !at.Timestamp.Equal(day(1)) {
+		t.Errorf("expected day 1 snapshot of 100, got %+v", at)
+	}
+
+	if _, err := store.GetBridgeTVLAt(ctx, "wormhole", "ethereum", day(1).Add(-time.Second)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound before the first snapshot, got %v", err)
+	}
+	if err := store.SaveBridgeTVL(ctx, []model.BridgeTVL{{Bridge: "wormhole", Chain: "ethereum", TVLUSD: 1, Source: "x"}}); err == nil {
+		t.Error("Expected error for snapshot without timestamp, got nil")
+	}
+}
+
+// TestSQLiteStore_PriceAt verifies the latest quote at or before a time is
+// returned, with corrections replacing earlier values.
+func TestSQLiteStore_PriceAt(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	hour := func(h int) time.Time { return time.Date(2024, 3, 1, h, 0, 0, 0, time.UTC) }
+	quotes := []model.PriceQuote{
+		{Timestamp: hour(0), USD: 3400, Source: "coingecko"},
+		{Timestamp: hour(1), USD: 3450, Source: "coingecko"},
+		{Timestamp: hour(1), USD: 3460, Source: "kraken"},
+	}
+	if err := store.SavePrices(ctx, quotes); err != nil {
+		t.Fatalf("SavePrices failed: %v", err)
+	}
+	if err := store.SavePrices(ctx, []model.PriceQuote{{Timestamp: hour(0), USD: 3410, Source: "coingecko"}}); err != nil {
+		t.Fatalf("SavePrices failed: %v", err)
+	}
+
+	quote, err := store.PriceAt(ctx, hour(0).Add(59*time.Minute))
+	if err != nil {
+		t.Fatalf("PriceAt failed: %v", err)
+	}
+	if quote.USD != 3410 || !quote.Timestamp.Equal(hour(0)) {
+		t.Errorf("expected corrected hour 0 quote of 3410, got %+v", quote)
+	}
+
+	// Sources quoting at the same time are broken by name
+	if quote, err := store.PriceAt(ctx, hour(5)); err != nil || quote.Source != "coingecko" || quote.USD != 3450 {
+		t.Errorf("expected coingecko hour 1 quote, got %+v (%v)", quote, err)
+	}
+
+	if _, err := store.PriceAt(ctx, hour(0).Add(-time.Second)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound before the first quote, got %v", err)
+	}
+	if err := store.SavePrices(ctx, []model.PriceQuote{{Timestamp: hour(2), USD: -1, Source: "x"}}); err == nil {
+		t.Error("Expected error for negative price, got nil")
+	}
+}
+
+// TestSubscribeSlots_Unsupported verifies stores without a change feed
+// fail to subscribe, including through a cache.
+func TestSubscribeSlots_Unsupported(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	if _, err := SubscribeSlots(context.Background(), store); err == nil {
+		t.Error("Expected error subscribing to SQLite, got nil")
+	}
+	cached := NewCachedStore(store, NewMemoryCache(0), DefaultCacheTTLs())
+	if _, err := cached.SubscribeSlots(context.Background()); err == nil {
+		t.Error("Expected error subscribing through the cache, got nil")
+	}
+}
+
+// TestSQLiteStore_Jobs verifies claims hand out queued jobs oldest first,
+// completion is restricted to the claiming worker, and stale jobs are
+// requeued until they run out of attempts.
+func TestSQLiteStore_Jobs(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	ctx := context.Background()
+
+	first, err := store.EnqueueJob(ctx, "montecarlo", json.RawMessage(`{"tau":1800}`))
+	if err != nil {
+		t.Fatalf("EnqueueJob failed: %v", err)
+	}
+	second, err := store.EnqueueJob(ctx, "sweep", nil)
+	if err != nil {
+		t.Fatalf("EnqueueJob failed: %v", err)
+	}
+	if first.Status != JobQueued || string(second.Params) != "{}" {
+		t.Errorf("expected a queued job with empty params, got %+v and %+v", first, second)
+	}
+	if _, err := store.EnqueueJob(ctx, "sweep", json.RawMessage("{")); err == nil {
+		t.Error("Expected error for invalid params, got nil")
+	}
+
+	claimed, err := store.ClaimJob(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("ClaimJob failed: %v", err)
+	}
+	if claimed.ID != first.ID || claimed.Status != JobRunning || claimed.Attempts != 1 || claimed.StartedAt.IsZero() {
+		t.Errorf("expected job %d running on its first attempt, got %+v", first.ID, claimed)
+	}
+	if string(claimed.Params) != `{"tau":1800}` {
+		t.Errorf("expected params to round-trip, got %s", claimed.Params)
+	}
+	if _, err := store.ClaimJob(ctx, "worker-2"); err != nil {
+		t.Fatalf("ClaimJob failed: %v", err)
+	}
+	if _, err := store.ClaimJob(ctx, "worker-3"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound from an empty queue, got %v", err)
+	}
+
+	if err := store.CompleteJob(ctx, first.ID, "worker-2", "", nil); !errors.Is(err, ErrJobNotClaimed) {
+		t.Errorf("expected ErrJobNotClaimed for another worker, got %v", err)
+	}
+	if err := store.CompleteJob(ctx, first.ID, "worker-1", "analysis:1:2:3", nil); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+	done, err := store.GetJob(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetJob failed: %v", err)
+	}
+	if done.Status != JobSucceeded || done.ResultRef != "analysis:1:2:3" || done.FinishedAt.IsZero() {
+		t.Errorf("expected succeeded job with result ref, got %+v", done)
+	}
+
+	// worker-2 disappears: its job is requeued until attempts run out
+	future := time.Now().Add(time.Hour)
+	for attempt := 1; attempt <= MaxJobAttempts; attempt++ {
+		if n, err := store.RequeueStaleJobs(ctx, future); err != nil || n != 1 {
+			t.Fatalf("RequeueStaleJobs: expected 1 job, got %d (%v)", n, err)
+		}
+		job, err := store.GetJob(ctx, second.ID)
+		if err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+		if attempt == MaxJobAttempts {
+			if job.Status != JobFailed || job.Error == "" {
+				t.Errorf("expected job failed after %d attempts, got %+v", attempt, job)
+			}
+			break
+		}
+		if job.Status != JobQueued {
+			t.Fatalf("expected job requeued after attempt %d, got %+v", attempt, job)
+		}
+		if _, err := store.ClaimJob(ctx, "worker-2"); err != nil {
+			t.Fatalf("ClaimJob failed: %v", err)
+		}
+	}
+
+	if _, err := store.GetJob(ctx, 999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
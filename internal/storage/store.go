@@ -0,0 +1,563 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// Store is the persistence interface shared by all storage backends.
+//
+// PostgresStore (TimescaleDB) targets production deployments; SQLiteStore
+// lets the API server and CLIs run on a laptop with zero infrastructure;
+// ClickHouseStore handles multi-year datasets of tens of millions of rows.
+type Store interface {
+	// InitSchema creates tables and indexes if they do not exist.
+	InitSchema(ctx context.Context) error
+
+	// BatchInsertBribes stores slot bribes reported by relayURL in chunks
+	// of the store's batch size, each committed in its own transaction.
+	// Every relay's report is kept for provenance; the canonical record for
+	// already-stored slots is resolved by the store's ConflictPolicy.
+	//
+	// A failed chunk does not stop later chunks: the report accounts for
+	// every row, and the error joins the failures of all failed chunks.
+	BatchInsertBribes(ctx context.Context, bribes []model.SlotBribe, relayURL string) (InsertReport, error)
+
+	// BatchInsertBids stores bids received by relays, each attributed to
+	// its RelayURL. A relay's report of a block already stored is ignored.
+	BatchInsertBids(ctx context.Context, bids []model.ReceivedBid) error
+
+	// GetSlotBids returns every stored received bid for the inclusive slot
+	// range, ordered by slot, receive time, relay and block hash.
+	GetSlotBids(ctx context.Context, startSlot, endSlot uint64) ([]model.ReceivedBid, error)
+
+	// SaveBridgeTVL stores bridge TVL snapshots. A snapshot already stored
+	// for the same bridge, chain, time and source is replaced.
+	SaveBridgeTVL(ctx context.Context, snapshots []model.BridgeTVL) error
+
+	// GetBridgeTVL returns the snapshots of bridge taken in [from, to) on
+	// chain ("" for every chain), ordered by time, chain and source.
+	GetBridgeTVL(ctx context.Context, bridge, chain string, from, to time.Time) ([]model.BridgeTVL, error)
+
+	// GetBridgeTVLAt returns the latest snapshot of bridge on chain ("" for
+	// any chain) taken at or before at, or ErrNotFound.
+	GetBridgeTVLAt(ctx context.Context, bridge, chain string, at time.Time) (model.BridgeTVL, error)
+
+	// SavePrices stores ETH/USD quotes. A quote already stored for the same
+	// time and source is replaced.
+	SavePrices(ctx context.Context, quotes []model.PriceQuote) error
+
+	// PriceAt returns the latest ETH/USD quote taken at or before at, or
+	// ErrNotFound. Quotes from several sources at the same time are broken
+	// by source name.
+	PriceAt(ctx context.Context, at time.Time) (model.PriceQuote, error)
+
+	// GetSlotRange returns bribes for the inclusive slot range, ordered by slot.
+	GetSlotRange(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error)
+
+	// GetTimeRange returns bribes for slots whose start time falls in
+	// [from, to), ordered by slot. Stored slot times follow model.Mainnet.
+	GetTimeRange(ctx context.Context, from, to time.Time) ([]model.SlotBribe, error)
+
+	// ForEachSlot streams bribes for the inclusive slot range to fn in slot
+	// order without materializing the range. Iteration stops at the first
+	// error returned by fn, which ForEachSlot returns.
+	ForEachSlot(ctx context.Context, startSlot, endSlot uint64, fn func(model.SlotBribe) error) error
+
+	// GetSlotRangeStats aggregates the stored bribes of the inclusive slot
+	// range inside the database, so a range's cost is available without
+	// loading its rows. An empty range has zero count and total and nil
+	// minimum and maximum.
+	GetSlotRangeStats(ctx context.Context, startSlot, endSlot uint64) (SlotRangeStats, error)
+
+	// GetBuilderStats returns one page of per-builder block counts in the
+	// order selected by query, with entities joined from the builders
+	// table. The zero query returns every builder, most active first.
+	GetBuilderStats(ctx context.Context, query BuilderStatsQuery) ([]model.BuilderStats, error)
+
+	// GetBuilderConcentration computes the top-k builders by block count in
+	// the inclusive slot range, and their share α of its blocks, inside the
+	// database rather than loading the range (see
+	// model.ComputeBuilderConcentration). An empty range yields zero blocks
+	// and α = 0.
+	GetBuilderConcentration(ctx context.Context, startSlot, endSlot uint64, topK int) (BuilderConcentration, error)
+
+	// GetBuilder returns the builders table row for pubkey, or ErrNotFound.
+	GetBuilder(ctx context.Context, pubkey string) (Builder, error)
+
+	// SaveBuilderLabels sets the entity and labels of each builder's pubkey,
+	// adding pubkeys not yet ingested. Slot spans are left untouched.
+	SaveBuilderLabels(ctx context.Context, builders []Builder) error
+
+	// FindGaps returns the ranges of slots within [startSlot, endSlot] for
+	// which no bribe is stored, in ascending order.
+	FindGaps(ctx context.Context, startSlot, endSlot uint64) ([]SlotGap, error)
+
+	// FindDiscrepancies returns the slots within [startSlot, endSlot] for
+	// which relays reported different values or builders, in ascending order.
+	FindDiscrepancies(ctx context.Context, startSlot, endSlot uint64) ([]SlotDiscrepancy, error)
+
+	// GetRelayReports returns every relay's report for the slots within
+	// [startSlot, endSlot] from relay_bribes, with RelayURL set, ordered by
+	// slot and then relay URL.
+	GetRelayReports(ctx context.Context, startSlot, endSlot uint64) ([]model.SlotBribe, error)
+
+	// SaveAnalysis persists a censorship cost computation.
+	SaveAnalysis(ctx context.Context, record AnalysisRecord) error
+
+	// GetAnalysis returns the stored analysis for an exact (start, end, top-k)
+	// key, or ErrNotFound.
+	GetAnalysis(ctx context.Context, startSlot, endSlot uint64, topK int) (AnalysisRecord, error)
+
+	// GetAnalyses returns stored analyses matching query, most recent first.
+	GetAnalyses(ctx context.Context, query AnalysisQuery) ([]AnalysisRecord, error)
+
+	// Close releases all database resources.
+	Close() error
+}
+
+// AnalysisRecord is one row of the censorship_analysis table.
+type AnalysisRecord struct {
+	StartSlot            uint64
+	EndSlot              uint64
+	DurationSlots        uint64
+	TotalBlocks          uint64 // Stored slots in the range; DurationSlots for stored analyses, which cover complete ranges
+	TotalCostWei         *big.Int
+	TotalCostETH         float64
+	TotalCostUSD         float64 // 0 when no ETH price was supplied
+	BuilderConcentration float64
+	TopKBuilders         int
+	EffectiveCostETH     float64
+	BreakevenTVLUSD      float64 // 0 when no ETH price was supplied
+	SuccessProbability   float64
+	ComputedAt           time.Time
+	TopBuilders          []model.BuilderStats // Most active builders in the range, at most TopKBuilders
+}
+
+// ErrNotFound is returned when a requested record does not exist.
+var ErrNotFound = errors.New("storage: record not found")
+
+// ConflictPolicy decides which record is kept when a slot is ingested
+// more than once, e.g. from several relays.
+type ConflictPolicy string
+
+// Conflict policies.
+const (
+	// ConflictKeepFirst keeps the first stored record (the default).
+	ConflictKeepFirst ConflictPolicy = "first"
+
+	// ConflictKeepMax keeps the record with the highest value, along with
+	// its builder and relay. This matches delivered-payload semantics: the
+	// proposer accepts the highest bid any relay offered.
+	ConflictKeepMax ConflictPolicy = "max"
+)
+
+// ParseConflictPolicy validates a policy name; "" selects ConflictKeepFirst.
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(s); p {
+	case "":
+		return ConflictKeepFirst, nil
+	case ConflictKeepFirst, ConflictKeepMax:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy '%s' (want first or max)", s)
+	}
+}
+
+// SlotGap is an inclusive range of slots with no stored bribe.
+type SlotGap struct {
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+}
+
+// Len returns the number of missing slots in the gap.
+func (g SlotGap) Len() uint64 {
+	return g.End - g.Start + 1
+}
+
+// MissingSlots returns the total number of slots covered by gaps.
+func MissingSlots(gaps []SlotGap) uint64 {
+	var n uint64
+	for _, g := range gaps {
+		n += g.Len()
+	}
+	return n
+}
+
+// RelayReport is one relay's record for a slot.
+type RelayReport struct {
+	RelayURL      string
+	ValueWei      *big.Int
+	BuilderPubkey string
+	Canonical     bool // Whether this report is the slot's stored canonical record
+}
+
+// SlotDiscrepancy lists every relay's report for a slot on which relays
+// disagree, ordered by relay URL.
+type SlotDiscrepancy struct {
+	Slot    uint64
+	Reports []RelayReport
+}
+
+// addReport appends report to the discrepancy for slot, starting a new one
+// when slot differs from the last. Reports must arrive in slot order.
+func addReport(discrepancies []SlotDiscrepancy, slot uint64, report RelayReport) []SlotDiscrepancy {
+	if n := len(discrepancies); n > 0 && discrepancies[n-1].Slot == slot {
+		discrepancies[n-1].Reports = append(discrepancies[n-1].Reports, report)
+		return discrepancies
+	}
+	return append(discrepancies, SlotDiscrepancy{Slot: slot, Reports: []RelayReport{report}})
+}
+
+// BuilderSort selects the ordering of builder stats. All orders are
+// descending, with ties broken by pubkey.
+type BuilderSort string
+
+// Builder stats orderings.
+const (
+	SortByBlockCount BuilderSort = "block_count"
+	SortByTotalValue BuilderSort = "total_value"
+	SortByLastSeen   BuilderSort = "last_seen"
+)
+
+// ParseBuilderSort validates a sort name; "" selects SortByBlockCount.
+func ParseBuilderSort(s string) (BuilderSort, error) {
+	switch sort := BuilderSort(s); sort {
+	case "":
+		return SortByBlockCount, nil
+	case SortByBlockCount, SortByTotalValue, SortByLastSeen:
+		return sort, nil
+	default:
+		return "", fmt.Errorf("unknown sort '%s' (want block_count, total_value or last_seen)", s)
+	}
+}
+
+// BuilderStatsQuery pages and orders GetBuilderStats results.
+type BuilderStatsQuery struct {
+	SortBy BuilderSort // Defaults to SortByBlockCount
+	Limit  int         // Maximum builders to return; 0 means no limit
+	Offset int         // Builders to skip
+
+	// Optional inclusive slot window; EndSlot 0 covers every slot. Value
+	// shares are relative to the window.
+	StartSlot uint64
+	EndSlot   uint64
+}
+
+// windowed reports whether the query is restricted to a slot window.
+func (q BuilderStatsQuery) windowed() bool {
+	return q.EndSlot > 0
+}
+
+// slotFilter renders the condition restricting slot_bribes rows to the
+// query's window as placeholders 1 and 2, or "TRUE" for every slot.
+func (q BuilderStatsQuery) slotFilter(ph func(n int) string) (string, []interface{}) {
+	if !q.windowed() {
+		return "TRUE", nil
+	}
+	return "slot_number BETWEEN " + ph(1) + " AND " + ph(2), []interface{}{q.StartSlot, q.EndSlot}
+}
+
+// orderExpr returns the ORDER BY expression for the query's sort,
+// looked up in a backend-specific table.
+func (q BuilderStatsQuery) orderExpr(exprs map[BuilderSort]string) (string, error) {
+	if q.Limit < 0 || q.Offset < 0 {
+		return "", fmt.Errorf("limit and offset must be non-negative")
+	}
+	if q.windowed() && q.StartSlot > q.EndSlot {
+		return "", fmt.Errorf("start slot %d is after end slot %d", q.StartSlot, q.EndSlot)
+	}
+	sort, err := ParseBuilderSort(string(q.SortBy))
+	if err != nil {
+		return "", err
+	}
+	return exprs[sort] + " DESC, builder_pubkey ASC", nil
+}
+
+// limitClause renders LIMIT/OFFSET; unlimited is the dialect's "no limit" value.
+func (q BuilderStatsQuery) limitClause(unlimited string) string {
+	if q.Limit == 0 && q.Offset == 0 {
+		return ""
+	}
+	limit := unlimited
+	if q.Limit > 0 {
+		limit = strconv.Itoa(q.Limit)
+	}
+	return " LIMIT " + limit + " OFFSET " + strconv.Itoa(q.Offset)
+}
+
+// RollingWindow contains statistics for one sliding window of slots,
+// computed by the database rather than in Go.
+type RollingWindow struct {
+	Slot      uint64 // Last slot of the window
+	MeanETH   float64
+	StdDevETH float64
+	MinETH    float64
+	MaxETH    float64
+	TotalWei  *big.Int // Exact window sum
+}
+
+// SlotRangeStats aggregates the stored bribes of a slot range. Values
+// are exact wei.
+type SlotRangeStats struct {
+	Count    uint64   // Stored slots; fewer than the range length if it has gaps
+	TotalWei *big.Int // Σ value_wei, the range's censorship cost when complete
+	MinWei   *big.Int // Smallest bribe; nil for an empty range
+	MaxWei   *big.Int // Largest bribe; nil for an empty range
+}
+
+// Driver names accepted by Open.
+const (
+	DriverPostgres   = "postgres"
+	DriverSQLite     = "sqlite"
+	DriverClickHouse = "clickhouse"
+)
+
+// Open connects to the backend selected by config.Driver.
+// An empty driver defaults to Postgres.
+func Open(config Config) (Store, error) {
+	switch config.Driver {
+	case DriverPostgres, "":
+		return NewPostgresStore(config)
+	case DriverSQLite:
+		store, err := NewSQLiteStore(config.Path)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.SetConflictPolicy(config.OnConflict); err != nil {
+			store.Close()
+			return nil, err
+		}
+		store.SetBatchSize(config.BatchSize)
+		return store, nil
+	case DriverClickHouse:
+		return NewClickHouseStore(config)
+	default:
+		return nil, fmt.Errorf("unknown storage driver '%s'", config.Driver)
+	}
+}
+
+// SlotSource adapts a store's slot range to a model.BribeSource so cost
+// functions can consume it incrementally.
+func SlotSource(ctx context.Context, store Store, startSlot, endSlot uint64) model.BribeSource {
+	return func(fn func(model.SlotBribe) error) error {
+		return store.ForEachSlot(ctx, startSlot, endSlot, fn)
+	}
+}
+
+// collectSlots gathers a streamed slot range into a slice.
+func collectSlots(forEach func(fn func(model.SlotBribe) error) error) ([]model.SlotBribe, error) {
+	var bribes []model.SlotBribe
+	err := forEach(func(bribe model.SlotBribe) error {
+		bribes = append(bribes, bribe)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bribes, nil
+}
+
+// scanSlotRows calls fn for every (slot_number, value_wei, builder_pubkey,
+// relay_url) row.
+func scanSlotRows(rows *sql.Rows, fn func(model.SlotBribe) error) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		var slot uint64
+		var valueWeiStr string
+		var builderPubkey string
+		var relayURL string
+
+		if err := rows.Scan(&slot, &valueWeiStr, &builderPubkey, &relayURL); err != nil {
+			return err
+		}
+
+		valueWei, ok := new(big.Int).SetString(valueWeiStr, 10)
+		if !ok {
+			return fmt.Errorf("invalid stored value '%s' for slot %d", valueWeiStr, slot)
+		}
+
+		if err := fn(model.SlotBribe{
+			Slot:          slot,
+			ValueWei:      valueWei,
+			BuilderPubkey: builderPubkey,
+			RelayURL:      relayURL,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// findGapsSQL implements FindGaps for database/sql backends using LAG to
+// find holes between consecutive stored slots. ph renders bind parameters.
+func findGapsSQL(ctx context.Context, db *sql.DB, ph func(n int) string, startSlot, endSlot uint64) ([]SlotGap, error) {
+	if startSlot > endSlot {
+		return nil, fmt.Errorf("start slot %d is after end slot %d", startSlot, endSlot)
+	}
+
+	var first, last sql.NullInt64
+	err := db.QueryRowContext(ctx, `
+		SELECT MIN(slot_number), MAX(slot_number)
+		FROM slot_bribes
+		WHERE slot_number BETWEEN `+ph(1)+` AND `+ph(2), startSlot, endSlot).Scan(&first, &last)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slot bounds: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT prev + 1, slot_number - 1
+		FROM (
+			SELECT slot_number, LAG(slot_number) OVER (ORDER BY slot_number) AS prev
+			FROM slot_bribes
+			WHERE slot_number BETWEEN `+ph(1)+` AND `+ph(2)+`
+		) s
+		WHERE slot_number - prev > 1
+		ORDER BY slot_number ASC
+	`, startSlot, endSlot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query gaps: %w", err)
+	}
+	defer rows.Close()
+
+	var inner []SlotGap
+	for rows.Next() {
+		var gap SlotGap
+		if err := rows.Scan(&gap.Start, &gap.End); err != nil {
+			return nil, err
+		}
+		inner = append(inner, gap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return boundGaps(startSlot, endSlot, uint64(first.Int64), uint64(last.Int64), first.Valid, inner), nil
+}
+
+// findDiscrepanciesSQL implements FindDiscrepancies for database/sql
+// backends from the slot_relay_discrepancies view. ph renders bind parameters.
+func findDiscrepanciesSQL(ctx context.Context, db *sql.DB, ph func(n int) string, startSlot, endSlot uint64) ([]SlotDiscrepancy, error) {
+	if startSlot > endSlot {
+		return nil, fmt.Errorf("start slot %d is after end slot %d", startSlot, endSlot)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT r.slot_number, r.relay_url, r.value_wei, r.builder_pubkey,
+			COALESCE(r.relay_url = s.relay_url, FALSE)
+		FROM relay_bribes r
+		JOIN slot_relay_discrepancies d ON d.slot_number = r.slot_number
+		LEFT JOIN slot_bribes s ON s.slot_number = r.slot_number
+		WHERE d.slot_number BETWEEN `+ph(1)+` AND `+ph(2)+`
+		ORDER BY r.slot_number ASC, r.relay_url ASC
+	`, startSlot, endSlot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query discrepancies: %w", err)
+	}
+	defer rows.Close()
+
+	var discrepancies []SlotDiscrepancy
+	for rows.Next() {
+		var slot uint64
+		var valueWeiStr string
+		var report RelayReport
+
+		if err := rows.Scan(&slot, &report.RelayURL, &valueWeiStr, &report.BuilderPubkey, &report.Canonical); err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if report.ValueWei, ok = new(big.Int).SetString(valueWeiStr, 10); !ok {
+			return nil, fmt.Errorf("invalid stored value '%s' for slot %d", valueWeiStr, slot)
+		}
+		discrepancies = addReport(discrepancies, slot, report)
+	}
+
+	return discrepancies, rows.Err()
+}
+
+// getRelayReportsSQL implements GetRelayReports for database/sql backends.
+// ph renders bind parameters.
+func getRelayReportsSQL(ctx context.Context, db *sql.DB, ph func(n int) string, startSlot, endSlot uint64) ([]model.SlotBribe, error) {
+	if startSlot > endSlot {
+		return nil, fmt.Errorf("start slot %d is after end slot %d", startSlot, endSlot)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT slot_number, value_wei, builder_pubkey, relay_url
+		FROM relay_bribes
+		WHERE slot_number BETWEEN `+ph(1)+` AND `+ph(2)+`
+		ORDER BY slot_number ASC, relay_url ASC
+	`, startSlot, endSlot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query relay reports: %w", err)
+	}
+	return collectSlots(func(fn func(model.SlotBribe) error) error {
+		return scanSlotRows(rows, fn)
+	})
+}
+
+// boundGaps adds the leading and trailing gaps around the stored slots
+// [first, last] to the inner gaps found between them. When no slot is
+// stored, the whole range is one gap.
+func boundGaps(startSlot, endSlot, first, last uint64, found bool, inner []SlotGap) []SlotGap {
+	if !found {
+		return []SlotGap{{Start: startSlot, End: endSlot}}
+	}
+
+	gaps := make([]SlotGap, 0, len(inner)+2)
+	if first > startSlot {
+		gaps = append(gaps, SlotGap{Start: startSlot, End: first - 1})
+	}
+	gaps = append(gaps, inner...)
+	if last < endSlot {
+		gaps = append(gaps, SlotGap{Start: last + 1, End: endSlot})
+	}
+	return gaps
+}
+
+// nullableFloat maps the "not supplied" zero value to SQL NULL.
+func nullableFloat(v float64) interface{} {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
+
+// Compile-time interface checks.
+var (
+	_ Store = (*PostgresStore)(nil)
+	_ Store = (*SQLiteStore)(nil)
+	_ Store = (*ClickHouseStore)(nil)
+
+	_ Migrator = (*PostgresStore)(nil)
+	_ Migrator = (*SQLiteStore)(nil)
+
+	_ HealthChecker = (*PostgresStore)(nil)
+	_ HealthChecker = (*SQLiteStore)(nil)
+	_ HealthChecker = (*ClickHouseStore)(nil)
+	_ HealthChecker = (*CachedStore)(nil)
+	_ HealthChecker = (*InstrumentedStore)(nil)
+
+	_ SlotSubscriber = (*PostgresStore)(nil)
+	_ SlotSubscriber = (*CachedStore)(nil)
+	_ SlotSubscriber = (*InstrumentedStore)(nil)
+
+	_ JobQueue = (*PostgresStore)(nil)
+	_ JobQueue = (*SQLiteStore)(nil)
+	_ JobQueue = (*CachedStore)(nil)
+	_ JobQueue = (*InstrumentedStore)(nil)
+
+	_ schemaInspector = (*PostgresStore)(nil)
+	_ schemaInspector = (*SQLiteStore)(nil)
+	_ schemaInspector = (*ClickHouseStore)(nil)
+)
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"insolventbydesign/internal/model"
+)
+
+// validateTVL rejects snapshots that cannot be keyed or compared.
+func validateTVL(snapshot model.BridgeTVL) error {
+	switch {
+	case snapshot.Bridge == "" || snapshot.Chain == "" || snapshot.Source == "":
+		return fmt.Errorf("bridge TVL snapshot needs a bridge, chain and source: %+v", snapshot)
+	case snapshot.Timestamp.IsZero():
+		return fmt.Errorf("bridge TVL snapshot for %s on %s has no timestamp", snapshot.Bridge, snapshot.Chain)
+	case math.IsNaN(snapshot.TVLUSD) || math.IsInf(snapshot.TVLUSD, 0) || snapshot.TVLUSD < 0:
+		return fmt.Errorf("invalid TVL %v for %s on %s", snapshot.TVLUSD, snapshot.Bridge, snapshot.Chain)
+	}
+	return nil
+}
+
+// scanTVLRows reads (bridge, chain, snapshot_time_unix, tvl_usd, source) rows.
+func scanTVLRows(rows *sql.Rows) ([]model.BridgeTVL, error) {
+	defer rows.Close()
+
+	var snapshots []model.BridgeTVL
+	for rows.Next() {
+		var snapshot model.BridgeTVL
+		var unix int64
+		if err := rows.Scan(&snapshot.Bridge, &snapshot.Chain, &unix, &snapshot.TVLUSD, &snapshot.Source); err != nil {
+			return nil, err
+		}
+		snapshot.Timestamp = time.Unix(unix, 0).UTC()
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+// dedupeTVL keeps the last of several snapshots sharing a key, so a single
+// upsert statement never touches a row twice.
+func dedupeTVL(snapshots []model.BridgeTVL) []model.BridgeTVL {
+	type key struct {
+		bridge, chain, source string
+		unix                  int64
+	}
+	index := make(map[key]int, len(snapshots))
+	deduped := make([]model.BridgeTVL, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		k := key{snapshot.Bridge, snapshot.Chain, snapshot.Source, snapshot.Timestamp.Unix()}
+		if i, ok := index[k]; ok {
+			deduped[i] = snapshot
+			continue
+		}
+		index[k] = len(deduped)
+		deduped = append(deduped, snapshot)
+	}
+	return deduped
+}
@@ -48,13 +48,15 @@ echo ""
 
 # Step 4: Rolling statistics
 echo "[4/6] Computing rolling statistics..."
-./bin/analysis --data=$DATA_DIR/bribes.json --mode=rolling --window=1000 > $ANALYSIS_DIR/reports/rolling.txt
+./bin/analysis --data=$DATA_DIR/bribes.json --mode=rolling --window=1000 \
+    --chart=$ANALYSIS_DIR/plots/rolling_cost.png > $ANALYSIS_DIR/reports/rolling.txt
 echo "✓ Rolling analysis complete"
 echo ""
 
 # Step 5: Concentration analysis
 echo "[5/6] Analyzing builder concentration..."
-./bin/analysis --data=$DATA_DIR/bribes.json --mode=concentration --window=1000 > $ANALYSIS_DIR/reports/concentration.txt
+./bin/analysis --data=$DATA_DIR/bribes.json --mode=concentration --window=1000 \
+    --chart=$ANALYSIS_DIR/plots/concentration.png > $ANALYSIS_DIR/reports/concentration.txt
 echo "✓ Concentration analysis complete"
 echo ""
 
@@ -66,7 +68,8 @@ echo "[6/6] Running Monte Carlo simulation..."
     --eth-price=$ETH_PRICE \
     --bridge-tvl=$BRIDGE_TVL \
     --success-prob=0.8 \
-    --simulations=100000 > $ANALYSIS_DIR/reports/monte_carlo.txt
+    --simulations=100000 \
+    --chart=$ANALYSIS_DIR/plots/profit_surface.png > $ANALYSIS_DIR/reports/monte_carlo.txt
 echo "✓ Monte Carlo complete"
 echo ""
 
@@ -75,6 +78,7 @@ echo "====================================="
 echo "Analysis Complete!"
 echo "====================================="
 echo "Reports generated in: $ANALYSIS_DIR/reports/"
+echo "Charts generated in:  $ANALYSIS_DIR/plots/"
 echo ""
 echo "View results:"
 echo "  cat $ANALYSIS_DIR/reports/summary.txt"